@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ackToken  string
+	ackFor    time.Duration
+	ackReason string
+	ackBy     string
+)
+
+// ackCmd represents the ack command
+var ackCmd = &cobra.Command{
+	Use:   "ack",
+	Short: "Snooze a finding with the token from a notification",
+	Long: `Acknowledge a finding with the signed token included in a notification.
+The finding is suppressed in the history store the daemon records reports
+in (daemon.history_dir, a directory or gs://bucket/prefix), and the daemon
+stops notifying about it until the snooze expires. The drift is still
+analyzed and reported.
+
+The token is verified with the snooze secret from daemon.notifications.snooze
+in the config file. --for can shorten the snooze signed in the token but not
+extend it.
+
+Examples:
+  drift-analysis-cli ack --token eyJrIjoi...
+  drift-analysis-cli ack --token eyJrIjoi... --for 72h --reason "migration on Friday"`,
+	RunE: runAck,
+}
+
+func init() {
+	rootCmd.AddCommand(ackCmd)
+	ackCmd.Flags().StringVar(&ackToken, "token", "", "snooze token from a notification (required)")
+	ackCmd.Flags().DurationVar(&ackFor, "for", 0, "snooze duration (default and maximum: the duration in the token)")
+	ackCmd.Flags().StringVar(&ackReason, "reason", "", "why the finding is snoozed")
+	ackCmd.Flags().StringVar(&ackBy, "by", "", "who snoozed the finding (default: current user)")
+	_ = ackCmd.MarkFlagRequired("token")
+}
+
+func runAck(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	snoozer, store, err := loadSnoozer(ctx)
+	if err != nil {
+		return err
+	}
+	if snoozer == nil {
+		return fmt.Errorf("snoozing is not configured; add daemon.notifications.snooze to %s", cfgFile)
+	}
+
+	claims, err := snoozer.Verify(ackToken)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	until, err := claims.Until(now, ackFor)
+	if err != nil {
+		return fmt.Errorf("--for: %w", err)
+	}
+	by := ackBy
+	if by == "" {
+		by = currentActor()
+	}
+
+	sup := history.Suppression{
+		Key:          claims.Key,
+		ResourceType: claims.ResourceType,
+		Resource:     claims.Resource,
+		Field:        claims.Field,
+		Until:        until,
+		By:           by,
+		Reason:       ackReason,
+		Created:      now,
+	}
+	if err := store.AppendSuppression(ctx, sup); err != nil {
+		return err
+	}
+
+	fmt.Printf("Snoozed %s %s %s until %s\n", sup.ResourceType, sup.Resource, sup.Field, sup.Until.Format(time.RFC3339))
+	return nil
+}
+
+// loadSnoozer reads the snooze settings from the daemon section of the config
+// file and opens the store suppressions are recorded in; it returns nil when
+// snoozing is not configured
+func loadSnoozer(ctx context.Context) (*notify.Snoozer, history.SuppressionStore, error) {
+	var config struct {
+		Daemon struct {
+			HistoryDir    string        `yaml:"history_dir"`
+			Notifications notify.Config `yaml:"notifications"`
+		} `yaml:"daemon"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, nil, err
+	}
+	snoozer, err := config.Daemon.Notifications.Snoozer()
+	if err != nil || snoozer == nil {
+		return nil, nil, err
+	}
+	store, err := openSuppressions(ctx, snoozer, config.Daemon.HistoryDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	return snoozer, store, nil
+}
+
+// openSuppressions opens the store snoozes are recorded in: the snooze
+// history_dir, or else daemon.history_dir, so ack and the daemon share it
+// whether it is a local directory or a GCS bucket
+func openSuppressions(ctx context.Context, snoozer *notify.Snoozer, historyDir string) (history.SuppressionStore, error) {
+	location := snoozer.HistoryDir()
+	if location == "" {
+		location = historyDir
+	}
+	if location == "" {
+		return nil, fmt.Errorf("snoozing needs a history store shared with the daemon; set daemon.history_dir in %s", cfgFile)
+	}
+	return history.OpenSuppressionStore(ctx, location)
+}
+
+// snoozedKeys returns the notification keys of findings with an active
+// snooze, or nil when snoozing is not configured
+func snoozedKeys(ctx context.Context, config *notify.Config, historyDir string, now time.Time) (map[string]bool, error) {
+	active, err := snoozes(ctx, config, historyDir, now)
+	return suppressionKeys(active), err
+}
+
+// snoozes returns the snoozes in effect at now by key, or nil when snoozing
+// is not configured. historyDir is daemon.history_dir.
+func snoozes(ctx context.Context, config *notify.Config, historyDir string, now time.Time) (map[string]history.Suppression, error) {
+	snoozer, err := config.Snoozer()
+	if err != nil || snoozer == nil {
+		return nil, err
+	}
+	store, err := openSuppressions(ctx, snoozer, historyDir)
+	if err != nil {
+		return nil, err
+	}
+	return store.ActiveSuppressions(ctx, now)
+}
+
+// suppressionKeys returns the notification keys of the suppressions
+func suppressionKeys(active map[string]history.Suppression) map[string]bool {
+	if active == nil {
+		return nil
+	}
+	keys := make(map[string]bool, len(active))
+	for key := range active {
+		keys[key] = true
+	}
+	return keys
+}
+
+// activeSuppressions returns the snoozes in effect at now, or nil when no
+// config file is used or snoozing is not configured
+func activeSuppressions(ctx context.Context, now time.Time) (map[string]history.Suppression, error) {
+	if cfgFile == "" {
+		return nil, nil
+	}
+	snoozer, store, err := loadSnoozer(ctx)
+	if err != nil || snoozer == nil {
+		return nil, err
+	}
+	return store.ActiveSuppressions(ctx, now)
+}
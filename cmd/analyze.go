@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/bigquery"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/network"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/pubsub"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/redis"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	analyzeOutputFormat string
+	analyzeOnly         []string
+)
+
+// analyzeConfig is the config file as used by the analyze command
+type analyzeConfig struct {
+	daemonConfig      `yaml:",inline"`
+	VPCBaselines      []network.VPCBaseline       `yaml:"vpc_baselines"`
+	PubSubBaselines   []pubsub.PubSubBaseline     `yaml:"pubsub_baselines"`
+	BigQueryBaselines []bigquery.BigQueryBaseline `yaml:"bigquery_baselines"`
+	RedisBaselines    []redis.RedisBaseline       `yaml:"redis_baselines"`
+}
+
+// analysis is one resource type the analyze command runs
+type analysis struct {
+	kind string
+	// baselines is the number of baselines the config file has for it
+	baselines func(config *analyzeConfig) int
+	scan      func(ctx context.Context, config *analyzeConfig, projects []string) (map[string]*report.Report, error)
+}
+
+// analyses are the analyzers run by analyze, in order. A new analyzer is
+// added here with a scan of every one of its baselines.
+var analyses = []analysis{
+	{
+		kind:      "sql",
+		baselines: func(c *analyzeConfig) int { return len(c.SQLBaselines) },
+		scan: func(ctx context.Context, c *analyzeConfig, projects []string) (map[string]*report.Report, error) {
+			return scanSQL(ctx, &c.daemonConfig, projects, nil)
+		},
+	},
+	{
+		kind:      "gke",
+		baselines: func(c *analyzeConfig) int { return len(c.GKEBaselines) },
+		scan: func(ctx context.Context, c *analyzeConfig, projects []string) (map[string]*report.Report, error) {
+			return scanGKE(ctx, &c.daemonConfig, projects)
+		},
+	},
+	{kind: "vpc", baselines: func(c *analyzeConfig) int { return len(c.VPCBaselines) }, scan: scanVPC},
+	{kind: "pubsub", baselines: func(c *analyzeConfig) int { return len(c.PubSubBaselines) }, scan: scanPubSub},
+	{kind: "bigquery", baselines: func(c *analyzeConfig) int { return len(c.BigQueryBaselines) }, scan: scanBigQuery},
+	{kind: "redis", baselines: func(c *analyzeConfig) int { return len(c.RedisBaselines) }, scan: scanRedis},
+}
+
+// analyzeCmd represents the analyze command
+var analyzeCmd = &cobra.Command{
+	Use:     "analyze",
+	Aliases: []string{"all"},
+	Short:   "Analyze every resource type with baselines in one combined report",
+	Long: `Run every analyzer that has baselines in the config file (Cloud SQL, GKE,
+VPC, Pub/Sub, BigQuery and Redis) and print one combined report, with the
+compliance score overall and per resource type.
+
+An analyzer that fails does not stop the others; its failure is reported and
+the command exits non-zero once the combined report is printed.
+
+Examples:
+  drift-analysis-cli analyze
+  drift-analysis-cli analyze --only sql,gke -o json
+  drift-analysis-cli all -o tui`,
+	RunE: runAnalyze,
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.Flags().StringVarP(&analyzeOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+	analyzeCmd.Flags().StringSliceVar(&analyzeOnly, "only", nil, "only run these analyzers (sql|gke|vpc|pubsub|bigquery|redis, comma-separated)")
+	analyzeCmd.Flags().BoolVar(&showRemediation, "show-remediation", false, "add the fix of each drift to the report: a gcloud command with the values filled in and the Terraform attribute change")
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var config analyzeConfig
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+	if err := validateAnalyzeConfig(&config); err != nil {
+		return err
+	}
+
+	selected, err := selectAnalyses(&config, analyzeOnly)
+	if err != nil {
+		return err
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+
+	combined := &report.Report{
+		Title:     "GCP Drift Analysis Report",
+		Timestamp: time.Now().UTC(),
+		Resources: []report.Resource{},
+	}
+	var failed []string
+	for _, a := range selected {
+		fmt.Fprintf(os.Stderr, "Analyzing %s (%d baseline(s))\n", a.kind, a.baselines(&config))
+		reports, err := a.scan(ctx, &config, projects)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s analysis failed: %v\n", a.kind, err)
+			failed = append(failed, a.kind)
+			continue
+		}
+		merged := mergeReports(reports)
+		combined.Resources = append(combined.Resources, merged.Resources...)
+		for _, r := range reports {
+			combined.Errors = append(combined.Errors, r.Errors...)
+		}
+	}
+
+	if err := linker.Annotate(combined); err != nil {
+		return err
+	}
+	annotateRemediation(combined)
+	combined.Summary = combined.Summarize()
+
+	if err := printReport(combined, analyzeOutputFormat); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("analyses failed: %v", failed)
+	}
+	return nil
+}
+
+// validateAnalyzeConfig validates the baselines of every analyzer
+func validateAnalyzeConfig(config *analyzeConfig) error {
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+	for _, baseline := range config.PubSubBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid Pub/Sub baseline: %w", err)
+		}
+	}
+	return nil
+}
+
+// selectAnalyses returns the analyzers named by --only, or every analyzer
+// with baselines in the config file
+func selectAnalyses(config *analyzeConfig, only []string) ([]analysis, error) {
+	kinds := make([]string, 0, len(analyses))
+	for _, a := range analyses {
+		kinds = append(kinds, a.kind)
+	}
+
+	var selected []analysis
+	if len(only) > 0 {
+		wanted := make(map[string]bool, len(only))
+		for _, kind := range only {
+			wanted[strings.TrimSpace(kind)] = true
+		}
+		for _, a := range analyses {
+			if !wanted[a.kind] {
+				continue
+			}
+			delete(wanted, a.kind)
+			if a.baselines(config) == 0 {
+				return nil, fmt.Errorf("no %s baselines defined in config", a.kind)
+			}
+			selected = append(selected, a)
+		}
+		for kind := range wanted {
+			return nil, fmt.Errorf("unknown analyzer %q (expected one of %s)", kind, strings.Join(kinds, ", "))
+		}
+		return selected, nil
+	}
+
+	for _, a := range analyses {
+		if a.baselines(config) > 0 {
+			selected = append(selected, a)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no baselines defined in config for any of %s", strings.Join(kinds, ", "))
+	}
+	return selected, nil
+}
+
+// scanVPC analyzes VPC networks against every VPC baseline
+func scanVPC(ctx context.Context, config *analyzeConfig, projects []string) (map[string]*report.Report, error) {
+	analyzer, err := network.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create network analyzer: %w", err)
+	}
+	defer analyzer.Close()
+
+	networks, err := analyzer.DiscoverNetworks(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover networks: %w", err)
+	}
+
+	reports := make(map[string]*report.Report)
+	for _, baseline := range config.VPCBaselines {
+		matched := network.FilterNetworks(networks, baseline.Networks)
+		reports[baseline.Name] = analyzer.AnalyzeDrift(matched, baseline.Config).ToReport()
+	}
+	return reports, nil
+}
+
+// scanPubSub analyzes Pub/Sub topics and subscriptions against every Pub/Sub baseline
+func scanPubSub(ctx context.Context, config *analyzeConfig, projects []string) (map[string]*report.Report, error) {
+	analyzer, err := pubsub.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub analyzer: %w", err)
+	}
+	defer analyzer.Close()
+
+	topics, err := analyzer.DiscoverTopics(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover topics: %w", err)
+	}
+	subscriptions, err := analyzer.DiscoverSubscriptions(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover subscriptions: %w", err)
+	}
+
+	reports := make(map[string]*report.Report)
+	for _, baseline := range config.PubSubBaselines {
+		var baselineTopics []*pubsub.Topic
+		if baseline.TopicConfig != nil {
+			baselineTopics = pubsub.FilterTopics(topics, baseline.FilterLabels)
+		}
+		var baselineSubscriptions []*pubsub.Subscription
+		if baseline.SubscriptionConfig != nil {
+			baselineSubscriptions = pubsub.FilterSubscriptions(subscriptions, baseline.FilterLabels)
+		}
+		reports[baseline.Name] = analyzer.AnalyzeDrift(baselineTopics, baselineSubscriptions, baseline).ToReport()
+	}
+	return reports, nil
+}
+
+// scanBigQuery analyzes BigQuery datasets against every BigQuery baseline
+func scanBigQuery(ctx context.Context, config *analyzeConfig, projects []string) (map[string]*report.Report, error) {
+	analyzer, err := bigquery.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery analyzer: %w", err)
+	}
+	defer analyzer.Close()
+
+	datasets, err := analyzer.DiscoverDatasets(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover datasets: %w", err)
+	}
+
+	reports := make(map[string]*report.Report)
+	for _, baseline := range config.BigQueryBaselines {
+		matched := bigquery.FilterByLabels(datasets, baseline.FilterLabels)
+		reports[baseline.Name] = analyzer.AnalyzeDrift(matched, baseline.Config).ToReport()
+	}
+	return reports, nil
+}
+
+// scanRedis analyzes Memorystore Redis instances against every Redis baseline
+func scanRedis(ctx context.Context, config *analyzeConfig, projects []string) (map[string]*report.Report, error) {
+	analyzer, err := redis.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Redis analyzer: %w", err)
+	}
+	defer analyzer.Close()
+
+	instances, err := analyzer.DiscoverInstances(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover instances: %w", err)
+	}
+
+	reports := make(map[string]*report.Report)
+	for _, baseline := range config.RedisBaselines {
+		matched := redis.FilterByLabels(instances, baseline.FilterLabels)
+		reports[baseline.Name] = analyzer.AnalyzeDrift(matched, baseline.Config).ToReport()
+	}
+	return reports, nil
+}
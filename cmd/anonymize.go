@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/spf13/cobra"
+)
+
+// addAnonymizeFlag registers --anonymize on a command exporting schemas
+func addAnonymizeFlag(cmd *cobra.Command, patterns *[]string) {
+	cmd.Flags().StringArrayVar(patterns, "anonymize", nil, "hash table, column and other identifiers matching this regular expression in exported schemas (repeatable, added to output.anonymize.patterns)")
+}
+
+// schemaAnonymizer builds the anonymizer of exported schema artifacts from
+// output.anonymize in the config file and the --anonymize patterns; it
+// returns nil when no pattern is configured
+func schemaAnonymizer(patterns []string) (sql.Anonymizer, error) {
+	output, err := loadOutputConfig()
+	if err != nil {
+		return nil, err
+	}
+	config := output.Anonymize
+	config.Patterns = append(config.Patterns, patterns...)
+	anonymizer, err := sql.NewHashAnonymizer(config)
+	if err != nil || anonymizer == nil {
+		return nil, err
+	}
+	return anonymizer, nil
+}
@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/apply"
+	"github.com/jessequinn/drift-analysis-cli/pkg/approval"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	applyFields      []string
+	applyDryRun      bool
+	applyConcurrency int
+	applyHistoryDir  string
+)
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply <plan.yaml>",
+	Short: "Apply whitelisted low-risk fixes from a remediation plan",
+	Long: `Apply fixes from a remediation plan (written with -o plan) through the GCP
+APIs. Only a whitelist of low-risk Cloud SQL checks can be applied (enabling
+backups, the backup window and retention, labels, maintenance window and Query
+Insights settings), and each check must be opted in with --field. Every other
+step is skipped.
+
+Steps for the same instance are applied as one patch. The patch fails instead
+of overwriting the instance if a field it sets changed since the plan was
+made.
+Every attempt is appended to the change log in the history directory.
+
+When the config file has an approval section, the eligible steps are first
+posted for review (to an approvals file, a GitHub issue or a Slack thread)
+and only steps approved before the timeout are applied. Set
+approval.required to refuse changes whenever no approval gate is configured.
+
+Examples:
+  drift-analysis-cli gcp sql -o plan > plan.yaml
+  drift-analysis-cli apply plan.yaml --field labels.* --dry-run
+  drift-analysis-cli apply plan.yaml --field sql.labels --field settings.insights_config.query_insights_enabled`,
+	Args: cobra.ExactArgs(1),
+	RunE: runApply,
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringArrayVar(&applyFields, "field", nil, "check ID or field to apply (repeatable, required)")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "show what would be applied without changing anything")
+	applyCmd.Flags().IntVar(&applyConcurrency, "concurrency", 2, "maximum number of resources changed at once")
+	applyCmd.Flags().StringVar(&applyHistoryDir, "history-dir", "history", "directory of the change log")
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+
+	var plan checks.Plan
+	if err := yaml.Unmarshal(data, &plan); err != nil {
+		return fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if plan.Version != checks.PlanVersion {
+		return fmt.Errorf("unsupported plan version %d (expected %d)", plan.Version, checks.PlanVersion)
+	}
+
+	if len(applyFields) == 0 {
+		return fmt.Errorf("no fields opted in; pass --field for each check to apply")
+	}
+	if applyConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	remediator, err := sql.NewRemediator(ctx)
+	if err != nil {
+		return err
+	}
+	executors := map[string]apply.Executor{"Cloud SQL": remediator}
+
+	optedIn, err := resolveApplyFields(applyFields, executors)
+	if err != nil {
+		return err
+	}
+
+	for i := range plan.Steps {
+		if plan.Steps[i].ID == "" {
+			plan.Steps[i].ID = checks.StepID(plan.Steps[i])
+		}
+	}
+
+	runner := &apply.Runner{
+		Executors: executors,
+		Options: apply.Options{
+			Checks:      optedIn,
+			DryRun:      applyDryRun,
+			Concurrency: applyConcurrency,
+		},
+	}
+
+	if !applyDryRun {
+		approvals, err := requestApprovals(ctx, &plan, runner.Eligible(&plan))
+		if err != nil {
+			return err
+		}
+		runner.Options.Approvals = approvals
+	}
+
+	started := time.Now()
+	results := runner.Run(ctx, &plan)
+	return recordResults(results, applyHistoryDir, started)
+}
+
+// recordResults prints one line per step, appends the attempts to the change
+// log in dir and fails when any step failed
+func recordResults(results []apply.Result, dir string, started time.Time) error {
+	failed := 0
+	for _, result := range results {
+		step := result.Step
+		line := fmt.Sprintf("[%s] %s %s %s/%s %s: %s -> %s",
+			result.Status, step.ID, step.CheckID, step.Project, step.Resource, step.Field, step.Actual, step.Expected)
+		switch {
+		case result.Err != nil:
+			failed++
+			line += fmt.Sprintf(" (%v)", result.Err)
+		case result.Reason != "":
+			line += fmt.Sprintf(" (%s)", result.Reason)
+		}
+		fmt.Println(line)
+	}
+
+	store := history.NewStore(dir)
+	if err := store.AppendChanges(apply.Changes(results, currentActor(), started)); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d step(s) failed", failed)
+	}
+	return nil
+}
+
+// requestApprovals posts the eligible steps to the configured approval gate
+// and waits for decisions. It returns nil when no gate is configured.
+func requestApprovals(ctx context.Context, plan *checks.Plan, steps []checks.Step) (map[string]apply.Approval, error) {
+	var config struct {
+		Approval *approval.Config `yaml:"approval"`
+	}
+	doc, err := readConfig()
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := decodeConfigNode(doc, &config); err != nil {
+		return nil, err
+	}
+
+	gate, err := config.Approval.Gate()
+	if err != nil {
+		return nil, err
+	}
+	if gate == nil {
+		if config.Approval != nil && config.Approval.Required {
+			return nil, fmt.Errorf("approval is required but no approval backend (file, github or slack) is configured")
+		}
+		return nil, nil
+	}
+
+	approvals := make(map[string]apply.Approval)
+	if len(steps) == 0 {
+		return approvals, nil
+	}
+
+	timeout, interval, err := config.Approval.Durations()
+	if err != nil {
+		return nil, err
+	}
+	if err := gate.Request(ctx, plan, steps); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Waiting up to %s for approval of %d step(s)...\n", timeout, len(steps))
+
+	approvals, err = approval.Wait(ctx, gate, steps, config.Approval.Approvers, timeout, interval)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("%d of %d step(s) decided\n", len(approvals), len(steps))
+	return approvals, nil
+}
+
+// resolveApplyFields maps --field values (check IDs or drift fields) to check
+// IDs and rejects checks no executor whitelists
+func resolveApplyFields(fields []string, executors map[string]apply.Executor) (map[string]bool, error) {
+	optedIn := make(map[string]bool)
+	for _, field := range fields {
+		found := checks.Find(field)
+		if len(found) == 0 {
+			return nil, fmt.Errorf("no check matches %q; run 'checks list' to see all checks", field)
+		}
+		for _, c := range found {
+			executor, ok := executors[c.ResourceType]
+			if !ok || !executor.Supports(c.ID) {
+				return nil, fmt.Errorf("check %s is not whitelisted for auto-remediation", c.ID)
+			}
+			optedIn[c.ID] = true
+		}
+	}
+	return optedIn, nil
+}
+
+// currentActor identifies who ran the command in the change log
+func currentActor() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var badgeDir string
+
+func init() {
+	gcpCmd.PersistentFlags().StringVar(&badgeDir, "badge-dir", "", "write an SVG drift badge per baseline into this directory")
+}
+
+// writeBadge writes an SVG badge for a report to <dir>/<name>-badge.svg. The
+// file name is stable so the badge can be embedded and is replaced on each scan.
+func writeBadge(dir, name string, r *report.Report) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create badge directory: %w", err)
+	}
+
+	fileName := unsafeFileChars.ReplaceAllString(name, "_") + "-badge.svg"
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, []byte(r.FormatBadge("drift")), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/baselinetest"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
+	"github.com/jessequinn/drift-analysis-cli/pkg/policydoc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	baselineTestVerbose bool
+	baselineDocFormat   string
+	baselineDocOutput   string
+	baselineDocTitle    string
+)
+
+// baselineCmd represents the baseline command
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "Work with the baselines in the config file",
+}
+
+// baselineTestCmd represents the baseline test command
+var baselineTestCmd = &cobra.Command{
+	Use:   "test <fixtures...>",
+	Short: "Run baselines against fixture resources and check the reported drift",
+	Long: `Analyze fixture resources against the baselines in the config file and
+assert the drift they produce, without calling any GCP API. Fixtures are YAML
+files, or directories of them, listing test cases: a synthetic Cloud SQL
+instance or GKE cluster, the baseline it is analyzed against and the expected
+result. Run it in CI to catch baseline mistakes before a config change is
+merged.
+
+The command exits with an error when any expectation is not met.
+
+Examples:
+  drift-analysis-cli baseline test testdata/baselines/
+  drift-analysis-cli baseline test --config config.yaml sql-fixtures.yaml -v`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBaselineTest,
+}
+
+// baselineDocCmd represents the baseline doc command
+var baselineDocCmd = &cobra.Command{
+	Use:   "doc",
+	Short: "Render the baselines as a human-readable configuration standard",
+	Long: `Render the baselines in the config file as a configuration standard document:
+for every baseline, the resources it applies to and each field it sets with
+the required value, the severity drift is reported with and the rationale of
+the check. Allowed values and ranges are shown instead of the single value
+they accept, and severity overrides replace the default severity.
+
+The document is generated from the same config the scanner enforces, so
+publishing it in CI keeps written policy and tooling from drifting apart.
+
+Examples:
+  drift-analysis-cli baseline doc > STANDARD.md
+  drift-analysis-cli baseline doc -o html --output-file standard.html
+  drift-analysis-cli baseline doc -o html --output-file gs://my-bucket/policy/standard.html`,
+	Args: cobra.NoArgs,
+	RunE: runBaselineDoc,
+}
+
+func init() {
+	rootCmd.AddCommand(baselineCmd)
+	baselineCmd.AddCommand(baselineTestCmd)
+	baselineCmd.AddCommand(baselineDocCmd)
+	baselineTestCmd.Flags().BoolVarP(&baselineTestVerbose, "verbose", "v", false, "list passing tests too")
+	baselineDocCmd.Flags().StringVarP(&baselineDocFormat, "output", "o", "markdown", "document format (markdown, html)")
+	baselineDocCmd.Flags().StringVar(&baselineDocOutput, "output-file", "", "output file or gs://bucket/object (default: stdout)")
+	baselineDocCmd.Flags().StringVar(&baselineDocTitle, "title", "Configuration Standard", "document title")
+}
+
+func runBaselineTest(cmd *cobra.Command, args []string) error {
+	// Failing tests are printed as they run; usage would only bury them
+	cmd.SilenceUsage = true
+
+	var config struct {
+		SQLBaselines []sql.SQLBaseline `yaml:"sql_baselines"`
+		GKEBaselines []gke.GKEBaseline `yaml:"gke_baselines"`
+		Policy       struct {
+			SQL *sql.Policy `yaml:"sql"`
+		} `yaml:"policy"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+
+	suites, err := baselinetest.LoadFiles(args)
+	if err != nil {
+		return err
+	}
+	baselines := baselinetest.Baselines{
+		SQL:       config.SQLBaselines,
+		GKE:       config.GKEBaselines,
+		SQLPolicy: config.Policy.SQL,
+	}
+
+	passed, failed := 0, 0
+	for _, suite := range suites {
+		for _, result := range baselinetest.Run(suite, baselines) {
+			if result.Passed() {
+				passed++
+				if baselineTestVerbose {
+					fmt.Printf("PASS  %s: %s\n", result.File, result.Name)
+				}
+				continue
+			}
+			failed++
+			fmt.Printf("FAIL  %s: %s\n", result.File, result.Name)
+			for _, failure := range result.Failures {
+				fmt.Printf("      %s\n", failure)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d baseline test(s) failed", failed)
+	}
+	return nil
+}
+
+func runBaselineDoc(cmd *cobra.Command, args []string) error {
+	if baselineDocFormat != "markdown" && baselineDocFormat != "html" {
+		return fmt.Errorf("unsupported format: %s (use 'markdown' or 'html')", baselineDocFormat)
+	}
+
+	// The document must describe baselines the scanner accepts
+	var config struct {
+		SQLBaselines []sql.SQLBaseline `yaml:"sql_baselines"`
+		GKEBaselines []gke.GKEBaseline `yaml:"gke_baselines"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+
+	configData, err := readConfigData()
+	if err != nil {
+		return err
+	}
+	doc, err := policydoc.Build(baselineDocTitle, cfgFile, configData, policydoc.Kinds)
+	if err != nil {
+		return err
+	}
+	output := policydoc.FormatMarkdown(doc)
+	if baselineDocFormat == "html" {
+		if output, err = policydoc.FormatHTML(doc); err != nil {
+			return fmt.Errorf("failed to render document: %w", err)
+		}
+	}
+
+	if gcs.IsURI(baselineDocOutput) {
+		ctx := context.Background()
+		client, err := sharedGCSClient(ctx)
+		if err != nil {
+			return err
+		}
+		if err := client.Upload(ctx, baselineDocOutput, []byte(output)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Document written to: %s\n", baselineDocOutput)
+	} else if baselineDocOutput != "" {
+		if err := os.WriteFile(baselineDocOutput, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Document written to: %s\n", baselineDocOutput)
+	} else {
+		fmt.Print(output)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var (
+	canaryFlag string
+	canarySeed int64
+)
+
+func init() {
+	gcpCmd.PersistentFlags().StringVar(&canaryFlag, "canary", "", "evaluate baselines against a random sample of matched resources, e.g. 10%, and project drift rates")
+	gcpCmd.PersistentFlags().Int64Var(&canarySeed, "canary-seed", 0, "seed for --canary sampling, to repeat a sample (default: random)")
+}
+
+// canarySampler draws the random sample for --canary
+type canarySampler struct {
+	percent float64
+	rng     *rand.Rand
+}
+
+// newCanarySampler parses --canary; it returns nil when canary mode is off
+func newCanarySampler() (*canarySampler, error) {
+	if canaryFlag == "" {
+		return nil, nil
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(canaryFlag), "%"), 64)
+	if err != nil || percent <= 0 || percent > 100 {
+		return nil, fmt.Errorf("invalid --canary %q: expected a percentage between 0 and 100, e.g. 10%%", canaryFlag)
+	}
+	// A sample must not replace the fleet's badge or split reports
+	if badgeDir != "" || splitBy != "" || historyDir != "" || bigqueryTable != "" || logDestination != "" || sccSource != "" {
+		return nil, fmt.Errorf("--canary cannot be combined with --badge-dir, --split-by, --history-dir, --bigquery-table, --log-destination or --scc-source")
+	}
+
+	seed := canarySeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &canarySampler{percent: percent, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// canarySample returns a random sample of items in their original order. The
+// sample has at least one item when items is not empty; a nil sampler
+// returns every item.
+func canarySample[T any](c *canarySampler, items []T) []T {
+	if c == nil || len(items) == 0 {
+		return items
+	}
+
+	size := int(math.Ceil(float64(len(items)) * c.percent / 100))
+	indexes := c.rng.Perm(len(items))[:size]
+	sort.Ints(indexes)
+
+	sample := make([]T, size)
+	for i, index := range indexes {
+		sample[i] = items[index]
+	}
+	return sample
+}
+
+// printCanary writes the projected drift of a sampled report, if canary mode is on
+func printCanary(w io.Writer, c *canarySampler, r *report.Report, population int) {
+	if c == nil {
+		return
+	}
+	fmt.Fprint(w, r.ProjectCanary(population).Format())
+	fmt.Fprintln(w)
+}
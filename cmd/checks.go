@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	// Analyzers register their checks at initialization
+	_ "github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	_ "github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+)
+
+var (
+	checksOutputFormat string
+	checksResourceType string
+	checksWide         bool
+)
+
+// checksCmd represents the checks command
+var checksCmd = &cobra.Command{
+	Use:   "checks",
+	Short: "Document the drift checks performed by the analyzers",
+}
+
+// checksListCmd represents the checks list command
+var checksListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List every registered drift check",
+	Long: `List the drift checks registered by the analyzers with their field path,
+default severity and description. Use -o json or -o yaml to generate
+documentation from the registry, including remediation hints.
+
+Examples:
+  drift-analysis-cli checks list
+  drift-analysis-cli checks list --resource-type "GKE Cluster"
+  drift-analysis-cli checks list --wide
+  drift-analysis-cli checks list -o yaml > docs/checks.yaml`,
+	RunE: runChecksList,
+}
+
+// checksExplainCmd represents the checks explain command
+var checksExplainCmd = &cobra.Command{
+	Use:   "explain <check-id|field>",
+	Short: "Explain what a drift check verifies and how to fix it",
+	Long: `Show the metadata of a drift check: what it verifies, the field it reports,
+its default severity and a remediation hint. The argument is either a check ID
+from 'checks list' or a field as printed in a drift report.
+
+Examples:
+  drift-analysis-cli checks explain sql.settings.backup_enabled
+  drift-analysis-cli checks explain "nodepool[default-pool].machine_type"
+  drift-analysis-cli checks explain settings.ip_configuration.authorized_networks`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChecksExplain,
+}
+
+func init() {
+	rootCmd.AddCommand(checksCmd)
+	checksCmd.AddCommand(checksListCmd)
+	checksCmd.AddCommand(checksExplainCmd)
+
+	checksCmd.PersistentFlags().StringVarP(&checksOutputFormat, "output", "o", "text", "output format (text|json|yaml)")
+	checksListCmd.Flags().BoolVar(&checksWide, "wide", false, "include remediation hints in text output")
+	checksListCmd.Flags().StringVar(&checksResourceType, "resource-type", "", "only list checks for this resource type (e.g. \"Cloud SQL\")")
+}
+
+func runChecksList(cmd *cobra.Command, args []string) error {
+	list := make([]*checks.Check, 0)
+	for _, c := range checks.All() {
+		if checksResourceType != "" && !strings.EqualFold(c.ResourceType, checksResourceType) {
+			continue
+		}
+		list = append(list, c)
+	}
+
+	switch checksOutputFormat {
+	case "json", "yaml":
+		return printChecks(list, checksOutputFormat)
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		header := "ID\tRESOURCE\tPATH\tSEVERITY\tDESCRIPTION"
+		if checksWide {
+			header += "\tREMEDIATION"
+		}
+		fmt.Fprintln(w, header)
+		for _, c := range list {
+			description := c.Description
+			if c.Immutable {
+				description += " [RECREATE]"
+			}
+			row := fmt.Sprintf("%s\t%s\t%s\t%s\t%s", c.ID, c.ResourceType, c.Path, c.Severity, description)
+			if checksWide {
+				row += "\t" + c.Remediation
+			}
+			fmt.Fprintln(w, row)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported output format %q for checks list (text|json|yaml)", checksOutputFormat)
+	}
+
+	return nil
+}
+
+func runChecksExplain(cmd *cobra.Command, args []string) error {
+	found := checks.Find(args[0])
+	if len(found) == 0 {
+		return fmt.Errorf("no check matches %q; run 'checks list' to see all checks", args[0])
+	}
+
+	switch checksOutputFormat {
+	case "json", "yaml":
+		return printChecks(found, checksOutputFormat)
+	case "text":
+	default:
+		return fmt.Errorf("unsupported output format %q for checks explain (text|json|yaml)", checksOutputFormat)
+	}
+
+	for i, c := range found {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s\n", c.ID)
+		fmt.Printf("  Resource:    %s\n", c.ResourceType)
+		fmt.Printf("  Field:       %s\n", c.Path)
+		fmt.Printf("  Severity:    %s (default)\n", c.Severity)
+		fmt.Printf("  Verifies:    %s\n", c.Description)
+		if c.Immutable {
+			fmt.Println("  Immutable:   yes, fixing drift requires recreating the resource")
+		}
+		if c.RequiresRestart {
+			fmt.Println("  Restart:     yes, the fix restarts the instance or recreates nodes")
+		}
+		if c.Remediation != "" {
+			fmt.Printf("  Remediation: %s\n", c.Remediation)
+		}
+	}
+	return nil
+}
+
+// printChecks renders checks as JSON or YAML
+func printChecks(list []*checks.Check, format string) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	data, err := yaml.Marshal(list)
+	if err != nil {
+		return fmt.Errorf("failed to format YAML: %w", err)
+	}
+	fmt.Print(string(data))
+	return nil
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/spf13/cobra"
+)
+
+var (
+	credentialsFile           string
+	impersonateServiceAccount string
+	quotaProject              string
+)
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&credentialsFile, "credentials-file", "", "service account key or external account file used instead of the application default credentials")
+	rootCmd.PersistentFlags().StringVar(&impersonateServiceAccount, "impersonate-service-account", "", "run every GCP call as this service account, e.g. drift-audit@PROJECT.iam.gserviceaccount.com")
+	rootCmd.PersistentFlags().StringVar(&quotaProject, "quota-project", "", "project billed for API quota instead of the credentials' project")
+}
+
+// setupCredentials points every GCP client at the credentials from the flags
+// or, when unset, the workspace config. The ambient gcloud and
+// GOOGLE_APPLICATION_CREDENTIALS configuration is left unchanged.
+func setupCredentials(cmd *cobra.Command, args []string) error {
+	config := credentials.Config{
+		File:                      workspace.Credentials,
+		ImpersonateServiceAccount: workspace.ImpersonateServiceAccount,
+		QuotaProject:              workspace.QuotaProject,
+	}
+	if credentialsFile != "" {
+		config.File = credentialsFile
+	}
+	if impersonateServiceAccount != "" {
+		config.ImpersonateServiceAccount = impersonateServiceAccount
+	}
+	if quotaProject != "" {
+		config.QuotaProject = quotaProject
+	}
+	if config == (credentials.Config{}) {
+		return nil
+	}
+	return credentials.Configure(cmd.Context(), config)
+}
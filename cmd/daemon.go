@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/export"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/notify"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/scheduler"
+	"github.com/spf13/cobra"
+)
+
+var daemonOnce bool
+
+// daemonConfig is the full config file as used by the daemon
+type daemonConfig struct {
+	Projects     []string          `yaml:"projects"`
+	SQLBaselines []sql.SQLBaseline `yaml:"sql_baselines"`
+	GKEBaselines []gke.GKEBaseline `yaml:"gke_baselines"`
+	Policy       struct {
+		SQL *sql.Policy `yaml:"sql"`
+	} `yaml:"policy"`
+	DatabaseOwners sql.DatabaseOwners          `yaml:"database_owners"`
+	Forecast       *history.ForecastPolicy     `yaml:"forecast"`
+	Escalation     *history.EscalationPolicy   `yaml:"escalation"`
+	Operational    *analyzer.OperationalPolicy `yaml:"operational"`
+	LabelPolicy    *analyzer.LabelPolicy       `yaml:"label_policy"`
+	Daemon         struct {
+		Schedule       string            `yaml:"schedule"`
+		Analyses       []string          `yaml:"analyses"`
+		ReportDir      string            `yaml:"report_dir"`
+		HistoryDir     string            `yaml:"history_dir"`
+		BigQueryTable  string            `yaml:"bigquery_table"`
+		LogDestination string            `yaml:"log_destination"`
+		LogProject     string            `yaml:"log_project"`
+		LogName        string            `yaml:"log_name"`
+		SCC            *export.SCCConfig `yaml:"security_command_center"`
+		Notifications  notify.Config     `yaml:"notifications"`
+	} `yaml:"daemon"`
+}
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run scheduled drift analyses as a long-running process",
+	Long: `Run the configured Cloud SQL and GKE analyses on a schedule, persist every
+report to disk and send notifications for new findings. Intended to run as a
+Kubernetes Deployment; SIGINT/SIGTERM stop the daemon after the current scan.
+
+The schedule is read from the daemon section of the config file and accepts
+@hourly, @daily, @weekly, "@every <duration>" or a plain duration.
+
+Examples:
+  drift-analysis-cli daemon --config /etc/drift/config.yaml
+  drift-analysis-cli daemon --once   # run a single scan and exit`,
+	RunE: runDaemon,
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	daemonCmd.Flags().BoolVar(&daemonOnce, "once", false, "run a single scan and exit")
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	var config daemonConfig
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	interval, err := scheduler.ParseSchedule(config.Daemon.Schedule)
+	if err != nil {
+		return fmt.Errorf("daemon: %w", err)
+	}
+
+	if len(config.Daemon.Analyses) == 0 {
+		config.Daemon.Analyses = []string{"sql", "gke"}
+	}
+	for _, kind := range config.Daemon.Analyses {
+		if kind != "sql" && kind != "gke" {
+			return fmt.Errorf("daemon: unsupported analysis %q (sql|gke)", kind)
+		}
+	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+	if err := config.DatabaseOwners.Validate(); err != nil {
+		return err
+	}
+	if err := config.Forecast.Validate(); err != nil {
+		return err
+	}
+	if err := config.Escalation.Validate(); err != nil {
+		return err
+	}
+	if err := config.Operational.Validate(); err != nil {
+		return err
+	}
+	if err := config.LabelPolicy.Validate(); err != nil {
+		return err
+	}
+
+	if config.Daemon.ReportDir == "" {
+		config.Daemon.ReportDir = "reports"
+	}
+	if err := os.MkdirAll(config.Daemon.ReportDir, 0755); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	queue, err := deadLetterQueue(&config.Daemon.Notifications)
+	if err != nil {
+		return err
+	}
+	notifiers, err := config.Daemon.Notifications.Notifiers()
+	if err != nil {
+		return err
+	}
+
+	var historyStore history.ReportStore
+	if config.Daemon.HistoryDir != "" {
+		historyStore, err = history.OpenReportStore(context.Background(), config.Daemon.HistoryDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	exporters, err := newExporters(context.Background(), outputConfig{
+		BigQueryTable:  config.Daemon.BigQueryTable,
+		LogDestination: config.Daemon.LogDestination,
+		LogProject:     config.Daemon.LogProject,
+		LogName:        config.Daemon.LogName,
+		SCC:            config.Daemon.SCC,
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "daemon: ", log.LstdFlags)
+	scan := func(ctx context.Context) error {
+		// Replay notifications that failed in earlier runs before sending new ones
+		if queue != nil {
+			result, err := queue.Flush(ctx, false)
+			if err != nil {
+				logger.Printf("failed to flush queued notifications: %v", err)
+			} else if result != (notify.FlushResult{}) {
+				logger.Printf("queued notifications: %d sent, %d pending, %d dropped", result.Sent, result.Pending, result.Dropped)
+			}
+		}
+		return runScheduledScan(ctx, &config, notifiers, historyStore, exporters, logger)
+	}
+
+	if daemonOnce {
+		return scan(ctx)
+	}
+
+	logger.Printf("starting; running %v every %s", config.Daemon.Analyses, interval)
+	s := &scheduler.Scheduler{
+		Interval: interval,
+		Job:      scan,
+		OnError: func(err error) {
+			logger.Printf("scan failed: %v", err)
+		},
+	}
+	if err := s.Run(ctx); err != nil {
+		return err
+	}
+	logger.Printf("stopped")
+	return nil
+}
+
+// runScheduledScan runs every configured analysis once, persists the reports
+// and sends notifications. An analysis failure does not stop the others.
+func runScheduledScan(ctx context.Context, config *daemonConfig, notifiers []notify.Notifier, historyStore history.ReportStore, exporters []export.Exporter, logger *log.Logger) error {
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	started := time.Now().UTC()
+	runID := report.NewScanID()
+	acknowledged, err := snoozes(ctx, &config.Daemon.Notifications, config.Daemon.HistoryDir, started)
+	if err != nil {
+		logger.Printf("failed to read snoozed findings, notifying about all: %v", err)
+	}
+	snoozed := suppressionKeys(acknowledged)
+
+	var failed []string
+	for _, kind := range config.Daemon.Analyses {
+		var reports map[string]*report.Report
+		switch kind {
+		case "sql":
+			reports, err = scanSQL(ctx, config, projects, historyStore)
+		case "gke":
+			reports, err = scanGKE(ctx, config, projects)
+		}
+		if err != nil {
+			logger.Printf("%s analysis failed: %v", kind, err)
+			failed = append(failed, kind)
+			continue
+		}
+
+		for name, r := range reports {
+			if err := escalateFindings(ctx, logger.Writer(), historyStore, config.Escalation, kind+"-"+name, r); err != nil {
+				logger.Printf("failed to escalate %s-%s findings: %v", kind, name, err)
+			}
+			r.Acknowledgements = history.Acknowledgements(r, acknowledged)
+			path, err := persistReport(config.Daemon.ReportDir, kind+"-"+name, started, r)
+			if err != nil {
+				logger.Printf("%v", err)
+			} else {
+				logger.Printf("wrote %s (%d resources, %d with drift)", path, len(r.Resources), r.DriftedCount())
+			}
+			if historyStore != nil {
+				if path, err := historyStore.SaveReport(ctx, unsafeFileChars.ReplaceAllString(kind+"-"+name, "_"), r); err != nil {
+					logger.Printf("%v", err)
+				} else {
+					logger.Printf("recorded %s", path)
+				}
+			}
+			for _, exporter := range exporters {
+				if n, err := exporter.Export(ctx, runID, r); err != nil {
+					logger.Printf("%v", err)
+				} else {
+					logger.Printf("exported %d findings to %s", n, exporter.Destination())
+				}
+			}
+			if path, err := writeBadge(config.Daemon.ReportDir, kind+"-"+name, r); err != nil {
+				logger.Printf("%v", err)
+			} else {
+				logger.Printf("wrote %s (%s)", path, r.BadgeMessage())
+			}
+
+			notified := notify.Unsuppressed(r, snoozed)
+			for _, n := range notifiers {
+				if err := n.Notify(ctx, notified); err != nil {
+					logger.Printf("notification failed: %v", err)
+				}
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("analyses failed: %v", failed)
+	}
+	return nil
+}
+
+// scanSQL analyzes Cloud SQL instances against every SQL baseline. Disk growth
+// is forecast from the reports in historyStore, when set.
+func scanSQL(ctx context.Context, config *daemonConfig, projects []string, historyStore history.ReportStore) (map[string]*report.Report, error) {
+	if len(config.SQLBaselines) == 0 {
+		return nil, fmt.Errorf("no SQL baselines defined in config")
+	}
+
+	analyzer, err := sql.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	analyzer.SetPolicy(config.Policy.SQL)
+	analyzer.SetDatabaseOwners(config.DatabaseOwners)
+	if config.Operational != nil {
+		analyzer.SetOperationalSeverities(config.Operational.SQL)
+	}
+	analyzer.SetLabelPolicy(config.LabelPolicy)
+
+	instances, err := analyzer.DiscoverInstances(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover instances: %w", err)
+	}
+
+	reports := make(map[string]*report.Report)
+	for _, baseline := range config.SQLBaselines {
+		matched := sql.SelectInstances(instances, baseline, config.SQLBaselines)
+		driftReport := analyzer.AnalyzeDrift(matched, baseline.Config)
+		sql.CheckCapacity(driftReport, baseline.Capacity)
+		sql.CheckUniqueness(driftReport, baseline.Unique)
+		projections, err := forecastGrowth(ctx, historyStore, config.Forecast, "sql-"+baseline.Name, driftReport.ToReport())
+		if err != nil {
+			return nil, err
+		}
+		sql.AddForecasts(driftReport, projections)
+		reports[baseline.Name] = driftReport.ToReport()
+	}
+	return reports, nil
+}
+
+// scanGKE analyzes GKE clusters against every GKE baseline
+func scanGKE(ctx context.Context, config *daemonConfig, projects []string) (map[string]*report.Report, error) {
+	if len(config.GKEBaselines) == 0 {
+		return nil, fmt.Errorf("no GKE baselines defined in config")
+	}
+
+	analyzer, err := gke.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GKE analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	if config.Operational != nil {
+		analyzer.SetOperationalSeverities(config.Operational.GKE)
+	}
+	analyzer.SetLabelPolicy(config.LabelPolicy)
+
+	clusters, err := analyzer.DiscoverClusters(ctx, projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover clusters: %w", err)
+	}
+
+	reports := make(map[string]*report.Report)
+	for _, baseline := range config.GKEBaselines {
+		matched := gke.SelectClusters(clusters, baseline, config.GKEBaselines)
+		driftReport := analyzer.AnalyzeDrift(matched, baseline.ClusterConfig, baseline.NodePoolBaselines())
+		gke.CheckCapacity(driftReport, baseline.Capacity)
+		gke.CheckUniqueness(driftReport, baseline.Unique)
+		reports[baseline.Name] = driftReport.ToReport()
+	}
+	return reports, nil
+}
+
+// persistReport writes a report as JSON to <dir>/<name>-<timestamp>.json
+func persistReport(dir, name string, at time.Time, r *report.Report) (string, error) {
+	output, err := r.FormatJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to format JSON: %w", err)
+	}
+
+	fileName := unsafeFileChars.ReplaceAllString(name+"-"+at.Format("20060102T150405Z"), "_") + ".json"
+	path := filepath.Join(dir, fileName)
+	if err := os.WriteFile(path, []byte(output+"\n"), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
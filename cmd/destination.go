@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/export"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var reportDir string
+
+func init() {
+	gcpCmd.PersistentFlags().StringVar(&reportDir, "report-dir", "", "write each report to this directory or gs://bucket/path under a timestamped name instead of stdout")
+}
+
+// outputConfig is the output section of the config file; flags take precedence
+type outputConfig struct {
+	// ReportDir is the default for --report-dir
+	ReportDir string `yaml:"report_dir"`
+	// SchemaDir is the default for sql db --output-dir
+	SchemaDir string `yaml:"schema_dir"`
+	// BaselineDir is the default for sql db snapshot --baseline-dir
+	BaselineDir string `yaml:"baseline_dir"`
+	// BigQueryTable is the default for --bigquery-table
+	BigQueryTable string `yaml:"bigquery_table"`
+	// LogDestination and LogProject are the defaults for --log-destination
+	// and --log-project
+	LogDestination string `yaml:"log_destination"`
+	LogProject     string `yaml:"log_project"`
+	// LogName is the log entries are written to (default drift-analysis)
+	LogName string `yaml:"log_name"`
+	// SCC exports security drifts to Security Command Center; --scc-source
+	// overrides its source
+	SCC *export.SCCConfig `yaml:"security_command_center"`
+	// Anonymize hashes matching identifiers in exported schema artifacts;
+	// --anonymize adds patterns
+	Anonymize sql.AnonymizeConfig `yaml:"anonymize"`
+}
+
+// loadOutputConfig reads the output section of the config file
+func loadOutputConfig() (outputConfig, error) {
+	var config struct {
+		Output outputConfig `yaml:"output"`
+	}
+	if doc, err := readConfig(); err == nil {
+		if err := decodeConfigNode(doc, &config); err != nil {
+			return outputConfig{}, err
+		}
+	}
+	return config.Output, nil
+}
+
+// reportDestination returns --report-dir, or output.report_dir from the
+// config file; "" prints reports to stdout
+func reportDestination() (string, error) {
+	if reportDir != "" {
+		return reportDir, nil
+	}
+	config, err := loadOutputConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.ReportDir, nil
+}
+
+var (
+	gcsClientMu sync.Mutex
+	gcsClient   *gcs.Client
+)
+
+// sharedGCSClient returns the Cloud Storage client, creating it on first use
+func sharedGCSClient(ctx context.Context) (*gcs.Client, error) {
+	gcsClientMu.Lock()
+	defer gcsClientMu.Unlock()
+
+	if gcsClient == nil {
+		client, err := gcs.NewClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		gcsClient = client
+	}
+	return gcsClient, nil
+}
+
+// writeFile writes data as fileName into a local directory or a gs://
+// location and returns where it was written
+func writeFile(ctx context.Context, location, fileName string, data []byte) (string, error) {
+	if gcs.IsURI(location) {
+		client, err := sharedGCSClient(ctx)
+		if err != nil {
+			return "", err
+		}
+		uri := gcs.Join(location, fileName)
+		if err := client.Upload(ctx, uri, data); err != nil {
+			return "", err
+		}
+		return uri, nil
+	}
+
+	if err := os.MkdirAll(location, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	path := filepath.Join(location, fileName)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// typedReport is a service drift report; text, JSON and YAML are rendered
+// from the service model as on stdout, other formats from the generic model
+type typedReport interface {
+	FormatText() string
+	FormatJSON() (string, error)
+	FormatYAML() (string, error)
+	ToReport() *report.Report
+}
+
+// writeReportFile writes a report in the given format to dir as
+// <name>-<timestamp>.<ext>
+func writeReportFile(ctx context.Context, w io.Writer, dir, name, format, scanID string, r typedReport) error {
+	if format == "tui" {
+		return fmt.Errorf("--report-dir cannot be combined with the tui output format")
+	}
+	ext, ok := formatExtensions[format]
+	if !ok {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	var output string
+	var err error
+	switch format {
+	case "text":
+		output = r.FormatText()
+	case "json":
+		output, err = r.FormatJSON()
+		output += "\n"
+	case "yaml":
+		output, err = r.FormatYAML()
+	default:
+		output, err = renderGeneric(r.ToReport(), format, scanID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", format, err)
+	}
+
+	generic := r.ToReport()
+	fileName := gcs.TimestampedName(unsafeFileChars.ReplaceAllString(name, "_"), "."+ext, generic.Timestamp)
+	path, err := writeFile(ctx, dir, fileName, []byte(output))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Wrote %s (%d resources, %d with drift)\n", path, len(generic.Resources), generic.DriftedCount())
+	return nil
+}
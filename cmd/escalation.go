@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// loadEscalationPolicy reads the escalation section of the config file; nil
+// disables escalation
+func loadEscalationPolicy() (*history.EscalationPolicy, error) {
+	var config struct {
+		Escalation *history.EscalationPolicy `yaml:"escalation"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Escalation.Validate(); err != nil {
+		return nil, err
+	}
+	return config.Escalation, nil
+}
+
+// escalateFindings raises the severity of the current report's drifts that
+// stayed unresolved across the reports recorded under name. The drifts are
+// shared with the report current was converted from, so both are updated.
+// Nothing is escalated without a history store or escalation policy.
+func escalateFindings(ctx context.Context, w io.Writer, store history.ReportStore, policy *history.EscalationPolicy, name string, current *report.Report) error {
+	if store == nil || policy == nil {
+		return nil
+	}
+	past, err := store.LoadReports(ctx, unsafeFileChars.ReplaceAllString(name, "_"))
+	if err != nil {
+		return err
+	}
+	if n := policy.Escalate(past, current); n > 0 {
+		fmt.Fprintf(w, "Escalated %d long-unresolved findings\n", n)
+	}
+	return nil
+}
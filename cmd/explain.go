@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var explainPlan string
+
+func init() {
+	gcpCmd.PersistentFlags().StringVar(&explainPlan, "explain-plan", "", "print the projects, baselines, connections and sinks a run would use (yaml|json) and exit without scanning")
+	gcpCmd.PersistentFlags().Lookup("explain-plan").NoOptDefVal = "yaml"
+}
+
+// runPlan is what a command would query and write, printed by --explain-plan
+type runPlan struct {
+	Command  string   `json:"command" yaml:"command"`
+	Config   string   `json:"config" yaml:"config"`
+	Profile  string   `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Projects []string `json:"projects,omitempty" yaml:"projects,omitempty"`
+	// Labels are the --label and --filter-role selectors every baseline
+	// applies on top of its own filter labels
+	Labels      map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Baselines   []baselinePlan    `json:"baselines,omitempty" yaml:"baselines,omitempty"`
+	Connections []connectionPlan  `json:"connections,omitempty" yaml:"connections,omitempty"`
+	Output      outputPlan        `json:"output" yaml:"output,omitempty"`
+}
+
+// baselinePlan is how a baseline selects resources
+type baselinePlan struct {
+	Name         string            `json:"name" yaml:"name"`
+	FilterLabels map[string]string `json:"filter_labels,omitempty" yaml:"filter_labels,omitempty"`
+	Names        []string          `json:"names,omitempty" yaml:"names,omitempty"`
+	NamePattern  string            `json:"name_pattern,omitempty" yaml:"name_pattern,omitempty"`
+}
+
+// connectionPlan is a database connection that would be inspected
+type connectionPlan struct {
+	Name     string `json:"name" yaml:"name"`
+	Instance string `json:"instance" yaml:"instance"`
+	Database string `json:"database" yaml:"database"`
+}
+
+// outputPlan is where reports and findings would be written
+type outputPlan struct {
+	Format     string `json:"format,omitempty" yaml:"format,omitempty"`
+	ReportDir  string `json:"report_dir,omitempty" yaml:"report_dir,omitempty"`
+	SplitBy    string `json:"split_by,omitempty" yaml:"split_by,omitempty"`
+	SplitDir   string `json:"split_dir,omitempty" yaml:"split_dir,omitempty"`
+	HistoryDir string `json:"history_dir,omitempty" yaml:"history_dir,omitempty"`
+	BadgeDir   string `json:"badge_dir,omitempty" yaml:"badge_dir,omitempty"`
+	Canary     string `json:"canary,omitempty" yaml:"canary,omitempty"`
+	// SchemaDir receives the schemas exported by sql db
+	SchemaDir string `json:"schema_dir,omitempty" yaml:"schema_dir,omitempty"`
+	// Exporters are the sinks every finding is sent to
+	Exporters []string `json:"exporters,omitempty" yaml:"exporters,omitempty"`
+}
+
+// newRunPlan starts the plan of a command with the output flags and config
+// applied
+func newRunPlan(cmd *cobra.Command, projects []string, labels map[string]string, format string) (*runPlan, error) {
+	reportDest, err := reportDestination()
+	if err != nil {
+		return nil, err
+	}
+	exports, err := exportConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &runPlan{
+		Command:  cmd.CommandPath(),
+		Config:   cfgFile,
+		Profile:  profileName,
+		Projects: projects,
+		Output: outputPlan{
+			Format:     format,
+			ReportDir:  reportDest,
+			SplitBy:    splitBy,
+			HistoryDir: historyDir,
+			BadgeDir:   badgeDir,
+			Canary:     canaryFlag,
+		},
+	}
+	if len(labels) > 0 {
+		plan.Labels = labels
+	}
+	if splitBy != "" {
+		plan.Output.SplitDir = splitDir
+	}
+	// Canary samples are never exported
+	if canaryFlag == "" {
+		plan.Output.Exporters = exportDestinations(exports)
+	}
+	return plan, nil
+}
+
+// addBaseline adds a baseline with its filter labels and the names or name
+// pattern it is restricted to
+func (p *runPlan) addBaseline(name string, filterLabels map[string]string, names []string, pattern string) {
+	p.Baselines = append(p.Baselines, baselinePlan{
+		Name:         name,
+		FilterLabels: filterLabels,
+		Names:        names,
+		NamePattern:  pattern,
+	})
+}
+
+// addConnection adds a database connection that would be inspected
+func (p *runPlan) addConnection(conn *sql.DatabaseConnection) {
+	p.Connections = append(p.Connections, connectionPlan{
+		Name:     conn.Name,
+		Instance: conn.GetConnectionName(),
+		Database: conn.Database,
+	})
+}
+
+// explainDatabaseConnections prints the plan of sql db: the connections
+// --all or --connection select and where their schemas are written
+func explainDatabaseConnections(cmd *cobra.Command, cfg *sql.Config) error {
+	plan := &runPlan{
+		Command: cmd.CommandPath(),
+		Config:  cfgFile,
+		Profile: profileName,
+		Output:  outputPlan{SchemaDir: outputDir, HistoryDir: historyDir},
+	}
+	switch {
+	case inspectAll:
+		for i := range cfg.DatabaseConnections {
+			plan.addConnection(&cfg.DatabaseConnections[i])
+		}
+	case dbConnectionName != "":
+		for i := range cfg.DatabaseConnections {
+			if cfg.DatabaseConnections[i].Name == dbConnectionName {
+				plan.addConnection(&cfg.DatabaseConnections[i])
+			}
+		}
+		if len(plan.Connections) == 0 {
+			return fmt.Errorf("connection '%s' not found in config (use --list to see available connections)", dbConnectionName)
+		}
+	default:
+		return fmt.Errorf("connection name is required (use -connection flag, --all for all connections, or --list to see available)")
+	}
+	return printPlan(plan)
+}
+
+// printPlan writes the plan to stdout in the --explain-plan format
+func printPlan(plan *runPlan) error {
+	switch explainPlan {
+	case "yaml":
+		encoder := yaml.NewEncoder(os.Stdout)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(plan); err != nil {
+			return fmt.Errorf("failed to format plan: %w", err)
+		}
+		return encoder.Close()
+	case "json":
+		output, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format plan: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	default:
+		return fmt.Errorf("invalid --explain-plan %q: expected yaml or json", explainPlan)
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/export"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var (
+	bigqueryTable  string
+	logDestination string
+	logProject     string
+	sccSource      string
+)
+
+func init() {
+	gcpCmd.PersistentFlags().StringVar(&bigqueryTable, "bigquery-table", "", "stream every drift finding into this BigQuery table (project.dataset.table), created if missing")
+	gcpCmd.PersistentFlags().StringVar(&logDestination, "log-destination", "", "also write every drift finding as a structured log entry (cloud-logging)")
+	gcpCmd.PersistentFlags().StringVar(&logProject, "log-project", "", "project receiving --log-destination entries (default: each resource's project)")
+	gcpCmd.PersistentFlags().StringVar(&sccSource, "scc-source", "", "create Security Command Center findings for security drifts in this source (organizations/ORG_ID/sources/SOURCE_ID)")
+}
+
+// openExporters returns the exporters enabled by flags or the output section
+// of the config file. Canary samples are never exported.
+func openExporters(ctx context.Context) ([]export.Exporter, error) {
+	config, err := exportConfig()
+	if err != nil {
+		return nil, err
+	}
+	if canaryFlag != "" {
+		return nil, nil
+	}
+	return newExporters(ctx, config)
+}
+
+// exportConfig returns the output section of the config file with the
+// export flags applied
+func exportConfig() (outputConfig, error) {
+	config, err := loadOutputConfig()
+	if err != nil {
+		return config, err
+	}
+	if bigqueryTable != "" {
+		config.BigQueryTable = bigqueryTable
+	}
+	if logDestination != "" {
+		config.LogDestination = logDestination
+	}
+	if logProject != "" {
+		config.LogProject = logProject
+	}
+	if sccSource != "" {
+		if config.SCC == nil {
+			config.SCC = &export.SCCConfig{}
+		}
+		config.SCC.Source = sccSource
+	}
+	return config, nil
+}
+
+// exportDestinations names where the exporters of an output config write
+// findings, without opening them
+func exportDestinations(config outputConfig) []string {
+	var destinations []string
+	if config.BigQueryTable != "" {
+		destinations = append(destinations, "bigquery:"+config.BigQueryTable)
+	}
+	if config.LogDestination != "" {
+		destinations = append(destinations, config.LogDestination)
+	}
+	if config.SCC != nil && config.SCC.Source != "" {
+		destinations = append(destinations, "security-command-center:"+config.SCC.Source)
+	}
+	return destinations
+}
+
+// newExporters creates the BigQuery, log and Security Command Center
+// exporters of an output config; empty settings are skipped
+func newExporters(ctx context.Context, config outputConfig) ([]export.Exporter, error) {
+	var exporters []export.Exporter
+	if config.BigQueryTable != "" {
+		bq, err := export.NewBigQuery(ctx, config.BigQueryTable)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, bq)
+	}
+	switch config.LogDestination {
+	case "":
+	case "cloud-logging":
+		logging, err := export.NewCloudLogging(ctx, config.LogProject, config.LogName)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, logging)
+	default:
+		return nil, fmt.Errorf("unsupported log destination %q (cloud-logging)", config.LogDestination)
+	}
+	if config.SCC != nil && config.SCC.Source != "" {
+		scc, err := export.NewSecurityCommandCenter(ctx, *config.SCC)
+		if err != nil {
+			return nil, err
+		}
+		exporters = append(exporters, scc)
+	}
+	return exporters, nil
+}
+
+// exportFindings writes a report's drift findings to every exporter
+func exportFindings(ctx context.Context, w io.Writer, exporters []export.Exporter, runID string, r *report.Report) error {
+	for _, exporter := range exporters {
+		n, err := exporter.Export(ctx, runID, r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "Exported %d findings to %s\n", n, exporter.Destination())
+	}
+	return nil
+}
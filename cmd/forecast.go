@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// loadForecastPolicy reads the forecast section of the config file; nil
+// disables forecasts
+func loadForecastPolicy() (*history.ForecastPolicy, error) {
+	var config struct {
+		Forecast *history.ForecastPolicy `yaml:"forecast"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Forecast.Validate(); err != nil {
+		return nil, err
+	}
+	return config.Forecast, nil
+}
+
+// forecastGrowth projects the metrics of the reports recorded under name
+// followed by the current report. Nothing is projected without a history
+// store or forecast policy.
+func forecastGrowth(ctx context.Context, store history.ReportStore, policy *history.ForecastPolicy, name string, current *report.Report) ([]history.Projection, error) {
+	if store == nil || policy == nil {
+		return nil, nil
+	}
+	past, err := store.LoadReports(ctx, unsafeFileChars.ReplaceAllString(name, "_"))
+	if err != nil {
+		return nil, err
+	}
+	return policy.Forecast(append(past, current)), nil
+}
+
+// schemaHistory records the size of inspected databases and forecasts their
+// growth; a nil schemaHistory records nothing
+type schemaHistory struct {
+	store  history.ReportStore
+	policy *history.ForecastPolicy
+}
+
+// openSchemaHistory opens the history store for --history-dir, or returns
+// nil when database sizes are not recorded
+func openSchemaHistory(ctx context.Context) (*schemaHistory, error) {
+	store, err := openHistory(ctx)
+	if err != nil || store == nil {
+		return nil, err
+	}
+	policy, err := loadForecastPolicy()
+	if err != nil {
+		return nil, err
+	}
+	return &schemaHistory{store: store, policy: policy}, nil
+}
+
+// record saves an inspected database as sqldb-<connection> and prints a
+// forecast when its size is projected to reach the configured limit
+func (h *schemaHistory) record(ctx context.Context, w io.Writer, conn *sql.DatabaseConnection, schema *sql.DatabaseSchema) error {
+	if h == nil {
+		return nil
+	}
+
+	var result *sql.SchemaValidationResult
+	if conn.SchemaBaseline != nil {
+		result = sql.ValidateSchemaAgainstBaseline(schema, conn.SchemaBaseline)
+	}
+	r := &report.Report{
+		Title:     "Cloud SQL Database Schema Report",
+		Timestamp: time.Now(),
+		Resources: []report.Resource{sql.SchemaResource(conn, schema, result)},
+	}
+
+	name := "sqldb-" + conn.Name
+	projections, err := forecastGrowth(ctx, h.store, h.policy, name, r)
+	if err != nil {
+		return err
+	}
+	for _, p := range projections {
+		fmt.Fprintf(w, "  [FORECAST] %s: %s (expected %s)\n", p.Metric, p.Actual(), p.Expected())
+	}
+	return recordHistory(ctx, w, h.store, name, r)
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/bigquery"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+var bigqueryOutputFormat string
+
+// bigqueryCmd represents the bigquery command
+var bigqueryCmd = &cobra.Command{
+	Use:   "bigquery",
+	Short: "Analyze BigQuery datasets for configuration drift",
+	Long: `Analyze BigQuery datasets against baseline configurations.
+Compares location, default table expiration, CMEK encryption and access
+entries. Datasets shared with allUsers or allAuthenticatedUsers are always
+reported as critical drift.`,
+	RunE: runBigQueryAnalysis,
+}
+
+func init() {
+	gcpCmd.AddCommand(bigqueryCmd)
+	bigqueryCmd.Flags().StringVarP(&bigqueryOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+}
+
+func runBigQueryAnalysis(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Read config file
+	var config struct {
+		Projects          []string                    `yaml:"projects"`
+		BigQueryBaselines []bigquery.BigQueryBaseline `yaml:"bigquery_baselines"`
+	}
+
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	if len(config.BigQueryBaselines) == 0 {
+		return fmt.Errorf("no BigQuery baselines defined in config")
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, nil, bigqueryOutputFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range config.BigQueryBaselines {
+			plan.addBaseline(baseline.Name, baseline.FilterLabels, nil, "")
+		}
+		return printPlan(plan)
+	}
+	canary, err := newCanarySampler()
+	if err != nil {
+		return err
+	}
+
+	staleness, err := loadStalenessPolicy()
+	if err != nil {
+		return err
+	}
+	historyStore, err := openHistory(ctx)
+	if err != nil {
+		return err
+	}
+	reportDest, err := reportDestination()
+	if err != nil {
+		return err
+	}
+	acknowledged, err := activeSuppressions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	escalation, err := loadEscalationPolicy()
+	if err != nil {
+		return err
+	}
+	sloPolicy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+	exporters, err := openExporters(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Create analyzer
+	analyzer, err := bigquery.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create BigQuery analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+
+	// Discover datasets once; baselines only filter them
+	datasets, err := analyzer.DiscoverDatasets(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover datasets: %w", err)
+	}
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if genericFormats[bigqueryOutputFormat] || splitBy != "" {
+		progress = os.Stderr
+	}
+	scanID := newScanID()
+
+	// Run analysis for each baseline
+	for _, baseline := range config.BigQueryBaselines {
+		fmt.Fprintf(progress, "Analyzing BigQuery datasets: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
+
+		matched := bigquery.FilterByLabels(datasets, baseline.FilterLabels)
+		report := analyzer.AnalyzeDrift(canarySample(canary, matched), baseline.Config)
+		if err := escalateFindings(ctx, progress, historyStore, escalation, "bigquery-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := linker.Annotate(report.ToReport()); err != nil {
+			return err
+		}
+		annotateRemediation(report.ToReport())
+		if err := annotateGitHub(progress, "bigquery_baselines", baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		report.Warnings = baselineWarnings(staleness, baseline.Name, baseline.Metadata, nil, nil)
+		report.Acknowledgements = history.Acknowledgements(report.ToReport(), acknowledged)
+		if report.SLO, err = measureSLO(ctx, historyStore, sloPolicy, "bigquery-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		stabilize(&report.Timestamp, report.Datasets, func(d *bigquery.DatasetDrift) ([]string, []driftreport.Drift) {
+			return []string{d.Project, d.Location, d.Name}, d.Drifts
+		})
+		printCanary(progress, canary, report.ToReport(), len(matched))
+		if err := recordHistory(ctx, progress, historyStore, "bigquery-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := exportFindings(ctx, progress, exporters, scanID, report.ToReport()); err != nil {
+			return err
+		}
+
+		if splitBy != "" {
+			if err := writeSplitReports(report.ToReport(), bigqueryOutputFormat, scanID, "bigquery-"+baseline.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reportDest != "" {
+			if err := writeReportFile(ctx, progress, reportDest, "bigquery-"+baseline.Name, bigqueryOutputFormat, scanID, report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Output report
+		switch bigqueryOutputFormat {
+		case "tui":
+			return tui.Run(tui.FromReport(report.ToReport()))
+		case "json":
+			output, err := report.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(output)
+		case "yaml":
+			output, err := report.FormatYAML()
+			if err != nil {
+				return fmt.Errorf("failed to format YAML: %w", err)
+			}
+			fmt.Println(output)
+		case "ndjson", "dot", "mermaid", "sarif", "gitlab-codequality", "junit", "plan":
+			if err := printGeneric(report.ToReport(), bigqueryOutputFormat, scanID); err != nil {
+				return err
+			}
+			continue
+		default:
+			fmt.Println(report.FormatText())
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -4,50 +4,147 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/metacache"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
 	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
-var gkeOutputFormat string
+var (
+	gkeOutputFormat   string
+	gkeEstimateCost   bool
+	gkeGenerateConfig bool
+	gkeGroupBy        string
+)
 
 // gkeCmd represents the gke command
 var gkeCmd = &cobra.Command{
 	Use:   "gke",
 	Short: "Analyze GKE clusters for configuration drift",
 	Long: `Analyze Google Kubernetes Engine clusters against baseline configurations.
-Compares cluster settings, node pool configurations, networking, and security settings.`,
+Compares cluster settings, node pool configurations, networking, and security settings.
+
+Use --generate-config to print a gke_baselines section built from the
+discovered clusters, with --group-by <label> for one baseline per label value
+selected through filter_labels.
+
+Use --tui to browse the report interactively, with --watch to re-run the
+analysis from the terminal UI.`,
 	RunE: runGKEAnalysis,
 }
 
 func init() {
 	gcpCmd.AddCommand(gkeCmd)
-	gkeCmd.Flags().StringVarP(&gkeOutputFormat, "output", "o", "text", "output format (text|json|yaml|tui)")
+	gkeCmd.Flags().StringVarP(&gkeOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+	gkeCmd.Flags().BoolVar(&gkeGenerateConfig, "generate-config", false, "generate baselines from the current state instead of analyzing")
+	gkeCmd.Flags().StringVar(&gkeGroupBy, "group-by", "", "with --generate-config, generate one baseline per value of this label (e.g. cluster-role)")
+	gkeCmd.Flags().BoolVar(&gkeEstimateCost, "estimate-cost", false, "annotate node pool drifts with their hourly cost delta, priced from the Cloud Billing Catalog")
 }
 
 func runGKEAnalysis(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// Read config file
-	configData, err := os.ReadFile(cfgFile)
-	if err != nil {
+	doc, err := readConfig()
+	if err != nil && !(gkeGenerateConfig && os.IsNotExist(err)) {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
+	if gkeGroupBy != "" && !gkeGenerateConfig {
+		return fmt.Errorf("--group-by requires --generate-config")
+	}
+	if err := resolveTUI(cmd, &gkeOutputFormat); err != nil {
+		return err
+	}
 
 	var config struct {
 		Projects     []string          `yaml:"projects"`
 		GKEBaselines []gke.GKEBaseline `yaml:"gke_baselines"`
 	}
 
-	if err := yaml.Unmarshal(configData, &config); err != nil {
-		return fmt.Errorf("failed to parse config: %w", err)
+	if err := decodeConfigNode(doc, &config); err != nil {
+		return err
 	}
 
-	if len(config.GKEBaselines) == 0 {
+	if len(config.GKEBaselines) == 0 && !gkeGenerateConfig {
 		return fmt.Errorf("no GKE baselines defined in config")
 	}
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	labels, err := labelSelector("cluster-role")
+	if err != nil {
+		return err
+	}
+	if gkeGenerateConfig {
+		return generateGKEConfig(ctx, projects, labels)
+	}
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, labels, gkeOutputFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range config.GKEBaselines {
+			plan.addBaseline(baseline.Name, baseline.FilterLabels, baseline.InstanceNames, baseline.NamePattern)
+		}
+		return printPlan(plan)
+	}
+	canary, err := newCanarySampler()
+	if err != nil {
+		return err
+	}
+
+	staleness, err := loadStalenessPolicy()
+	if err != nil {
+		return err
+	}
+	historyStore, err := openHistory(ctx)
+	if err != nil {
+		return err
+	}
+	reportDest, err := reportDestination()
+	if err != nil {
+		return err
+	}
+	acknowledged, err := activeSuppressions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	escalation, err := loadEscalationPolicy()
+	if err != nil {
+		return err
+	}
+	sloPolicy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+	operational, err := loadOperationalPolicy()
+	if err != nil {
+		return err
+	}
+	labelPolicy, err := loadLabelPolicy()
+	if err != nil {
+		return err
+	}
+	exporters, err := openExporters(ctx)
+	if err != nil {
+		return err
+	}
 
 	// Create analyzer
 	analyzer, err := gke.NewAnalyzer(ctx)
@@ -55,45 +152,131 @@ func runGKEAnalysis(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create GKE analyzer: %w", err)
 	}
 	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+	analyzer.SetRetryPolicy(retryPolicy())
+	if operational != nil {
+		analyzer.SetOperationalSeverities(operational.GKE)
+	}
+	analyzer.SetLabelPolicy(labelPolicy)
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if genericFormats[gkeOutputFormat] || splitBy != "" {
+		progress = os.Stderr
+	}
+	scanID := newScanID()
+	metadata := newMetadataCache()
+	var estimator *gke.CostEstimator
+	if gkeEstimateCost {
+		estimator, err = gke.NewCostEstimator(ctx, metacache.New(metadataCacheDir(), metadataCacheTTL))
+		if err != nil {
+			return err
+		}
+	}
 
 	// Run analysis for each baseline
 	for _, baseline := range config.GKEBaselines {
-		fmt.Printf("Analyzing GKE clusters: %s\n", baseline.Name)
-		fmt.Println("================================================================================")
+		fmt.Fprintf(progress, "Analyzing GKE clusters: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
 
 		// Discover clusters
-		clusters, err := analyzer.DiscoverClusters(ctx, config.Projects)
+		clusters, err := analyzer.DiscoverClusters(ctx, projects)
 		if err != nil {
 			return fmt.Errorf("failed to discover clusters: %w", err)
 		}
 
-		// Filter by labels if specified
-		if len(baseline.FilterLabels) > 0 {
-			filtered := make([]*gke.ClusterInstance, 0)
-			for _, cluster := range clusters {
-				matches := true
-				for key, value := range baseline.FilterLabels {
-					if cluster.Labels[key] != value {
-						matches = false
-						break
-					}
-				}
-				if matches {
-					filtered = append(filtered, cluster)
-				}
+		// Filter by the baseline's labels and names, then by --label
+		clusters = gke.SelectClusters(clusters, baseline, config.GKEBaselines)
+		clusters = gke.FilterByLabels(clusters, labels)
+
+		population := len(clusters)
+		clusters = canarySample(canary, clusters)
+
+		// Analyze drift
+		report := analyzer.AnalyzeDrift(clusters, baseline.ClusterConfig, baseline.NodePoolBaselines())
+		gke.CheckCapacity(report, baseline.Capacity)
+		gke.CheckUniqueness(report, baseline.Unique)
+		if err := escalateFindings(ctx, progress, historyStore, escalation, "gke-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := linker.Annotate(report.ToReport()); err != nil {
+			return err
+		}
+		annotateRemediation(report.ToReport())
+		if err := annotateGitHub(progress, "gke_baselines", baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		versions, machineTypes := baseline.Expected()
+		report.Warnings = baselineWarnings(staleness, baseline.Name, baseline.Metadata, versions, machineTypes)
+		report.Acknowledgements = history.Acknowledgements(report.ToReport(), acknowledged)
+		if report.SLO, err = measureSLO(ctx, historyStore, sloPolicy, "gke-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if metadata != nil {
+			warnMetadataLookup(progress, analyzer.AnnotateStaleBaseline(ctx, metadata, report, baseline.ClusterConfig))
+		}
+		if estimator != nil {
+			if err := gke.AnnotateCost(ctx, report, estimator.PriceList); err != nil {
+				fmt.Fprintf(progress, "Warning: could not estimate node pool costs: %v\n", err)
 			}
-			clusters = filtered
+		}
+		stabilize(&report.Timestamp, report.Instances, func(c *gke.ClusterDrift) ([]string, []driftreport.Drift) {
+			return []string{c.Project, c.Location, c.Name}, c.Drifts
+		})
+		printCanary(progress, canary, report.ToReport(), population)
+		if err := recordHistory(ctx, progress, historyStore, "gke-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := exportFindings(ctx, progress, exporters, scanID, report.ToReport()); err != nil {
+			return err
 		}
 
-		// Analyze drift
-		report := analyzer.AnalyzeDrift(clusters, baseline.ClusterConfig, baseline.NodePoolConfig)
+		if badgeDir != "" {
+			path, err := writeBadge(badgeDir, "gke-"+baseline.Name, report.ToReport())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(progress, "Wrote badge %s\n", path)
+		}
+
+		if splitBy != "" {
+			if err := writeSplitReports(report.ToReport(), gkeOutputFormat, scanID, "gke-"+baseline.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reportDest != "" {
+			if err := writeReportFile(ctx, progress, reportDest, "gke-"+baseline.Name, gkeOutputFormat, scanID, report); err != nil {
+				return err
+			}
+			continue
+		}
 
 		// Output report
 		switch gkeOutputFormat {
 		case "tui":
 			// Convert to TUI format and run interactive display
 			tuiData := tui.FromGKEReport(report)
-			return tui.Run(tuiData)
+			if !tuiWatch {
+				return tui.Run(tuiData)
+			}
+			return tui.RunLive(tuiData, func(ctx context.Context) (tui.ReportData, error) {
+				clusters, err := analyzer.DiscoverClusters(ctx, projects)
+				if err != nil {
+					return tui.ReportData{}, fmt.Errorf("failed to discover clusters: %w", err)
+				}
+				clusters = gke.SelectClusters(clusters, baseline, config.GKEBaselines)
+				clusters = gke.FilterByLabels(clusters, labels)
+				report := analyzer.AnalyzeDrift(clusters, baseline.ClusterConfig, baseline.NodePoolBaselines())
+				gke.CheckCapacity(report, baseline.Capacity)
+				gke.CheckUniqueness(report, baseline.Unique)
+				if err := linker.Annotate(report.ToReport()); err != nil {
+					return tui.ReportData{}, err
+				}
+				annotateRemediation(report.ToReport())
+				return tui.FromGKEReport(report), nil
+			})
 		case "json":
 			output, err := report.FormatJSON()
 			if err != nil {
@@ -106,6 +289,11 @@ func runGKEAnalysis(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("failed to format YAML: %w", err)
 			}
 			fmt.Println(output)
+		case "ndjson", "dot", "mermaid", "sarif", "gitlab-codequality", "junit", "plan":
+			if err := printGeneric(report.ToReport(), gkeOutputFormat, scanID); err != nil {
+				return err
+			}
+			continue
 		default:
 			fmt.Println(report.FormatText())
 		}
@@ -115,3 +303,25 @@ func runGKEAnalysis(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// generateGKEConfig prints baselines built from the clusters selected by
+// --label, grouped by --group-by
+func generateGKEConfig(ctx context.Context, projects []string, labels map[string]string) error {
+	analyzer, err := gke.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GKE analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	analyzer.SetRetryPolicy(retryPolicy())
+
+	clusters, err := analyzer.DiscoverClusters(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %w", err)
+	}
+	output, err := gke.GenerateBaselineConfig(gke.FilterByLabels(clusters, labels), gkeGroupBy)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var gkeWorkloadsFormat string
+
+// gkeWorkloadsCmd represents the gke workloads command
+var gkeWorkloadsCmd = &cobra.Command{
+	Use:   "workloads",
+	Short: "Inspect Kubernetes objects in GKE clusters against the baselines",
+	Long: `Connect to the Kubernetes API of each cluster a GKE baseline selects and
+compare its in-cluster state against the baseline's workloads section:
+required namespaces, Pod Security Standards, deployments and DaemonSets.
+
+Clusters are reached with the application default credentials, like kubectl
+after gcloud container clusters get-credentials. The credentials need
+permission to list namespaces, deployments and daemonsets.`,
+	RunE: runGKEWorkloads,
+}
+
+func init() {
+	gkeCmd.AddCommand(gkeWorkloadsCmd)
+	gkeWorkloadsCmd.Flags().StringVarP(&gkeWorkloadsFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+}
+
+func runGKEWorkloads(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var config struct {
+		Projects     []string          `yaml:"projects"`
+		GKEBaselines []gke.GKEBaseline `yaml:"gke_baselines"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	var baselines []gke.GKEBaseline
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+		if baseline.Workloads != nil {
+			baselines = append(baselines, baseline)
+		}
+	}
+	if len(baselines) == 0 {
+		return fmt.Errorf("no GKE baselines define workloads in config")
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+	labels, err := labelSelector("cluster-role")
+	if err != nil {
+		return err
+	}
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, labels, gkeWorkloadsFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range baselines {
+			plan.addBaseline(baseline.Name, baseline.FilterLabels, baseline.InstanceNames, baseline.NamePattern)
+		}
+		return printPlan(plan)
+	}
+
+	analyzer, err := gke.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GKE analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+	analyzer.SetRetryPolicy(retryPolicy())
+
+	inspector, err := gke.NewWorkloadInspector(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create workload inspector: %w", err)
+	}
+	defer applyContinueOnError(inspector)()
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if gkeWorkloadsFormat != "text" {
+		progress = os.Stderr
+	}
+
+	clusters, err := analyzer.DiscoverClusters(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %w", err)
+	}
+
+	for _, baseline := range baselines {
+		fmt.Fprintf(progress, "Inspecting GKE workloads: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
+
+		matched := gke.SelectClusters(clusters, baseline, config.GKEBaselines)
+		matched = gke.FilterByLabels(matched, labels)
+
+		driftReport, err := inspector.AnalyzeWorkloads(ctx, matched, baseline.Workloads)
+		if err != nil {
+			return err
+		}
+		stabilize(&driftReport.Timestamp, driftReport.Instances, func(c *gke.ClusterDrift) ([]string, []driftreport.Drift) {
+			return []string{c.Project, c.Location, c.Name}, c.Drifts
+		})
+
+		r := driftReport.ToReport()
+		r.Title = "GKE Workload Drift Report: " + baseline.Name
+		if err := printReport(r, gkeWorkloadsFormat); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/pubsub"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+var pubsubOutputFormat string
+
+// pubsubCmd represents the pubsub command
+var pubsubCmd = &cobra.Command{
+	Use:   "pubsub",
+	Short: "Analyze Pub/Sub topics and subscriptions for configuration drift",
+	Long: `Analyze Pub/Sub topics and subscriptions against baseline configurations.
+Compares topic retention, CMEK encryption and schema settings, and
+subscription ack deadlines, retention, dead-letter policies, exactly-once
+delivery and message ordering.`,
+	RunE: runPubSubAnalysis,
+}
+
+func init() {
+	gcpCmd.AddCommand(pubsubCmd)
+	pubsubCmd.Flags().StringVarP(&pubsubOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+}
+
+func runPubSubAnalysis(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Read config file
+	var config struct {
+		Projects        []string                `yaml:"projects"`
+		PubSubBaselines []pubsub.PubSubBaseline `yaml:"pubsub_baselines"`
+	}
+
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	if len(config.PubSubBaselines) == 0 {
+		return fmt.Errorf("no Pub/Sub baselines defined in config")
+	}
+	for _, baseline := range config.PubSubBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid Pub/Sub baseline: %w", err)
+		}
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, nil, pubsubOutputFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range config.PubSubBaselines {
+			plan.addBaseline(baseline.Name, baseline.FilterLabels, nil, "")
+		}
+		return printPlan(plan)
+	}
+	canary, err := newCanarySampler()
+	if err != nil {
+		return err
+	}
+
+	staleness, err := loadStalenessPolicy()
+	if err != nil {
+		return err
+	}
+	historyStore, err := openHistory(ctx)
+	if err != nil {
+		return err
+	}
+	reportDest, err := reportDestination()
+	if err != nil {
+		return err
+	}
+	acknowledged, err := activeSuppressions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	escalation, err := loadEscalationPolicy()
+	if err != nil {
+		return err
+	}
+	sloPolicy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+	exporters, err := openExporters(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Create analyzer
+	analyzer, err := pubsub.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Pub/Sub analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+
+	// Discover topics and subscriptions once; baselines only filter them
+	topics, err := analyzer.DiscoverTopics(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover topics: %w", err)
+	}
+	subscriptions, err := analyzer.DiscoverSubscriptions(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover subscriptions: %w", err)
+	}
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if genericFormats[pubsubOutputFormat] || splitBy != "" {
+		progress = os.Stderr
+	}
+	scanID := newScanID()
+
+	// Run analysis for each baseline
+	for _, baseline := range config.PubSubBaselines {
+		fmt.Fprintf(progress, "Analyzing Pub/Sub resources: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
+
+		var baselineTopics []*pubsub.Topic
+		if baseline.TopicConfig != nil {
+			baselineTopics = pubsub.FilterTopics(topics, baseline.FilterLabels)
+		}
+		var baselineSubscriptions []*pubsub.Subscription
+		if baseline.SubscriptionConfig != nil {
+			baselineSubscriptions = pubsub.FilterSubscriptions(subscriptions, baseline.FilterLabels)
+		}
+
+		population := len(baselineTopics) + len(baselineSubscriptions)
+		report := analyzer.AnalyzeDrift(canarySample(canary, baselineTopics), canarySample(canary, baselineSubscriptions), baseline)
+		if err := escalateFindings(ctx, progress, historyStore, escalation, "pubsub-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := linker.Annotate(report.ToReport()); err != nil {
+			return err
+		}
+		annotateRemediation(report.ToReport())
+		if err := annotateGitHub(progress, "pubsub_baselines", baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		report.Warnings = baselineWarnings(staleness, baseline.Name, baseline.Metadata, nil, nil)
+		report.Acknowledgements = history.Acknowledgements(report.ToReport(), acknowledged)
+		if report.SLO, err = measureSLO(ctx, historyStore, sloPolicy, "pubsub-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		stabilize(&report.Timestamp, report.Resources, func(r *pubsub.ResourceDrift) ([]string, []driftreport.Drift) {
+			return []string{r.Kind, r.Project, r.Name}, r.Drifts
+		})
+		printCanary(progress, canary, report.ToReport(), population)
+		if err := recordHistory(ctx, progress, historyStore, "pubsub-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := exportFindings(ctx, progress, exporters, scanID, report.ToReport()); err != nil {
+			return err
+		}
+
+		if splitBy != "" {
+			if err := writeSplitReports(report.ToReport(), pubsubOutputFormat, scanID, "pubsub-"+baseline.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reportDest != "" {
+			if err := writeReportFile(ctx, progress, reportDest, "pubsub-"+baseline.Name, pubsubOutputFormat, scanID, report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Output report
+		switch pubsubOutputFormat {
+		case "tui":
+			return tui.Run(tui.FromReport(report.ToReport()))
+		case "json":
+			output, err := report.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(output)
+		case "yaml":
+			output, err := report.FormatYAML()
+			if err != nil {
+				return fmt.Errorf("failed to format YAML: %w", err)
+			}
+			fmt.Println(output)
+		case "ndjson", "dot", "mermaid", "sarif", "gitlab-codequality", "junit", "plan":
+			if err := printGeneric(report.ToReport(), pubsubOutputFormat, scanID); err != nil {
+				return err
+			}
+			continue
+		default:
+			fmt.Println(report.FormatText())
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/redis"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	redisOutputFormat   string
+	redisGenerateConfig bool
+)
+
+// redisCmd represents the redis command
+var redisCmd = &cobra.Command{
+	Use:   "redis",
+	Short: "Analyze Memorystore for Redis instances for configuration drift",
+	Long: `Analyze Memorystore for Redis instances against baseline configurations.
+Compares tier, memory size, Redis version, AUTH, TLS mode, maintenance window,
+and read replicas.
+
+Use --generate-config to print a redis_baselines section built from the
+first discovered instance.`,
+	RunE: runRedisAnalysis,
+}
+
+func init() {
+	gcpCmd.AddCommand(redisCmd)
+	redisCmd.Flags().StringVarP(&redisOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+	redisCmd.Flags().BoolVar(&redisGenerateConfig, "generate-config", false, "generate a baseline config from the current state")
+}
+
+func runRedisAnalysis(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Read config file
+	doc, err := readConfig()
+	if err != nil && !(redisGenerateConfig && os.IsNotExist(err)) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config struct {
+		Projects       []string              `yaml:"projects"`
+		RedisBaselines []redis.RedisBaseline `yaml:"redis_baselines"`
+	}
+
+	if err := decodeConfigNode(doc, &config); err != nil {
+		return err
+	}
+
+	if len(config.RedisBaselines) == 0 && !redisGenerateConfig {
+		return fmt.Errorf("no Redis baselines defined in config")
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, nil, redisOutputFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range config.RedisBaselines {
+			plan.addBaseline(baseline.Name, baseline.FilterLabels, nil, "")
+		}
+		return printPlan(plan)
+	}
+	canary, err := newCanarySampler()
+	if err != nil {
+		return err
+	}
+
+	staleness, err := loadStalenessPolicy()
+	if err != nil {
+		return err
+	}
+	historyStore, err := openHistory(ctx)
+	if err != nil {
+		return err
+	}
+	reportDest, err := reportDestination()
+	if err != nil {
+		return err
+	}
+	acknowledged, err := activeSuppressions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	escalation, err := loadEscalationPolicy()
+	if err != nil {
+		return err
+	}
+	sloPolicy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+	exporters, err := openExporters(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Create analyzer
+	analyzer, err := redis.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create Redis analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+
+	// Discover instances once; baselines only filter them
+	instances, err := analyzer.DiscoverInstances(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover instances: %w", err)
+	}
+
+	if redisGenerateConfig {
+		output, err := redis.GenerateBaselineConfig(instances)
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+		return nil
+	}
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if genericFormats[redisOutputFormat] || splitBy != "" {
+		progress = os.Stderr
+	}
+	scanID := newScanID()
+
+	// Run analysis for each baseline
+	for _, baseline := range config.RedisBaselines {
+		fmt.Fprintf(progress, "Analyzing Redis instances: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
+
+		matched := redis.FilterByLabels(instances, baseline.FilterLabels)
+		report := analyzer.AnalyzeDrift(canarySample(canary, matched), baseline.Config)
+		if err := escalateFindings(ctx, progress, historyStore, escalation, "redis-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := linker.Annotate(report.ToReport()); err != nil {
+			return err
+		}
+		annotateRemediation(report.ToReport())
+		if err := annotateGitHub(progress, "redis_baselines", baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		versions, tiers := baseline.Expected()
+		report.Warnings = baselineWarnings(staleness, baseline.Name, baseline.Metadata, versions, tiers)
+		report.Acknowledgements = history.Acknowledgements(report.ToReport(), acknowledged)
+		if report.SLO, err = measureSLO(ctx, historyStore, sloPolicy, "redis-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		stabilize(&report.Timestamp, report.Instances, func(i *redis.InstanceDrift) ([]string, []driftreport.Drift) {
+			return []string{i.Project, i.Location, i.Name}, i.Drifts
+		})
+		printCanary(progress, canary, report.ToReport(), len(matched))
+		if err := recordHistory(ctx, progress, historyStore, "redis-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := exportFindings(ctx, progress, exporters, scanID, report.ToReport()); err != nil {
+			return err
+		}
+
+		if splitBy != "" {
+			if err := writeSplitReports(report.ToReport(), redisOutputFormat, scanID, "redis-"+baseline.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reportDest != "" {
+			if err := writeReportFile(ctx, progress, reportDest, "redis-"+baseline.Name, redisOutputFormat, scanID, report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Output report
+		switch redisOutputFormat {
+		case "tui":
+			return tui.Run(tui.FromReport(report.ToReport()))
+		case "json":
+			output, err := report.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(output)
+		case "yaml":
+			output, err := report.FormatYAML()
+			if err != nil {
+				return fmt.Errorf("failed to format YAML: %w", err)
+			}
+			fmt.Println(output)
+		case "ndjson", "dot", "mermaid", "sarif", "gitlab-codequality", "junit", "plan":
+			if err := printGeneric(report.ToReport(), redisOutputFormat, scanID); err != nil {
+				return err
+			}
+			continue
+		default:
+			fmt.Println(report.FormatText())
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
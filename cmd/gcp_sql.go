@@ -4,50 +4,155 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
 	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
-var sqlOutputFormat string
+var (
+	sqlOutputFormat   string
+	sqlGenerateConfig bool
+	sqlGroupBy        string
+)
 
 // sqlCmd represents the sql command
 var sqlCmd = &cobra.Command{
 	Use:   "sql",
 	Short: "Analyze Cloud SQL instances for configuration drift",
 	Long: `Analyze Google Cloud SQL instances against baseline configurations.
-Compares database flags, settings, backups, and more.`,
+Compares database flags, settings, backups, and more.
+
+Use --generate-config to print a sql_baselines section built from the
+discovered instances, with --group-by <label> for one baseline per label value
+selected through filter_labels.
+
+Use --tui to browse the report interactively, with --watch to re-run the
+analysis from the terminal UI.`,
 	RunE: runSQLAnalysis,
 }
 
 func init() {
 	gcpCmd.AddCommand(sqlCmd)
-	sqlCmd.Flags().StringVarP(&sqlOutputFormat, "output", "o", "text", "output format (text|json|yaml|tui)")
+	sqlCmd.Flags().StringVarP(&sqlOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+	sqlCmd.Flags().BoolVar(&sqlGenerateConfig, "generate-config", false, "generate baselines from the current state instead of analyzing")
+	sqlCmd.Flags().StringVar(&sqlGroupBy, "group-by", "", "with --generate-config, generate one baseline per value of this label (e.g. database-role)")
 }
 
 func runSQLAnalysis(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
 	// Read config file
-	configData, err := os.ReadFile(cfgFile)
-	if err != nil {
+	doc, err := readConfig()
+	if err != nil && !(sqlGenerateConfig && os.IsNotExist(err)) {
 		return fmt.Errorf("failed to read config file: %w", err)
 	}
+	if sqlGroupBy != "" && !sqlGenerateConfig {
+		return fmt.Errorf("--group-by requires --generate-config")
+	}
+	if err := resolveTUI(cmd, &sqlOutputFormat); err != nil {
+		return err
+	}
 
 	var config struct {
 		Projects     []string          `yaml:"projects"`
 		SQLBaselines []sql.SQLBaseline `yaml:"sql_baselines"`
+		Policy       struct {
+			SQL *sql.Policy `yaml:"sql"`
+		} `yaml:"policy"`
+		DatabaseOwners sql.DatabaseOwners `yaml:"database_owners"`
 	}
 
-	if err := yaml.Unmarshal(configData, &config); err != nil {
-		return fmt.Errorf("failed to parse config: %w", err)
+	if err := decodeConfigNode(doc, &config); err != nil {
+		return err
 	}
 
-	if len(config.SQLBaselines) == 0 {
+	if len(config.SQLBaselines) == 0 && !sqlGenerateConfig {
 		return fmt.Errorf("no SQL baselines defined in config")
 	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	if err := config.DatabaseOwners.Validate(); err != nil {
+		return err
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	labels, err := labelSelector("database-role")
+	if err != nil {
+		return err
+	}
+	if sqlGenerateConfig {
+		return generateSQLConfig(ctx, projects, labels)
+	}
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, labels, sqlOutputFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range config.SQLBaselines {
+			plan.addBaseline(baseline.Name, baseline.FilterLabels, baseline.InstanceNames, baseline.NamePattern)
+		}
+		return printPlan(plan)
+	}
+	canary, err := newCanarySampler()
+	if err != nil {
+		return err
+	}
+
+	staleness, err := loadStalenessPolicy()
+	if err != nil {
+		return err
+	}
+	historyStore, err := openHistory(ctx)
+	if err != nil {
+		return err
+	}
+	forecast, err := loadForecastPolicy()
+	if err != nil {
+		return err
+	}
+	reportDest, err := reportDestination()
+	if err != nil {
+		return err
+	}
+	acknowledged, err := activeSuppressions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	escalation, err := loadEscalationPolicy()
+	if err != nil {
+		return err
+	}
+	sloPolicy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+	operational, err := loadOperationalPolicy()
+	if err != nil {
+		return err
+	}
+	labelPolicy, err := loadLabelPolicy()
+	if err != nil {
+		return err
+	}
+	exporters, err := openExporters(ctx)
+	if err != nil {
+		return err
+	}
 
 	// Create analyzer
 	analyzer, err := sql.NewAnalyzer(ctx)
@@ -55,45 +160,126 @@ func runSQLAnalysis(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create SQL analyzer: %w", err)
 	}
 	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+	analyzer.SetRetryPolicy(retryPolicy())
+	if operational != nil {
+		analyzer.SetOperationalSeverities(operational.SQL)
+	}
+	analyzer.SetLabelPolicy(labelPolicy)
+	analyzer.SetPolicy(config.Policy.SQL)
+	analyzer.SetDatabaseOwners(config.DatabaseOwners)
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if genericFormats[sqlOutputFormat] || splitBy != "" {
+		progress = os.Stderr
+	}
+	scanID := newScanID()
+	metadata := newMetadataCache()
 
 	// Run analysis for each baseline
 	for _, baseline := range config.SQLBaselines {
-		fmt.Printf("Analyzing SQL instances: %s\n", baseline.Name)
-		fmt.Println("================================================================================")
+		fmt.Fprintf(progress, "Analyzing SQL instances: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
 
 		// Discover instances
-		instances, err := analyzer.DiscoverInstances(ctx, config.Projects)
+		instances, err := analyzer.DiscoverInstances(ctx, projects)
 		if err != nil {
 			return fmt.Errorf("failed to discover instances: %w", err)
 		}
 
-		// Filter by labels if specified
-		if len(baseline.FilterLabels) > 0 {
-			filtered := make([]*sql.DatabaseInstance, 0)
-			for _, inst := range instances {
-				matches := true
-				for key, value := range baseline.FilterLabels {
-					if inst.Labels[key] != value {
-						matches = false
-						break
-					}
-				}
-				if matches {
-					filtered = append(filtered, inst)
-				}
-			}
-			instances = filtered
-		}
+		// Filter by the baseline's labels and names, then by --label
+		instances = sql.SelectInstances(instances, baseline, config.SQLBaselines)
+		instances = sql.FilterByLabels(instances, labels)
+
+		population := len(instances)
+		instances = canarySample(canary, instances)
 
 		// Analyze drift
 		report := analyzer.AnalyzeDrift(instances, baseline.Config)
+		sql.CheckCapacity(report, baseline.Capacity)
+		sql.CheckUniqueness(report, baseline.Unique)
+		projections, err := forecastGrowth(ctx, historyStore, forecast, "sql-"+baseline.Name, report.ToReport())
+		if err != nil {
+			return err
+		}
+		sql.AddForecasts(report, projections)
+		if err := escalateFindings(ctx, progress, historyStore, escalation, "sql-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := linker.Annotate(report.ToReport()); err != nil {
+			return err
+		}
+		annotateRemediation(report.ToReport())
+		if err := annotateGitHub(progress, "sql_baselines", baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		versions, tiers := baseline.Expected()
+		report.Warnings = baselineWarnings(staleness, baseline.Name, baseline.Metadata, versions, tiers)
+		report.Acknowledgements = history.Acknowledgements(report.ToReport(), acknowledged)
+		if report.SLO, err = measureSLO(ctx, historyStore, sloPolicy, "sql-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if metadata != nil {
+			warnMetadataLookup(progress, analyzer.AnnotateStaleBaseline(ctx, metadata, report, instances, baseline.Config))
+		}
+		stabilize(&report.Timestamp, report.Instances, func(i *sql.InstanceDrift) ([]string, []driftreport.Drift) {
+			return []string{i.Project, i.Region, i.Name}, i.Drifts
+		})
+		printCanary(progress, canary, report.ToReport(), population)
+		if err := recordHistory(ctx, progress, historyStore, "sql-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := exportFindings(ctx, progress, exporters, scanID, report.ToReport()); err != nil {
+			return err
+		}
+
+		if badgeDir != "" {
+			path, err := writeBadge(badgeDir, "sql-"+baseline.Name, report.ToReport())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(progress, "Wrote badge %s\n", path)
+		}
+
+		if splitBy != "" {
+			if err := writeSplitReports(report.ToReport(), sqlOutputFormat, scanID, "sql-"+baseline.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reportDest != "" {
+			if err := writeReportFile(ctx, progress, reportDest, "sql-"+baseline.Name, sqlOutputFormat, scanID, report); err != nil {
+				return err
+			}
+			continue
+		}
 
 		// Output report
 		switch sqlOutputFormat {
 		case "tui":
 			// Convert to TUI format and run interactive display
 			tuiData := tui.FromSQLReport(report)
-			return tui.Run(tuiData)
+			if !tuiWatch {
+				return tui.Run(tuiData)
+			}
+			return tui.RunLive(tuiData, func(ctx context.Context) (tui.ReportData, error) {
+				instances, err := analyzer.DiscoverInstances(ctx, projects)
+				if err != nil {
+					return tui.ReportData{}, fmt.Errorf("failed to discover instances: %w", err)
+				}
+				instances = sql.SelectInstances(instances, baseline, config.SQLBaselines)
+				instances = sql.FilterByLabels(instances, labels)
+				report := analyzer.AnalyzeDrift(instances, baseline.Config)
+				sql.CheckCapacity(report, baseline.Capacity)
+				sql.CheckUniqueness(report, baseline.Unique)
+				if err := linker.Annotate(report.ToReport()); err != nil {
+					return tui.ReportData{}, err
+				}
+				annotateRemediation(report.ToReport())
+				return tui.FromSQLReport(report), nil
+			})
 		case "json":
 			output, err := report.FormatJSON()
 			if err != nil {
@@ -106,6 +292,11 @@ func runSQLAnalysis(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("failed to format YAML: %w", err)
 			}
 			fmt.Println(output)
+		case "ndjson", "dot", "mermaid", "sarif", "gitlab-codequality", "junit", "plan":
+			if err := printGeneric(report.ToReport(), sqlOutputFormat, scanID); err != nil {
+				return err
+			}
+			continue
 		default:
 			fmt.Println(report.FormatText())
 		}
@@ -115,3 +306,25 @@ func runSQLAnalysis(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// generateSQLConfig prints baselines built from the instances selected by
+// --label, grouped by --group-by
+func generateSQLConfig(ctx context.Context, projects []string, labels map[string]string) error {
+	analyzer, err := sql.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create SQL analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	analyzer.SetRetryPolicy(retryPolicy())
+
+	instances, err := analyzer.DiscoverInstances(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover instances: %w", err)
+	}
+	output, err := sql.GenerateBaselineConfig(sql.FilterByLabels(instances, labels), sqlGroupBy)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
@@ -1,26 +1,41 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
 
 var (
-	dbConnectionName string
-	compareWithCache bool
-	listConnections  bool
-	cacheDir         string
-	inspectAll       bool
-	outputFormat     string
-	outputDir        string
+	dbConnectionName   string
+	compareWithCache   bool
+	writeMigration     bool
+	updateCache        string
+	listConnections    bool
+	cacheDir           string
+	inspectAll         bool
+	outputFormat       string
+	outputDir          string
+	inspectConcurrency int
+	dbQueryTimeout     time.Duration
+	dbAnonymize        []string
+
+	// dbAnonymizer rewrites identifiers of exported schemas; nil exports
+	// them as inspected
+	dbAnonymizer sql.Anonymizer
 )
 
 // sqlDbCmd represents the database schema inspection command using config
@@ -42,8 +57,17 @@ Examples:
   # Compare current schema with cached baseline
   drift-analysis-cli sql db -config config.yaml -connection cfssl-test --compare
 
+  # Also write SQL that reverts the database to the cached baseline
+  drift-analysis-cli sql db -config config.yaml -connection cfssl-test --compare --migration
+
+  # Compare without prompting, e.g. in CI, and keep the cached baseline
+  drift-analysis-cli sql db -config config.yaml -connection cfssl-test --compare --update-cache=never
+
   # List all database connections in config
-  drift-analysis-cli sql db -config config.yaml --list`,
+  drift-analysis-cli sql db -config config.yaml --list
+
+  # Inspect every connection, eight at a time
+  drift-analysis-cli sql db -config config.yaml --all --concurrency 8`,
 	RunE: runSQLDb,
 }
 
@@ -52,29 +76,60 @@ func init() {
 	
 	sqlDbCmd.Flags().StringVarP(&dbConnectionName, "connection", "c", "", "database connection name from config")
 	sqlDbCmd.Flags().BoolVar(&compareWithCache, "compare", false, "compare current schema with cached baseline")
+	sqlDbCmd.Flags().BoolVar(&writeMigration, "migration", false, "with --compare, write candidate SQL that brings the database back to the cached baseline to <connection>-migration.sql (review before running)")
+	sqlDbCmd.Flags().StringVar(&updateCache, "update-cache", "", "with --compare, when to update the cached baseline: never|always|on-change (default: ask in a terminal, otherwise never)")
 	sqlDbCmd.Flags().BoolVar(&listConnections, "list", false, "list all database connections in config")
 	sqlDbCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "cache directory (default: .drift-cache/database-schemas)")
 	sqlDbCmd.Flags().BoolVar(&inspectAll, "all", false, "inspect all database connections in config")
-	sqlDbCmd.Flags().StringVarP(&outputFormat, "format", "f", "summary", "output format: summary|full|ddl|json|yaml")
-	sqlDbCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "output directory for generated files (default: current directory)")
+	sqlDbCmd.Flags().StringVarP(&outputFormat, "format", "f", "summary", "output format: summary|full|ddl|json|yaml|spec")
+	sqlDbCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "", "output directory or gs://bucket/path for generated files (default: output.schema_dir, then current directory)")
+	sqlDbCmd.Flags().IntVar(&inspectConcurrency, "concurrency", 4, "number of connections inspected at once with --all")
+	addAnonymizeFlag(sqlDbCmd, &dbAnonymize)
+	sqlDbCmd.Flags().DurationVar(&dbQueryTimeout, "query-timeout", time.Minute, "maximum duration of each catalog query; longer queries are canceled on the server")
 }
 
 func runSQLDb(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	// Ctrl+C cancels in-flight catalog queries on the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if writeMigration && !compareWithCache {
+		return fmt.Errorf("--migration requires --compare")
+	}
+	switch updateCache {
+	case "", "never", "always", "on-change":
+	default:
+		return fmt.Errorf("unsupported --update-cache: %s (never|always|on-change)", updateCache)
+	}
+	if updateCache != "" && !compareWithCache {
+		return fmt.Errorf("--update-cache requires --compare")
+	}
 
 	// Load config
 	if cfgFile == "" {
 		return fmt.Errorf("config file is required (use -config flag)")
 	}
 
-	configData, err := os.ReadFile(cfgFile)
-	if err != nil {
-		return fmt.Errorf("failed to read config file: %w", err)
+	var cfg sql.Config
+	if err := decodeConfig(&cfg); err != nil {
+		return err
+	}
+	if err := cfg.LoadSchemaSpecs(filepath.Dir(cfgFile)); err != nil {
+		return err
 	}
 
-	var cfg sql.Config
-	if err := yaml.Unmarshal(configData, &cfg); err != nil {
-		return fmt.Errorf("failed to parse config file: %w", err)
+	if outputDir == "" {
+		output, err := loadOutputConfig()
+		if err != nil {
+			return err
+		}
+		outputDir = output.SchemaDir
+	}
+
+	var err error
+	dbAnonymizer, err = schemaAnonymizer(dbAnonymize)
+	if err != nil {
+		return err
 	}
 
 	// Handle list command
@@ -82,9 +137,18 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 		return listDatabaseConnections(&cfg)
 	}
 
+	if explainPlan != "" {
+		return explainDatabaseConnections(cmd, &cfg)
+	}
+
+	schemas, err := openSchemaHistory(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Handle inspect all connections
 	if inspectAll {
-		return inspectAllConnections(ctx, &cfg)
+		return inspectAllConnections(ctx, &cfg, schemas)
 	}
 
 	// Validate connection name
@@ -93,16 +157,9 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 	}
 
 	// Find the connection
-	var conn *sql.DatabaseConnection
-	for i := range cfg.DatabaseConnections {
-		if cfg.DatabaseConnections[i].Name == dbConnectionName {
-			conn = &cfg.DatabaseConnections[i]
-			break
-		}
-	}
-
-	if conn == nil {
-		return fmt.Errorf("connection '%s' not found in config (use --list to see available connections)", dbConnectionName)
+	conn, err := findDatabaseConnection(&cfg, dbConnectionName)
+	if err != nil {
+		return err
 	}
 
 	// Validate connection
@@ -111,7 +168,7 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create cache manager
-	cache, err := sql.NewSchemaCache(cacheDir)
+	cache, err := sql.NewSchemaCache(schemaCacheDir())
 	if err != nil {
 		return fmt.Errorf("failed to create cache: %w", err)
 	}
@@ -133,6 +190,7 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create inspector: %w", err)
 	}
+	inspector.SetQueryTimeout(dbQueryTimeout)
 
 	// Inspect current schema
 	fmt.Println("Connecting and inspecting schema...")
@@ -156,21 +214,29 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 		validationResult := sql.ValidateSchemaAgainstBaseline(currentSchema, conn.SchemaBaseline)
 		
 		if validationResult.HasDrift {
-			fmt.Println("\n[WARNING] Schema drift detected!\n")
+			fmt.Printf("\n[WARNING] Schema drift detected!\n\n")
 			fmt.Println(sql.FormatValidationResult(validationResult))
 		} else {
-			fmt.Println("[OK] Database matches baseline expectations\n")
+			fmt.Printf("[OK] Database matches baseline expectations\n\n")
 		}
 	}
 
+	if err := schemas.record(ctx, os.Stdout, conn, currentSchema); err != nil {
+		fmt.Printf("WARNING: Failed to record history: %v\n", err)
+	}
+
 	// Generate output based on format
-	if err := generateOutput(currentSchema, conn.Name, outputFormat, outputDir); err != nil {
+	if err := generateOutput(ctx, os.Stdout, currentSchema, conn.Name, outputFormat, outputDir, dbAnonymizer); err != nil {
 		return fmt.Errorf("failed to generate output: %w", err)
 	}
 
 	// Compare with cached baseline if requested
 	if compareWithCache {
 		if !cacheExists {
+			if updateCache == "never" {
+				fmt.Println("WARNING: No cached baseline found; --update-cache=never leaves it uncached")
+				return nil
+			}
 			fmt.Println("WARNING: No cached baseline found. Creating initial cache...")
 			if err := cache.Save(conn.GetConnectionName(), conn.Database, currentSchema); err != nil {
 				return fmt.Errorf("failed to save cache: %w", err)
@@ -189,22 +255,44 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 		
 		if !diff.HasChanges() {
 			fmt.Println("\nNo schema changes detected!")
+			if updateCache == "always" {
+				if err := cache.Save(conn.GetConnectionName(), conn.Database, currentSchema); err != nil {
+					return fmt.Errorf("failed to update cache: %w", err)
+				}
+			}
 			return nil
 		}
 
-		fmt.Println("\nWARNING: Schema changes detected:\n")
+		fmt.Printf("\nWARNING: Schema changes detected:\n\n")
 		printSchemaDiff(diff)
 
-		// Ask if user wants to update cache
-		fmt.Println("\nUpdate cached baseline? (yes/no)")
-		var response string
-		fmt.Scanln(&response)
-		if response == "yes" || response == "y" {
-			if err := cache.Save(conn.GetConnectionName(), conn.Database, currentSchema); err != nil {
-				return fmt.Errorf("failed to update cache: %w", err)
+		if writeMigration {
+			if err := writeOutput(ctx, os.Stdout, conn.Name, "migration.sql", diff.MigrationSQL(), outputDir); err != nil {
+				return fmt.Errorf("failed to write migration: %w", err)
 			}
-			fmt.Println("Cache updated")
+			fmt.Println("Review the migration before running it; it is generated, not tested")
+		}
+
+		update := updateCache == "always" || updateCache == "on-change"
+		if updateCache == "" {
+			if !isTerminal(os.Stdin) {
+				fmt.Println("\nCached baseline left unchanged (set --update-cache to update it without a prompt)")
+				return nil
+			}
+			// Ask if user wants to update cache
+			fmt.Println("\nUpdate cached baseline? (yes/no)")
+			var response string
+			fmt.Scanln(&response)
+			update = response == "yes" || response == "y"
+		}
+		if !update {
+			fmt.Println("Cached baseline left unchanged")
+			return nil
+		}
+		if err := cache.Save(conn.GetConnectionName(), conn.Database, currentSchema); err != nil {
+			return fmt.Errorf("failed to update cache: %w", err)
 		}
+		fmt.Println("Cache updated")
 	} else {
 		// Save to cache
 		if err := cache.Save(conn.GetConnectionName(), conn.Database, currentSchema); err != nil {
@@ -221,6 +309,16 @@ func runSQLDb(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// findDatabaseConnection returns the database connection with the name
+func findDatabaseConnection(cfg *sql.Config, name string) (*sql.DatabaseConnection, error) {
+	for i := range cfg.DatabaseConnections {
+		if cfg.DatabaseConnections[i].Name == name {
+			return &cfg.DatabaseConnections[i], nil
+		}
+	}
+	return nil, fmt.Errorf("connection '%s' not found in config (use --list to see available connections)", name)
+}
+
 func listDatabaseConnections(cfg *sql.Config) error {
 	if len(cfg.DatabaseConnections) == 0 {
 		fmt.Println("No database connections defined in config")
@@ -261,6 +359,9 @@ func printSchemaDiff(diff *sql.SchemaDiff) {
 		fmt.Printf("Modified Tables (%d):\n", len(diff.ModifiedTables))
 		for _, t := range diff.ModifiedTables {
 			fmt.Printf("  ~ %s.%s\n", t.Schema, t.Name)
+			for _, line := range t.Summary() {
+				fmt.Printf("      %s\n", line)
+			}
 		}
 		fmt.Println()
 	}
@@ -281,6 +382,78 @@ func printSchemaDiff(diff *sql.SchemaDiff) {
 		fmt.Println()
 	}
 
+	if len(diff.AddedSequences) > 0 {
+		fmt.Printf("Added Sequences (%d):\n", len(diff.AddedSequences))
+		for _, seq := range diff.AddedSequences {
+			fmt.Printf("  + %s.%s\n", seq.Schema, seq.Name)
+		}
+		fmt.Println()
+	}
+
+	if len(diff.DeletedSequences) > 0 {
+		fmt.Printf("Deleted Sequences (%d):\n", len(diff.DeletedSequences))
+		for _, seq := range diff.DeletedSequences {
+			fmt.Printf("  - %s.%s\n", seq.Schema, seq.Name)
+		}
+		fmt.Println()
+	}
+
+	if len(diff.ModifiedSequences) > 0 {
+		fmt.Printf("Modified Sequences (%d):\n", len(diff.ModifiedSequences))
+		for _, seq := range diff.ModifiedSequences {
+			fmt.Printf("  ~ %s: %s\n", seq.Name, strings.Join(seq.Changes(), ", "))
+		}
+		fmt.Println()
+	}
+
+	if len(diff.AddedFunctions) > 0 {
+		fmt.Printf("Added Functions (%d):\n", len(diff.AddedFunctions))
+		for _, fn := range diff.AddedFunctions {
+			fmt.Printf("  + %s\n", fn.Signature())
+		}
+		fmt.Println()
+	}
+
+	if len(diff.DeletedFunctions) > 0 {
+		fmt.Printf("Deleted Functions (%d):\n", len(diff.DeletedFunctions))
+		for _, fn := range diff.DeletedFunctions {
+			fmt.Printf("  - %s\n", fn.Signature())
+		}
+		fmt.Println()
+	}
+
+	if len(diff.ModifiedFunctions) > 0 {
+		fmt.Printf("Modified Functions (%d):\n", len(diff.ModifiedFunctions))
+		for _, fn := range diff.ModifiedFunctions {
+			fmt.Printf("  ~ %s: %s\n", fn.Signature, strings.Join(fn.Changes(), ", "))
+		}
+		fmt.Println()
+	}
+
+	if len(diff.AddedProcedures) > 0 {
+		fmt.Printf("Added Procedures (%d):\n", len(diff.AddedProcedures))
+		for _, proc := range diff.AddedProcedures {
+			fmt.Printf("  + %s\n", proc.Signature())
+		}
+		fmt.Println()
+	}
+
+	if len(diff.DeletedProcedures) > 0 {
+		fmt.Printf("Deleted Procedures (%d):\n", len(diff.DeletedProcedures))
+		for _, proc := range diff.DeletedProcedures {
+			fmt.Printf("  - %s\n", proc.Signature())
+		}
+		fmt.Println()
+	}
+
+	if len(diff.ModifiedProcedures) > 0 {
+		fmt.Printf("Modified Procedures (%d):\n", len(diff.ModifiedProcedures))
+		for _, proc := range diff.ModifiedProcedures {
+			fmt.Printf("  ~ %s: %s\n", proc.Signature, strings.Join(proc.Changes(), ", "))
+		}
+		fmt.Println()
+	}
+
 	if len(diff.AddedRoles) > 0 {
 		fmt.Printf("Added Roles (%d):\n", len(diff.AddedRoles))
 		for _, r := range diff.AddedRoles {
@@ -312,114 +485,208 @@ func printSchemaDiff(diff *sql.SchemaDiff) {
 		}
 		fmt.Println()
 	}
+
+	settingName := func(s sql.DatabaseSetting) string {
+		if s.Role != "" {
+			return fmt.Sprintf("%s (role %s)", s.Name, s.Role)
+		}
+		return s.Name
+	}
+
+	if len(diff.AddedSettings) > 0 {
+		fmt.Printf("Added Setting Overrides (%d):\n", len(diff.AddedSettings))
+		for _, s := range diff.AddedSettings {
+			fmt.Printf("  + %s = %s\n", settingName(s), s.Value)
+		}
+		fmt.Println()
+	}
+
+	if len(diff.ChangedSettings) > 0 {
+		fmt.Printf("Changed Setting Overrides (%d):\n", len(diff.ChangedSettings))
+		for _, c := range diff.ChangedSettings {
+			fmt.Printf("  ~ %s: %s → %s\n", settingName(c.New), c.Old.Value, c.New.Value)
+		}
+		fmt.Println()
+	}
+
+	if len(diff.DeletedSettings) > 0 {
+		fmt.Printf("Deleted Setting Overrides (%d):\n", len(diff.DeletedSettings))
+		for _, s := range diff.DeletedSettings {
+			fmt.Printf("  - %s\n", settingName(s))
+		}
+		fmt.Println()
+	}
+
+	if len(diff.ChangedOwners) > 0 {
+		fmt.Printf("Changed Owners (%d):\n", len(diff.ChangedOwners))
+		for _, o := range diff.ChangedOwners {
+			fmt.Printf("  ~ %s %s: %s → %s\n", o.ObjectType, o.Name, o.Old, o.New)
+		}
+		fmt.Println()
+	}
 }
 
-// inspectAllConnections inspects all configured database connections
-func inspectAllConnections(ctx context.Context, cfg *sql.Config) error {
+// inspectAllConnections inspects all configured database connections, up to
+// --concurrency at a time. Each connection's output is buffered and printed
+// in config order.
+func inspectAllConnections(ctx context.Context, cfg *sql.Config, schemas *schemaHistory) error {
 	if len(cfg.DatabaseConnections) == 0 {
 		fmt.Println("No database connections defined in config")
 		return nil
 	}
+	if inspectConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
 
-	fmt.Printf("Inspecting %d database connection(s)...\n\n", len(cfg.DatabaseConnections))
+	total := len(cfg.DatabaseConnections)
+	fmt.Printf("Inspecting %d database connection(s), %d at a time...\n\n", total, min(inspectConcurrency, total))
 
 	// Create cache manager
-	cache, err := sql.NewSchemaCache(cacheDir)
+	cache, err := sql.NewSchemaCache(schemaCacheDir())
 	if err != nil {
 		return fmt.Errorf("failed to create cache: %w", err)
 	}
 
-	for i, conn := range cfg.DatabaseConnections {
-		fmt.Printf("[%d/%d] Inspecting: %s\n", i+1, len(cfg.DatabaseConnections), conn.Name)
-		fmt.Printf("  Instance: %s\n", conn.GetConnectionName())
-		fmt.Printf("  Database: %s\n\n", conn.Database)
+	// Every inspection dials through one connector, sharing its token source
+	// and per-instance certificates
+	dialer := sql.NewDialer(ctx)
+	defer dialer.Close()
 
-		// Validate connection
-		if err := conn.Validate(); err != nil {
-			fmt.Printf("  ERROR: Invalid connection config: %v\n\n", err)
-			continue
-		}
+	outputs := make([]bytes.Buffer, total)
+	failed := make([]bool, total)
+	done := make([]chan struct{}, total)
+	sem := make(chan struct{}, inspectConcurrency)
+	for i := range cfg.DatabaseConnections {
+		done[i] = make(chan struct{})
+		go func() {
+			defer close(done[i])
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			failed[i] = !inspectConnection(ctx, &cfg.DatabaseConnections[i], dialer, cache, schemas, &outputs[i])
+		}()
+	}
 
-		// Create inspector
-		inspector, err := sql.NewInspectorFromDatabaseConnection(&conn)
-		if err != nil {
-			fmt.Printf("  ERROR: Failed to create inspector: %v\n\n", err)
-			continue
+	// Print each connection once it and every connection before it are done
+	failures := 0
+	for i, conn := range cfg.DatabaseConnections {
+		<-done[i]
+		fmt.Printf("[%d/%d] Inspecting: %s\n", i+1, total, conn.Name)
+		os.Stdout.Write(outputs[i].Bytes())
+		fmt.Println()
+		if failed[i] {
+			failures++
 		}
+	}
 
-		// Inspect database
-		schema, err := inspector.InspectDatabase(ctx)
-		if err != nil {
-			fmt.Printf("  ERROR: Failed to inspect database: %v\n\n", err)
-			continue
-		}
-
-		fmt.Printf("  Inspection complete!\n")
-		fmt.Printf("    Tables: %d\n", len(schema.Tables))
-		fmt.Printf("    Views: %d\n", len(schema.Views))
-		fmt.Printf("    Sequences: %d\n", len(schema.Sequences))
-		fmt.Printf("    Functions: %d\n", len(schema.Functions))
-		fmt.Printf("    Procedures: %d\n", len(schema.Procedures))
-		fmt.Printf("    Roles: %d\n", len(schema.Roles))
-		fmt.Printf("    Extensions: %d\n", len(schema.Extensions))
-
-		// Validate against baseline if configured
-		if conn.SchemaBaseline != nil {
-			validationResult := sql.ValidateSchemaAgainstBaseline(schema, conn.SchemaBaseline)
-			
-			if validationResult.HasDrift {
-				fmt.Printf("    [WARNING] Schema drift detected!\n")
-				// Print detailed mismatches
-				if len(validationResult.CountMismatches) > 0 {
-					fmt.Printf("      Count mismatches:\n")
-					for _, cm := range validationResult.CountMismatches {
-						fmt.Printf("        - %s: expected %d, got %d (diff: %+d)\n", 
-							cm.ObjectType, cm.Expected, cm.Actual, cm.Actual-cm.Expected)
-					}
+	fmt.Printf("Completed inspecting %d connection(s)", total)
+	if failures > 0 {
+		fmt.Printf(", %d failed", failures)
+	}
+	fmt.Println()
+	return nil
+}
+
+// inspectConnection inspects one database connection through the shared
+// dialer, writing its progress and results to w. It reports whether the
+// inspection succeeded.
+func inspectConnection(ctx context.Context, conn *sql.DatabaseConnection, dialer *sql.Dialer, cache *sql.SchemaCache, schemas *schemaHistory, w io.Writer) bool {
+	fmt.Fprintf(w, "  Instance: %s\n", conn.GetConnectionName())
+	fmt.Fprintf(w, "  Database: %s\n\n", conn.Database)
+
+	// Validate connection
+	if err := conn.Validate(); err != nil {
+		fmt.Fprintf(w, "  ERROR: Invalid connection config: %v\n", err)
+		return false
+	}
+
+	// Create inspector
+	inspector, err := sql.NewInspectorFromDatabaseConnection(conn)
+	if err != nil {
+		fmt.Fprintf(w, "  ERROR: Failed to create inspector: %v\n", err)
+		return false
+	}
+	inspector.SetOutput(w)
+	inspector.SetDialer(dialer)
+	inspector.SetQueryTimeout(dbQueryTimeout)
+
+	// Inspect database
+	schema, err := inspector.InspectDatabase(ctx)
+	if err != nil {
+		fmt.Fprintf(w, "  ERROR: Failed to inspect database: %v\n", err)
+		return false
+	}
+
+	fmt.Fprintf(w, "  Inspection complete!\n")
+	fmt.Fprintf(w, "    Tables: %d\n", len(schema.Tables))
+	fmt.Fprintf(w, "    Views: %d\n", len(schema.Views))
+	fmt.Fprintf(w, "    Sequences: %d\n", len(schema.Sequences))
+	fmt.Fprintf(w, "    Functions: %d\n", len(schema.Functions))
+	fmt.Fprintf(w, "    Procedures: %d\n", len(schema.Procedures))
+	fmt.Fprintf(w, "    Roles: %d\n", len(schema.Roles))
+	fmt.Fprintf(w, "    Extensions: %d\n", len(schema.Extensions))
+
+	// Validate against baseline if configured
+	if conn.SchemaBaseline != nil {
+		validationResult := sql.ValidateSchemaAgainstBaseline(schema, conn.SchemaBaseline)
+
+		if validationResult.HasDrift {
+			fmt.Fprintf(w, "    [WARNING] Schema drift detected!\n")
+			// Print detailed mismatches
+			if len(validationResult.CountMismatches) > 0 {
+				fmt.Fprintf(w, "      Count mismatches:\n")
+				for _, cm := range validationResult.CountMismatches {
+					fmt.Fprintf(w, "        - %s: expected %d, got %d (diff: %+d)\n",
+						cm.ObjectType, cm.Expected, cm.Actual, cm.Actual-cm.Expected)
 				}
-				if len(validationResult.MissingObjects) > 0 {
-					fmt.Printf("      Missing objects: %d\n", len(validationResult.MissingObjects))
-					for _, mo := range validationResult.MissingObjects {
-						fmt.Printf("        - %s: %s\n", mo.ObjectType, mo.Name)
-					}
+			}
+			if len(validationResult.MissingObjects) > 0 {
+				fmt.Fprintf(w, "      Missing objects: %d\n", len(validationResult.MissingObjects))
+				for _, mo := range validationResult.MissingObjects {
+					fmt.Fprintf(w, "        - %s: %s\n", mo.ObjectType, mo.Name)
 				}
-				if len(validationResult.ForbiddenObjects) > 0 {
-					fmt.Printf("      Forbidden objects: %d\n", len(validationResult.ForbiddenObjects))
-					for _, fo := range validationResult.ForbiddenObjects {
-						fmt.Printf("        - %s: %s\n", fo.ObjectType, fo.Name)
-					}
+			}
+			if len(validationResult.ForbiddenObjects) > 0 {
+				fmt.Fprintf(w, "      Forbidden objects: %d\n", len(validationResult.ForbiddenObjects))
+				for _, fo := range validationResult.ForbiddenObjects {
+					fmt.Fprintf(w, "        - %s: %s\n", fo.ObjectType, fo.Name)
 				}
-				if len(validationResult.OwnershipViolations) > 0 {
-					fmt.Printf("      Ownership violations: %d\n", len(validationResult.OwnershipViolations))
-					for _, ov := range validationResult.OwnershipViolations {
-						fmt.Printf("        - %s %s: owned by '%s', expected '%s'\n", 
-							ov.ObjectType, ov.ObjectName, ov.ActualOwner, ov.ExpectedOwner)
-					}
+			}
+			if len(validationResult.OwnershipViolations) > 0 {
+				fmt.Fprintf(w, "      Ownership violations: %d\n", len(validationResult.OwnershipViolations))
+				for _, ov := range validationResult.OwnershipViolations {
+					fmt.Fprintf(w, "        - %s %s: owned by '%s', expected '%s'\n",
+						ov.ObjectType, ov.ObjectName, ov.ActualOwner, ov.ExpectedOwner)
 				}
-			} else {
-				fmt.Printf("    [OK] Matches baseline\n")
 			}
+		} else {
+			fmt.Fprintf(w, "    [OK] Matches baseline\n")
 		}
+	}
 
-		// Save to cache
-		if err := cache.Save(conn.GetConnectionName(), conn.Database, schema); err != nil {
-			fmt.Printf("  WARNING: Failed to save cache: %v\n", err)
-		}
-
-		// Generate output
-		if err := generateOutput(schema, conn.Name, outputFormat, outputDir); err != nil {
-			fmt.Printf("  WARNING: Failed to generate output: %v\n", err)
-		}
-
-		fmt.Println()
+	// Save to cache
+	if path, err := cache.Write(conn.GetConnectionName(), conn.Database, schema); err != nil {
+		fmt.Fprintf(w, "  WARNING: Failed to save cache: %v\n", err)
+	} else {
+		fmt.Fprintf(w, "Cached schema to: %s\n", path)
+	}
+	if err := schemas.record(ctx, w, conn, schema); err != nil {
+		fmt.Fprintf(w, "  WARNING: Failed to record history: %v\n", err)
 	}
 
-	fmt.Printf("Completed inspecting %d connection(s)\n", len(cfg.DatabaseConnections))
-	return nil
+	// Generate output
+	if err := generateOutput(ctx, w, schema, conn.Name, outputFormat, outputDir, dbAnonymizer); err != nil {
+		fmt.Fprintf(w, "  WARNING: Failed to generate output: %v\n", err)
+	}
+	return true
 }
 
-// generateOutput generates output in the specified format
-func generateOutput(schema *sql.DatabaseSchema, connectionName string, format string, outputDir string) error {
+// generateOutput generates output in the specified format, with identifiers
+// rewritten by anonymizer when set
+func generateOutput(ctx context.Context, w io.Writer, schema *sql.DatabaseSchema, connectionName string, format string, outputDir string, anonymizer sql.Anonymizer) error {
+	if anonymizer != nil {
+		schema = schema.Anonymized(anonymizer)
+	}
+
 	switch format {
 	case "summary":
 		// Just console output, already done
@@ -428,12 +695,12 @@ func generateOutput(schema *sql.DatabaseSchema, connectionName string, format st
 	case "full":
 		// Full detailed report
 		output := generateFullReport(schema)
-		return writeOutput(connectionName, "full-report.txt", output, outputDir)
+		return writeOutput(ctx, w, connectionName, "full-report.txt", output, outputDir)
 
 	case "ddl":
 		// DDL statements
 		output := schema.GenerateDDL()
-		return writeOutput(connectionName, "schema.sql", output, outputDir)
+		return writeOutput(ctx, w, connectionName, "schema.sql", output, outputDir)
 
 	case "json":
 		// JSON format
@@ -441,7 +708,7 @@ func generateOutput(schema *sql.DatabaseSchema, connectionName string, format st
 		if err != nil {
 			return fmt.Errorf("failed to marshal to JSON: %w", err)
 		}
-		return writeOutput(connectionName, "schema.json", string(data), outputDir)
+		return writeOutput(ctx, w, connectionName, "schema.json", string(data), outputDir)
 
 	case "yaml":
 		// YAML format
@@ -449,7 +716,15 @@ func generateOutput(schema *sql.DatabaseSchema, connectionName string, format st
 		if err != nil {
 			return fmt.Errorf("failed to marshal to YAML: %w", err)
 		}
-		return writeOutput(connectionName, "schema.yaml", string(data), outputDir)
+		return writeOutput(ctx, w, connectionName, "schema.yaml", string(data), outputDir)
+
+	case "spec":
+		// Strict schema spec, a starting point for schema_baseline.spec_file
+		data, err := yaml.Marshal(sql.NewSchemaSpec(schema))
+		if err != nil {
+			return fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+		return writeOutput(ctx, w, connectionName, "schema-spec.yaml", string(data), outputDir)
 
 	default:
 		return fmt.Errorf("unsupported format: %s", format)
@@ -571,17 +846,31 @@ func generateFullReport(schema *sql.DatabaseSchema) string {
 	return sb.String()
 }
 
-// writeOutput writes output to a file
-func writeOutput(connectionName string, filename string, content string, outputDir string) error {
-	// Sanitize connection name for filename
+// safeConnectionName sanitizes a connection name for use in file names
+func safeConnectionName(connectionName string) string {
 	safeName := strings.ReplaceAll(connectionName, ":", "_")
-	safeName = strings.ReplaceAll(safeName, "/", "_")
-	
+	return strings.ReplaceAll(safeName, "/", "_")
+}
+
+// writeOutput writes output to a file
+func writeOutput(ctx context.Context, w io.Writer, connectionName string, filename string, content string, outputDir string) error {
+	safeName := safeConnectionName(connectionName)
+
 	// Construct filename with connection name prefix
 	baseFilename := strings.TrimSuffix(filename, filepath.Ext(filename))
 	ext := filepath.Ext(filename)
 	fullFilename := fmt.Sprintf("%s-%s%s", safeName, baseFilename, ext)
 
+	// Objects in a bucket are never overwritten, so each run gets its own name
+	if gcs.IsURI(outputDir) {
+		uri, err := writeFile(ctx, outputDir, gcs.TimestampedName(safeName+"-"+baseFilename, ext, time.Now()), []byte(content))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  Output written to: %s\n", uri)
+		return nil
+	}
+
 	// Determine output path
 	outputPath := fullFilename
 	if outputDir != "" {
@@ -597,6 +886,6 @@ func writeOutput(connectionName string, filename string, content string, outputD
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	fmt.Printf("  Output written to: %s\n", outputPath)
+	fmt.Fprintf(w, "  Output written to: %s\n", outputPath)
 	return nil
 }
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	compareSource    string
+	compareTarget    string
+	compareFormat    string
+	compareMigration bool
+	compareOutputDir string
+)
+
+// sqlDbCompareCmd represents the sql db compare command
+var sqlDbCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare the schemas of two configured database connections",
+	Long: `Inspect two database connections from the config file and diff their schemas
+directly, e.g. a production database against its DR replica. Tables are
+compared column by column, index by index and constraint by constraint;
+views, sequences, functions, procedures, roles, extensions, setting overrides
+and owners are compared too. Changes are reported from the source to the
+target: "added" objects exist only in the target.
+
+Neither schema is cached. --migration writes candidate SQL that brings the
+target back to the source to <target>-migration.sql, for review.
+
+Examples:
+  drift-analysis-cli gcp sql db compare --config config.yaml --source orders-prod --target orders-dr
+  drift-analysis-cli gcp sql db compare --config config.yaml --source orders-prod --target orders-dr -f json`,
+	RunE: runSQLDbCompare,
+}
+
+func init() {
+	sqlDbCmd.AddCommand(sqlDbCompareCmd)
+	sqlDbCompareCmd.Flags().StringVar(&compareSource, "source", "", "database connection the target is compared against")
+	sqlDbCompareCmd.Flags().StringVar(&compareTarget, "target", "", "database connection compared against the source")
+	sqlDbCompareCmd.Flags().StringVarP(&compareFormat, "format", "f", "text", "output format: text|json|yaml")
+	sqlDbCompareCmd.Flags().BoolVar(&compareMigration, "migration", false, "write candidate SQL that brings the target back to the source to <target>-migration.sql (review before running)")
+	sqlDbCompareCmd.Flags().StringVarP(&compareOutputDir, "output-dir", "o", "", "output directory or gs://bucket/path for the migration (default: output.schema_dir, then current directory)")
+	sqlDbCompareCmd.Flags().DurationVar(&dbQueryTimeout, "query-timeout", time.Minute, "maximum duration of each catalog query; longer queries are canceled on the server")
+	_ = sqlDbCompareCmd.MarkFlagRequired("source")
+	_ = sqlDbCompareCmd.MarkFlagRequired("target")
+}
+
+func runSQLDbCompare(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch compareFormat {
+	case "text", "json", "yaml":
+	default:
+		return fmt.Errorf("unsupported format: %s (text|json|yaml)", compareFormat)
+	}
+	if compareSource == compareTarget {
+		return fmt.Errorf("--source and --target must be different connections")
+	}
+	if cfgFile == "" {
+		return fmt.Errorf("config file is required (use -config flag)")
+	}
+
+	var cfg sql.Config
+	if err := decodeConfig(&cfg); err != nil {
+		return err
+	}
+
+	source, err := findDatabaseConnection(&cfg, compareSource)
+	if err != nil {
+		return err
+	}
+	target, err := findDatabaseConnection(&cfg, compareTarget)
+	if err != nil {
+		return err
+	}
+
+	if compareOutputDir == "" {
+		output, err := loadOutputConfig()
+		if err != nil {
+			return err
+		}
+		compareOutputDir = output.SchemaDir
+	}
+
+	// Progress goes to stderr when stdout carries the diff
+	progress := io.Writer(os.Stdout)
+	if compareFormat != "text" {
+		progress = os.Stderr
+	}
+
+	// Both inspections dial through one connector
+	dialer := sql.NewDialer(ctx)
+	defer dialer.Close()
+
+	sourceSchema, err := inspectForCompare(ctx, source, dialer, progress)
+	if err != nil {
+		return err
+	}
+	targetSchema, err := inspectForCompare(ctx, target, dialer, progress)
+	if err != nil {
+		return err
+	}
+
+	diff := sql.CompareSchemas(sourceSchema, targetSchema)
+
+	switch compareFormat {
+	case "json":
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal to JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(diff)
+		if err != nil {
+			return fmt.Errorf("failed to marshal to YAML: %w", err)
+		}
+		fmt.Print(string(data))
+	default:
+		if !diff.HasChanges() {
+			fmt.Printf("\nNo schema differences between %s and %s\n", source.Name, target.Name)
+		} else {
+			fmt.Printf("\nSchema differences from %s to %s:\n\n", source.Name, target.Name)
+			printSchemaDiff(diff)
+		}
+	}
+
+	if compareMigration && diff.HasChanges() {
+		if err := writeOutput(ctx, progress, target.Name, "migration.sql", diff.MigrationSQL(), compareOutputDir); err != nil {
+			return fmt.Errorf("failed to write migration: %w", err)
+		}
+	}
+	return nil
+}
+
+// inspectForCompare inspects the schema of a connection without caching it
+func inspectForCompare(ctx context.Context, conn *sql.DatabaseConnection, dialer *sql.Dialer, w io.Writer) (*sql.DatabaseSchema, error) {
+	if err := conn.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid connection config %s: %w", conn.Name, err)
+	}
+
+	fmt.Fprintf(w, "Inspecting %s (%s, database %s)...\n", conn.Name, conn.GetConnectionName(), conn.Database)
+	inspector, err := sql.NewInspectorFromDatabaseConnection(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inspector for %s: %w", conn.Name, err)
+	}
+	inspector.SetOutput(w)
+	inspector.SetDialer(dialer)
+	inspector.SetQueryTimeout(dbQueryTimeout)
+
+	schema, err := inspector.InspectDatabase(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", conn.Name, err)
+	}
+	return schema, nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
+	"github.com/spf13/cobra"
+)
+
+// defaultBaselineDir holds promoted baselines when neither --baseline-dir
+// nor output.baseline_dir is set
+const defaultBaselineDir = "schema-baselines"
+
+var (
+	snapshotConnection  string
+	snapshotPromote     bool
+	snapshotBaselineDir string
+	snapshotMessage     string
+)
+
+// sqlDbSnapshotCmd represents the sql db snapshot command
+var sqlDbSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Promote the cached schema of a connection to its canonical baseline",
+	Long: `Show the cached schema of a database connection and how it differs from the
+canonical baseline; with --promote, write it as the new baseline.
+
+The baseline is written to <connection>-baseline.json in --baseline-dir
+(default: output.baseline_dir, then schema-baselines), usually a directory
+checked into git, or to a gs://bucket/path location, where each promotion gets
+its own timestamped object. It records who promoted it, when, the git commit
+of the config repository (or $GITHUB_SHA / $CI_COMMIT_SHA) and --message, so
+baseline updates can be audited.
+
+Examples:
+  # Show what would be promoted
+  drift-analysis-cli gcp sql db snapshot --config config.yaml -c orders-db
+
+  # Promote the cached schema
+  drift-analysis-cli gcp sql db snapshot --config config.yaml -c orders-db --promote -m "add orders.created_at (#412)"`,
+	RunE: runSQLDbSnapshot,
+}
+
+func init() {
+	sqlDbCmd.AddCommand(sqlDbSnapshotCmd)
+	sqlDbSnapshotCmd.Flags().StringVarP(&snapshotConnection, "connection", "c", "", "database connection name from config")
+	sqlDbSnapshotCmd.Flags().BoolVar(&snapshotPromote, "promote", false, "write the cached schema as the canonical baseline")
+	sqlDbSnapshotCmd.Flags().StringVar(&snapshotBaselineDir, "baseline-dir", "", "directory or gs://bucket/path for baselines (default: output.baseline_dir, then schema-baselines)")
+	sqlDbSnapshotCmd.Flags().StringVarP(&snapshotMessage, "message", "m", "", "reason for the promotion, recorded in the baseline")
+	sqlDbSnapshotCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "cache directory (default: .drift-cache/database-schemas)")
+	_ = sqlDbSnapshotCmd.MarkFlagRequired("connection")
+}
+
+func runSQLDbSnapshot(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if cfgFile == "" {
+		return fmt.Errorf("config file is required (use -config flag)")
+	}
+
+	var cfg sql.Config
+	if err := decodeConfig(&cfg); err != nil {
+		return err
+	}
+
+	conn, err := findDatabaseConnection(&cfg, snapshotConnection)
+	if err != nil {
+		return err
+	}
+
+	if snapshotBaselineDir == "" {
+		output, err := loadOutputConfig()
+		if err != nil {
+			return err
+		}
+		snapshotBaselineDir = output.BaselineDir
+		if snapshotBaselineDir == "" {
+			snapshotBaselineDir = defaultBaselineDir
+		}
+	}
+
+	cache, err := sql.NewSchemaCache(schemaCacheDir())
+	if err != nil {
+		return fmt.Errorf("failed to create cache: %w", err)
+	}
+	cached, err := cache.Load(conn.GetConnectionName(), conn.Database)
+	if err != nil {
+		return fmt.Errorf("%w (inspect it first with sql db -c %s)", err, conn.Name)
+	}
+
+	fmt.Printf("Cached schema of %s (%s, database %s)\n", conn.Name, conn.GetConnectionName(), conn.Database)
+	fmt.Printf("  Inspected: %s (age: %v)\n\n", cached.Timestamp.Format(time.RFC3339), time.Since(cached.Timestamp).Round(time.Second))
+
+	// Baselines in a bucket are timestamped, so only local ones are compared
+	if !gcs.IsURI(snapshotBaselineDir) {
+		path := filepath.Join(snapshotBaselineDir, safeConnectionName(conn.Name)+"-baseline.json")
+		current, err := sql.LoadPromotedBaseline(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			fmt.Printf("No baseline at %s yet\n", path)
+		case err != nil:
+			return err
+		default:
+			fmt.Printf("Current baseline: %s\n", path)
+			fmt.Printf("  Promoted: %s by %s\n", current.PromotedAt.Format(time.RFC3339), current.PromotedBy)
+			if current.GitSHA != "" {
+				fmt.Printf("  Commit: %s\n", current.GitSHA)
+			}
+			if current.Message != "" {
+				fmt.Printf("  Message: %s\n", current.Message)
+			}
+
+			diff := sql.CompareSchemas(current.Schema, cached.Schema)
+			if !diff.HasChanges() {
+				fmt.Println("\nThe cached schema matches the current baseline; nothing to promote")
+				return nil
+			}
+			fmt.Printf("\nChanges from the current baseline:\n\n")
+			printSchemaDiff(diff)
+		}
+	}
+
+	if !snapshotPromote {
+		fmt.Println("\nRun with --promote to write the cached schema as the baseline")
+		return nil
+	}
+
+	baseline := cached.Promote(currentActor(), gitCommit(ctx), snapshotMessage, time.Now())
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	fmt.Printf("\nPromoting the cached schema of %s as baseline\n", conn.Name)
+	return writeOutput(ctx, os.Stdout, conn.Name, "baseline.json", string(data), snapshotBaselineDir)
+}
+
+// gitCommit returns the commit checked out in the repository holding the
+// config file, or the commit a CI job is building; "" when there is neither
+func gitCommit(ctx context.Context) string {
+	out, err := exec.CommandContext(ctx, "git", "-C", filepath.Dir(cfgFile), "rev-parse", "HEAD").Output()
+	if err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	for _, env := range []string{"GITHUB_SHA", "CI_COMMIT_SHA"} {
+		if sha := os.Getenv(env); sha != "" {
+			return sha
+		}
+	}
+	return ""
+}
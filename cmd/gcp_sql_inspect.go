@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
 	"github.com/spf13/cobra"
 )
 
@@ -23,6 +27,9 @@ var (
 	inspectDatabase string
 	inspectOutput   string
 	inspectFormat   string
+	inspectTimeout  time.Duration
+	inspectAnon     []string
+	inspectIAMAuth  bool
 )
 
 // sqlInspectCmd represents the sql inspect command
@@ -41,7 +48,10 @@ Supports two connection methods:
 1. Cloud SQL connector (recommended): --instance project:region:instance-name
 2. Direct connection: --host IP --port 5432
 
-This command requires database connection credentials.`,
+This command requires database connection credentials: a password, or
+--iam-auth to log in to a Cloud SQL instance with IAM database authentication
+as the user of the GCP credentials (the IAM user email, or the service account
+email without .gserviceaccount.com).`,
 	RunE: runSQLInspect,
 }
 
@@ -57,17 +67,21 @@ func init() {
 	
 	// Common flags
 	sqlInspectCmd.Flags().StringVarP(&inspectUser, "user", "u", "", "database user (required)")
-	sqlInspectCmd.Flags().StringVarP(&inspectPassword, "password", "p", "", "database password (required)")
+	sqlInspectCmd.Flags().StringVarP(&inspectPassword, "password", "p", "", "database password (required unless --iam-auth)")
 	sqlInspectCmd.Flags().StringVarP(&inspectDatabase, "database", "d", "postgres", "database name")
-	sqlInspectCmd.Flags().StringVarP(&inspectOutput, "output-file", "o", "", "output file (default: stdout)")
+	sqlInspectCmd.Flags().StringVarP(&inspectOutput, "output-file", "o", "", "output file or gs://bucket/object (default: stdout)")
 	sqlInspectCmd.Flags().StringVarP(&inspectFormat, "format", "f", "report", "output format (report|ddl)")
+	addAnonymizeFlag(sqlInspectCmd, &inspectAnon)
+	sqlInspectCmd.Flags().BoolVar(&inspectIAMAuth, "iam-auth", false, "log in to the Cloud SQL instance with IAM database authentication instead of a password")
+	sqlInspectCmd.Flags().DurationVar(&inspectTimeout, "query-timeout", time.Minute, "maximum duration of each catalog query; longer queries are canceled on the server")
 	
 	sqlInspectCmd.MarkFlagRequired("user")
-	sqlInspectCmd.MarkFlagRequired("password")
 }
 
 func runSQLInspect(cmd *cobra.Command, args []string) error {
-	ctx := context.Background()
+	// Ctrl+C cancels in-flight catalog queries on the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Validate: either instance or host must be provided
 	if inspectInstance == "" && inspectHost == "" {
@@ -76,17 +90,35 @@ func runSQLInspect(cmd *cobra.Command, args []string) error {
 	if inspectInstance != "" && inspectHost != "" {
 		return fmt.Errorf("cannot specify both --instance and --host, choose one connection method")
 	}
+	if inspectIAMAuth {
+		if inspectInstance == "" {
+			return fmt.Errorf("--iam-auth requires --instance (Cloud SQL connector)")
+		}
+		if inspectPassword != "" {
+			return fmt.Errorf("cannot specify both --password and --iam-auth")
+		}
+	} else if inspectPassword == "" {
+		return fmt.Errorf("--password is required unless --iam-auth is set")
+	}
+
+	anonymizer, err := schemaAnonymizer(inspectAnon)
+	if err != nil {
+		return err
+	}
 
 	// Create inspector
 	var inspector *sql.DatabaseInspector
 	if inspectInstance != "" {
 		fmt.Fprintf(os.Stderr, "Connecting to Cloud SQL instance %s as %s...\n", inspectInstance, inspectUser)
 		inspector = sql.NewCloudSQLInspector(inspectInstance, inspectUser, inspectPassword, inspectDatabase)
+		inspector.SetIAMAuthN(inspectIAMAuth)
 	} else {
 		fmt.Fprintf(os.Stderr, "Connecting to %s:%d as %s...\n", inspectHost, inspectPort, inspectUser)
 		inspector = sql.NewDatabaseInspector(inspectHost, inspectUser, inspectPassword, inspectDatabase, inspectPort)
 	}
 
+	inspector.SetQueryTimeout(inspectTimeout)
+
 	// Inspect database
 	schema, err := inspector.InspectDatabase(ctx)
 	if err != nil {
@@ -95,6 +127,10 @@ func runSQLInspect(cmd *cobra.Command, args []string) error {
 
 	fmt.Fprintf(os.Stderr, "Successfully extracted schema for database: %s\n\n", schema.DatabaseName)
 
+	if anonymizer != nil {
+		schema = schema.Anonymized(anonymizer)
+	}
+
 	// Generate output
 	var output string
 	switch inspectFormat {
@@ -107,7 +143,16 @@ func runSQLInspect(cmd *cobra.Command, args []string) error {
 	}
 
 	// Write output
-	if inspectOutput != "" {
+	if gcs.IsURI(inspectOutput) {
+		client, err := sharedGCSClient(ctx)
+		if err != nil {
+			return err
+		}
+		if err := client.Upload(ctx, inspectOutput, []byte(output)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Output written to: %s\n", inspectOutput)
+	} else if inspectOutput != "" {
 		if err := os.WriteFile(inspectOutput, []byte(output), 0644); err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
 		}
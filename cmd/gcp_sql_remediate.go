@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/apply"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remediateFields       []string
+	remediateDryRun       bool
+	remediateYes          bool
+	remediateConcurrency  int
+	remediateChangeLogDir string
+)
+
+// sqlRemediateCmd represents the sql remediate command
+var sqlRemediateCmd = &cobra.Command{
+	Use:   "remediate",
+	Short: "Scan Cloud SQL instances and apply whitelisted low-risk fixes",
+	Long: `Scan Cloud SQL instances against the baselines and fix selected drifts
+through the SQL Admin API, without writing a plan first. Only low-risk checks
+are whitelisted: enabling automated backups, the backup window and retention,
+labels, the maintenance window and Query Insights settings. None of them
+restart the instance.
+
+Each check must be opted in with --field, or listed in remediation.fields in
+the config file. Before anything changes, the fixes for each instance are
+shown and must be confirmed; --yes skips the prompts for unattended runs.
+When the config file has an approval section, the approval gate replaces the
+prompts, as for apply. Every attempt is appended to the change log.
+
+Examples:
+  drift-analysis-cli gcp sql remediate --field settings.backup_enabled --dry-run
+  drift-analysis-cli gcp sql remediate --field settings.maintenance_window.hour --field labels.*
+  drift-analysis-cli gcp sql remediate --label env=prod --yes`,
+	RunE: runSQLRemediate,
+}
+
+func init() {
+	sqlCmd.AddCommand(sqlRemediateCmd)
+	sqlRemediateCmd.Flags().StringArrayVar(&remediateFields, "field", nil, "check ID or field to fix (repeatable; default: remediation.fields from the config)")
+	sqlRemediateCmd.Flags().BoolVar(&remediateDryRun, "dry-run", false, "show the fixes that would be applied without changing anything")
+	sqlRemediateCmd.Flags().BoolVarP(&remediateYes, "yes", "y", false, "apply without asking for confirmation")
+	sqlRemediateCmd.Flags().IntVar(&remediateConcurrency, "concurrency", 2, "maximum number of instances changed at once")
+	sqlRemediateCmd.Flags().StringVar(&remediateChangeLogDir, "change-log-dir", "history", "directory of the change log")
+}
+
+func runSQLRemediate(cmd *cobra.Command, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var config struct {
+		Projects     []string          `yaml:"projects"`
+		SQLBaselines []sql.SQLBaseline `yaml:"sql_baselines"`
+		Policy       struct {
+			SQL *sql.Policy `yaml:"sql"`
+		} `yaml:"policy"`
+		Remediation struct {
+			Fields []string `yaml:"fields"`
+		} `yaml:"remediation"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+	if len(config.SQLBaselines) == 0 {
+		return fmt.Errorf("no SQL baselines defined in config")
+	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+
+	fields := remediateFields
+	if len(fields) == 0 {
+		fields = config.Remediation.Fields
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("no fields opted in; pass --field for each check to fix or list them in remediation.fields")
+	}
+	if remediateConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	remediator, err := sql.NewRemediator(ctx)
+	if err != nil {
+		return err
+	}
+	executors := map[string]apply.Executor{"Cloud SQL": remediator}
+	optedIn, err := resolveApplyFields(fields, executors)
+	if err != nil {
+		return err
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+	labels, err := labelSelector("database-role")
+	if err != nil {
+		return err
+	}
+
+	analyzer, err := sql.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create SQL analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	analyzer.SetRetryPolicy(retryPolicy())
+	analyzer.SetPolicy(config.Policy.SQL)
+
+	instances, err := analyzer.DiscoverInstances(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover instances: %w", err)
+	}
+
+	plan := &checks.Plan{
+		Version:     checks.PlanVersion,
+		Source:      "sql remediate",
+		GeneratedAt: time.Now().UTC(),
+		Steps:       []checks.Step{},
+	}
+	for _, baseline := range config.SQLBaselines {
+		selected := sql.SelectInstances(instances, baseline, config.SQLBaselines)
+		selected = sql.FilterByLabels(selected, labels)
+		drift := analyzer.AnalyzeDrift(selected, baseline.Config)
+		plan.Steps = append(plan.Steps, checks.BuildPlan(drift.ToReport()).Steps...)
+	}
+	for i := range plan.Steps {
+		plan.Steps[i].Order = i + 1
+	}
+
+	runner := &apply.Runner{
+		Executors: executors,
+		Options: apply.Options{
+			Checks:      optedIn,
+			DryRun:      remediateDryRun,
+			Concurrency: remediateConcurrency,
+		},
+	}
+
+	eligible := runner.Eligible(plan)
+	if len(eligible) == 0 {
+		fmt.Println("No drift to fix for the opted-in fields")
+		return nil
+	}
+
+	if !remediateDryRun {
+		approvals, err := requestApprovals(ctx, plan, eligible)
+		if err != nil {
+			return err
+		}
+		if approvals == nil && !remediateYes {
+			if !isTerminal(os.Stdin) {
+				return fmt.Errorf("confirmation needs a terminal; pass --yes to apply without confirmation")
+			}
+			approvals, err = confirmSteps(os.Stdin, os.Stdout, eligible, currentActor())
+			if err != nil {
+				return err
+			}
+		}
+		runner.Options.Approvals = approvals
+	}
+
+	started := time.Now()
+	results := runner.Run(ctx, plan)
+	return recordResults(results, remediateChangeLogDir, started)
+}
+
+// confirmSteps asks once per resource whether to apply its steps and returns
+// the answers as approvals by actor. Anything but y or yes declines.
+func confirmSteps(in io.Reader, out io.Writer, steps []checks.Step, actor string) (map[string]apply.Approval, error) {
+	type resource struct{ project, name string }
+	var order []resource
+	byResource := make(map[resource][]checks.Step)
+	for _, step := range steps {
+		key := resource{step.Project, step.Resource}
+		if _, seen := byResource[key]; !seen {
+			order = append(order, key)
+		}
+		byResource[key] = append(byResource[key], step)
+	}
+
+	reader := bufio.NewReader(in)
+	approvals := make(map[string]apply.Approval, len(steps))
+	for _, key := range order {
+		resourceSteps := byResource[key]
+		fmt.Fprintf(out, "\n%s/%s:\n", key.project, key.name)
+		for _, step := range resourceSteps {
+			fmt.Fprintf(out, "  %s: %s -> %s\n", step.Field, step.Actual, step.Expected)
+		}
+		fmt.Fprintf(out, "Apply %d fix(es) to %s? [y/N] ", len(resourceSteps), key.name)
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		approved := answer == "y" || answer == "yes"
+		for _, step := range resourceSteps {
+			approvals[step.ID] = apply.Approval{Approved: approved, By: actor}
+		}
+		if err == io.EOF {
+			fmt.Fprintln(out)
+			break
+		}
+	}
+	return approvals, nil
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/network"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	driftreport "github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
+	"github.com/spf13/cobra"
+)
+
+var vpcOutputFormat string
+
+// vpcCmd represents the vpc command
+var vpcCmd = &cobra.Command{
+	Use:   "vpc",
+	Short: "Analyze VPC networks and firewall rules for configuration drift",
+	Long: `Analyze Google Cloud VPC networks against baseline configurations.
+Compares routing mode, subnet flow logs and Private Google Access, allowed
+ingress CIDRs, required deny rules, and firewall rule logging.`,
+	RunE: runVPCAnalysis,
+}
+
+func init() {
+	gcpCmd.AddCommand(vpcCmd)
+	vpcCmd.Flags().StringVarP(&vpcOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+}
+
+func runVPCAnalysis(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	// Read config file
+	var config struct {
+		Projects     []string              `yaml:"projects"`
+		VPCBaselines []network.VPCBaseline `yaml:"vpc_baselines"`
+	}
+
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	if len(config.VPCBaselines) == 0 {
+		return fmt.Errorf("no VPC baselines defined in config")
+	}
+
+	projects, err := resolveProjects(ctx, config.Projects)
+	if err != nil {
+		return err
+	}
+
+	if explainPlan != "" {
+		plan, err := newRunPlan(cmd, projects, nil, vpcOutputFormat)
+		if err != nil {
+			return err
+		}
+		for _, baseline := range config.VPCBaselines {
+			plan.addBaseline(baseline.Name, nil, baseline.Networks, "")
+		}
+		return printPlan(plan)
+	}
+	canary, err := newCanarySampler()
+	if err != nil {
+		return err
+	}
+
+	staleness, err := loadStalenessPolicy()
+	if err != nil {
+		return err
+	}
+	historyStore, err := openHistory(ctx)
+	if err != nil {
+		return err
+	}
+	reportDest, err := reportDestination()
+	if err != nil {
+		return err
+	}
+	acknowledged, err := activeSuppressions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	escalation, err := loadEscalationPolicy()
+	if err != nil {
+		return err
+	}
+	sloPolicy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+	exporters, err := openExporters(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Create analyzer
+	analyzer, err := network.NewAnalyzer(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create network analyzer: %w", err)
+	}
+	defer analyzer.Close()
+	defer applyContinueOnError(analyzer)()
+
+	// Machine-readable formats expect a clean stream on stdout, so progress goes to stderr
+	progress := os.Stdout
+	if genericFormats[vpcOutputFormat] || splitBy != "" {
+		progress = os.Stderr
+	}
+	scanID := newScanID()
+
+	// Discover networks once; baselines only filter them
+	networks, err := analyzer.DiscoverNetworks(ctx, projects)
+	if err != nil {
+		return fmt.Errorf("failed to discover networks: %w", err)
+	}
+
+	// Run analysis for each baseline
+	for _, baseline := range config.VPCBaselines {
+		fmt.Fprintf(progress, "Analyzing VPC networks: %s\n", baseline.Name)
+		fmt.Fprintln(progress, "================================================================================")
+
+		matched := network.FilterNetworks(networks, baseline.Networks)
+		report := analyzer.AnalyzeDrift(canarySample(canary, matched), baseline.Config)
+		if err := escalateFindings(ctx, progress, historyStore, escalation, "vpc-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := linker.Annotate(report.ToReport()); err != nil {
+			return err
+		}
+		annotateRemediation(report.ToReport())
+		if err := annotateGitHub(progress, "vpc_baselines", baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		report.Warnings = baselineWarnings(staleness, baseline.Name, baseline.Metadata, nil, nil)
+		report.Acknowledgements = history.Acknowledgements(report.ToReport(), acknowledged)
+		if report.SLO, err = measureSLO(ctx, historyStore, sloPolicy, "vpc-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		stabilize(&report.Timestamp, report.Instances, func(n *network.NetworkDrift) ([]string, []driftreport.Drift) {
+			return []string{n.Project, n.Name}, n.Drifts
+		})
+		printCanary(progress, canary, report.ToReport(), len(matched))
+		if err := recordHistory(ctx, progress, historyStore, "vpc-"+baseline.Name, report.ToReport()); err != nil {
+			return err
+		}
+		if err := exportFindings(ctx, progress, exporters, scanID, report.ToReport()); err != nil {
+			return err
+		}
+
+		if splitBy != "" {
+			if err := writeSplitReports(report.ToReport(), vpcOutputFormat, scanID, "vpc-"+baseline.Name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if reportDest != "" {
+			if err := writeReportFile(ctx, progress, reportDest, "vpc-"+baseline.Name, vpcOutputFormat, scanID, report); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Output report
+		switch vpcOutputFormat {
+		case "tui":
+			return tui.Run(tui.FromReport(report.ToReport()))
+		case "json":
+			output, err := report.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format JSON: %w", err)
+			}
+			fmt.Println(output)
+		case "yaml":
+			output, err := report.FormatYAML()
+			if err != nil {
+				return fmt.Errorf("failed to format YAML: %w", err)
+			}
+			fmt.Println(output)
+		case "ndjson", "dot", "mermaid", "sarif", "gitlab-codequality", "junit", "plan":
+			if err := printGeneric(report.ToReport(), vpcOutputFormat, scanID); err != nil {
+				return err
+			}
+			continue
+		default:
+			fmt.Println(report.FormatText())
+		}
+
+		fmt.Println()
+	}
+
+	return nil
+}
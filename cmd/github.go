@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/configfile"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var githubAnnotations bool
+
+func init() {
+	gcpCmd.PersistentFlags().BoolVar(&githubAnnotations, "github-annotations", false, "emit a GitHub Actions ::error/::warning annotation per drift, pointing at its baseline in the config file, and append a summary to $GITHUB_STEP_SUMMARY")
+}
+
+// annotateGitHub writes a workflow command per drift of a baseline's report
+// to w and appends the report's summary to $GITHUB_STEP_SUMMARY, with
+// --github-annotations. list is the config key of the baseline, e.g.
+// sql_baselines.
+func annotateGitHub(w io.Writer, list, baseline string, r *report.Report) error {
+	if !githubAnnotations {
+		return nil
+	}
+
+	// Drifts of the same field point at the same line
+	lines := make(map[string]configfile.Location)
+	fmt.Fprint(w, r.FormatGitHubAnnotations(report.GitHubOptions{
+		Locate: func(res report.Resource, d report.Drift) (string, int) {
+			loc, ok := lines[d.Field]
+			if !ok {
+				if loc, ok = configfile.LocateBaseline(cfgFile, list, baseline, d.Field); !ok {
+					loc = configfile.Location{File: cfgFile}
+				}
+				lines[d.Field] = loc
+			}
+			return filepath.ToSlash(loc.File), loc.Line
+		},
+		Title: func(res report.Resource, d report.Drift) string {
+			if c, ok := checks.ForDrift(res.Type, d.Field, d.Severity); ok {
+				return c.ID
+			}
+			return ""
+		},
+	}))
+
+	path := os.Getenv("GITHUB_STEP_SUMMARY")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open the step summary: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(r.FormatGitHubSummary(fmt.Sprintf("%s: %s", r.Title, baseline))); err != nil {
+		return fmt.Errorf("failed to write the step summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	deterministic bool
+	goldenFile    string
+	updateGolden  bool
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&deterministic, "deterministic", false, "render reproducible output: fixed timestamp and scan ID, sorted resources and drifts, no color")
+	rootCmd.PersistentFlags().StringVar(&goldenFile, "golden", "", "compare stdout with this golden file instead of printing it (implies --deterministic)")
+	rootCmd.PersistentFlags().BoolVar(&updateGolden, "update-golden", false, "write stdout to the --golden file instead of comparing")
+}
+
+// golden captures stdout while a command runs with --golden
+var golden *goldenCapture
+
+// goldenCapture redirects stdout into a buffer
+type goldenCapture struct {
+	stdout *os.File
+	writer *os.File
+	done   chan struct{}
+	output bytes.Buffer
+}
+
+// setupOutput applies --deterministic and starts capturing stdout for --golden
+func setupOutput(cmd *cobra.Command, args []string) error {
+	if updateGolden && goldenFile == "" {
+		return fmt.Errorf("--update-golden requires --golden")
+	}
+	if goldenFile != "" {
+		deterministic = true
+	}
+	if !deterministic {
+		return nil
+	}
+	report.DisableColor()
+
+	if goldenFile == "" {
+		return nil
+	}
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+	capture := &goldenCapture{stdout: os.Stdout, writer: writer, done: make(chan struct{})}
+	go func() {
+		_, _ = io.Copy(&capture.output, reader)
+		close(capture.done)
+	}()
+	golden = capture
+	os.Stdout = writer
+	return nil
+}
+
+// finishGolden restores stdout and compares the captured output with the
+// golden file, or rewrites it with --update-golden. Output of a failed command
+// is printed instead.
+func finishGolden(succeeded bool) error {
+	if golden == nil {
+		return nil
+	}
+	os.Stdout = golden.stdout
+	golden.writer.Close()
+	<-golden.done
+	got := golden.output.String()
+	golden = nil
+
+	if !succeeded {
+		fmt.Print(got)
+		return nil
+	}
+
+	if updateGolden {
+		if err := os.WriteFile(goldenFile, []byte(got), 0o644); err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Updated golden file %s\n", goldenFile)
+		return nil
+	}
+
+	want, err := os.ReadFile(goldenFile)
+	if err != nil {
+		return fmt.Errorf("failed to read golden file (create it with --update-golden): %w", err)
+	}
+	if diff := report.DiffGolden(string(want), got); diff != "" {
+		return fmt.Errorf("output differs from golden file %s at %s", goldenFile, diff)
+	}
+	fmt.Fprintf(os.Stderr, "Output matches golden file %s\n", goldenFile)
+	return nil
+}
+
+// newScanID returns a random scan ID, or a fixed one with --deterministic
+func newScanID() string {
+	if deterministic {
+		return report.GoldenScanID
+	}
+	return report.NewScanID()
+}
+
+// stabilize makes a resource-specific report deterministic with
+// --deterministic: the timestamp is fixed and items and their drifts are
+// sorted. key returns an item's sort key and drifts.
+func stabilize[T any](timestamp *time.Time, items []T, key func(T) ([]string, []report.Drift)) {
+	if !deterministic {
+		return
+	}
+	*timestamp = report.GoldenTime
+
+	sortKey := func(item T) string {
+		parts, _ := key(item)
+		return strings.Join(parts, "\x00")
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return sortKey(items[i]) < sortKey(items[j])
+	})
+	for _, item := range items {
+		_, drifts := key(item)
+		report.SortDrifts(drifts)
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// historyDir is where gcp commands record their reports
+	historyDir string
+
+	historyLocation     string
+	historyName         string
+	historyLast         int
+	historyOutputFormat string
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show compliance trends from recorded drift reports",
+	Long: `Show how compliance changed across the reports recorded with --history-dir,
+together with the drifts that appeared and were resolved in each run. Reports
+are grouped by analysis and baseline, e.g. sql-production.
+
+The history location is a local directory or a GCS bucket (gs://bucket/prefix).
+
+Examples:
+  drift-analysis-cli gcp sql --history-dir history
+  drift-analysis-cli history
+  drift-analysis-cli history --history-dir gs://drift-reports/prod --name sql-production --last 30
+  drift-analysis-cli history -o json`,
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.AddCommand(historySLOCmd)
+	historyCmd.PersistentFlags().StringVar(&historyLocation, "history-dir", "history", "history directory or gs://bucket/prefix to read reports from")
+	historyCmd.PersistentFlags().StringVar(&historyName, "name", "", "only show reports recorded under this name (e.g. sql-production)")
+	historyCmd.Flags().IntVar(&historyLast, "last", 10, "number of most recent runs to show per name (0 for all)")
+	historyCmd.PersistentFlags().StringVarP(&historyOutputFormat, "output", "o", "text", "output format (text|json)")
+
+	gcpCmd.PersistentFlags().StringVar(&historyDir, "history-dir", "", "record each report in this directory or gs://bucket/prefix for the history command")
+}
+
+// historySLOCmd represents the history slo command
+var historySLOCmd = &cobra.Command{
+	Use:   "slo",
+	Short: "Show remediation SLO attainment per team from recorded drift reports",
+	Long: `Measure how quickly drift is resolved against the targets in the slo section
+of the config file, e.g. critical drift resolved within 48h for 95% of
+findings. Findings are followed across the reports recorded with --history-dir
+and grouped by the team label of their resources.
+
+A finding meets its target when it is resolved in time, and breaches it when
+it is resolved late or is still open past the target. Findings still open
+within the target are listed but not counted. Only findings resolved in the
+last window_days (default 30) count.
+
+Examples:
+  drift-analysis-cli history slo
+  drift-analysis-cli history slo --history-dir gs://drift-reports/prod --name sql-production
+  drift-analysis-cli history slo -o json`,
+	RunE: runHistorySLO,
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if historyOutputFormat != "text" && historyOutputFormat != "json" {
+		return fmt.Errorf("unsupported output format %q for history (text|json)", historyOutputFormat)
+	}
+
+	ctx := context.Background()
+	store, names, err := openHistoryNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	trends := make(map[string][]history.Run)
+	for i, name := range names {
+		reports, err := store.LoadReports(ctx, name)
+		if err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			return fmt.Errorf("no reports recorded under %q in %s", name, historyLocation)
+		}
+
+		runs := history.Trend(reports)
+		if historyLast > 0 && len(runs) > historyLast {
+			runs = runs[len(runs)-historyLast:]
+		}
+		trends[name] = runs
+
+		if historyOutputFormat == "text" {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Print(history.FormatTrend(name, runs))
+		}
+	}
+
+	if historyOutputFormat == "json" {
+		output, err := json.MarshalIndent(trends, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(output))
+	}
+	return nil
+}
+
+func runHistorySLO(cmd *cobra.Command, args []string) error {
+	if historyOutputFormat != "text" && historyOutputFormat != "json" {
+		return fmt.Errorf("unsupported output format %q for history slo (text|json)", historyOutputFormat)
+	}
+	policy, err := loadSLOPolicy()
+	if err != nil {
+		return err
+	}
+	if policy == nil || len(policy.Targets) == 0 {
+		return fmt.Errorf("no SLO targets configured; add an slo section to %s", cfgFile)
+	}
+
+	ctx := context.Background()
+	store, names, err := openHistoryNames(ctx)
+	if err != nil {
+		return err
+	}
+	var histories [][]*report.Report
+	for _, name := range names {
+		reports, err := store.LoadReports(ctx, name)
+		if err != nil {
+			return err
+		}
+		histories = append(histories, reports)
+	}
+	slos := policy.Attainment(histories, time.Now())
+
+	if historyOutputFormat == "json" {
+		output, err := json.MarshalIndent(slos, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(string(output))
+		return nil
+	}
+	fmt.Printf("SLO attainment across %d history name(s) in %s\n\n", len(names), historyLocation)
+	if len(slos) == 0 {
+		fmt.Println("No findings with an SLO target recorded.")
+		return nil
+	}
+	fmt.Print(report.FormatSLO(slos))
+	return nil
+}
+
+// openHistoryNames opens the --history-dir store of the history commands and
+// returns the names to read: --name, or every name recorded
+func openHistoryNames(ctx context.Context) (history.ReportStore, []string, error) {
+	store, err := history.OpenReportStore(ctx, historyLocation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if historyName != "" {
+		return store, []string{historyName}, nil
+	}
+	names, err := store.ReportNames(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("no reports recorded in %s; run a scan with --history-dir first", historyLocation)
+	}
+	return store, names, nil
+}
+
+// openHistory opens the store for --history-dir, or returns nil when reports
+// are not recorded
+func openHistory(ctx context.Context) (history.ReportStore, error) {
+	if historyDir == "" {
+		return nil, nil
+	}
+	return history.OpenReportStore(ctx, historyDir)
+}
+
+// recordHistory saves a report under a name in the history store; a nil
+// store records nothing
+func recordHistory(ctx context.Context, w io.Writer, store history.ReportStore, name string, r *report.Report) error {
+	if store == nil {
+		return nil
+	}
+	path, err := store.SaveReport(ctx, unsafeFileChars.ReplaceAllString(name, "_"), r)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Recorded report in %s\n", path)
+	return nil
+}
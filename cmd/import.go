@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/importer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importOutputFormat string
+	importResourceType string
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import drift detected by other tools",
+	Long: `Import drift findings produced by other tools and render them through the
+same report formats as the native analyzers.`,
+}
+
+// importTerraformCmd represents the import terraform command
+var importTerraformCmd = &cobra.Command{
+	Use:   "terraform <plan.json|->",
+	Short: "Import a Terraform JSON plan",
+	Long: `Import the output of 'terraform show -json plan.out'. Changes made outside
+Terraform (resource_drift) and pending changes (resource_changes) are reported
+as drift.
+
+Examples:
+  terraform plan -out plan.out && terraform show -json plan.out > plan.json
+  drift-analysis-cli import terraform plan.json
+  terraform show -json plan.out | drift-analysis-cli import terraform - -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(args[0], func(r io.Reader) (*report.Report, error) {
+			return importer.Terraform(r)
+		})
+	},
+}
+
+// importGcloudDiffCmd represents the import gcloud-diff command
+var importGcloudDiffCmd = &cobra.Command{
+	Use:   "gcloud-diff <file.diff|->",
+	Short: "Import a unified diff of gcloud describe output",
+	Long: `Import a unified diff between two 'gcloud ... describe --format=yaml' outputs.
+The old side of the diff is treated as the baseline and the new side as the
+actual state.
+
+Examples:
+  diff -u baseline/prod-db.yaml <(gcloud sql instances describe prod-db --format=yaml) > prod-db.diff
+  drift-analysis-cli import gcloud-diff prod-db.diff --resource-type "Cloud SQL"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runImport(args[0], func(r io.Reader) (*report.Report, error) {
+			return importer.GcloudDiff(r, importResourceType)
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importTerraformCmd)
+	importCmd.AddCommand(importGcloudDiffCmd)
+
+	importCmd.PersistentFlags().StringVarP(&importOutputFormat, "output", "o", "text", "output format (text|json|yaml|ndjson|dot|mermaid|sarif|gitlab-codequality|junit|plan|tui)")
+	importGcloudDiffCmd.Flags().StringVar(&importResourceType, "resource-type", "gcloud resource", "resource type label used in the report")
+}
+
+func runImport(path string, parse func(io.Reader) (*report.Report, error)) error {
+	var input io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		input = f
+	}
+
+	r, err := parse(input)
+	if err != nil {
+		return err
+	}
+
+	return printReport(r, importOutputFormat)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// loadLabelPolicy reads the label_policy section of the config file; nil
+// disables label checks
+func loadLabelPolicy() (*analyzer.LabelPolicy, error) {
+	var config struct {
+		LabelPolicy *analyzer.LabelPolicy `yaml:"label_policy"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	if err := config.LabelPolicy.Validate(); err != nil {
+		return nil, err
+	}
+	return config.LabelPolicy, nil
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	labelFilters []string
+	filterRole   string
+)
+
+func init() {
+	addLabelFlags(sqlCmd, "database-role")
+	addLabelFlags(gkeCmd, "cluster-role")
+	addLabelFlags(sqlRemediateCmd, "database-role")
+}
+
+// addLabelFlags registers --label and the deprecated --filter-role, which
+// filtered on a single role label, on an analysis command
+func addLabelFlags(cmd *cobra.Command, roleLabel string) {
+	cmd.Flags().StringArrayVar(&labelFilters, "label", nil, "only analyze resources with this label, as key=value (repeatable; all must match)")
+	cmd.Flags().StringVar(&filterRole, "filter-role", "", "only analyze resources with this "+roleLabel+" label")
+	_ = cmd.Flags().MarkDeprecated("filter-role", "use --label "+roleLabel+"=<role> instead")
+}
+
+// labelSelector returns the labels selected by --label and --filter-role;
+// roleLabel is the label key --filter-role stands for
+func labelSelector(roleLabel string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, filter := range labelFilters {
+		for _, pair := range strings.Split(filter, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok || key == "" {
+				return nil, fmt.Errorf("invalid --label %q: expected key=value", pair)
+			}
+			if existing, found := labels[key]; found && existing != value {
+				return nil, fmt.Errorf("--label %s is given twice with different values (%q and %q)", key, existing, value)
+			}
+			labels[key] = value
+		}
+	}
+	if filterRole != "" {
+		if existing, found := labels[roleLabel]; found && existing != filterRole {
+			return nil, fmt.Errorf("--filter-role %q conflicts with --label %s=%s", filterRole, roleLabel, existing)
+		}
+		labels[roleLabel] = filterRole
+	}
+	return labels, nil
+}
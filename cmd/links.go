@@ -0,0 +1,15 @@
+package cmd
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/report"
+
+// loadLinker reads the links section of the config file; drifts get console
+// links unless it disables them
+func loadLinker() (*report.Linker, error) {
+	var config struct {
+		Links report.LinkConfig `yaml:"links"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	return report.NewLinker(config.Links)
+}
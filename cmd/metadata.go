@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/metacache"
+)
+
+var (
+	metadataChecks   bool
+	metadataCacheTTL time.Duration
+)
+
+func init() {
+	gcpCmd.PersistentFlags().BoolVar(&metadataChecks, "metadata-checks", true, "warn when a baseline expects GKE versions or Cloud SQL flag values GCP no longer supports")
+	gcpCmd.PersistentFlags().DurationVar(&metadataCacheTTL, "metadata-cache-ttl", 24*time.Hour, "how long GKE server configs and Cloud SQL flag lists are cached in "+metacache.DefaultDir)
+}
+
+// newMetadataCache returns the cache used for stale-baseline checks, or nil
+// when they are disabled
+func newMetadataCache() *metacache.Cache {
+	if !metadataChecks {
+		return nil
+	}
+	return metacache.New(metadataCacheDir(), metadataCacheTTL)
+}
+
+// warnMetadataLookup reports a failed metadata lookup; findings are still
+// reported, only without stale-baseline warnings
+func warnMetadataLookup(w io.Writer, err error) {
+	if err != nil {
+		fmt.Fprintf(w, "Warning: could not check the baseline against GCP metadata: %v\n", err)
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/notify"
+	"github.com/spf13/cobra"
+)
+
+var notifyFlushForce bool
+
+// notifyCmd represents the notify command
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Manage notifications queued after a failed delivery",
+}
+
+// notifyFlushCmd represents the notify flush command
+var notifyFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Replay the notifications queued after a failed delivery",
+	Long: `Resend the notifications in the dead-letter queue configured under
+daemon.notifications.dead_letter. A notification is queued when its sink
+fails with a network error, a timeout, 429 or a 5xx response, and is retried
+with backoff on the next run; flush replays the ones that are due, or all of
+them with --force.
+
+Sent notifications are removed from the queue. Notifications that fail again
+wait twice as long, and are dropped after max_attempts or when the sink
+rejects them with another 4xx response.
+
+Examples:
+  drift-analysis-cli notify flush
+  drift-analysis-cli notify flush --force`,
+	RunE: runNotifyFlush,
+}
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	notifyCmd.AddCommand(notifyFlushCmd)
+	notifyFlushCmd.Flags().BoolVar(&notifyFlushForce, "force", false, "resend every queued notification, ignoring the backoff")
+}
+
+func runNotifyFlush(cmd *cobra.Command, args []string) error {
+	var config struct {
+		Daemon struct {
+			Notifications notify.Config `yaml:"notifications"`
+		} `yaml:"daemon"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	queue, err := deadLetterQueue(&config.Daemon.Notifications)
+	if err != nil {
+		return err
+	}
+	if queue == nil {
+		return fmt.Errorf("no dead-letter queue configured; add daemon.notifications.dead_letter to %s", cfgFile)
+	}
+
+	result, err := queue.Flush(cmd.Context(), notifyFlushForce)
+	fmt.Printf("Flushed %s: %d sent, %d pending, %d dropped\n", queue.Dir(), result.Sent, result.Pending, result.Dropped)
+	return err
+}
+
+// deadLetterQueue builds the dead-letter queue of the notifications, queuing
+// under the workspace's cache_dir unless the queue has a dir of its own. The
+// notifiers built from config afterwards share the queue directory.
+func deadLetterQueue(config *notify.Config) (*notify.DeadLetterQueue, error) {
+	if config.DeadLetter != nil && config.DeadLetter.Dir == "" && workspace.CacheDir != "" {
+		config.DeadLetter.Dir = filepath.Join(workspace.CacheDir, "notifications")
+	}
+	return config.DeadLetterQueue()
+}
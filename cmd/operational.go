@@ -0,0 +1,18 @@
+package cmd
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// loadOperationalPolicy reads the operational section of the config file;
+// nil keeps the default severities of unhealthy resource states
+func loadOperationalPolicy() (*analyzer.OperationalPolicy, error) {
+	var config struct {
+		Operational *analyzer.OperationalPolicy `yaml:"operational"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Operational.Validate(); err != nil {
+		return nil, err
+	}
+	return config.Operational, nil
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/tui"
+)
+
+// genericFormats are output formats rendered from the generic report model
+var genericFormats = map[string]bool{
+	"ndjson":             true,
+	"dot":                true,
+	"mermaid":            true,
+	"sarif":              true,
+	"gitlab-codequality": true,
+	"junit":              true,
+	"plan":               true,
+}
+
+// printReport renders a generic report in the requested output format
+func printReport(r *report.Report, format string) error {
+	if deterministic {
+		r.Normalize()
+	}
+
+	switch format {
+	case "tui":
+		return tui.Run(tui.FromReport(r))
+	case "json":
+		output, err := r.FormatJSON()
+		if err != nil {
+			return fmt.Errorf("failed to format JSON: %w", err)
+		}
+		fmt.Println(output)
+	case "yaml":
+		output, err := r.FormatYAML()
+		if err != nil {
+			return fmt.Errorf("failed to format YAML: %w", err)
+		}
+		fmt.Println(output)
+	default:
+		if genericFormats[format] {
+			return printGeneric(r, format, newScanID())
+		}
+		fmt.Println(r.FormatText())
+	}
+
+	return nil
+}
+
+// printGeneric renders formats that only exist for the generic report model
+func printGeneric(r *report.Report, format, scanID string) error {
+	output, err := renderGeneric(r, format, scanID)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// renderGeneric renders a generic report to a string in any non-interactive format
+func renderGeneric(r *report.Report, format, scanID string) (string, error) {
+	switch format {
+	case "ndjson":
+		output, err := r.FormatNDJSON(scanID)
+		if err != nil {
+			return "", fmt.Errorf("failed to format NDJSON: %w", err)
+		}
+		return output, nil
+	case "dot":
+		return r.FormatDOT(), nil
+	case "mermaid":
+		return r.FormatMermaid(), nil
+	case "plan":
+		return checks.BuildPlan(r).FormatYAML()
+	case "junit":
+		output, err := r.FormatJUnit(junitOptions())
+		if err != nil {
+			return "", err
+		}
+		return output + "\n", nil
+	case "sarif":
+		output, err := r.FormatSARIF(sarifOptions())
+		if err != nil {
+			return "", err
+		}
+		return output + "\n", nil
+	case "gitlab-codequality":
+		output, err := r.FormatGitLabCodeQuality(codeQualityOptions())
+		if err != nil {
+			return "", err
+		}
+		return output + "\n", nil
+	case "json":
+		output, err := r.FormatJSON()
+		if err != nil {
+			return "", fmt.Errorf("failed to format JSON: %w", err)
+		}
+		return output + "\n", nil
+	case "yaml":
+		output, err := r.FormatYAML()
+		if err != nil {
+			return "", fmt.Errorf("failed to format YAML: %w", err)
+		}
+		return output, nil
+	case "text", "":
+		return r.FormatText(), nil
+	default:
+		return "", fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// sarifOptions resolves SARIF rules from the check registry and reports the
+// config file as the location of every finding
+func sarifOptions() report.SARIFOptions {
+	version := rootCmd.Version
+	if deterministic {
+		// Golden files must not change with every release
+		version = ""
+	}
+	return report.SARIFOptions{
+		ToolVersion: version,
+		ArtifactURI: filepath.ToSlash(cfgFile),
+		Rule: func(res report.Resource, d report.Drift) (report.SARIFRule, bool) {
+			c, ok := checks.ForDrift(res.Type, d.Field, d.Severity)
+			if !ok {
+				return report.SARIFRule{}, false
+			}
+			return report.SARIFRule{ID: c.ID, Description: c.Description, Help: c.Remediation}, true
+		},
+	}
+}
+
+// codeQualityOptions names issues after the checks in the registry and
+// reports the config file as the location of every issue
+func codeQualityOptions() report.CodeQualityOptions {
+	return report.CodeQualityOptions{
+		Path: filepath.ToSlash(cfgFile),
+		CheckName: func(res report.Resource, d report.Drift) string {
+			if c, ok := checks.ForDrift(res.Type, d.Field, d.Severity); ok {
+				return c.ID
+			}
+			return ""
+		},
+	}
+}
+
+// junitOptions adds a passing test case for every registered check field of a
+// resource type that has no drift
+func junitOptions() report.JUnitOptions {
+	return report.JUnitOptions{
+		Fields: func(resourceType string) []string {
+			seen := make(map[string]bool)
+			var fields []string
+			for _, c := range checks.All() {
+				// Wildcard paths only exist per key, so they appear only when drifted
+				if c.ResourceType != resourceType || strings.Contains(c.Path, "*") || seen[c.Path] {
+					continue
+				}
+				seen[c.Path] = true
+				fields = append(fields, c.Path)
+			}
+			return fields
+		},
+	}
+}
@@ -0,0 +1,307 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/notify"
+	"github.com/jessequinn/drift-analysis-cli/pkg/pipeline"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run workflows defined in the config file",
+}
+
+// runPipelineCmd represents the run pipeline command
+var runPipelineCmd = &cobra.Command{
+	Use:   "pipeline [name]",
+	Short: "Run a named scan pipeline from the config file",
+	Long: `Run a pipeline from the pipelines section of the config file. A pipeline is
+a sequence of steps executed in order:
+
+  scan: sql|gke        analyze resources against every SQL or GKE baseline
+  inspect: {...}       inspect database schemas and check their schema baselines
+  render: {...}        write everything collected so far in an output format
+  notify: {...}        send everything collected so far to notification sinks
+
+Without a name, the configured pipelines are listed.
+
+Examples:
+  drift-analysis-cli run pipeline
+  drift-analysis-cli run pipeline nightly --config /etc/drift/config.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPipeline,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	runCmd.AddCommand(runPipelineCmd)
+}
+
+// pipelineConfig is the config file as used by pipelines
+type pipelineConfig struct {
+	daemonConfig `yaml:",inline"`
+	Pipelines    map[string]pipeline.Pipeline `yaml:"pipelines"`
+}
+
+// renderFormats are the output formats a render step supports
+func renderFormats() map[string]bool {
+	formats := map[string]bool{"text": true, "json": true, "yaml": true}
+	for format := range genericFormats {
+		formats[format] = true
+	}
+	return formats
+}
+
+func runPipeline(cmd *cobra.Command, args []string) error {
+	var config pipelineConfig
+	if err := decodeConfig(&config); err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return listPipelines(config.Pipelines)
+	}
+	name := args[0]
+	p, ok := config.Pipelines[name]
+	if !ok {
+		return fmt.Errorf("pipeline %q not found in config (run 'run pipeline' to list pipelines)", name)
+	}
+	if err := p.Validate(renderFormats()); err != nil {
+		return fmt.Errorf("pipeline %s: %w", name, err)
+	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	for _, baseline := range config.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+
+	var sqlConfig sql.Config
+	if err := decodeConfig(&sqlConfig); err != nil {
+		return err
+	}
+	if err := sqlConfig.LoadSchemaSpecs(filepath.Dir(cfgFile)); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Projects are resolved once, by the first scan step
+	var projects []string
+	resolve := func(ctx context.Context) ([]string, error) {
+		if projects != nil {
+			return projects, nil
+		}
+		resolved, err := resolveProjects(ctx, config.Projects)
+		if err != nil {
+			return nil, err
+		}
+		projects = resolved
+		return projects, nil
+	}
+
+	_, err := p.Run(ctx, name, pipeline.Handlers{
+		Scan: func(ctx context.Context, kind string) (*report.Report, error) {
+			projects, err := resolve(ctx)
+			if err != nil {
+				return nil, err
+			}
+			var reports map[string]*report.Report
+			switch kind {
+			case "sql":
+				reports, err = scanSQL(ctx, &config.daemonConfig, projects, nil)
+			case "gke":
+				reports, err = scanGKE(ctx, &config.daemonConfig, projects)
+			}
+			if err != nil {
+				return nil, err
+			}
+			return mergeReports(reports), nil
+		},
+		Inspect: func(ctx context.Context, step pipeline.InspectStep) (*report.Report, error) {
+			return inspectSchemas(ctx, sqlConfig.DatabaseConnections, step)
+		},
+		Render: renderPipelineReport,
+		Notify: func(ctx context.Context, r *report.Report, step pipeline.NotifyStep) error {
+			return notifyPipelineReport(ctx, &config.Daemon.Notifications, config.Daemon.HistoryDir, r, step)
+		},
+		Logf: func(format string, args ...any) {
+			fmt.Fprintf(os.Stderr, format+"\n", args...)
+		},
+	})
+	return err
+}
+
+// listPipelines prints the configured pipelines and their steps
+func listPipelines(pipelines map[string]pipeline.Pipeline) error {
+	if len(pipelines) == 0 {
+		fmt.Println("No pipelines defined in config")
+		return nil
+	}
+
+	names := make([]string, 0, len(pipelines))
+	for name := range pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name)
+		for i, step := range pipelines[name].Steps {
+			fmt.Printf("  %d. %s\n", i+1, step.Label())
+		}
+	}
+	return nil
+}
+
+// mergeReports combines per-baseline reports in baseline name order
+func mergeReports(reports map[string]*report.Report) *report.Report {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := &report.Report{Resources: []report.Resource{}}
+	for _, name := range names {
+		merged.Resources = append(merged.Resources, reports[name].Resources...)
+	}
+	return merged
+}
+
+// inspectSchemas inspects the selected database connections and reports
+// their schema baseline drift. A failed connection does not stop the others.
+func inspectSchemas(ctx context.Context, connections []sql.DatabaseConnection, step pipeline.InspectStep) (*report.Report, error) {
+	scope := step.Scope()
+	r := &report.Report{Resources: []report.Resource{}}
+	var errs []error
+	for i := range connections {
+		conn := &connections[i]
+		if !scope.Matches(conn.Name) {
+			continue
+		}
+		if err := conn.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: invalid connection config: %w", conn.Name, err))
+			continue
+		}
+
+		inspector, err := sql.NewInspectorFromDatabaseConnection(conn)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to create inspector: %w", conn.Name, err))
+			continue
+		}
+		schema, err := inspector.InspectDatabase(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: failed to inspect database: %w", conn.Name, err))
+			continue
+		}
+
+		var result *sql.SchemaValidationResult
+		if conn.SchemaBaseline != nil {
+			result = sql.ValidateSchemaAgainstBaseline(schema, conn.SchemaBaseline)
+		}
+		r.Resources = append(r.Resources, sql.SchemaResource(conn, schema, result))
+	}
+	return r, errors.Join(errs...)
+}
+
+// renderPipelineReport writes the collected report to a file or stdout
+func renderPipelineReport(r *report.Report, step pipeline.RenderStep) error {
+	out := *r
+	if deterministic {
+		out.Resources = make([]report.Resource, len(r.Resources))
+		copy(out.Resources, r.Resources)
+		out.Normalize()
+	}
+
+	output, err := renderGeneric(&out, step.Format, newScanID())
+	if err != nil {
+		return err
+	}
+	if step.Path == "" {
+		fmt.Print(output)
+		return nil
+	}
+
+	if dir := filepath.Dir(step.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(step.Path, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", step.Path, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", step.Path)
+	return nil
+}
+
+// notifyPipelineReport sends the collected report to the named sinks, or to
+// every configured notifier, after replaying the notifications queued by
+// earlier runs. Snoozed findings, read from historyDir, are left out.
+func notifyPipelineReport(ctx context.Context, config *notify.Config, historyDir string, r *report.Report, step pipeline.NotifyStep) error {
+	queue, err := deadLetterQueue(config)
+	if err != nil {
+		return err
+	}
+	if queue != nil {
+		result, err := queue.Flush(ctx, false)
+		if err != nil {
+			return err
+		}
+		if result != (notify.FlushResult{}) {
+			fmt.Fprintf(os.Stderr, "Queued notifications: %d sent, %d pending, %d dropped\n", result.Sent, result.Pending, result.Dropped)
+		}
+	}
+
+	var notifiers []notify.Notifier
+	if len(step.Sinks) == 0 {
+		if notifiers, err = config.Notifiers(); err != nil {
+			return err
+		}
+	} else {
+		snoozer, err := config.Snoozer()
+		if err != nil {
+			return err
+		}
+		router, err := notify.NewRouter(config.Sinks, []notify.RouteConfig{{Sinks: step.Sinks}}, snoozer)
+		if err != nil {
+			return err
+		}
+		queue.Attach(router)
+		notifiers = []notify.Notifier{router}
+	}
+	if len(notifiers) == 0 {
+		return fmt.Errorf("no notifiers configured in daemon.notifications")
+	}
+
+	snoozed, err := snoozedKeys(ctx, config, historyDir, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	notified := notify.Unsuppressed(r, snoozed)
+
+	var errs []error
+	for _, n := range notifiers {
+		if err := n.Notify(ctx, notified); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
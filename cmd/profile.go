@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/configfile"
+	"github.com/jessequinn/drift-analysis-cli/pkg/metacache"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var profileName string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "overlay this named profile from the config file's profiles section, e.g. prod")
+}
+
+// workspaceConfig are the per-environment settings of the config file,
+// usually set in a profile
+type workspaceConfig struct {
+	// Credentials is a service account key file used instead of the
+	// application default credentials, relative to the config file
+	Credentials string `yaml:"credentials"`
+	// CacheDir holds the schema and metadata caches (default .drift-cache)
+	CacheDir string `yaml:"cache_dir"`
+	// ImpersonateServiceAccount runs every GCP call as this service account
+	ImpersonateServiceAccount string `yaml:"impersonate_service_account"`
+	// QuotaProject is billed for API quota instead of the credentials' project
+	QuotaProject string `yaml:"quota_project"`
+}
+
+// workspace is the workspace config of the selected profile
+var workspace workspaceConfig
+
+// loadedConfig is the config file with the selected profile applied, read
+// and parsed once by setupProfile; commands and features decode their
+// sections from it
+var loadedConfig struct {
+	data []byte
+	doc  *yaml.Node
+	err  error
+}
+
+// setupProfile reads the config file and loads the workspace config of the
+// selected profile. Without --profile a missing or invalid config file is
+// left for the command to report.
+func setupProfile(cmd *cobra.Command, args []string) error {
+	loadedConfig.data, loadedConfig.doc, loadedConfig.err = parseConfig(cfgFile, profileName)
+	if loadedConfig.err != nil {
+		if profileName == "" {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", loadedConfig.err)
+	}
+
+	var ws workspaceConfig
+	if err := decodeConfigNode(loadedConfig.doc, &ws); err != nil {
+		if profileName == "" {
+			return nil
+		}
+		return err
+	}
+	if ws.Credentials != "" {
+		if !filepath.IsAbs(ws.Credentials) {
+			ws.Credentials = filepath.Join(filepath.Dir(cfgFile), ws.Credentials)
+		}
+		if _, err := os.Stat(ws.Credentials); err != nil {
+			return fmt.Errorf("invalid credentials: %w", err)
+		}
+	}
+	workspace = ws
+	return nil
+}
+
+// parseConfig reads the config file with the profile applied and parses
+// it; the document is nil for an empty file. An error reading path itself
+// wraps the os error.
+func parseConfig(path, profile string) ([]byte, *yaml.Node, error) {
+	data, err := configfile.ReadProfile(path, profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return data, nil, nil
+	}
+	return data, doc.Content[0], nil
+}
+
+// readConfig returns the config file read by setupProfile. The error wraps
+// the os error, so callers can test for fs.ErrNotExist.
+func readConfig() (*yaml.Node, error) {
+	return loadedConfig.doc, loadedConfig.err
+}
+
+// readConfigData returns the config file read by setupProfile as YAML, for
+// consumers that walk the document themselves
+func readConfigData() ([]byte, error) {
+	if loadedConfig.err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", loadedConfig.err)
+	}
+	return loadedConfig.data, nil
+}
+
+// decodeConfig decodes the sections of the config file that v declares
+func decodeConfig(v any) error {
+	doc, err := readConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return decodeConfigNode(doc, v)
+}
+
+// decodeConfigNode decodes a config file returned by readConfig into v,
+// leaving v unchanged for an empty file
+func decodeConfigNode(doc *yaml.Node, v any) error {
+	if doc == nil {
+		return nil
+	}
+	if err := doc.Decode(v); err != nil {
+		return fmt.Errorf("failed to parse config: %w", err)
+	}
+	return nil
+}
+
+// metadataCacheDir returns where GKE server configs and Cloud SQL flag lists
+// are cached
+func metadataCacheDir() string {
+	if workspace.CacheDir != "" {
+		return filepath.Join(workspace.CacheDir, "metadata")
+	}
+	return metacache.DefaultDir
+}
+
+// schemaCacheDir returns --cache-dir, or the schema cache under the
+// workspace's cache_dir; "" selects the default
+func schemaCacheDir() string {
+	if cacheDir == "" && workspace.CacheDir != "" {
+		return filepath.Join(workspace.CacheDir, "database-schemas")
+	}
+	return cacheDir
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/project"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var (
+	allAccessibleProjects bool
+	includeProjects       []string
+	excludeProjects       []string
+	continueOnError       bool
+)
+
+func init() {
+	gcpCmd.PersistentFlags().BoolVar(&allAccessibleProjects, "all-accessible", false, "analyze every active project the current credentials can access")
+	gcpCmd.PersistentFlags().StringSliceVar(&includeProjects, "include-projects", nil, "with --all-accessible, only analyze projects matching these wildcard patterns")
+	gcpCmd.PersistentFlags().StringSliceVar(&excludeProjects, "exclude-projects", nil, "with --all-accessible, skip projects matching these wildcard patterns, e.g. '*-sandbox'")
+	gcpCmd.PersistentFlags().BoolVar(&continueOnError, "continue-on-error", false, "skip projects that cannot be scanned, listing them in the report's Errors section")
+}
+
+// resolveProjects returns the projects to analyze. Configured projects win;
+// otherwise --all-accessible enumerates visible projects, and as a last resort
+// the active gcloud/ADC project is used with a notice on stderr.
+func resolveProjects(ctx context.Context, configured []string) ([]string, error) {
+	if allAccessibleProjects {
+		filter, err := discoveryFilter()
+		if err != nil {
+			return nil, err
+		}
+		accessible, err := project.ListAccessible(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(accessible) == 0 {
+			return nil, fmt.Errorf("no accessible projects found for the current credentials")
+		}
+		projects := filter.Apply(accessible)
+		if len(projects) == 0 {
+			return nil, fmt.Errorf("all %d accessible project(s) were filtered out by project_discovery", len(accessible))
+		}
+		if skipped := len(accessible) - len(projects); skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Note: analyzing %d accessible project(s), %d skipped by project_discovery\n", len(projects), skipped)
+		} else {
+			fmt.Fprintf(os.Stderr, "Note: analyzing %d accessible project(s)\n", len(projects))
+		}
+		return projects, nil
+	}
+
+	if len(configured) > 0 {
+		return configured, nil
+	}
+
+	defaultProject, source, err := project.DefaultProject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "Note: no projects configured, using %q from %s\n", defaultProject, source)
+	return []string{defaultProject}, nil
+}
+
+// discoveryFilter combines the project_discovery section of the config file
+// with the --include-projects and --exclude-projects flags
+func discoveryFilter() (project.Filter, error) {
+	var config struct {
+		ProjectDiscovery project.Filter `yaml:"project_discovery"`
+	}
+	if doc, err := readConfig(); err == nil {
+		if err := decodeConfigNode(doc, &config); err != nil {
+			return project.Filter{}, err
+		}
+	}
+
+	filter := config.ProjectDiscovery
+	filter.Include = append(filter.Include, includeProjects...)
+	filter.Exclude = append(filter.Exclude, excludeProjects...)
+	if err := filter.Validate(); err != nil {
+		return project.Filter{}, fmt.Errorf("project_discovery: %w", err)
+	}
+	return filter, nil
+}
+
+// projectSkipper is implemented by analyzers that can continue past projects
+// they fail to scan
+type projectSkipper interface {
+	SetContinueOnError(enabled bool)
+	ScanErrors() []report.ScanError
+}
+
+// applyContinueOnError configures an analyzer for --continue-on-error. The
+// returned function warns on stderr about skipped projects.
+func applyContinueOnError(a projectSkipper) func() {
+	a.SetContinueOnError(continueOnError)
+	return func() {
+		if skipped := len(a.ScanErrors()); skipped > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d project(s) could not be scanned and are listed under Errors in the report\n", skipped)
+		}
+	}
+}
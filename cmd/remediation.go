@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var showRemediation bool
+
+func init() {
+	gcpCmd.PersistentFlags().BoolVar(&showRemediation, "show-remediation", false, "add the fix of each drift to the report: a gcloud command with the values filled in and the Terraform attribute change")
+}
+
+// annotateRemediation fills in the remediation of every drift with --show-remediation
+func annotateRemediation(r *report.Report) {
+	if showRemediation {
+		checks.AnnotateRemediation(r)
+	}
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+)
+
+var maxRetries int
+
+func init() {
+	gcpCmd.PersistentFlags().IntVar(&maxRetries, "max-retries", retry.DefaultPolicy().MaxRetries, "retries per Cloud SQL or GKE API call on rate limits, quota and server errors, with exponential backoff")
+}
+
+// retryPolicy returns the API retry policy for --max-retries
+func retryPolicy() retry.Policy {
+	policy := retry.DefaultPolicy()
+	policy.MaxRetries = max(maxRetries, 0)
+	return policy
+}
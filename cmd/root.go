@@ -16,12 +16,17 @@ var rootCmd = &cobra.Command{
 	Long: `Drift Analysis CLI is a comprehensive tool for detecting configuration drift
 in cloud infrastructure resources. It supports multiple cloud providers and resource types,
 comparing actual resource configurations against defined baselines.`,
-	Version: "1.0.0",
+	Version:           "1.0.0",
+	PersistentPreRunE: setup,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if goldenErr := finishGolden(err == nil); err == nil {
+		err = goldenErr
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -30,3 +35,15 @@ func Execute() {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "config.yaml", "config file path")
 }
+
+// setup applies the selected profile and the output flags before any
+// command runs
+func setup(cmd *cobra.Command, args []string) error {
+	if err := setupProfile(cmd, args); err != nil {
+		return err
+	}
+	if err := setupCredentials(cmd, args); err != nil {
+		return err
+	}
+	return setupOutput(cmd, args)
+}
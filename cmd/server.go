@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/drift"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serverAddr        string
+	serverToken       string
+	serverAnalyses    []string
+	serverScanOnStart bool
+)
+
+// serverCmd represents the server command
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Serve drift analyses and their latest reports over HTTP",
+	Long: `Run an HTTP server that triggers the Cloud SQL and GKE analyses on request
+and keeps their latest reports in memory, so a portal can show drift without
+running the CLI. Every endpoint returns JSON:
+
+  GET  /healthz
+  GET  /api/v1/analyses                 state of the last run of each analysis
+  POST /api/v1/analyses/{sql|gke}       start an analysis (409 while it runs)
+  GET  /api/v1/analyses/{sql|gke}       state of the last run
+  GET  /api/v1/reports/{sql|gke}        latest report
+  GET  /api/v1/resources                resources of the latest reports, filtered
+                                        by ?type=, project=, name=, severity= and drifted=true
+  GET  /api/v1/resources/{project}/{name}
+
+Analyses run with the operator's credentials, so the server listens on
+127.0.0.1 by default. With a token (DRIFT_SERVER_TOKEN or --token), every
+endpoint but /healthz requires "Authorization: Bearer <token>"; a non-loopback
+address is refused without one. SIGINT/SIGTERM stop the server after the runs
+in progress are cancelled.
+
+Examples:
+  drift-analysis-cli server --addr 127.0.0.1:8080
+  DRIFT_SERVER_TOKEN=$(cat token) drift-analysis-cli server --addr :8080
+  drift-analysis-cli server --analyses sql --scan-on-start=false`,
+	RunE: runServer,
+}
+
+func init() {
+	rootCmd.AddCommand(serverCmd)
+	serverCmd.Flags().StringVar(&serverAddr, "addr", "127.0.0.1:8080", "address to listen on; other than loopback requires a token")
+	serverCmd.Flags().StringVar(&serverToken, "token", "", "bearer token required by the API (default: $DRIFT_SERVER_TOKEN)")
+	serverCmd.Flags().StringSliceVar(&serverAnalyses, "analyses", nil, "analyses to serve (sql|gke, comma-separated; default: those with baselines in the config)")
+	serverCmd.Flags().BoolVar(&serverScanOnStart, "scan-on-start", true, "run every analysis once at startup")
+}
+
+func runServer(cmd *cobra.Command, args []string) error {
+	token := serverToken
+	if token == "" {
+		token = os.Getenv("DRIFT_SERVER_TOKEN")
+	}
+	if token == "" && !server.IsLoopback(serverAddr) {
+		return fmt.Errorf("refusing to listen on %s without authentication: set DRIFT_SERVER_TOKEN or --token, or listen on a loopback address", serverAddr)
+	}
+
+	cfg, err := drift.LoadConfig(cfgFile, profileName)
+	if err != nil {
+		return err
+	}
+	linker, err := loadLinker()
+	if err != nil {
+		return err
+	}
+
+	kinds := serverAnalyses
+	if len(kinds) == 0 {
+		if len(cfg.SQLBaselines) > 0 {
+			kinds = append(kinds, "sql")
+		}
+		if len(cfg.GKEBaselines) > 0 {
+			kinds = append(kinds, "gke")
+		}
+	}
+	if len(kinds) == 0 {
+		return fmt.Errorf("no SQL or GKE baselines defined in config")
+	}
+	for _, baseline := range cfg.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	for _, baseline := range cfg.GKEBaselines {
+		if err := baseline.Validate(); err != nil {
+			return fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+
+	annotate := func(r *report.Report, err error) (*report.Report, error) {
+		if err != nil {
+			return nil, err
+		}
+		if err := linker.Annotate(r); err != nil {
+			return nil, err
+		}
+		annotateRemediation(r)
+		return r, nil
+	}
+	// Every run resolves the projects again, so new projects are picked up
+	retry := retryPolicy()
+	analyses := make(map[string]server.Analysis)
+	for _, kind := range kinds {
+		switch kind {
+		case "sql":
+			analyses[kind] = func(ctx context.Context) (*report.Report, error) {
+				projects, err := resolveProjects(ctx, cfg.Projects)
+				if err != nil {
+					return nil, err
+				}
+				opts := cfg.SQLOptions()
+				opts.Projects = projects
+				opts.ContinueOnError = continueOnError
+				opts.Retry = &retry
+				return annotate(drift.RunSQLAnalysis(ctx, opts))
+			}
+		case "gke":
+			analyses[kind] = func(ctx context.Context) (*report.Report, error) {
+				projects, err := resolveProjects(ctx, cfg.Projects)
+				if err != nil {
+					return nil, err
+				}
+				opts := cfg.GKEOptions()
+				opts.Projects = projects
+				opts.ContinueOnError = continueOnError
+				opts.Retry = &retry
+				return annotate(drift.RunGKEAnalysis(ctx, opts))
+			}
+		default:
+			return fmt.Errorf("unsupported analysis %q (sql|gke)", kind)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger := log.New(os.Stderr, "server: ", log.LstdFlags)
+	srv := server.New(ctx, analyses, logger.Printf)
+	srv.SetToken(token)
+	if serverScanOnStart {
+		for _, kind := range srv.Kinds() {
+			if _, err := srv.Trigger(kind); err != nil {
+				return err
+			}
+		}
+	}
+
+	httpServer := &http.Server{
+		Addr:              serverAddr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- httpServer.ListenAndServe()
+	}()
+	logger.Printf("listening on %s; serving %v", serverAddr, srv.Kinds())
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	srv.Wait()
+	logger.Printf("stopped")
+	return nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// loadSLOPolicy reads the slo section of the config file; nil disables SLO
+// reporting
+func loadSLOPolicy() (*history.SLOPolicy, error) {
+	var config struct {
+		SLO *history.SLOPolicy `yaml:"slo"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	if err := config.SLO.Validate(); err != nil {
+		return nil, err
+	}
+	return config.SLO, nil
+}
+
+// measureSLO returns the SLO attainment of the reports recorded under name
+// followed by the current report. Nothing is measured without a history
+// store or SLO policy.
+func measureSLO(ctx context.Context, store history.ReportStore, policy *history.SLOPolicy, name string, current *report.Report) ([]report.SLOAttainment, error) {
+	if store == nil || policy == nil {
+		return nil, nil
+	}
+	past, err := store.LoadReports(ctx, unsafeFileChars.ReplaceAllString(name, "_"))
+	if err != nil {
+		return nil, err
+	}
+	return policy.Attainment([][]*report.Report{append(past, current)}, time.Now()), nil
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+var (
+	splitBy  string
+	splitDir string
+)
+
+// formatExtensions maps output formats to file extensions for split reports
+var formatExtensions = map[string]string{
+	"text":               "txt",
+	"json":               "json",
+	"yaml":               "yaml",
+	"ndjson":             "ndjson",
+	"dot":                "dot",
+	"mermaid":            "mmd",
+	"sarif":              "sarif",
+	"gitlab-codequality": "codequality.json",
+	"junit":              "xml",
+	"plan":               "plan.yaml",
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func init() {
+	gcpCmd.PersistentFlags().StringVar(&splitBy, "split-by", "", "write one report per label value, e.g. label:team")
+	gcpCmd.PersistentFlags().StringVar(&splitDir, "split-dir", ".", "directory for reports written with --split-by")
+}
+
+// splitLabelKey parses the --split-by value and returns the label key
+func splitLabelKey(value string) (string, error) {
+	kind, key, ok := strings.Cut(value, ":")
+	if !ok || kind != "label" || key == "" {
+		return "", fmt.Errorf("invalid --split-by %q: expected label:<key>", value)
+	}
+	return key, nil
+}
+
+// writeSplitReports partitions a report by the --split-by label and writes one
+// file per partition into --split-dir. name identifies the analyzer and baseline.
+func writeSplitReports(r *report.Report, format, scanID, name string) error {
+	key, err := splitLabelKey(splitBy)
+	if err != nil {
+		return err
+	}
+
+	if format == "tui" {
+		return fmt.Errorf("--split-by cannot be combined with the tui output format")
+	}
+	ext, ok := formatExtensions[format]
+	if !ok {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if err := os.MkdirAll(splitDir, 0755); err != nil {
+		return fmt.Errorf("failed to create split directory: %w", err)
+	}
+
+	parts := r.SplitByLabel(key)
+	values := make([]string, 0, len(parts))
+	for value := range parts {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		part := parts[value]
+		output, err := renderGeneric(part, format, scanID)
+		if err != nil {
+			return err
+		}
+
+		fileName := unsafeFileChars.ReplaceAllString(fmt.Sprintf("%s-%s-%s", name, key, value), "_") + "." + ext
+		path := filepath.Join(splitDir, fileName)
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Wrote %s (%d resources, %d with drift)\n", path, len(part.Resources), part.DriftedCount())
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// loadStalenessPolicy reads the baseline_staleness section of the config
+// file; nil disables staleness warnings
+func loadStalenessPolicy() (*analyzer.StalenessPolicy, error) {
+	var config struct {
+		BaselineStaleness *analyzer.StalenessPolicy `yaml:"baseline_staleness"`
+	}
+	if err := decodeConfig(&config); err != nil {
+		return nil, err
+	}
+	if err := config.BaselineStaleness.Validate(); err != nil {
+		return nil, err
+	}
+	return config.BaselineStaleness, nil
+}
+
+// baselineWarnings checks a baseline against the staleness policy
+func baselineWarnings(policy *analyzer.StalenessPolicy, name string, meta analyzer.BaselineMetadata, versions, tiers []string) []string {
+	return policy.Check(name, meta, time.Now(), versions, tiers)
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tuiOutput bool
+	tuiWatch  bool
+)
+
+func init() {
+	for _, cmd := range []*cobra.Command{sqlCmd, gkeCmd} {
+		cmd.Flags().BoolVar(&tuiOutput, "tui", false, "open the report in the interactive terminal UI (same as -o tui)")
+		cmd.Flags().BoolVar(&tuiWatch, "watch", false, "with --tui, keep the analyzers ready and re-run them with r, e.g. during a remediation session")
+	}
+}
+
+// resolveTUI applies --tui to the output format and rejects --watch outside
+// the interactive view
+func resolveTUI(cmd *cobra.Command, format *string) error {
+	if tuiOutput {
+		if cmd.Flags().Changed("output") && *format != "tui" {
+			return fmt.Errorf("--tui cannot be combined with -o %s", *format)
+		}
+		*format = "tui"
+	}
+	if tuiWatch && *format != "tui" {
+		return fmt.Errorf("--watch requires --tui or -o tui")
+	}
+	return nil
+}
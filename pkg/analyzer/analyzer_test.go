@@ -2,7 +2,11 @@ package analyzer
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 )
 
 // MockAnalyzer implements ResourceAnalyzer for testing
@@ -79,3 +83,114 @@ func TestBaseline_Interface(t *testing.T) {
 		t.Errorf("Validate() failed: %v", err)
 	}
 }
+
+func TestNameScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope NameScope
+		input string
+		want  bool
+	}{
+		{"unscoped", NameScope{}, "db-1", true},
+		{"listed name", NameScope{Names: []string{"db-1", "db-2"}}, "db-2", true},
+		{"unlisted name", NameScope{Names: []string{"db-1"}}, "db-2", false},
+		{"pattern", NameScope{Pattern: "^prod-.*"}, "prod-orders", true},
+		{"pattern mismatch", NameScope{Pattern: "^prod-.*"}, "staging-prod-orders", false},
+		{"name or pattern", NameScope{Names: []string{"legacy"}, Pattern: "^prod-"}, "legacy", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scope.Matches(tt.input); got != tt.want {
+				t.Errorf("Matches(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if err := (NameScope{Pattern: "(prod"}).Validate(); err == nil {
+		t.Error("Validate() accepted an invalid pattern")
+	}
+}
+
+func TestProjectErrors(t *testing.T) {
+	denied := errors.New("googleapi: Error 403: permission denied")
+
+	var stop ProjectErrors
+	if stop.Skip("p1", denied) {
+		t.Error("Skip() without continue-on-error should stop the scan")
+	}
+
+	var p ProjectErrors
+	p.SetContinueOnError(true)
+	if !p.Skip("p1", denied) {
+		t.Error("Skip() with continue-on-error should skip the project")
+	}
+	p.Skip("p1", denied) // repeated discovery for another baseline
+	if p.Skip("p2", fmt.Errorf("listing: %w", context.Canceled)) {
+		t.Error("Skip() should not skip a cancelled scan")
+	}
+
+	errs := p.ScanErrors()
+	if len(errs) != 1 || errs[0].Project != "p1" || errs[0].Error != denied.Error() {
+		t.Errorf("ScanErrors() = %+v, want one error for p1", errs)
+	}
+}
+
+func TestStalenessPolicy(t *testing.T) {
+	policy := &StalenessPolicy{
+		MaxAgeDays:         90,
+		DeprecatedVersions: []string{"POSTGRES_1[0-2]"},
+		DeprecatedTiers:    []string{"db-n1-*"},
+	}
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		meta     BaselineMetadata
+		versions []string
+		tiers    []string
+		want     []string
+	}{
+		{
+			name:     "current",
+			meta:     BaselineMetadata{UpdatedAt: "2026-05-01"},
+			versions: []string{"POSTGRES_15"},
+			tiers:    []string{"db-custom-4-16384"},
+		},
+		{
+			name: "too old",
+			meta: BaselineMetadata{UpdatedAt: "2025-12-01", Owner: "platform-team"},
+			want: []string{`baseline "app" was last updated 2025-12-01, more than 90 days ago; ask platform-team to review it`},
+		},
+		{
+			name: "undated",
+			want: []string{`baseline "app" has no metadata.updated_at date`},
+		},
+		{
+			name:     "deprecated version and tier",
+			meta:     BaselineMetadata{UpdatedAt: "2026-05-01"},
+			versions: []string{"POSTGRES_12", "POSTGRES_12", ""},
+			tiers:    []string{"db-n1-standard-2"},
+			want: []string{
+				`baseline "app" expects deprecated version POSTGRES_12`,
+				`baseline "app" expects deprecated tier db-n1-standard-2`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Check("app", tt.meta, now, tt.versions, tt.tiers)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var none *StalenessPolicy
+	if got := none.Check("app", BaselineMetadata{}, now, []string{"POSTGRES_12"}, nil); got != nil {
+		t.Errorf("nil policy Check() = %q, want none", got)
+	}
+	if err := (BaselineMetadata{UpdatedAt: "01/02/2026"}).Validate(); err == nil {
+		t.Error("Validate() accepted a malformed date")
+	}
+}
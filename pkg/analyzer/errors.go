@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// ProjectErrors lets an analyzer continue past projects it cannot scan, such
+// as projects the credentials lack permissions on. Analyzers embed it; the
+// zero value stops at the first failed project.
+type ProjectErrors struct {
+	continueOnError bool
+	errors          []report.ScanError
+}
+
+// SetContinueOnError records per-project discovery errors instead of failing
+// the whole scan
+func (p *ProjectErrors) SetContinueOnError(enabled bool) {
+	p.continueOnError = enabled
+}
+
+// Skip reports whether a project whose discovery failed should be skipped,
+// recording its error. Cancellation is never skipped.
+func (p *ProjectErrors) Skip(project string, err error) bool {
+	if !p.continueOnError || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// Discovery runs once per baseline, so the same failure repeats
+	scanErr := report.ScanError{Project: project, Error: err.Error()}
+	for _, recorded := range p.errors {
+		if recorded == scanErr {
+			return true
+		}
+	}
+	p.errors = append(p.errors, scanErr)
+	return true
+}
+
+// ScanErrors returns the errors of skipped projects
+func (p *ProjectErrors) ScanErrors() []report.ScanError {
+	return append([]report.ScanError(nil), p.errors...)
+}
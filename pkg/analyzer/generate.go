@@ -0,0 +1,136 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ResourceGroup is a set of resources a generated config covers with one
+// baseline
+type ResourceGroup struct {
+	// Value of the grouping label; empty when all resources form one group
+	Value string
+	// Members are indices into the grouped resources, sorted by project and
+	// name; the first one is the template of the baseline
+	Members []int
+	// CommonLabels are the labels every member carries with the same value
+	CommonLabels map[string]string
+}
+
+// Describe summarizes the group for a comment above its generated baseline
+func (g ResourceGroup) Describe(resources []UniqueResource, kind string) string {
+	first := resources[g.Members[0]]
+	return fmt.Sprintf("Generated from %s/%s; covers %d %s", first.Project, first.Name, len(g.Members), plural(len(g.Members), kind))
+}
+
+// GroupByLabel groups resources by the value of a label, sorted by value, for
+// generating one baseline per group. An empty label puts every resource in
+// one group. Resources without the label are returned separately.
+func GroupByLabel(resources []UniqueResource, label string) (groups []ResourceGroup, unlabeled []int) {
+	order := make([]int, len(resources))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := resources[order[a]], resources[order[b]]
+		if ra.Project != rb.Project {
+			return ra.Project < rb.Project
+		}
+		return ra.Name < rb.Name
+	})
+
+	index := make(map[string]int)
+	for _, i := range order {
+		value, ok := "", true
+		if label != "" {
+			value, ok = resources[i].Labels[label]
+		}
+		if !ok {
+			unlabeled = append(unlabeled, i)
+			continue
+		}
+		g, found := index[value]
+		if !found {
+			g = len(groups)
+			index[value] = g
+			groups = append(groups, ResourceGroup{Value: value})
+		}
+		groups[g].Members = append(groups[g].Members, i)
+	}
+
+	sort.Slice(groups, func(a, b int) bool { return groups[a].Value < groups[b].Value })
+	for g := range groups {
+		groups[g].CommonLabels = commonLabels(resources, groups[g].Members)
+	}
+	return groups, unlabeled
+}
+
+// commonLabels returns the labels all members share with the same value
+func commonLabels(resources []UniqueResource, members []int) map[string]string {
+	common := make(map[string]string)
+	for key, value := range resources[members[0]].Labels {
+		common[key] = value
+	}
+	for _, i := range members[1:] {
+		for key, value := range common {
+			if resources[i].Labels[key] != value {
+				delete(common, key)
+			}
+		}
+	}
+	if len(common) == 0 {
+		return nil
+	}
+	return common
+}
+
+// MarshalGenerated renders a generated config with a comment above each item
+// of the list under listKey and an optional header comment
+func MarshalGenerated(config interface{}, listKey, header string, comments []string) (string, error) {
+	var root yaml.Node
+	if err := root.Encode(config); err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	root.HeadComment = header
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != listKey {
+			continue
+		}
+		for j, item := range root.Content[i+1].Content {
+			if j < len(comments) {
+				item.HeadComment = comments[j]
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// UnlabeledComment lists resources a config grouped by label does not cover
+func UnlabeledComment(resources []UniqueResource, unlabeled []int, label, kind string) string {
+	if len(unlabeled) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(unlabeled))
+	for _, i := range unlabeled {
+		names = append(names, resources[i].Project+"/"+resources[i].Name)
+	}
+	return fmt.Sprintf("%d %s without label %s not covered: %s", len(unlabeled), plural(len(unlabeled), kind), label, strings.Join(names, ", "))
+}
+
+func plural(n int, kind string) string {
+	if n == 1 {
+		return kind
+	}
+	return kind + "s"
+}
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupByLabel(t *testing.T) {
+	resources := []UniqueResource{
+		{Project: "shop", Name: "orders-2", Labels: map[string]string{"role": "oltp", "team": "orders"}},
+		{Project: "shop", Name: "reports", Labels: map[string]string{"role": "analytics"}},
+		{Project: "shop", Name: "orders-1", Labels: map[string]string{"role": "oltp", "team": "orders", "tier": "gold"}},
+		{Project: "shop", Name: "scratch"},
+	}
+
+	groups, unlabeled := GroupByLabel(resources, "role")
+	if len(groups) != 2 || groups[0].Value != "analytics" || groups[1].Value != "oltp" {
+		t.Fatalf("groups = %+v, want analytics and oltp", groups)
+	}
+	oltp := groups[1]
+	if !reflect.DeepEqual(oltp.Members, []int{2, 0}) {
+		t.Errorf("oltp members = %v, want sorted by name", oltp.Members)
+	}
+	if want := map[string]string{"role": "oltp", "team": "orders"}; !reflect.DeepEqual(oltp.CommonLabels, want) {
+		t.Errorf("oltp common labels = %v, want %v", oltp.CommonLabels, want)
+	}
+	if got := oltp.Describe(resources, "instance"); got != "Generated from shop/orders-1; covers 2 instances" {
+		t.Errorf("Describe() = %q", got)
+	}
+	if !reflect.DeepEqual(unlabeled, []int{3}) {
+		t.Errorf("unlabeled = %v, want scratch", unlabeled)
+	}
+	if got := UnlabeledComment(resources, unlabeled, "role", "instance"); !strings.Contains(got, "1 instance without label role not covered: shop/scratch") {
+		t.Errorf("UnlabeledComment() = %q", got)
+	}
+
+	groups, unlabeled = GroupByLabel(resources, "")
+	if len(groups) != 1 || len(groups[0].Members) != 4 || unlabeled != nil {
+		t.Errorf("ungrouped = %+v, %v, want one group of every resource", groups, unlabeled)
+	}
+}
@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// LabelPolicy sets the labels every analyzed resource must carry, e.g. the
+// team and cost center labels used for cost allocation. It applies to every
+// resource, whichever baseline matched it.
+type LabelPolicy struct {
+	// Required are label keys every resource must set
+	Required []string `yaml:"required,omitempty"`
+	// AllowedValues lists the acceptable values per label key
+	AllowedValues map[string][]string `yaml:"allowed_values,omitempty"`
+	// Patterns are regular expressions label values must match per key
+	Patterns map[string]string `yaml:"patterns,omitempty"`
+	// Severity of label findings; defaults to the severity of the check
+	Severity string `yaml:"severity,omitempty"`
+
+	compiled map[string]*regexp.Regexp
+}
+
+// LabelViolation is a label that is missing or has a value the policy does
+// not accept
+type LabelViolation struct {
+	Key string
+	// Expected describes the accepted values
+	Expected string
+	// Actual is the label value, or "not set"
+	Actual string
+}
+
+// Validate checks the severity and compiles the patterns
+func (p *LabelPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Severity != "" && report.SeverityRank(p.Severity) == 0 {
+		return fmt.Errorf("label_policy: unknown severity %q (critical, high, medium or low)", p.Severity)
+	}
+	for _, key := range p.Required {
+		if key == "" {
+			return fmt.Errorf("label_policy.required: empty label key")
+		}
+	}
+	for key, values := range p.AllowedValues {
+		if len(values) == 0 {
+			return fmt.Errorf("label_policy.allowed_values.%s: at least one value is required", key)
+		}
+	}
+	p.compiled = make(map[string]*regexp.Regexp, len(p.Patterns))
+	for key, pattern := range p.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("label_policy.patterns.%s: %w", key, err)
+		}
+		p.compiled[key] = re
+	}
+	return nil
+}
+
+// Violations checks the labels of a resource: required keys first in policy
+// order, then the values of the remaining constrained keys by key. Labels
+// that are not required are only checked when set.
+func (p *LabelPolicy) Violations(labels map[string]string) []LabelViolation {
+	if p == nil {
+		return nil
+	}
+
+	var violations []LabelViolation
+	checked := make(map[string]bool)
+	check := func(key string) {
+		if checked[key] {
+			return
+		}
+		checked[key] = true
+		value, ok := labels[key]
+		if !ok {
+			violations = append(violations, LabelViolation{Key: key, Expected: p.describe(key), Actual: "not set"})
+			return
+		}
+		if !p.accepts(key, value) {
+			violations = append(violations, LabelViolation{Key: key, Expected: p.describe(key), Actual: value})
+		}
+	}
+
+	for _, key := range p.Required {
+		check(key)
+	}
+	var constrained []string
+	for key := range p.AllowedValues {
+		constrained = append(constrained, key)
+	}
+	for key := range p.Patterns {
+		if _, ok := p.AllowedValues[key]; !ok {
+			constrained = append(constrained, key)
+		}
+	}
+	sort.Strings(constrained)
+	for _, key := range constrained {
+		if _, ok := labels[key]; ok {
+			check(key)
+		}
+	}
+	return violations
+}
+
+// accepts reports whether a label value is allowed and matches the pattern
+// of its key
+func (p *LabelPolicy) accepts(key, value string) bool {
+	if values, ok := p.AllowedValues[key]; ok {
+		allowed := false
+		for _, v := range values {
+			if v == value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if pattern, ok := p.Patterns[key]; ok {
+		re := p.compiled[key]
+		if re == nil {
+			re = regexp.MustCompile(pattern)
+		}
+		if !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe renders the values accepted for a key for the Expected column
+func (p *LabelPolicy) describe(key string) string {
+	var parts []string
+	if values, ok := p.AllowedValues[key]; ok {
+		parts = append(parts, fmt.Sprintf("one of %v", values))
+	}
+	if pattern, ok := p.Patterns[key]; ok {
+		parts = append(parts, "matching "+pattern)
+	}
+	if len(parts) == 0 {
+		return "set"
+	}
+	return strings.Join(parts, " and ")
+}
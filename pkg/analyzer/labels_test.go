@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLabelPolicyViolations(t *testing.T) {
+	policy := &LabelPolicy{
+		Required:      []string{"team", "cost-center"},
+		AllowedValues: map[string][]string{"env": {"prod", "dev"}},
+		Patterns:      map[string]string{"cost-center": `^cc-[0-9]{4}$`},
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := policy.Violations(map[string]string{"cost-center": "marketing", "env": "staging", "owner": "x"})
+	want := []LabelViolation{
+		{Key: "team", Expected: "set", Actual: "not set"},
+		{Key: "cost-center", Expected: "matching ^cc-[0-9]{4}$", Actual: "marketing"},
+		{Key: "env", Expected: "one of [prod dev]", Actual: "staging"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Violations() = %+v, want %+v", got, want)
+	}
+
+	// Constrained labels that are not required may be left unset
+	if got := policy.Violations(map[string]string{"team": "data", "cost-center": "cc-0042"}); len(got) != 0 {
+		t.Errorf("Violations() = %+v, want none", got)
+	}
+	var none *LabelPolicy
+	if got := none.Violations(nil); got != nil {
+		t.Errorf("nil policy Violations() = %+v", got)
+	}
+}
+
+func TestLabelPolicyValidate(t *testing.T) {
+	tests := []struct {
+		policy  LabelPolicy
+		wantErr bool
+	}{
+		{policy: LabelPolicy{Required: []string{"team"}, Severity: "high"}},
+		{policy: LabelPolicy{Required: []string{""}}, wantErr: true},
+		{policy: LabelPolicy{Severity: "urgent"}, wantErr: true},
+		{policy: LabelPolicy{AllowedValues: map[string][]string{"env": nil}}, wantErr: true},
+		{policy: LabelPolicy{Patterns: map[string]string{"team": "("}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+		}
+	}
+}
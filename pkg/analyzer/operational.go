@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// IgnoreState is the severity that leaves a resource state unreported
+const IgnoreState = "ignore"
+
+// OperationalSeverities maps resource states, e.g. FAILED, to the severity of
+// the operational finding they are reported as
+type OperationalSeverities map[string]string
+
+// Severity returns the severity of a state, falling back to defaults. States
+// mapped to neither, or to ignore, are not reported.
+func (s OperationalSeverities) Severity(state string, defaults OperationalSeverities) (string, bool) {
+	severity, ok := s[state]
+	if !ok {
+		severity, ok = defaults[state]
+	}
+	if !ok || severity == IgnoreState {
+		return "", false
+	}
+	return severity, true
+}
+
+// OperationalPolicy overrides the severities of unhealthy resource states,
+// reported as operational findings apart from configuration drift
+type OperationalPolicy struct {
+	// SQL maps Cloud SQL instance states such as FAILED or SUSPENDED
+	SQL OperationalSeverities `yaml:"sql,omitempty"`
+	// GKE maps GKE cluster statuses such as DEGRADED or ERROR
+	GKE OperationalSeverities `yaml:"gke,omitempty"`
+}
+
+// Validate checks every severity
+func (p *OperationalPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for section, severities := range map[string]OperationalSeverities{"sql": p.SQL, "gke": p.GKE} {
+		for state, severity := range severities {
+			if severity != IgnoreState && report.SeverityRank(severity) == 0 {
+				return fmt.Errorf("operational.%s.%s: unknown severity %q (critical, high, medium, low or ignore)", section, state, severity)
+			}
+		}
+	}
+	return nil
+}
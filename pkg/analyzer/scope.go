@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NameScope restricts a baseline to resources by name, so individual
+// instances or groups like ^prod-.* can have their own baseline
+type NameScope struct {
+	// Names lists resource names the baseline applies to
+	Names []string
+	// Pattern is a regular expression resource names must match
+	Pattern string
+}
+
+// Scoped reports whether any name restriction is set
+func (s NameScope) Scoped() bool {
+	return len(s.Names) > 0 || s.Pattern != ""
+}
+
+// Validate checks that the pattern compiles
+func (s NameScope) Validate() error {
+	if s.Pattern == "" {
+		return nil
+	}
+	if _, err := regexp.Compile(s.Pattern); err != nil {
+		return fmt.Errorf("invalid name_pattern %q: %w", s.Pattern, err)
+	}
+	return nil
+}
+
+// Matches reports whether a resource name is in scope: it is listed in
+// Names or matches Pattern. Without restrictions every name matches.
+func (s NameScope) Matches(name string) bool {
+	if !s.Scoped() {
+		return true
+	}
+	for _, n := range s.Names {
+		if n == name {
+			return true
+		}
+	}
+	if s.Pattern == "" {
+		return false
+	}
+	matched, err := regexp.MatchString(s.Pattern, name)
+	return err == nil && matched
+}
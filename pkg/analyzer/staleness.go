@@ -0,0 +1,107 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// dateLayout is the format of baseline review dates
+const dateLayout = "2006-01-02"
+
+// BaselineMetadata records when a baseline was last reviewed and who owns it
+type BaselineMetadata struct {
+	// UpdatedAt is the date of the last review, as YYYY-MM-DD
+	UpdatedAt string `yaml:"updated_at,omitempty" json:"updated_at,omitempty"`
+	// Owner is asked to review the baseline when it goes stale
+	Owner string `yaml:"owner,omitempty" json:"owner,omitempty"`
+}
+
+// Validate checks the review date format
+func (m BaselineMetadata) Validate() error {
+	if m.UpdatedAt == "" {
+		return nil
+	}
+	if _, err := time.Parse(dateLayout, m.UpdatedAt); err != nil {
+		return fmt.Errorf("invalid metadata.updated_at %q: expected YYYY-MM-DD", m.UpdatedAt)
+	}
+	return nil
+}
+
+// StalenessPolicy flags baselines that are due for review: not updated
+// within MaxAgeDays, or expecting versions or tiers that are deprecated
+type StalenessPolicy struct {
+	MaxAgeDays int `yaml:"max_age_days,omitempty"`
+	// DeprecatedVersions and DeprecatedTiers are shell wildcard patterns,
+	// e.g. POSTGRES_1[0-2] or db-n1-*
+	DeprecatedVersions []string `yaml:"deprecated_versions,omitempty"`
+	DeprecatedTiers    []string `yaml:"deprecated_tiers,omitempty"`
+}
+
+// Validate checks the age and every pattern
+func (p *StalenessPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("baseline_staleness.max_age_days must not be negative")
+	}
+	for _, pattern := range append(append([]string{}, p.DeprecatedVersions...), p.DeprecatedTiers...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid baseline_staleness pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Check returns review warnings for a baseline. versions and tiers are the
+// values the baseline expects; empty values are ignored.
+func (p *StalenessPolicy) Check(name string, meta BaselineMetadata, now time.Time, versions, tiers []string) []string {
+	if p == nil {
+		return nil
+	}
+
+	var warnings []string
+	if p.MaxAgeDays > 0 {
+		review := ""
+		if meta.Owner != "" {
+			review = fmt.Sprintf("; ask %s to review it", meta.Owner)
+		}
+		updated, err := time.Parse(dateLayout, meta.UpdatedAt)
+		switch {
+		case meta.UpdatedAt == "":
+			warnings = append(warnings, fmt.Sprintf("baseline %q has no metadata.updated_at date%s", name, review))
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("baseline %q has an invalid metadata.updated_at date %q%s", name, meta.UpdatedAt, review))
+		case now.Sub(updated) > time.Duration(p.MaxAgeDays)*24*time.Hour:
+			warnings = append(warnings, fmt.Sprintf("baseline %q was last updated %s, more than %d days ago%s",
+				name, meta.UpdatedAt, p.MaxAgeDays, review))
+		}
+	}
+	for _, v := range deprecated(p.DeprecatedVersions, versions) {
+		warnings = append(warnings, fmt.Sprintf("baseline %q expects deprecated version %s", name, v))
+	}
+	for _, t := range deprecated(p.DeprecatedTiers, tiers) {
+		warnings = append(warnings, fmt.Sprintf("baseline %q expects deprecated tier %s", name, t))
+	}
+	return warnings
+}
+
+// deprecated returns the values matching any pattern, once each
+func deprecated(patterns, values []string) []string {
+	var matched []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		for _, pattern := range patterns {
+			if ok, err := path.Match(pattern, value); err == nil && ok {
+				matched = append(matched, value)
+				break
+			}
+		}
+	}
+	return matched
+}
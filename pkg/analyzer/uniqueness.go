@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UniquenessRule limits how many resources of a baseline may share a name or
+// label values across projects, e.g. one vault instance per environment
+type UniquenessRule struct {
+	// By lists what resources are grouped on: name or label:<key>
+	By []string `yaml:"by"`
+	// Max is the most resources a group may hold (default 1)
+	Max int `yaml:"max,omitempty"`
+}
+
+// Validate checks the grouping keys and limit
+func (r UniquenessRule) Validate() error {
+	if len(r.By) == 0 {
+		return fmt.Errorf("unique: by lists nothing to group on")
+	}
+	for _, by := range r.By {
+		if by == "name" {
+			continue
+		}
+		if key, ok := strings.CutPrefix(by, "label:"); !ok || key == "" {
+			return fmt.Errorf("unique: invalid by %q: expected name or label:<key>", by)
+		}
+	}
+	if r.Max < 0 {
+		return fmt.Errorf("unique: max must not be negative")
+	}
+	return nil
+}
+
+// max returns the most resources a group may hold
+func (r UniquenessRule) max() int {
+	if r.Max == 0 {
+		return 1
+	}
+	return r.Max
+}
+
+// Expected describes the limit for a group, as shown in a finding
+func (r UniquenessRule) Expected(group string) string {
+	return fmt.Sprintf("at most %d with %s", r.max(), group)
+}
+
+// UniqueResource is a resource checked against uniqueness rules
+type UniqueResource struct {
+	Project string
+	Name    string
+	Labels  map[string]string
+}
+
+// Duplicate is a group of resources holding more members than a rule allows
+type Duplicate struct {
+	// Group describes the shared values, e.g. name=vault or env=prod
+	Group string
+	// Members are indices into the checked resources
+	Members []int
+}
+
+// Actual describes the group's members, as shown in a finding
+func (d Duplicate) Actual(resources []UniqueResource) string {
+	names := make([]string, 0, len(d.Members))
+	for _, i := range d.Members {
+		names = append(names, resources[i].Project+"/"+resources[i].Name)
+	}
+	return fmt.Sprintf("%d: %s", len(names), strings.Join(names, ", "))
+}
+
+// Duplicates groups the resources by the rule and returns the groups over the
+// limit, sorted by group. Resources missing a grouped label are not counted.
+func (r UniquenessRule) Duplicates(resources []UniqueResource) []Duplicate {
+	var order []string
+	groups := make(map[string][]int)
+	for i, res := range resources {
+		group, ok := r.group(res)
+		if !ok {
+			continue
+		}
+		if _, found := groups[group]; !found {
+			order = append(order, group)
+		}
+		groups[group] = append(groups[group], i)
+	}
+
+	var duplicates []Duplicate
+	for _, group := range order {
+		if members := groups[group]; len(members) > r.max() {
+			duplicates = append(duplicates, Duplicate{Group: group, Members: members})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool { return duplicates[i].Group < duplicates[j].Group })
+	return duplicates
+}
+
+// group returns the values a resource shares with the rest of its group
+func (r UniquenessRule) group(res UniqueResource) (string, bool) {
+	parts := make([]string, 0, len(r.By))
+	for _, by := range r.By {
+		if by == "name" {
+			parts = append(parts, "name="+res.Name)
+			continue
+		}
+		key := strings.TrimPrefix(by, "label:")
+		value, ok := res.Labels[key]
+		if !ok {
+			return "", false
+		}
+		parts = append(parts, key+"="+value)
+	}
+	return strings.Join(parts, ","), true
+}
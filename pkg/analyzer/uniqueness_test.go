@@ -0,0 +1,51 @@
+package analyzer
+
+import "testing"
+
+func TestUniquenessRuleDuplicates(t *testing.T) {
+	resources := []UniqueResource{
+		{Project: "p1", Name: "vault", Labels: map[string]string{"env": "prod"}},
+		{Project: "p2", Name: "vault", Labels: map[string]string{"env": "prod"}},
+		{Project: "p3", Name: "vault-stg", Labels: map[string]string{"env": "staging"}},
+		{Project: "p4", Name: "vault"},
+	}
+
+	// One instance per env; the unlabelled instance is not counted
+	byEnv := UniquenessRule{By: []string{"label:env"}}
+	duplicates := byEnv.Duplicates(resources)
+	if len(duplicates) != 1 || duplicates[0].Group != "env=prod" {
+		t.Fatalf("Duplicates() = %+v, want one env=prod group", duplicates)
+	}
+	if got := duplicates[0].Actual(resources); got != "2: p1/vault, p2/vault" {
+		t.Errorf("Actual() = %q", got)
+	}
+	if got := byEnv.Expected("env=prod"); got != "at most 1 with env=prod" {
+		t.Errorf("Expected() = %q", got)
+	}
+
+	byName := UniquenessRule{By: []string{"name"}, Max: 2}
+	if duplicates := byName.Duplicates(resources); len(duplicates) != 1 || len(duplicates[0].Members) != 3 {
+		t.Errorf("Duplicates() by name = %+v, want the three vault instances", duplicates)
+	}
+	if duplicates := (UniquenessRule{By: []string{"name", "label:env"}}).Duplicates(resources); len(duplicates) != 1 || duplicates[0].Group != "name=vault,env=prod" {
+		t.Errorf("Duplicates() by name and env = %+v", duplicates)
+	}
+}
+
+func TestUniquenessRuleValidate(t *testing.T) {
+	tests := []struct {
+		rule    UniquenessRule
+		wantErr bool
+	}{
+		{rule: UniquenessRule{By: []string{"name", "label:env"}}},
+		{rule: UniquenessRule{}, wantErr: true},
+		{rule: UniquenessRule{By: []string{"label:"}}, wantErr: true},
+		{rule: UniquenessRule{By: []string{"region"}}, wantErr: true},
+		{rule: UniquenessRule{By: []string{"name"}, Max: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// Package apply executes remediation plan steps through per-resource-type
+// executors. Only steps that were explicitly opted in and that an executor
+// whitelists are applied; everything else is skipped.
+package apply
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+)
+
+// Executor applies remediation steps to resources of one type
+type Executor interface {
+	// Supports reports whether the check is whitelisted for auto-remediation
+	Supports(checkID string) bool
+	// Apply applies every step for a single resource as one change
+	Apply(ctx context.Context, steps []checks.Step) error
+}
+
+// StatusSkipped marks steps that were not applied; other results use the
+// history change statuses
+const StatusSkipped = "skipped"
+
+// Result is the outcome of one plan step
+type Result struct {
+	Step       checks.Step
+	Status     string
+	Reason     string
+	ApprovedBy string
+	Err        error
+}
+
+// Approval is a reviewer's decision on a plan step
+type Approval struct {
+	Approved bool
+	By       string
+}
+
+// Options are the guardrails for a run
+type Options struct {
+	// Checks are the opted-in check IDs; steps for other checks are skipped
+	Checks map[string]bool
+	// Approvals are the review decisions keyed by step ID. When set, only
+	// approved steps are applied; nil disables the approval gate.
+	Approvals map[string]Approval
+	// DryRun reports what would be applied without calling any API
+	DryRun bool
+	// Concurrency limits how many resources are changed at once (default: 1)
+	Concurrency int
+}
+
+// Runner applies a plan with the executors registered per resource type
+type Runner struct {
+	Executors map[string]Executor
+	Options   Options
+}
+
+// resourceKey identifies a resource; its steps are applied together
+type resourceKey struct {
+	resourceType, project, resource string
+}
+
+// Run applies the eligible steps of a plan and returns one result per step in
+// plan order. Steps for the same resource are applied in a single change, and
+// at most Options.Concurrency resources are changed concurrently.
+func (r *Runner) Run(ctx context.Context, plan *checks.Plan) []Result {
+	results := make([]Result, len(plan.Steps))
+	groups := make(map[resourceKey][]int)
+	var order []resourceKey
+
+	for i, step := range plan.Steps {
+		results[i] = Result{Step: step, ApprovedBy: r.Options.Approvals[step.ID].By}
+		if reason := r.skipReason(step); reason != "" {
+			results[i].Status = StatusSkipped
+			results[i].Reason = reason
+			continue
+		}
+
+		key := resourceKey{step.ResourceType, step.Project, step.Resource}
+		if _, seen := groups[key]; !seen {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	concurrency := r.Options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for _, key := range order {
+		indexes := groups[key]
+		if r.Options.DryRun {
+			for _, i := range indexes {
+				results[i].Status = history.StatusDryRun
+			}
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key resourceKey, indexes []int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			steps := make([]checks.Step, len(indexes))
+			for j, i := range indexes {
+				steps[j] = plan.Steps[i]
+			}
+			err := r.Executors[key.resourceType].Apply(ctx, steps)
+			for _, i := range indexes {
+				if err != nil {
+					results[i].Status = history.StatusFailed
+					results[i].Err = err
+				} else {
+					results[i].Status = history.StatusApplied
+				}
+			}
+		}(key, indexes)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Eligible returns the steps that would be applied once approved, i.e. every
+// step that passes the guardrails except the approval gate
+func (r *Runner) Eligible(plan *checks.Plan) []checks.Step {
+	var steps []checks.Step
+	for _, step := range plan.Steps {
+		if r.guardrailReason(step) == "" {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// skipReason explains why a step is not applied, or returns "" when it is eligible
+func (r *Runner) skipReason(step checks.Step) string {
+	if reason := r.guardrailReason(step); reason != "" {
+		return reason
+	}
+	if r.Options.Approvals == nil {
+		return ""
+	}
+	approval, ok := r.Options.Approvals[step.ID]
+	switch {
+	case !ok:
+		return "not approved"
+	case !approval.Approved:
+		return fmt.Sprintf("rejected by %s", approval.By)
+	}
+	return ""
+}
+
+// guardrailReason checks the action, whitelist and opt-in of a step
+func (r *Runner) guardrailReason(step checks.Step) string {
+	if step.Action != checks.ActionUpdate {
+		return fmt.Sprintf("%s steps are never applied automatically", step.Action)
+	}
+	executor, ok := r.Executors[step.ResourceType]
+	if !ok || !executor.Supports(step.CheckID) {
+		return "not whitelisted for auto-remediation"
+	}
+	if !r.Options.Checks[step.CheckID] {
+		return "not opted in"
+	}
+	return ""
+}
+
+// Changes converts applied, dry-run and failed results to change log entries
+func Changes(results []Result, actor string, at time.Time) []history.Change {
+	var changes []history.Change
+	for _, result := range results {
+		if result.Status == StatusSkipped {
+			continue
+		}
+		change := history.Change{
+			Time:         at.UTC(),
+			Actor:        actor,
+			CheckID:      result.Step.CheckID,
+			ResourceType: result.Step.ResourceType,
+			Project:      result.Step.Project,
+			Resource:     result.Step.Resource,
+			Field:        result.Step.Field,
+			From:         result.Step.Actual,
+			To:           result.Step.Expected,
+			Status:       result.Status,
+			ApprovedBy:   result.ApprovedBy,
+		}
+		if result.Err != nil {
+			change.Error = result.Err.Error()
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -0,0 +1,146 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+)
+
+type fakeExecutor struct {
+	mu      sync.Mutex
+	calls   map[string]int
+	running int
+	peak    int
+	fail    string
+}
+
+func (f *fakeExecutor) Supports(checkID string) bool {
+	return checkID == "sql.labels" || checkID == "sql.settings.insights_config.query_insights_enabled"
+}
+
+func (f *fakeExecutor) Apply(ctx context.Context, steps []checks.Step) error {
+	f.mu.Lock()
+	f.calls[steps[0].Resource] += len(steps)
+	f.running++
+	if f.running > f.peak {
+		f.peak = f.running
+	}
+	f.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	if steps[0].Resource == f.fail {
+		return errors.New("patch failed")
+	}
+	return nil
+}
+
+func testPlan() *checks.Plan {
+	step := func(resource, checkID, action string) checks.Step {
+		return checks.Step{ID: resource + "/" + checkID, ResourceType: "Cloud SQL", Project: "p", Resource: resource, CheckID: checkID, Action: action, Field: checkID}
+	}
+	return &checks.Plan{Steps: []checks.Step{
+		step("db-1", "sql.labels", checks.ActionUpdate),
+		step("db-1", "sql.settings.insights_config.query_insights_enabled", checks.ActionUpdate),
+		step("db-2", "sql.labels", checks.ActionUpdate),
+		step("db-3", "sql.labels", checks.ActionUpdate),
+		step("db-3", "sql.tier", checks.ActionUpdate),
+		step("db-4", "sql.labels", checks.ActionManual),
+	}}
+}
+
+func TestRun(t *testing.T) {
+	executor := &fakeExecutor{calls: make(map[string]int), fail: "db-3"}
+	runner := &Runner{
+		Executors: map[string]Executor{"Cloud SQL": executor},
+		Options: Options{
+			Checks:      map[string]bool{"sql.labels": true, "sql.settings.insights_config.query_insights_enabled": true, "sql.tier": true},
+			Concurrency: 2,
+		},
+	}
+
+	results := runner.Run(context.Background(), testPlan())
+	want := []string{history.StatusApplied, history.StatusApplied, history.StatusApplied, history.StatusFailed, StatusSkipped, StatusSkipped}
+	for i, result := range results {
+		if result.Status != want[i] {
+			t.Errorf("results[%d] (%s %s) = %s, want %s", i, result.Step.Resource, result.Step.CheckID, result.Status, want[i])
+		}
+	}
+
+	// Both db-1 steps go out as one change
+	if executor.calls["db-1"] != 2 {
+		t.Errorf("db-1 applied %d steps, want 2 in one call", executor.calls["db-1"])
+	}
+	if executor.peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", executor.peak)
+	}
+
+	changes := Changes(results, "alice", time.Now())
+	if len(changes) != 4 || changes[3].Error != "patch failed" || changes[0].Actor != "alice" {
+		t.Errorf("Changes() = %+v", changes)
+	}
+}
+
+func TestRunDryRunAndOptIn(t *testing.T) {
+	executor := &fakeExecutor{calls: make(map[string]int)}
+	runner := &Runner{
+		Executors: map[string]Executor{"Cloud SQL": executor},
+		Options:   Options{Checks: map[string]bool{"sql.labels": true}, DryRun: true},
+	}
+
+	results := runner.Run(context.Background(), testPlan())
+	if len(executor.calls) != 0 {
+		t.Errorf("dry run called the executor: %v", executor.calls)
+	}
+	if results[0].Status != history.StatusDryRun {
+		t.Errorf("results[0] = %s, want dry_run", results[0].Status)
+	}
+	if results[1].Status != StatusSkipped || results[1].Reason != "not opted in" {
+		t.Errorf("results[1] = %s (%s), want skipped: not opted in", results[1].Status, results[1].Reason)
+	}
+}
+
+func TestRunApprovals(t *testing.T) {
+	executor := &fakeExecutor{calls: make(map[string]int)}
+	runner := &Runner{
+		Executors: map[string]Executor{"Cloud SQL": executor},
+		Options:   Options{Checks: map[string]bool{"sql.labels": true, "sql.settings.insights_config.query_insights_enabled": true}},
+	}
+
+	plan := testPlan()
+	if eligible := runner.Eligible(plan); len(eligible) != 4 {
+		t.Fatalf("Eligible() returned %d steps, want 4", len(eligible))
+	}
+
+	runner.Options.Approvals = map[string]Approval{
+		"db-1/sql.labels": {Approved: true, By: "bob"},
+		"db-2/sql.labels": {Approved: false, By: "carol"},
+	}
+	results := runner.Run(context.Background(), plan)
+
+	if results[0].Status != history.StatusApplied || results[0].ApprovedBy != "bob" {
+		t.Errorf("results[0] = %s by %q, want applied by bob", results[0].Status, results[0].ApprovedBy)
+	}
+	if results[1].Reason != "not approved" {
+		t.Errorf("results[1] reason = %q, want not approved", results[1].Reason)
+	}
+	if results[2].Reason != "rejected by carol" {
+		t.Errorf("results[2] reason = %q, want rejected by carol", results[2].Reason)
+	}
+	if executor.calls["db-1"] != 1 || executor.calls["db-2"] != 0 {
+		t.Errorf("executor calls = %v, want only the approved db-1 step", executor.calls)
+	}
+
+	changes := Changes(results, "alice", time.Now())
+	if len(changes) != 1 || changes[0].ApprovedBy != "bob" {
+		t.Errorf("Changes() = %+v", changes)
+	}
+}
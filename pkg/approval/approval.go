@@ -0,0 +1,209 @@
+// Package approval gates remediation on human review. A gate publishes the
+// steps of a plan (to a file, a GitHub issue or a Slack thread) and collects
+// approve/reject decisions until every step is decided or the wait times out.
+package approval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/apply"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+// Default wait settings
+const (
+	defaultTimeout      = time.Hour
+	defaultPollInterval = 30 * time.Second
+)
+
+// Gate publishes a plan for review and reads back the decisions
+type Gate interface {
+	// Request publishes the steps awaiting approval
+	Request(ctx context.Context, plan *checks.Plan, steps []checks.Step) error
+	// Decisions returns every decision recorded since the request, oldest first
+	Decisions(ctx context.Context) ([]Decision, error)
+}
+
+// Decision approves or rejects one step; StepID "all" applies to every step
+type Decision struct {
+	StepID   string
+	Approved bool
+	By       string
+}
+
+// Config selects the approval backend; exactly one of File, GitHub and Slack
+// must be set
+type Config struct {
+	// Required makes apply refuse to change anything without an approval gate
+	Required bool `yaml:"required"`
+	// Approvers restricts who may decide. It is required for the github and
+	// slack backends, where anyone who can comment could otherwise approve;
+	// empty allows anyone with write access to the approvals file.
+	Approvers []string `yaml:"approvers,omitempty"`
+	// Timeout is how long to wait for decisions (default: 1h)
+	Timeout string `yaml:"timeout,omitempty"`
+	// PollInterval is how often decisions are read (default: 30s)
+	PollInterval string `yaml:"poll_interval,omitempty"`
+
+	File   *FileConfig   `yaml:"file,omitempty"`
+	GitHub *GitHubConfig `yaml:"github,omitempty"`
+	Slack  *SlackConfig  `yaml:"slack,omitempty"`
+}
+
+// Gate builds the configured gate; it returns nil when no backend is set
+func (c *Config) Gate() (Gate, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	var gates []Gate
+	if c.File != nil {
+		gates = append(gates, NewFile(*c.File))
+	}
+	if (c.GitHub != nil || c.Slack != nil) && len(c.Approvers) == 0 {
+		return nil, fmt.Errorf("approval: approvers is required for the github and slack backends")
+	}
+	if c.GitHub != nil {
+		gate, err := NewGitHub(*c.GitHub)
+		if err != nil {
+			return nil, err
+		}
+		gates = append(gates, gate)
+	}
+	if c.Slack != nil {
+		gate, err := NewSlack(*c.Slack)
+		if err != nil {
+			return nil, err
+		}
+		gates = append(gates, gate)
+	}
+
+	switch len(gates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return gates[0], nil
+	default:
+		return nil, fmt.Errorf("approval: configure only one of file, github and slack")
+	}
+}
+
+// Durations parses the timeout and poll interval, applying defaults
+func (c *Config) Durations() (timeout, interval time.Duration, err error) {
+	timeout, interval = defaultTimeout, defaultPollInterval
+	if c.Timeout != "" {
+		if timeout, err = time.ParseDuration(c.Timeout); err != nil {
+			return 0, 0, fmt.Errorf("approval: invalid timeout %q: %w", c.Timeout, err)
+		}
+	}
+	if c.PollInterval != "" {
+		if interval, err = time.ParseDuration(c.PollInterval); err != nil {
+			return 0, 0, fmt.Errorf("approval: invalid poll_interval %q: %w", c.PollInterval, err)
+		}
+	}
+	if timeout <= 0 || interval <= 0 {
+		return 0, 0, fmt.Errorf("approval: timeout and poll_interval must be positive")
+	}
+	return timeout, interval, nil
+}
+
+// Wait polls the gate until every step is decided or the timeout expires and
+// returns the decisions keyed by step ID. Undecided steps are left out, so
+// they are not applied. A rejection is final; decisions from users outside
+// approvers are ignored.
+func Wait(ctx context.Context, gate Gate, steps []checks.Step, approvers []string, timeout, interval time.Duration) (map[string]apply.Approval, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		decisions, err := gate.Decisions(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("approval: timed out after %s: %w", timeout, err)
+			}
+			return nil, err
+		}
+
+		approvals := Resolve(decisions, steps, approvers)
+		if len(approvals) == len(steps) {
+			return approvals, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return approvals, nil
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Resolve folds decisions into one approval per step; empty approvers accept
+// decisions from anyone
+func Resolve(decisions []Decision, steps []checks.Step, approvers []string) map[string]apply.Approval {
+	allowed := make(map[string]bool)
+	for _, approver := range approvers {
+		allowed[strings.ToLower(approver)] = true
+	}
+
+	approvals := make(map[string]apply.Approval)
+	decide := func(id string, decision Decision) {
+		if current, ok := approvals[id]; ok && !current.Approved {
+			return
+		}
+		approvals[id] = apply.Approval{Approved: decision.Approved, By: decision.By}
+	}
+
+	for _, decision := range decisions {
+		if len(allowed) > 0 && !allowed[strings.ToLower(decision.By)] {
+			continue
+		}
+		for _, step := range steps {
+			if decision.StepID == "all" || decision.StepID == step.ID {
+				decide(step.ID, decision)
+			}
+		}
+	}
+	return approvals
+}
+
+// commandPattern matches "/approve <ids|all>" and "/reject <ids|all>" lines
+var commandPattern = regexp.MustCompile(`(?im)^\s*/(approve|reject)\s+(.+)$`)
+
+// ParseCommands extracts decisions from a comment or chat message
+func ParseCommands(text, by string) []Decision {
+	var decisions []Decision
+	for _, match := range commandPattern.FindAllStringSubmatch(text, -1) {
+		approved := strings.EqualFold(match[1], "approve")
+		for _, id := range strings.FieldsFunc(match[2], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+			decisions = append(decisions, Decision{StepID: strings.ToLower(id), Approved: approved, By: by})
+		}
+	}
+	return decisions
+}
+
+// FormatRequest renders the steps awaiting approval as Markdown, with the
+// commands reviewers reply with
+func FormatRequest(plan *checks.Plan, steps []checks.Step) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Remediation plan awaiting approval (%d step(s))", len(steps))
+	if plan.Source != "" {
+		fmt.Fprintf(&b, " from %s", plan.Source)
+	}
+	b.WriteString("\n\n")
+	for _, step := range steps {
+		fmt.Fprintf(&b, "- `%s` [%s] %s/%s %s: %s -> %s\n",
+			step.ID, step.Risk, step.Project, step.Resource, step.Field, step.Actual, step.Expected)
+	}
+	b.WriteString("\nReply with `/approve <id> [<id>...]`, `/reject <id>` or `/approve all`.")
+	return b.String()
+}
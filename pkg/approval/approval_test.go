@@ -0,0 +1,213 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+func testSteps() []checks.Step {
+	return []checks.Step{
+		{ID: "aaa", CheckID: "sql.labels", Project: "p", Resource: "db-1", Field: "labels.team", Actual: "", Expected: "data"},
+		{ID: "bbb", CheckID: "sql.labels", Project: "p", Resource: "db-2", Field: "labels.team", Actual: "", Expected: "data"},
+	}
+}
+
+func TestParseCommands(t *testing.T) {
+	text := "looks good\n/approve AAA, bbb\n/reject ccc\nnot /approve ddd"
+	got := ParseCommands(text, "alice")
+	want := []Decision{
+		{StepID: "aaa", Approved: true, By: "alice"},
+		{StepID: "bbb", Approved: true, By: "alice"},
+		{StepID: "ccc", Approved: false, By: "alice"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseCommands() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ParseCommands()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	decisions := []Decision{
+		{StepID: "all", Approved: true, By: "mallory"},
+		{StepID: "bbb", Approved: false, By: "Bob"},
+		{StepID: "all", Approved: true, By: "alice"},
+	}
+
+	approvals := Resolve(decisions, testSteps(), []string{"alice", "bob"})
+	if a := approvals["aaa"]; !a.Approved || a.By != "alice" {
+		t.Errorf("aaa = %+v, want approved by alice", a)
+	}
+	// A rejection is not overridden by a later approval
+	if b := approvals["bbb"]; b.Approved || b.By != "Bob" {
+		t.Errorf("bbb = %+v, want rejected by Bob", b)
+	}
+
+	if approvals := Resolve(decisions[:1], testSteps(), []string{"alice"}); len(approvals) != 0 {
+		t.Errorf("decision from a non-approver was counted: %+v", approvals)
+	}
+}
+
+func TestFileGate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "approvals.yaml")
+	gate := NewFile(FileConfig{Path: path})
+	ctx := context.Background()
+
+	if err := gate.Request(ctx, &checks.Plan{Source: "test"}, testSteps()); err != nil {
+		t.Fatal(err)
+	}
+	decisions, err := gate.Decisions(ctx)
+	if err != nil || len(decisions) != 0 {
+		t.Fatalf("Decisions() = %+v, %v; want none pending", decisions, err)
+	}
+
+	data, _ := os.ReadFile(path)
+	edited := strings.Replace(string(data), "decision: pending", "decision: approved", 1)
+	edited = strings.Replace(edited, `by: ""`, "by: alice", 1)
+	if err := os.WriteFile(path, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Requesting again keeps the decision already made
+	if err := gate.Request(ctx, &checks.Plan{Source: "test"}, testSteps()); err != nil {
+		t.Fatal(err)
+	}
+	decisions, err = gate.Decisions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 1 || decisions[0] != (Decision{StepID: "aaa", Approved: true, By: "alice"}) {
+		t.Errorf("Decisions() = %+v, want aaa approved by alice", decisions)
+	}
+}
+
+func TestGitHubGate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/infra/issues/7/comments":
+			json.NewEncoder(w).Encode(map[string]interface{}{"id": 100, "created_at": "2026-01-01T00:00:00Z"})
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/infra/issues/7/comments":
+			w.Write([]byte(`[
+				{"id": 100, "body": "/approve all", "user": {"login": "bot"}},
+				{"id": 101, "body": "/approve aaa", "user": {"login": "alice"}},
+				{"id": 102, "body": "/reject bbb", "user": {"login": "bob"}}
+			]`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gate, err := NewGitHub(GitHubConfig{Repo: "acme/infra", Issue: 7, Token: "token", APIURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := gate.Request(ctx, &checks.Plan{}, testSteps()); err != nil {
+		t.Fatal(err)
+	}
+
+	approvals, err := Wait(ctx, gate, testSteps(), nil, time.Second, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !approvals["aaa"].Approved || approvals["bbb"].Approved || approvals["bbb"].By != "bob" {
+		t.Errorf("approvals = %+v", approvals)
+	}
+}
+
+type staticGate []Decision
+
+func (g staticGate) Request(ctx context.Context, plan *checks.Plan, steps []checks.Step) error {
+	return nil
+}
+
+func (g staticGate) Decisions(ctx context.Context) ([]Decision, error) {
+	return g, nil
+}
+
+func TestWaitTimeout(t *testing.T) {
+	gate := staticGate{{StepID: "aaa", Approved: true, By: "alice"}}
+
+	approvals, err := Wait(context.Background(), gate, testSteps(), nil, 50*time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(approvals) != 1 || !approvals["aaa"].Approved {
+		t.Errorf("approvals = %+v, want only aaa approved", approvals)
+	}
+}
+
+func TestSlackGate_Pages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/chat.postMessage":
+			w.Write([]byte(`{"ok": true, "channel": "C1", "ts": "1.0"}`))
+		case r.URL.Path == "/conversations.replies" && r.URL.Query().Get("cursor") == "":
+			w.Write([]byte(`{"ok": true, "messages": [
+				{"ts": "1.0", "user": "UBOT", "text": "/approve all"},
+				{"ts": "1.1", "user": "UALICE", "text": "looks fine"}
+			], "response_metadata": {"next_cursor": "page2"}}`))
+		case r.URL.Path == "/conversations.replies" && r.URL.Query().Get("cursor") == "page2":
+			w.Write([]byte(`{"ok": true, "messages": [
+				{"ts": "1.2", "user": "UALICE", "text": "/approve aaa"}
+			], "response_metadata": {"next_cursor": ""}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gate, err := NewSlack(SlackConfig{Token: "token", Channel: "C1", APIURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := gate.Request(ctx, &checks.Plan{}, testSteps()); err != nil {
+		t.Fatal(err)
+	}
+
+	decisions, err := gate.Decisions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decisions) != 1 || decisions[0].StepID != "aaa" || decisions[0].By != "UALICE" {
+		t.Errorf("decisions = %+v, want the approval from the second page", decisions)
+	}
+}
+
+func TestConfigGate_RequiresApprovers(t *testing.T) {
+	config := &Config{Slack: &SlackConfig{Token: "token", Channel: "C1"}}
+	if _, err := config.Gate(); err == nil || !strings.Contains(err.Error(), "approvers is required") {
+		t.Errorf("Gate() error = %v, want approvers to be required", err)
+	}
+	config = &Config{GitHub: &GitHubConfig{Repo: "acme/infra", Token: "token"}}
+	if _, err := config.Gate(); err == nil {
+		t.Error("Gate() should require approvers for github")
+	}
+
+	config.Approvers = []string{"alice"}
+	if gate, err := config.Gate(); err != nil || gate == nil {
+		t.Errorf("Gate() = %v, %v", gate, err)
+	}
+	if gate, err := (&Config{File: &FileConfig{Path: "approvals.yaml"}}).Gate(); err != nil || gate == nil {
+		t.Errorf("file Gate() without approvers = %v, %v", gate, err)
+	}
+}
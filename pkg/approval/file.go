@@ -0,0 +1,127 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"gopkg.in/yaml.v3"
+)
+
+// Decision values in an approvals file
+const (
+	filePending  = "pending"
+	fileApproved = "approved"
+	fileRejected = "rejected"
+)
+
+// FileConfig configures an approvals file that reviewers edit
+type FileConfig struct {
+	Path string `yaml:"path"`
+}
+
+// fileEntry is one step in an approvals file
+type fileEntry struct {
+	ID       string `yaml:"id"`
+	Step     string `yaml:"step"`
+	Decision string `yaml:"decision"`
+	By       string `yaml:"by"`
+}
+
+// approvalsFile is the document reviewers edit
+type approvalsFile struct {
+	Source string      `yaml:"source,omitempty"`
+	Steps  []fileEntry `yaml:"steps"`
+}
+
+// File collects decisions from a YAML file, e.g. one reviewed in a pull request
+type File struct {
+	path string
+}
+
+// NewFile creates a file-based gate; the path defaults to approvals.yaml
+func NewFile(config FileConfig) *File {
+	if config.Path == "" {
+		config.Path = "approvals.yaml"
+	}
+	return &File{path: config.Path}
+}
+
+// Request writes a pending entry for each step, keeping decisions already in
+// the file for the same step IDs
+func (f *File) Request(ctx context.Context, plan *checks.Plan, steps []checks.Step) error {
+	existing, err := f.read()
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]fileEntry)
+	for _, entry := range existing.Steps {
+		previous[entry.ID] = entry
+	}
+
+	doc := approvalsFile{Source: plan.Source}
+	for _, step := range steps {
+		entry, ok := previous[step.ID]
+		if !ok {
+			entry = fileEntry{ID: step.ID, Decision: filePending}
+		}
+		entry.Step = fmt.Sprintf("%s %s/%s %s: %s -> %s",
+			step.CheckID, step.Project, step.Resource, step.Field, step.Actual, step.Expected)
+		doc.Steps = append(doc.Steps, entry)
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("approval: failed to encode %s: %w", f.path, err)
+	}
+	header := "# Set decision to approved or rejected and by to your name for each step\n"
+	if err := os.WriteFile(f.path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("approval: failed to write %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Decisions returns the approved and rejected entries in the file
+func (f *File) Decisions(ctx context.Context) ([]Decision, error) {
+	doc, err := f.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var decisions []Decision
+	for _, entry := range doc.Steps {
+		switch strings.ToLower(entry.Decision) {
+		case fileApproved, fileRejected:
+			decisions = append(decisions, Decision{
+				StepID:   entry.ID,
+				Approved: strings.EqualFold(entry.Decision, fileApproved),
+				By:       entry.By,
+			})
+		case filePending, "":
+		default:
+			return nil, fmt.Errorf("approval: %s: invalid decision %q for step %s (approved|rejected|pending)", f.path, entry.Decision, entry.ID)
+		}
+	}
+	return decisions, nil
+}
+
+// read parses the approvals file; a missing file is empty
+func (f *File) read() (*approvalsFile, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &approvalsFile{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("approval: failed to read %s: %w", f.path, err)
+	}
+
+	var doc approvalsFile
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("approval: failed to parse %s: %w", f.path, err)
+	}
+	return &doc, nil
+}
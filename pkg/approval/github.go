@@ -0,0 +1,131 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+const defaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubConfig posts the plan to a GitHub issue and reads /approve and
+// /reject comments
+type GitHubConfig struct {
+	// Repo is owner/name
+	Repo string `yaml:"repo"`
+	// Issue is an existing issue or pull request to comment on; a new issue
+	// is opened when 0
+	Issue int `yaml:"issue,omitempty"`
+	// Token defaults to the GITHUB_TOKEN environment variable
+	Token  string `yaml:"token,omitempty"`
+	APIURL string `yaml:"api_url,omitempty"`
+}
+
+// GitHub collects decisions from issue comments
+type GitHub struct {
+	config GitHubConfig
+	client *http.Client
+
+	// issue and since are set by Request
+	issue     int
+	requestID int64
+	since     time.Time
+}
+
+// NewGitHub creates a GitHub gate
+func NewGitHub(config GitHubConfig) (*GitHub, error) {
+	if owner, name, ok := strings.Cut(config.Repo, "/"); !ok || owner == "" || name == "" {
+		return nil, fmt.Errorf("github: repo must be owner/name, got %q", config.Repo)
+	}
+	if config.Token == "" {
+		config.Token = os.Getenv("GITHUB_TOKEN")
+	}
+	if config.Token == "" {
+		return nil, fmt.Errorf("github: token is required (or set GITHUB_TOKEN)")
+	}
+	if config.APIURL == "" {
+		config.APIURL = defaultGitHubAPIURL
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+
+	return &GitHub{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+		issue:  config.Issue,
+	}, nil
+}
+
+type githubComment struct {
+	ID        int64     `json:"id"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+}
+
+// Request comments on the configured issue, or opens a new one
+func (g *GitHub) Request(ctx context.Context, plan *checks.Plan, steps []checks.Step) error {
+	body := FormatRequest(plan, steps)
+
+	if g.issue == 0 {
+		var issue struct {
+			Number    int       `json:"number"`
+			CreatedAt time.Time `json:"created_at"`
+		}
+		payload := map[string]string{"title": fmt.Sprintf("Approve remediation plan (%d step(s))", len(steps)), "body": body}
+		if err := g.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues", g.config.Repo), payload, &issue); err != nil {
+			return err
+		}
+		g.issue, g.since = issue.Number, issue.CreatedAt
+		return nil
+	}
+
+	var comment githubComment
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", g.config.Repo, g.issue)
+	if err := g.do(ctx, http.MethodPost, path, map[string]string{"body": body}, &comment); err != nil {
+		return err
+	}
+	g.requestID, g.since = comment.ID, comment.CreatedAt
+	return nil
+}
+
+// Decisions parses the commands in comments posted after the request
+func (g *GitHub) Decisions(ctx context.Context) ([]Decision, error) {
+	if g.issue == 0 {
+		return nil, fmt.Errorf("github: no approval request posted")
+	}
+
+	var decisions []Decision
+	for page := 1; ; page++ {
+		var comments []githubComment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100&page=%d&since=%s",
+			g.config.Repo, g.issue, page, g.since.UTC().Format(time.RFC3339))
+		if err := g.do(ctx, http.MethodGet, path, nil, &comments); err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			if comment.ID <= g.requestID {
+				continue
+			}
+			decisions = append(decisions, ParseCommands(comment.Body, comment.User.Login)...)
+		}
+		if len(comments) < 100 {
+			return decisions, nil
+		}
+	}
+}
+
+func (g *GitHub) do(ctx context.Context, method, path string, in, out interface{}) error {
+	headers := map[string]string{
+		"Authorization":        "Bearer " + g.config.Token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": "2022-11-28",
+	}
+	return doJSON(ctx, g.client, "github", method, g.config.APIURL+path, headers, in, out)
+}
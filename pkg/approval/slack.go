@@ -0,0 +1,126 @@
+package approval
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+const defaultSlackAPIURL = "https://slack.com/api"
+
+// SlackConfig posts the plan with a bot token and reads /approve and /reject
+// replies in its thread. The bot needs the chat:write and channels:history
+// (or groups:history) scopes; approvers are Slack user IDs.
+type SlackConfig struct {
+	// Token defaults to the SLACK_BOT_TOKEN environment variable
+	Token   string `yaml:"token,omitempty"`
+	Channel string `yaml:"channel"`
+	APIURL  string `yaml:"api_url,omitempty"`
+}
+
+// Slack collects decisions from thread replies
+type Slack struct {
+	config SlackConfig
+	client *http.Client
+
+	// channel and ts identify the request message once posted
+	channel, ts string
+}
+
+// NewSlack creates a Slack gate
+func NewSlack(config SlackConfig) (*Slack, error) {
+	if config.Token == "" {
+		config.Token = os.Getenv("SLACK_BOT_TOKEN")
+	}
+	if config.Token == "" {
+		return nil, fmt.Errorf("slack: token is required (or set SLACK_BOT_TOKEN)")
+	}
+	if config.Channel == "" {
+		return nil, fmt.Errorf("slack: channel is required")
+	}
+	if config.APIURL == "" {
+		config.APIURL = defaultSlackAPIURL
+	}
+	config.APIURL = strings.TrimSuffix(config.APIURL, "/")
+
+	return &Slack{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// slackResponse is the envelope of every Web API response
+type slackResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+	// Messages are set by conversations.replies; the first is the parent
+	Messages []struct {
+		TS   string `json:"ts"`
+		User string `json:"user"`
+		Text string `json:"text"`
+	} `json:"messages"`
+	// ResponseMetadata holds the cursor of the next page of replies
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// Request posts the plan to the channel
+func (s *Slack) Request(ctx context.Context, plan *checks.Plan, steps []checks.Step) error {
+	payload := map[string]string{"channel": s.config.Channel, "text": FormatRequest(plan, steps)}
+	resp, err := s.call(ctx, http.MethodPost, "chat.postMessage", payload)
+	if err != nil {
+		return err
+	}
+	s.channel, s.ts = resp.Channel, resp.TS
+	return nil
+}
+
+// Decisions parses the commands in replies to the request message
+func (s *Slack) Decisions(ctx context.Context) ([]Decision, error) {
+	if s.ts == "" {
+		return nil, fmt.Errorf("slack: no approval request posted")
+	}
+
+	var decisions []Decision
+	cursor := ""
+	for {
+		query := url.Values{"channel": {s.channel}, "ts": {s.ts}, "limit": {"200"}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		resp, err := s.call(ctx, http.MethodGet, "conversations.replies?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range resp.Messages {
+			if message.TS == s.ts {
+				continue
+			}
+			decisions = append(decisions, ParseCommands(message.Text, message.User)...)
+		}
+		if cursor = resp.ResponseMetadata.NextCursor; cursor == "" {
+			return decisions, nil
+		}
+	}
+}
+
+// call invokes a Web API method; Slack reports errors in the body with HTTP 200
+func (s *Slack) call(ctx context.Context, method, path string, in interface{}) (*slackResponse, error) {
+	var resp slackResponse
+	headers := map[string]string{"Authorization": "Bearer " + s.config.Token}
+	if err := doJSON(ctx, s.client, "slack", method, s.config.APIURL+"/"+path, headers, in, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("slack: %s failed: %s", strings.SplitN(path, "?", 2)[0], resp.Error)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,312 @@
+// Package baselinetest runs baselines against fixture resources and checks
+// the reported drift, so baselines can be unit-tested before a config change
+// is merged.
+package baselinetest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// Suite is a fixture file: a list of test cases
+type Suite struct {
+	File  string `yaml:"-"`
+	Tests []Case `yaml:"tests"`
+}
+
+// Case analyzes one fixture resource against a named baseline. Exactly one of
+// SQLInstance and GKECluster must be set.
+type Case struct {
+	Name        string       `yaml:"name"`
+	Baseline    string       `yaml:"baseline"`
+	SQLInstance *SQLInstance `yaml:"sql_instance,omitempty"`
+	GKECluster  *GKECluster  `yaml:"gke_cluster,omitempty"`
+	Expect      Expectation  `yaml:"expect"`
+}
+
+// SQLInstance describes a synthetic Cloud SQL instance
+type SQLInstance struct {
+	Project           string                 `yaml:"project,omitempty"`
+	Name              string                 `yaml:"name"`
+	Region            string                 `yaml:"region,omitempty"`
+	State             string                 `yaml:"state,omitempty"`
+	Labels            map[string]string      `yaml:"labels,omitempty"`
+	Databases         []string               `yaml:"databases,omitempty"`
+	MaintenanceWindow *sql.MaintenanceWindow `yaml:"maintenance_window,omitempty"`
+	Config            *sql.DatabaseConfig    `yaml:"config"`
+}
+
+// GKECluster describes a synthetic GKE cluster
+type GKECluster struct {
+	Project   string                `yaml:"project,omitempty"`
+	Name      string                `yaml:"name"`
+	Location  string                `yaml:"location,omitempty"`
+	Status    string                `yaml:"status,omitempty"`
+	Labels    map[string]string     `yaml:"labels,omitempty"`
+	Config    *gke.ClusterConfig    `yaml:"config"`
+	NodePools []*gke.NodePoolConfig `yaml:"node_pools,omitempty"`
+}
+
+// Expectation describes the drift a case must produce
+type Expectation struct {
+	// Matched is whether the baseline must select the resource by its filter
+	// labels and name scope (default: true). An unselected resource is not
+	// analyzed.
+	Matched *bool `yaml:"matched,omitempty"`
+	// Drifts must all be reported; empty attributes are not checked
+	Drifts []ExpectedDrift `yaml:"drifts,omitempty"`
+	// NoDrift lists fields that must not drift
+	NoDrift []string `yaml:"no_drift,omitempty"`
+	// Exact fails on any drift not listed in Drifts; with no Drifts the
+	// resource must be free of drift
+	Exact bool `yaml:"exact,omitempty"`
+}
+
+// ExpectedDrift matches a reported drift by field and, when set, severity
+// and values
+type ExpectedDrift struct {
+	Field    string `yaml:"field"`
+	Severity string `yaml:"severity,omitempty"`
+	Expected string `yaml:"expected,omitempty"`
+	Actual   string `yaml:"actual,omitempty"`
+}
+
+// Baselines are the baselines and policy under test
+type Baselines struct {
+	SQL       []sql.SQLBaseline
+	GKE       []gke.GKEBaseline
+	SQLPolicy *sql.Policy
+}
+
+// Result is the outcome of one case
+type Result struct {
+	File     string
+	Name     string
+	Failures []string
+}
+
+// Passed reports whether the case met every expectation
+func (r Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// LoadFiles reads fixture files; directories are searched for *.yaml and
+// *.yml files
+func LoadFiles(paths []string) ([]*Suite, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixtures: %w", err)
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, _ := filepath.Glob(filepath.Join(path, pattern))
+			files = append(files, matches...)
+		}
+	}
+	sort.Strings(files)
+
+	suites := make([]*Suite, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		suite := &Suite{File: file}
+		if err := yaml.Unmarshal(data, suite); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		suites = append(suites, suite)
+	}
+	return suites, nil
+}
+
+// Run executes every case of a suite
+func Run(suite *Suite, baselines Baselines) []Result {
+	results := make([]Result, 0, len(suite.Tests))
+	for i, c := range suite.Tests {
+		name := c.Name
+		if name == "" {
+			name = fmt.Sprintf("test %d", i+1)
+		}
+		results = append(results, Result{File: suite.File, Name: name, Failures: runCase(c, baselines)})
+	}
+	return results
+}
+
+// runCase analyzes the fixture and returns the unmet expectations
+func runCase(c Case, baselines Baselines) []string {
+	var matched bool
+	var analyze func() []report.Drift
+
+	switch {
+	case c.SQLInstance != nil && c.GKECluster != nil:
+		return []string{"set only one of sql_instance and gke_cluster"}
+	case c.SQLInstance != nil:
+		baseline, ok := findSQL(baselines.SQL, c.Baseline)
+		if !ok {
+			return []string{fmt.Sprintf("no sql_baselines entry named %q", c.Baseline)}
+		}
+		inst := c.SQLInstance.instance()
+		matched = len(sql.SelectInstances([]*sql.DatabaseInstance{inst}, baseline, baselines.SQL)) == 1
+		analyze = func() []report.Drift {
+			analyzer := &sql.Analyzer{}
+			analyzer.SetPolicy(baselines.SQLPolicy)
+			return analyzer.AnalyzeInstance(inst, baseline.Config).Drifts
+		}
+	case c.GKECluster != nil:
+		baseline, ok := findGKE(baselines.GKE, c.Baseline)
+		if !ok {
+			return []string{fmt.Sprintf("no gke_baselines entry named %q", c.Baseline)}
+		}
+		cluster := c.GKECluster.instance()
+		matched = len(gke.SelectClusters([]*gke.ClusterInstance{cluster}, baseline, baselines.GKE)) == 1
+		analyze = func() []report.Drift {
+			analyzer := &gke.Analyzer{}
+			r := analyzer.AnalyzeDrift([]*gke.ClusterInstance{cluster}, baseline.ClusterConfig, baseline.NodePoolBaselines())
+			return r.Instances[0].Drifts
+		}
+	default:
+		return []string{"set sql_instance or gke_cluster"}
+	}
+
+	wantMatched := c.Expect.Matched == nil || *c.Expect.Matched
+	if matched != wantMatched {
+		if matched {
+			return []string{"baseline selects the resource, expected it not to"}
+		}
+		return []string{"baseline does not select the resource (filter_labels, instance_names, name_pattern or a name-scoped baseline)"}
+	}
+	if !matched {
+		return nil
+	}
+	return check(c.Expect, analyze())
+}
+
+// check compares reported drifts against the expectation
+func check(expect Expectation, drifts []report.Drift) []string {
+	var failures []string
+	used := make([]bool, len(drifts))
+
+	for _, want := range expect.Drifts {
+		found := false
+		var candidates []string
+		for i, got := range drifts {
+			if got.Field != want.Field {
+				continue
+			}
+			if mismatch := compare(want, got); mismatch != "" {
+				candidates = append(candidates, mismatch)
+				continue
+			}
+			used[i], found = true, true
+			break
+		}
+		switch {
+		case found:
+		case len(candidates) > 0:
+			failures = append(failures, fmt.Sprintf("%s: %s", want.Field, strings.Join(candidates, "; ")))
+		default:
+			failures = append(failures, fmt.Sprintf("%s: expected drift, none reported", want.Field))
+		}
+	}
+
+	for _, field := range expect.NoDrift {
+		for _, got := range drifts {
+			if got.Field == field {
+				failures = append(failures, fmt.Sprintf("%s: unexpected drift (expected %s, actual %s)", field, got.Expected, got.Actual))
+			}
+		}
+	}
+
+	if expect.Exact {
+		for i, got := range drifts {
+			if !used[i] {
+				failures = append(failures, fmt.Sprintf("%s: unexpected %s drift (expected %s, actual %s)", got.Field, got.Severity, got.Expected, got.Actual))
+			}
+		}
+	}
+	return failures
+}
+
+// compare describes how a reported drift differs from the expected one, or
+// returns "" when every set attribute matches
+func compare(want ExpectedDrift, got report.Drift) string {
+	var diffs []string
+	if want.Severity != "" && want.Severity != got.Severity {
+		diffs = append(diffs, fmt.Sprintf("severity %s, want %s", got.Severity, want.Severity))
+	}
+	if want.Expected != "" && want.Expected != got.Expected {
+		diffs = append(diffs, fmt.Sprintf("expected value %q, want %q", got.Expected, want.Expected))
+	}
+	if want.Actual != "" && want.Actual != got.Actual {
+		diffs = append(diffs, fmt.Sprintf("actual value %q, want %q", got.Actual, want.Actual))
+	}
+	return strings.Join(diffs, ", ")
+}
+
+func (f *SQLInstance) instance() *sql.DatabaseInstance {
+	config := f.Config
+	if config == nil {
+		config = &sql.DatabaseConfig{}
+	}
+	if config.Settings == nil {
+		config.Settings = &sql.Settings{}
+	}
+	return &sql.DatabaseInstance{
+		Project:           f.Project,
+		Name:              f.Name,
+		State:             f.State,
+		Region:            f.Region,
+		Config:            config,
+		MaintenanceWindow: f.MaintenanceWindow,
+		Labels:            f.Labels,
+		Databases:         f.Databases,
+	}
+}
+
+func (f *GKECluster) instance() *gke.ClusterInstance {
+	config := f.Config
+	if config == nil {
+		config = &gke.ClusterConfig{}
+	}
+	return &gke.ClusterInstance{
+		Project:   f.Project,
+		Name:      f.Name,
+		Location:  f.Location,
+		Status:    f.Status,
+		Config:    config,
+		NodePools: f.NodePools,
+		Labels:    f.Labels,
+	}
+}
+
+func findSQL(baselines []sql.SQLBaseline, name string) (sql.SQLBaseline, bool) {
+	for _, b := range baselines {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return sql.SQLBaseline{}, false
+}
+
+func findGKE(baselines []gke.GKEBaseline, name string) (gke.GKEBaseline, bool) {
+	for _, b := range baselines {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return gke.GKEBaseline{}, false
+}
@@ -0,0 +1,141 @@
+package baselinetest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+)
+
+const fixtures = `tests:
+  - name: production tier drift
+    baseline: production
+    sql_instance:
+      name: db-prod
+      labels: {env: prod}
+      config:
+        database_version: POSTGRES_15
+        tier: db-f1-micro
+        settings:
+          backup_enabled: true
+    expect:
+      drifts:
+        - field: tier
+          expected: db-custom-4-16384
+          actual: db-f1-micro
+      no_drift: [database_version]
+  - name: dev instance is not selected
+    baseline: production
+    sql_instance:
+      name: db-dev
+      labels: {env: dev}
+    expect:
+      matched: false
+  - name: cluster matches
+    baseline: clusters
+    gke_cluster:
+      name: gke-1
+      config:
+        release_channel: REGULAR
+    expect:
+      exact: true
+`
+
+func testBaselines() Baselines {
+	return Baselines{
+		SQL: []sql.SQLBaseline{{
+			Name:         "production",
+			FilterLabels: map[string]string{"env": "prod"},
+			Config: &sql.DatabaseConfig{
+				DatabaseVersion: "POSTGRES_15",
+				Tier:            "db-custom-4-16384",
+				Settings:        &sql.Settings{BackupEnabled: true},
+			},
+		}},
+		GKE: []gke.GKEBaseline{{
+			Name:          "clusters",
+			ClusterConfig: &gke.ClusterConfig{ReleaseChannel: "REGULAR"},
+		}},
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "baselines.yaml"), []byte(fixtures), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	suites, err := LoadFiles([]string{dir})
+	if err != nil {
+		t.Fatalf("LoadFiles() error = %v", err)
+	}
+	if len(suites) != 1 {
+		t.Fatalf("LoadFiles() loaded %d files, want 1", len(suites))
+	}
+
+	results := Run(suites[0], testBaselines())
+	if len(results) != 3 {
+		t.Fatalf("Run() returned %d results, want 3", len(results))
+	}
+	for _, r := range results {
+		if !r.Passed() {
+			t.Errorf("%s failed: %v", r.Name, r.Failures)
+		}
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	notMatched := false
+	tests := []struct {
+		name string
+		c    Case
+		want string
+	}{
+		{
+			name: "unknown baseline",
+			c:    Case{Baseline: "staging", SQLInstance: &SQLInstance{Name: "db"}},
+			want: `no sql_baselines entry named "staging"`,
+		},
+		{
+			name: "no resource",
+			c:    Case{Baseline: "production"},
+			want: "set sql_instance or gke_cluster",
+		},
+		{
+			name: "unexpected match",
+			c: Case{Baseline: "production", SQLInstance: &SQLInstance{Name: "db", Labels: map[string]string{"env": "prod"}},
+				Expect: Expectation{Matched: &notMatched}},
+			want: "expected it not to",
+		},
+		{
+			name: "wrong severity",
+			c: Case{Baseline: "production", SQLInstance: &SQLInstance{Name: "db", Labels: map[string]string{"env": "prod"},
+				Config: &sql.DatabaseConfig{DatabaseVersion: "POSTGRES_15", Settings: &sql.Settings{BackupEnabled: true}}},
+				Expect: Expectation{Drifts: []ExpectedDrift{{Field: "tier", Severity: "no-such-severity"}}}},
+			want: "want no-such-severity",
+		},
+		{
+			name: "missing drift",
+			c: Case{Baseline: "clusters", GKECluster: &GKECluster{Name: "gke-1", Config: &gke.ClusterConfig{ReleaseChannel: "REGULAR"}},
+				Expect: Expectation{Drifts: []ExpectedDrift{{Field: "release_channel"}}}},
+			want: "expected drift, none reported",
+		},
+		{
+			name: "exact",
+			c: Case{Baseline: "clusters", GKECluster: &GKECluster{Name: "gke-1", Config: &gke.ClusterConfig{ReleaseChannel: "RAPID"}},
+				Expect: Expectation{Exact: true}},
+			want: "release_channel: unexpected",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failures := runCase(tt.c, testBaselines())
+			if got := strings.Join(failures, "\n"); !strings.Contains(got, tt.want) {
+				t.Errorf("runCase() failures = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Allowed maps drift fields to their acceptable values. Keys are field paths
+// as shown by `checks list` (e.g. "tier", "cluster.release_channel"); a "*"
+// key such as "nodepool[*].machine_type" applies to every node pool, while
+// "nodepool[pool-a].machine_type" applies to one.
+type Allowed map[string][]string
+
+// Values returns the acceptable values for a field, preferring an exact key
+// over a wildcard key
+func (a Allowed) Values(field string) []string {
+	values, _ := lookupField(a, field)
+	return values
+}
+
+// lookupField returns the value for a field from a map keyed by field paths,
+// preferring an exact key over a wildcard key
+func lookupField[V any](m map[string]V, field string) (V, bool) {
+	if value, ok := m[field]; ok {
+		return value, true
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.Contains(key, "*") && matchPath(key, field) {
+			return m[key], true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Validate checks that every key names a registered check path and has values
+func (a Allowed) Validate() error {
+	for key, values := range a {
+		if len(values) == 0 {
+			return fmt.Errorf("allowed_values.%s: at least one value is required", key)
+		}
+		if !defaultRegistry.hasPath(key) {
+			return fmt.Errorf("allowed_values.%s: unknown field; run 'checks list' for valid paths", key)
+		}
+	}
+	return nil
+}
+
+// DescribeValues renders a set of acceptable values for the Expected column
+func DescribeValues(values []string) string {
+	return fmt.Sprintf("one of %v", values)
+}
+
+// OneOf appends a drift when actual is not an acceptable value. When the
+// field has no acceptable values configured it falls back to String with the
+// single expected value.
+func (c *Check) OneOf(drifts []report.Drift, allowed Allowed, expected, actual string) []report.Drift {
+	values := allowed.Values(c.Path)
+	if len(values) == 0 {
+		return c.String(drifts, expected, actual)
+	}
+
+	for _, v := range values {
+		if v == actual {
+			return drifts
+		}
+	}
+	return c.Append(drifts, DescribeValues(values), actual)
+}
+
+// hasPath reports whether a registered check reports the given field. Both
+// wildcard keys ("nodepool[*].x") and concrete keys ("nodepool[a].x") match.
+func (r *Registry) hasPath(field string) bool {
+	for _, c := range r.All() {
+		if c.Path == field || matchPath(c.Path, field) {
+			return true
+		}
+	}
+	return false
+}
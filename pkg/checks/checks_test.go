@@ -0,0 +1,222 @@
+package checks
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{ID: "b.check", ResourceType: "B", Path: "b", Severity: "low"})
+	r.Register(Check{ID: "a.check", ResourceType: "A", Path: "a", Severity: "high"})
+
+	all := r.All()
+	if len(all) != 2 || all[0].ID != "a.check" || all[1].ID != "b.check" {
+		t.Errorf("All() = %+v, want sorted by ID", all)
+	}
+	if _, ok := r.Get("a.check"); !ok {
+		t.Error("Get(a.check) not found")
+	}
+
+	assertPanics(t, "duplicate", func() {
+		r.Register(Check{ID: "a.check", ResourceType: "A", Path: "a", Severity: "high"})
+	})
+	assertPanics(t, "invalid severity", func() {
+		r.Register(Check{ID: "c.check", ResourceType: "C", Path: "c", Severity: "urgent"})
+	})
+	assertPanics(t, "incomplete", func() {
+		r.Register(Check{ID: "d.check", Severity: "low"})
+	})
+}
+
+func TestCompare(t *testing.T) {
+	c := &Check{ID: "x", ResourceType: "X", Path: "pool[*].size", Severity: "medium", Immutable: true}
+
+	drifts := c.String(nil, "", "anything")
+	drifts = c.String(drifts, "a", "a")
+	drifts = c.Int(drifts, 0, 5)
+	drifts = c.Bool(drifts, true, true)
+	if len(drifts) != 0 {
+		t.Fatalf("expected no drifts, got %+v", drifts)
+	}
+
+	drifts = c.At("default").Int(drifts, 10, 20)
+	if len(drifts) != 1 {
+		t.Fatalf("expected one drift, got %+v", drifts)
+	}
+	d := drifts[0]
+	if d.Field != "pool[default].size" || d.Expected != "10" || d.Actual != "20" || d.Severity != "medium" || !d.Immutable {
+		t.Errorf("drift = %+v", d)
+	}
+	if c.Path != "pool[*].size" {
+		t.Errorf("At() modified the registered check path: %s", c.Path)
+	}
+}
+
+func TestSetDiff(t *testing.T) {
+	missing, extra := SetDiff([]string{"a", "b", "c"}, []string{"c", "d", "a"})
+	if !reflect.DeepEqual(missing, []string{"b"}) {
+		t.Errorf("missing = %v, want [b]", missing)
+	}
+	if !reflect.DeepEqual(extra, []string{"d"}) {
+		t.Errorf("extra = %v, want [d]", extra)
+	}
+}
+
+func assertPanics(t *testing.T, name string, fn func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s: expected panic", name)
+		}
+	}()
+	fn()
+}
+
+func TestFind(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{ID: "gke.nodepool.machine_type", ResourceType: "GKE", Path: "nodepool[*].machine_type", Severity: "high"})
+	r.Register(Check{ID: "sql.networks", ResourceType: "SQL", Path: "networks", Severity: "high"})
+	r.Register(Check{ID: "sql.networks.extra", ResourceType: "SQL", Path: "networks", Severity: "medium"})
+	r.Register(Check{ID: "gke.nodepool.missing", ResourceType: "GKE", Path: "nodepool[*]", Severity: "high"})
+	r.Register(Check{ID: "gke.nodepool.labels", ResourceType: "GKE", Path: "nodepool[*].labels[*]", Severity: "high"})
+
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{"sql.networks", []string{"sql.networks"}},
+		{"networks", []string{"sql.networks", "sql.networks.extra"}},
+		{"nodepool[default-pool].machine_type", []string{"gke.nodepool.machine_type"}},
+		{"nodepool[].machine_type", nil},
+		{"nodepool[gpu]", []string{"gke.nodepool.missing"}},
+		{"nodepool[gpu].labels[cloud.google.com/gke-spot]", []string{"gke.nodepool.labels"}},
+		{"unknown", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			var got []string
+			for _, c := range r.Find(tt.ref) {
+				got = append(got, c.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForDrift(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{ID: "sql.networks", ResourceType: "SQL", Path: "networks", Severity: "high"})
+	r.Register(Check{ID: "sql.networks.extra", ResourceType: "SQL", Path: "networks", Severity: "medium"})
+	r.Register(Check{ID: "gke.networks", ResourceType: "GKE", Path: "networks", Severity: "low"})
+
+	tests := []struct {
+		resourceType, severity string
+		want                   string
+	}{
+		{"SQL", "medium", "sql.networks.extra"},
+		{"SQL", "high", "sql.networks"},
+		{"SQL", "critical", "sql.networks"},
+		{"GKE", "high", "gke.networks"},
+		{"Redis", "high", ""},
+	}
+
+	for _, tt := range tests {
+		c, ok := r.ForDrift(tt.resourceType, "networks", tt.severity)
+		var got string
+		if ok {
+			got = c.ID
+		}
+		if got != tt.want {
+			t.Errorf("ForDrift(%s, networks, %s) = %q, want %q", tt.resourceType, tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestForField(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{ID: "x.flags.extra", ResourceType: "X", Path: "flags.*", Severity: "low"})
+	r.Register(Check{ID: "x.flags", ResourceType: "X", Path: "flags.*", Severity: "medium"})
+	r.Register(Check{ID: "y.flags", ResourceType: "Y", Path: "flags.*", Severity: "high"})
+
+	if c, ok := r.ForField("X", "flags.max_connections"); !ok || c.ID != "x.flags" {
+		t.Errorf("ForField(X, flags.max_connections) = %+v, want x.flags", c)
+	}
+	if c, ok := r.ForField("Y", "flags.max_connections"); !ok || c.ID != "y.flags" {
+		t.Errorf("ForField(Y, flags.max_connections) = %+v, want y.flags", c)
+	}
+	if _, ok := r.ForField("X", "tier"); ok {
+		t.Error("ForField(X, tier) found a check")
+	}
+}
+
+func TestOneOf(t *testing.T) {
+	c := &Check{ID: "x", ResourceType: "X", Path: "pool[*].type", Severity: "high"}
+	allowed := Allowed{
+		"pool[*].type":   {"small", "medium"},
+		"pool[big].type": {"large"},
+	}
+
+	tests := []struct {
+		name     string
+		pool     string
+		allowed  Allowed
+		expected string
+		actual   string
+		want     string
+	}{
+		{"allowed value", "a", allowed, "", "medium", ""},
+		{"value outside set", "a", allowed, "", "large", "one of [small medium]"},
+		{"exact key wins", "big", allowed, "", "large", ""},
+		{"set overrides expected", "a", allowed, "small", "medium", ""},
+		{"no set falls back to expected", "a", nil, "small", "medium", "small"},
+		{"no set and no expected", "a", nil, "", "medium", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drifts := c.At(tt.pool).OneOf(nil, tt.allowed, tt.expected, tt.actual)
+			if tt.want == "" {
+				if len(drifts) != 0 {
+					t.Errorf("expected no drift, got %+v", drifts)
+				}
+				return
+			}
+			if len(drifts) != 1 || drifts[0].Expected != tt.want || drifts[0].Actual != tt.actual {
+				t.Errorf("drifts = %+v, want expected %q", drifts, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	overrides := SeverityOverrides{
+		"tier":                   "low",
+		"nodepool[*].disk_size":  "high",
+		"nodepool[db].disk_size": "critical",
+	}
+
+	drifts := []report.Drift{
+		{Field: "tier", Severity: "medium"},
+		{Field: "nodepool[web].disk_size", Severity: "low"},
+		{Field: "nodepool[db].disk_size", Severity: "low"},
+		{Field: "disk_type", Severity: "medium"},
+	}
+	overrides.Apply(drifts)
+
+	want := []string{"low", "high", "critical", "medium"}
+	for i, drift := range drifts {
+		if drift.Severity != want[i] {
+			t.Errorf("%s severity = %s, want %s", drift.Field, drift.Severity, want[i])
+		}
+	}
+
+	if err := (SeverityOverrides{"tier": "urgent"}).Validate(); err == nil {
+		t.Error("Validate() accepted an invalid severity")
+	}
+}
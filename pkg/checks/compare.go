@@ -0,0 +1,82 @@
+package checks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// At returns a copy of the check with the "*" in its path replaced by key
+func (c *Check) At(key string) *Check {
+	at := *c
+	at.Path = strings.Replace(c.Path, "*", key, 1)
+	return &at
+}
+
+// Drift builds a drift for the check with its default severity
+func (c *Check) Drift(expected, actual string) report.Drift {
+	return report.Drift{
+		Field:     c.Path,
+		Expected:  expected,
+		Actual:    actual,
+		Severity:  c.Severity,
+		Immutable: c.Immutable,
+	}
+}
+
+// Append adds a drift for the check unconditionally
+func (c *Check) Append(drifts []report.Drift, expected, actual string) []report.Drift {
+	return append(drifts, c.Drift(expected, actual))
+}
+
+// String appends a drift when expected is set and actual differs
+func (c *Check) String(drifts []report.Drift, expected, actual string) []report.Drift {
+	if expected == "" || actual == expected {
+		return drifts
+	}
+	return c.Append(drifts, expected, actual)
+}
+
+// Bool appends a drift when actual differs from expected
+func (c *Check) Bool(drifts []report.Drift, expected, actual bool) []report.Drift {
+	if actual == expected {
+		return drifts
+	}
+	return c.Append(drifts, fmt.Sprintf("%v", expected), fmt.Sprintf("%v", actual))
+}
+
+// Int appends a drift when expected is positive and actual differs
+func (c *Check) Int(drifts []report.Drift, expected, actual int64) []report.Drift {
+	if expected <= 0 || actual == expected {
+		return drifts
+	}
+	return c.Append(drifts, fmt.Sprintf("%d", expected), fmt.Sprintf("%d", actual))
+}
+
+// SetDiff returns the expected entries missing from actual and the actual
+// entries not in expected, each in input order
+func SetDiff(expected, actual []string) (missing, extra []string) {
+	expectedSet := make(map[string]bool, len(expected))
+	for _, v := range expected {
+		expectedSet[v] = true
+	}
+	actualSet := make(map[string]bool, len(actual))
+	for _, v := range actual {
+		actualSet[v] = true
+	}
+
+	missing = make([]string, 0)
+	for _, v := range expected {
+		if !actualSet[v] {
+			missing = append(missing, v)
+		}
+	}
+	extra = make([]string, 0)
+	for _, v := range actual {
+		if !expectedSet[v] {
+			extra = append(extra, v)
+		}
+	}
+	return missing, extra
+}
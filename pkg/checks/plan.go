@@ -0,0 +1,204 @@
+package checks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// PlanVersion is the schema version of remediation plans
+const PlanVersion = 1
+
+// Plan actions
+const (
+	ActionUpdate   = "update"   // run Command against the existing resource
+	ActionRecreate = "recreate" // the setting is fixed at creation; replace the resource
+	ActionManual   = "manual"   // follow Instructions
+)
+
+// Plan risk levels
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// resourceTokens are the placeholders remediation hints use for the resource
+// name; they are replaced with the name and the flags scoping it to its project
+var resourceTokens = []string{"INSTANCE", "CLUSTER"}
+
+// nodePoolKey extracts the node pool from fields such as "nodepool[pool-a].machine_type"
+var nodePoolKey = regexp.MustCompile(`^nodepool\[([^\]]+)\]`)
+
+// Plan is an ordered, machine-consumable list of remediation steps built from
+// a drift report
+type Plan struct {
+	Version     int       `json:"version" yaml:"version"`
+	Source      string    `json:"source" yaml:"source"`
+	GeneratedAt time.Time `json:"generated_at" yaml:"generated_at"`
+	Steps       []Step    `json:"steps" yaml:"steps"`
+}
+
+// Step is a single remediation for one drifted field
+type Step struct {
+	// ID identifies the step by resource, field and target value, e.g. for approvals
+	ID           string `json:"id" yaml:"id"`
+	Order        int    `json:"order" yaml:"order"`
+	CheckID      string `json:"check_id,omitempty" yaml:"check_id,omitempty"`
+	ResourceType string `json:"resource_type" yaml:"resource_type"`
+	Project      string `json:"project,omitempty" yaml:"project,omitempty"`
+	Resource     string `json:"resource" yaml:"resource"`
+	Location     string `json:"location,omitempty" yaml:"location,omitempty"`
+	Field        string `json:"field" yaml:"field"`
+	Expected     string `json:"expected" yaml:"expected"`
+	Actual       string `json:"actual" yaml:"actual"`
+	Severity     string `json:"severity" yaml:"severity"`
+	Action       string `json:"action" yaml:"action"`
+	// Command is a gcloud command with the resource filled in; remaining
+	// upper-case placeholders (e.g. TIER) take the expected value
+	Command         string `json:"command,omitempty" yaml:"command,omitempty"`
+	Instructions    string `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+	Risk            string `json:"risk" yaml:"risk"`
+	RequiresRestart bool   `json:"requires_restart" yaml:"requires_restart"`
+}
+
+// riskRank orders plan risk levels
+var riskRank = map[string]int{RiskLow: 1, RiskMedium: 2, RiskHigh: 3}
+
+// BuildPlan creates a remediation plan for every drift in the report, using
+// the default registry. Steps are ordered by risk so low-risk, in-place fixes
+// come first and restarts and recreations last; within a risk level the
+// report order is kept.
+func BuildPlan(r *report.Report) *Plan {
+	return defaultRegistry.BuildPlan(r)
+}
+
+// BuildPlan creates a remediation plan for every drift in the report
+func (reg *Registry) BuildPlan(r *report.Report) *Plan {
+	plan := &Plan{
+		Version:     PlanVersion,
+		Source:      r.Title,
+		GeneratedAt: r.Timestamp.UTC(),
+		Steps:       []Step{},
+	}
+
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			step := Step{
+				ResourceType: res.Type,
+				Project:      res.Project,
+				Resource:     res.Name,
+				Location:     res.Location,
+				Field:        d.Field,
+				Expected:     d.Expected,
+				Actual:       d.Actual,
+				Severity:     d.Severity,
+			}
+
+			c, ok := reg.ForDrift(res.Type, d.Field, d.Severity)
+			if !ok {
+				step.Action = ActionManual
+				step.Instructions = "No remediation is registered for this field; update the resource to the expected value"
+				step.Risk = RiskMedium
+				plan.Steps = append(plan.Steps, step)
+				continue
+			}
+
+			step.CheckID = c.ID
+			step.RequiresRestart = c.RequiresRestart
+			switch {
+			case c.Immutable || d.Immutable:
+				step.Action = ActionRecreate
+				step.Instructions = c.Remediation
+				step.Risk = RiskHigh
+			case strings.HasPrefix(c.Remediation, "gcloud "):
+				step.Action = ActionUpdate
+				step.Command, step.Instructions = remediationCommand(c.Remediation, res, d.Field)
+				step.Risk = RiskLow
+			default:
+				step.Action = ActionManual
+				step.Instructions = c.Remediation
+				step.Risk = RiskMedium
+			}
+			if step.RequiresRestart && riskRank[step.Risk] < riskRank[RiskMedium] {
+				step.Risk = RiskMedium
+			}
+			plan.Steps = append(plan.Steps, step)
+		}
+	}
+
+	sort.SliceStable(plan.Steps, func(i, j int) bool {
+		a, b := plan.Steps[i], plan.Steps[j]
+		if riskRank[a.Risk] != riskRank[b.Risk] {
+			return riskRank[a.Risk] < riskRank[b.Risk]
+		}
+		return !a.RequiresRestart && b.RequiresRestart
+	})
+	for i := range plan.Steps {
+		plan.Steps[i].Order = i + 1
+		plan.Steps[i].ID = StepID(plan.Steps[i])
+	}
+	return plan
+}
+
+// StepID derives a short, stable ID from the resource, field and expected
+// value, so the same fix gets the same ID in every plan
+func StepID(step Step) string {
+	key := strings.Join([]string{step.ResourceType, step.Project, step.Resource, step.Field, step.Expected}, "\x00")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// remediationCommand fills the resource into a gcloud remediation hint. A
+// trailing note such as "(restarts the instance)" is returned separately.
+func remediationCommand(remediation string, res report.Resource, field string) (command, note string) {
+	command = remediation
+	if i := strings.Index(command, " ("); i >= 0 {
+		command, note = command[:i], strings.TrimSuffix(command[i+2:], ")")
+	}
+
+	scope := res.Name
+	if strings.HasPrefix(command, "gcloud container ") && res.Location != "" {
+		scope += " --location=" + res.Location
+	}
+	if res.Project != "" {
+		scope += " --project=" + res.Project
+	}
+	for _, token := range resourceTokens {
+		if strings.Contains(command, token) {
+			command = strings.Replace(command, token, scope, 1)
+			break
+		}
+	}
+
+	if m := nodePoolKey.FindStringSubmatch(field); m != nil {
+		command = strings.ReplaceAll(command, "POOL", m[1])
+	}
+	return command, note
+}
+
+// FormatYAML renders the plan as YAML
+func (p *Plan) FormatYAML() (string, error) {
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatJSON renders the plan as indented JSON
+func (p *Plan) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal plan: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestBuildPlan(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{ID: "sql.tier", ResourceType: "Cloud SQL", Path: "tier", Severity: "high",
+		Remediation: "gcloud sql instances patch INSTANCE --tier=TIER (restarts the instance)", RequiresRestart: true})
+	r.Register(Check{ID: "sql.backup", ResourceType: "Cloud SQL", Path: "settings.backup_enabled", Severity: "critical",
+		Remediation: "gcloud sql instances patch INSTANCE --backup-start-time=HH:MM"})
+	r.Register(Check{ID: "gke.network", ResourceType: "GKE Cluster", Path: "cluster.network", Severity: "high",
+		Remediation: "Create a replacement cluster", Immutable: true})
+	r.Register(Check{ID: "gke.nodepool.auto_repair", ResourceType: "GKE Cluster", Path: "nodepool[*].auto_repair", Severity: "high",
+		Remediation: "gcloud container node-pools update POOL --cluster=CLUSTER --enable-autorepair"})
+
+	rep := &report.Report{
+		Title: "Drift",
+		Resources: []report.Resource{
+			{Type: "GKE Cluster", Project: "p", Name: "c1", Location: "us-central1", Drifts: []report.Drift{
+				{Field: "cluster.network", Expected: "vpc-a", Actual: "vpc-b", Severity: "high"},
+				{Field: "nodepool[pool-a].auto_repair", Expected: "true", Actual: "false", Severity: "high"},
+			}},
+			{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []report.Drift{
+				{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-custom-2-8192", Severity: "high"},
+				{Field: "settings.backup_enabled", Expected: "true", Actual: "false", Severity: "critical"},
+				{Field: "database_flags.unknown", Expected: "on", Actual: "off", Severity: "low"},
+			}},
+		},
+	}
+
+	plan := r.BuildPlan(rep)
+	if plan.Version != PlanVersion || len(plan.Steps) != 5 {
+		t.Fatalf("plan = %+v", plan)
+	}
+
+	wantOrder := []string{"nodepool[pool-a].auto_repair", "settings.backup_enabled", "database_flags.unknown", "tier", "cluster.network"}
+	for i, step := range plan.Steps {
+		if step.Order != i+1 || step.Field != wantOrder[i] {
+			t.Errorf("step %d = #%d %s, want %s", i, step.Order, step.Field, wantOrder[i])
+		}
+	}
+
+	pool := plan.Steps[0]
+	if want := "gcloud container node-pools update pool-a --cluster=c1 --location=us-central1 --project=p --enable-autorepair"; pool.Command != want {
+		t.Errorf("node pool command = %q, want %q", pool.Command, want)
+	}
+
+	tier := plan.Steps[3]
+	if tier.Action != ActionUpdate || tier.Risk != RiskMedium || !tier.RequiresRestart {
+		t.Errorf("tier step = %+v", tier)
+	}
+	if tier.Command != "gcloud sql instances patch db-1 --project=p --tier=TIER" || tier.Instructions != "restarts the instance" {
+		t.Errorf("tier command = %q, instructions = %q", tier.Command, tier.Instructions)
+	}
+
+	if unknown := plan.Steps[2]; unknown.Action != ActionManual || unknown.CheckID != "" {
+		t.Errorf("unregistered field step = %+v", unknown)
+	}
+	again := r.BuildPlan(rep)
+	for i, step := range plan.Steps {
+		if step.ID == "" || step.ID != again.Steps[i].ID {
+			t.Errorf("step %d ID %q is not stable (%q)", i, step.ID, again.Steps[i].ID)
+		}
+	}
+
+	if network := plan.Steps[4]; network.Action != ActionRecreate || network.Risk != RiskHigh {
+		t.Errorf("immutable step = %+v", network)
+	}
+}
@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Range accepts a numeric field within bounds or within a tolerance of the
+// baseline value instead of requiring an exact match, e.g. for disks grown
+// by autoresize
+type Range struct {
+	// Min and Max bound the value; either may be omitted
+	Min *float64 `yaml:"min,omitempty" json:"min,omitempty"`
+	Max *float64 `yaml:"max,omitempty" json:"max,omitempty"`
+	// Tolerance is the absolute deviation from the baseline value accepted
+	Tolerance float64 `yaml:"tolerance,omitempty" json:"tolerance,omitempty"`
+	// TolerancePercent is the deviation accepted as a percentage of the
+	// baseline value; the larger of both tolerances applies
+	TolerancePercent float64 `yaml:"tolerance_percent,omitempty" json:"tolerance_percent,omitempty"`
+}
+
+// Ranges maps numeric drift fields to their accepted range. Keys follow the
+// same rules as Allowed.
+type Ranges map[string]Range
+
+// Validate checks that every key names a registered check path and its range
+// is consistent
+func (r Ranges) Validate() error {
+	for key, rng := range r {
+		if !defaultRegistry.hasPath(key) {
+			return fmt.Errorf("ranges.%s: unknown field; run 'checks list' for valid paths", key)
+		}
+		if err := rng.validate(); err != nil {
+			return fmt.Errorf("ranges.%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Describe renders the values accepted for a field around the expected
+// value, e.g. "between 100 and 500", when the field has a range
+func (r Ranges) Describe(field string, expected float64) (string, bool) {
+	rng, ok := lookupField(r, field)
+	if !ok {
+		return "", false
+	}
+	return rng.describe(expected), true
+}
+
+// validate checks that the range sets bounds or a tolerance, but not both
+func (r Range) validate() error {
+	bounded := r.Min != nil || r.Max != nil
+	tolerant := r.Tolerance != 0 || r.TolerancePercent != 0
+	switch {
+	case !bounded && !tolerant:
+		return fmt.Errorf("set min, max, tolerance or tolerance_percent")
+	case bounded && tolerant:
+		return fmt.Errorf("min/max cannot be combined with a tolerance")
+	case r.Min != nil && r.Max != nil && *r.Min > *r.Max:
+		return fmt.Errorf("min %s is greater than max %s", formatNumber(*r.Min), formatNumber(*r.Max))
+	case r.Tolerance < 0 || r.TolerancePercent < 0:
+		return fmt.Errorf("tolerance must not be negative")
+	}
+	return nil
+}
+
+// describe renders the accepted values for the Expected column
+func (r Range) describe(expected float64) string {
+	switch {
+	case r.Min != nil && r.Max != nil:
+		return fmt.Sprintf("between %s and %s", formatNumber(*r.Min), formatNumber(*r.Max))
+	case r.Min != nil:
+		return ">= " + formatNumber(*r.Min)
+	case r.Max != nil:
+		return "<= " + formatNumber(*r.Max)
+	}
+	return fmt.Sprintf("%s ±%s", formatNumber(expected), formatNumber(r.tolerance(expected)))
+}
+
+// tolerance returns the deviation accepted around expected
+func (r Range) tolerance(expected float64) float64 {
+	return math.Max(r.Tolerance, math.Abs(expected)*r.TolerancePercent/100)
+}
+
+// accepts reports whether actual is in range. A tolerance needs a positive
+// baseline value; without one every value is accepted, as with Int.
+func (r Range) accepts(expected, actual float64) bool {
+	if r.Min != nil || r.Max != nil {
+		return (r.Min == nil || actual >= *r.Min) && (r.Max == nil || actual <= *r.Max)
+	}
+	if expected <= 0 {
+		return true
+	}
+	return math.Abs(actual-expected) <= r.tolerance(expected)
+}
+
+// Number appends a drift when actual is outside the range configured for
+// the field. Without a range it falls back to Int with the expected value.
+func (c *Check) Number(drifts []report.Drift, ranges Ranges, expected, actual int64) []report.Drift {
+	rng, ok := lookupField(ranges, c.Path)
+	if !ok {
+		return c.Int(drifts, expected, actual)
+	}
+	if rng.accepts(float64(expected), float64(actual)) {
+		return drifts
+	}
+	return c.Append(drifts, rng.describe(float64(expected)), fmt.Sprintf("%d", actual))
+}
+
+// formatNumber renders a number without a trailing fraction when it is whole
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
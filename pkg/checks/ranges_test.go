@@ -0,0 +1,71 @@
+package checks
+
+import "testing"
+
+func float(v float64) *float64 { return &v }
+
+func TestNumber(t *testing.T) {
+	c := &Check{ID: "x", ResourceType: "X", Path: "pool[*].disk_size_gb", Severity: "medium"}
+
+	tests := []struct {
+		name     string
+		ranges   Ranges
+		expected int64
+		actual   int64
+		want     string
+	}{
+		{"no range, equal", nil, 100, 100, ""},
+		{"no range, different", nil, 100, 120, "100"},
+		{"within bounds", Ranges{"pool[*].disk_size_gb": {Min: float(100), Max: float(500)}}, 100, 250, ""},
+		{"above max", Ranges{"pool[*].disk_size_gb": {Min: float(100), Max: float(500)}}, 100, 600, "between 100 and 500"},
+		{"below min", Ranges{"pool[*].disk_size_gb": {Min: float(100)}}, 100, 50, ">= 100"},
+		{"above max only", Ranges{"pool[*].disk_size_gb": {Max: float(200)}}, 100, 300, "<= 200"},
+		{"within tolerance", Ranges{"pool[*].disk_size_gb": {Tolerance: 10}}, 100, 110, ""},
+		{"outside tolerance", Ranges{"pool[*].disk_size_gb": {Tolerance: 10}}, 100, 111, "100 ±10"},
+		{"percent tolerance wins", Ranges{"pool[*].disk_size_gb": {Tolerance: 5, TolerancePercent: 20}}, 100, 120, ""},
+		{"outside percent tolerance", Ranges{"pool[*].disk_size_gb": {TolerancePercent: 10}}, 200, 230, "200 ±20"},
+		{"tolerance without baseline value", Ranges{"pool[*].disk_size_gb": {Tolerance: 10}}, 0, 500, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drifts := c.At("a").Number(nil, tt.ranges, tt.expected, tt.actual)
+			if tt.want == "" {
+				if len(drifts) != 0 {
+					t.Errorf("expected no drift, got %+v", drifts)
+				}
+				return
+			}
+			if len(drifts) != 1 || drifts[0].Expected != tt.want {
+				t.Errorf("drifts = %+v, want expected %q", drifts, tt.want)
+			}
+		})
+	}
+}
+
+func TestRangeValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rng     Range
+		wantErr bool
+	}{
+		{"bounds", Range{Min: float(1), Max: float(2)}, false},
+		{"tolerance", Range{Tolerance: 1, TolerancePercent: 5}, false},
+		{"empty", Range{}, true},
+		{"bounds and tolerance", Range{Min: float(1), Tolerance: 1}, true},
+		{"min above max", Range{Min: float(3), Max: float(2)}, true},
+		{"negative tolerance", Range{Tolerance: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rng.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (Ranges{"no_such_field": {Tolerance: 1}}).Validate(); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,234 @@
+// Package checks provides the registry of field comparisons shared by the
+// resource analyzers. Each analyzer registers its checks with metadata (field
+// path, default severity, description) and uses them to build drifts, so the
+// full list of checks can be documented from a single source.
+package checks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Severities in increasing order of impact
+var Severities = []string{"low", "medium", "high", "critical"}
+
+// Check describes a single drift comparison performed by an analyzer
+type Check struct {
+	ID           string `json:"id" yaml:"id"`
+	ResourceType string `json:"resource_type" yaml:"resource_type"`
+	// Path is the field path reported in drifts; "*" stands for a map key or node pool name
+	Path        string `json:"path" yaml:"path"`
+	Severity    string `json:"severity" yaml:"severity"`
+	Description string `json:"description" yaml:"description"`
+	// Remediation is a hint for bringing the resource back in line with the baseline
+	Remediation string `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	Immutable   bool   `json:"immutable,omitempty" yaml:"immutable,omitempty"`
+	// RequiresRestart marks fixes that restart the instance or recreate nodes
+	RequiresRestart bool `json:"requires_restart,omitempty" yaml:"requires_restart,omitempty"`
+	// TerraformResource and TerraformAttribute locate the checked setting in
+	// the Terraform Google provider, e.g. google_sql_database_instance and
+	// settings.tier; "*" in the attribute stands for the drifted map key
+	TerraformResource  string `json:"terraform_resource,omitempty" yaml:"terraform_resource,omitempty"`
+	TerraformAttribute string `json:"terraform_attribute,omitempty" yaml:"terraform_attribute,omitempty"`
+}
+
+// WithTerraform records where the Terraform Google provider sets the value
+// the check compares. It is called on the result of Register during package
+// initialization.
+func (c *Check) WithTerraform(resource, attribute string) *Check {
+	c.TerraformResource = resource
+	c.TerraformAttribute = attribute
+	return c
+}
+
+// Registry holds registered checks keyed by ID
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]*Check
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{checks: make(map[string]*Check)}
+}
+
+// Register adds a check to the registry and returns it. Registration happens
+// at package initialization, so invalid or duplicate checks panic.
+func (r *Registry) Register(c Check) *Check {
+	if c.ID == "" || c.Path == "" || c.ResourceType == "" {
+		panic(fmt.Sprintf("checks: incomplete check %+v", c))
+	}
+	if !validSeverity(c.Severity) {
+		panic(fmt.Sprintf("checks: check %s has invalid severity %q", c.ID, c.Severity))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.checks[c.ID]; exists {
+		panic(fmt.Sprintf("checks: duplicate check %s", c.ID))
+	}
+	r.checks[c.ID] = &c
+	return &c
+}
+
+// Get returns the check with the given ID
+func (r *Registry) Get(id string) (*Check, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.checks[id]
+	return c, ok
+}
+
+// Find looks up a check by ID or by a reported drift field such as
+// "nodepool[default-pool].machine_type". Several checks may report the same
+// field (e.g. missing and extra entries); all of them are returned.
+func (r *Registry) Find(ref string) []*Check {
+	if c, ok := r.Get(ref); ok {
+		return []*Check{c}
+	}
+
+	var found []*Check
+	for _, c := range r.All() {
+		if matchPath(c.Path, ref) {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+// ForDrift returns the check that reported a drift on field for a resource
+// type. When several checks share the field, the one whose default severity
+// matches the drift is preferred.
+func (r *Registry) ForDrift(resourceType, field, severity string) (*Check, bool) {
+	var found *Check
+	for _, c := range r.All() {
+		if c.ResourceType != resourceType || !matchPath(c.Path, field) {
+			continue
+		}
+		if c.Severity == severity {
+			return c, true
+		}
+		if found == nil {
+			found = c
+		}
+	}
+	return found, found != nil
+}
+
+// ForField returns the check reporting a field of a resource type. When
+// several checks share the field, the one with the shortest ID is preferred:
+// the main check rather than variants such as ".extra".
+func (r *Registry) ForField(resourceType, field string) (*Check, bool) {
+	var found *Check
+	for _, c := range r.All() {
+		if c.ResourceType != resourceType || !matchPath(c.Path, field) {
+			continue
+		}
+		if found == nil || len(c.ID) < len(found.ID) {
+			found = c
+		}
+	}
+	return found, found != nil
+}
+
+// All returns every registered check, sorted by ID
+func (r *Registry) All() []*Check {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]*Check, 0, len(r.checks))
+	for _, c := range r.checks {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ID < all[j].ID
+	})
+	return all
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a check to the default registry
+func Register(c Check) *Check {
+	return defaultRegistry.Register(c)
+}
+
+// Get returns a check from the default registry
+func Get(id string) (*Check, bool) {
+	return defaultRegistry.Get(id)
+}
+
+// Find looks up checks in the default registry by ID or drift field
+func Find(ref string) []*Check {
+	return defaultRegistry.Find(ref)
+}
+
+// ForDrift looks up the check behind a drift in the default registry
+func ForDrift(resourceType, field, severity string) (*Check, bool) {
+	return defaultRegistry.ForDrift(resourceType, field, severity)
+}
+
+// ForField looks up the check reporting a field in the default registry
+func ForField(resourceType, field string) (*Check, bool) {
+	return defaultRegistry.ForField(resourceType, field)
+}
+
+// All returns every check in the default registry, sorted by ID
+func All() []*Check {
+	return defaultRegistry.All()
+}
+
+// matchPath reports whether field matches path, where each "*" in path
+// matches a non-empty key without "]", e.g. "nodepool[*].labels[*]" matches
+// "nodepool[gpu].labels[team]" but "nodepool[*]" does not
+func matchPath(path, field string) bool {
+	prefix, rest, wildcard := strings.Cut(path, "*")
+	if !wildcard {
+		return path == field
+	}
+	if !strings.HasPrefix(field, prefix) {
+		return false
+	}
+	field = field[len(prefix):]
+	for i := 1; i <= len(field) && field[i-1] != ']'; i++ {
+		if matchPath(rest, field[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathKeys returns the keys the "*" wildcards of path match in field, or nil
+// when field doesn't match
+func pathKeys(path, field string) []string {
+	prefix, rest, wildcard := strings.Cut(path, "*")
+	if !wildcard {
+		if path == field {
+			return []string{}
+		}
+		return nil
+	}
+	if !strings.HasPrefix(field, prefix) {
+		return nil
+	}
+	field = field[len(prefix):]
+	for i := 1; i <= len(field) && field[i-1] != ']'; i++ {
+		if keys := pathKeys(rest, field[i:]); keys != nil {
+			return append([]string{field[:i]}, keys...)
+		}
+	}
+	return nil
+}
+
+func validSeverity(severity string) bool {
+	for _, s := range Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// valuePlaceholder matches a flag whose value is an upper-case placeholder,
+// e.g. --tier=TIER, --availability-type=REGIONAL|ZONAL or, for map
+// settings, --update-labels=KEY=VALUE
+var valuePlaceholder = regexp.MustCompile(`(--[a-z0-9-]+=)(KEY=)?([A-Z][A-Z0-9_|:]*)(\s|$)`)
+
+// switchFlag matches a trailing flag without a value, e.g. --enable-autorepair
+var switchFlag = regexp.MustCompile(`(\s)--([a-z0-9-]+)$`)
+
+// shellSafe matches values that need no quoting on a command line
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9._:/@%+=,-]+$`)
+
+// AnnotateRemediation sets the remediation of every drift in the report,
+// using the default registry
+func AnnotateRemediation(r *report.Report) {
+	defaultRegistry.AnnotateRemediation(r)
+}
+
+// AnnotateRemediation sets the remediation of every drift in the report: the
+// command of its check with the resource and, where the check compares the
+// setting itself, the expected value filled in, and the Terraform attribute
+// change. Settings fixed at creation only get instructions.
+func (reg *Registry) AnnotateRemediation(r *report.Report) {
+	for i := range r.Resources {
+		res := r.Resources[i]
+		for j := range res.Drifts {
+			d := &res.Drifts[j]
+			c, ok := reg.ForDrift(res.Type, d.Field, d.Severity)
+			if !ok {
+				continue
+			}
+			d.Remediation = c.remediation(res, *d)
+		}
+	}
+}
+
+// remediation builds the fix for a drift this check reported
+func (c *Check) remediation(res report.Resource, d report.Drift) *report.Remediation {
+	fix := &report.Remediation{}
+	keys := pathKeys(c.Path, d.Field)
+	if c.Immutable || d.Immutable || !strings.HasPrefix(c.Remediation, "gcloud ") {
+		fix.Instructions = c.Remediation
+	} else {
+		fix.Command, fix.Instructions = remediationCommand(c.Remediation, res, d.Field)
+		if c.TerraformAttribute != "" {
+			fix.Command = fillValue(fix.Command, keys, d.Expected)
+		}
+	}
+	fix.Terraform = c.terraformDiff(res, d, keys)
+
+	if fix.Command == "" && fix.Terraform == "" && fix.Instructions == "" {
+		return nil
+	}
+	return fix
+}
+
+// fillValue puts the expected value into a command whose only placeholder is
+// the value of the checked setting. Switches are negated when the expected
+// value is false, as gcloud spells --enable-x as --no-enable-x.
+func fillValue(command string, keys []string, expected string) string {
+	if !concreteValue(expected) {
+		return command
+	}
+	if expected == "true" || expected == "false" {
+		if expected == "false" && !valuePlaceholder.MatchString(command) {
+			command = switchFlag.ReplaceAllString(command, "$1--no-$2")
+		}
+		return command
+	}
+
+	if len(valuePlaceholder.FindAllString(command, -1)) != 1 {
+		return command
+	}
+	return valuePlaceholder.ReplaceAllStringFunc(command, func(flag string) string {
+		m := valuePlaceholder.FindStringSubmatch(flag)
+		value := shellQuote(expected)
+		if m[2] != "" {
+			if len(keys) == 0 {
+				return flag
+			}
+			value = shellQuote(keys[len(keys)-1] + "=" + expected)
+		}
+		return m[1] + value + m[4]
+	})
+}
+
+// terraformDiff renders the change of the Terraform attribute behind the
+// check, e.g. google_sql_database_instance.orders: settings.tier = "db-f1-micro" -> "db-custom-2-7680"
+func (c *Check) terraformDiff(res report.Resource, d report.Drift, keys []string) string {
+	if c.TerraformResource == "" || c.TerraformAttribute == "" || !concreteValue(d.Expected) {
+		return ""
+	}
+	name := res.Name
+	// Node pools are separate resources named after the pool
+	if m := nodePoolKey.FindStringSubmatch(d.Field); m != nil && c.TerraformResource == "google_container_node_pool" {
+		name = m[1]
+	}
+	attribute := c.TerraformAttribute
+	if len(keys) > 0 {
+		attribute = strings.Replace(attribute, "*", keys[len(keys)-1], 1)
+	}
+	return fmt.Sprintf("%s.%s: %s = %s -> %s", c.TerraformResource, name, attribute, terraformValue(d.Actual), terraformValue(d.Expected))
+}
+
+// concreteValue reports whether an expected value is a setting's value rather
+// than a description such as "one of [a b]" or a range
+func concreteValue(expected string) bool {
+	return expected != "" && !strings.ContainsAny(expected, " []<>")
+}
+
+// terraformValue renders a drift value as an HCL literal
+func terraformValue(value string) string {
+	if value == "" {
+		return "null"
+	}
+	if value == "true" || value == "false" {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// shellQuote quotes a value for a POSIX shell unless it is safe as is
+func shellQuote(value string) string {
+	if shellSafe.MatchString(value) {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,144 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestAnnotateRemediation(t *testing.T) {
+	r := NewRegistry()
+	r.Register(Check{ID: "sql.tier", ResourceType: "Cloud SQL", Path: "tier", Severity: "high",
+		Remediation: "gcloud sql instances patch INSTANCE --tier=TIER (restarts the instance)"}).
+		WithTerraform("google_sql_database_instance", "settings.tier")
+	r.Register(Check{ID: "sql.labels", ResourceType: "Cloud SQL", Path: "labels.*", Severity: "low",
+		Remediation: "gcloud sql instances patch INSTANCE --update-labels=KEY=VALUE"}).
+		WithTerraform("google_sql_database_instance", `settings.user_labels["*"]`)
+	r.Register(Check{ID: "sql.capacity", ResourceType: "Cloud SQL", Path: "capacity.max_vcpus", Severity: "medium",
+		Remediation: "gcloud sql instances patch INSTANCE --tier=TIER with a smaller tier, or agree a higher cap"})
+	r.Register(Check{ID: "gke.network", ResourceType: "GKE Cluster", Path: "cluster.network", Severity: "high",
+		Remediation: "Create a replacement cluster", Immutable: true}).
+		WithTerraform("google_container_cluster", "network")
+	r.Register(Check{ID: "gke.nodepool.auto_repair", ResourceType: "GKE Cluster", Path: "nodepool[*].auto_repair", Severity: "high",
+		Remediation: "gcloud container node-pools update POOL --cluster=CLUSTER --enable-autorepair"}).
+		WithTerraform("google_container_node_pool", "management.auto_repair")
+	r.Register(Check{ID: "gke.nodepool.machine_type", ResourceType: "GKE Cluster", Path: "nodepool[*].machine_type", Severity: "high",
+		Remediation: "Create a node pool with --machine-type=TYPE, cordon and drain the old pool, then delete it"}).
+		WithTerraform("google_container_node_pool", "node_config.machine_type")
+
+	rep := &report.Report{
+		Resources: []report.Resource{
+			{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []report.Drift{
+				{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-custom-2-8192", Severity: "high"},
+				{Field: "labels.team", Expected: "payments", Actual: "", Severity: "low"},
+				{Field: "capacity.max_vcpus", Expected: "16", Actual: "24", Severity: "medium"},
+				{Field: "tier", Expected: "one of [db-f1-micro db-g1-small]", Actual: "db-custom-2-8192", Severity: "high"},
+				{Field: "database_flags.unknown", Expected: "on", Actual: "off", Severity: "low"},
+			}},
+			{Type: "GKE Cluster", Project: "p", Name: "c1", Location: "us-central1", Drifts: []report.Drift{
+				{Field: "cluster.network", Expected: "vpc-a", Actual: "vpc-b", Severity: "high"},
+				{Field: "nodepool[pool-a].auto_repair", Expected: "false", Actual: "true", Severity: "high"},
+				{Field: "nodepool[pool-a].machine_type", Expected: "e2-standard-8", Actual: "e2-standard-4", Severity: "high"},
+			}},
+		},
+	}
+	r.AnnotateRemediation(rep)
+
+	tests := []struct {
+		name     string
+		drift    report.Drift
+		want     report.Remediation
+		wantNone bool
+	}{
+		{
+			name:  "value filled in",
+			drift: rep.Resources[0].Drifts[0],
+			want: report.Remediation{
+				Command:      "gcloud sql instances patch db-1 --project=p --tier=db-custom-4-16384",
+				Terraform:    `google_sql_database_instance.db-1: settings.tier = "db-custom-2-8192" -> "db-custom-4-16384"`,
+				Instructions: "restarts the instance",
+			},
+		},
+		{
+			name:  "map key",
+			drift: rep.Resources[0].Drifts[1],
+			want: report.Remediation{
+				Command:   "gcloud sql instances patch db-1 --project=p --update-labels=team=payments",
+				Terraform: `google_sql_database_instance.db-1: settings.user_labels["team"] = null -> "payments"`,
+			},
+		},
+		{
+			name:  "not the setting's value",
+			drift: rep.Resources[0].Drifts[2],
+			want: report.Remediation{
+				Command: "gcloud sql instances patch db-1 --project=p --tier=TIER with a smaller tier, or agree a higher cap",
+			},
+		},
+		{
+			name:  "allowed values",
+			drift: rep.Resources[0].Drifts[3],
+			want: report.Remediation{
+				Command:      "gcloud sql instances patch db-1 --project=p --tier=TIER",
+				Instructions: "restarts the instance",
+			},
+		},
+		{
+			name:     "unregistered field",
+			drift:    rep.Resources[0].Drifts[4],
+			wantNone: true,
+		},
+		{
+			name:  "immutable",
+			drift: rep.Resources[1].Drifts[0],
+			want: report.Remediation{
+				Terraform:    `google_container_cluster.c1: network = "vpc-b" -> "vpc-a"`,
+				Instructions: "Create a replacement cluster",
+			},
+		},
+		{
+			name:  "switch turned off",
+			drift: rep.Resources[1].Drifts[1],
+			want: report.Remediation{
+				Command:   "gcloud container node-pools update pool-a --cluster=c1 --location=us-central1 --project=p --no-enable-autorepair",
+				Terraform: "google_container_node_pool.pool-a: management.auto_repair = true -> false",
+			},
+		},
+		{
+			name:  "manual with terraform",
+			drift: rep.Resources[1].Drifts[2],
+			want: report.Remediation{
+				Terraform:    `google_container_node_pool.pool-a: node_config.machine_type = "e2-standard-4" -> "e2-standard-8"`,
+				Instructions: "Create a node pool with --machine-type=TYPE, cordon and drain the old pool, then delete it",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantNone {
+				if tt.drift.Remediation != nil {
+					t.Errorf("remediation = %+v, want none", tt.drift.Remediation)
+				}
+				return
+			}
+			if tt.drift.Remediation == nil {
+				t.Fatal("remediation not set")
+			}
+			if *tt.drift.Remediation != tt.want {
+				t.Errorf("remediation = %+v, want %+v", *tt.drift.Remediation, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	for value, want := range map[string]string{
+		"db-custom-4-16384": "db-custom-4-16384",
+		"10.0.0.0/8":        "10.0.0.0/8",
+		"it's":              `'it'\''s'`,
+		"a b":               "'a b'",
+	} {
+		if got := shellQuote(value); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package checks
+
+import (
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// SeverityOverrides replaces the built-in severity of drift fields, e.g. to treat
+// "tier" as low in a dev baseline and critical in a prod one. Keys follow the
+// same rules as Allowed.
+type SeverityOverrides map[string]string
+
+// Severity returns the override for a field, preferring an exact key over a
+// wildcard key
+func (s SeverityOverrides) Severity(field string) (string, bool) {
+	return lookupField(s, field)
+}
+
+// Validate checks that every key names a registered check path and every
+// value is a known severity
+func (s SeverityOverrides) Validate() error {
+	for key, severity := range s {
+		if report.SeverityRank(severity) == 0 {
+			return fmt.Errorf("severity_overrides.%s: invalid severity %q (critical|high|medium|low)", key, severity)
+		}
+		if !defaultRegistry.hasPath(key) {
+			return fmt.Errorf("severity_overrides.%s: unknown field; run 'checks list' for valid paths", key)
+		}
+	}
+	return nil
+}
+
+// Apply replaces the severity of every drift whose field has an override
+func (s SeverityOverrides) Apply(drifts []report.Drift) {
+	if len(s) == 0 {
+		return
+	}
+	for i := range drifts {
+		if severity, ok := s.Severity(drifts[i].Field); ok {
+			drifts[i].Severity = severity
+		}
+	}
+}
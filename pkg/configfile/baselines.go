@@ -0,0 +1,134 @@
+package configfile
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultsKey is the top-level key holding the defaults of each baseline list
+const defaultsKey = "defaults"
+
+// extendsKey names the baseline another baseline inherits from
+const extendsKey = "extends"
+
+// baselinesSuffix marks the top-level keys listing baselines, such as
+// sql_baselines
+const baselinesSuffix = "_baselines"
+
+// applyBaselineDefaults removes the defaults key from a top-level mapping and
+// resolves the baselines of every list: a baseline with extends inherits the
+// baseline of that name in the same list, any other baseline the list's
+// defaults. The baseline's own values win; mappings merge key by key and any
+// other value, including lists, replaces the inherited one.
+func applyBaselineDefaults(root *yaml.Node, path string) error {
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var defaults *yaml.Node
+	for i := 0; i < len(root.Content); i += 2 {
+		if root.Content[i].Value == defaultsKey {
+			defaults = root.Content[i+1]
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+			break
+		}
+	}
+	if defaults != nil {
+		if defaults.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s line %d: %s must map baseline lists to their defaults", path, defaults.Line, defaultsKey)
+		}
+		for i := 0; i < len(defaults.Content); i += 2 {
+			key, value := defaults.Content[i], defaults.Content[i+1]
+			if !strings.HasSuffix(key.Value, baselinesSuffix) {
+				return fmt.Errorf("%s line %d: %s.%s is not a baseline list such as sql_baselines", path, key.Line, defaultsKey, key.Value)
+			}
+			if value.Kind != yaml.MappingNode {
+				return fmt.Errorf("%s line %d: %s.%s must be a baseline", path, value.Line, defaultsKey, key.Value)
+			}
+		}
+	}
+
+	for i := 0; i < len(root.Content); i += 2 {
+		key, list := root.Content[i].Value, root.Content[i+1]
+		if !strings.HasSuffix(key, baselinesSuffix) || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		var listDefaults *yaml.Node
+		if defaults != nil {
+			listDefaults = lookup(defaults, key)
+		}
+		if err := resolveBaselines(list, listDefaults, key, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveBaselines applies inheritance to the baselines of one list
+func resolveBaselines(list, defaults *yaml.Node, listKey, path string) error {
+	byName := make(map[string]int)
+	for i, item := range list.Content {
+		if name := lookup(item, "name"); item.Kind == yaml.MappingNode && name != nil {
+			byName[name.Value] = i
+		}
+	}
+
+	resolved := make(map[int]bool)
+	resolving := make(map[int]bool)
+	var resolve func(i int) error
+	resolve = func(i int) error {
+		item := list.Content[i]
+		if resolved[i] || item.Kind != yaml.MappingNode {
+			return nil
+		}
+		if resolving[i] {
+			return fmt.Errorf("%s line %d: %s: extends cycle", path, item.Line, listKey)
+		}
+		resolving[i] = true
+
+		var base *yaml.Node
+		parent := removeKey(item, extendsKey)
+		switch {
+		case parent != nil:
+			j, ok := byName[parent.Value]
+			if !ok || parent.Kind != yaml.ScalarNode {
+				return fmt.Errorf("%s line %d: %s: extends unknown baseline %q", path, parent.Line, listKey, parent.Value)
+			}
+			if err := resolve(j); err != nil {
+				return err
+			}
+			base = copyNode(list.Content[j])
+			// The name identifies the baseline and is never inherited
+			removeKey(base, "name")
+		case defaults != nil:
+			base = copyNode(defaults)
+		}
+		if base != nil {
+			overlay(base, item)
+			list.Content[i] = base
+		}
+		resolved[i] = true
+		return nil
+	}
+
+	for i := range list.Content {
+		if err := resolve(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeKey deletes a key from a mapping and returns its value, or nil
+func removeKey(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value := mapping.Content[i+1]
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,412 @@
+// Package configfile loads the YAML config file, resolving file includes and
+// anchors into a single document before it is decoded.
+//
+// Two include mechanisms are supported, with paths relative to the including
+// file:
+//
+//	include: [sql-baselines.yaml, gke-baselines.yaml]  # merged into the file
+//	flags: !include flags/prod.yaml                    # replaces the value
+//
+// Files listed under the top-level include (or includes) key are merged in
+// order before the including file: mappings merge key by key, sequences are
+// concatenated and the including file's scalars win. Anchors and aliases are
+// expanded, and top-level keys starting with "x-" are dropped, so anchor
+// definitions can be kept out of the way of config validation:
+//
+//	x-prod-flags: &prod-flags
+//	  - name: max_connections
+//	    value: "500"
+//
+// A workspace file can hold several environments under the top-level profiles
+// key. Selecting a profile overlays its keys on the rest of the file:
+// mappings merge key by key and any other value, including sequences,
+// replaces the top-level one. Without a selected profile the profiles key is
+// ignored.
+//
+//	projects: [shop-staging]
+//	profiles:
+//	  prod:
+//	    projects: [shop-prod]
+//	    output:
+//	      report_dir: reports/prod
+//
+// Baselines inherit common settings instead of repeating them. The top-level
+// defaults key holds a baseline per list that every baseline of the list is
+// merged onto, and a baseline setting extends inherits the named baseline of
+// its list instead. The baseline's own values win: mappings merge key by key
+// and any other value, including lists, replaces the inherited one.
+//
+//	defaults:
+//	  sql_baselines:
+//	    config:
+//	      settings: {backup_enabled: true, ssl_mode: ENCRYPTED_ONLY}
+//	sql_baselines:
+//	  - name: oltp
+//	    config: {tier: db-custom-8-32768}
+//	  - name: oltp-eu
+//	    extends: oltp
+//	    filter_labels: {region: eu}
+//
+// Values may reference environment variables as ${VAR}, or ${VAR:-default}
+// to fall back when VAR is unset or empty; $${ is a literal ${. Variables are
+// substituted after includes and the profile are resolved, and an unset
+// variable without a default is an error.
+//
+//	projects: ["${GCP_PROJECT}"]
+//	report_bucket: gs://${REPORT_BUCKET:-drift-reports}
+package configfile
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeKey is the top-level key listing files merged into a config
+const includeKey = "include"
+
+// includesKey is accepted as a spelling of includeKey
+const includesKey = "includes"
+
+// includeTag replaces a value with the content of a file
+const includeTag = "!include"
+
+// extensionPrefix marks top-level keys that only hold anchors
+const extensionPrefix = "x-"
+
+// profilesKey is the top-level key holding the named profiles of a workspace
+const profilesKey = "profiles"
+
+// Read loads the config file at path and returns it as a single YAML document
+// with includes resolved and anchors expanded. An error reading path itself
+// wraps the os error, so callers can test for fs.ErrNotExist.
+func Read(path string) ([]byte, error) {
+	return ReadProfile(path, "")
+}
+
+// ReadProfile loads the config file at path like Read, with the named
+// profile overlaid on its top-level keys; an empty profile selects none
+func ReadProfile(path, profile string) ([]byte, error) {
+	root, err := load(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		if profile != "" {
+			return nil, fmt.Errorf("profile %q is not defined in %s", profile, path)
+		}
+		return nil, nil
+	}
+	dropExtensions(root)
+	if err := applyProfile(root, profile, path); err != nil {
+		return nil, err
+	}
+	if err := applyBaselineDefaults(root, path); err != nil {
+		return nil, err
+	}
+	// After the profile is applied, so only variables of the selected
+	// environment need to be set
+	if err := expandEnv(root, path); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// load parses the file at path and resolves its includes. stack holds the
+// files being loaded, to detect include cycles. It returns nil for an empty
+// file.
+func load(path string, stack []string) (*yaml.Node, error) {
+	for _, parent := range stack {
+		if parent == path {
+			return nil, fmt.Errorf("include cycle: %s -> %s", strings.Join(stack, " -> "), path)
+		}
+	}
+	stack = append(stack, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if len(stack) > 1 {
+			// Not wrapped: a missing include is an error even where a missing
+			// config file is not
+			return nil, fmt.Errorf("failed to read %s included from %s: %v", path, stack[len(stack)-2], err)
+		}
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+
+	// Aliases are expanded before included content is spliced in, so every
+	// anchor is resolved within the file that defines it
+	expandAliases(root)
+	resolveMerges(root)
+	dir := filepath.Dir(path)
+	if err := resolveTags(root, dir, stack); err != nil {
+		return nil, err
+	}
+	return mergeIncludes(root, dir, stack)
+}
+
+// expandAliases replaces every alias with a copy of its anchored node and
+// clears the anchors
+func expandAliases(n *yaml.Node) {
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		*n = *copyNode(n.Alias)
+	}
+	n.Anchor = ""
+	for _, child := range n.Content {
+		expandAliases(child)
+	}
+}
+
+// resolveMerges applies merge keys (<<: *anchor) so decoded configs only see
+// the keys they declare. Keys set in the mapping itself win over merged keys,
+// and earlier merged mappings win over later ones.
+func resolveMerges(n *yaml.Node) {
+	for _, child := range n.Content {
+		resolveMerges(child)
+	}
+	if n.Kind != yaml.MappingNode {
+		return
+	}
+
+	var sources []*yaml.Node
+	content := make([]*yaml.Node, 0, len(n.Content))
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if key.Kind != yaml.ScalarNode || key.Value != "<<" || key.Style != 0 {
+			content = append(content, key, value)
+			continue
+		}
+		switch value.Kind {
+		case yaml.MappingNode:
+			sources = append(sources, value)
+		case yaml.SequenceNode:
+			sources = append(sources, value.Content...)
+		}
+	}
+	if sources == nil {
+		return
+	}
+
+	n.Content = content
+	for _, source := range sources {
+		for i := 0; i+1 < len(source.Content); i += 2 {
+			if lookup(n, source.Content[i].Value) == nil {
+				n.Content = append(n.Content, source.Content[i], source.Content[i+1])
+			}
+		}
+	}
+}
+
+// copyNode deep-copies a node, following aliases
+func copyNode(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		return copyNode(n.Alias)
+	}
+	c := *n
+	c.Anchor = ""
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		c.Content[i] = copyNode(child)
+	}
+	return &c
+}
+
+// resolveTags replaces every value tagged !include with the referenced file
+func resolveTags(n *yaml.Node, dir string, stack []string) error {
+	if n.Tag == includeTag {
+		if n.Kind != yaml.ScalarNode || n.Value == "" {
+			return fmt.Errorf("%s line %d: %s expects a file path", stack[len(stack)-1], n.Line, includeTag)
+		}
+		included, err := load(resolvePath(dir, n.Value), stack)
+		if err != nil {
+			return err
+		}
+		if included == nil {
+			included = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		}
+		*n = *included
+		return nil
+	}
+	for _, child := range n.Content {
+		if err := resolveTags(child, dir, stack); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeIncludes removes the include key from a top-level mapping and merges
+// the listed files beneath it
+func mergeIncludes(root *yaml.Node, dir string, stack []string) (*yaml.Node, error) {
+	if root.Kind != yaml.MappingNode {
+		return root, nil
+	}
+
+	var paths []string
+	for i := 0; i < len(root.Content); i += 2 {
+		name := root.Content[i].Value
+		if name != includeKey && name != includesKey {
+			continue
+		}
+		value := root.Content[i+1]
+		switch value.Kind {
+		case yaml.ScalarNode:
+			paths = append(paths, value.Value)
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("%s line %d: %s must list file paths", stack[len(stack)-1], item.Line, name)
+				}
+				paths = append(paths, item.Value)
+			}
+		default:
+			return nil, fmt.Errorf("%s line %d: %s must be a file path or a list of them", stack[len(stack)-1], value.Line, name)
+		}
+		// Both spellings may be used; their files merge in file order
+		root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		i -= 2
+	}
+
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, p := range paths {
+		included, err := load(resolvePath(dir, p), stack)
+		if err != nil {
+			return nil, err
+		}
+		if included == nil {
+			continue
+		}
+		if included.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s: an included config must be a mapping", p)
+		}
+		merge(merged, included)
+	}
+	merge(merged, root)
+	return merged, nil
+}
+
+// merge merges src into dst: mappings merge key by key, sequences are
+// concatenated and any other src value replaces dst's
+func merge(dst, src *yaml.Node) {
+	for i := 0; i < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := lookup(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			merge(existing, value)
+		case existing.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			existing.Content = append(existing.Content, value.Content...)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+// lookup returns the value of key in a mapping, or nil
+func lookup(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// dropExtensions removes the top-level keys that only hold anchors
+func dropExtensions(root *yaml.Node) {
+	if root.Kind != yaml.MappingNode {
+		return
+	}
+	content := root.Content[:0]
+	for i := 0; i < len(root.Content); i += 2 {
+		if strings.HasPrefix(root.Content[i].Value, extensionPrefix) {
+			continue
+		}
+		content = append(content, root.Content[i], root.Content[i+1])
+	}
+	root.Content = content
+}
+
+// applyProfile removes the profiles key from a top-level mapping and
+// overlays the named profile
+func applyProfile(root *yaml.Node, name, path string) error {
+	var profiles *yaml.Node
+	if root.Kind == yaml.MappingNode {
+		for i := 0; i < len(root.Content); i += 2 {
+			if root.Content[i].Value == profilesKey {
+				profiles = root.Content[i+1]
+				root.Content = append(root.Content[:i], root.Content[i+2:]...)
+				break
+			}
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	if profiles == nil || profiles.Kind != yaml.MappingNode {
+		return fmt.Errorf("profile %q is not defined in %s", name, path)
+	}
+
+	profile := lookup(profiles, name)
+	if profile == nil {
+		var names []string
+		for i := 0; i < len(profiles.Content); i += 2 {
+			names = append(names, profiles.Content[i].Value)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("profile %q is not defined in %s (available: %s)", name, path, strings.Join(names, ", "))
+	}
+	if profile.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s line %d: profile %q must be a mapping", path, profile.Line, name)
+	}
+	overlay(root, profile)
+	return nil
+}
+
+// overlay applies src on top of dst: mappings merge key by key and any other
+// src value replaces dst's, so a profile's project list replaces the default
+// one instead of extending it
+func overlay(dst, src *yaml.Node) {
+	for i := 0; i < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		existing := lookup(dst, key.Value)
+		switch {
+		case existing == nil:
+			dst.Content = append(dst.Content, key, value)
+		case existing.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			overlay(existing, value)
+		default:
+			*existing = *value
+		}
+	}
+}
+
+// resolvePath resolves an include path relative to the including file
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,374 @@
+package configfile
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeFiles writes name → content files into a temporary directory
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+type testConfig struct {
+	Projects     []string `yaml:"projects"`
+	SQLBaselines []struct {
+		Name   string `yaml:"name"`
+		Tier   string `yaml:"tier"`
+		Region string `yaml:"region"`
+		Flags  []struct {
+			Name  string `yaml:"name"`
+			Value string `yaml:"value"`
+		} `yaml:"flags"`
+	} `yaml:"sql_baselines"`
+	Daemon struct {
+		Schedule string   `yaml:"schedule"`
+		Analyses []string `yaml:"analyses"`
+	} `yaml:"daemon"`
+}
+
+func TestRead(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `include: [common.yaml]
+x-defaults: &defaults
+  tier: db-custom-4-16384
+  region: us-central1
+projects: [shop-prod]
+sql_baselines:
+  - name: orders
+    <<: *defaults
+    flags: !include flags/prod.yaml
+  - name: reporting
+    <<: *defaults
+    tier: db-custom-2-8192
+daemon:
+  schedule: "@hourly"
+`,
+		"common.yaml": `projects: [shared]
+daemon:
+  schedule: "@daily"
+  analyses: [sql]
+`,
+		"flags/prod.yaml": `- name: max_connections
+  value: "500"
+`,
+	})
+
+	data, err := Read(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// Anchor holders and merge keys are gone, so strict decoding succeeds
+	var config testConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		t.Fatalf("strict decode error = %v\n%s", err, data)
+	}
+
+	if strings.Join(config.Projects, ",") != "shared,shop-prod" {
+		t.Errorf("projects = %v, want included projects first", config.Projects)
+	}
+	if config.Daemon.Schedule != "@hourly" || len(config.Daemon.Analyses) != 1 {
+		t.Errorf("daemon = %+v, want the including file's schedule and the included analyses", config.Daemon)
+	}
+	if len(config.SQLBaselines) != 2 {
+		t.Fatalf("sql_baselines = %d, want 2", len(config.SQLBaselines))
+	}
+	orders, reporting := config.SQLBaselines[0], config.SQLBaselines[1]
+	if orders.Tier != "db-custom-4-16384" || orders.Region != "us-central1" {
+		t.Errorf("orders = %+v, want the merged defaults", orders)
+	}
+	if len(orders.Flags) != 1 || orders.Flags[0].Value != "500" {
+		t.Errorf("orders flags = %+v, want the included flags", orders.Flags)
+	}
+	if reporting.Tier != "db-custom-2-8192" || reporting.Region != "us-central1" {
+		t.Errorf("reporting = %+v, want its own tier over the merged default", reporting)
+	}
+}
+
+func TestReadIncludesKey(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `includes: [sql-baselines.yaml]
+include: gke-baselines.yaml
+projects: [shop-prod]
+`,
+		"sql-baselines.yaml": "projects: [sql]\n",
+		"gke-baselines.yaml": "projects: [gke]\n",
+	})
+
+	data, err := Read(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string][]string
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(config["projects"], ","); got != "sql,gke,shop-prod" || len(config) != 1 {
+		t.Errorf("config = %v, want both include keys merged in order and removed", config)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"cycle-a.yaml":   "include: cycle-b.yaml\n",
+		"cycle-b.yaml":   "include: cycle-a.yaml\n",
+		"missing.yaml":   "include: [nowhere.yaml]\n",
+		"list.yaml":      "include: list-item.yaml\n",
+		"list-item.yaml": "- a\n",
+	})
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"cycle-a.yaml", "include cycle"},
+		{"missing.yaml", "nowhere.yaml included from"},
+		{"list.yaml", "must be a mapping"},
+	}
+	for _, tt := range tests {
+		_, err := Read(filepath.Join(dir, tt.file))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Read(%s) error = %v, want %q", tt.file, err, tt.want)
+		}
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Read(%s) error wraps fs.ErrNotExist", tt.file)
+		}
+	}
+
+	if _, err := Read(filepath.Join(dir, "absent.yaml")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Read() of a missing config error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestReadProfile(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"workspace.yaml": `include: [profiles.yaml]
+projects: [shop-staging, shop-dev]
+cache_dir: .drift-cache/staging
+daemon:
+  schedule: "@hourly"
+  analyses: [sql]
+`,
+		"profiles.yaml": `profiles:
+  prod:
+    projects: [shop-prod]
+    cache_dir: .drift-cache/prod
+    daemon:
+      analyses: [sql, gke]
+  staging: {}
+`,
+	})
+	path := filepath.Join(dir, "workspace.yaml")
+
+	decode := func(profile string) (testConfig, map[string]interface{}) {
+		t.Helper()
+		data, err := ReadProfile(path, profile)
+		if err != nil {
+			t.Fatalf("ReadProfile(%q) error = %v", profile, err)
+		}
+		var config testConfig
+		var keys map[string]interface{}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			t.Fatal(err)
+		}
+		if err := yaml.Unmarshal(data, &keys); err != nil {
+			t.Fatal(err)
+		}
+		return config, keys
+	}
+
+	prod, keys := decode("prod")
+	if strings.Join(prod.Projects, ",") != "shop-prod" {
+		t.Errorf("prod projects = %v, want the profile's list only", prod.Projects)
+	}
+	if keys["cache_dir"] != ".drift-cache/prod" {
+		t.Errorf("prod cache_dir = %v", keys["cache_dir"])
+	}
+	if prod.Daemon.Schedule != "@hourly" || strings.Join(prod.Daemon.Analyses, ",") != "sql,gke" {
+		t.Errorf("prod daemon = %+v, want the default schedule with the profile's analyses", prod.Daemon)
+	}
+	if _, found := keys["profiles"]; found {
+		t.Error("profiles key was not removed")
+	}
+
+	base, keys := decode("")
+	if strings.Join(base.Projects, ",") != "shop-staging,shop-dev" || keys["cache_dir"] != ".drift-cache/staging" {
+		t.Errorf("without a profile = %+v, %v", base.Projects, keys["cache_dir"])
+	}
+
+	_, err := ReadProfile(path, "qa")
+	if err == nil || !strings.Contains(err.Error(), "available: prod, staging") {
+		t.Errorf("ReadProfile(qa) error = %v, want the available profiles", err)
+	}
+}
+
+func TestReadEnv(t *testing.T) {
+	t.Setenv("DRIFT_TEST_PROJECT", "shop-prod")
+	t.Setenv("DRIFT_TEST_DISK", "200")
+	t.Setenv("DRIFT_TEST_EMPTY", "")
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `projects:
+  - "${DRIFT_TEST_PROJECT}"
+  - shop-${DRIFT_TEST_SUFFIX:-dev}
+disk_size_gb: ${DRIFT_TEST_DISK}
+quoted: "${DRIFT_TEST_DISK}"
+bucket: ${DRIFT_TEST_EMPTY:-drift-reports}
+literal: $${DRIFT_TEST_PROJECT}
+${DRIFT_TEST_KEY}: kept
+`,
+		"missing.yaml": `projects: ["${DRIFT_TEST_UNSET_B}", "${DRIFT_TEST_UNSET_A}"]
+profiles:
+  other:
+    password: ${DRIFT_TEST_UNSET_C}
+`,
+	})
+
+	data, err := Read(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"projects":          []interface{}{"shop-prod", "shop-dev"},
+		"disk_size_gb":      200,
+		"quoted":            "200",
+		"bucket":            "drift-reports",
+		"literal":           "${DRIFT_TEST_PROJECT}",
+		"${DRIFT_TEST_KEY}": "kept",
+	}
+	for key, value := range want {
+		if got := config[key]; !equalYAML(got, value) {
+			t.Errorf("%s = %#v, want %#v", key, got, value)
+		}
+	}
+
+	// Variables of unselected profiles need not be set
+	_, err = Read(filepath.Join(dir, "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "not set: DRIFT_TEST_UNSET_A, DRIFT_TEST_UNSET_B (") {
+		t.Errorf("Read() error = %v, want the unset variables", err)
+	}
+}
+
+func equalYAML(a, b interface{}) bool {
+	x, _ := yaml.Marshal(a)
+	y, _ := yaml.Marshal(b)
+	return bytes.Equal(x, y)
+}
+
+func TestReadBaselineDefaults(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `defaults:
+  sql_baselines:
+    filter_labels: {env: prod}
+    config:
+      tier: db-custom-4-16384
+      database_flags: {log_connections: "on"}
+      settings:
+        backup_enabled: true
+        ssl_mode: ENCRYPTED_ONLY
+sql_baselines:
+  - name: oltp
+    config:
+      tier: db-custom-8-32768
+      settings:
+        ssl_mode: TRUSTED_CLIENT_CERTIFICATE_REQUIRED
+  - name: oltp-eu
+    extends: oltp
+    filter_labels: {env: prod, region: eu}
+  - name: reporting
+    config:
+      required_databases: [reports]
+gke_baselines:
+  - name: prod
+    cluster_config: {release_channel: STABLE}
+`,
+		"cycle.yaml": `sql_baselines:
+  - {name: a, extends: b}
+  - {name: b, extends: a}
+`,
+		"unknown.yaml": `sql_baselines:
+  - {name: a, extends: missing}
+`,
+		"not-a-list.yaml": `defaults:
+  projects: [shop]
+`,
+	})
+
+	data, err := Read(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		Defaults     interface{} `yaml:"defaults"`
+		SQLBaselines []struct {
+			Name         string            `yaml:"name"`
+			Extends      string            `yaml:"extends"`
+			FilterLabels map[string]string `yaml:"filter_labels"`
+			Config       struct {
+				Tier              string            `yaml:"tier"`
+				DatabaseFlags     map[string]string `yaml:"database_flags"`
+				RequiredDatabases []string          `yaml:"required_databases"`
+				Settings          map[string]interface{}
+			} `yaml:"config"`
+		} `yaml:"sql_baselines"`
+		GKEBaselines []map[string]interface{} `yaml:"gke_baselines"`
+	}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Defaults != nil {
+		t.Errorf("defaults key was not removed")
+	}
+	if len(config.SQLBaselines) != 3 {
+		t.Fatalf("got %d SQL baselines, want 3", len(config.SQLBaselines))
+	}
+	oltp, eu, reporting := config.SQLBaselines[0], config.SQLBaselines[1], config.SQLBaselines[2]
+	if oltp.Config.Tier != "db-custom-8-32768" || oltp.Config.Settings["backup_enabled"] != true ||
+		oltp.Config.Settings["ssl_mode"] != "TRUSTED_CLIENT_CERTIFICATE_REQUIRED" || oltp.Config.DatabaseFlags["log_connections"] != "on" {
+		t.Errorf("oltp = %+v, want its overrides merged onto the defaults", oltp)
+	}
+	if eu.Name != "oltp-eu" || eu.Extends != "" || eu.Config.Tier != "db-custom-8-32768" ||
+		eu.Config.Settings["ssl_mode"] != "TRUSTED_CLIENT_CERTIFICATE_REQUIRED" || eu.FilterLabels["region"] != "eu" {
+		t.Errorf("oltp-eu = %+v, want oltp's resolved config under its own name", eu)
+	}
+	if reporting.Config.Tier != "db-custom-4-16384" || len(reporting.Config.RequiredDatabases) != 1 || reporting.FilterLabels["env"] != "prod" {
+		t.Errorf("reporting = %+v, want the defaults", reporting)
+	}
+	if len(config.GKEBaselines[0]) != 2 {
+		t.Errorf("gke baseline = %v, want no defaults from another list", config.GKEBaselines[0])
+	}
+
+	for file, want := range map[string]string{
+		"cycle.yaml":      "extends cycle",
+		"unknown.yaml":    `extends unknown baseline "missing"`,
+		"not-a-list.yaml": "defaults.projects is not a baseline list",
+	} {
+		if _, err := Read(filepath.Join(dir, file)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Read(%s) error = %v, want %q", file, err, want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package configfile
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPattern matches $${ escapes and ${VAR} or ${VAR:-default} references
+var envPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnv substitutes environment variables in every scalar value. Keys are
+// left alone. A plain scalar is re-resolved after substitution, so
+// `disk_size_gb: ${DISK_SIZE}` still decodes as a number; quoted scalars stay
+// strings. Variables that are unset and have no default are an error, listed
+// together.
+func expandEnv(n *yaml.Node, path string) error {
+	missing := make(map[string]bool)
+	expandNode(n, missing)
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%s: environment variables not set: %s (set them or use ${VAR:-default})", path, strings.Join(names, ", "))
+}
+
+func expandNode(n *yaml.Node, missing map[string]bool) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(n.Value, "${") {
+			return
+		}
+		n.Value = envPattern.ReplaceAllStringFunc(n.Value, func(ref string) string {
+			if ref == "$${" {
+				return "${"
+			}
+			m := envPattern.FindStringSubmatch(ref)
+			// As in the shell, the default also replaces an empty value
+			hasDefault := strings.Contains(ref, ":-")
+			if value, ok := os.LookupEnv(m[1]); ok && (value != "" || !hasDefault) {
+				return value
+			}
+			if hasDefault {
+				return m[2]
+			}
+			missing[m[1]] = true
+			return ""
+		})
+		if n.Style == 0 {
+			n.Tag = ""
+		}
+	case yaml.MappingNode:
+		// Only values; a key names a field and is never substituted
+		for i := 1; i < len(n.Content); i += 2 {
+			expandNode(n.Content[i], missing)
+		}
+	default:
+		for _, child := range n.Content {
+			expandNode(child, missing)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package configfile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Location is a line of a config file
+type Location struct {
+	File string
+	// Line is 1-based
+	Line int
+}
+
+// LocateBaseline finds the entry of the named baseline in a list such as
+// sql_baselines, in the config file at path or else in the files it
+// includes; the list may be nested, e.g. under a profile. When the entry
+// spells out the drifted field, such as database_flags.max_connections, the
+// location is the line of that setting, otherwise that of the entry. ok is
+// false when no file defines the baseline, e.g. when it only comes from
+// defaults.
+func LocateBaseline(path, list, name, field string) (Location, bool) {
+	return locateBaseline(path, list, name, field, make(map[string]bool))
+}
+
+func locateBaseline(path, list, name, field string, seen map[string]bool) (Location, bool) {
+	if seen[path] {
+		return Location{}, false
+	}
+	seen[path] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Location{}, false
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 {
+		return Location{}, false
+	}
+	root := doc.Content[0]
+
+	if entry := findBaseline(root, list, name); entry != nil {
+		return Location{File: path, Line: settingLine(entry, field)}, true
+	}
+
+	if root.Kind != yaml.MappingNode {
+		return Location{}, false
+	}
+	for _, key := range []string{includeKey, includesKey} {
+		value := lookup(root, key)
+		if value == nil {
+			continue
+		}
+		paths := []*yaml.Node{value}
+		if value.Kind == yaml.SequenceNode {
+			paths = value.Content
+		}
+		for _, p := range paths {
+			if p.Kind != yaml.ScalarNode {
+				continue
+			}
+			if loc, ok := locateBaseline(resolvePath(filepath.Dir(path), p.Value), list, name, field, seen); ok {
+				return loc, true
+			}
+		}
+	}
+	return Location{}, false
+}
+
+// findBaseline returns the entry with the name in the first list with the
+// key, searched depth first
+func findBaseline(n *yaml.Node, list, name string) *yaml.Node {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(n.Content); i += 2 {
+			key, value := n.Content[i], n.Content[i+1]
+			if key.Value == list && value.Kind == yaml.SequenceNode {
+				for _, entry := range value.Content {
+					if entry.Kind != yaml.MappingNode {
+						continue
+					}
+					if v := lookup(entry, "name"); v != nil && v.Value == name {
+						return entry
+					}
+				}
+			}
+			if found := findBaseline(value, list, name); found != nil {
+				return found
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			if found := findBaseline(item, list, name); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// settingLine returns the line of the deepest setting of a baseline entry
+// matching the field. Each part of the field is looked up below the previous
+// match, at any depth, and parts the entry does not spell out are skipped.
+func settingLine(entry *yaml.Node, field string) int {
+	line := entry.Line
+	node := entry
+	for _, part := range strings.Split(fieldKeys(field), ".") {
+		if part == "" {
+			continue
+		}
+		if key, value := findKey(node, part); key != nil {
+			line, node = key.Line, value
+		}
+	}
+	return line
+}
+
+// fieldKeys drops index qualifiers such as [default-pool] from a field
+func fieldKeys(field string) string {
+	var sb strings.Builder
+	depth := 0
+	for _, r := range field {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// findKey returns the first mapping key named name below n, and its value,
+// searched depth first
+func findKey(n *yaml.Node, name string) (*yaml.Node, *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(n.Content); i += 2 {
+			if n.Content[i].Value == name {
+				return n.Content[i], n.Content[i+1]
+			}
+		}
+		for i := 1; i < len(n.Content); i += 2 {
+			if key, value := findKey(n.Content[i], name); key != nil {
+				return key, value
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range n.Content {
+			// Lists of named settings, e.g. database flags, are matched by name
+			if item.Kind == yaml.MappingNode {
+				if v := lookup(item, "name"); v != nil && v.Value == name {
+					return v, item
+				}
+			}
+			if key, value := findKey(item, name); key != nil {
+				return key, value
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package configfile
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLocateBaseline(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `include: [gke.yaml]
+projects: [shop-prod]
+sql_baselines:
+  - name: oltp
+    config:
+      tier: db-custom-4-16384
+      database_flags:
+        max_connections: "500"
+  - name: analytics
+    extends: oltp
+profiles:
+  staging:
+    sql_baselines:
+      - name: staging
+        config:
+          tier: db-f1-micro
+`,
+		"gke.yaml": `gke_baselines:
+  - name: api
+    cluster_config:
+      network: vpc-a
+`,
+	})
+	config := filepath.Join(dir, "config.yaml")
+	gke := filepath.Join(dir, "gke.yaml")
+
+	tests := []struct {
+		name        string
+		list, entry string
+		field       string
+		want        Location
+		wantOK      bool
+	}{
+		{name: "setting", list: "sql_baselines", entry: "oltp", field: "tier", want: Location{config, 6}, wantOK: true},
+		{name: "nested setting", list: "sql_baselines", entry: "oltp", field: "database_flags.max_connections", want: Location{config, 8}, wantOK: true},
+		{name: "inherited setting", list: "sql_baselines", entry: "analytics", field: "tier", want: Location{config, 9}, wantOK: true},
+		{name: "profile", list: "sql_baselines", entry: "staging", field: "tier", want: Location{config, 16}, wantOK: true},
+		{name: "included file", list: "gke_baselines", entry: "api", field: "cluster.network", want: Location{gke, 4}, wantOK: true},
+		{name: "index qualifier", list: "gke_baselines", entry: "api", field: "nodepool[default].machine_type", want: Location{gke, 2}, wantOK: true},
+		{name: "unknown baseline", list: "sql_baselines", entry: "missing", field: "tier"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LocateBaseline(config, tt.list, tt.entry, tt.field)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("LocateBaseline() = %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
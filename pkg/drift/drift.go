@@ -0,0 +1,151 @@
+// Package drift runs drift analyses from Go code, for embedding the checks in
+// operators and services. Nothing is printed and nothing exits the process:
+// an analysis returns a report.Report, and failures are returned as errors.
+//
+// Options can be built directly or loaded from the tool's config file:
+//
+//	cfg, err := drift.LoadConfig("config.yaml", "")
+//	if err != nil {
+//		return err
+//	}
+//	r, err := drift.RunSQLAnalysis(ctx, cfg.SQLOptions())
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Printf("%.1f%% compliant\n", r.Compliance())
+//
+// Clients use the application default credentials unless
+// credentials.Configure selects others.
+package drift
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/configfile"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// Options are the settings shared by every analysis
+type Options struct {
+	// Projects are the projects to scan
+	Projects []string
+	// Labels only selects resources carrying every one of these labels, on
+	// top of the filters of each baseline
+	Labels map[string]string
+	// ContinueOnError skips projects that cannot be scanned, listing them in
+	// the report's Errors, instead of failing the analysis
+	ContinueOnError bool
+	// Retry controls how API calls are retried; nil uses retry.DefaultPolicy
+	Retry *retry.Policy
+	// Warnings receives problems that do not stop an analysis; nil discards them
+	Warnings io.Writer
+}
+
+// Config is the part of the tool's config file the analyses use
+type Config struct {
+	Projects     []string          `yaml:"projects"`
+	SQLBaselines []sql.SQLBaseline `yaml:"sql_baselines"`
+	GKEBaselines []gke.GKEBaseline `yaml:"gke_baselines"`
+	Policy       struct {
+		SQL *sql.Policy `yaml:"sql"`
+	} `yaml:"policy"`
+	DatabaseOwners sql.DatabaseOwners          `yaml:"database_owners"`
+	Operational    *analyzer.OperationalPolicy `yaml:"operational"`
+	LabelPolicy    *analyzer.LabelPolicy       `yaml:"label_policy"`
+}
+
+// LoadConfig reads the config file at path, with its includes, baseline
+// inheritance and environment variables resolved. A non-empty profile
+// selects an environment of a workspace file.
+func LoadConfig(path, profile string) (*Config, error) {
+	data, err := configfile.ReadProfile(path, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// ParseConfig parses a config file that is already a single YAML document
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return &config, nil
+}
+
+// SQLOptions returns the options of a Cloud SQL analysis of every SQL baseline
+func (c *Config) SQLOptions() SQLOptions {
+	opts := SQLOptions{
+		Options:        Options{Projects: c.Projects},
+		Baselines:      c.SQLBaselines,
+		Policy:         c.Policy.SQL,
+		DatabaseOwners: c.DatabaseOwners,
+		LabelPolicy:    c.LabelPolicy,
+	}
+	if c.Operational != nil {
+		opts.Operational = c.Operational.SQL
+	}
+	return opts
+}
+
+// GKEOptions returns the options of a GKE analysis of every GKE baseline
+func (c *Config) GKEOptions() GKEOptions {
+	opts := GKEOptions{
+		Options:     Options{Projects: c.Projects},
+		Baselines:   c.GKEBaselines,
+		LabelPolicy: c.LabelPolicy,
+	}
+	if c.Operational != nil {
+		opts.Operational = c.Operational.GKE
+	}
+	return opts
+}
+
+// validate checks the options shared by every analysis
+func (o Options) validate() error {
+	if len(o.Projects) == 0 {
+		return fmt.Errorf("no projects to scan")
+	}
+	return nil
+}
+
+// retryPolicy returns the retry policy to set on analyzers
+func (o Options) retryPolicy() retry.Policy {
+	if o.Retry != nil {
+		return *o.Retry
+	}
+	return retry.DefaultPolicy()
+}
+
+// warnings returns the writer for analysis warnings
+func (o Options) warnings() io.Writer {
+	if o.Warnings != nil {
+		return o.Warnings
+	}
+	return io.Discard
+}
+
+// combine merges the per-baseline reports of one analysis, in baseline
+// order, with the projects that could not be scanned
+func combine(title string, reports []*report.Report, errors []report.ScanError) *report.Report {
+	combined := &report.Report{
+		Title:     title,
+		Resources: []report.Resource{},
+		Errors:    errors,
+	}
+	for _, r := range reports {
+		if combined.Timestamp.IsZero() {
+			combined.Timestamp = r.Timestamp
+		}
+		combined.Resources = append(combined.Resources, r.Resources...)
+		combined.Warnings = append(combined.Warnings, r.Warnings...)
+	}
+	return combined
+}
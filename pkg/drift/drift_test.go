@@ -0,0 +1,106 @@
+package drift
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`
+projects: [prod-a, prod-b]
+sql_baselines:
+  - name: application
+    filter_labels: {database-role: application}
+    config:
+      tier: db-custom-4-16384
+gke_baselines:
+  - name: api
+    filter_labels: {cluster-role: api}
+operational:
+  sql: {SUSPENDED: critical}
+  gke: {DEGRADED: high}
+`))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	sqlOpts := cfg.SQLOptions()
+	if len(sqlOpts.Projects) != 2 || len(sqlOpts.Baselines) != 1 || sqlOpts.Baselines[0].Name != "application" {
+		t.Errorf("SQLOptions() = %+v, want both projects and the application baseline", sqlOpts)
+	}
+	if sqlOpts.Operational["SUSPENDED"] != "critical" {
+		t.Errorf("SQLOptions().Operational = %v, want the sql states", sqlOpts.Operational)
+	}
+
+	gkeOpts := cfg.GKEOptions()
+	if len(gkeOpts.Baselines) != 1 || gkeOpts.Baselines[0].Name != "api" {
+		t.Errorf("GKEOptions().Baselines = %+v, want the api baseline", gkeOpts.Baselines)
+	}
+	if gkeOpts.Operational["DEGRADED"] != "high" {
+		t.Errorf("GKEOptions().Operational = %v, want the gke statuses", gkeOpts.Operational)
+	}
+}
+
+func TestRunValidatesOptions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{
+			name: "sql without projects",
+			run: func() error {
+				_, err := RunSQLAnalysis(ctx, SQLOptions{})
+				return err
+			},
+			wantErr: "no projects",
+		},
+		{
+			name: "sql without baselines",
+			run: func() error {
+				_, err := RunSQLAnalysis(ctx, SQLOptions{Options: Options{Projects: []string{"p"}}})
+				return err
+			},
+			wantErr: "no SQL baselines",
+		},
+		{
+			name: "gke without baselines",
+			run: func() error {
+				_, err := RunGKEAnalysis(ctx, GKEOptions{Options: Options{Projects: []string{"p"}}})
+				return err
+			},
+			wantErr: "no GKE baselines",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCombine(t *testing.T) {
+	errs := []report.ScanError{{Project: "denied", Error: "permission denied"}}
+	r := combine("Report", []*report.Report{
+		{Resources: []report.Resource{{Name: "a"}}},
+		{Resources: []report.Resource{{Name: "b"}, {Name: "c"}}},
+	}, errs)
+
+	var names []string
+	for _, res := range r.Resources {
+		names = append(names, res.Name)
+	}
+	if got := strings.Join(names, ","); got != "a,b,c" {
+		t.Errorf("resources = %s, want a,b,c in baseline order", got)
+	}
+	if len(r.Errors) != 1 || r.Title != "Report" {
+		t.Errorf("combine() = %+v, want the title and scan errors", r)
+	}
+}
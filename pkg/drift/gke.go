@@ -0,0 +1,64 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// GKEOptions configure a GKE analysis
+type GKEOptions struct {
+	Options
+	// Baselines are analyzed in order; a cluster is checked against every
+	// baseline that selects it
+	Baselines []gke.GKEBaseline
+	// Operational maps cluster statuses such as DEGRADED to a severity
+	Operational analyzer.OperationalSeverities
+	// LabelPolicy lists the labels every cluster must carry
+	LabelPolicy *analyzer.LabelPolicy
+}
+
+// RunGKEAnalysis discovers the GKE clusters of the projects and analyzes
+// them against every baseline, returning one report
+func RunGKEAnalysis(ctx context.Context, opts GKEOptions) (*report.Report, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if len(opts.Baselines) == 0 {
+		return nil, fmt.Errorf("no GKE baselines")
+	}
+	for _, baseline := range opts.Baselines {
+		if err := baseline.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid GKE baseline: %w", err)
+		}
+	}
+
+	a, err := gke.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GKE analyzer: %w", err)
+	}
+	defer a.Close()
+	a.SetRetryPolicy(opts.retryPolicy())
+	a.SetContinueOnError(opts.ContinueOnError)
+	a.SetOperationalSeverities(opts.Operational)
+	a.SetLabelPolicy(opts.LabelPolicy)
+
+	clusters, err := a.DiscoverClusters(ctx, opts.Projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover clusters: %w", err)
+	}
+
+	reports := make([]*report.Report, 0, len(opts.Baselines))
+	for _, baseline := range opts.Baselines {
+		matched := gke.SelectClusters(clusters, baseline, opts.Baselines)
+		matched = gke.FilterByLabels(matched, opts.Labels)
+		driftReport := a.AnalyzeDrift(matched, baseline.ClusterConfig, baseline.NodePoolBaselines())
+		gke.CheckCapacity(driftReport, baseline.Capacity)
+		gke.CheckUniqueness(driftReport, baseline.Unique)
+		reports = append(reports, driftReport.ToReport())
+	}
+	return combine("GCP GKE Drift Analysis Report", reports, a.ScanErrors()), nil
+}
@@ -0,0 +1,76 @@
+package drift
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// SQLOptions configure a Cloud SQL analysis
+type SQLOptions struct {
+	Options
+	// Baselines are analyzed in order. An instance is checked against every
+	// baseline scoped by instance name that selects it; only instances no
+	// such baseline selects are checked against baselines that select by
+	// labels alone
+	Baselines []sql.SQLBaseline
+	// Policy holds organization-wide rules checked on every instance
+	Policy *sql.Policy
+	// DatabaseOwners are the expected owners of databases by name
+	DatabaseOwners sql.DatabaseOwners
+	// Operational maps instance states such as SUSPENDED to a severity
+	Operational analyzer.OperationalSeverities
+	// LabelPolicy lists the labels every instance must carry
+	LabelPolicy *analyzer.LabelPolicy
+}
+
+// RunSQLAnalysis discovers the Cloud SQL instances of the projects and
+// analyzes them against every baseline, returning one report
+func RunSQLAnalysis(ctx context.Context, opts SQLOptions) (*report.Report, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if len(opts.Baselines) == 0 {
+		return nil, fmt.Errorf("no SQL baselines")
+	}
+	for _, baseline := range opts.Baselines {
+		if err := baseline.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid SQL baseline: %w", err)
+		}
+	}
+	if err := opts.DatabaseOwners.Validate(); err != nil {
+		return nil, err
+	}
+
+	a, err := sql.NewAnalyzer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL analyzer: %w", err)
+	}
+	defer a.Close()
+	a.SetRetryPolicy(opts.retryPolicy())
+	a.SetContinueOnError(opts.ContinueOnError)
+	a.SetWarnings(opts.warnings())
+	a.SetPolicy(opts.Policy)
+	a.SetDatabaseOwners(opts.DatabaseOwners)
+	a.SetOperationalSeverities(opts.Operational)
+	a.SetLabelPolicy(opts.LabelPolicy)
+
+	instances, err := a.DiscoverInstances(ctx, opts.Projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover instances: %w", err)
+	}
+
+	reports := make([]*report.Report, 0, len(opts.Baselines))
+	for _, baseline := range opts.Baselines {
+		matched := sql.SelectInstances(instances, baseline, opts.Baselines)
+		matched = sql.FilterByLabels(matched, opts.Labels)
+		driftReport := a.AnalyzeDrift(matched, baseline.Config)
+		sql.CheckCapacity(driftReport, baseline.Capacity)
+		sql.CheckUniqueness(driftReport, baseline.Unique)
+		reports = append(reports, driftReport.ToReport())
+	}
+	return combine("GCP Cloud SQL Drift Analysis Report", reports, a.ScanErrors()), nil
+}
@@ -0,0 +1,214 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	bigqueryapi "google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+// maxRowsPerInsert stays well below the streaming insert limit of 50,000 rows
+// and 10 MB per request
+const maxRowsPerInsert = 500
+
+// Table identifies a BigQuery table
+type Table struct {
+	Project string
+	Dataset string
+	Table   string
+}
+
+// ParseTable parses project.dataset.table or project:dataset.table
+func ParseTable(spec string) (Table, error) {
+	parts := strings.Split(strings.Replace(spec, ":", ".", 1), ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return Table{}, fmt.Errorf("invalid BigQuery table %q: expected project.dataset.table", spec)
+	}
+	return Table{Project: parts[0], Dataset: parts[1], Table: parts[2]}, nil
+}
+
+// String returns the table as project.dataset.table
+func (t Table) String() string {
+	return t.Project + "." + t.Dataset + "." + t.Table
+}
+
+// findingsSchema is one row per drift finding, partitioned by day on timestamp
+var findingsSchema = &bigqueryapi.TableSchema{
+	Fields: []*bigqueryapi.TableFieldSchema{
+		{Name: "run_id", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "timestamp", Type: "TIMESTAMP", Mode: "REQUIRED"},
+		{Name: "resource_type", Type: "STRING"},
+		{Name: "project", Type: "STRING"},
+		{Name: "resource", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "location", Type: "STRING"},
+		{Name: "labels", Type: "RECORD", Mode: "REPEATED", Fields: []*bigqueryapi.TableFieldSchema{
+			{Name: "key", Type: "STRING"},
+			{Name: "value", Type: "STRING"},
+		}},
+		{Name: "field", Type: "STRING", Mode: "REQUIRED"},
+		{Name: "expected", Type: "STRING"},
+		{Name: "actual", Type: "STRING"},
+		{Name: "severity", Type: "STRING"},
+		{Name: "immutable", Type: "BOOLEAN"},
+		{Name: "owners", Type: "STRING", Mode: "REPEATED"},
+	},
+}
+
+// Compile-time interface implementation check
+var _ Exporter = (*BigQuery)(nil)
+
+// BigQuery streams drift findings into a table, creating it on first use
+type BigQuery struct {
+	service *bigqueryapi.Service
+	table   Table
+	retry   retry.Policy
+	checked bool
+}
+
+// NewBigQuery creates an exporter for a table given as project.dataset.table
+func NewBigQuery(ctx context.Context, spec string) (*BigQuery, error) {
+	table, err := ParseTable(spec)
+	if err != nil {
+		return nil, err
+	}
+	service, err := bigqueryapi.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery service: %w", err)
+	}
+	return &BigQuery{service: service, table: table, retry: retry.DefaultPolicy()}, nil
+}
+
+// Destination returns the table as project.dataset.table
+func (b *BigQuery) Destination() string {
+	return b.table.String()
+}
+
+// Export inserts one row per drift finding of the report, tagged with runID,
+// and returns the number of rows written. Rows carry an insert ID derived from
+// the run and finding, so a retried request does not duplicate findings.
+func (b *BigQuery) Export(ctx context.Context, runID string, r *report.Report) (int, error) {
+	rows := findingRows(runID, r)
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if err := b.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	for start := 0; start < len(rows); start += maxRowsPerInsert {
+		end := min(start+maxRowsPerInsert, len(rows))
+		request := &bigqueryapi.TableDataInsertAllRequest{Rows: rows[start:end]}
+		resp, err := retry.Do(ctx, b.retry, b.service.Tabledata.InsertAll(b.table.Project, b.table.Dataset, b.table.Table, request).Context(ctx).Do)
+		if err != nil {
+			return start, fmt.Errorf("failed to insert findings into %s: %w", b.table, err)
+		}
+		if len(resp.InsertErrors) > 0 {
+			return start, fmt.Errorf("failed to insert %d of %d findings into %s: %s",
+				len(resp.InsertErrors), end-start, b.table, insertErrorMessage(resp.InsertErrors))
+		}
+	}
+	return len(rows), nil
+}
+
+// ensureTable creates the table with the findings schema unless it exists
+func (b *BigQuery) ensureTable(ctx context.Context) error {
+	if b.checked {
+		return nil
+	}
+
+	_, err := retry.Do(ctx, b.retry, b.service.Tables.Get(b.table.Project, b.table.Dataset, b.table.Table).Context(ctx).Do)
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+		table := &bigqueryapi.Table{
+			TableReference: &bigqueryapi.TableReference{
+				ProjectId: b.table.Project,
+				DatasetId: b.table.Dataset,
+				TableId:   b.table.Table,
+			},
+			Schema:           findingsSchema,
+			TimePartitioning: &bigqueryapi.TimePartitioning{Type: "DAY", Field: "timestamp"},
+		}
+		_, err = retry.Do(ctx, b.retry, b.service.Tables.Insert(b.table.Project, b.table.Dataset, table).Context(ctx).Do)
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to prepare BigQuery table %s: %w", b.table, err)
+	}
+	b.checked = true
+	return nil
+}
+
+// findingRows converts the report's drift findings into insert rows
+func findingRows(runID string, r *report.Report) []*bigqueryapi.TableDataInsertAllRequestRows {
+	events := r.Events(runID)
+	rows := make([]*bigqueryapi.TableDataInsertAllRequestRows, 0, len(events))
+	for _, event := range events {
+		keys := make([]string, 0, len(event.Labels))
+		for key := range event.Labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		labels := make([]map[string]string, 0, len(keys))
+		for _, key := range keys {
+			labels = append(labels, map[string]string{"key": key, "value": event.Labels[key]})
+		}
+		owners := event.Owners
+		if owners == nil {
+			owners = []string{}
+		}
+
+		rows = append(rows, &bigqueryapi.TableDataInsertAllRequestRows{
+			InsertId: insertID(event),
+			Json: map[string]bigqueryapi.JsonValue{
+				"run_id":        runID,
+				"timestamp":     event.Timestamp.Format("2006-01-02T15:04:05.000000Z"),
+				"resource_type": event.ResourceType,
+				"project":       event.Project,
+				"resource":      event.Resource,
+				"location":      event.Location,
+				"labels":        labels,
+				"field":         event.Field,
+				"expected":      event.Expected,
+				"actual":        event.Actual,
+				"severity":      event.Severity,
+				"immutable":     event.Immutable,
+				"owners":        owners,
+			},
+		})
+	}
+	return rows
+}
+
+// insertID identifies a finding within a run for best-effort deduplication
+func insertID(event report.Event) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		event.ScanID, event.ResourceType, event.Project, event.Location, event.Resource, event.Field,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// insertErrorMessage summarizes the first row errors of a streaming insert
+func insertErrorMessage(errs []*bigqueryapi.TableDataInsertAllResponseInsertErrors) string {
+	var messages []string
+	for _, rowErr := range errs {
+		for _, e := range rowErr.Errors {
+			messages = append(messages, fmt.Sprintf("row %d: %s", rowErr.Index, e.Message))
+		}
+		if len(messages) >= 3 {
+			break
+		}
+	}
+	return strings.Join(messages, "; ")
+}
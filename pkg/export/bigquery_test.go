@@ -0,0 +1,82 @@
+package export
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Table
+		wantErr bool
+	}{
+		{spec: "proj.drift.findings", want: Table{"proj", "drift", "findings"}},
+		{spec: "proj:drift.findings", want: Table{"proj", "drift", "findings"}},
+		{spec: "drift.findings", wantErr: true},
+		{spec: "proj..findings", wantErr: true},
+		{spec: "a.b.c.d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTable(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTable(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTable(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestFindingRows(t *testing.T) {
+	r := &report.Report{
+		Timestamp: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		Resources: []report.Resource{
+			{
+				Type:    "Cloud SQL",
+				Project: "p",
+				Name:    "db-1",
+				Labels:  map[string]string{"team": "payments", "env": "prod"},
+				Drifts: []report.Drift{
+					{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-custom-2-8192", Severity: "high"},
+					{Field: "databases.missing", Expected: "app", Actual: "", Severity: "high", Owners: []string{"payments"}},
+				},
+			},
+			{Type: "Cloud SQL", Project: "p", Name: "db-2"},
+		},
+	}
+
+	rows := findingRows("run-1", r)
+	if len(rows) != 2 {
+		t.Fatalf("findingRows() = %d rows, want 2", len(rows))
+	}
+
+	row := rows[0].Json
+	if row["run_id"] != "run-1" || row["resource"] != "db-1" || row["field"] != "tier" || row["severity"] != "high" {
+		t.Errorf("row = %v", row)
+	}
+	if row["timestamp"] != "2026-03-01T08:00:00.000000Z" {
+		t.Errorf("timestamp = %v", row["timestamp"])
+	}
+	labels := row["labels"].([]map[string]string)
+	if len(labels) != 2 || labels[0]["key"] != "env" || labels[1]["value"] != "payments" {
+		t.Errorf("labels = %v, want sorted key/value pairs", labels)
+	}
+	if owners := rows[1].Json["owners"].([]string); len(owners) != 1 || owners[0] != "payments" {
+		t.Errorf("owners = %v", owners)
+	}
+
+	if rows[0].InsertId == rows[1].InsertId {
+		t.Error("findings share an insert ID")
+	}
+	if again := findingRows("run-1", r); again[0].InsertId != rows[0].InsertId {
+		t.Error("insert ID is not stable for the same run")
+	}
+	if other := findingRows("run-2", r); other[0].InsertId == rows[0].InsertId {
+		t.Error("insert ID is shared across runs")
+	}
+}
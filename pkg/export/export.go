@@ -0,0 +1,18 @@
+// Package export streams drift findings into external stores for long-term
+// compliance reporting and alerting.
+package export
+
+import (
+	"context"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Exporter writes the drift findings of a report to an external store
+type Exporter interface {
+	// Export writes one record per finding, tagged with runID, and returns
+	// the number of records written
+	Export(ctx context.Context, runID string, r *report.Report) (int, error)
+	// Destination names where findings are written, e.g. a BigQuery table
+	Destination() string
+}
@@ -0,0 +1,132 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	loggingapi "google.golang.org/api/logging/v2"
+)
+
+// DefaultLogName is the log drift entries are written to
+const DefaultLogName = "drift-analysis"
+
+// maxEntriesPerWrite stays below the 10 MB limit of a write request
+const maxEntriesPerWrite = 500
+
+// logSeverities maps drift severities to Cloud Logging severities, so
+// log-based alerts can filter on severity>=ERROR
+var logSeverities = map[string]string{
+	"critical": "CRITICAL",
+	"high":     "ERROR",
+	"medium":   "WARNING",
+	"low":      "NOTICE",
+}
+
+// CloudLogging writes each drift finding as a structured log entry
+type CloudLogging struct {
+	service *loggingapi.Service
+	// project receives every entry; empty writes each entry to the project
+	// of its resource
+	project string
+	logName string
+	retry   retry.Policy
+}
+
+// Compile-time interface implementation check
+var _ Exporter = (*CloudLogging)(nil)
+
+// NewCloudLogging creates an exporter writing to logName in project, or in
+// each resource's project when project is empty
+func NewCloudLogging(ctx context.Context, project, logName string) (*CloudLogging, error) {
+	if logName == "" {
+		logName = DefaultLogName
+	}
+	service, err := loggingapi.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Logging service: %w", err)
+	}
+	return &CloudLogging{service: service, project: project, logName: logName, retry: retry.DefaultPolicy()}, nil
+}
+
+// Destination returns the log the entries are written to
+func (c *CloudLogging) Destination() string {
+	project := c.project
+	if project == "" {
+		project = "<resource project>"
+	}
+	return "Cloud Logging projects/" + project + "/logs/" + c.logName
+}
+
+// Export writes one log entry per drift finding of the report. Entries that
+// name no project and have no default project are skipped.
+func (c *CloudLogging) Export(ctx context.Context, runID string, r *report.Report) (int, error) {
+	entries, err := c.entries(runID, r)
+	if err != nil {
+		return 0, err
+	}
+
+	for start := 0; start < len(entries); start += maxEntriesPerWrite {
+		end := min(start+maxEntriesPerWrite, len(entries))
+		request := &loggingapi.WriteLogEntriesRequest{Entries: entries[start:end]}
+		if _, err := retry.Do(ctx, c.retry, c.service.Entries.Write(request).Context(ctx).Do); err != nil {
+			return start, fmt.Errorf("failed to write drift log entries: %w", err)
+		}
+	}
+	return len(entries), nil
+}
+
+// entries converts the report's drift findings into log entries
+func (c *CloudLogging) entries(runID string, r *report.Report) ([]*loggingapi.LogEntry, error) {
+	events := r.Events(runID)
+	entries := make([]*loggingapi.LogEntry, 0, len(events))
+	for _, event := range events {
+		project := c.project
+		if project == "" {
+			project = event.Project
+		}
+		if project == "" {
+			continue
+		}
+
+		payload, err := json.Marshal(logPayload{
+			Message: fmt.Sprintf("%s %s/%s: %s expected %q, got %q",
+				event.ResourceType, event.Project, event.Resource, event.Field, event.Expected, event.Actual),
+			Event: event,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal log entry: %w", err)
+		}
+
+		severity, ok := logSeverities[event.Severity]
+		if !ok {
+			severity = "DEFAULT"
+		}
+		entries = append(entries, &loggingapi.LogEntry{
+			LogName:     "projects/" + project + "/logs/" + url.PathEscape(c.logName),
+			Resource:    &loggingapi.MonitoredResource{Type: "global", Labels: map[string]string{"project_id": project}},
+			Severity:    severity,
+			Timestamp:   event.Timestamp.Format("2006-01-02T15:04:05.999999999Z07:00"),
+			InsertId:    insertID(event),
+			JsonPayload: payload,
+			Labels: map[string]string{
+				"scan_id":       runID,
+				"resource_type": event.ResourceType,
+				"resource":      event.Resource,
+				"field":         event.Field,
+			},
+		})
+	}
+	return entries, nil
+}
+
+// logPayload is the jsonPayload of an entry: the NDJSON event plus a message
+// shown as the entry summary in Logs Explorer
+type logPayload struct {
+	Message string `json:"message"`
+	report.Event
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestCloudLoggingEntries(t *testing.T) {
+	r := &report.Report{
+		Timestamp: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		Resources: []report.Resource{
+			{Type: "Cloud SQL", Project: "shop-prod", Name: "db-1", Drifts: []report.Drift{
+				{Field: "settings.backup_enabled", Expected: "true", Actual: "false", Severity: "critical"},
+				{Field: "labels.team", Expected: "payments", Actual: "", Severity: "low"},
+			}},
+			// Findings without a project have nowhere to go without --log-project
+			{Type: "Imported", Name: "orphan", Drifts: []report.Drift{{Field: "tier", Severity: "high"}}},
+		},
+	}
+
+	c := &CloudLogging{logName: DefaultLogName}
+	entries, err := c.entries("run-1", r)
+	if err != nil {
+		t.Fatalf("entries() = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+
+	e := entries[0]
+	if e.LogName != "projects/shop-prod/logs/drift-analysis" || e.Resource.Labels["project_id"] != "shop-prod" {
+		t.Errorf("entry written to %s (%v)", e.LogName, e.Resource.Labels)
+	}
+	if e.Severity != "CRITICAL" || entries[1].Severity != "NOTICE" {
+		t.Errorf("severities = %s, %s, want CRITICAL, NOTICE", e.Severity, entries[1].Severity)
+	}
+	if e.Timestamp != "2026-03-01T08:00:00Z" || e.Labels["scan_id"] != "run-1" || e.Labels["field"] != "settings.backup_enabled" {
+		t.Errorf("entry = %s %v", e.Timestamp, e.Labels)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(e.JsonPayload, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload["message"] != `Cloud SQL shop-prod/db-1: settings.backup_enabled expected "true", got "false"` ||
+		payload["resource"] != "db-1" || payload["severity"] != "critical" {
+		t.Errorf("payload = %v", payload)
+	}
+
+	c.project = "drift-ops"
+	entries, err = c.entries("run-1", r)
+	if err != nil {
+		t.Fatalf("entries() = %v", err)
+	}
+	if len(entries) != 3 || entries[2].LogName != "projects/drift-ops/logs/drift-analysis" {
+		t.Errorf("with a log project got %d entries, last to %s", len(entries), entries[len(entries)-1].LogName)
+	}
+}
@@ -0,0 +1,294 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	securitycenterapi "google.golang.org/api/securitycenter/v1"
+)
+
+// defaultSCCMinSeverity is the lowest severity exported to Security Command
+// Center unless configured otherwise
+const defaultSCCMinSeverity = "high"
+
+// sccSourcePattern matches the name of a custom SCC source
+var sccSourcePattern = regexp.MustCompile(`^organizations/[0-9]+/sources/[0-9]+$`)
+
+// DefaultSecurityFields are the security-related fields exported per resource
+// type. A pattern ending in "*" matches by prefix; any other pattern matches
+// the field itself and its children.
+var DefaultSecurityFields = map[string][]string{
+	"Cloud SQL": {
+		"settings.ip_configuration.require_ssl",
+		"settings.ip_configuration.ipv4_enabled",
+		"settings.ip_configuration.authorized_networks",
+	},
+	"GKE Cluster": {
+		"cluster.private_cluster",
+		"cluster.master_authorized_networks*",
+		"cluster.workload_identity",
+		"cluster.network_policy",
+		"cluster.binary_authorization",
+		"cluster.shielded_nodes",
+		"cluster.database_encryption",
+		"cluster.security_posture",
+	},
+	"Memorystore Redis": {
+		"auth_enabled",
+		"transit_encryption_mode",
+	},
+	"BigQuery Dataset": {
+		"access",
+		"default_encryption_configuration",
+	},
+}
+
+// sccSeverities maps drift severities to SCC finding severities
+var sccSeverities = map[string]string{
+	"critical": "CRITICAL",
+	"high":     "HIGH",
+	"medium":   "MEDIUM",
+	"low":      "LOW",
+}
+
+// SCCConfig configures the Security Command Center exporter
+type SCCConfig struct {
+	// Source is the custom source findings are written to,
+	// organizations/ORG_ID/sources/SOURCE_ID
+	Source string `yaml:"source"`
+	// MinSeverity is the lowest severity exported (default high)
+	MinSeverity string `yaml:"min_severity,omitempty"`
+	// Fields replaces the default security fields of a resource type
+	Fields map[string][]string `yaml:"fields,omitempty"`
+}
+
+// Validate checks the source name and minimum severity
+func (c *SCCConfig) Validate() error {
+	if c == nil || c.Source == "" {
+		return nil
+	}
+	if !sccSourcePattern.MatchString(c.Source) {
+		return fmt.Errorf("invalid Security Command Center source %q: expected organizations/ORG_ID/sources/SOURCE_ID", c.Source)
+	}
+	if c.MinSeverity != "" && report.SeverityRank(c.MinSeverity) == 0 {
+		return fmt.Errorf("security_command_center: invalid min_severity %q", c.MinSeverity)
+	}
+	return nil
+}
+
+// minSeverity returns the lowest severity exported
+func (c *SCCConfig) minSeverity() string {
+	if c.MinSeverity == "" {
+		return defaultSCCMinSeverity
+	}
+	return c.MinSeverity
+}
+
+// fields returns the security field patterns of a resource type
+func (c *SCCConfig) fields(resourceType string) []string {
+	if patterns, ok := c.Fields[resourceType]; ok {
+		return patterns
+	}
+	return DefaultSecurityFields[resourceType]
+}
+
+// matchField reports whether a drift field matches a security field pattern
+func matchField(pattern, field string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(field, prefix)
+	}
+	return field == pattern || strings.HasPrefix(field, pattern+".") || strings.HasPrefix(field, pattern+"[")
+}
+
+// Compile-time interface implementation check
+var _ Exporter = (*SecurityCommandCenter)(nil)
+
+// SecurityCommandCenter creates or updates one finding per security-related
+// drift in a custom source, and deactivates findings whose drift is resolved
+type SecurityCommandCenter struct {
+	service *securitycenterapi.Service
+	config  SCCConfig
+	retry   retry.Policy
+}
+
+// NewSecurityCommandCenter creates an exporter for the configured source
+func NewSecurityCommandCenter(ctx context.Context, config SCCConfig) (*SecurityCommandCenter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	service, err := securitycenterapi.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Security Command Center service: %w", err)
+	}
+	return &SecurityCommandCenter{service: service, config: config, retry: retry.DefaultPolicy()}, nil
+}
+
+// Destination returns the source findings are written to
+func (s *SecurityCommandCenter) Destination() string {
+	return "Security Command Center " + s.config.Source
+}
+
+// Export upserts a finding for every security-related drift at or above the
+// minimum severity. Finding IDs are derived from the resource and field, so a
+// drift seen again updates its existing finding. Active findings of scanned
+// resources that no longer drift are set to INACTIVE.
+func (s *SecurityCommandCenter) Export(ctx context.Context, runID string, r *report.Report) (int, error) {
+	findings, err := s.findings(runID, r)
+	if err != nil {
+		return 0, err
+	}
+
+	active := make(map[string]bool, len(findings))
+	for i, finding := range findings {
+		if _, err := retry.Do(ctx, s.retry, s.service.Organizations.Sources.Findings.Patch(finding.Name, finding).Context(ctx).Do); err != nil {
+			return i, fmt.Errorf("failed to write finding %s: %w", finding.Name, err)
+		}
+		active[finding.Name] = true
+	}
+
+	if err := s.deactivateResolved(ctx, r, active); err != nil {
+		return len(findings), err
+	}
+	return len(findings), nil
+}
+
+// deactivateResolved sets active findings of the report's resources to
+// INACTIVE unless they were written in this run
+func (s *SecurityCommandCenter) deactivateResolved(ctx context.Context, r *report.Report, active map[string]bool) error {
+	scanned := make(map[string]bool, len(r.Resources))
+	for _, res := range r.Resources {
+		scanned[sccResourceName(res.Type, res.Project, res.Location, res.Name)] = true
+	}
+
+	var resolved []string
+	list := s.service.Organizations.Sources.Findings.List(s.config.Source).
+		Filter(`state="ACTIVE"`).Context(ctx)
+	err := list.Pages(ctx, func(resp *securitycenterapi.ListFindingsResponse) error {
+		for _, result := range resp.ListFindingsResults {
+			finding := result.Finding
+			if finding == nil || active[finding.Name] || !scanned[finding.ResourceName] {
+				continue
+			}
+			resolved = append(resolved, finding.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list active findings in %s: %w", s.config.Source, err)
+	}
+
+	request := &securitycenterapi.SetFindingStateRequest{
+		State:     "INACTIVE",
+		StartTime: r.Timestamp.UTC().Format(time.RFC3339),
+	}
+	for _, name := range resolved {
+		if _, err := retry.Do(ctx, s.retry, s.service.Organizations.Sources.Findings.SetState(name, request).Context(ctx).Do); err != nil {
+			return fmt.Errorf("failed to deactivate finding %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// findings converts the report's security-related drifts of resources with a
+// known project into SCC findings
+func (s *SecurityCommandCenter) findings(runID string, r *report.Report) ([]*securitycenterapi.Finding, error) {
+	minRank := report.SeverityRank(s.config.minSeverity())
+
+	var findings []*securitycenterapi.Finding
+	for _, event := range r.Events(runID) {
+		if report.SeverityRank(event.Severity) < minRank || !s.isSecurityField(event.ResourceType, event.Field) {
+			continue
+		}
+		// SCC attaches findings to a resource, which needs a project
+		if event.Project == "" {
+			continue
+		}
+
+		properties := map[string]string{
+			"scan_id":       event.ScanID,
+			"resource_type": event.ResourceType,
+			"project":       event.Project,
+			"location":      event.Location,
+			"resource":      event.Resource,
+			"field":         event.Field,
+			"expected":      event.Expected,
+			"actual":        event.Actual,
+			"severity":      event.Severity,
+		}
+		sourceProperties, err := json.Marshal(properties)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal finding properties: %w", err)
+		}
+
+		findings = append(findings, &securitycenterapi.Finding{
+			Name:         s.config.Source + "/findings/" + findingID(event),
+			Parent:       s.config.Source,
+			State:        "ACTIVE",
+			Category:     findingCategory(event.Field),
+			Severity:     sccSeverities[event.Severity],
+			FindingClass: "MISCONFIGURATION",
+			ResourceName: sccResourceName(event.ResourceType, event.Project, event.Location, event.Resource),
+			EventTime:    event.Timestamp.Format(time.RFC3339),
+			Description: fmt.Sprintf("%s %s drifted from its baseline: %s expected %q, got %q",
+				event.ResourceType, event.Resource, event.Field, event.Expected, event.Actual),
+			SourceProperties: sourceProperties,
+		})
+	}
+	return findings, nil
+}
+
+// isSecurityField reports whether a drift field of a resource type is exported
+func (s *SecurityCommandCenter) isSecurityField(resourceType, field string) bool {
+	for _, pattern := range s.config.fields(resourceType) {
+		if matchField(pattern, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// findingID identifies a drift across runs: the same resource and field
+// always map to the same finding. SCC IDs are 1-32 alphanumeric characters.
+func findingID(event report.Event) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		event.ResourceType, event.Project, event.Location, event.Resource, event.Field,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// nonAlphanumeric matches the runs of characters replaced in a category
+var nonAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// findingCategory turns a drift field into an SCC category, e.g.
+// settings.ip_configuration.require_ssl becomes
+// SETTINGS_IP_CONFIGURATION_REQUIRE_SSL
+func findingCategory(field string) string {
+	return strings.Trim(strings.ToUpper(nonAlphanumeric.ReplaceAllString(field, "_")), "_")
+}
+
+// sccResourceName returns the full resource name SCC attaches a finding to;
+// unknown resource types are attached to their project
+func sccResourceName(resourceType, project, location, name string) string {
+	switch resourceType {
+	case "Cloud SQL":
+		return "//sqladmin.googleapis.com/projects/" + project + "/instances/" + name
+	case "GKE Cluster":
+		return "//container.googleapis.com/projects/" + project + "/locations/" + location + "/clusters/" + name
+	case "Memorystore Redis":
+		return "//redis.googleapis.com/projects/" + project + "/locations/" + location + "/instances/" + name
+	case "BigQuery Dataset":
+		return "//bigquery.googleapis.com/projects/" + project + "/datasets/" + name
+	case "VPC Network":
+		return "//compute.googleapis.com/projects/" + project + "/global/networks/" + name
+	}
+	return "//cloudresourcemanager.googleapis.com/projects/" + project
+}
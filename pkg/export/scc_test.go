@@ -0,0 +1,106 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestSCCFindings(t *testing.T) {
+	source := "organizations/123/sources/456"
+	r := &report.Report{
+		Timestamp: time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		Resources: []report.Resource{
+			{
+				Type:     "Cloud SQL",
+				Project:  "shop-prod",
+				Name:     "orders",
+				Location: "us-central1",
+				Drifts: []report.Drift{
+					{Field: "settings.ip_configuration.require_ssl", Expected: "true", Actual: "false", Severity: "critical"},
+					// Not security-related
+					{Field: "settings.tier", Expected: "db-custom-4-16384", Actual: "db-custom-2-8192", Severity: "high"},
+					// Below the minimum severity
+					{Field: "settings.ip_configuration.ipv4_enabled", Expected: "false", Actual: "true", Severity: "medium"},
+				},
+			},
+			{
+				Type:     "GKE Cluster",
+				Project:  "shop-prod",
+				Name:     "web",
+				Location: "us-central1",
+				Drifts: []report.Drift{
+					{Field: "cluster.binary_authorization.enabled", Expected: "true", Actual: "false", Severity: "high"},
+					{Field: "cluster.master_authorized_networks_config", Expected: "enabled", Actual: "disabled", Severity: "high"},
+				},
+			},
+			// No project to attach the finding to
+			{Type: "Cloud SQL", Name: "local", Drifts: []report.Drift{
+				{Field: "settings.ip_configuration.require_ssl", Expected: "true", Actual: "false", Severity: "critical"},
+			}},
+		},
+	}
+
+	s := &SecurityCommandCenter{config: SCCConfig{Source: source}}
+	findings, err := s.findings("run-1", r)
+	if err != nil {
+		t.Fatalf("findings() error = %v", err)
+	}
+	if len(findings) != 3 {
+		t.Fatalf("findings() = %d findings, want 3", len(findings))
+	}
+
+	f := findings[0]
+	if f.Category != "SETTINGS_IP_CONFIGURATION_REQUIRE_SSL" || f.Severity != "CRITICAL" || f.State != "ACTIVE" {
+		t.Errorf("finding = %s %s %s", f.Category, f.Severity, f.State)
+	}
+	if f.ResourceName != "//sqladmin.googleapis.com/projects/shop-prod/instances/orders" {
+		t.Errorf("ResourceName = %s", f.ResourceName)
+	}
+	if f.EventTime != "2026-03-01T08:00:00Z" {
+		t.Errorf("EventTime = %s", f.EventTime)
+	}
+	var properties map[string]string
+	if err := json.Unmarshal(f.SourceProperties, &properties); err != nil || properties["actual"] != "false" || properties["scan_id"] != "run-1" {
+		t.Errorf("SourceProperties = %s (%v)", f.SourceProperties, err)
+	}
+	if findings[1].ResourceName != "//container.googleapis.com/projects/shop-prod/locations/us-central1/clusters/web" {
+		t.Errorf("ResourceName = %s", findings[1].ResourceName)
+	}
+
+	// The same drift in a later run updates the same finding
+	again, _ := s.findings("run-2", r)
+	if again[0].Name != f.Name || len(f.Name) != len(source)+len("/findings/")+32 {
+		t.Errorf("finding name %s is not stable across runs (got %s)", f.Name, again[0].Name)
+	}
+	if findings[0].Name == findings[1].Name {
+		t.Error("findings share a name")
+	}
+
+	s.config.MinSeverity = "medium"
+	s.config.Fields = map[string][]string{"GKE Cluster": {"cluster.network_policy"}}
+	findings, _ = s.findings("run-1", r)
+	if len(findings) != 2 || findings[1].Category != "SETTINGS_IP_CONFIGURATION_IPV4_ENABLED" {
+		t.Errorf("with overrides got %d findings", len(findings))
+	}
+}
+
+func TestSCCConfigValidate(t *testing.T) {
+	tests := []struct {
+		config  SCCConfig
+		wantErr bool
+	}{
+		{config: SCCConfig{}},
+		{config: SCCConfig{Source: "organizations/123/sources/456", MinSeverity: "medium"}},
+		{config: SCCConfig{Source: "projects/p/sources/456"}, wantErr: true},
+		{config: SCCConfig{Source: "organizations/123/sources/456", MinSeverity: "urgent"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.config, err, tt.wantErr)
+		}
+	}
+}
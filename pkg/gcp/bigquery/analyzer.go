@@ -0,0 +1,344 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	bigqueryapi "google.golang.org/api/bigquery/v2"
+)
+
+// Dataset represents a BigQuery dataset with its configuration
+type Dataset struct {
+	Project  string
+	Name     string
+	Location string
+	Labels   map[string]string
+	Config   *DatasetConfig
+}
+
+// DatasetConfig holds the configuration parameters of a dataset
+type DatasetConfig struct {
+	Location               string   `yaml:"location,omitempty" json:"location,omitempty"`
+	DefaultTableExpiration string   `yaml:"default_table_expiration,omitempty" json:"default_table_expiration,omitempty"`
+	RequireCMEK            *bool    `yaml:"require_cmek,omitempty" json:"require_cmek,omitempty"`
+	KMSKeyName             string   `yaml:"kms_key_name,omitempty" json:"kms_key_name,omitempty"`
+	Access                 []string `yaml:"access,omitempty" json:"access,omitempty"`
+}
+
+// publicPrincipals are access members that expose a dataset outside the organization
+var publicPrincipals = map[string]bool{
+	"allUsers":              true,
+	"allAuthenticatedUsers": true,
+}
+
+// Analyzer performs drift analysis on BigQuery datasets
+type Analyzer struct {
+	analyzer.ProjectErrors
+
+	service    *bigqueryapi.Service
+	lastReport *DriftReport
+	projects   []string
+}
+
+// NewAnalyzer creates a new BigQuery Analyzer instance
+func NewAnalyzer(ctx context.Context) (*Analyzer, error) {
+	service, err := bigqueryapi.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+
+	return &Analyzer{service: service}, nil
+}
+
+// Close releases resources held by the Analyzer
+func (a *Analyzer) Close() error {
+	return nil
+}
+
+// Compile-time interface implementation check
+var _ analyzer.ResourceAnalyzer = (*Analyzer)(nil)
+
+// Analyze performs drift analysis implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) Analyze(ctx context.Context, projects []string) error {
+	a.projects = projects
+	return nil
+}
+
+// GenerateReport generates a formatted report implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GenerateReport() (string, error) {
+	if a.lastReport == nil {
+		return "", fmt.Errorf("no analysis has been performed yet")
+	}
+	return a.lastReport.FormatText(), nil
+}
+
+// GetDriftCount returns the number of drifts detected implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GetDriftCount() int {
+	if a.lastReport == nil {
+		return 0
+	}
+	return a.lastReport.DriftedDatasets
+}
+
+// DiscoverDatasets finds all BigQuery datasets across the specified projects
+func (a *Analyzer) DiscoverDatasets(ctx context.Context, projects []string) ([]*Dataset, error) {
+	var datasets []*Dataset
+
+	for _, project := range projects {
+		projectDatasets, err := a.discoverProjectDatasets(ctx, project)
+		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to discover datasets in project %s: %w", project, err)
+		}
+		datasets = append(datasets, projectDatasets...)
+	}
+
+	return datasets, nil
+}
+
+// discoverProjectDatasets lists the datasets of a project and fetches each one,
+// since access entries and encryption are only returned by datasets.get
+func (a *Analyzer) discoverProjectDatasets(ctx context.Context, project string) ([]*Dataset, error) {
+	var ids []string
+	err := a.service.Datasets.List(project).Pages(ctx, func(resp *bigqueryapi.DatasetList) error {
+		for _, ds := range resp.Datasets {
+			if ds.DatasetReference != nil {
+				ids = append(ids, ds.DatasetReference.DatasetId)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	datasets := make([]*Dataset, 0, len(ids))
+	for _, id := range ids {
+		ds, err := a.service.Datasets.Get(project, id).Context(ctx).Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dataset %s: %w", id, err)
+		}
+		datasets = append(datasets, &Dataset{
+			Project:  project,
+			Name:     id,
+			Location: ds.Location,
+			Labels:   ds.Labels,
+			Config:   extractConfig(ds),
+		})
+	}
+
+	return datasets, nil
+}
+
+// extractConfig extracts configuration parameters from a dataset
+func extractConfig(ds *bigqueryapi.Dataset) *DatasetConfig {
+	config := &DatasetConfig{
+		Location:               ds.Location,
+		DefaultTableExpiration: formatExpiration(ds.DefaultTableExpirationMs),
+		Access:                 make([]string, 0, len(ds.Access)),
+	}
+
+	if ds.DefaultEncryptionConfiguration != nil {
+		config.KMSKeyName = ds.DefaultEncryptionConfiguration.KmsKeyName
+	}
+	requireCMEK := config.KMSKeyName != ""
+	config.RequireCMEK = &requireCMEK
+
+	for _, entry := range ds.Access {
+		if s := accessEntry(entry); s != "" {
+			config.Access = append(config.Access, s)
+		}
+	}
+	sort.Strings(config.Access)
+
+	return config
+}
+
+// accessEntry renders an access entry as "ROLE type:member"; authorized views,
+// routines and datasets are skipped since they grant no principal access
+func accessEntry(entry *bigqueryapi.DatasetAccess) string {
+	var member string
+	switch {
+	case entry.UserByEmail != "":
+		member = "user:" + entry.UserByEmail
+	case entry.GroupByEmail != "":
+		member = "group:" + entry.GroupByEmail
+	case entry.Domain != "":
+		member = "domain:" + entry.Domain
+	case entry.SpecialGroup != "":
+		member = "specialGroup:" + entry.SpecialGroup
+	case entry.IamMember != "":
+		member = "iamMember:" + entry.IamMember
+	default:
+		return ""
+	}
+	return fmt.Sprintf("%s %s", entry.Role, member)
+}
+
+// isPublicEntry reports whether a rendered access entry grants public access
+func isPublicEntry(entry string) bool {
+	_, member, _ := strings.Cut(entry, " ")
+	_, principal, _ := strings.Cut(member, ":")
+	return publicPrincipals[principal]
+}
+
+// formatExpiration renders a default table expiration in milliseconds as a duration
+func formatExpiration(ms int64) string {
+	if ms == 0 {
+		return "none"
+	}
+	return (time.Duration(ms) * time.Millisecond).String()
+}
+
+// sameExpiration compares expirations in any Go duration form ("720h" equals "720h0m0s")
+func sameExpiration(actual, expected string) bool {
+	if actual == expected {
+		return true
+	}
+	a, errA := time.ParseDuration(actual)
+	e, errE := time.ParseDuration(expected)
+	return errA == nil && errE == nil && a == e
+}
+
+// AnalyzeDrift compares discovered datasets against a baseline and generates a drift report
+func (a *Analyzer) AnalyzeDrift(datasets []*Dataset, baseline *DatasetConfig) *DriftReport {
+	report := &DriftReport{
+		Timestamp:     time.Now(),
+		TotalDatasets: len(datasets),
+		Datasets:      make([]*DatasetDrift, 0),
+		Errors:        a.ScanErrors(),
+	}
+
+	for _, ds := range datasets {
+		drift := a.analyzeDataset(ds, baseline)
+		report.Datasets = append(report.Datasets, drift)
+
+		if len(drift.Drifts) > 0 {
+			report.DriftedDatasets++
+		}
+	}
+
+	a.lastReport = report
+	return report
+}
+
+// analyzeDataset compares a single dataset against the baseline configuration.
+// Public access is always reported, even without a baseline.
+func (a *Analyzer) analyzeDataset(ds *Dataset, baseline *DatasetConfig) *DatasetDrift {
+	drift := &DatasetDrift{
+		Project:  ds.Project,
+		Name:     ds.Name,
+		Location: ds.Location,
+		Labels:   ds.Labels,
+		Drifts:   make([]Drift, 0),
+	}
+
+	actual := ds.Config
+
+	for _, entry := range actual.Access {
+		if isPublicEntry(entry) {
+			drift.Drifts = append(drift.Drifts, Drift{
+				Field:    "access",
+				Expected: "no public access",
+				Actual:   entry,
+				Severity: "critical",
+			})
+		}
+	}
+
+	if baseline == nil {
+		return drift
+	}
+
+	if baseline.Location != "" && !strings.EqualFold(actual.Location, baseline.Location) {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "location",
+			Expected: baseline.Location,
+			Actual:   actual.Location,
+			Severity: "high",
+		})
+	}
+
+	if baseline.DefaultTableExpiration != "" && !sameExpiration(actual.DefaultTableExpiration, baseline.DefaultTableExpiration) {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "default_table_expiration",
+			Expected: baseline.DefaultTableExpiration,
+			Actual:   actual.DefaultTableExpiration,
+			Severity: "medium",
+		})
+	}
+
+	if baseline.RequireCMEK != nil && *baseline.RequireCMEK && actual.KMSKeyName == "" {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "default_encryption_configuration.kms_key_name",
+			Expected: "customer-managed key",
+			Actual:   "Google-managed key",
+			Severity: "high",
+		})
+	} else if baseline.KMSKeyName != "" && actual.KMSKeyName != baseline.KMSKeyName {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "default_encryption_configuration.kms_key_name",
+			Expected: baseline.KMSKeyName,
+			Actual:   actual.KMSKeyName,
+			Severity: "high",
+		})
+	}
+
+	if len(baseline.Access) > 0 {
+		a.compareAccess(actual.Access, baseline.Access, drift)
+	}
+
+	return drift
+}
+
+// compareAccess reports required access entries that are missing and extra
+// entries not in the baseline. Public entries are already reported as critical.
+func (a *Analyzer) compareAccess(actual, baseline []string, drift *DatasetDrift) {
+	actualSet := make(map[string]bool)
+	for _, entry := range actual {
+		actualSet[entry] = true
+	}
+	baselineSet := make(map[string]bool)
+	for _, entry := range baseline {
+		baselineSet[entry] = true
+	}
+
+	missing := make([]string, 0)
+	for _, entry := range baseline {
+		if !actualSet[entry] {
+			missing = append(missing, entry)
+		}
+	}
+
+	extra := make([]string, 0)
+	for _, entry := range actual {
+		if !baselineSet[entry] && !isPublicEntry(entry) {
+			extra = append(extra, entry)
+		}
+	}
+
+	if len(missing) > 0 {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "access",
+			Expected: fmt.Sprintf("Required: %v", missing),
+			Actual:   fmt.Sprintf("%v", actual),
+			Severity: "medium",
+		})
+	}
+
+	if len(extra) > 0 {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "access",
+			Expected: fmt.Sprintf("%v", baseline),
+			Actual:   fmt.Sprintf("Extra: %v", extra),
+			Severity: "high",
+		})
+	}
+}
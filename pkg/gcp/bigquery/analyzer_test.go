@@ -0,0 +1,117 @@
+package bigquery
+
+import (
+	"testing"
+
+	bigqueryapi "google.golang.org/api/bigquery/v2"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestExtractConfig(t *testing.T) {
+	config := extractConfig(&bigqueryapi.Dataset{
+		Location:                 "EU",
+		DefaultTableExpirationMs: 30 * 24 * 3600 * 1000,
+		DefaultEncryptionConfiguration: &bigqueryapi.EncryptionConfiguration{
+			KmsKeyName: "projects/p/locations/eu/keyRings/r/cryptoKeys/k",
+		},
+		Access: []*bigqueryapi.DatasetAccess{
+			{Role: "WRITER", GroupByEmail: "etl@example.com"},
+			{Role: "READER", SpecialGroup: "projectReaders"},
+			{View: &bigqueryapi.TableReference{TableId: "v"}},
+		},
+	})
+
+	if config.DefaultTableExpiration != "720h0m0s" {
+		t.Errorf("DefaultTableExpiration = %v, want 720h0m0s", config.DefaultTableExpiration)
+	}
+	if !*config.RequireCMEK {
+		t.Error("RequireCMEK = false, want true")
+	}
+	want := []string{"READER specialGroup:projectReaders", "WRITER group:etl@example.com"}
+	if len(config.Access) != len(want) {
+		t.Fatalf("Access = %v, want %v", config.Access, want)
+	}
+	for i := range want {
+		if config.Access[i] != want[i] {
+			t.Errorf("Access[%d] = %v, want %v", i, config.Access[i], want[i])
+		}
+	}
+
+	if got := formatExpiration(0); got != "none" {
+		t.Errorf("formatExpiration(0) = %v, want none", got)
+	}
+}
+
+func TestAnalyzeDrift(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &DatasetConfig{
+		Location:               "EU",
+		DefaultTableExpiration: "720h",
+		RequireCMEK:            boolPtr(true),
+		Access:                 []string{"OWNER group:data-admins@example.com"},
+	}
+
+	compliant := &Dataset{Name: "good", Config: &DatasetConfig{
+		Location:               "EU",
+		DefaultTableExpiration: "720h0m0s",
+		KMSKeyName:             "key",
+		Access:                 []string{"OWNER group:data-admins@example.com"},
+	}}
+
+	drifted := &Dataset{Name: "bad", Config: &DatasetConfig{
+		Location:               "US",
+		DefaultTableExpiration: "none",
+		Access: []string{
+			"READER iamMember:allUsers",
+			"WRITER user:someone@example.com",
+		},
+	}}
+
+	report := a.AnalyzeDrift([]*Dataset{compliant, drifted}, baseline)
+	if report.DriftedDatasets != 1 {
+		t.Fatalf("DriftedDatasets = %d, want 1", report.DriftedDatasets)
+	}
+	if len(report.Datasets[0].Drifts) != 0 {
+		t.Errorf("compliant dataset drifts = %v, want none", report.Datasets[0].Drifts)
+	}
+
+	got := make(map[string][]string)
+	for _, d := range report.Datasets[1].Drifts {
+		got[d.Field] = append(got[d.Field], d.Severity)
+	}
+	want := map[string][]string{
+		"access":                   {"critical", "medium", "high"},
+		"location":                 {"high"},
+		"default_table_expiration": {"medium"},
+		"default_encryption_configuration.kms_key_name": {"high"},
+	}
+	for field, severities := range want {
+		if len(got[field]) != len(severities) {
+			t.Errorf("drift %s = %v, want %v", field, got[field], severities)
+			continue
+		}
+		for i := range severities {
+			if got[field][i] != severities[i] {
+				t.Errorf("drift %s[%d] severity = %q, want %q", field, i, got[field][i], severities[i])
+			}
+		}
+	}
+}
+
+func TestPublicAccessWithoutBaseline(t *testing.T) {
+	a := &Analyzer{}
+
+	ds := &Dataset{Name: "shared", Config: &DatasetConfig{
+		Access: []string{"READER specialGroup:allAuthenticatedUsers", "READER group:team@example.com"},
+	}}
+
+	report := a.AnalyzeDrift([]*Dataset{ds}, nil)
+	drifts := report.Datasets[0].Drifts
+	if len(drifts) != 1 || drifts[0].Severity != "critical" {
+		t.Errorf("drifts = %+v, want one critical public access drift", drifts)
+	}
+}
@@ -0,0 +1,57 @@
+package bigquery
+
+import (
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// BigQueryBaseline represents a BigQuery dataset baseline with optional filters
+type BigQueryBaseline struct {
+	Name         string            `yaml:"name,omitempty"`
+	FilterLabels map[string]string `yaml:"filter_labels,omitempty"`
+	Config       *DatasetConfig    `yaml:"config"`
+	// Metadata records when the baseline was last reviewed
+	Metadata analyzer.BaselineMetadata `yaml:"metadata,omitempty"`
+}
+
+// Compile-time interface implementation check
+var _ analyzer.Baseline = (*BigQueryBaseline)(nil)
+
+// GetName returns the baseline name implementing analyzer.Baseline interface
+func (b BigQueryBaseline) GetName() string {
+	return b.Name
+}
+
+// Validate checks if the baseline is valid implementing analyzer.Baseline interface
+func (b BigQueryBaseline) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("baseline name is required")
+	}
+	if err := b.Metadata.Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	return nil
+}
+
+// FilterByLabels returns datasets matching all of the given labels
+func FilterByLabels(datasets []*Dataset, labels map[string]string) []*Dataset {
+	if len(labels) == 0 {
+		return datasets
+	}
+
+	filtered := make([]*Dataset, 0)
+	for _, ds := range datasets {
+		matches := true
+		for key, value := range labels {
+			if ds.Labels[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, ds)
+		}
+	}
+	return filtered
+}
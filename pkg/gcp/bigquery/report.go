@@ -0,0 +1,153 @@
+package bigquery
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// DriftReport contains the complete analysis results for all BigQuery datasets
+type DriftReport struct {
+	Timestamp       time.Time       `json:"timestamp" yaml:"timestamp"`
+	TotalDatasets   int             `json:"total_datasets" yaml:"total_datasets"`
+	DriftedDatasets int             `json:"drifted_datasets" yaml:"drifted_datasets"`
+	Datasets        []*DatasetDrift `json:"datasets" yaml:"datasets"`
+	// Warnings flag a baseline that is due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []report.ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []report.Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []report.SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
+}
+
+// DatasetDrift represents drift analysis results for a single dataset
+type DatasetDrift struct {
+	Project  string            `json:"project" yaml:"project"`
+	Name     string            `json:"name" yaml:"name"`
+	Location string            `json:"location" yaml:"location"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Drifts   []Drift           `json:"drifts" yaml:"drifts"`
+}
+
+// Drift represents a single configuration difference from the baseline
+type Drift = report.Drift
+
+// FormatText generates a human-readable text report
+func (r *DriftReport) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+	sb.WriteString("  GCP BigQuery Drift Analysis Report\n")
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("Generated: %s\n", r.Timestamp.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Total Datasets: %d\n", r.TotalDatasets))
+	sb.WriteString(fmt.Sprintf("Datasets with Drift: %d\n", r.DriftedDatasets))
+
+	if r.TotalDatasets > 0 {
+		sb.WriteString(fmt.Sprintf("Compliance Rate: %.1f%%\n\n",
+			float64(r.TotalDatasets-r.DriftedDatasets)/float64(r.TotalDatasets)*100))
+	}
+
+	// Summary by severity
+	var all []Drift
+	for _, ds := range r.Datasets {
+		all = append(all, ds.Drifts...)
+	}
+	sb.WriteString(report.FormatDriftSummary(report.CountBySeverity(all)))
+	sb.WriteString(report.FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(report.FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(report.FormatSLO(r.SLO))
+
+	// Detailed dataset reports
+	for i, ds := range r.Datasets {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(ds.FormatText())
+	}
+	sb.WriteString(report.FormatScanErrors(r.Errors))
+
+	return sb.String()
+}
+
+// FormatText generates a formatted text representation of dataset drift details
+func (dd *DatasetDrift) FormatText() string {
+	var sb strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("75")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	divider := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("───────────────────────────────────────────────────────────────────────────────")
+
+	sb.WriteString(divider + "\n")
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("BigQuery Dataset: %s", dd.Name)) + "\n\n")
+	sb.WriteString(labelStyle.Render("Project:  ") + valueStyle.Render(dd.Project) + "\n")
+	sb.WriteString(labelStyle.Render("Location: ") + valueStyle.Render(dd.Location) + "\n")
+
+	sb.WriteString("\n")
+	sb.WriteString(report.FormatDrifts(dd.Drifts))
+
+	return sb.String()
+}
+
+// FormatJSON generates JSON output of the drift report
+func (r *DriftReport) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatYAML generates YAML output of the drift report
+func (r *DriftReport) FormatYAML() (string, error) {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToReport converts the BigQuery drift report into the generic report model
+func (r *DriftReport) ToReport() *report.Report {
+	resources := make([]report.Resource, 0, len(r.Datasets))
+	for _, ds := range r.Datasets {
+		resources = append(resources, report.Resource{
+			Type:     "BigQuery Dataset",
+			Project:  ds.Project,
+			Name:     ds.Name,
+			Location: ds.Location,
+			Labels:   ds.Labels,
+			Drifts:   ds.Drifts,
+		})
+	}
+
+	return &report.Report{
+		Title:            "GCP BigQuery Drift Analysis Report",
+		Timestamp:        r.Timestamp,
+		Resources:        resources,
+		Warnings:         r.Warnings,
+		Errors:           r.Errors,
+		Acknowledgements: r.Acknowledgements,
+		SLO:              r.SLO,
+	}
+}
@@ -0,0 +1,188 @@
+// Package credentials selects the credentials of every GCP client the tool
+// creates: a key file, an impersonated service account and a quota project
+// can replace the application default credentials without changing the
+// ambient gcloud or GOOGLE_APPLICATION_CREDENTIALS configuration.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"cloud.google.com/go/cloudsqlconn"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+)
+
+const (
+	// CloudPlatformScope is requested for API tokens; IAM roles restrict
+	// what it grants
+	CloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	// SQLLoginScope is requested for the tokens Cloud SQL IAM database
+	// authentication logs in with
+	SQLLoginScope = "https://www.googleapis.com/auth/sqlservice.login"
+)
+
+// Config selects the credentials clients authenticate with. The zero value
+// uses the application default credentials.
+type Config struct {
+	// File is a service account key or external account configuration
+	File string
+	// ImpersonateServiceAccount is the email of a service account whose
+	// short-lived tokens are used, minted with the credentials from File or
+	// the application default credentials. The caller needs
+	// roles/iam.serviceAccountTokenCreator on it.
+	ImpersonateServiceAccount string
+	// QuotaProject is billed for API quota instead of the credentials' project
+	QuotaProject string
+}
+
+var (
+	mu      sync.RWMutex
+	current Config
+	// tokens is nil when clients use the application default credentials
+	tokens oauth2.TokenSource
+	// loginTokens are the tokens of the same principal for IAM database
+	// authentication
+	loginTokens oauth2.TokenSource
+	// projectID is the project of the key file, if any
+	projectID string
+)
+
+// Configure sets the credentials of clients created afterwards. It reads the
+// key file and prepares impersonation, but tokens are only fetched when a
+// client first calls an API.
+func Configure(ctx context.Context, config Config) error {
+	ts, project, err := tokenSource(ctx, config, CloudPlatformScope)
+	if err != nil {
+		return err
+	}
+	login, _, err := tokenSource(ctx, config, SQLLoginScope)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	current, tokens, loginTokens, projectID = config, ts, login, project
+	return nil
+}
+
+// tokenSource returns the tokens of the configured principal with a scope
+// and the project of the key file, or nil without a key file or
+// impersonation
+func tokenSource(ctx context.Context, config Config, scope string) (oauth2.TokenSource, string, error) {
+	var ts oauth2.TokenSource
+	var project string
+	var base []option.ClientOption
+	if config.File != "" {
+		data, err := os.ReadFile(config.File)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read credentials file: %w", err)
+		}
+		creds, err := google.CredentialsFromJSON(ctx, data, scope)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid credentials file %s: %w", config.File, err)
+		}
+		ts, project = creds.TokenSource, creds.ProjectID
+		// The impersonation request itself needs cloud-platform
+		base, err = fileOptions(ctx, data, ts, scope)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	if config.ImpersonateServiceAccount != "" {
+		impersonated, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: config.ImpersonateServiceAccount,
+			Scopes:          []string{scope},
+		}, base...)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to impersonate %s: %w", config.ImpersonateServiceAccount, err)
+		}
+		ts = impersonated
+	}
+	return ts, project, nil
+}
+
+// fileOptions returns the client options impersonation requests are made
+// with: the key file's tokens, scoped for cloud-platform
+func fileOptions(ctx context.Context, data []byte, ts oauth2.TokenSource, scope string) ([]option.ClientOption, error) {
+	if scope != CloudPlatformScope {
+		creds, err := google.CredentialsFromJSON(ctx, data, CloudPlatformScope)
+		if err != nil {
+			return nil, err
+		}
+		ts = creds.TokenSource
+	}
+	return []option.ClientOption{option.WithTokenSource(ts)}, nil
+}
+
+// ClientOptions returns the options for Google API clients, e.g.
+// sqladmin.NewService(ctx, credentials.ClientOptions()...)
+func ClientOptions() []option.ClientOption {
+	mu.RLock()
+	defer mu.RUnlock()
+	var opts []option.ClientOption
+	if tokens != nil {
+		opts = append(opts, option.WithTokenSource(tokens))
+	}
+	if current.QuotaProject != "" {
+		opts = append(opts, option.WithQuotaProject(current.QuotaProject))
+	}
+	return opts
+}
+
+// DialerOptions returns the options for the Cloud SQL connector. With
+// configured credentials the dialer also gets login tokens, so single dials
+// can enable IAM database authentication.
+func DialerOptions() []cloudsqlconn.Option {
+	mu.RLock()
+	defer mu.RUnlock()
+	var opts []cloudsqlconn.Option
+	if tokens != nil {
+		opts = append(opts, cloudsqlconn.WithIAMAuthN(), cloudsqlconn.WithIAMAuthNTokenSources(tokens, loginTokens))
+	}
+	if current.QuotaProject != "" {
+		opts = append(opts, cloudsqlconn.WithQuotaProject(current.QuotaProject))
+	}
+	return opts
+}
+
+// TokenSource returns the token source for clients outside the Google API
+// libraries, such as the Kubernetes API of a GKE cluster
+func TokenSource(ctx context.Context) (oauth2.TokenSource, error) {
+	mu.RLock()
+	ts := tokens
+	mu.RUnlock()
+	if ts != nil {
+		return ts, nil
+	}
+	ts, err := google.DefaultTokenSource(ctx, CloudPlatformScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find default credentials: %w", err)
+	}
+	return ts, nil
+}
+
+// ProjectID returns the project the credentials belong to: the quota
+// project, the project of the key file or that of the application default
+// credentials
+func ProjectID(ctx context.Context) (string, error) {
+	mu.RLock()
+	quota, project := current.QuotaProject, projectID
+	mu.RUnlock()
+	if quota != "" {
+		return quota, nil
+	}
+	if project != "" {
+		return project, nil
+	}
+	creds, err := google.FindDefaultCredentials(ctx, CloudPlatformScope)
+	if err != nil {
+		return "", err
+	}
+	return creds.ProjectID, nil
+}
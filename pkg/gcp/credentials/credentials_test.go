@@ -0,0 +1,90 @@
+package credentials
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeKeyFile writes a service account key of project to a temporary file
+func writeKeyFile(t *testing.T, project string) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"project_id":     project,
+		"private_key_id": "1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})),
+		"client_email":   "drift@" + project + ".iam.gserviceaccount.com",
+		"token_uri":      "https://oauth2.googleapis.com/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigure(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { _ = Configure(ctx, Config{}) })
+
+	if err := Configure(ctx, Config{}); err != nil {
+		t.Fatal(err)
+	}
+	if opts := ClientOptions(); len(opts) != 0 {
+		t.Errorf("default ClientOptions() = %d options, want none", len(opts))
+	}
+	if opts := DialerOptions(); len(opts) != 0 {
+		t.Errorf("default DialerOptions() = %d options, want none", len(opts))
+	}
+
+	keyFile := writeKeyFile(t, "audit-project")
+	if err := Configure(ctx, Config{File: keyFile}); err != nil {
+		t.Fatal(err)
+	}
+	if opts := ClientOptions(); len(opts) != 1 {
+		t.Errorf("ClientOptions() = %d options, want the token source", len(opts))
+	}
+	if project, err := ProjectID(ctx); err != nil || project != "audit-project" {
+		t.Errorf("ProjectID() = %q, %v, want the key's project", project, err)
+	}
+
+	// Impersonation mints tokens lazily, so configuring it needs no network
+	config := Config{File: keyFile, ImpersonateServiceAccount: "drift-audit@ops.iam.gserviceaccount.com", QuotaProject: "ops"}
+	if err := Configure(ctx, config); err != nil {
+		t.Fatal(err)
+	}
+	if opts := ClientOptions(); len(opts) != 2 {
+		t.Errorf("ClientOptions() = %d options, want the token source and quota project", len(opts))
+	}
+	if opts := DialerOptions(); len(opts) != 3 {
+		t.Errorf("DialerOptions() = %d options, want IAM authentication, the token sources and quota project", len(opts))
+	}
+	if project, _ := ProjectID(ctx); project != "ops" {
+		t.Errorf("ProjectID() = %q, want the quota project", project)
+	}
+	if _, err := TokenSource(ctx); err != nil {
+		t.Errorf("TokenSource() error = %v", err)
+	}
+
+	if err := Configure(ctx, Config{File: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("Configure() with a missing key file succeeded")
+	}
+	// A failed Configure keeps the previous credentials
+	if project, _ := ProjectID(ctx); project != "ops" {
+		t.Errorf("ProjectID() after failed Configure = %q", project)
+	}
+}
@@ -3,10 +3,14 @@ package gke
 import (
 	"context"
 	"fmt"
-
+	"path"
+	"strings"
 	"time"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
 	container "google.golang.org/api/container/v1"
 )
 
@@ -19,6 +23,15 @@ type ClusterInstance struct {
 	Config    *ClusterConfig
 	NodePools []*NodePoolConfig
 	Labels    map[string]string
+	Inventory *Inventory
+
+	// Endpoint and CACertificate, a base64 PEM bundle, reach the cluster's
+	// Kubernetes API
+	Endpoint      string
+	CACertificate string
+
+	// Conditions are the messages explaining an unhealthy status
+	Conditions []string
 }
 
 // ClusterConfig holds the cluster-level configuration
@@ -48,6 +61,14 @@ type ClusterConfig struct {
 	Addons            *AddonsConfig      `yaml:"addons,omitempty" json:"addons,omitempty"`
 	LoggingConfig     *LoggingConfig     `yaml:"logging_config,omitempty" json:"logging_config,omitempty"`
 	MonitoringConfig  *MonitoringConfig  `yaml:"monitoring_config,omitempty" json:"monitoring_config,omitempty"`
+	// ClusterAutoscaling is the cluster-level autoscaling, including node
+	// auto-provisioning; unset skips the comparison
+	ClusterAutoscaling *ClusterAutoscalingConfig `yaml:"cluster_autoscaling,omitempty" json:"cluster_autoscaling,omitempty"`
+
+	// AllowedValues lists acceptable values per cluster field, replacing the single expected value
+	AllowedValues checks.Allowed `yaml:"allowed_values,omitempty" json:"allowed_values,omitempty"`
+	// SeverityOverrides replaces the built-in severity per cluster field
+	SeverityOverrides checks.SeverityOverrides `yaml:"severity_overrides,omitempty" json:"severity_overrides,omitempty"`
 }
 
 // IPAllocationPolicy holds IP allocation configuration
@@ -86,7 +107,16 @@ type NodePoolConfig struct {
 	AutoRepair       bool               `yaml:"auto_repair" json:"auto_repair"`
 	ServiceAccount   string             `yaml:"service_account,omitempty" json:"service_account,omitempty"`
 	Labels           map[string]string  `yaml:"labels,omitempty" json:"labels,omitempty"`
-	Taints           []string           `yaml:"taints,omitempty" json:"taints,omitempty"`
+	// AllowedValues lists acceptable values per node pool field (e.g. "nodepool[*].machine_type")
+	AllowedValues checks.Allowed `yaml:"allowed_values,omitempty" json:"allowed_values,omitempty"`
+	// SeverityOverrides replaces the built-in severity per node pool field
+	SeverityOverrides checks.SeverityOverrides `yaml:"severity_overrides,omitempty" json:"severity_overrides,omitempty"`
+	Taints            []string                 `yaml:"taints,omitempty" json:"taints,omitempty"`
+	// Ranges accepts numeric node pool fields within bounds or a tolerance
+	Ranges checks.Ranges `yaml:"ranges,omitempty" json:"ranges,omitempty"`
+	// ForbiddenTaints are shell wildcard patterns of taints no pool may carry,
+	// e.g. "dedicated=*:NO_SCHEDULE"; * also matches the / of prefixed keys
+	ForbiddenTaints []string `yaml:"forbidden_taints,omitempty" json:"forbidden_taints,omitempty"`
 }
 
 // AutoscalingConfig holds autoscaling settings
@@ -96,6 +126,24 @@ type AutoscalingConfig struct {
 	MaxNodeCount int64 `yaml:"max_node_count" json:"max_node_count"`
 }
 
+// ClusterAutoscalingConfig holds cluster-level autoscaling settings
+type ClusterAutoscalingConfig struct {
+	// NodeAutoProvisioning lets GKE create and delete node pools for
+	// pending pods
+	NodeAutoProvisioning bool `yaml:"node_auto_provisioning" json:"node_auto_provisioning"`
+	// AutoscalingProfile is BALANCED or OPTIMIZE_UTILIZATION
+	AutoscalingProfile string `yaml:"autoscaling_profile,omitempty" json:"autoscaling_profile,omitempty"`
+	// ResourceLimits bound the resources node auto-provisioning may add, by
+	// resource type: cpu, memory (GB) or a GPU type such as nvidia-tesla-t4
+	ResourceLimits map[string]ResourceLimit `yaml:"resource_limits,omitempty" json:"resource_limits,omitempty"`
+}
+
+// ResourceLimit bounds the total amount of a resource in the cluster
+type ResourceLimit struct {
+	Minimum int64 `yaml:"minimum,omitempty" json:"minimum,omitempty"`
+	Maximum int64 `yaml:"maximum" json:"maximum"`
+}
+
 // MaintenanceWindow defines cluster maintenance window
 type MaintenanceWindow struct {
 	StartTime string `yaml:"start_time" json:"start_time"`
@@ -111,19 +159,30 @@ type AddonsConfig struct {
 
 // Analyzer performs drift analysis on GKE clusters
 type Analyzer struct {
+	analyzer.ProjectErrors
+
 	service    *container.Service
 	lastReport *DriftReport
 	projects   []string
+	retry      retry.Policy
+	statuses   analyzer.OperationalSeverities
+	labels     *analyzer.LabelPolicy
 }
 
 // NewAnalyzer creates a new GKE Analyzer instance
 func NewAnalyzer(ctx context.Context) (*Analyzer, error) {
-	service, err := container.NewService(ctx)
+	service, err := container.NewService(ctx, credentials.ClientOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GKE client: %w", err)
 	}
 
-	return &Analyzer{service: service}, nil
+	return &Analyzer{service: service, retry: retry.DefaultPolicy()}, nil
+}
+
+// SetRetryPolicy controls how GKE API calls are retried on rate limits,
+// quota and server errors
+func (a *Analyzer) SetRetryPolicy(p retry.Policy) {
+	a.retry = p
 }
 
 // Close releases resources held by the Analyzer
@@ -163,6 +222,9 @@ func (a *Analyzer) DiscoverClusters(ctx context.Context, projects []string) ([]*
 	for _, project := range projects {
 		projectClusters, err := a.discoverProjectClusters(ctx, project)
 		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
 			return nil, fmt.Errorf("failed to discover clusters in project %s: %w", project, err)
 		}
 		clusters = append(clusters, projectClusters...)
@@ -174,7 +236,7 @@ func (a *Analyzer) DiscoverClusters(ctx context.Context, projects []string) ([]*
 // discoverProjectClusters lists all GKE clusters in a single GCP project
 func (a *Analyzer) discoverProjectClusters(ctx context.Context, project string) ([]*ClusterInstance, error) {
 	parent := fmt.Sprintf("projects/%s/locations/-", project)
-	resp, err := a.service.Projects.Locations.Clusters.List(parent).Context(ctx).Do()
+	resp, err := retry.Do(ctx, a.retry, a.service.Projects.Locations.Clusters.List(parent).Context(ctx).Do)
 	if err != nil {
 		return nil, err
 	}
@@ -189,6 +251,16 @@ func (a *Analyzer) discoverProjectClusters(ctx context.Context, project string)
 			Config:    extractClusterConfig(cluster),
 			NodePools: extractNodePools(cluster),
 			Labels:    cluster.ResourceLabels,
+			Inventory: buildInventory(cluster),
+			Endpoint:  cluster.Endpoint,
+		}
+		if cluster.MasterAuth != nil {
+			clusterInstance.CACertificate = cluster.MasterAuth.ClusterCaCertificate
+		}
+		for _, condition := range cluster.Conditions {
+			if condition.Message != "" {
+				clusterInstance.Conditions = append(clusterInstance.Conditions, condition.Message)
+			}
 		}
 
 		clusters = append(clusters, clusterInstance)
@@ -235,6 +307,9 @@ func extractClusterConfig(cluster *container.Cluster) *ClusterConfig {
 	// Extract maintenance window
 	config.MaintenanceWindow = extractMaintenanceWindow(cluster)
 
+	// Extract cluster autoscaling and node auto-provisioning
+	config.ClusterAutoscaling = extractClusterAutoscaling(cluster)
+
 	return config
 }
 
@@ -286,15 +361,16 @@ func extractNodePools(cluster *container.Cluster) []*NodePoolConfig {
 }
 
 // AnalyzeDrift compares discovered clusters against a baseline and generates a drift report
-func (a *Analyzer) AnalyzeDrift(clusters []*ClusterInstance, baseline *ClusterConfig, nodePoolBaseline *NodePoolConfig) *DriftReport {
+func (a *Analyzer) AnalyzeDrift(clusters []*ClusterInstance, baseline *ClusterConfig, nodePools *NodePoolBaselines) *DriftReport {
 	report := &DriftReport{
 		Timestamp:     time.Now(),
 		TotalClusters: len(clusters),
 		Instances:     make([]*ClusterDrift, 0),
+		Errors:        a.ScanErrors(),
 	}
 
 	for _, cluster := range clusters {
-		drift := a.analyzeCluster(cluster, baseline, nodePoolBaseline)
+		drift := a.analyzeCluster(cluster, baseline, nodePools)
 		report.Instances = append(report.Instances, drift)
 
 		if len(drift.Drifts) > 0 {
@@ -307,7 +383,7 @@ func (a *Analyzer) AnalyzeDrift(clusters []*ClusterInstance, baseline *ClusterCo
 }
 
 // analyzeCluster compares a single cluster against the baseline configuration
-func (a *Analyzer) analyzeCluster(cluster *ClusterInstance, baseline *ClusterConfig, nodePoolBaseline *NodePoolConfig) *ClusterDrift {
+func (a *Analyzer) analyzeCluster(cluster *ClusterInstance, baseline *ClusterConfig, nodePools *NodePoolBaselines) *ClusterDrift {
 	drift := &ClusterDrift{
 		Project:   cluster.Project,
 		Name:      cluster.Name,
@@ -315,21 +391,33 @@ func (a *Analyzer) analyzeCluster(cluster *ClusterInstance, baseline *ClusterCon
 		Status:    cluster.Status,
 		Labels:    cluster.Labels,
 		NodePools: cluster.NodePools,
+		Inventory: cluster.Inventory,
 		Drifts:    make([]Drift, 0),
 	}
 
 	if baseline == nil {
+		a.checkLabelPolicy(cluster, drift)
+		a.checkOperationalStatus(cluster, drift)
 		return drift
 	}
 
 	// Compare cluster config
 	a.compareClusterConfig(cluster.Config, baseline, drift)
+	baseline.SeverityOverrides.Apply(drift.Drifts)
 
 	// Compare node pools
-	if nodePoolBaseline != nil {
-		a.compareNodePools(cluster.NodePools, nodePoolBaseline, drift)
+	if nodePools != nil {
+		a.compareNodePools(cluster.NodePools, nodePools, drift)
 	}
 
+	// Check the organization-wide label policy
+	a.checkLabelPolicy(cluster, drift)
+
+	// Report unhealthy statuses apart from configuration drift
+	a.checkOperationalStatus(cluster, drift)
+
+	drift.Recommendations = recreationRecommendations(drift.Drifts)
+
 	return drift
 }
 
@@ -342,6 +430,9 @@ func (a *Analyzer) compareClusterConfig(actual, baseline *ClusterConfig, drift *
 	// Core cluster features
 	a.compareCoreFeaturesCluster(actual, baseline, drift)
 
+	// Creation-time settings that can only be fixed by recreating the cluster
+	a.compareImmutable(actual, baseline, drift)
+
 	// Networking
 	a.compareNetworking(actual, baseline, drift)
 
@@ -355,6 +446,9 @@ func (a *Analyzer) compareClusterConfig(actual, baseline *ClusterConfig, drift *
 	a.compareLoggingCluster(actual, baseline, drift)
 	a.compareMonitoringCluster(actual, baseline, drift)
 
+	// Cluster autoscaling and node auto-provisioning
+	a.compareClusterAutoscaling(actual, baseline, drift)
+
 	// Compare master authorized networks if specified in baseline
 	if len(baseline.MasterAuthorizedNets) > 0 {
 		a.compareMasterAuthorizedNetworks(baseline, actual, drift)
@@ -363,285 +457,174 @@ func (a *Analyzer) compareClusterConfig(actual, baseline *ClusterConfig, drift *
 
 // compareVersion compares master version
 func (a *Analyzer) compareVersion(actual, baseline *ClusterConfig, drift *ClusterDrift) {
-	if baseline.MasterVersion != "" {
-		actualMinor := extractMinorVersion(actual.MasterVersion)
-		baselineMinor := extractMinorVersion(baseline.MasterVersion)
-		if actualMinor != baselineMinor {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    "cluster.master_version",
-				Expected: baseline.MasterVersion,
-				Actual:   actual.MasterVersion,
-				Severity: "high",
-			})
+	actualMinor := extractMinorVersion(actual.MasterVersion)
+
+	if versions := baseline.AllowedValues.Values(checkMasterVersion.Path); len(versions) > 0 {
+		for _, v := range versions {
+			if extractMinorVersion(v) == actualMinor {
+				return
+			}
 		}
+		drift.Drifts = checkMasterVersion.Append(drift.Drifts, checks.DescribeValues(versions), actual.MasterVersion)
+		return
+	}
+
+	if baseline.MasterVersion != "" && actualMinor != extractMinorVersion(baseline.MasterVersion) {
+		drift.Drifts = checkMasterVersion.Append(drift.Drifts, baseline.MasterVersion, actual.MasterVersion)
 	}
 }
 
 // compareReleaseChannel compares release channel
 func (a *Analyzer) compareReleaseChannel(actual, baseline *ClusterConfig, drift *ClusterDrift) {
-	if baseline.ReleaseChannel != "" && actual.ReleaseChannel != baseline.ReleaseChannel {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.release_channel",
-			Expected: baseline.ReleaseChannel,
-			Actual:   actual.ReleaseChannel,
-			Severity: "medium",
-		})
-	}
+	drift.Drifts = checkReleaseChannel.OneOf(drift.Drifts, baseline.AllowedValues, baseline.ReleaseChannel, actual.ReleaseChannel)
 }
 
 // compareCoreFeaturesCluster compares core cluster features
 func (a *Analyzer) compareCoreFeaturesCluster(actual, baseline *ClusterConfig, drift *ClusterDrift) {
-	if actual.PrivateCluster != baseline.PrivateCluster {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.private_cluster",
-			Expected: fmt.Sprintf("%v", baseline.PrivateCluster),
-			Actual:   fmt.Sprintf("%v", actual.PrivateCluster),
-			Severity: "critical",
-		})
+	drift.Drifts = checkPrivateCluster.Bool(drift.Drifts, baseline.PrivateCluster, actual.PrivateCluster)
+	drift.Drifts = checkWorkloadIdentity.Bool(drift.Drifts, baseline.WorkloadIdentity, actual.WorkloadIdentity)
+	drift.Drifts = checkNetworkPolicy.Bool(drift.Drifts, baseline.NetworkPolicy, actual.NetworkPolicy)
+	drift.Drifts = checkBinaryAuthorization.Bool(drift.Drifts, baseline.BinaryAuthorization, actual.BinaryAuthorization)
+}
+
+// compareImmutable compares settings fixed at cluster creation: the VPC network,
+// subnetwork and IP allocation (VPC-native mode and pod/service ranges)
+func (a *Analyzer) compareImmutable(actual, baseline *ClusterConfig, drift *ClusterDrift) {
+	if baseline.Network != "" && path.Base(actual.Network) != path.Base(baseline.Network) {
+		drift.Drifts = checkNetwork.Append(drift.Drifts, baseline.Network, actual.Network)
+	}
+
+	if baseline.Subnetwork != "" && path.Base(actual.Subnetwork) != path.Base(baseline.Subnetwork) {
+		drift.Drifts = checkSubnetwork.Append(drift.Drifts, baseline.Subnetwork, actual.Subnetwork)
 	}
 
-	if actual.WorkloadIdentity != baseline.WorkloadIdentity {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.workload_identity",
-			Expected: fmt.Sprintf("%v", baseline.WorkloadIdentity),
-			Actual:   fmt.Sprintf("%v", actual.WorkloadIdentity),
-			Severity: "high",
-		})
+	if baseline.IPAllocationPolicy == nil {
+		return
 	}
 
-	if actual.NetworkPolicy != baseline.NetworkPolicy {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.network_policy",
-			Expected: fmt.Sprintf("%v", baseline.NetworkPolicy),
-			Actual:   fmt.Sprintf("%v", actual.NetworkPolicy),
-			Severity: "high",
-		})
+	actualPolicy := actual.IPAllocationPolicy
+	if actualPolicy == nil {
+		actualPolicy = &IPAllocationPolicy{}
 	}
 
-	if actual.BinaryAuthorization != baseline.BinaryAuthorization {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.binary_authorization",
-			Expected: fmt.Sprintf("%v", baseline.BinaryAuthorization),
-			Actual:   fmt.Sprintf("%v", actual.BinaryAuthorization),
-			Severity: "high",
-		})
+	if baseline.IPAllocationPolicy.UseIPAliases {
+		drift.Drifts = checkIPAliases.Bool(drift.Drifts, true, actualPolicy.UseIPAliases)
 	}
+	drift.Drifts = checkClusterIPv4CIDR.String(drift.Drifts,
+		baseline.IPAllocationPolicy.ClusterIPv4CIDR, actualPolicy.ClusterIPv4CIDR)
+	drift.Drifts = checkServicesIPv4CIDR.String(drift.Drifts,
+		baseline.IPAllocationPolicy.ServicesIPv4CIDR, actualPolicy.ServicesIPv4CIDR)
 }
 
-// compareNetworking compares networking configuration
-func (a *Analyzer) compareNetworking(actual, baseline *ClusterConfig, drift *ClusterDrift) {
-	if baseline.DatapathProvider != "" && actual.DatapathProvider != baseline.DatapathProvider {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.datapath_provider",
-			Expected: baseline.DatapathProvider,
-			Actual:   actual.DatapathProvider,
-			Severity: "medium",
-		})
+// recreationRecommendations explains the impact of fixing immutable drifts
+func recreationRecommendations(drifts []Drift) []string {
+	var fields []string
+	for _, d := range drifts {
+		if d.Immutable {
+			fields = append(fields, d.Field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
 	}
 
-	if actual.MasterGlobalAccess != baseline.MasterGlobalAccess {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.master_global_access",
-			Expected: fmt.Sprintf("%v", baseline.MasterGlobalAccess),
-			Actual:   fmt.Sprintf("%v", actual.MasterGlobalAccess),
-			Severity: "medium",
-		})
+	return []string{
+		fmt.Sprintf("RECREATE: %s cannot be changed in place", strings.Join(fields, ", ")),
+		"Recreation impact: a new cluster must be created and workloads migrated; the control plane endpoint, node IPs and pod/service ranges change, and persistent volumes must be re-attached or restored",
+		"Plan a blue/green migration: create the replacement cluster from the baseline, shift traffic, then delete the drifted cluster",
 	}
 }
 
+// compareNetworking compares networking configuration
+func (a *Analyzer) compareNetworking(actual, baseline *ClusterConfig, drift *ClusterDrift) {
+	drift.Drifts = checkDatapathProvider.OneOf(drift.Drifts, baseline.AllowedValues, baseline.DatapathProvider, actual.DatapathProvider)
+	drift.Drifts = checkMasterGlobalAccess.Bool(drift.Drifts, baseline.MasterGlobalAccess, actual.MasterGlobalAccess)
+}
+
 // compareIPAllocation compares IP allocation policy
 func (a *Analyzer) compareIPAllocation(actual, baseline *ClusterConfig, drift *ClusterDrift) {
-	if baseline.IPAllocationPolicy != nil && actual.IPAllocationPolicy != nil {
-		if baseline.IPAllocationPolicy.StackType != "" &&
-			actual.IPAllocationPolicy.StackType != baseline.IPAllocationPolicy.StackType {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    "cluster.ip_allocation_policy.stack_type",
-				Expected: baseline.IPAllocationPolicy.StackType,
-				Actual:   actual.IPAllocationPolicy.StackType,
-				Severity: "high",
-			})
-		}
+	if actual.IPAllocationPolicy == nil {
+		return
+	}
+	expected := ""
+	if baseline.IPAllocationPolicy != nil {
+		expected = baseline.IPAllocationPolicy.StackType
 	}
+	drift.Drifts = checkStackType.OneOf(drift.Drifts, baseline.AllowedValues, expected, actual.IPAllocationPolicy.StackType)
 }
 
 // compareSecurityCluster compares security features
 func (a *Analyzer) compareSecurityCluster(actual, baseline *ClusterConfig, drift *ClusterDrift) {
-	if actual.ShieldedNodes != baseline.ShieldedNodes {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.shielded_nodes",
-			Expected: fmt.Sprintf("%v", baseline.ShieldedNodes),
-			Actual:   fmt.Sprintf("%v", actual.ShieldedNodes),
-			Severity: "high",
-		})
-	}
-
-	if actual.DatabaseEncryption != baseline.DatabaseEncryption {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.database_encryption",
-			Expected: fmt.Sprintf("%v", baseline.DatabaseEncryption),
-			Actual:   fmt.Sprintf("%v", actual.DatabaseEncryption),
-			Severity: "critical",
-		})
-	}
-
-	if baseline.SecurityPosture != "" && actual.SecurityPosture != baseline.SecurityPosture {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.security_posture",
-			Expected: baseline.SecurityPosture,
-			Actual:   actual.SecurityPosture,
-			Severity: "high",
-		})
-	}
+	drift.Drifts = checkShieldedNodes.Bool(drift.Drifts, baseline.ShieldedNodes, actual.ShieldedNodes)
+	drift.Drifts = checkDatabaseEncryption.Bool(drift.Drifts, baseline.DatabaseEncryption, actual.DatabaseEncryption)
+	drift.Drifts = checkSecurityPosture.OneOf(drift.Drifts, baseline.AllowedValues, baseline.SecurityPosture, actual.SecurityPosture)
 }
 
 // compareLoggingCluster compares logging configuration
 func (a *Analyzer) compareLoggingCluster(actual, baseline *ClusterConfig, drift *ClusterDrift) {
 	if baseline.LoggingConfig != nil && actual.LoggingConfig != nil {
-		if actual.LoggingConfig.EnableSystemLogs != baseline.LoggingConfig.EnableSystemLogs {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    "cluster.logging_config.enable_system_logs",
-				Expected: fmt.Sprintf("%v", baseline.LoggingConfig.EnableSystemLogs),
-				Actual:   fmt.Sprintf("%v", actual.LoggingConfig.EnableSystemLogs),
-				Severity: "medium",
-			})
-		}
-		if actual.LoggingConfig.EnableWorkloadLogs != baseline.LoggingConfig.EnableWorkloadLogs {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    "cluster.logging_config.enable_workload_logs",
-				Expected: fmt.Sprintf("%v", baseline.LoggingConfig.EnableWorkloadLogs),
-				Actual:   fmt.Sprintf("%v", actual.LoggingConfig.EnableWorkloadLogs),
-				Severity: "low",
-			})
-		}
+		drift.Drifts = checkSystemLogs.Bool(drift.Drifts,
+			baseline.LoggingConfig.EnableSystemLogs, actual.LoggingConfig.EnableSystemLogs)
+		drift.Drifts = checkWorkloadLogs.Bool(drift.Drifts,
+			baseline.LoggingConfig.EnableWorkloadLogs, actual.LoggingConfig.EnableWorkloadLogs)
 	}
 }
 
 // compareMonitoringCluster compares monitoring configuration
 func (a *Analyzer) compareMonitoringCluster(actual, baseline *ClusterConfig, drift *ClusterDrift) {
 	if baseline.MonitoringConfig != nil && actual.MonitoringConfig != nil {
-		if actual.MonitoringConfig.EnableSystemMetrics != baseline.MonitoringConfig.EnableSystemMetrics {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    "cluster.monitoring_config.enable_system_metrics",
-				Expected: fmt.Sprintf("%v", baseline.MonitoringConfig.EnableSystemMetrics),
-				Actual:   fmt.Sprintf("%v", actual.MonitoringConfig.EnableSystemMetrics),
-				Severity: "medium",
-			})
-		}
-		if actual.MonitoringConfig.EnableAPIServerMetrics != baseline.MonitoringConfig.EnableAPIServerMetrics {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    "cluster.monitoring_config.enable_apiserver_metrics",
-				Expected: fmt.Sprintf("%v", baseline.MonitoringConfig.EnableAPIServerMetrics),
-				Actual:   fmt.Sprintf("%v", actual.MonitoringConfig.EnableAPIServerMetrics),
-				Severity: "low",
-			})
-		}
+		drift.Drifts = checkSystemMetrics.Bool(drift.Drifts,
+			baseline.MonitoringConfig.EnableSystemMetrics, actual.MonitoringConfig.EnableSystemMetrics)
+		drift.Drifts = checkAPIServerMetrics.Bool(drift.Drifts,
+			baseline.MonitoringConfig.EnableAPIServerMetrics, actual.MonitoringConfig.EnableAPIServerMetrics)
 	}
 }
 
-// compareMasterAuthorizedNetworks compares master authorized network lists between baseline and actual
-func (a *Analyzer) compareMasterAuthorizedNetworks(baseline, actual *ClusterConfig, drift *ClusterDrift) {
-	// Create sets for comparison
-	baselineNets := make(map[string]bool)
-	for _, net := range baseline.MasterAuthorizedNets {
-		baselineNets[net] = true
+// compareClusterAutoscaling compares cluster-level autoscaling. Resource
+// limits are only compared when both enable node auto-provisioning; a limit
+// the baseline sets but the cluster lacks is reported as not set.
+func (a *Analyzer) compareClusterAutoscaling(actual, baseline *ClusterConfig, drift *ClusterDrift) {
+	expected := baseline.ClusterAutoscaling
+	if expected == nil {
+		return
 	}
-
-	actualNets := make(map[string]bool)
-	for _, net := range actual.MasterAuthorizedNets {
-		actualNets[net] = true
+	current := actual.ClusterAutoscaling
+	if current == nil {
+		current = &ClusterAutoscalingConfig{}
 	}
 
-	// Find required networks (in baseline but not in actual)
-	requiredNets := make([]string, 0)
-	for net := range baselineNets {
-		if !actualNets[net] {
-			requiredNets = append(requiredNets, net)
-		}
+	drift.Drifts = checkNodeAutoProvisioning.Bool(drift.Drifts, expected.NodeAutoProvisioning, current.NodeAutoProvisioning)
+	drift.Drifts = checkAutoscalingProfile.OneOf(drift.Drifts, baseline.AllowedValues, expected.AutoscalingProfile, current.AutoscalingProfile)
+	if !expected.NodeAutoProvisioning || !current.NodeAutoProvisioning {
+		return
 	}
 
-	// Find extra networks (in actual but not in baseline)
-	extraNets := make([]string, 0)
-	for net := range actualNets {
-		if !baselineNets[net] {
-			extraNets = append(extraNets, net)
+	for _, resource := range sortedKeys(expected.ResourceLimits) {
+		limit := expected.ResourceLimits[resource]
+		actualLimit, exists := current.ResourceLimits[resource]
+		if !exists {
+			drift.Drifts = checkResourceLimitMax.At(resource).Append(drift.Drifts, fmt.Sprintf("%d", limit.Maximum), "not set")
+			continue
 		}
+		drift.Drifts = checkResourceLimitMin.At(resource).Int(drift.Drifts, limit.Minimum, actualLimit.Minimum)
+		drift.Drifts = checkResourceLimitMax.At(resource).Int(drift.Drifts, limit.Maximum, actualLimit.Maximum)
 	}
+}
+
+// compareMasterAuthorizedNetworks compares master authorized network lists between baseline and actual
+func (a *Analyzer) compareMasterAuthorizedNetworks(baseline, actual *ClusterConfig, drift *ClusterDrift) {
+	required, extra := checks.SetDiff(baseline.MasterAuthorizedNets, actual.MasterAuthorizedNets)
 
 	// Report required networks as high severity
-	if len(requiredNets) > 0 {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.master_authorized_networks",
-			Expected: fmt.Sprintf("Required: %v", requiredNets),
-			Actual:   fmt.Sprintf("%v", actual.MasterAuthorizedNets),
-			Severity: "high",
-		})
+	if len(required) > 0 {
+		drift.Drifts = checkRequiredMasterNets.Append(drift.Drifts,
+			fmt.Sprintf("Required: %v", required), fmt.Sprintf("%v", actual.MasterAuthorizedNets))
 	}
 
 	// Report extra networks as medium severity
-	if len(extraNets) > 0 {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "cluster.master_authorized_networks",
-			Expected: fmt.Sprintf("%v", baseline.MasterAuthorizedNets),
-			Actual:   fmt.Sprintf("Extra: %v", extraNets),
-			Severity: "medium",
-		})
-	}
-}
-
-// compareNodePools compares node pools against baseline
-func (a *Analyzer) compareNodePools(actualPools []*NodePoolConfig, baseline *NodePoolConfig, drift *ClusterDrift) {
-	for _, pool := range actualPools {
-		poolPrefix := fmt.Sprintf("nodepool[%s]", pool.Name)
-
-		// Machine type
-		if baseline.MachineType != "" && pool.MachineType != baseline.MachineType {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("%s.machine_type", poolPrefix),
-				Expected: baseline.MachineType,
-				Actual:   pool.MachineType,
-				Severity: "high",
-			})
-		}
-
-		// Disk size
-		if baseline.DiskSizeGB > 0 && pool.DiskSizeGB != baseline.DiskSizeGB {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("%s.disk_size_gb", poolPrefix),
-				Expected: fmt.Sprintf("%d", baseline.DiskSizeGB),
-				Actual:   fmt.Sprintf("%d", pool.DiskSizeGB),
-				Severity: "medium",
-			})
-		}
-
-		// Image type
-		if baseline.ImageType != "" && pool.ImageType != baseline.ImageType {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("%s.image_type", poolPrefix),
-				Expected: baseline.ImageType,
-				Actual:   pool.ImageType,
-				Severity: "medium",
-			})
-		}
-
-		// Auto upgrade
-		if pool.AutoUpgrade != baseline.AutoUpgrade {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("%s.auto_upgrade", poolPrefix),
-				Expected: fmt.Sprintf("%v", baseline.AutoUpgrade),
-				Actual:   fmt.Sprintf("%v", pool.AutoUpgrade),
-				Severity: "high",
-			})
-		}
-
-		// Auto repair
-		if pool.AutoRepair != baseline.AutoRepair {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("%s.auto_repair", poolPrefix),
-				Expected: fmt.Sprintf("%v", baseline.AutoRepair),
-				Actual:   fmt.Sprintf("%v", pool.AutoRepair),
-				Severity: "high",
-			})
-		}
+	if len(extra) > 0 {
+		drift.Drifts = checkExtraMasterNets.Append(drift.Drifts,
+			fmt.Sprintf("%v", baseline.MasterAuthorizedNets), fmt.Sprintf("Extra: %v", extra))
 	}
 }
 
@@ -2,7 +2,12 @@ package gke
 
 import (
 	"context"
+	"strings"
 	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"google.golang.org/api/container/v1"
 )
 
 func TestClusterConfig(t *testing.T) {
@@ -178,3 +183,270 @@ func TestExtractMinorVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestCompareImmutable(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &ClusterConfig{
+		Network:        "prod-vpc",
+		Subnetwork:     "gke-subnet",
+		PrivateCluster: true,
+		IPAllocationPolicy: &IPAllocationPolicy{
+			UseIPAliases:     true,
+			ServicesIPv4CIDR: "10.20.0.0/20",
+		},
+	}
+
+	cluster := &ClusterInstance{
+		Name: "legacy",
+		Config: &ClusterConfig{
+			Network:    "projects/p/global/networks/default",
+			Subnetwork: "projects/p/regions/us-central1/subnetworks/gke-subnet",
+		},
+	}
+
+	drift := a.analyzeCluster(cluster, baseline, nil)
+
+	want := map[string]bool{
+		"cluster.network":                                 true,
+		"cluster.private_cluster":                         true,
+		"cluster.ip_allocation_policy.use_ip_aliases":     true,
+		"cluster.ip_allocation_policy.services_ipv4_cidr": true,
+	}
+	got := make(map[string]bool)
+	for _, d := range drift.Drifts {
+		if d.Immutable {
+			got[d.Field] = true
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("immutable drifts = %v, want %v", got, want)
+	}
+	for field := range want {
+		if !got[field] {
+			t.Errorf("missing immutable drift %s", field)
+		}
+	}
+
+	if len(drift.Recommendations) == 0 {
+		t.Fatal("expected recreation recommendations")
+	}
+	if !strings.HasPrefix(drift.Recommendations[0], "RECREATE: ") {
+		t.Errorf("Recommendations[0] = %q, want RECREATE prefix", drift.Recommendations[0])
+	}
+
+	// Matching clusters get no recreation recommendations
+	cluster.Config = &ClusterConfig{
+		Network:            "prod-vpc",
+		Subnetwork:         "gke-subnet",
+		PrivateCluster:     true,
+		IPAllocationPolicy: &IPAllocationPolicy{UseIPAliases: true, ServicesIPv4CIDR: "10.20.0.0/20"},
+	}
+	if drift := a.analyzeCluster(cluster, baseline, nil); len(drift.Recommendations) != 0 {
+		t.Errorf("Recommendations = %v, want none", drift.Recommendations)
+	}
+}
+
+func TestAllowedValues(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &ClusterConfig{
+		AllowedValues: checks.Allowed{
+			"cluster.master_version":  {"1.32", "1.33"},
+			"cluster.release_channel": {"REGULAR", "STABLE"},
+		},
+	}
+	pools := &NodePoolConfig{
+		AllowedValues: checks.Allowed{
+			"nodepool[*].machine_type":   {"n2-standard-4", "n2-standard-8"},
+			"nodepool[gpu].machine_type": {"a2-highgpu-1g"},
+		},
+	}
+
+	cluster := &ClusterInstance{
+		Name: "prod",
+		Config: &ClusterConfig{
+			MasterVersion:  "1.33.5-gke.1080000",
+			ReleaseChannel: "RAPID",
+		},
+		NodePools: []*NodePoolConfig{
+			{Name: "default", MachineType: "n2-standard-8"},
+			{Name: "batch", MachineType: "e2-medium"},
+			{Name: "gpu", MachineType: "a2-highgpu-1g"},
+		},
+	}
+
+	drift := a.analyzeCluster(cluster, baseline, &NodePoolBaselines{Default: pools})
+
+	got := make(map[string]string)
+	for _, d := range drift.Drifts {
+		got[d.Field] = d.Expected
+	}
+	want := map[string]string{
+		"cluster.release_channel":      "one of [REGULAR STABLE]",
+		"nodepool[batch].machine_type": "one of [n2-standard-4 n2-standard-8]",
+	}
+	for field, expected := range want {
+		if got[field] != expected {
+			t.Errorf("%s expected = %q, want %q", field, got[field], expected)
+		}
+	}
+	for _, field := range []string{"cluster.master_version", "nodepool[default].machine_type", "nodepool[gpu].machine_type"} {
+		if _, ok := got[field]; ok {
+			t.Errorf("unexpected drift on %s", field)
+		}
+	}
+
+	if err := (GKEBaseline{Name: "prod", ClusterConfig: baseline, NodePoolConfig: pools}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &ClusterConfig{
+		ReleaseChannel:    "STABLE",
+		SeverityOverrides: checks.SeverityOverrides{"cluster.release_channel": "low"},
+	}
+	pools := &NodePoolConfig{
+		MachineType:       "n2-standard-4",
+		SeverityOverrides: checks.SeverityOverrides{"nodepool[*].machine_type": "critical"},
+	}
+	cluster := &ClusterInstance{
+		Name:      "dev",
+		Config:    &ClusterConfig{ReleaseChannel: "RAPID"},
+		NodePools: []*NodePoolConfig{{Name: "default", MachineType: "e2-medium"}},
+	}
+
+	severities := make(map[string]string)
+	for _, d := range a.analyzeCluster(cluster, baseline, &NodePoolBaselines{Default: pools}).Drifts {
+		severities[d.Field] = d.Severity
+	}
+	if severities["cluster.release_channel"] != "low" {
+		t.Errorf("cluster.release_channel severity = %q, want low", severities["cluster.release_channel"])
+	}
+	if severities["nodepool[default].machine_type"] != "critical" {
+		t.Errorf("nodepool[default].machine_type severity = %q, want critical", severities["nodepool[default].machine_type"])
+	}
+
+	if err := (GKEBaseline{Name: "dev", ClusterConfig: baseline, NodePoolConfig: pools}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestMasterVersionWarning(t *testing.T) {
+	config := &ServerConfig{ValidMasterVersions: []string{"1.31.1-gke.100", "1.30.5-gke.200", "1.30.4-gke.100"}}
+
+	if got := masterVersionWarning([]string{"1.30"}, config, "us-central1"); got != "" {
+		t.Errorf("masterVersionWarning() for an offered version = %q, want none", got)
+	}
+	got := masterVersionWarning([]string{"1.27", "1.31"}, config, "us-central1")
+	want := "baseline version 1.27 is no longer offered in us-central1 (valid: 1.31, 1.30)"
+	if got != want {
+		t.Errorf("masterVersionWarning() = %q, want %q", got, want)
+	}
+}
+
+func TestCompareClusterAutoscaling(t *testing.T) {
+	a := &Analyzer{}
+	baseline := &ClusterConfig{
+		ClusterAutoscaling: &ClusterAutoscalingConfig{
+			NodeAutoProvisioning: true,
+			AutoscalingProfile:   "OPTIMIZE_UTILIZATION",
+			ResourceLimits: map[string]ResourceLimit{
+				"cpu":             {Maximum: 200},
+				"memory":          {Minimum: 16, Maximum: 800},
+				"nvidia-tesla-t4": {Maximum: 4},
+			},
+		},
+	}
+
+	extracted := extractClusterAutoscaling(&container.Cluster{Autoscaling: &container.ClusterAutoscaling{
+		EnableNodeAutoprovisioning: true,
+		ResourceLimits: []*container.ResourceLimit{
+			{ResourceType: "cpu", Maximum: 200},
+			{ResourceType: "memory", Minimum: 8, Maximum: 1600},
+		},
+	}})
+	if extracted.AutoscalingProfile != "BALANCED" {
+		t.Errorf("unspecified profile = %q, want BALANCED", extracted.AutoscalingProfile)
+	}
+
+	drift := &ClusterDrift{}
+	a.compareClusterAutoscaling(&ClusterConfig{ClusterAutoscaling: extracted}, baseline, drift)
+	got := make(map[string]string)
+	for _, d := range drift.Drifts {
+		got[d.Field] = d.Expected + " " + d.Actual + " " + d.Severity
+	}
+	want := map[string]string{
+		"cluster.cluster_autoscaling.autoscaling_profile":                      "OPTIMIZE_UTILIZATION BALANCED medium",
+		"cluster.cluster_autoscaling.resource_limits[memory].minimum":          "16 8 medium",
+		"cluster.cluster_autoscaling.resource_limits[memory].maximum":          "800 1600 high",
+		"cluster.cluster_autoscaling.resource_limits[nvidia-tesla-t4].maximum": "4 not set high",
+	}
+	if len(got) != len(want) {
+		t.Errorf("drifts = %v, want %v", got, want)
+	}
+	for field, w := range want {
+		if got[field] != w {
+			t.Errorf("%s = %q, want %q", field, got[field], w)
+		}
+	}
+
+	// NAP switched on against a baseline without it is reported, without
+	// comparing limits
+	baseline.ClusterAutoscaling.NodeAutoProvisioning = false
+	baseline.ClusterAutoscaling.AutoscalingProfile = ""
+	drift = &ClusterDrift{}
+	a.compareClusterAutoscaling(&ClusterConfig{ClusterAutoscaling: extracted}, baseline, drift)
+	if len(drift.Drifts) != 1 || drift.Drifts[0].Field != "cluster.cluster_autoscaling.node_auto_provisioning" || drift.Drifts[0].Actual != "true" {
+		t.Errorf("drifts = %+v, want node_auto_provisioning true", drift.Drifts)
+	}
+}
+
+func TestCheckOperationalStatus(t *testing.T) {
+	a := &Analyzer{}
+	cluster := &ClusterInstance{Name: "prod", Status: "DEGRADED", Conditions: []string{"Node pool default-pool is unhealthy"}}
+
+	// Unhealthy clusters are reported without a baseline too
+	drift := a.analyzeCluster(cluster, nil, nil)
+	if len(drift.Drifts) != 1 {
+		t.Fatalf("got %d drifts, want the status: %+v", len(drift.Drifts), drift.Drifts)
+	}
+	if d := drift.Drifts[0]; d.Field != "status" || d.Severity != "high" || !d.Operational ||
+		d.Actual != "DEGRADED: Node pool default-pool is unhealthy" {
+		t.Errorf("drift = %+v", d)
+	}
+
+	a.SetOperationalSeverities(map[string]string{"DEGRADED": "ignore"})
+	if drift := a.analyzeCluster(cluster, nil, nil); len(drift.Drifts) != 0 {
+		t.Errorf("ignored status drifts = %+v", drift.Drifts)
+	}
+	cluster.Status = "RUNNING"
+	a.SetOperationalSeverities(nil)
+	if drift := a.analyzeCluster(cluster, nil, nil); len(drift.Drifts) != 0 {
+		t.Errorf("RUNNING drifts = %+v", drift.Drifts)
+	}
+}
+
+func TestCheckLabelPolicy(t *testing.T) {
+	a := &Analyzer{}
+	a.SetLabelPolicy(&analyzer.LabelPolicy{
+		Required:      []string{"team"},
+		AllowedValues: map[string][]string{"env": {"prod", "dev"}},
+	})
+	cluster := &ClusterInstance{Name: "prod", Status: "RUNNING", Labels: map[string]string{"env": "production"}}
+
+	// The policy applies to clusters without a baseline too
+	drift := a.analyzeCluster(cluster, nil, nil)
+	if len(drift.Drifts) != 2 {
+		t.Fatalf("got %d drifts, want the missing team and the invalid env: %+v", len(drift.Drifts), drift.Drifts)
+	}
+	if d := drift.Drifts[0]; d.Field != "labels.team" || d.Actual != "not set" || d.Severity != "medium" {
+		t.Errorf("team drift = %+v", d)
+	}
+	if d := drift.Drifts[1]; d.Field != "labels.env" || d.Expected != "one of [prod dev]" || d.Actual != "production" {
+		t.Errorf("env drift = %+v", d)
+	}
+}
@@ -0,0 +1,205 @@
+package gke
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/checks"
+
+// resourceType is the resource type reported for GKE checks
+const resourceType = "GKE Cluster"
+
+// Terraform Google provider resources of a cluster and its node pools
+const (
+	terraformCluster  = "google_container_cluster"
+	terraformNodePool = "google_container_node_pool"
+)
+
+func register(id, path, severity, description, remediation string) *checks.Check {
+	return checks.Register(checks.Check{
+		ID:           "gke." + id,
+		ResourceType: resourceType,
+		Path:         path,
+		Severity:     severity,
+		Description:  description,
+		Remediation:  remediation,
+	})
+}
+
+// registerImmutable registers a check on a setting fixed at cluster creation
+func registerImmutable(id, path, severity, description, remediation string) *checks.Check {
+	return checks.Register(checks.Check{
+		ID:           "gke." + id,
+		ResourceType: resourceType,
+		Path:         path,
+		Severity:     severity,
+		Description:  description,
+		Remediation:  remediation,
+		Immutable:    true,
+	})
+}
+
+// registerRestart registers a check whose fix recreates the nodes
+func registerRestart(id, path, severity, description, remediation string) *checks.Check {
+	return checks.Register(checks.Check{
+		ID:              "gke." + id,
+		ResourceType:    resourceType,
+		Path:            path,
+		Severity:        severity,
+		Description:     description,
+		Remediation:     remediation,
+		RequiresRestart: true,
+	})
+}
+
+// Version checks
+var (
+	checkMasterVersion = register("cluster.master_version", "cluster.master_version", "high", "Control plane minor version",
+		"gcloud container clusters upgrade CLUSTER --master --cluster-version=VERSION").WithTerraform(terraformCluster, "min_master_version")
+	checkReleaseChannel = register("cluster.release_channel", "cluster.release_channel", "medium", "Release channel (RAPID, REGULAR, STABLE)",
+		"gcloud container clusters update CLUSTER --release-channel=CHANNEL").WithTerraform(terraformCluster, "release_channel.channel")
+)
+
+// Creation-time checks
+var (
+	checkNetwork = registerImmutable("cluster.network", "cluster.network", "high", "VPC network the cluster is attached to",
+		"Create a replacement cluster in the baseline network and migrate workloads").WithTerraform(terraformCluster, "network")
+	checkSubnetwork = registerImmutable("cluster.subnetwork", "cluster.subnetwork", "high", "Subnetwork used for nodes",
+		"Create a replacement cluster in the baseline subnetwork and migrate workloads").WithTerraform(terraformCluster, "subnetwork")
+	checkPrivateCluster = registerImmutable("cluster.private_cluster", "cluster.private_cluster", "critical", "Private nodes without public IPs",
+		"Create a replacement cluster with --enable-private-nodes and migrate workloads").WithTerraform(terraformCluster, "private_cluster_config.enable_private_nodes")
+	checkIPAliases = registerImmutable("cluster.ip_allocation_policy.use_ip_aliases", "cluster.ip_allocation_policy.use_ip_aliases", "high", "VPC-native (alias IP) networking",
+		"Create a replacement VPC-native cluster (--enable-ip-alias) and migrate workloads")
+	checkClusterIPv4CIDR = registerImmutable("cluster.ip_allocation_policy.cluster_ipv4_cidr", "cluster.ip_allocation_policy.cluster_ipv4_cidr", "medium", "Pod IPv4 range",
+		"Create a replacement cluster with --cluster-ipv4-cidr=CIDR and migrate workloads").WithTerraform(terraformCluster, "ip_allocation_policy.cluster_ipv4_cidr_block")
+	checkServicesIPv4CIDR = registerImmutable("cluster.ip_allocation_policy.services_ipv4_cidr", "cluster.ip_allocation_policy.services_ipv4_cidr", "medium", "Service IPv4 range",
+		"Create a replacement cluster with --services-ipv4-cidr=CIDR and migrate workloads").WithTerraform(terraformCluster, "ip_allocation_policy.services_ipv4_cidr_block")
+)
+
+// Networking checks
+var (
+	checkDatapathProvider = register("cluster.datapath_provider", "cluster.datapath_provider", "medium", "Dataplane (ADVANCED_DATAPATH for Dataplane V2)",
+		"Dataplane V2 is chosen at creation; create a replacement cluster with --enable-dataplane-v2").WithTerraform(terraformCluster, "datapath_provider")
+	checkMasterGlobalAccess = register("cluster.master_global_access", "cluster.master_global_access", "medium", "Control plane reachable from all regions",
+		"gcloud container clusters update CLUSTER --enable-master-global-access").WithTerraform(terraformCluster, "private_cluster_config.master_global_access_config.enabled")
+	checkStackType = register("cluster.ip_allocation_policy.stack_type", "cluster.ip_allocation_policy.stack_type", "high", "IPv4 or dual-stack networking",
+		"gcloud container clusters update CLUSTER --stack-type=STACK_TYPE").WithTerraform(terraformCluster, "ip_allocation_policy.stack_type")
+	checkRequiredMasterNets = register("cluster.master_authorized_networks", "cluster.master_authorized_networks", "high", "Required master authorized networks missing",
+		"gcloud container clusters update CLUSTER --enable-master-authorized-networks --master-authorized-networks=CIDR,...")
+	checkExtraMasterNets = register("cluster.master_authorized_networks.extra", "cluster.master_authorized_networks", "medium", "Master authorized networks not in the baseline",
+		"Remove the network with --master-authorized-networks=... (the list replaces all networks) or add it to the baseline")
+)
+
+// Security checks
+var (
+	checkWorkloadIdentity = register("cluster.workload_identity", "cluster.workload_identity", "high", "Workload Identity enabled",
+		"gcloud container clusters update CLUSTER --workload-pool=PROJECT_ID.svc.id.goog")
+	checkNetworkPolicy = registerRestart("cluster.network_policy", "cluster.network_policy", "high", "Network policy enforcement enabled",
+		"gcloud container clusters update CLUSTER --update-addons=NetworkPolicy=ENABLED, then --enable-network-policy").WithTerraform(terraformCluster, "network_policy.enabled")
+	checkBinaryAuthorization = register("cluster.binary_authorization", "cluster.binary_authorization", "high", "Binary Authorization enabled",
+		"gcloud container clusters update CLUSTER --binauthz-evaluation-mode=PROJECT_SINGLETON_POLICY_ENFORCE")
+	checkShieldedNodes = registerRestart("cluster.shielded_nodes", "cluster.shielded_nodes", "high", "Shielded GKE nodes enabled",
+		"gcloud container clusters update CLUSTER --enable-shielded-nodes").WithTerraform(terraformCluster, "enable_shielded_nodes")
+	checkDatabaseEncryption = register("cluster.database_encryption", "cluster.database_encryption", "critical", "Application-layer secrets encryption with Cloud KMS",
+		"gcloud container clusters update CLUSTER --database-encryption-key=KMS_KEY")
+	checkSecurityPosture = register("cluster.security_posture", "cluster.security_posture", "high", "Security posture dashboard mode",
+		"gcloud container clusters update CLUSTER --security-posture=MODE")
+)
+
+// Observability checks
+var (
+	checkSystemLogs = register("cluster.logging_config.enable_system_logs", "cluster.logging_config.enable_system_logs", "medium", "System component logs collected",
+		"gcloud container clusters update CLUSTER --logging=SYSTEM[,WORKLOAD]")
+	checkWorkloadLogs = register("cluster.logging_config.enable_workload_logs", "cluster.logging_config.enable_workload_logs", "low", "Workload logs collected",
+		"gcloud container clusters update CLUSTER --logging=SYSTEM,WORKLOAD")
+	checkSystemMetrics = register("cluster.monitoring_config.enable_system_metrics", "cluster.monitoring_config.enable_system_metrics", "medium", "System metrics collected",
+		"gcloud container clusters update CLUSTER --monitoring=SYSTEM[,API_SERVER]")
+	checkAPIServerMetrics = register("cluster.monitoring_config.enable_apiserver_metrics", "cluster.monitoring_config.enable_apiserver_metrics", "low", "API server metrics collected",
+		"gcloud container clusters update CLUSTER --monitoring=SYSTEM,API_SERVER")
+)
+
+// Cluster autoscaling checks
+var (
+	checkNodeAutoProvisioning = register("cluster.cluster_autoscaling.node_auto_provisioning", "cluster.cluster_autoscaling.node_auto_provisioning", "high", "Node auto-provisioning creates node pools for pending pods",
+		"gcloud container clusters update CLUSTER --enable-autoprovisioning --max-cpu=MAX --max-memory=MAX, or --no-enable-autoprovisioning")
+	checkAutoscalingProfile = register("cluster.cluster_autoscaling.autoscaling_profile", "cluster.cluster_autoscaling.autoscaling_profile", "medium", "Cluster autoscaler profile (BALANCED or OPTIMIZE_UTILIZATION)",
+		"gcloud container clusters update CLUSTER --autoscaling-profile=PROFILE").WithTerraform(terraformCluster, "cluster_autoscaling.autoscaling_profile")
+	checkResourceLimitMin = register("cluster.cluster_autoscaling.resource_limits.minimum", "cluster.cluster_autoscaling.resource_limits[*].minimum", "medium", "Node auto-provisioning minimum of the resource",
+		"gcloud container clusters update CLUSTER --enable-autoprovisioning --min-cpu=MIN --min-memory=MIN")
+	checkResourceLimitMax = register("cluster.cluster_autoscaling.resource_limits.maximum", "cluster.cluster_autoscaling.resource_limits[*].maximum", "high", "Node auto-provisioning maximum of the resource",
+		"gcloud container clusters update CLUSTER --enable-autoprovisioning --max-cpu=MAX --max-memory=MAX (GPUs with --max-accelerator=type=TYPE,count=N)")
+)
+
+// Node pool checks
+var (
+	checkPoolMachineType = registerRestart("nodepool.machine_type", "nodepool[*].machine_type", "high", "Node machine type",
+		"Create a node pool with --machine-type=TYPE, cordon and drain the old pool, then delete it").WithTerraform(terraformNodePool, "node_config.machine_type")
+	checkPoolDiskSize = registerRestart("nodepool.disk_size_gb", "nodepool[*].disk_size_gb", "medium", "Node boot disk size in GB",
+		"Create a node pool with --disk-size=SIZE, cordon and drain the old pool, then delete it").WithTerraform(terraformNodePool, "node_config.disk_size_gb")
+	checkPoolImageType = registerRestart("nodepool.image_type", "nodepool[*].image_type", "medium", "Node image (e.g. COS_CONTAINERD)",
+		"gcloud container clusters upgrade CLUSTER --node-pool=POOL --image-type=IMAGE").WithTerraform(terraformNodePool, "node_config.image_type")
+	checkPoolAutoUpgrade = register("nodepool.auto_upgrade", "nodepool[*].auto_upgrade", "high", "Node auto-upgrade enabled",
+		"gcloud container node-pools update POOL --cluster=CLUSTER --enable-autoupgrade").WithTerraform(terraformNodePool, "management.auto_upgrade")
+	checkPoolAutoRepair = register("nodepool.auto_repair", "nodepool[*].auto_repair", "high", "Node auto-repair enabled",
+		"gcloud container node-pools update POOL --cluster=CLUSTER --enable-autorepair").WithTerraform(terraformNodePool, "management.auto_repair")
+	checkPoolAutoscaling = register("nodepool.autoscaling.enabled", "nodepool[*].autoscaling.enabled", "high", "Cluster autoscaler enabled on the node pool",
+		"gcloud container clusters update CLUSTER --node-pool=POOL --enable-autoscaling --min-nodes=MIN --max-nodes=MAX")
+	checkPoolAutoscalingOff = register("nodepool.autoscaling.disabled", "nodepool[*].autoscaling.enabled", "medium", "Cluster autoscaler disabled where the baseline fixes the node count",
+		"gcloud container clusters update CLUSTER --node-pool=POOL --no-enable-autoscaling")
+	checkPoolMinNodes = register("nodepool.autoscaling.min_node_count", "nodepool[*].autoscaling.min_node_count", "medium", "Autoscaling minimum node count",
+		"gcloud container clusters update CLUSTER --node-pool=POOL --enable-autoscaling --min-nodes=MIN").WithTerraform(terraformNodePool, "autoscaling.min_node_count")
+	checkPoolMaxNodes = register("nodepool.autoscaling.max_node_count", "nodepool[*].autoscaling.max_node_count", "medium", "Autoscaling maximum node count",
+		"gcloud container clusters update CLUSTER --node-pool=POOL --enable-autoscaling --max-nodes=MAX").WithTerraform(terraformNodePool, "autoscaling.max_node_count")
+	checkPoolLabel = register("nodepool.labels", "nodepool[*].labels[*]", "high", "Required Kubernetes node label value",
+		"gcloud container node-pools update POOL --cluster=CLUSTER --node-labels=KEY=VALUE,... (replaces all node labels)").WithTerraform(terraformNodePool, "node_config.labels[\"*\"]")
+	checkPoolTaint = register("nodepool.taints", "nodepool[*].taints[*]", "medium", "Required node taint",
+		"gcloud container node-pools update POOL --cluster=CLUSTER --node-taints=KEY=VALUE:EFFECT,... (replaces all node taints)")
+	checkPoolForbiddenTaint = register("nodepool.forbidden_taints", "nodepool[*].taints[*]", "high", "Node taint the baseline forbids",
+		"gcloud container node-pools update POOL --cluster=CLUSTER --node-taints= with the remaining taints")
+	checkPoolMissing = register("nodepool.missing", "nodepool[*]", "high", "Node pool named in the baseline exists",
+		"gcloud container node-pools create POOL --cluster=CLUSTER with the baseline's machine type and disk")
+	checkPoolExtra = register("nodepool.extra", "nodepool[*]", "medium", "Node pool matched by a baseline entry",
+		"Drain and delete the pool with gcloud container node-pools delete POOL --cluster=CLUSTER, or add it to the baseline")
+)
+
+// Workload checks compare the in-cluster state read through the Kubernetes API
+var (
+	checkNamespaceMissing = register("workload.namespace.missing", "namespace[*]", "high", "Namespace required by the baseline exists",
+		"kubectl create namespace NAMESPACE")
+	checkNamespacePodSecurity = register("workload.namespace.pod_security", "namespace[*].pod_security", "high", "Pod Security Standard enforced on the namespace",
+		"kubectl label --overwrite namespace NAMESPACE pod-security.kubernetes.io/enforce=LEVEL")
+	checkDeploymentMissing = register("workload.deployment.missing", "deployment[*]", "high", "Deployment required by the baseline exists",
+		"Deploy the workload to the cluster, e.g. with kubectl apply or its delivery pipeline")
+	checkDeploymentAvailable = register("workload.deployment.available", "deployment[*].available_replicas", "medium", "Deployment replicas available",
+		"kubectl -n NAMESPACE describe deployment NAME to find why replicas are unavailable")
+	checkDaemonSetMissing = register("workload.daemonset.missing", "daemonset[*]", "high", "DaemonSet required by the baseline exists",
+		"Deploy the DaemonSet to the cluster, e.g. with kubectl apply or its delivery pipeline")
+	checkDaemonSetAvailable = register("workload.daemonset.available", "daemonset[*].number_available", "medium", "DaemonSet pods available on every scheduled node",
+		"kubectl -n NAMESPACE describe daemonset NAME to find why pods are unavailable")
+)
+
+// Operational checks
+var (
+	checkStatus = register("status", "status", "critical", "Cluster in an unhealthy status such as DEGRADED or ERROR (operational)",
+		"gcloud container clusters describe CLUSTER --format='value(conditions)' and gcloud container operations list --filter=targetLink~CLUSTER to find the cause")
+)
+
+// Label policy checks
+var (
+	checkLabelPolicy = register("label_policy", "labels.*", "medium", "Label required by the label_policy, with an allowed value (org policy)",
+		"gcloud container clusters update CLUSTER --location=LOCATION --update-labels=KEY=VALUE").WithTerraform(terraformCluster, "resource_labels[\"*\"]")
+)
+
+// Capacity checks compare a cluster's size at its autoscaling maxima with the
+// capacity envelope of its labels
+var (
+	checkCapacityNodes = register("capacity.max_nodes", "capacity.max_nodes", "medium", "Nodes at autoscaling maxima within the capacity envelope",
+		"gcloud container node-pools update POOL --cluster=CLUSTER --enable-autoscaling --max-nodes=N, or agree a higher cap")
+	checkCapacityVCPUs = register("capacity.max_vcpus", "capacity.max_vcpus", "medium", "vCPUs at autoscaling maxima within the capacity envelope",
+		"Lower node pool autoscaling maxima or move to a smaller machine type, or agree a higher cap")
+	checkCapacityMemory = register("capacity.max_memory_gb", "capacity.max_memory_gb", "medium", "Memory in GB at autoscaling maxima within the capacity envelope",
+		"Lower node pool autoscaling maxima or move to a smaller machine type, or agree a higher cap")
+)
+
+// Uniqueness checks flag clusters of a baseline sharing a name or label values
+// across projects beyond the baseline's unique rules
+var (
+	checkUnique = register("unique", "unique", "high", "No more clusters share a name or label values than the baseline allows",
+		"Remove or relabel the duplicate, e.g. one created by a copy-pasted Terraform module")
+)
@@ -22,6 +22,14 @@ type Command struct {
 	Format         string
 	FilterRole     string
 	GenerateConfig bool
+
+	// Labels selects resources carrying every label; FilterRole is the
+	// deprecated form of a single cluster-role label
+	Labels map[string]string
+
+	// GroupBy makes GenerateConfig emit one baseline per value of this label
+	// instead of a single baseline from the first cluster
+	GroupBy string
 }
 
 // Config represents the YAML configuration file structure for GKE
@@ -37,10 +45,24 @@ type Config struct {
 
 // GKEBaseline represents a GKE configuration baseline with optional filters
 type GKEBaseline struct {
-	Name           string            `yaml:"name,omitempty"`
-	FilterLabels   map[string]string `yaml:"filter_labels,omitempty"`
-	ClusterConfig  *ClusterConfig    `yaml:"cluster_config"`
-	NodePoolConfig *NodePoolConfig   `yaml:"nodepool_config,omitempty"`
+	Name         string            `yaml:"name,omitempty"`
+	FilterLabels map[string]string `yaml:"filter_labels,omitempty"`
+	// InstanceNames and NamePattern scope the baseline to clusters by name
+	InstanceNames  []string        `yaml:"instance_names,omitempty"`
+	NamePattern    string          `yaml:"name_pattern,omitempty"`
+	ClusterConfig  *ClusterConfig  `yaml:"cluster_config"`
+	NodePoolConfig *NodePoolConfig `yaml:"nodepool_config,omitempty"`
+	// NodePools are node pool baselines per pool name or pattern; unmatched
+	// pools fall back to NodePoolConfig or, without it, are reported extra
+	NodePools []NodePoolBaseline `yaml:"nodepools,omitempty"`
+	// Metadata records when the baseline was last reviewed
+	Metadata analyzer.BaselineMetadata `yaml:"metadata,omitempty"`
+	// Capacity caps cluster size by label; the first matching envelope applies
+	Capacity []CapacityEnvelope `yaml:"capacity,omitempty"`
+	// Unique limits how many clusters may share a name or label values
+	Unique []analyzer.UniquenessRule `yaml:"unique,omitempty"`
+	// Workloads is the expected in-cluster state, checked by gke workloads
+	Workloads *WorkloadBaseline `yaml:"workloads,omitempty"`
 }
 
 // Compile-time interface implementation check
@@ -56,9 +78,66 @@ func (b GKEBaseline) Validate() error {
 	if b.Name == "" {
 		return fmt.Errorf("baseline name is required")
 	}
+	if err := b.Scope().Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	if err := b.Metadata.Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	if b.ClusterConfig != nil {
+		if err := b.ClusterConfig.AllowedValues.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: %w", b.Name, err)
+		}
+		if err := b.ClusterConfig.SeverityOverrides.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: %w", b.Name, err)
+		}
+	}
+	if b.NodePoolConfig != nil {
+		if err := b.NodePoolConfig.validate(); err != nil {
+			return fmt.Errorf("baseline %s: %w", b.Name, err)
+		}
+	}
+	names := make(map[string]bool, len(b.NodePools))
+	for i, pool := range b.NodePools {
+		if err := pool.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: nodepools[%d]: %w", b.Name, i, err)
+		}
+		if pool.Name != "" && names[pool.Name] {
+			return fmt.Errorf("baseline %s: nodepools[%d]: duplicate pool %q", b.Name, i, pool.Name)
+		}
+		names[pool.Name] = true
+	}
+	for i, envelope := range b.Capacity {
+		if err := envelope.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: capacity[%d]: %w", b.Name, i, err)
+		}
+	}
+	for i, rule := range b.Unique {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: unique[%d]: %w", b.Name, i, err)
+		}
+	}
+	if err := b.Workloads.Validate(); err != nil {
+		return fmt.Errorf("baseline %s: workloads: %w", b.Name, err)
+	}
 	return nil
 }
 
+// Expected returns the control plane versions and node machine types the
+// baseline accepts, so deprecated ones can be flagged for review
+func (b GKEBaseline) Expected() (versions, machineTypes []string) {
+	if b.ClusterConfig != nil {
+		versions = append([]string{b.ClusterConfig.MasterVersion}, b.ClusterConfig.AllowedValues.Values(checkMasterVersion.Path)...)
+	}
+	if b.NodePoolConfig != nil {
+		machineTypes = []string{b.NodePoolConfig.MachineType}
+	}
+	for _, pool := range b.NodePools {
+		machineTypes = append(machineTypes, pool.MachineType)
+	}
+	return versions, machineTypes
+}
+
 // Execute runs the GKE drift analysis command
 func (c *Command) Execute(ctx context.Context) error {
 	// Use provided baselines and projects from main
@@ -78,7 +157,13 @@ func (c *Command) Execute(ctx context.Context) error {
 		return fmt.Errorf("must provide either -projects or -config")
 	}
 
-	// Apply command-line filter if specified
+	// Apply command-line filters if specified
+	for key, value := range c.Labels {
+		if filterLabels == nil {
+			filterLabels = make(map[string]string)
+		}
+		filterLabels[key] = value
+	}
 	if c.FilterRole != "" {
 		if filterLabels == nil {
 			filterLabels = make(map[string]string)
@@ -114,6 +199,9 @@ func (c *Command) Execute(ctx context.Context) error {
 
 	// Generate baseline config if requested
 	if c.GenerateConfig {
+		if c.GroupBy != "" {
+			return writeGeneratedConfig(clusters, c.GroupBy, c.OutputFile)
+		}
 		return generateBaselineConfig(clusters, c.OutputFile)
 	}
 
@@ -169,6 +257,20 @@ func generateBaselineConfig(clusters []*ClusterInstance, outputPath string) erro
 	return nil
 }
 
+// writeGeneratedConfig writes a config with one baseline per value of the
+// groupBy label
+func writeGeneratedConfig(clusters []*ClusterInstance, groupBy, outputPath string) error {
+	output, err := GenerateBaselineConfig(clusters, groupBy)
+	if err != nil {
+		return err
+	}
+	if outputPath != "" {
+		return os.WriteFile(outputPath, []byte(output), 0644)
+	}
+	fmt.Print(output)
+	return nil
+}
+
 // outputReport formats and writes the drift report
 func outputReport(report *DriftReport, format, outputPath string) error {
 	var output string
@@ -186,6 +288,16 @@ func outputReport(report *DriftReport, format, outputPath string) error {
 			return err
 		}
 		output = string(data)
+	case "ndjson":
+		data, err := report.FormatNDJSON()
+		if err != nil {
+			return err
+		}
+		output = strings.TrimSuffix(data, "\n")
+	case "dot":
+		output = strings.TrimSuffix(report.ToReport().FormatDOT(), "\n")
+	case "mermaid":
+		output = strings.TrimSuffix(report.ToReport().FormatMermaid(), "\n")
 	case "text":
 		output = report.FormatText()
 	default:
@@ -214,10 +326,7 @@ func analyzeMultipleBaselines(analyzer *Analyzer, allClusters []*ClusterInstance
 	// Analyze each baseline with its filters
 	for _, baseline := range baselines {
 		// Filter clusters for this baseline
-		filteredClusters := allClusters
-		if len(baseline.FilterLabels) > 0 {
-			filteredClusters = filterClustersByLabels(allClusters, baseline.FilterLabels)
-		}
+		filteredClusters := SelectClusters(allClusters, baseline, baselines)
 
 		// Analyze with this baseline
 		for _, cluster := range filteredClusters {
@@ -226,7 +335,7 @@ func analyzeMultipleBaselines(analyzer *Analyzer, allClusters []*ClusterInstance
 				continue // Skip already analyzed clusters
 			}
 
-			drift := analyzer.analyzeCluster(cluster, baseline.ClusterConfig, baseline.NodePoolConfig)
+			drift := analyzer.analyzeCluster(cluster, baseline.ClusterConfig, baseline.NodePoolBaselines())
 			combinedReport.Instances = append(combinedReport.Instances, drift)
 
 			if len(drift.Drifts) > 0 {
@@ -0,0 +1,236 @@
+package gke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/metacache"
+	"google.golang.org/api/cloudbilling/v1"
+)
+
+// computeEngineService is the Cloud Billing Catalog ID of Compute Engine
+const computeEngineService = "services/6F81-5844-456A"
+
+// ComputeRates are the on-demand USD prices of one vCPU hour and one GiB of
+// memory per hour of a machine family
+type ComputeRates struct {
+	Core float64 `json:"core"`
+	RAM  float64 `json:"ram"`
+}
+
+// PriceList holds the compute rates of a region by machine family, e.g. n2
+type PriceList map[string]ComputeRates
+
+// skuPrefixes are the Cloud Billing Catalog SKU descriptions of on-demand
+// cores and memory by machine family, followed by " Core" or " Ram"
+var skuPrefixes = map[string]string{
+	"n1":  "N1 Predefined Instance",
+	"e2":  "E2 Instance",
+	"n2":  "N2 Instance",
+	"n2d": "N2D AMD Instance",
+	"n4":  "N4 Instance",
+	"c2":  "Compute optimized",
+	"c2d": "C2D AMD Instance",
+	"c3":  "C3 Instance",
+	"c3d": "C3D Instance",
+	"t2d": "T2D AMD Instance",
+	"t2a": "T2A Arm Instance",
+}
+
+// Hourly returns the approximate on-demand hourly cost of one node of a
+// machine type. Custom types are priced at their family's predefined rates,
+// and shared-core e2 types at their full vCPUs.
+func (p PriceList) Hourly(machineType string) (float64, bool) {
+	vcpus, memoryGB, ok := machineTypeSize(machineType)
+	if !ok {
+		return 0, false
+	}
+	family, _, _ := strings.Cut(machineType, "-")
+	if family == "custom" {
+		family = "n1"
+	}
+	rates, ok := p[family]
+	if !ok {
+		return 0, false
+	}
+	return float64(vcpus)*rates.Core + memoryGB*rates.RAM, true
+}
+
+// priceListFromSKUs builds the price list of a region from Compute Engine SKUs
+func priceListFromSKUs(skus []*cloudbilling.Sku, region string) PriceList {
+	prices := make(PriceList)
+	for _, sku := range skus {
+		if sku.Category == nil || sku.Category.UsageType != "OnDemand" || !containsRegion(sku.ServiceRegions, region) {
+			continue
+		}
+		price, ok := unitPrice(sku)
+		if !ok {
+			continue
+		}
+		for family, prefix := range skuPrefixes {
+			rates := prices[family]
+			switch {
+			case strings.HasPrefix(sku.Description, prefix+" Core running in"):
+				rates.Core = price
+			case strings.HasPrefix(sku.Description, prefix+" Ram running in"):
+				rates.RAM = price
+			default:
+				continue
+			}
+			prices[family] = rates
+		}
+	}
+
+	// A family is only priced when both its cores and memory are
+	for family, rates := range prices {
+		if rates.Core == 0 || rates.RAM == 0 {
+			delete(prices, family)
+		}
+	}
+	return prices
+}
+
+// unitPrice returns the price of the first tier of a SKU
+func unitPrice(sku *cloudbilling.Sku) (float64, bool) {
+	if len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+		return 0, false
+	}
+	tiers := sku.PricingInfo[0].PricingExpression.TieredRates
+	if len(tiers) == 0 || tiers[0].UnitPrice == nil {
+		return 0, false
+	}
+	money := tiers[0].UnitPrice
+	return float64(money.Units) + float64(money.Nanos)/1e9, true
+}
+
+func containsRegion(regions []string, region string) bool {
+	for _, r := range regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
+
+// CostEstimator looks up Compute Engine prices in the Cloud Billing Catalog
+type CostEstimator struct {
+	service *cloudbilling.APIService
+	cache   *metacache.Cache
+
+	mu   sync.Mutex
+	skus []*cloudbilling.Sku
+}
+
+// NewCostEstimator creates an estimator whose price lists are read through
+// the metadata cache
+func NewCostEstimator(ctx context.Context, cache *metacache.Cache) (*CostEstimator, error) {
+	service, err := cloudbilling.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Billing client: %w", err)
+	}
+	return &CostEstimator{service: service, cache: cache}, nil
+}
+
+// PriceList returns the compute rates of a region. The Compute Engine SKUs
+// are listed once per estimator, on the first region missing from the cache.
+func (e *CostEstimator) PriceList(ctx context.Context, region string) (PriceList, error) {
+	return metacache.Lookup(e.cache, "compute-prices/"+region, func() (PriceList, error) {
+		skus, err := e.computeSKUs(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return priceListFromSKUs(skus, region), nil
+	})
+}
+
+// computeSKUs lists the Compute Engine SKUs priced in USD
+func (e *CostEstimator) computeSKUs(ctx context.Context) ([]*cloudbilling.Sku, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.skus != nil {
+		return e.skus, nil
+	}
+
+	var skus []*cloudbilling.Sku
+	err := e.service.Services.Skus.List(computeEngineService).CurrencyCode("USD").Pages(ctx, func(resp *cloudbilling.ListSkusResponse) error {
+		skus = append(skus, resp.Skus...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Compute Engine prices: %w", err)
+	}
+	e.skus = skus
+	return skus, nil
+}
+
+// AnnotateCost sets the hourly cost delta of node pool drifts that change
+// what a pool costs: its machine type, at the pool's node count or
+// autoscaling maximum, and its autoscaling node counts, at its machine type.
+// Drifts whose machine types can't be priced are left as they are. Failed
+// lookups are returned after every cluster has been annotated.
+func AnnotateCost(ctx context.Context, report *DriftReport, prices func(ctx context.Context, region string) (PriceList, error)) error {
+	var errs []error
+	for _, cluster := range report.Instances {
+		pools := make(map[string]*NodePoolConfig)
+		for _, pool := range cluster.NodePools {
+			pools[checkPoolMachineType.At(pool.Name).Path] = pool
+			pools[checkPoolMinNodes.At(pool.Name).Path] = pool
+			pools[checkPoolMaxNodes.At(pool.Name).Path] = pool
+		}
+
+		var list PriceList
+		for i := range cluster.Drifts {
+			drift := &cluster.Drifts[i]
+			pool, ok := pools[drift.Field]
+			if !ok {
+				continue
+			}
+			if list == nil {
+				var err error
+				if list, err = prices(ctx, locationRegion(cluster.Location)); err != nil {
+					errs = append(errs, err)
+					break
+				}
+			}
+
+			if drift.Field == checkPoolMachineType.At(pool.Name).Path {
+				expected, okExpected := list.Hourly(drift.Expected)
+				actual, okActual := list.Hourly(drift.Actual)
+				if okExpected && okActual {
+					drift.HourlyCostDelta = (actual - expected) * float64(poolNodes(pool))
+				}
+				continue
+			}
+			perNode, okPrice := list.Hourly(pool.MachineType)
+			expected, errExpected := strconv.ParseInt(drift.Expected, 10, 64)
+			actual, errActual := strconv.ParseInt(drift.Actual, 10, 64)
+			if okPrice && errExpected == nil && errActual == nil {
+				drift.HourlyCostDelta = perNode * float64(actual-expected)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// poolNodes is the node count a pool is priced at: its autoscaling maximum,
+// or its node count per zone
+func poolNodes(pool *NodePoolConfig) int64 {
+	if pool.Autoscaling != nil && pool.Autoscaling.Enabled {
+		return pool.Autoscaling.MaxNodeCount
+	}
+	return pool.InitialNodeCount
+}
+
+// locationRegion returns the region of a zone such as us-central1-a, or the
+// location itself when it is a region
+func locationRegion(location string) string {
+	if strings.Count(location, "-") == 2 {
+		return location[:strings.LastIndex(location, "-")]
+	}
+	return location
+}
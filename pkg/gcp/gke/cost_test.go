@@ -0,0 +1,106 @@
+package gke
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"google.golang.org/api/cloudbilling/v1"
+)
+
+func testSKU(description, usageType string, units, nanos int64, regions ...string) *cloudbilling.Sku {
+	return &cloudbilling.Sku{
+		Description:    description,
+		Category:       &cloudbilling.Category{ResourceFamily: "Compute", UsageType: usageType},
+		ServiceRegions: regions,
+		PricingInfo: []*cloudbilling.PricingInfo{{
+			PricingExpression: &cloudbilling.PricingExpression{
+				TieredRates: []*cloudbilling.TierRate{{UnitPrice: &cloudbilling.Money{Units: units, Nanos: nanos}}},
+			},
+		}},
+	}
+}
+
+func TestPriceListFromSKUs(t *testing.T) {
+	skus := []*cloudbilling.Sku{
+		testSKU("N2 Instance Core running in Americas", "OnDemand", 0, 31611000, "us-central1"),
+		testSKU("N2 Instance Ram running in Americas", "OnDemand", 0, 4237000, "us-central1"),
+		testSKU("N2D AMD Instance Core running in Americas", "OnDemand", 0, 27502000, "us-central1"),
+		testSKU("N2D AMD Instance Ram running in Americas", "OnDemand", 0, 3686000, "us-central1"),
+		// Other usage types, regions and families without memory are ignored
+		testSKU("N2 Instance Core running in Americas", "Preemptible", 0, 7650000, "us-central1"),
+		testSKU("N2 Instance Core running in EMEA", "OnDemand", 0, 34773000, "europe-west1"),
+		testSKU("E2 Instance Core running in Americas", "OnDemand", 0, 21811000, "us-central1"),
+	}
+
+	prices := priceListFromSKUs(skus, "us-central1")
+	if len(prices) != 2 {
+		t.Fatalf("priceListFromSKUs() = %v, want n2 and n2d", prices)
+	}
+	if got := prices["n2"]; got != (ComputeRates{Core: 0.031611, RAM: 0.004237}) {
+		t.Errorf("n2 rates = %+v", got)
+	}
+	if got := prices["n2d"]; got != (ComputeRates{Core: 0.027502, RAM: 0.003686}) {
+		t.Errorf("n2d rates = %+v", got)
+	}
+}
+
+func TestAnnotateCost(t *testing.T) {
+	prices := PriceList{
+		"n1": {Core: 0.03, RAM: 0.004},
+		"n2": {Core: 0.03, RAM: 0.004},
+	}
+	var regions []string
+	lookup := func(ctx context.Context, region string) (PriceList, error) {
+		regions = append(regions, region)
+		if region == "europe-west1" {
+			return nil, fmt.Errorf("no prices")
+		}
+		return prices, nil
+	}
+
+	report := &DriftReport{Instances: []*ClusterDrift{
+		{
+			Name:     "prod",
+			Location: "us-central1-a",
+			NodePools: []*NodePoolConfig{
+				{Name: "default-pool", MachineType: "n1-highmem-32", InitialNodeCount: 3},
+				{Name: "batch", MachineType: "n2-standard-4", Autoscaling: &AutoscalingConfig{Enabled: true, MinNodeCount: 1, MaxNodeCount: 10}},
+			},
+			Drifts: []Drift{
+				{Field: "nodepool[default-pool].machine_type", Expected: "n1-standard-4", Actual: "n1-highmem-32"},
+				{Field: "nodepool[batch].autoscaling.max_node_count", Expected: "5", Actual: "10"},
+				{Field: "nodepool[default-pool].auto_repair", Expected: "true", Actual: "false"},
+				// Unpriced families are left unannotated
+				{Field: "nodepool[default-pool].machine_type", Expected: "a2-highgpu-1g", Actual: "n1-highmem-32"},
+			},
+		},
+		{
+			Name:      "eu",
+			Location:  "europe-west1",
+			NodePools: []*NodePoolConfig{{Name: "default-pool", MachineType: "n2-standard-4", InitialNodeCount: 1}},
+			Drifts:    []Drift{{Field: "nodepool[default-pool].machine_type", Expected: "n2-standard-2", Actual: "n2-standard-4"}},
+		},
+	}}
+
+	err := AnnotateCost(context.Background(), report, lookup)
+	if err == nil {
+		t.Error("AnnotateCost() error = nil, want the failed europe-west1 lookup")
+	}
+	if len(regions) != 2 || regions[0] != "us-central1" {
+		t.Errorf("looked up regions %v, want one lookup per cluster region", regions)
+	}
+
+	drifts := report.Instances[0].Drifts
+	// n1-highmem-32: 32*0.03 + 208*0.004 = 1.792; n1-standard-4: 4*0.03 + 15*0.004 = 0.18
+	want := []float64{(1.792 - 0.18) * 3, 5 * (4*0.03 + 16*0.004), 0, 0}
+	for i, drift := range drifts {
+		if math.Abs(drift.HourlyCostDelta-want[i]) > 1e-9 {
+			t.Errorf("%s cost delta = %v, want %v", drift.Field, drift.HourlyCostDelta, want[i])
+		}
+	}
+	if got := report.Instances[1].Drifts[0].HourlyCostDelta; got != 0 {
+		t.Errorf("cost delta without prices = %v, want 0", got)
+	}
+}
@@ -133,3 +133,25 @@ func extractMasterAuthorizedNets(cluster *container.Cluster) []string {
 	}
 	return nets
 }
+
+// extractClusterAutoscaling extracts cluster autoscaling from cluster. An
+// unspecified profile is reported as BALANCED, the profile GKE applies.
+func extractClusterAutoscaling(cluster *container.Cluster) *ClusterAutoscalingConfig {
+	if cluster.Autoscaling == nil {
+		return nil
+	}
+	config := &ClusterAutoscalingConfig{
+		NodeAutoProvisioning: cluster.Autoscaling.EnableNodeAutoprovisioning,
+		AutoscalingProfile:   cluster.Autoscaling.AutoscalingProfile,
+	}
+	if config.AutoscalingProfile == "" || config.AutoscalingProfile == "PROFILE_UNSPECIFIED" {
+		config.AutoscalingProfile = "BALANCED"
+	}
+	for _, limit := range cluster.Autoscaling.ResourceLimits {
+		if config.ResourceLimits == nil {
+			config.ResourceLimits = make(map[string]ResourceLimit)
+		}
+		config.ResourceLimits[limit.ResourceType] = ResourceLimit{Minimum: limit.Minimum, Maximum: limit.Maximum}
+	}
+	return config
+}
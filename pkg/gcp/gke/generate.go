@@ -0,0 +1,60 @@
+package gke
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// GenerateBaselineConfig renders a projects list and gke_baselines section
+// from discovered clusters. With groupBy, clusters are grouped by the value
+// of that label and each group gets a baseline selecting it through
+// filter_labels; without it one baseline covers every cluster. A baseline
+// takes the cluster configuration and node pools of its group's first
+// cluster by project and name.
+func GenerateBaselineConfig(clusters []*ClusterInstance, groupBy string) (string, error) {
+	if len(clusters) == 0 {
+		return "", fmt.Errorf("no clusters to generate config from")
+	}
+
+	resources := make([]analyzer.UniqueResource, len(clusters))
+	for i, cluster := range clusters {
+		resources[i] = analyzer.UniqueResource{Project: cluster.Project, Name: cluster.Name, Labels: cluster.Labels}
+	}
+	groups, unlabeled := analyzer.GroupByLabel(resources, groupBy)
+	if len(groups) == 0 {
+		return "", fmt.Errorf("no clusters have label %s", groupBy)
+	}
+
+	var config struct {
+		Projects     []string      `yaml:"projects"`
+		GKEBaselines []GKEBaseline `yaml:"gke_baselines"`
+	}
+	projects := make(map[string]bool)
+	comments := make([]string, 0, len(groups))
+	for _, group := range groups {
+		template := clusters[group.Members[0]]
+		baseline := GKEBaseline{Name: "default", ClusterConfig: template.Config}
+		if groupBy != "" {
+			baseline.Name = group.Value
+			baseline.FilterLabels = map[string]string{groupBy: group.Value}
+		}
+		// Pools are matched by name, so each group's pools are listed
+		for _, pool := range template.NodePools {
+			baseline.NodePools = append(baseline.NodePools, NodePoolBaseline{NodePoolConfig: *pool})
+		}
+		config.GKEBaselines = append(config.GKEBaselines, baseline)
+		comments = append(comments, group.Describe(resources, "cluster"))
+		for _, i := range group.Members {
+			projects[clusters[i].Project] = true
+		}
+	}
+	for project := range projects {
+		config.Projects = append(config.Projects, project)
+	}
+	sort.Strings(config.Projects)
+
+	header := analyzer.UnlabeledComment(resources, unlabeled, groupBy, "cluster")
+	return analyzer.MarshalGenerated(config, "gke_baselines", header, comments)
+}
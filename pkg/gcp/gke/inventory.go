@@ -0,0 +1,255 @@
+package gke
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	container "google.golang.org/api/container/v1"
+)
+
+// Inventory is the size of a cluster: the nodes it runs now and its capacity
+// with every node pool scaled to its autoscaling maximum
+type Inventory struct {
+	Nodes    int64 `json:"nodes" yaml:"nodes"`
+	MaxNodes int64 `json:"max_nodes" yaml:"max_nodes"`
+	// VCPUs and MemoryGB are the capacity at MaxNodes
+	VCPUs    int64   `json:"vcpus" yaml:"vcpus"`
+	MemoryGB float64 `json:"memory_gb" yaml:"memory_gb"`
+	// UnknownMachineTypes could not be sized and are left out of VCPUs and MemoryGB
+	UnknownMachineTypes []string `json:"unknown_machine_types,omitempty" yaml:"unknown_machine_types,omitempty"`
+}
+
+// memoryPerVCPU is the memory in GB per vCPU of predefined machine types by
+// family and class
+var memoryPerVCPU = map[string]float64{
+	"n1-standard":  3.75,
+	"n1-highmem":   6.5,
+	"n1-highcpu":   0.9,
+	"e2-standard":  4,
+	"e2-highmem":   8,
+	"e2-highcpu":   1,
+	"n2-standard":  4,
+	"n2-highmem":   8,
+	"n2-highcpu":   1,
+	"n2d-standard": 4,
+	"n2d-highmem":  8,
+	"n2d-highcpu":  1,
+	"n4-standard":  4,
+	"n4-highmem":   8,
+	"n4-highcpu":   2,
+	"c2-standard":  4,
+	"c2d-standard": 4,
+	"c2d-highmem":  8,
+	"c2d-highcpu":  2,
+	"c3-standard":  4,
+	"c3-highmem":   8,
+	"c3-highcpu":   2,
+	"c3d-standard": 4,
+	"c3d-highmem":  8,
+	"c3d-highcpu":  2,
+	"t2d-standard": 4,
+	"t2a-standard": 4,
+}
+
+// sharedCoreSizes are machine types without a vCPU count in their name
+var sharedCoreSizes = map[string]struct {
+	vcpus    int64
+	memoryGB float64
+}{
+	"e2-micro":  {2, 1},
+	"e2-small":  {2, 2},
+	"e2-medium": {2, 4},
+	"f1-micro":  {1, 0.6},
+	"g1-small":  {1, 1.7},
+}
+
+// machineTypeSize returns the vCPUs and memory of a machine type, covering
+// predefined types of common families and custom types such as
+// e2-custom-4-8192
+func machineTypeSize(machineType string) (vcpus int64, memoryGB float64, ok bool) {
+	name := strings.TrimSuffix(strings.TrimSuffix(machineType, "-ext"), "-lssd")
+	if size, found := sharedCoreSizes[name]; found {
+		return size.vcpus, size.memoryGB, true
+	}
+
+	if _, custom, found := strings.Cut(name, "custom-"); found {
+		cpus, memoryMB, found := strings.Cut(custom, "-")
+		if !found {
+			return 0, 0, false
+		}
+		vcpus, err := strconv.ParseInt(cpus, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		mb, err := strconv.ParseInt(memoryMB, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		return vcpus, float64(mb) / 1024, true
+	}
+
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		return 0, 0, false
+	}
+	ratio, found := memoryPerVCPU[name[:i]]
+	if !found {
+		return 0, 0, false
+	}
+	vcpus, err := strconv.ParseInt(name[i+1:], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return vcpus, float64(vcpus) * ratio, true
+}
+
+// poolMaxNodes returns the nodes a pool can scale to across its zones. A
+// pool without autoscaling keeps its node count per zone.
+func poolMaxNodes(np *container.NodePool) int64 {
+	zones := int64(len(np.Locations))
+	if zones == 0 {
+		zones = 1
+	}
+	if np.Autoscaling != nil && np.Autoscaling.Enabled {
+		if np.Autoscaling.TotalMaxNodeCount > 0 {
+			return np.Autoscaling.TotalMaxNodeCount
+		}
+		return np.Autoscaling.MaxNodeCount * zones
+	}
+	return np.InitialNodeCount * zones
+}
+
+// buildInventory sizes a cluster from its node pools' machine types and
+// autoscaling maxima
+func buildInventory(cluster *container.Cluster) *Inventory {
+	inv := &Inventory{Nodes: cluster.CurrentNodeCount}
+	unknown := make(map[string]bool)
+	for _, np := range cluster.NodePools {
+		nodes := poolMaxNodes(np)
+		inv.MaxNodes += nodes
+
+		machineType := ""
+		if np.Config != nil {
+			machineType = np.Config.MachineType
+		}
+		vcpus, memoryGB, ok := machineTypeSize(machineType)
+		if !ok {
+			unknown[machineType] = true
+			continue
+		}
+		inv.VCPUs += vcpus * nodes
+		inv.MemoryGB += memoryGB * float64(nodes)
+	}
+
+	for machineType := range unknown {
+		inv.UnknownMachineTypes = append(inv.UnknownMachineTypes, machineType)
+	}
+	sort.Strings(inv.UnknownMachineTypes)
+	return inv
+}
+
+// CapacityEnvelope caps the size of clusters carrying the given labels at
+// their autoscaling maxima. Zero caps are not checked.
+type CapacityEnvelope struct {
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	MaxNodes    int64             `yaml:"max_nodes,omitempty"`
+	MaxVCPUs    int64             `yaml:"max_vcpus,omitempty"`
+	MaxMemoryGB float64           `yaml:"max_memory_gb,omitempty"`
+}
+
+// Validate checks that the envelope caps something and no cap is negative
+func (e CapacityEnvelope) Validate() error {
+	if e.MaxNodes < 0 || e.MaxVCPUs < 0 || e.MaxMemoryGB < 0 {
+		return fmt.Errorf("capacity caps must not be negative")
+	}
+	if e.MaxNodes == 0 && e.MaxVCPUs == 0 && e.MaxMemoryGB == 0 {
+		return fmt.Errorf("capacity envelope sets no max_nodes, max_vcpus or max_memory_gb")
+	}
+	return nil
+}
+
+// matches reports whether a cluster carries every label of the envelope
+func (e CapacityEnvelope) matches(labels map[string]string) bool {
+	for key, value := range e.Labels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckCapacity flags clusters whose inventory exceeds the first envelope
+// their labels match
+func CheckCapacity(r *DriftReport, envelopes []CapacityEnvelope) {
+	for _, cluster := range r.Instances {
+		if cluster.Inventory == nil {
+			continue
+		}
+		for _, envelope := range envelopes {
+			if !envelope.matches(cluster.Labels) {
+				continue
+			}
+			drifted := len(cluster.Drifts) > 0
+			cluster.Drifts = envelope.check(cluster.Inventory, cluster.Drifts)
+			if !drifted && len(cluster.Drifts) > 0 {
+				r.DriftedClusters++
+			}
+			break
+		}
+	}
+}
+
+// check appends a drift per cap the inventory exceeds
+func (e CapacityEnvelope) check(inv *Inventory, drifts []Drift) []Drift {
+	if e.MaxNodes > 0 && inv.MaxNodes > e.MaxNodes {
+		drifts = checkCapacityNodes.Append(drifts, fmt.Sprintf("<= %d", e.MaxNodes), fmt.Sprintf("%d", inv.MaxNodes))
+	}
+	if e.MaxVCPUs > 0 && inv.VCPUs > e.MaxVCPUs {
+		drifts = checkCapacityVCPUs.Append(drifts, fmt.Sprintf("<= %d", e.MaxVCPUs), fmt.Sprintf("%d", inv.VCPUs))
+	}
+	if e.MaxMemoryGB > 0 && inv.MemoryGB > e.MaxMemoryGB {
+		drifts = checkCapacityMemory.Append(drifts, fmt.Sprintf("<= %.1f", e.MaxMemoryGB), fmt.Sprintf("%.1f", inv.MemoryGB))
+	}
+	return drifts
+}
+
+// FormatInventory renders the size of every cluster and the fleet total
+func FormatInventory(clusters []*ClusterDrift) string {
+	var sb strings.Builder
+	var total Inventory
+	rows := 0
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Cluster\tNodes\tMax nodes\tvCPUs\tMemory (GB)")
+	for _, cluster := range clusters {
+		inv := cluster.Inventory
+		if inv == nil {
+			continue
+		}
+		rows++
+		name := cluster.Name
+		if len(inv.UnknownMachineTypes) > 0 {
+			name += " *"
+		}
+		fmt.Fprintf(w, "  %s/%s\t%d\t%d\t%d\t%.1f\n", cluster.Project, name, inv.Nodes, inv.MaxNodes, inv.VCPUs, inv.MemoryGB)
+		total.Nodes += inv.Nodes
+		total.MaxNodes += inv.MaxNodes
+		total.VCPUs += inv.VCPUs
+		total.MemoryGB += inv.MemoryGB
+		total.UnknownMachineTypes = append(total.UnknownMachineTypes, inv.UnknownMachineTypes...)
+	}
+	if rows == 0 {
+		return ""
+	}
+	fmt.Fprintf(w, "  Total\t%d\t%d\t%d\t%.1f\n", total.Nodes, total.MaxNodes, total.VCPUs, total.MemoryGB)
+	w.Flush()
+
+	out := "Inventory (vCPUs and memory at autoscaling maxima):\n" + sb.String()
+	if len(total.UnknownMachineTypes) > 0 {
+		out += "* includes machine types that could not be sized\n"
+	}
+	return out + "\n"
+}
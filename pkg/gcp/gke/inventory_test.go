@@ -0,0 +1,128 @@
+package gke
+
+import (
+	"strings"
+	"testing"
+
+	container "google.golang.org/api/container/v1"
+)
+
+func TestMachineTypeSize(t *testing.T) {
+	tests := []struct {
+		machineType string
+		vcpus       int64
+		memoryGB    float64
+		ok          bool
+	}{
+		{"e2-standard-4", 4, 16, true},
+		{"n1-standard-8", 8, 30, true},
+		{"n2-highmem-16", 16, 128, true},
+		{"c3-standard-4-lssd", 4, 16, true},
+		{"e2-medium", 2, 4, true},
+		{"custom-4-16384", 4, 16, true},
+		{"n2-custom-8-49152-ext", 8, 48, true},
+		{"a2-highgpu-1g", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		vcpus, memoryGB, ok := machineTypeSize(tt.machineType)
+		if vcpus != tt.vcpus || memoryGB != tt.memoryGB || ok != tt.ok {
+			t.Errorf("machineTypeSize(%q) = %d, %v, %v, want %d, %v, %v",
+				tt.machineType, vcpus, memoryGB, ok, tt.vcpus, tt.memoryGB, tt.ok)
+		}
+	}
+}
+
+func TestBuildInventory(t *testing.T) {
+	cluster := &container.Cluster{
+		CurrentNodeCount: 7,
+		NodePools: []*container.NodePool{
+			// Regional pool autoscaling to 5 nodes per zone
+			{
+				Config:      &container.NodeConfig{MachineType: "e2-standard-4"},
+				Locations:   []string{"us-central1-a", "us-central1-b", "us-central1-c"},
+				Autoscaling: &container.NodePoolAutoscaling{Enabled: true, MaxNodeCount: 5},
+			},
+			// Total limit across zones
+			{
+				Config:      &container.NodeConfig{MachineType: "n2-highmem-8"},
+				Locations:   []string{"us-central1-a", "us-central1-b"},
+				Autoscaling: &container.NodePoolAutoscaling{Enabled: true, MaxNodeCount: 10, TotalMaxNodeCount: 4},
+			},
+			// Fixed size
+			{
+				Config:           &container.NodeConfig{MachineType: "a2-highgpu-1g"},
+				InitialNodeCount: 2,
+			},
+		},
+	}
+
+	inv := buildInventory(cluster)
+	if inv.Nodes != 7 || inv.MaxNodes != 21 {
+		t.Errorf("nodes = %d, max nodes = %d, want 7 and 21", inv.Nodes, inv.MaxNodes)
+	}
+	if inv.VCPUs != 15*4+4*8 || inv.MemoryGB != 15*16+4*64 {
+		t.Errorf("vcpus = %d, memory = %v, want 92 and 496", inv.VCPUs, inv.MemoryGB)
+	}
+	if strings.Join(inv.UnknownMachineTypes, ",") != "a2-highgpu-1g" {
+		t.Errorf("UnknownMachineTypes = %v", inv.UnknownMachineTypes)
+	}
+}
+
+func TestCheckCapacity(t *testing.T) {
+	envelopes := []CapacityEnvelope{
+		{Labels: map[string]string{"env": "prod"}, MaxNodes: 50, MaxVCPUs: 200},
+		{MaxNodes: 10, MaxMemoryGB: 64},
+	}
+	for _, e := range envelopes {
+		if err := e.Validate(); err != nil {
+			t.Fatalf("Validate() = %v", err)
+		}
+	}
+
+	r := &DriftReport{Instances: []*ClusterDrift{
+		{Name: "prod-1", Labels: map[string]string{"env": "prod"}, Inventory: &Inventory{MaxNodes: 30, VCPUs: 240, MemoryGB: 960}},
+		{Name: "dev-1", Labels: map[string]string{"env": "dev"}, Inventory: &Inventory{MaxNodes: 12, VCPUs: 48, MemoryGB: 192}},
+		{Name: "dev-2", Inventory: &Inventory{MaxNodes: 3, VCPUs: 6, MemoryGB: 12}},
+	}}
+	CheckCapacity(r, envelopes)
+
+	want := map[string]string{
+		// Only the first matching envelope applies
+		"prod-1": "capacity.max_vcpus",
+		"dev-1":  "capacity.max_nodes,capacity.max_memory_gb",
+		"dev-2":  "",
+	}
+	for _, cluster := range r.Instances {
+		var fields []string
+		for _, d := range cluster.Drifts {
+			fields = append(fields, d.Field)
+		}
+		if got := strings.Join(fields, ","); got != want[cluster.Name] {
+			t.Errorf("%s drifts = %s, want %s", cluster.Name, got, want[cluster.Name])
+		}
+	}
+	if r.DriftedClusters != 2 {
+		t.Errorf("DriftedClusters = %d, want 2", r.DriftedClusters)
+	}
+
+	if err := (CapacityEnvelope{Labels: map[string]string{"env": "prod"}}).Validate(); err == nil {
+		t.Error("Validate() of an envelope without caps = nil, want error")
+	}
+}
+
+func TestFormatInventory(t *testing.T) {
+	text := FormatInventory([]*ClusterDrift{
+		{Project: "p", Name: "a", Inventory: &Inventory{Nodes: 3, MaxNodes: 6, VCPUs: 24, MemoryGB: 96}},
+		{Project: "p", Name: "b", Inventory: &Inventory{Nodes: 1, MaxNodes: 2, VCPUs: 4, MemoryGB: 16, UnknownMachineTypes: []string{"a2-highgpu-1g"}}},
+	})
+	for _, want := range []string{"p/a", "p/b *", "Total", "112.0", "could not be sized"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatInventory() missing %q:\n%s", want, text)
+		}
+	}
+	if FormatInventory(nil) != "" {
+		t.Error("FormatInventory(nil) should be empty")
+	}
+}
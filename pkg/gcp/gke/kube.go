@@ -0,0 +1,224 @@
+package gke
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"golang.org/x/oauth2"
+)
+
+// kubeRequestTimeout bounds each Kubernetes API request
+const kubeRequestTimeout = 30 * time.Second
+
+// kubeListLimit is the page size of Kubernetes list requests
+const kubeListLimit = 500
+
+// WorkloadInspector reads the in-cluster state of GKE clusters through their
+// Kubernetes API, authenticating with the configured GCP credentials as
+// gcloud container clusters get-credentials does
+type WorkloadInspector struct {
+	analyzer.ProjectErrors
+
+	tokens oauth2.TokenSource
+}
+
+// NewWorkloadInspector creates an inspector using the configured GCP
+// credentials
+func NewWorkloadInspector(ctx context.Context) (*WorkloadInspector, error) {
+	tokens, err := credentials.TokenSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &WorkloadInspector{tokens: tokens}, nil
+}
+
+// AnalyzeWorkloads inspects every cluster and compares its in-cluster state
+// against the workload baseline. A cluster that can't be reached fails the
+// analysis, or with continue-on-error is listed under the report's errors.
+func (i *WorkloadInspector) AnalyzeWorkloads(ctx context.Context, clusters []*ClusterInstance, baseline *WorkloadBaseline) (*DriftReport, error) {
+	report := &DriftReport{
+		Timestamp: time.Now(),
+		Instances: make([]*ClusterDrift, 0),
+	}
+
+	for _, cluster := range clusters {
+		state, err := i.State(ctx, cluster)
+		if err != nil {
+			err = fmt.Errorf("failed to inspect workloads of cluster %s: %w", cluster.Name, err)
+			if i.Skip(cluster.Project, err) {
+				continue
+			}
+			return nil, err
+		}
+
+		drift := CompareWorkloads(cluster, state, baseline)
+		report.Instances = append(report.Instances, drift)
+		if len(drift.Drifts) > 0 {
+			report.DriftedClusters++
+		}
+	}
+	report.TotalClusters = len(report.Instances)
+	report.Errors = i.ScanErrors()
+	return report, nil
+}
+
+// State reads the namespaces, deployments and DaemonSets of a cluster
+func (i *WorkloadInspector) State(ctx context.Context, cluster *ClusterInstance) (*WorkloadState, error) {
+	client, err := i.client(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &WorkloadState{
+		Namespaces:  make(map[string]map[string]string),
+		Deployments: make(map[string]WorkloadStatus),
+		DaemonSets:  make(map[string]WorkloadStatus),
+	}
+
+	var namespaces []struct {
+		Metadata kubeMetadata `json:"metadata"`
+	}
+	if err := list(ctx, client, "/api/v1/namespaces", &namespaces); err != nil {
+		return nil, err
+	}
+	for _, ns := range namespaces {
+		state.Namespaces[ns.Metadata.Name] = ns.Metadata.Labels
+	}
+
+	var deployments []struct {
+		Metadata kubeMetadata `json:"metadata"`
+		Spec     struct {
+			Replicas *int32 `json:"replicas"`
+		} `json:"spec"`
+		Status struct {
+			AvailableReplicas int32 `json:"availableReplicas"`
+		} `json:"status"`
+	}
+	if err := list(ctx, client, "/apis/apps/v1/deployments", &deployments); err != nil {
+		return nil, err
+	}
+	for _, d := range deployments {
+		// Replicas defaults to 1 when unset
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		state.Deployments[d.Metadata.key()] = WorkloadStatus{Desired: desired, Available: d.Status.AvailableReplicas}
+	}
+
+	var daemonSets []struct {
+		Metadata kubeMetadata `json:"metadata"`
+		Status   struct {
+			DesiredNumberScheduled int32 `json:"desiredNumberScheduled"`
+			NumberAvailable        int32 `json:"numberAvailable"`
+		} `json:"status"`
+	}
+	if err := list(ctx, client, "/apis/apps/v1/daemonsets", &daemonSets); err != nil {
+		return nil, err
+	}
+	for _, ds := range daemonSets {
+		state.DaemonSets[ds.Metadata.key()] = WorkloadStatus{Desired: ds.Status.DesiredNumberScheduled, Available: ds.Status.NumberAvailable}
+	}
+	return state, nil
+}
+
+// kubeMetadata is the object metadata read from the Kubernetes API
+type kubeMetadata struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels"`
+}
+
+// key identifies a namespaced object as namespace/name
+func (m kubeMetadata) key() string {
+	return m.Namespace + "/" + m.Name
+}
+
+// kubeClient issues read-only requests to a cluster's Kubernetes API
+type kubeClient struct {
+	endpoint string
+	http     *http.Client
+}
+
+// client returns a Kubernetes API client for a cluster that trusts the
+// cluster's CA and authenticates with the inspector's tokens
+func (i *WorkloadInspector) client(cluster *ClusterInstance) (*kubeClient, error) {
+	if cluster.Endpoint == "" {
+		return nil, fmt.Errorf("cluster has no endpoint")
+	}
+	pem, err := base64.StdEncoding.DecodeString(cluster.CACertificate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cluster CA certificate: %w", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("cluster has no CA certificate")
+	}
+
+	transport := &oauth2.Transport{
+		Source: i.tokens,
+		Base: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+		},
+	}
+	return &kubeClient{
+		endpoint: "https://" + cluster.Endpoint,
+		http:     &http.Client{Transport: transport, Timeout: kubeRequestTimeout},
+	}, nil
+}
+
+// list reads every page of a list request into items
+func list[T any](ctx context.Context, c *kubeClient, path string, items *[]T) error {
+	next := ""
+	for {
+		query := url.Values{"limit": {fmt.Sprint(kubeListLimit)}}
+		if next != "" {
+			query.Set("continue", next)
+		}
+		var page struct {
+			Metadata struct {
+				Continue string `json:"continue"`
+			} `json:"metadata"`
+			Items []T `json:"items"`
+		}
+		if err := c.get(ctx, path+"?"+query.Encode(), &page); err != nil {
+			return err
+		}
+		*items = append(*items, page.Items...)
+		if page.Metadata.Continue == "" {
+			return nil
+		}
+		next = page.Metadata.Continue
+	}
+}
+
+// get decodes the JSON response of a GET request
+func (c *kubeClient) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s: %s: %s", path, resp.Status, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,29 @@
+package gke
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// SetLabelPolicy sets the labels every cluster must carry
+func (a *Analyzer) SetLabelPolicy(policy *analyzer.LabelPolicy) {
+	a.labels = policy
+}
+
+// checkLabelPolicy reports labels missing from the cluster or with a value
+// the label policy does not accept. Labels already reported by the baseline
+// comparison are not reported twice, and the baseline's severity overrides
+// do not apply.
+func (a *Analyzer) checkLabelPolicy(cluster *ClusterInstance, drift *ClusterDrift) {
+	reported := make(map[string]bool)
+	for _, d := range drift.Drifts {
+		reported[d.Field] = true
+	}
+	for _, v := range a.labels.Violations(cluster.Labels) {
+		check := checkLabelPolicy.At(v.Key)
+		if reported[check.Path] {
+			continue
+		}
+		drift.Drifts = check.Append(drift.Drifts, v.Expected, v.Actual)
+		if a.labels.Severity != "" {
+			drift.Drifts[len(drift.Drifts)-1].Severity = a.labels.Severity
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package gke
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// NodePoolBaseline is the expected configuration of the node pools selected
+// by name or pattern, e.g. separate expectations for default-pool and GPU
+// pools. A pool set by name is required; it is reported missing when a
+// cluster lacks it.
+type NodePoolBaseline struct {
+	// NamePattern is a regular expression selecting pools; set it instead of
+	// the config's name to match several pools
+	NamePattern string `yaml:"name_pattern,omitempty"`
+	// Optional suppresses the missing finding for a pool set by name
+	Optional bool `yaml:"optional,omitempty"`
+
+	NodePoolConfig `yaml:",inline"`
+}
+
+// Validate checks that the baseline selects pools by name or pattern and
+// that its field references are known
+func (b NodePoolBaseline) Validate() error {
+	switch {
+	case b.Name == "" && b.NamePattern == "":
+		return fmt.Errorf("set name or name_pattern")
+	case b.Name != "" && b.NamePattern != "":
+		return fmt.Errorf("name and name_pattern cannot be combined")
+	}
+	if err := (analyzer.NameScope{Pattern: b.NamePattern}).Validate(); err != nil {
+		return err
+	}
+	return b.NodePoolConfig.validate()
+}
+
+// validate checks the node pool field references
+func (c *NodePoolConfig) validate() error {
+	if err := c.AllowedValues.Validate(); err != nil {
+		return err
+	}
+	if err := c.SeverityOverrides.Validate(); err != nil {
+		return err
+	}
+	for _, pattern := range c.ForbiddenTaints {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid forbidden taint %q: %w", pattern, err)
+		}
+	}
+	return c.Ranges.Validate()
+}
+
+// NodePoolBaselines selects the baseline each node pool is compared against
+type NodePoolBaselines struct {
+	// Pools are matched by exact name first, then by pattern in order
+	Pools []NodePoolBaseline
+	// Default applies to pools no entry matches. Without it such pools are
+	// reported as extra.
+	Default *NodePoolConfig
+}
+
+// NodePoolBaselines returns the node pool baselines of the baseline, or nil
+// when node pools are not compared
+func (b GKEBaseline) NodePoolBaselines() *NodePoolBaselines {
+	if len(b.NodePools) == 0 && b.NodePoolConfig == nil {
+		return nil
+	}
+	return &NodePoolBaselines{Pools: b.NodePools, Default: b.NodePoolConfig}
+}
+
+// match returns the baseline of a pool, or nil when none applies
+func (n *NodePoolBaselines) match(pool string) *NodePoolConfig {
+	for i := range n.Pools {
+		if n.Pools[i].Name == pool {
+			return &n.Pools[i].NodePoolConfig
+		}
+	}
+	for i := range n.Pools {
+		if p := &n.Pools[i]; p.NamePattern != "" && (analyzer.NameScope{Pattern: p.NamePattern}).Matches(pool) {
+			return &p.NodePoolConfig
+		}
+	}
+	return n.Default
+}
+
+// compareNodePools compares each node pool against its baseline and reports
+// required pools the cluster lacks and, without a default, unmatched pools
+func (a *Analyzer) compareNodePools(actualPools []*NodePoolConfig, baselines *NodePoolBaselines, drift *ClusterDrift) {
+	present := make(map[string]bool, len(actualPools))
+	for _, pool := range actualPools {
+		present[pool.Name] = true
+
+		baseline := baselines.match(pool.Name)
+		if baseline == nil {
+			drift.Drifts = checkPoolExtra.At(pool.Name).Append(drift.Drifts, "absent", "present")
+			continue
+		}
+		start := len(drift.Drifts)
+		a.compareNodePool(pool, baseline, drift)
+		baseline.SeverityOverrides.Apply(drift.Drifts[start:])
+	}
+
+	for i := range baselines.Pools {
+		expected := &baselines.Pools[i]
+		if expected.Name == "" || expected.Optional || present[expected.Name] {
+			continue
+		}
+		start := len(drift.Drifts)
+		drift.Drifts = checkPoolMissing.At(expected.Name).Append(drift.Drifts, "present", "missing")
+		expected.SeverityOverrides.Apply(drift.Drifts[start:])
+	}
+}
+
+// compareNodePool compares a node pool against its baseline
+func (a *Analyzer) compareNodePool(pool, baseline *NodePoolConfig, drift *ClusterDrift) {
+	drift.Drifts = checkPoolMachineType.At(pool.Name).OneOf(drift.Drifts, baseline.AllowedValues, baseline.MachineType, pool.MachineType)
+	drift.Drifts = checkPoolDiskSize.At(pool.Name).Number(drift.Drifts, baseline.Ranges, baseline.DiskSizeGB, pool.DiskSizeGB)
+	drift.Drifts = checkPoolImageType.At(pool.Name).OneOf(drift.Drifts, baseline.AllowedValues, baseline.ImageType, pool.ImageType)
+	drift.Drifts = checkPoolAutoUpgrade.At(pool.Name).Bool(drift.Drifts, baseline.AutoUpgrade, pool.AutoUpgrade)
+	drift.Drifts = checkPoolAutoRepair.At(pool.Name).Bool(drift.Drifts, baseline.AutoRepair, pool.AutoRepair)
+	a.compareAutoscaling(pool, baseline, drift)
+	a.compareScheduling(pool, baseline, drift)
+}
+
+// compareAutoscaling compares a node pool's autoscaling against its baseline.
+// Node counts are only compared when both enable autoscaling; ranges on them
+// accept counts tuned per cluster.
+func (a *Analyzer) compareAutoscaling(pool, baseline *NodePoolConfig, drift *ClusterDrift) {
+	if baseline.Autoscaling == nil {
+		return
+	}
+	expected := baseline.Autoscaling
+	actual := pool.Autoscaling
+	if actual == nil {
+		actual = &AutoscalingConfig{}
+	}
+
+	switch {
+	case expected.Enabled && !actual.Enabled:
+		drift.Drifts = checkPoolAutoscaling.At(pool.Name).Append(drift.Drifts, "true", "false")
+	case !expected.Enabled && actual.Enabled:
+		drift.Drifts = checkPoolAutoscalingOff.At(pool.Name).Append(drift.Drifts, "false", "true")
+	case expected.Enabled:
+		drift.Drifts = checkPoolMinNodes.At(pool.Name).Number(drift.Drifts, baseline.Ranges, expected.MinNodeCount, actual.MinNodeCount)
+		drift.Drifts = checkPoolMaxNodes.At(pool.Name).Number(drift.Drifts, baseline.Ranges, expected.MaxNodeCount, actual.MaxNodeCount)
+	}
+}
+
+// compareScheduling checks that a node pool carries the baseline's labels
+// and taints, which workloads select and tolerate, and none of its
+// forbidden taints. Labels and taints not in the baseline are ignored.
+func (a *Analyzer) compareScheduling(pool, baseline *NodePoolConfig, drift *ClusterDrift) {
+	for _, key := range sortedKeys(baseline.Labels) {
+		actual, exists := pool.Labels[key]
+		if !exists {
+			actual = "not set"
+		}
+		drift.Drifts = checkPoolLabel.At(pool.Name).At(key).String(drift.Drifts, baseline.Labels[key], actual)
+	}
+
+	actualTaints := make(map[string]string, len(pool.Taints))
+	for _, taint := range pool.Taints {
+		actualTaints[taintKey(taint)] = taint
+	}
+	for _, taint := range baseline.Taints {
+		expected := normalizeTaint(taint)
+		actual, exists := actualTaints[taintKey(expected)]
+		if !exists {
+			actual = "not set"
+		}
+		drift.Drifts = checkPoolTaint.At(pool.Name).At(taintKey(expected)).String(drift.Drifts, expected, actual)
+	}
+
+	for _, taint := range pool.Taints {
+		for _, pattern := range baseline.ForbiddenTaints {
+			if matchTaint(normalizeTaint(pattern), taint) {
+				drift.Drifts = checkPoolForbiddenTaint.At(pool.Name).At(taintKey(taint)).Append(drift.Drifts, "not "+pattern, taint)
+				break
+			}
+		}
+	}
+}
+
+// matchTaint matches a taint against a shell wildcard pattern in which *
+// also matches the / of domain-prefixed keys such as nvidia.com/gpu
+func matchTaint(pattern, taint string) bool {
+	matched, _ := path.Match(strings.ReplaceAll(pattern, "/", "\x00"), strings.ReplaceAll(taint, "/", "\x00"))
+	return matched
+}
+
+// taintEffects maps the kubectl spelling of taint effects to the GKE API's
+var taintEffects = strings.NewReplacer(
+	":NoSchedule", ":NO_SCHEDULE",
+	":PreferNoSchedule", ":PREFER_NO_SCHEDULE",
+	":NoExecute", ":NO_EXECUTE",
+)
+
+// normalizeTaint spells a baseline taint key=value:effect as the GKE API
+// reports it, so both kubectl and API effect names can be used
+func normalizeTaint(taint string) string {
+	return taintEffects.Replace(taint)
+}
+
+// taintKey returns the key of a key=value:effect taint
+func taintKey(taint string) string {
+	if i := strings.IndexAny(taint, "=:"); i >= 0 {
+		return taint[:i]
+	}
+	return taint
+}
+
+// sortedKeys returns the keys of a map in order, so drifts are reported
+// deterministically
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
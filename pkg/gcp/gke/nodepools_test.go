@@ -0,0 +1,207 @@
+package gke
+
+import (
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+func TestCompareNodePoolsPerPool(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := GKEBaseline{
+		Name: "prod",
+		NodePools: []NodePoolBaseline{
+			{NodePoolConfig: NodePoolConfig{Name: "default-pool", MachineType: "n2-standard-4"}},
+			{NamePattern: "^gpu-", NodePoolConfig: NodePoolConfig{
+				MachineType:       "a2-highgpu-1g",
+				SeverityOverrides: checks.SeverityOverrides{"nodepool[*].machine_type": "critical"},
+			}},
+			{NodePoolConfig: NodePoolConfig{Name: "system", MachineType: "e2-standard-2"}},
+			{Optional: true, NodePoolConfig: NodePoolConfig{Name: "batch", MachineType: "e2-standard-8"}},
+		},
+	}
+	if err := baseline.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	cluster := &ClusterInstance{
+		Name:   "prod",
+		Config: &ClusterConfig{},
+		NodePools: []*NodePoolConfig{
+			{Name: "default-pool", MachineType: "n2-standard-4"},
+			{Name: "gpu-a100", MachineType: "n2-standard-4"},
+			{Name: "scratch", MachineType: "e2-medium"},
+		},
+	}
+
+	got := make(map[string]string)
+	for _, d := range a.analyzeCluster(cluster, &ClusterConfig{}, baseline.NodePoolBaselines()).Drifts {
+		got[d.Field] = d.Expected + " " + d.Actual + " " + d.Severity
+	}
+	want := map[string]string{
+		"nodepool[gpu-a100].machine_type": "a2-highgpu-1g n2-standard-4 critical",
+		"nodepool[scratch]":               "absent present medium",
+		"nodepool[system]":                "present missing high",
+	}
+	if len(got) != len(want) {
+		t.Errorf("drifts = %v, want %v", got, want)
+	}
+	for field, expected := range want {
+		if got[field] != expected {
+			t.Errorf("%s = %q, want %q", field, got[field], expected)
+		}
+	}
+
+	// A default baseline covers unmatched pools instead of reporting them
+	baseline.NodePoolConfig = &NodePoolConfig{MachineType: "e2-medium"}
+	for _, d := range a.analyzeCluster(cluster, &ClusterConfig{}, baseline.NodePoolBaselines()).Drifts {
+		if d.Field == "nodepool[scratch]" || d.Field == "nodepool[scratch].machine_type" {
+			t.Errorf("unexpected drift on %s with a default baseline", d.Field)
+		}
+	}
+}
+
+func TestNodePoolBaselineValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		pools   []NodePoolBaseline
+		wantErr bool
+	}{
+		{"by name", []NodePoolBaseline{{NodePoolConfig: NodePoolConfig{Name: "default-pool"}}}, false},
+		{"by pattern", []NodePoolBaseline{{NamePattern: "^gpu-"}}, false},
+		{"no selector", []NodePoolBaseline{{}}, true},
+		{"name and pattern", []NodePoolBaseline{{NamePattern: "^gpu-", NodePoolConfig: NodePoolConfig{Name: "gpu"}}}, true},
+		{"invalid pattern", []NodePoolBaseline{{NamePattern: "(["}}, true},
+		{"duplicate name", []NodePoolBaseline{
+			{NodePoolConfig: NodePoolConfig{Name: "default-pool"}},
+			{NodePoolConfig: NodePoolConfig{Name: "default-pool"}},
+		}, true},
+		{"unknown field", []NodePoolBaseline{{NamePattern: ".*", NodePoolConfig: NodePoolConfig{
+			AllowedValues: checks.Allowed{"nodepool[*].color": {"blue"}},
+		}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GKEBaseline{Name: "prod", NodePools: tt.pools}.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompareAutoscaling(t *testing.T) {
+	a := &Analyzer{}
+	baseline := &NodePoolConfig{
+		Autoscaling: &AutoscalingConfig{Enabled: true, MinNodeCount: 1, MaxNodeCount: 10},
+		Ranges:      checks.Ranges{"nodepool[*].autoscaling.max_node_count": {Tolerance: 5}},
+	}
+	if err := (GKEBaseline{Name: "prod", NodePoolConfig: baseline}).Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		baseline    *AutoscalingConfig
+		autoscaling *AutoscalingConfig
+		want        map[string]string
+	}{
+		{"matching", nil, &AutoscalingConfig{Enabled: true, MinNodeCount: 1, MaxNodeCount: 10}, map[string]string{}},
+		{"max within tolerance", nil, &AutoscalingConfig{Enabled: true, MinNodeCount: 1, MaxNodeCount: 15}, map[string]string{}},
+		{"counts outside baseline", nil, &AutoscalingConfig{Enabled: true, MinNodeCount: 3, MaxNodeCount: 20}, map[string]string{
+			"nodepool[default].autoscaling.min_node_count": "1 3 medium",
+			"nodepool[default].autoscaling.max_node_count": "10 ±5 20 medium",
+		}},
+		{"disabled unexpectedly", nil, nil, map[string]string{
+			"nodepool[default].autoscaling.enabled": "true false high",
+		}},
+		{"enabled unexpectedly", &AutoscalingConfig{}, &AutoscalingConfig{Enabled: true, MaxNodeCount: 3}, map[string]string{
+			"nodepool[default].autoscaling.enabled": "false true medium",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := *baseline
+			if tt.baseline != nil {
+				expected.Autoscaling = tt.baseline
+			}
+			drift := &ClusterDrift{}
+			a.compareAutoscaling(&NodePoolConfig{Name: "default", Autoscaling: tt.autoscaling}, &expected, drift)
+
+			got := make(map[string]string)
+			for _, d := range drift.Drifts {
+				got[d.Field] = d.Expected + " " + d.Actual + " " + d.Severity
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("drifts = %v, want %v", got, tt.want)
+			}
+			for field, want := range tt.want {
+				if got[field] != want {
+					t.Errorf("%s = %q, want %q", field, got[field], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareScheduling(t *testing.T) {
+	a := &Analyzer{}
+	baseline := &NodePoolConfig{
+		Labels:            map[string]string{"workload": "gpu", "team": "ml"},
+		Taints:            []string{"nvidia.com/gpu=present:NoSchedule", "dedicated=ml:NO_SCHEDULE"},
+		ForbiddenTaints:   []string{"spot=*", "*:NoExecute", "*tpu*"},
+		SeverityOverrides: checks.SeverityOverrides{"nodepool[*].labels[*]": "medium"},
+	}
+	if err := (GKEBaseline{Name: "prod", NodePoolConfig: baseline}).Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	pool := &NodePoolConfig{
+		Name:   "gpu",
+		Labels: map[string]string{"workload": "gpu", "team": "search", "extra": "ignored"},
+		Taints: []string{
+			"nvidia.com/gpu=present:NO_SCHEDULE",
+			"dedicated=search:NO_SCHEDULE",
+			"spot=true:PREFER_NO_SCHEDULE",
+			"maintenance=true:NO_EXECUTE",
+			"other=true:NO_SCHEDULE",
+			"google.com/tpu=present:NO_SCHEDULE",
+		},
+	}
+	drift := &ClusterDrift{}
+	a.compareNodePool(pool, baseline, drift)
+	baseline.SeverityOverrides.Apply(drift.Drifts)
+
+	got := make(map[string]string)
+	for _, d := range drift.Drifts {
+		got[d.Field] = d.Expected + " " + d.Actual + " " + d.Severity
+	}
+	want := map[string]string{
+		"nodepool[gpu].labels[team]":        "ml search medium",
+		"nodepool[gpu].taints[dedicated]":   "dedicated=ml:NO_SCHEDULE dedicated=search:NO_SCHEDULE medium",
+		"nodepool[gpu].taints[spot]":        "not spot=* spot=true:PREFER_NO_SCHEDULE high",
+		"nodepool[gpu].taints[maintenance]": "not *:NoExecute maintenance=true:NO_EXECUTE high",
+		// * matches the / of a domain-prefixed key
+		"nodepool[gpu].taints[google.com/tpu]": "not *tpu* google.com/tpu=present:NO_SCHEDULE high",
+	}
+	if len(got) != len(want) {
+		t.Errorf("drifts = %v, want %v", got, want)
+	}
+	for field, w := range want {
+		if got[field] != w {
+			t.Errorf("%s = %q, want %q", field, got[field], w)
+		}
+	}
+
+	// The forbidden taint is attributed to its own check though it shares
+	// the required taint's path
+	if c, ok := checks.ForDrift(resourceType, "nodepool[gpu].taints[spot]", "high"); !ok || c != checkPoolForbiddenTaint {
+		t.Errorf("ForDrift() = %v, want %s", c, checkPoolForbiddenTaint.ID)
+	}
+	if c, ok := checks.ForDrift(resourceType, "nodepool[gpu].labels[team]", "high"); !ok || c != checkPoolLabel {
+		t.Errorf("ForDrift() = %v, want %s", c, checkPoolLabel.ID)
+	}
+}
@@ -0,0 +1,39 @@
+package gke
+
+import (
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// DefaultOperationalStatuses are the severities of unhealthy cluster
+// statuses unless overridden in the operational section of the config
+var DefaultOperationalStatuses = analyzer.OperationalSeverities{
+	"ERROR":    "critical",
+	"DEGRADED": "high",
+}
+
+// SetOperationalSeverities overrides the severities of unhealthy cluster
+// statuses; statuses left out keep their defaults
+func (a *Analyzer) SetOperationalSeverities(statuses analyzer.OperationalSeverities) {
+	a.statuses = statuses
+}
+
+// checkOperationalStatus reports a cluster in an unhealthy status as an
+// operational finding, with the messages of its conditions. It is added
+// after the baseline's severity overrides, which apply to configuration
+// drift.
+func (a *Analyzer) checkOperationalStatus(cluster *ClusterInstance, drift *ClusterDrift) {
+	severity, ok := a.statuses.Severity(cluster.Status, DefaultOperationalStatuses)
+	if !ok {
+		return
+	}
+	actual := cluster.Status
+	if len(cluster.Conditions) > 0 {
+		actual += ": " + strings.Join(cluster.Conditions, "; ")
+	}
+	drift.Drifts = checkStatus.Append(drift.Drifts, "RUNNING", actual)
+	finding := &drift.Drifts[len(drift.Drifts)-1]
+	finding.Severity = severity
+	finding.Operational = true
+}
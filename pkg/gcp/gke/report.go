@@ -17,6 +17,14 @@ type DriftReport struct {
 	TotalClusters   int             `json:"total_clusters" yaml:"total_clusters"`
 	DriftedClusters int             `json:"drifted_clusters" yaml:"drifted_clusters"`
 	Instances       []*ClusterDrift `json:"instances" yaml:"instances"`
+	// Warnings flag a baseline that is due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []report.ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []report.Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []report.SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
 }
 
 // ClusterDrift represents drift analysis results for a single GKE cluster
@@ -27,7 +35,10 @@ type ClusterDrift struct {
 	Status    string            `json:"status" yaml:"status"`
 	Labels    map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
 	NodePools []*NodePoolConfig `json:"node_pools,omitempty" yaml:"node_pools,omitempty"`
+	Inventory *Inventory        `json:"inventory,omitempty" yaml:"inventory,omitempty"`
 	Drifts    []Drift           `json:"drifts" yaml:"drifts"`
+	// Recommendations describe remediation steps, including recreation impact
+	Recommendations []string `json:"recommendations,omitempty" yaml:"recommendations,omitempty"`
 }
 
 // Drift represents a single configuration difference from the baseline
@@ -52,6 +63,13 @@ func (r *DriftReport) FormatText() string {
 	// Summary by severity
 	criticalCount, highCount, mediumCount, lowCount := r.countBySeverity()
 	sb.WriteString(report.FormatDriftSummary(criticalCount, highCount, mediumCount, lowCount))
+	if delta := r.HourlyCostDelta(); delta != 0 {
+		sb.WriteString(fmt.Sprintf("Estimated Cost Delta: %s\n\n", report.FormatCostDelta(delta)))
+	}
+	sb.WriteString(report.FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(report.FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(report.FormatSLO(r.SLO))
+	sb.WriteString(FormatInventory(r.Instances))
 
 	// Detailed cluster reports
 	for i, cluster := range r.Instances {
@@ -60,10 +78,22 @@ func (r *DriftReport) FormatText() string {
 		}
 		sb.WriteString(cluster.FormatText())
 	}
+	sb.WriteString(report.FormatScanErrors(r.Errors))
 
 	return sb.String()
 }
 
+// HourlyCostDelta sums the estimated hourly cost deltas of all drifts
+func (r *DriftReport) HourlyCostDelta() float64 {
+	var delta float64
+	for _, cluster := range r.Instances {
+		for _, drift := range cluster.Drifts {
+			delta += drift.HourlyCostDelta
+		}
+	}
+	return delta
+}
+
 // countBySeverity tallies the number of drifts by severity level across all clusters
 func (r *DriftReport) countBySeverity() (critical, high, medium, low int) {
 	for _, cluster := range r.Instances {
@@ -131,6 +161,16 @@ func (cd *ClusterDrift) FormatText() string {
 	sb.WriteString("\n")
 	sb.WriteString(report.FormatDrifts(cd.Drifts))
 
+	if len(cd.Recommendations) > 0 {
+		recStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("201"))
+
+		sb.WriteString(labelStyle.Render("Recommendations:") + "\n")
+		for _, rec := range cd.Recommendations {
+			sb.WriteString(recStyle.Render("  → "+rec) + "\n")
+		}
+	}
+
 	return sb.String()
 }
 
@@ -151,3 +191,34 @@ func (r *DriftReport) FormatYAML() (string, error) {
 	}
 	return string(data), nil
 }
+
+// ToReport converts the GKE drift report into the generic report model
+func (r *DriftReport) ToReport() *report.Report {
+	resources := make([]report.Resource, 0, len(r.Instances))
+	for _, cluster := range r.Instances {
+		resources = append(resources, report.Resource{
+			Type:     "GKE Cluster",
+			Project:  cluster.Project,
+			Name:     cluster.Name,
+			Location: cluster.Location,
+			State:    cluster.Status,
+			Labels:   cluster.Labels,
+			Drifts:   cluster.Drifts,
+		})
+	}
+
+	return &report.Report{
+		Title:            "GCP GKE Drift Analysis Report",
+		Timestamp:        r.Timestamp,
+		Resources:        resources,
+		Warnings:         r.Warnings,
+		Errors:           r.Errors,
+		Acknowledgements: r.Acknowledgements,
+		SLO:              r.SLO,
+	}
+}
+
+// FormatNDJSON generates one JSON object per drift finding, tagged with a new scan ID
+func (r *DriftReport) FormatNDJSON() (string, error) {
+	return r.ToReport().FormatNDJSON(report.NewScanID())
+}
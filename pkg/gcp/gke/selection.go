@@ -0,0 +1,46 @@
+package gke
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// Scope returns the name restriction of the baseline
+func (b GKEBaseline) Scope() analyzer.NameScope {
+	return analyzer.NameScope{Names: b.InstanceNames, Pattern: b.NamePattern}
+}
+
+// Selects reports whether the baseline applies to a cluster: it carries
+// every filter label and its name is in the baseline's scope
+func (b GKEBaseline) Selects(cluster *ClusterInstance) bool {
+	if len(b.FilterLabels) > 0 && !matchesLabels(cluster, b.FilterLabels) {
+		return false
+	}
+	return b.Scope().Matches(cluster.Name)
+}
+
+// SelectClusters returns the clusters a baseline applies to. Baselines
+// scoped by name take precedence: a cluster selected by one of them is not
+// analyzed by baselines that only filter by labels.
+func SelectClusters(clusters []*ClusterInstance, baseline GKEBaseline, baselines []GKEBaseline) []*ClusterInstance {
+	selected := make([]*ClusterInstance, 0)
+	for _, cluster := range clusters {
+		if baseline.Selects(cluster) && (baseline.Scope().Scoped() || !claimedByScoped(cluster, baselines)) {
+			selected = append(selected, cluster)
+		}
+	}
+	return selected
+}
+
+// claimedByScoped reports whether a name-scoped baseline selects the cluster
+func claimedByScoped(cluster *ClusterInstance, baselines []GKEBaseline) bool {
+	for _, b := range baselines {
+		if b.Scope().Scoped() && b.Selects(cluster) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByLabels returns the clusters carrying every label; no labels select
+// every cluster
+func FilterByLabels(clusters []*ClusterInstance, labels map[string]string) []*ClusterInstance {
+	return filterClustersByLabels(clusters, labels)
+}
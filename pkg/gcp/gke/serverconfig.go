@@ -0,0 +1,93 @@
+package gke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+	"github.com/jessequinn/drift-analysis-cli/pkg/metacache"
+)
+
+// ServerConfig lists the GKE versions a location currently offers
+type ServerConfig struct {
+	ValidMasterVersions []string `json:"valid_master_versions"`
+	ValidNodeVersions   []string `json:"valid_node_versions"`
+}
+
+// FetchServerConfig returns the server config of a location, read through
+// the metadata cache
+func (a *Analyzer) FetchServerConfig(ctx context.Context, cache *metacache.Cache, project, location string) (*ServerConfig, error) {
+	key := fmt.Sprintf("gke-server-config/%s/%s", project, location)
+	return metacache.Lookup(cache, key, func() (*ServerConfig, error) {
+		name := fmt.Sprintf("projects/%s/locations/%s", project, location)
+		resp, err := retry.Do(ctx, a.retry, a.service.Projects.Locations.GetServerConfig(name).Context(ctx).Do)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GKE server config for %s: %w", location, err)
+		}
+		return &ServerConfig{
+			ValidMasterVersions: resp.ValidMasterVersions,
+			ValidNodeVersions:   resp.ValidNodeVersions,
+		}, nil
+	})
+}
+
+// AnnotateStaleBaseline warns on master version drifts whose expected
+// version the cluster's location no longer offers: the baseline itself needs
+// updating, not only the cluster. Failed lookups are returned after every
+// cluster has been checked.
+func (a *Analyzer) AnnotateStaleBaseline(ctx context.Context, cache *metacache.Cache, report *DriftReport, baseline *ClusterConfig) error {
+	if baseline == nil {
+		return nil
+	}
+	expected := baseline.AllowedValues.Values(checkMasterVersion.Path)
+	if len(expected) == 0 && baseline.MasterVersion != "" {
+		expected = []string{baseline.MasterVersion}
+	}
+	if len(expected) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, cluster := range report.Instances {
+		for i := range cluster.Drifts {
+			if cluster.Drifts[i].Field != checkMasterVersion.Path {
+				continue
+			}
+			config, err := a.FetchServerConfig(ctx, cache, cluster.Project, cluster.Location)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			cluster.Drifts[i].Warning = masterVersionWarning(expected, config, cluster.Location)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// masterVersionWarning describes expected versions whose minor version is
+// no longer offered, or returns "" when all of them are
+func masterVersionWarning(expected []string, config *ServerConfig, location string) string {
+	var offered []string
+	valid := make(map[string]bool)
+	for _, v := range config.ValidMasterVersions {
+		minor := extractMinorVersion(v)
+		if !valid[minor] {
+			valid[minor] = true
+			offered = append(offered, minor)
+		}
+	}
+
+	var stale []string
+	for _, v := range expected {
+		if !valid[extractMinorVersion(v)] {
+			stale = append(stale, v)
+		}
+	}
+	if len(stale) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("baseline version %s is no longer offered in %s (valid: %s)",
+		strings.Join(stale, ", "), location, strings.Join(offered, ", "))
+}
@@ -0,0 +1,25 @@
+package gke
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// CheckUniqueness flags every cluster of a group that holds more clusters
+// than a unique rule allows, e.g. a cluster name reused in several projects
+func CheckUniqueness(r *DriftReport, rules []analyzer.UniquenessRule) {
+	resources := make([]analyzer.UniqueResource, len(r.Instances))
+	for i, cluster := range r.Instances {
+		resources[i] = analyzer.UniqueResource{Project: cluster.Project, Name: cluster.Name, Labels: cluster.Labels}
+	}
+
+	for _, rule := range rules {
+		for _, duplicate := range rule.Duplicates(resources) {
+			actual := duplicate.Actual(resources)
+			for _, i := range duplicate.Members {
+				cluster := r.Instances[i]
+				if len(cluster.Drifts) == 0 {
+					r.DriftedClusters++
+				}
+				cluster.Drifts = checkUnique.Append(cluster.Drifts, rule.Expected(duplicate.Group), actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package gke
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+// Pod Security Standard levels, from least to most restrictive
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// podSecurityLevels ranks the Pod Security Standard levels
+var podSecurityLevels = map[string]int{
+	PodSecurityPrivileged: 0,
+	PodSecurityBaseline:   1,
+	PodSecurityRestricted: 2,
+}
+
+// podSecurityEnforceLabel is the namespace label the Pod Security admission
+// controller enforces
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// defaultPodSecurityExempt are the namespaces GKE manages, which run
+// privileged system components
+var defaultPodSecurityExempt = []string{"kube-system", "kube-public", "kube-node-lease", "gke-*", "gmp-*"}
+
+// WorkloadBaseline is the expected in-cluster state of the baseline's
+// clusters, inspected through the Kubernetes API
+type WorkloadBaseline struct {
+	// Namespaces must exist, optionally with a Pod Security Standard
+	Namespaces []NamespaceBaseline `yaml:"namespaces,omitempty"`
+	// PodSecurity is the least restrictive Pod Security Standard any
+	// namespace may enforce, e.g. baseline
+	PodSecurity string `yaml:"pod_security,omitempty"`
+	// PodSecurityExempt are shell wildcard patterns of namespaces PodSecurity
+	// does not apply to (default: kube-system, kube-public, kube-node-lease,
+	// gke-*, gmp-*)
+	PodSecurityExempt []string `yaml:"pod_security_exempt,omitempty"`
+	// Deployments must exist and have their replicas available
+	Deployments []WorkloadRef `yaml:"deployments,omitempty"`
+	// DaemonSets must exist and run on every node they are scheduled to
+	DaemonSets []WorkloadRef `yaml:"daemonsets,omitempty"`
+}
+
+// NamespaceBaseline is a namespace the baseline requires
+type NamespaceBaseline struct {
+	Name string `yaml:"name"`
+	// PodSecurity is the Pod Security Standard the namespace must enforce
+	// at least
+	PodSecurity string `yaml:"pod_security,omitempty"`
+}
+
+// WorkloadRef names a workload the baseline requires
+type WorkloadRef struct {
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+	// MinAvailable is the fewest available replicas or pods accepted;
+	// without it every desired replica must be available
+	MinAvailable *int32 `yaml:"min_available,omitempty"`
+}
+
+// key identifies the workload as namespace/name
+func (w WorkloadRef) key() string {
+	return w.Namespace + "/" + w.Name
+}
+
+// Validate checks the Pod Security Standard levels, patterns and workload
+// references
+func (b *WorkloadBaseline) Validate() error {
+	if b == nil {
+		return nil
+	}
+	if err := validPodSecurity(b.PodSecurity); err != nil {
+		return err
+	}
+	for _, pattern := range b.PodSecurityExempt {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pod_security_exempt pattern %q: %w", pattern, err)
+		}
+	}
+	for _, ns := range b.Namespaces {
+		if ns.Name == "" {
+			return fmt.Errorf("workload namespaces need a name")
+		}
+		if err := validPodSecurity(ns.PodSecurity); err != nil {
+			return err
+		}
+	}
+	for _, refs := range [][]WorkloadRef{b.Deployments, b.DaemonSets} {
+		for _, ref := range refs {
+			if ref.Namespace == "" || ref.Name == "" {
+				return fmt.Errorf("workloads need a namespace and name")
+			}
+			if ref.MinAvailable != nil && *ref.MinAvailable < 0 {
+				return fmt.Errorf("workload %s: min_available must not be negative", ref.key())
+			}
+		}
+	}
+	return nil
+}
+
+// validPodSecurity checks a Pod Security Standard level; empty is allowed
+func validPodSecurity(level string) error {
+	if _, ok := podSecurityLevels[level]; level != "" && !ok {
+		return fmt.Errorf("unknown pod security level %q (want privileged, baseline or restricted)", level)
+	}
+	return nil
+}
+
+// exempt reports whether PodSecurity does not apply to a namespace
+func (b *WorkloadBaseline) exempt(namespace string) bool {
+	patterns := b.PodSecurityExempt
+	if patterns == nil {
+		patterns = defaultPodSecurityExempt
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkloadState is the in-cluster state of a cluster
+type WorkloadState struct {
+	// Namespaces maps each namespace to its labels
+	Namespaces map[string]map[string]string
+	// Deployments and DaemonSets are keyed by namespace/name
+	Deployments map[string]WorkloadStatus
+	DaemonSets  map[string]WorkloadStatus
+}
+
+// WorkloadStatus is the rollout status of a deployment or DaemonSet
+type WorkloadStatus struct {
+	// Desired is the replicas of a deployment, or the nodes a DaemonSet is
+	// scheduled to
+	Desired   int32
+	Available int32
+}
+
+// CompareWorkloads compares a cluster's in-cluster state against the
+// workload baseline
+func CompareWorkloads(cluster *ClusterInstance, state *WorkloadState, baseline *WorkloadBaseline) *ClusterDrift {
+	drift := &ClusterDrift{
+		Project:  cluster.Project,
+		Name:     cluster.Name,
+		Location: cluster.Location,
+		Status:   cluster.Status,
+		Labels:   cluster.Labels,
+	}
+
+	required := make(map[string]string)
+	for _, ns := range baseline.Namespaces {
+		labels, exists := state.Namespaces[ns.Name]
+		if !exists {
+			drift.Drifts = checkNamespaceMissing.At(ns.Name).Append(drift.Drifts, "present", "missing")
+			continue
+		}
+		if ns.PodSecurity != "" {
+			required[ns.Name] = ns.PodSecurity
+			comparePodSecurity(ns.Name, labels, ns.PodSecurity, drift)
+		}
+	}
+	if baseline.PodSecurity != "" {
+		names := make([]string, 0, len(state.Namespaces))
+		for name := range state.Namespaces {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, set := required[name]; set || baseline.exempt(name) {
+				continue
+			}
+			comparePodSecurity(name, state.Namespaces[name], baseline.PodSecurity, drift)
+		}
+	}
+
+	compareWorkloadRefs(baseline.Deployments, state.Deployments, checkDeploymentMissing, checkDeploymentAvailable, drift)
+	compareWorkloadRefs(baseline.DaemonSets, state.DaemonSets, checkDaemonSetMissing, checkDaemonSetAvailable, drift)
+	return drift
+}
+
+// comparePodSecurity checks that a namespace enforces at least a Pod
+// Security Standard level; a namespace without the label is privileged
+func comparePodSecurity(namespace string, labels map[string]string, minimum string, drift *ClusterDrift) {
+	actual := labels[podSecurityEnforceLabel]
+	level, known := podSecurityLevels[actual]
+	if known && level >= podSecurityLevels[minimum] {
+		return
+	}
+	if actual == "" {
+		actual = "not enforced"
+	}
+	drift.Drifts = checkNamespacePodSecurity.At(namespace).Append(drift.Drifts, "at least "+minimum, actual)
+}
+
+// compareWorkloadRefs checks that each required workload exists with enough
+// of its replicas or pods available
+func compareWorkloadRefs(refs []WorkloadRef, actual map[string]WorkloadStatus, missing, available *checks.Check, drift *ClusterDrift) {
+	for _, ref := range refs {
+		status, exists := actual[ref.key()]
+		if !exists {
+			drift.Drifts = missing.At(ref.key()).Append(drift.Drifts, "present", "missing")
+			continue
+		}
+		want := status.Desired
+		if ref.MinAvailable != nil {
+			want = *ref.MinAvailable
+		}
+		if status.Available < want {
+			drift.Drifts = available.At(ref.key()).Append(drift.Drifts,
+				"at least "+strconv.Itoa(int(want)), fmt.Sprintf("%d of %d", status.Available, status.Desired))
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package gke
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestCompareWorkloads(t *testing.T) {
+	two := int32(2)
+	baseline := &WorkloadBaseline{
+		Namespaces: []NamespaceBaseline{
+			{Name: "payments", PodSecurity: PodSecurityRestricted},
+			{Name: "search"},
+		},
+		PodSecurity: PodSecurityBaseline,
+		Deployments: []WorkloadRef{
+			{Namespace: "payments", Name: "api"},
+			{Namespace: "payments", Name: "worker", MinAvailable: &two},
+			{Namespace: "payments", Name: "missing"},
+		},
+		DaemonSets: []WorkloadRef{
+			{Namespace: "kube-system", Name: "fluentbit"},
+			{Namespace: "monitoring", Name: "node-exporter"},
+		},
+	}
+	if err := baseline.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	state := &WorkloadState{
+		Namespaces: map[string]map[string]string{
+			"payments":    {podSecurityEnforceLabel: PodSecurityBaseline},
+			"default":     {},
+			"batch":       {podSecurityEnforceLabel: PodSecurityRestricted},
+			"kube-system": {},
+			"gke-managed": {},
+		},
+		Deployments: map[string]WorkloadStatus{
+			"payments/api":    {Desired: 3, Available: 2},
+			"payments/worker": {Desired: 4, Available: 2},
+		},
+		DaemonSets: map[string]WorkloadStatus{
+			"kube-system/fluentbit": {Desired: 5, Available: 5},
+		},
+	}
+
+	drift := CompareWorkloads(&ClusterInstance{Project: "prod", Name: "prod-1"}, state, baseline)
+	got := make(map[string]string)
+	for _, d := range drift.Drifts {
+		got[d.Field] = d.Expected + " | " + d.Actual + " | " + d.Severity
+	}
+	want := map[string]string{
+		"namespace[search]":                           "present | missing | high",
+		"namespace[payments].pod_security":            "at least restricted | baseline | high",
+		"namespace[default].pod_security":             "at least baseline | not enforced | high",
+		"deployment[payments/api].available_replicas": "at least 3 | 2 of 3 | medium",
+		"deployment[payments/missing]":                "present | missing | high",
+		"daemonset[monitoring/node-exporter]":         "present | missing | high",
+	}
+	if len(got) != len(want) {
+		t.Errorf("drifts = %v, want %v", got, want)
+	}
+	for field, w := range want {
+		if got[field] != w {
+			t.Errorf("%s = %q, want %q", field, got[field], w)
+		}
+	}
+}
+
+func TestWorkloadBaselineValidate(t *testing.T) {
+	negative := int32(-1)
+	for _, b := range []*WorkloadBaseline{
+		{PodSecurity: "strict"},
+		{Namespaces: []NamespaceBaseline{{PodSecurity: PodSecurityBaseline}}},
+		{Deployments: []WorkloadRef{{Name: "api"}}},
+		{DaemonSets: []WorkloadRef{{Namespace: "ns", Name: "ds", MinAvailable: &negative}}},
+		{PodSecurityExempt: []string{"["}},
+	} {
+		if err := b.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", b)
+		}
+	}
+}
+
+func TestWorkloadState(t *testing.T) {
+	pages := map[string]string{
+		"/api/v1/namespaces": `{"items": [{"metadata": {"name": "payments", "labels": {"pod-security.kubernetes.io/enforce": "restricted"}}}]}`,
+		"/apis/apps/v1/deployments": `{"items": [
+			{"metadata": {"namespace": "payments", "name": "api"}, "spec": {"replicas": 3}, "status": {"availableReplicas": 3}},
+			{"metadata": {"namespace": "payments", "name": "cron"}, "spec": {}, "status": {}}
+		]}`,
+		"/apis/apps/v1/daemonsets": `{"items": [{"metadata": {"namespace": "kube-system", "name": "fluentbit"}, "status": {"desiredNumberScheduled": 4, "numberAvailable": 3}}]}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		// The deployments list is split over two pages
+		if r.URL.Path == "/apis/apps/v1/deployments" && r.URL.Query().Get("continue") == "" {
+			fmt.Fprint(w, `{"metadata": {"continue": "next"}, "items": []}`)
+			return
+		}
+		page, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	cluster := &ClusterInstance{
+		Name:          "prod-1",
+		Endpoint:      strings.TrimPrefix(server.URL, "https://"),
+		CACertificate: base64.StdEncoding.EncodeToString(ca),
+	}
+	inspector := &WorkloadInspector{tokens: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "test-token"})}
+
+	state, err := inspector.State(t.Context(), cluster)
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if got := state.Namespaces["payments"][podSecurityEnforceLabel]; got != PodSecurityRestricted {
+		t.Errorf("payments pod security = %q", got)
+	}
+	if got := state.Deployments["payments/api"]; got != (WorkloadStatus{Desired: 3, Available: 3}) {
+		t.Errorf("payments/api = %+v", got)
+	}
+	// Unset replicas default to 1
+	if got := state.Deployments["payments/cron"]; got != (WorkloadStatus{Desired: 1}) {
+		t.Errorf("payments/cron = %+v", got)
+	}
+	if got := state.DaemonSets["kube-system/fluentbit"]; got != (WorkloadStatus{Desired: 4, Available: 3}) {
+		t.Errorf("kube-system/fluentbit = %+v", got)
+	}
+
+	// Without the cluster's CA the server is not trusted
+	cluster.CACertificate = ""
+	if _, err := inspector.State(t.Context(), cluster); err == nil {
+		t.Error("State() without CA should fail")
+	}
+}
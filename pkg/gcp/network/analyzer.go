@@ -0,0 +1,561 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	compute "google.golang.org/api/compute/v1"
+)
+
+// VPCNetwork represents a VPC network with its subnets and firewall rules
+type VPCNetwork struct {
+	Project               string
+	Name                  string
+	RoutingMode           string
+	AutoCreateSubnetworks bool
+	Subnets               []*Subnet
+	FirewallRules         []*FirewallRule
+}
+
+// Subnet holds the configuration of a single subnetwork
+type Subnet struct {
+	Name                string  `yaml:"name" json:"name"`
+	Region              string  `yaml:"region" json:"region"`
+	IPCIDRRange         string  `yaml:"ip_cidr_range" json:"ip_cidr_range"`
+	PrivateGoogleAccess bool    `yaml:"private_google_access" json:"private_google_access"`
+	FlowLogsEnabled     bool    `yaml:"flow_logs_enabled" json:"flow_logs_enabled"`
+	FlowLogsSampling    float64 `yaml:"flow_logs_sampling,omitempty" json:"flow_logs_sampling,omitempty"`
+	FlowLogsAggregation string  `yaml:"flow_logs_aggregation,omitempty" json:"flow_logs_aggregation,omitempty"`
+}
+
+// FirewallRule holds the configuration of a single firewall rule
+type FirewallRule struct {
+	Name              string   `yaml:"name" json:"name"`
+	Direction         string   `yaml:"direction" json:"direction"`
+	Action            string   `yaml:"action" json:"action"`
+	Priority          int64    `yaml:"priority" json:"priority"`
+	SourceRanges      []string `yaml:"source_ranges,omitempty" json:"source_ranges,omitempty"`
+	DestinationRanges []string `yaml:"destination_ranges,omitempty" json:"destination_ranges,omitempty"`
+	Ports             []string `yaml:"ports,omitempty" json:"ports,omitempty"`
+	TargetTags        []string `yaml:"target_tags,omitempty" json:"target_tags,omitempty"`
+	Disabled          bool     `yaml:"disabled" json:"disabled"`
+	LogEnabled        bool     `yaml:"log_enabled" json:"log_enabled"`
+}
+
+// NetworkConfig holds the baseline expectations for a VPC network
+type NetworkConfig struct {
+	RoutingMode           string `yaml:"routing_mode,omitempty" json:"routing_mode,omitempty"`
+	AutoCreateSubnetworks *bool  `yaml:"auto_create_subnetworks,omitempty" json:"auto_create_subnetworks,omitempty"`
+
+	// Firewall expectations
+	AllowedIngressCIDRs []string       `yaml:"allowed_ingress_cidrs,omitempty" json:"allowed_ingress_cidrs,omitempty"`
+	RequiredDenyRules   []RequiredRule `yaml:"required_deny_rules,omitempty" json:"required_deny_rules,omitempty"`
+	FirewallLogging     *bool          `yaml:"firewall_logging,omitempty" json:"firewall_logging,omitempty"`
+
+	// Subnet expectations
+	PrivateGoogleAccess *bool           `yaml:"private_google_access,omitempty" json:"private_google_access,omitempty"`
+	FlowLogs            *FlowLogsConfig `yaml:"flow_logs,omitempty" json:"flow_logs,omitempty"`
+}
+
+// RequiredRule describes a deny rule that must exist. When Name is set the rule
+// is matched by name; otherwise any enabled deny rule with the same direction
+// covering the listed source ranges and ports satisfies it.
+type RequiredRule struct {
+	Name         string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Direction    string   `yaml:"direction,omitempty" json:"direction,omitempty"`
+	SourceRanges []string `yaml:"source_ranges,omitempty" json:"source_ranges,omitempty"`
+	Ports        []string `yaml:"ports,omitempty" json:"ports,omitempty"`
+}
+
+// FlowLogsConfig defines VPC flow log expectations applied to every subnet
+type FlowLogsConfig struct {
+	Enabled     bool    `yaml:"enabled" json:"enabled"`
+	MinSampling float64 `yaml:"min_sampling,omitempty" json:"min_sampling,omitempty"`
+	Aggregation string  `yaml:"aggregation_interval,omitempty" json:"aggregation_interval,omitempty"`
+}
+
+// VPCBaseline defines a named baseline applied to a set of networks
+type VPCBaseline struct {
+	Name     string         `yaml:"name"`
+	Networks []string       `yaml:"networks,omitempty"`
+	Config   *NetworkConfig `yaml:"config"`
+	// Metadata records when the baseline was last reviewed
+	Metadata analyzer.BaselineMetadata `yaml:"metadata,omitempty"`
+}
+
+// Analyzer performs drift analysis on VPC networks
+type Analyzer struct {
+	analyzer.ProjectErrors
+
+	service    *compute.Service
+	lastReport *DriftReport
+	projects   []string
+}
+
+// NewAnalyzer creates a new network Analyzer instance
+func NewAnalyzer(ctx context.Context) (*Analyzer, error) {
+	service, err := compute.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Compute client: %w", err)
+	}
+
+	return &Analyzer{service: service}, nil
+}
+
+// Close releases resources held by the Analyzer
+func (a *Analyzer) Close() error {
+	return nil
+}
+
+// Compile-time interface implementation check
+var _ analyzer.ResourceAnalyzer = (*Analyzer)(nil)
+
+// Analyze performs drift analysis implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) Analyze(ctx context.Context, projects []string) error {
+	a.projects = projects
+	return nil
+}
+
+// GenerateReport generates a formatted report implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GenerateReport() (string, error) {
+	if a.lastReport == nil {
+		return "", fmt.Errorf("no analysis has been performed yet")
+	}
+	return a.lastReport.FormatText(), nil
+}
+
+// GetDriftCount returns the number of drifts detected implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GetDriftCount() int {
+	if a.lastReport == nil {
+		return 0
+	}
+	return a.lastReport.DriftedNetworks
+}
+
+// DiscoverNetworks finds all VPC networks, subnets and firewall rules across the specified projects
+func (a *Analyzer) DiscoverNetworks(ctx context.Context, projects []string) ([]*VPCNetwork, error) {
+	var networks []*VPCNetwork
+
+	for _, project := range projects {
+		projectNetworks, err := a.discoverProjectNetworks(ctx, project)
+		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to discover networks in project %s: %w", project, err)
+		}
+		networks = append(networks, projectNetworks...)
+	}
+
+	return networks, nil
+}
+
+// discoverProjectNetworks lists networks in a single project and attaches their subnets and firewall rules
+func (a *Analyzer) discoverProjectNetworks(ctx context.Context, project string) ([]*VPCNetwork, error) {
+	byName := make(map[string]*VPCNetwork)
+	var networks []*VPCNetwork
+
+	err := a.service.Networks.List(project).Pages(ctx, func(resp *compute.NetworkList) error {
+		for _, n := range resp.Items {
+			network := &VPCNetwork{
+				Project:               project,
+				Name:                  n.Name,
+				AutoCreateSubnetworks: n.AutoCreateSubnetworks,
+			}
+			if n.RoutingConfig != nil {
+				network.RoutingMode = n.RoutingConfig.RoutingMode
+			}
+			byName[n.Name] = network
+			networks = append(networks, network)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = a.service.Subnetworks.AggregatedList(project).Pages(ctx, func(resp *compute.SubnetworkAggregatedList) error {
+		for _, scoped := range resp.Items {
+			for _, s := range scoped.Subnetworks {
+				if network, ok := byName[lastSegment(s.Network)]; ok {
+					network.Subnets = append(network.Subnets, extractSubnet(s))
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subnetworks: %w", err)
+	}
+
+	err = a.service.Firewalls.List(project).Pages(ctx, func(resp *compute.FirewallList) error {
+		for _, fw := range resp.Items {
+			if network, ok := byName[lastSegment(fw.Network)]; ok {
+				network.FirewallRules = append(network.FirewallRules, extractFirewallRule(fw))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list firewall rules: %w", err)
+	}
+
+	return networks, nil
+}
+
+// extractSubnet extracts subnet configuration from the Compute API representation
+func extractSubnet(s *compute.Subnetwork) *Subnet {
+	subnet := &Subnet{
+		Name:                s.Name,
+		Region:              lastSegment(s.Region),
+		IPCIDRRange:         s.IpCidrRange,
+		PrivateGoogleAccess: s.PrivateIpGoogleAccess,
+		FlowLogsEnabled:     s.EnableFlowLogs,
+	}
+
+	if s.LogConfig != nil {
+		subnet.FlowLogsEnabled = s.LogConfig.Enable
+		subnet.FlowLogsSampling = s.LogConfig.FlowSampling
+		subnet.FlowLogsAggregation = s.LogConfig.AggregationInterval
+	}
+
+	return subnet
+}
+
+// extractFirewallRule extracts firewall rule configuration from the Compute API representation
+func extractFirewallRule(fw *compute.Firewall) *FirewallRule {
+	rule := &FirewallRule{
+		Name:              fw.Name,
+		Direction:         fw.Direction,
+		Action:            "allow",
+		Priority:          fw.Priority,
+		SourceRanges:      fw.SourceRanges,
+		DestinationRanges: fw.DestinationRanges,
+		TargetTags:        fw.TargetTags,
+		Disabled:          fw.Disabled,
+		LogEnabled:        fw.LogConfig != nil && fw.LogConfig.Enable,
+	}
+
+	for _, allowed := range fw.Allowed {
+		rule.Ports = append(rule.Ports, protocolPorts(allowed.IPProtocol, allowed.Ports)...)
+	}
+
+	if len(fw.Denied) > 0 {
+		rule.Action = "deny"
+		for _, denied := range fw.Denied {
+			rule.Ports = append(rule.Ports, protocolPorts(denied.IPProtocol, denied.Ports)...)
+		}
+	}
+
+	return rule
+}
+
+// protocolPorts renders protocol/port pairs as "tcp:22", or just the protocol when no ports are set
+func protocolPorts(protocol string, ports []string) []string {
+	if len(ports) == 0 {
+		return []string{protocol}
+	}
+	result := make([]string, 0, len(ports))
+	for _, port := range ports {
+		result = append(result, fmt.Sprintf("%s:%s", protocol, port))
+	}
+	return result
+}
+
+// lastSegment returns the final path segment of a Compute API resource URL
+func lastSegment(url string) string {
+	if i := strings.LastIndex(url, "/"); i >= 0 {
+		return url[i+1:]
+	}
+	return url
+}
+
+// FilterNetworks returns networks whose names are in the given list; an empty list matches all
+func FilterNetworks(networks []*VPCNetwork, names []string) []*VPCNetwork {
+	if len(names) == 0 {
+		return networks
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	filtered := make([]*VPCNetwork, 0)
+	for _, n := range networks {
+		if wanted[n.Name] {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
+
+// AnalyzeDrift compares discovered networks against a baseline and generates a drift report
+func (a *Analyzer) AnalyzeDrift(networks []*VPCNetwork, baseline *NetworkConfig) *DriftReport {
+	report := &DriftReport{
+		Timestamp:     time.Now(),
+		TotalNetworks: len(networks),
+		Instances:     make([]*NetworkDrift, 0),
+		Errors:        a.ScanErrors(),
+	}
+
+	for _, network := range networks {
+		drift := a.analyzeNetwork(network, baseline)
+		report.Instances = append(report.Instances, drift)
+
+		if len(drift.Drifts) > 0 {
+			report.DriftedNetworks++
+		}
+	}
+
+	a.lastReport = report
+	return report
+}
+
+// analyzeNetwork compares a single network against the baseline configuration
+func (a *Analyzer) analyzeNetwork(network *VPCNetwork, baseline *NetworkConfig) *NetworkDrift {
+	drift := &NetworkDrift{
+		Project:       network.Project,
+		Name:          network.Name,
+		RoutingMode:   network.RoutingMode,
+		Subnets:       len(network.Subnets),
+		FirewallRules: len(network.FirewallRules),
+		Drifts:        make([]Drift, 0),
+	}
+
+	if baseline == nil {
+		return drift
+	}
+
+	if baseline.RoutingMode != "" && network.RoutingMode != baseline.RoutingMode {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "routing_mode",
+			Expected: baseline.RoutingMode,
+			Actual:   network.RoutingMode,
+			Severity: "medium",
+		})
+	}
+
+	if baseline.AutoCreateSubnetworks != nil && network.AutoCreateSubnetworks != *baseline.AutoCreateSubnetworks {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "auto_create_subnetworks",
+			Expected: fmt.Sprintf("%v", *baseline.AutoCreateSubnetworks),
+			Actual:   fmt.Sprintf("%v", network.AutoCreateSubnetworks),
+			Severity: "medium",
+		})
+	}
+
+	a.compareIngressRules(network.FirewallRules, baseline, drift)
+	a.compareRequiredDenyRules(network.FirewallRules, baseline, drift)
+	a.compareFirewallLogging(network.FirewallRules, baseline, drift)
+	a.compareSubnets(network.Subnets, baseline, drift)
+
+	return drift
+}
+
+// compareIngressRules flags enabled ingress allow rules whose source ranges fall outside the allowed CIDRs
+func (a *Analyzer) compareIngressRules(rules []*FirewallRule, baseline *NetworkConfig, drift *NetworkDrift) {
+	if len(baseline.AllowedIngressCIDRs) == 0 {
+		return
+	}
+
+	for _, rule := range rules {
+		if rule.Disabled || rule.Direction != "INGRESS" || rule.Action != "allow" {
+			continue
+		}
+
+		var disallowed []string
+		for _, source := range rule.SourceRanges {
+			if !cidrAllowed(source, baseline.AllowedIngressCIDRs) {
+				disallowed = append(disallowed, source)
+			}
+		}
+		if len(disallowed) == 0 {
+			continue
+		}
+
+		severity := "high"
+		for _, source := range disallowed {
+			if source == "0.0.0.0/0" || source == "::/0" {
+				severity = "critical"
+			}
+		}
+
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    fmt.Sprintf("firewall[%s].source_ranges", rule.Name),
+			Expected: fmt.Sprintf("within %v", baseline.AllowedIngressCIDRs),
+			Actual:   fmt.Sprintf("%v (ports: %v)", disallowed, rule.Ports),
+			Severity: severity,
+		})
+	}
+}
+
+// compareRequiredDenyRules validates that every required deny rule is present and enabled
+func (a *Analyzer) compareRequiredDenyRules(rules []*FirewallRule, baseline *NetworkConfig, drift *NetworkDrift) {
+	for _, required := range baseline.RequiredDenyRules {
+		if matchesRequiredRule(rules, required) {
+			continue
+		}
+
+		name := required.Name
+		if name == "" {
+			name = fmt.Sprintf("%s %v %v", required.direction(), required.SourceRanges, required.Ports)
+		}
+
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    fmt.Sprintf("required_deny_rules[%s]", name),
+			Expected: "present and enabled",
+			Actual:   "missing",
+			Severity: "high",
+		})
+	}
+}
+
+// compareFirewallLogging checks that firewall rule logging matches the baseline
+func (a *Analyzer) compareFirewallLogging(rules []*FirewallRule, baseline *NetworkConfig, drift *NetworkDrift) {
+	if baseline.FirewallLogging == nil {
+		return
+	}
+
+	for _, rule := range rules {
+		if rule.Disabled || rule.LogEnabled == *baseline.FirewallLogging {
+			continue
+		}
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    fmt.Sprintf("firewall[%s].log_enabled", rule.Name),
+			Expected: fmt.Sprintf("%v", *baseline.FirewallLogging),
+			Actual:   fmt.Sprintf("%v", rule.LogEnabled),
+			Severity: "low",
+		})
+	}
+}
+
+// compareSubnets checks flow log and Private Google Access settings on every subnet
+func (a *Analyzer) compareSubnets(subnets []*Subnet, baseline *NetworkConfig, drift *NetworkDrift) {
+	for _, subnet := range subnets {
+		prefix := fmt.Sprintf("subnet[%s]", subnet.Name)
+
+		if baseline.PrivateGoogleAccess != nil && subnet.PrivateGoogleAccess != *baseline.PrivateGoogleAccess {
+			drift.Drifts = append(drift.Drifts, Drift{
+				Field:    prefix + ".private_google_access",
+				Expected: fmt.Sprintf("%v", *baseline.PrivateGoogleAccess),
+				Actual:   fmt.Sprintf("%v", subnet.PrivateGoogleAccess),
+				Severity: "medium",
+			})
+		}
+
+		if baseline.FlowLogs == nil {
+			continue
+		}
+
+		if subnet.FlowLogsEnabled != baseline.FlowLogs.Enabled {
+			drift.Drifts = append(drift.Drifts, Drift{
+				Field:    prefix + ".flow_logs.enabled",
+				Expected: fmt.Sprintf("%v", baseline.FlowLogs.Enabled),
+				Actual:   fmt.Sprintf("%v", subnet.FlowLogsEnabled),
+				Severity: "medium",
+			})
+			continue
+		}
+
+		if !subnet.FlowLogsEnabled {
+			continue
+		}
+
+		if baseline.FlowLogs.MinSampling > 0 && subnet.FlowLogsSampling < baseline.FlowLogs.MinSampling {
+			drift.Drifts = append(drift.Drifts, Drift{
+				Field:    prefix + ".flow_logs.sampling",
+				Expected: fmt.Sprintf(">= %g", baseline.FlowLogs.MinSampling),
+				Actual:   fmt.Sprintf("%g", subnet.FlowLogsSampling),
+				Severity: "low",
+			})
+		}
+
+		if baseline.FlowLogs.Aggregation != "" && subnet.FlowLogsAggregation != baseline.FlowLogs.Aggregation {
+			drift.Drifts = append(drift.Drifts, Drift{
+				Field:    prefix + ".flow_logs.aggregation_interval",
+				Expected: baseline.FlowLogs.Aggregation,
+				Actual:   subnet.FlowLogsAggregation,
+				Severity: "low",
+			})
+		}
+	}
+}
+
+// direction returns the rule direction, defaulting to INGRESS like the Compute API
+func (r RequiredRule) direction() string {
+	if r.Direction == "" {
+		return "INGRESS"
+	}
+	return r.Direction
+}
+
+// matchesRequiredRule reports whether any enabled deny rule satisfies the requirement
+func matchesRequiredRule(rules []*FirewallRule, required RequiredRule) bool {
+	for _, rule := range rules {
+		if rule.Disabled || rule.Action != "deny" {
+			continue
+		}
+		if required.Name != "" {
+			if rule.Name == required.Name {
+				return true
+			}
+			continue
+		}
+		if rule.Direction != required.direction() {
+			continue
+		}
+		if containsAll(rule.SourceRanges, required.SourceRanges) && containsAll(rule.Ports, required.Ports) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll reports whether every wanted value is present in values
+func containsAll(values, wanted []string) bool {
+	set := make(map[string]bool)
+	for _, v := range values {
+		set[v] = true
+	}
+	for _, w := range wanted {
+		if !set[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// cidrAllowed reports whether source is contained in any of the allowed CIDRs
+func cidrAllowed(source string, allowed []string) bool {
+	_, sourceNet, err := net.ParseCIDR(source)
+	if err != nil {
+		ip := net.ParseIP(source)
+		if ip == nil {
+			return false
+		}
+		bits := 32
+		if ip.To4() == nil {
+			bits = 128
+		}
+		sourceNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	}
+	sourceOnes, _ := sourceNet.Mask.Size()
+
+	for _, cidr := range allowed {
+		_, allowedNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		allowedOnes, _ := allowedNet.Mask.Size()
+		if allowedNet.Contains(sourceNet.IP) && allowedOnes <= sourceOnes {
+			return true
+		}
+	}
+	return false
+}
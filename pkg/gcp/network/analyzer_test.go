@@ -0,0 +1,104 @@
+package network
+
+import (
+	"testing"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestCIDRAllowed(t *testing.T) {
+	allowed := []string{"10.0.0.0/8", "35.235.240.0/20"}
+
+	tests := []struct {
+		source string
+		want   bool
+	}{
+		{"10.1.0.0/16", true},
+		{"10.0.0.0/8", true},
+		{"35.235.241.0/24", true},
+		{"10.0.0.5", true},
+		{"0.0.0.0/0", false},
+		{"192.168.0.0/16", false},
+		{"not-a-cidr", false},
+	}
+
+	for _, tt := range tests {
+		if got := cidrAllowed(tt.source, allowed); got != tt.want {
+			t.Errorf("cidrAllowed(%q) = %v, want %v", tt.source, got, tt.want)
+		}
+	}
+}
+
+func TestFilterNetworks(t *testing.T) {
+	networks := []*VPCNetwork{{Name: "default"}, {Name: "prod-vpc"}}
+
+	if got := FilterNetworks(networks, nil); len(got) != 2 {
+		t.Errorf("FilterNetworks(nil) returned %d networks, want 2", len(got))
+	}
+	if got := FilterNetworks(networks, []string{"prod-vpc"}); len(got) != 1 || got[0].Name != "prod-vpc" {
+		t.Errorf("FilterNetworks([prod-vpc]) = %v, want [prod-vpc]", got)
+	}
+}
+
+func TestAnalyzeDrift(t *testing.T) {
+	a := &Analyzer{}
+
+	network := &VPCNetwork{
+		Project:     "test-project",
+		Name:        "prod-vpc",
+		RoutingMode: "REGIONAL",
+		Subnets: []*Subnet{
+			{Name: "app", FlowLogsEnabled: true, FlowLogsSampling: 0.1, PrivateGoogleAccess: true},
+			{Name: "db", FlowLogsEnabled: false, PrivateGoogleAccess: false},
+		},
+		FirewallRules: []*FirewallRule{
+			{Name: "allow-ssh-anywhere", Direction: "INGRESS", Action: "allow", SourceRanges: []string{"0.0.0.0/0"}, Ports: []string{"tcp:22"}},
+			{Name: "allow-internal", Direction: "INGRESS", Action: "allow", SourceRanges: []string{"10.0.0.0/8"}, LogEnabled: true},
+			{Name: "allow-partner", Direction: "INGRESS", Action: "allow", SourceRanges: []string{"203.0.113.0/24"}, LogEnabled: true, Disabled: true},
+			{Name: "deny-rdp", Direction: "INGRESS", Action: "deny", SourceRanges: []string{"0.0.0.0/0"}, Ports: []string{"tcp:3389"}, LogEnabled: true},
+		},
+	}
+
+	baseline := &NetworkConfig{
+		RoutingMode:         "GLOBAL",
+		AllowedIngressCIDRs: []string{"10.0.0.0/8"},
+		RequiredDenyRules: []RequiredRule{
+			{SourceRanges: []string{"0.0.0.0/0"}, Ports: []string{"tcp:3389"}},
+			{Name: "deny-telnet"},
+		},
+		FirewallLogging:     boolPtr(true),
+		PrivateGoogleAccess: boolPtr(true),
+		FlowLogs:            &FlowLogsConfig{Enabled: true, MinSampling: 0.5},
+	}
+
+	report := a.AnalyzeDrift([]*VPCNetwork{network}, baseline)
+	if report.TotalNetworks != 1 || report.DriftedNetworks != 1 {
+		t.Fatalf("TotalNetworks = %d, DriftedNetworks = %d, want 1 and 1", report.TotalNetworks, report.DriftedNetworks)
+	}
+
+	got := make(map[string]string)
+	for _, d := range report.Instances[0].Drifts {
+		got[d.Field] = d.Severity
+	}
+
+	want := map[string]string{
+		"routing_mode": "medium",
+		"firewall[allow-ssh-anywhere].source_ranges": "critical",
+		"required_deny_rules[deny-telnet]":           "high",
+		"firewall[allow-ssh-anywhere].log_enabled":   "low",
+		"subnet[app].flow_logs.sampling":             "low",
+		"subnet[db].flow_logs.enabled":               "medium",
+		"subnet[db].private_google_access":           "medium",
+	}
+
+	if len(got) != len(want) {
+		t.Errorf("got %d drifts, want %d: %v", len(got), len(want), got)
+	}
+	for field, severity := range want {
+		if got[field] != severity {
+			t.Errorf("drift %s severity = %q, want %q", field, got[field], severity)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// DriftReport contains the complete analysis results for all networks
+type DriftReport struct {
+	Timestamp       time.Time       `json:"timestamp" yaml:"timestamp"`
+	TotalNetworks   int             `json:"total_networks" yaml:"total_networks"`
+	DriftedNetworks int             `json:"drifted_networks" yaml:"drifted_networks"`
+	Instances       []*NetworkDrift `json:"instances" yaml:"instances"`
+	// Warnings flag a baseline that is due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []report.ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []report.Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []report.SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
+}
+
+// NetworkDrift represents drift analysis results for a single VPC network
+type NetworkDrift struct {
+	Project       string  `json:"project" yaml:"project"`
+	Name          string  `json:"name" yaml:"name"`
+	RoutingMode   string  `json:"routing_mode,omitempty" yaml:"routing_mode,omitempty"`
+	Subnets       int     `json:"subnets" yaml:"subnets"`
+	FirewallRules int     `json:"firewall_rules" yaml:"firewall_rules"`
+	Drifts        []Drift `json:"drifts" yaml:"drifts"`
+}
+
+// Drift represents a single configuration difference from the baseline
+type Drift = report.Drift
+
+// FormatText generates a human-readable text report
+func (r *DriftReport) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+	sb.WriteString("  GCP VPC Network Drift Analysis Report\n")
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("Generated: %s\n", r.Timestamp.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Total Networks: %d\n", r.TotalNetworks))
+	sb.WriteString(fmt.Sprintf("Networks with Drift: %d\n", r.DriftedNetworks))
+
+	if r.TotalNetworks > 0 {
+		sb.WriteString(fmt.Sprintf("Compliance Rate: %.1f%%\n\n",
+			float64(r.TotalNetworks-r.DriftedNetworks)/float64(r.TotalNetworks)*100))
+	}
+
+	// Summary by severity
+	var all []Drift
+	for _, network := range r.Instances {
+		all = append(all, network.Drifts...)
+	}
+	sb.WriteString(report.FormatDriftSummary(report.CountBySeverity(all)))
+	sb.WriteString(report.FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(report.FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(report.FormatSLO(r.SLO))
+
+	// Detailed network reports
+	for i, network := range r.Instances {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(network.FormatText())
+	}
+	sb.WriteString(report.FormatScanErrors(r.Errors))
+
+	return sb.String()
+}
+
+// FormatText generates a formatted text representation of network drift details
+func (nd *NetworkDrift) FormatText() string {
+	var sb strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("78")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	divider := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("───────────────────────────────────────────────────────────────────────────────")
+
+	sb.WriteString(divider + "\n")
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("VPC Network: %s", nd.Name)) + "\n\n")
+	sb.WriteString(labelStyle.Render("Project:        ") + valueStyle.Render(nd.Project) + "\n")
+	if nd.RoutingMode != "" {
+		sb.WriteString(labelStyle.Render("Routing Mode:   ") + valueStyle.Render(nd.RoutingMode) + "\n")
+	}
+	sb.WriteString(labelStyle.Render("Subnets:        ") + valueStyle.Render(fmt.Sprintf("%d", nd.Subnets)) + "\n")
+	sb.WriteString(labelStyle.Render("Firewall Rules: ") + valueStyle.Render(fmt.Sprintf("%d", nd.FirewallRules)) + "\n")
+
+	sb.WriteString("\n")
+	sb.WriteString(report.FormatDrifts(nd.Drifts))
+
+	return sb.String()
+}
+
+// FormatJSON generates JSON output of the drift report
+func (r *DriftReport) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatYAML generates YAML output of the drift report
+func (r *DriftReport) FormatYAML() (string, error) {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToReport converts the network drift report into the generic report model
+func (r *DriftReport) ToReport() *report.Report {
+	resources := make([]report.Resource, 0, len(r.Instances))
+	for _, network := range r.Instances {
+		resources = append(resources, report.Resource{
+			Type:     "VPC Network",
+			Project:  network.Project,
+			Name:     network.Name,
+			Location: "global",
+			Drifts:   network.Drifts,
+		})
+	}
+
+	return &report.Report{
+		Title:            "GCP VPC Network Drift Analysis Report",
+		Timestamp:        r.Timestamp,
+		Resources:        resources,
+		Warnings:         r.Warnings,
+		Errors:           r.Errors,
+		Acknowledgements: r.Acknowledgements,
+		SLO:              r.SLO,
+	}
+}
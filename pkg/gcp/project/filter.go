@@ -0,0 +1,53 @@
+package project
+
+import (
+	"fmt"
+	"path"
+)
+
+// Filter selects discovered projects by ID before any of them are scanned.
+// Patterns use shell wildcards, e.g. "*-sandbox" or "test-*".
+type Filter struct {
+	// Include keeps only matching projects (default: every project)
+	Include []string `yaml:"include,omitempty"`
+	// Exclude drops matching projects, even when they are included
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// Validate checks that every pattern is well formed
+func (f Filter) Validate() error {
+	for _, pattern := range append(append([]string{}, f.Include...), f.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid project pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether a project ID passes the filter
+func (f Filter) Allows(id string) bool {
+	if matchesAny(f.Exclude, id) {
+		return false
+	}
+	return len(f.Include) == 0 || matchesAny(f.Include, id)
+}
+
+// Apply returns the project IDs that pass the filter, in their original order
+func (f Filter) Apply(ids []string) []string {
+	kept := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if f.Allows(id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+func matchesAny(patterns []string, id string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, id); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"google.golang.org/api/cloudresourcemanager/v1"
+)
+
+// Source describes where an inferred project came from
+type Source string
+
+const (
+	SourceEnvironment Source = "environment"
+	SourceGcloud      Source = "gcloud config"
+	SourceADC         Source = "application default credentials"
+)
+
+// gcloudProject reads the active project from the gcloud CLI configuration
+var gcloudProject = func(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "gcloud", "config", "get-value", "project").Output()
+	if err != nil {
+		return "", err
+	}
+	return parseGcloudValue(string(out)), nil
+}
+
+// adcProject reads the quota/default project attached to the credentials
+var adcProject = credentials.ProjectID
+
+// DefaultProject infers the project to analyze when none are configured.
+// Environment variables take precedence, then the active gcloud configuration,
+// then the project attached to application default credentials.
+func DefaultProject(ctx context.Context) (string, Source, error) {
+	for _, env := range []string{"GOOGLE_CLOUD_PROJECT", "CLOUDSDK_CORE_PROJECT"} {
+		if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+			return value, SourceEnvironment, nil
+		}
+	}
+
+	if value, err := gcloudProject(ctx); err == nil && value != "" {
+		return value, SourceGcloud, nil
+	}
+
+	value, err := adcProject(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("no project configured and none could be inferred from gcloud or ADC: %w", err)
+	}
+	if value == "" {
+		return "", "", fmt.Errorf("no project configured and none could be inferred from gcloud or ADC")
+	}
+
+	return value, SourceADC, nil
+}
+
+// ListAccessible returns the IDs of all active projects the caller can see
+func ListAccessible(ctx context.Context) ([]string, error) {
+	service, err := cloudresourcemanager.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Resource Manager client: %w", err)
+	}
+
+	var projects []string
+	err = service.Projects.List().Filter("lifecycleState:ACTIVE").Pages(ctx, func(resp *cloudresourcemanager.ListProjectsResponse) error {
+		for _, p := range resp.Projects {
+			projects = append(projects, p.ProjectId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accessible projects: %w", err)
+	}
+
+	sort.Strings(projects)
+	return projects, nil
+}
+
+// parseGcloudValue normalizes `gcloud config get-value` output, treating "(unset)" as empty
+func parseGcloudValue(output string) string {
+	value := strings.TrimSpace(output)
+	if value == "(unset)" {
+		return ""
+	}
+	return value
+}
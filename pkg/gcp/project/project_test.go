@@ -0,0 +1,92 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseGcloudValue(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"my-project\n", "my-project"},
+		{"(unset)\n", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseGcloudValue(tt.input); got != tt.want {
+			t.Errorf("parseGcloudValue(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultProject(t *testing.T) {
+	origGcloud, origADC := gcloudProject, adcProject
+	defer func() { gcloudProject, adcProject = origGcloud, origADC }()
+
+	tests := []struct {
+		name       string
+		env        string
+		gcloud     string
+		adc        string
+		adcErr     error
+		wantID     string
+		wantSource Source
+		wantErr    bool
+	}{
+		{name: "environment wins", env: "env-project", gcloud: "gcloud-project", adc: "adc-project", wantID: "env-project", wantSource: SourceEnvironment},
+		{name: "gcloud before ADC", gcloud: "gcloud-project", adc: "adc-project", wantID: "gcloud-project", wantSource: SourceGcloud},
+		{name: "ADC fallback", adc: "adc-project", wantID: "adc-project", wantSource: SourceADC},
+		{name: "nothing found", adcErr: errors.New("no credentials"), wantErr: true},
+		{name: "ADC without project", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOOGLE_CLOUD_PROJECT", tt.env)
+			t.Setenv("CLOUDSDK_CORE_PROJECT", "")
+			gcloudProject = func(context.Context) (string, error) { return tt.gcloud, nil }
+			adcProject = func(context.Context) (string, error) { return tt.adc, tt.adcErr }
+
+			id, source, err := DefaultProject(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DefaultProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.wantID || source != tt.wantSource {
+				t.Errorf("DefaultProject() = (%q, %q), want (%q, %q)", id, source, tt.wantID, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	ids := []string{"prod-api", "prod-sandbox", "test-api", "staging-api"}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"no patterns", Filter{}, ids},
+		{"exclude", Filter{Exclude: []string{"*-sandbox", "test-*"}}, []string{"prod-api", "staging-api"}},
+		{"include", Filter{Include: []string{"prod-*"}}, []string{"prod-api", "prod-sandbox"}},
+		{"exclude wins", Filter{Include: []string{"prod-*"}, Exclude: []string{"*-sandbox"}}, []string{"prod-api"}},
+		{"single character", Filter{Include: []string{"????-api"}}, []string{"prod-api", "test-api"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.filter.Apply(ids)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Apply() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := (Filter{Exclude: []string{"[bad"}}).Validate(); err == nil {
+		t.Error("Validate() accepted a malformed pattern")
+	}
+}
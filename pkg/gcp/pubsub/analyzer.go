@@ -0,0 +1,414 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	pubsubapi "google.golang.org/api/pubsub/v1"
+)
+
+// Topic represents a Pub/Sub topic with its configuration
+type Topic struct {
+	Project string
+	Name    string
+	State   string
+	Labels  map[string]string
+	Config  *TopicConfig
+}
+
+// Subscription represents a Pub/Sub subscription with its configuration
+type Subscription struct {
+	Project string
+	Name    string
+	Topic   string
+	State   string
+	Labels  map[string]string
+	Config  *SubscriptionConfig
+}
+
+// TopicConfig holds the configuration parameters of a topic
+type TopicConfig struct {
+	MessageRetentionDuration string `yaml:"message_retention_duration,omitempty" json:"message_retention_duration,omitempty"`
+	RequireCMEK              *bool  `yaml:"require_cmek,omitempty" json:"require_cmek,omitempty"`
+	KMSKeyName               string `yaml:"kms_key_name,omitempty" json:"kms_key_name,omitempty"`
+	Schema                   string `yaml:"schema,omitempty" json:"schema,omitempty"`
+	SchemaEncoding           string `yaml:"schema_encoding,omitempty" json:"schema_encoding,omitempty"`
+}
+
+// SubscriptionConfig holds the configuration parameters of a subscription
+type SubscriptionConfig struct {
+	AckDeadlineSeconds       int64  `yaml:"ack_deadline_seconds,omitempty" json:"ack_deadline_seconds,omitempty"`
+	MessageRetentionDuration string `yaml:"message_retention_duration,omitempty" json:"message_retention_duration,omitempty"`
+	RequireDeadLetter        *bool  `yaml:"require_dead_letter,omitempty" json:"require_dead_letter,omitempty"`
+	DeadLetterTopic          string `yaml:"dead_letter_topic,omitempty" json:"dead_letter_topic,omitempty"`
+	MaxDeliveryAttempts      int64  `yaml:"max_delivery_attempts,omitempty" json:"max_delivery_attempts,omitempty"`
+	ExactlyOnceDelivery      *bool  `yaml:"exactly_once_delivery,omitempty" json:"exactly_once_delivery,omitempty"`
+	MessageOrdering          *bool  `yaml:"message_ordering,omitempty" json:"message_ordering,omitempty"`
+}
+
+// Analyzer performs drift analysis on Pub/Sub topics and subscriptions
+type Analyzer struct {
+	analyzer.ProjectErrors
+
+	service    *pubsubapi.Service
+	lastReport *DriftReport
+	projects   []string
+}
+
+// NewAnalyzer creates a new Pub/Sub Analyzer instance
+func NewAnalyzer(ctx context.Context) (*Analyzer, error) {
+	service, err := pubsubapi.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+
+	return &Analyzer{service: service}, nil
+}
+
+// Close releases resources held by the Analyzer
+func (a *Analyzer) Close() error {
+	return nil
+}
+
+// Compile-time interface implementation check
+var _ analyzer.ResourceAnalyzer = (*Analyzer)(nil)
+
+// Analyze performs drift analysis implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) Analyze(ctx context.Context, projects []string) error {
+	a.projects = projects
+	return nil
+}
+
+// GenerateReport generates a formatted report implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GenerateReport() (string, error) {
+	if a.lastReport == nil {
+		return "", fmt.Errorf("no analysis has been performed yet")
+	}
+	return a.lastReport.FormatText(), nil
+}
+
+// GetDriftCount returns the number of drifts detected implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GetDriftCount() int {
+	if a.lastReport == nil {
+		return 0
+	}
+	return a.lastReport.DriftedTopics + a.lastReport.DriftedSubscriptions
+}
+
+// DiscoverTopics finds all Pub/Sub topics across the specified projects
+func (a *Analyzer) DiscoverTopics(ctx context.Context, projects []string) ([]*Topic, error) {
+	var topics []*Topic
+
+	for _, project := range projects {
+		err := a.service.Projects.Topics.List("projects/"+project).Pages(ctx, func(resp *pubsubapi.ListTopicsResponse) error {
+			for _, t := range resp.Topics {
+				topics = append(topics, &Topic{
+					Project: project,
+					Name:    shortName(t.Name),
+					State:   t.State,
+					Labels:  t.Labels,
+					Config:  extractTopicConfig(t),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to discover topics in project %s: %w", project, err)
+		}
+	}
+
+	return topics, nil
+}
+
+// DiscoverSubscriptions finds all Pub/Sub subscriptions across the specified projects
+func (a *Analyzer) DiscoverSubscriptions(ctx context.Context, projects []string) ([]*Subscription, error) {
+	var subscriptions []*Subscription
+
+	for _, project := range projects {
+		err := a.service.Projects.Subscriptions.List("projects/"+project).Pages(ctx, func(resp *pubsubapi.ListSubscriptionsResponse) error {
+			for _, s := range resp.Subscriptions {
+				subscriptions = append(subscriptions, &Subscription{
+					Project: project,
+					Name:    shortName(s.Name),
+					Topic:   shortName(s.Topic),
+					State:   s.State,
+					Labels:  s.Labels,
+					Config:  extractSubscriptionConfig(s),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to discover subscriptions in project %s: %w", project, err)
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// extractTopicConfig extracts configuration parameters from a topic
+func extractTopicConfig(t *pubsubapi.Topic) *TopicConfig {
+	requireCMEK := t.KmsKeyName != ""
+	config := &TopicConfig{
+		MessageRetentionDuration: t.MessageRetentionDuration,
+		RequireCMEK:              &requireCMEK,
+		KMSKeyName:               t.KmsKeyName,
+	}
+
+	if t.SchemaSettings != nil {
+		config.Schema = shortName(t.SchemaSettings.Schema)
+		config.SchemaEncoding = t.SchemaSettings.Encoding
+	}
+
+	return config
+}
+
+// extractSubscriptionConfig extracts configuration parameters from a subscription
+func extractSubscriptionConfig(s *pubsubapi.Subscription) *SubscriptionConfig {
+	hasDeadLetter := s.DeadLetterPolicy != nil && s.DeadLetterPolicy.DeadLetterTopic != ""
+	exactlyOnce := s.EnableExactlyOnceDelivery
+	ordering := s.EnableMessageOrdering
+
+	config := &SubscriptionConfig{
+		AckDeadlineSeconds:       s.AckDeadlineSeconds,
+		MessageRetentionDuration: s.MessageRetentionDuration,
+		RequireDeadLetter:        &hasDeadLetter,
+		ExactlyOnceDelivery:      &exactlyOnce,
+		MessageOrdering:          &ordering,
+	}
+
+	if hasDeadLetter {
+		config.DeadLetterTopic = shortName(s.DeadLetterPolicy.DeadLetterTopic)
+		config.MaxDeliveryAttempts = s.DeadLetterPolicy.MaxDeliveryAttempts
+	}
+
+	return config
+}
+
+// shortName returns the resource ID from a full resource name
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// AnalyzeDrift compares discovered topics and subscriptions against a baseline
+func (a *Analyzer) AnalyzeDrift(topics []*Topic, subscriptions []*Subscription, baseline PubSubBaseline) *DriftReport {
+	report := &DriftReport{
+		Timestamp:          time.Now(),
+		TotalTopics:        len(topics),
+		TotalSubscriptions: len(subscriptions),
+		Resources:          make([]*ResourceDrift, 0),
+		Errors:             a.ScanErrors(),
+	}
+
+	for _, t := range topics {
+		drift := a.analyzeTopic(t, baseline.TopicConfig)
+		report.Resources = append(report.Resources, drift)
+		if len(drift.Drifts) > 0 {
+			report.DriftedTopics++
+		}
+	}
+
+	for _, s := range subscriptions {
+		drift := a.analyzeSubscription(s, baseline.SubscriptionConfig)
+		report.Resources = append(report.Resources, drift)
+		if len(drift.Drifts) > 0 {
+			report.DriftedSubscriptions++
+		}
+	}
+
+	a.lastReport = report
+	return report
+}
+
+// analyzeTopic compares a single topic against the baseline configuration
+func (a *Analyzer) analyzeTopic(t *Topic, baseline *TopicConfig) *ResourceDrift {
+	drift := &ResourceDrift{
+		Kind:    KindTopic,
+		Project: t.Project,
+		Name:    t.Name,
+		State:   t.State,
+		Labels:  t.Labels,
+		Drifts:  make([]Drift, 0),
+	}
+
+	if baseline == nil {
+		return drift
+	}
+
+	actual := t.Config
+
+	if baseline.MessageRetentionDuration != "" && !sameDuration(actual.MessageRetentionDuration, baseline.MessageRetentionDuration) {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "message_retention_duration",
+			Expected: baseline.MessageRetentionDuration,
+			Actual:   valueOrNotSet(actual.MessageRetentionDuration),
+			Severity: "medium",
+		})
+	}
+
+	if baseline.RequireCMEK != nil && *baseline.RequireCMEK && actual.KMSKeyName == "" {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "kms_key_name",
+			Expected: "customer-managed key",
+			Actual:   "Google-managed key",
+			Severity: "high",
+		})
+	} else if baseline.KMSKeyName != "" && actual.KMSKeyName != baseline.KMSKeyName {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "kms_key_name",
+			Expected: baseline.KMSKeyName,
+			Actual:   valueOrNotSet(actual.KMSKeyName),
+			Severity: "high",
+		})
+	}
+
+	if baseline.Schema != "" && actual.Schema != baseline.Schema {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "schema_settings.schema",
+			Expected: baseline.Schema,
+			Actual:   valueOrNotSet(actual.Schema),
+			Severity: "medium",
+		})
+	}
+
+	if baseline.SchemaEncoding != "" && actual.SchemaEncoding != baseline.SchemaEncoding {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "schema_settings.encoding",
+			Expected: baseline.SchemaEncoding,
+			Actual:   valueOrNotSet(actual.SchemaEncoding),
+			Severity: "low",
+		})
+	}
+
+	return drift
+}
+
+// analyzeSubscription compares a single subscription against the baseline configuration
+func (a *Analyzer) analyzeSubscription(s *Subscription, baseline *SubscriptionConfig) *ResourceDrift {
+	drift := &ResourceDrift{
+		Kind:    KindSubscription,
+		Project: s.Project,
+		Name:    s.Name,
+		Topic:   s.Topic,
+		State:   s.State,
+		Labels:  s.Labels,
+		Drifts:  make([]Drift, 0),
+	}
+
+	if baseline == nil {
+		return drift
+	}
+
+	actual := s.Config
+
+	if baseline.AckDeadlineSeconds > 0 && actual.AckDeadlineSeconds != baseline.AckDeadlineSeconds {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "ack_deadline_seconds",
+			Expected: fmt.Sprintf("%d", baseline.AckDeadlineSeconds),
+			Actual:   fmt.Sprintf("%d", actual.AckDeadlineSeconds),
+			Severity: "medium",
+		})
+	}
+
+	if baseline.MessageRetentionDuration != "" && !sameDuration(actual.MessageRetentionDuration, baseline.MessageRetentionDuration) {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "message_retention_duration",
+			Expected: baseline.MessageRetentionDuration,
+			Actual:   valueOrNotSet(actual.MessageRetentionDuration),
+			Severity: "medium",
+		})
+	}
+
+	a.compareDeadLetterPolicy(actual, baseline, drift)
+
+	if baseline.ExactlyOnceDelivery != nil && boolValue(actual.ExactlyOnceDelivery) != *baseline.ExactlyOnceDelivery {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "enable_exactly_once_delivery",
+			Expected: fmt.Sprintf("%v", *baseline.ExactlyOnceDelivery),
+			Actual:   fmt.Sprintf("%v", boolValue(actual.ExactlyOnceDelivery)),
+			Severity: "medium",
+		})
+	}
+
+	if baseline.MessageOrdering != nil && boolValue(actual.MessageOrdering) != *baseline.MessageOrdering {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "enable_message_ordering",
+			Expected: fmt.Sprintf("%v", *baseline.MessageOrdering),
+			Actual:   fmt.Sprintf("%v", boolValue(actual.MessageOrdering)),
+			Severity: "medium",
+		})
+	}
+
+	return drift
+}
+
+// compareDeadLetterPolicy compares the dead-letter topic and delivery attempts
+func (a *Analyzer) compareDeadLetterPolicy(actual, baseline *SubscriptionConfig, drift *ResourceDrift) {
+	required := boolValue(baseline.RequireDeadLetter) || baseline.DeadLetterTopic != ""
+	if required && actual.DeadLetterTopic == "" {
+		expected := "configured"
+		if baseline.DeadLetterTopic != "" {
+			expected = baseline.DeadLetterTopic
+		}
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "dead_letter_policy",
+			Expected: expected,
+			Actual:   "not configured",
+			Severity: "high",
+		})
+		return
+	}
+
+	if baseline.DeadLetterTopic != "" && actual.DeadLetterTopic != baseline.DeadLetterTopic {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "dead_letter_policy.dead_letter_topic",
+			Expected: baseline.DeadLetterTopic,
+			Actual:   actual.DeadLetterTopic,
+			Severity: "medium",
+		})
+	}
+
+	if baseline.MaxDeliveryAttempts > 0 && actual.DeadLetterTopic != "" &&
+		actual.MaxDeliveryAttempts != baseline.MaxDeliveryAttempts {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "dead_letter_policy.max_delivery_attempts",
+			Expected: fmt.Sprintf("%d", baseline.MaxDeliveryAttempts),
+			Actual:   fmt.Sprintf("%d", actual.MaxDeliveryAttempts),
+			Severity: "low",
+		})
+	}
+}
+
+// sameDuration compares two durations, accepting both API ("604800s") and Go ("168h") forms
+func sameDuration(actual, expected string) bool {
+	a, errA := time.ParseDuration(actual)
+	e, errE := time.ParseDuration(expected)
+	if errA != nil || errE != nil {
+		return actual == expected
+	}
+	return a == e
+}
+
+func valueOrNotSet(s string) string {
+	if s == "" {
+		return "not set"
+	}
+	return s
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
@@ -0,0 +1,147 @@
+package pubsub
+
+import (
+	"testing"
+
+	pubsubapi "google.golang.org/api/pubsub/v1"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestExtractConfig(t *testing.T) {
+	topic := extractTopicConfig(&pubsubapi.Topic{
+		Name:                     "projects/p/topics/orders",
+		KmsKeyName:               "projects/p/locations/global/keyRings/r/cryptoKeys/k",
+		MessageRetentionDuration: "604800s",
+		SchemaSettings: &pubsubapi.SchemaSettings{
+			Schema:   "projects/p/schemas/order",
+			Encoding: "JSON",
+		},
+	})
+	if !boolValue(topic.RequireCMEK) || topic.Schema != "order" || topic.SchemaEncoding != "JSON" {
+		t.Errorf("extractTopicConfig() = %+v", topic)
+	}
+
+	sub := extractSubscriptionConfig(&pubsubapi.Subscription{
+		AckDeadlineSeconds: 60,
+		DeadLetterPolicy: &pubsubapi.DeadLetterPolicy{
+			DeadLetterTopic:     "projects/p/topics/orders-dlq",
+			MaxDeliveryAttempts: 5,
+		},
+	})
+	if sub.DeadLetterTopic != "orders-dlq" || sub.MaxDeliveryAttempts != 5 || !boolValue(sub.RequireDeadLetter) {
+		t.Errorf("extractSubscriptionConfig() = %+v", sub)
+	}
+}
+
+func TestSameDuration(t *testing.T) {
+	tests := []struct {
+		actual, expected string
+		want             bool
+	}{
+		{"604800s", "168h", true},
+		{"604800s", "604800s", true},
+		{"86400s", "168h", false},
+		{"", "168h", false},
+	}
+	for _, tt := range tests {
+		if got := sameDuration(tt.actual, tt.expected); got != tt.want {
+			t.Errorf("sameDuration(%q, %q) = %v, want %v", tt.actual, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyzeDrift(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := PubSubBaseline{
+		Name: "default",
+		TopicConfig: &TopicConfig{
+			MessageRetentionDuration: "168h",
+			RequireCMEK:              boolPtr(true),
+			SchemaEncoding:           "JSON",
+		},
+		SubscriptionConfig: &SubscriptionConfig{
+			AckDeadlineSeconds:  60,
+			RequireDeadLetter:   boolPtr(true),
+			MaxDeliveryAttempts: 5,
+			ExactlyOnceDelivery: boolPtr(true),
+		},
+	}
+
+	topics := []*Topic{
+		{Name: "good", Config: &TopicConfig{
+			MessageRetentionDuration: "604800s",
+			KMSKeyName:               "key",
+			SchemaEncoding:           "JSON",
+		}},
+		{Name: "bad", Config: &TopicConfig{}},
+	}
+	subscriptions := []*Subscription{
+		{Name: "good-sub", Config: &SubscriptionConfig{
+			AckDeadlineSeconds:  60,
+			DeadLetterTopic:     "dlq",
+			MaxDeliveryAttempts: 5,
+			ExactlyOnceDelivery: boolPtr(true),
+		}},
+		{Name: "bad-sub", Config: &SubscriptionConfig{AckDeadlineSeconds: 10}},
+	}
+
+	report := a.AnalyzeDrift(topics, subscriptions, baseline)
+	if report.DriftedTopics != 1 || report.DriftedSubscriptions != 1 {
+		t.Fatalf("drifted = %d topics, %d subscriptions, want 1 and 1", report.DriftedTopics, report.DriftedSubscriptions)
+	}
+	if a.GetDriftCount() != 2 {
+		t.Errorf("GetDriftCount() = %d, want 2", a.GetDriftCount())
+	}
+
+	tests := []struct {
+		index int
+		want  map[string]string
+	}{
+		{0, map[string]string{}},
+		{1, map[string]string{
+			"message_retention_duration": "medium",
+			"kms_key_name":               "high",
+			"schema_settings.encoding":   "low",
+		}},
+		{2, map[string]string{}},
+		{3, map[string]string{
+			"ack_deadline_seconds":         "medium",
+			"dead_letter_policy":           "high",
+			"enable_exactly_once_delivery": "medium",
+		}},
+	}
+
+	for _, tt := range tests {
+		res := report.Resources[tt.index]
+		got := make(map[string]string)
+		for _, d := range res.Drifts {
+			got[d.Field] = d.Severity
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %d drifts, want %d: %v", res.Name, len(got), len(tt.want), got)
+		}
+		for field, severity := range tt.want {
+			if got[field] != severity {
+				t.Errorf("%s: drift %s severity = %q, want %q", res.Name, field, got[field], severity)
+			}
+		}
+	}
+
+	generic := report.ToReport()
+	if generic.Resources[0].Type != "Pub/Sub Topic" || generic.Resources[2].Type != "Pub/Sub Subscription" {
+		t.Errorf("ToReport() types = %s, %s", generic.Resources[0].Type, generic.Resources[2].Type)
+	}
+}
+
+func TestBaselineValidate(t *testing.T) {
+	if err := (PubSubBaseline{Name: "empty"}).Validate(); err == nil {
+		t.Error("Validate() expected error for baseline without configs")
+	}
+	if err := (PubSubBaseline{Name: "ok", TopicConfig: &TopicConfig{}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
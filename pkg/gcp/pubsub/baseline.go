@@ -0,0 +1,71 @@
+package pubsub
+
+import (
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// PubSubBaseline represents a Pub/Sub configuration baseline with optional filters.
+// Topic and subscription settings are checked independently; either may be omitted.
+type PubSubBaseline struct {
+	Name               string              `yaml:"name,omitempty"`
+	FilterLabels       map[string]string   `yaml:"filter_labels,omitempty"`
+	TopicConfig        *TopicConfig        `yaml:"topic_config,omitempty"`
+	SubscriptionConfig *SubscriptionConfig `yaml:"subscription_config,omitempty"`
+	// Metadata records when the baseline was last reviewed
+	Metadata analyzer.BaselineMetadata `yaml:"metadata,omitempty"`
+}
+
+// Compile-time interface implementation check
+var _ analyzer.Baseline = (*PubSubBaseline)(nil)
+
+// GetName returns the baseline name implementing analyzer.Baseline interface
+func (b PubSubBaseline) GetName() string {
+	return b.Name
+}
+
+// Validate checks if the baseline is valid implementing analyzer.Baseline interface
+func (b PubSubBaseline) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("baseline name is required")
+	}
+	if err := b.Metadata.Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	if b.TopicConfig == nil && b.SubscriptionConfig == nil {
+		return fmt.Errorf("baseline %s must define topic_config or subscription_config", b.Name)
+	}
+	return nil
+}
+
+// FilterTopics returns topics matching all of the given labels
+func FilterTopics(topics []*Topic, labels map[string]string) []*Topic {
+	filtered := make([]*Topic, 0)
+	for _, t := range topics {
+		if matchesLabels(t.Labels, labels) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// FilterSubscriptions returns subscriptions matching all of the given labels
+func FilterSubscriptions(subscriptions []*Subscription, labels map[string]string) []*Subscription {
+	filtered := make([]*Subscription, 0)
+	for _, s := range subscriptions {
+		if matchesLabels(s.Labels, labels) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+func matchesLabels(actual, labels map[string]string) bool {
+	for key, value := range labels {
+		if actual[key] != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// Resource kinds reported by the Pub/Sub analyzer
+const (
+	KindTopic        = "topic"
+	KindSubscription = "subscription"
+)
+
+// DriftReport contains the complete analysis results for topics and subscriptions
+type DriftReport struct {
+	Timestamp            time.Time        `json:"timestamp" yaml:"timestamp"`
+	TotalTopics          int              `json:"total_topics" yaml:"total_topics"`
+	DriftedTopics        int              `json:"drifted_topics" yaml:"drifted_topics"`
+	TotalSubscriptions   int              `json:"total_subscriptions" yaml:"total_subscriptions"`
+	DriftedSubscriptions int              `json:"drifted_subscriptions" yaml:"drifted_subscriptions"`
+	Resources            []*ResourceDrift `json:"resources" yaml:"resources"`
+	// Warnings flag a baseline that is due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []report.ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []report.Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []report.SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
+}
+
+// ResourceDrift represents drift analysis results for a single topic or subscription
+type ResourceDrift struct {
+	Kind    string            `json:"kind" yaml:"kind"`
+	Project string            `json:"project" yaml:"project"`
+	Name    string            `json:"name" yaml:"name"`
+	Topic   string            `json:"topic,omitempty" yaml:"topic,omitempty"`
+	State   string            `json:"state,omitempty" yaml:"state,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Drifts  []Drift           `json:"drifts" yaml:"drifts"`
+}
+
+// Drift represents a single configuration difference from the baseline
+type Drift = report.Drift
+
+// resourceType returns the display name of a resource kind
+func resourceType(kind string) string {
+	if kind == KindSubscription {
+		return "Pub/Sub Subscription"
+	}
+	return "Pub/Sub Topic"
+}
+
+// FormatText generates a human-readable text report
+func (r *DriftReport) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+	sb.WriteString("  GCP Pub/Sub Drift Analysis Report\n")
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("Generated: %s\n", r.Timestamp.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Topics: %d (%d with drift)\n", r.TotalTopics, r.DriftedTopics))
+	sb.WriteString(fmt.Sprintf("Subscriptions: %d (%d with drift)\n\n", r.TotalSubscriptions, r.DriftedSubscriptions))
+
+	// Summary by severity
+	var all []Drift
+	for _, res := range r.Resources {
+		all = append(all, res.Drifts...)
+	}
+	sb.WriteString(report.FormatDriftSummary(report.CountBySeverity(all)))
+	sb.WriteString(report.FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(report.FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(report.FormatSLO(r.SLO))
+
+	// Detailed resource reports
+	for i, res := range r.Resources {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(res.FormatText())
+	}
+	sb.WriteString(report.FormatScanErrors(r.Errors))
+
+	return sb.String()
+}
+
+// FormatText generates a formatted text representation of resource drift details
+func (rd *ResourceDrift) FormatText() string {
+	var sb strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("141")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	divider := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("───────────────────────────────────────────────────────────────────────────────")
+
+	sb.WriteString(divider + "\n")
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("%s: %s", resourceType(rd.Kind), rd.Name)) + "\n\n")
+	sb.WriteString(labelStyle.Render("Project:  ") + valueStyle.Render(rd.Project) + "\n")
+	if rd.Topic != "" {
+		sb.WriteString(labelStyle.Render("Topic:    ") + valueStyle.Render(rd.Topic) + "\n")
+	}
+	if rd.State != "" {
+		sb.WriteString(labelStyle.Render("State:    ") + valueStyle.Render(rd.State) + "\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(report.FormatDrifts(rd.Drifts))
+
+	return sb.String()
+}
+
+// FormatJSON generates JSON output of the drift report
+func (r *DriftReport) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatYAML generates YAML output of the drift report
+func (r *DriftReport) FormatYAML() (string, error) {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToReport converts the Pub/Sub drift report into the generic report model
+func (r *DriftReport) ToReport() *report.Report {
+	resources := make([]report.Resource, 0, len(r.Resources))
+	for _, res := range r.Resources {
+		resources = append(resources, report.Resource{
+			Type:     resourceType(res.Kind),
+			Project:  res.Project,
+			Name:     res.Name,
+			Location: "global",
+			State:    res.State,
+			Labels:   res.Labels,
+			Drifts:   res.Drifts,
+		})
+	}
+
+	return &report.Report{
+		Title:            "GCP Pub/Sub Drift Analysis Report",
+		Timestamp:        r.Timestamp,
+		Resources:        resources,
+		Warnings:         r.Warnings,
+		Errors:           r.Errors,
+		Acknowledgements: r.Acknowledgements,
+		SLO:              r.SLO,
+	}
+}
@@ -0,0 +1,330 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	redisapi "google.golang.org/api/redis/v1"
+)
+
+// RedisInstance represents a Memorystore for Redis instance with its configuration
+type RedisInstance struct {
+	Project  string
+	Name     string
+	Location string
+	State    string
+	Config   *InstanceConfig
+	Labels   map[string]string
+}
+
+// InstanceConfig holds the configuration parameters of a Memorystore instance
+type InstanceConfig struct {
+	Tier                  string             `yaml:"tier,omitempty" json:"tier,omitempty"`
+	MemorySizeGB          int64              `yaml:"memory_size_gb,omitempty" json:"memory_size_gb,omitempty"`
+	RedisVersion          string             `yaml:"redis_version,omitempty" json:"redis_version,omitempty"`
+	AuthEnabled           *bool              `yaml:"auth_enabled,omitempty" json:"auth_enabled,omitempty"`
+	TransitEncryptionMode string             `yaml:"transit_encryption_mode,omitempty" json:"transit_encryption_mode,omitempty"`
+	MaintenanceWindow     *MaintenanceWindow `yaml:"maintenance_window,omitempty" json:"maintenance_window,omitempty"`
+	ReadReplicasMode      string             `yaml:"read_replicas_mode,omitempty" json:"read_replicas_mode,omitempty"`
+	ReplicaCount          *int64             `yaml:"replica_count,omitempty" json:"replica_count,omitempty"`
+}
+
+// MaintenanceWindow defines the weekly maintenance window of an instance
+type MaintenanceWindow struct {
+	Day       string `yaml:"day" json:"day"`
+	StartHour int64  `yaml:"start_hour" json:"start_hour"`
+}
+
+// Analyzer performs drift analysis on Memorystore for Redis instances
+type Analyzer struct {
+	analyzer.ProjectErrors
+
+	service    *redisapi.Service
+	lastReport *DriftReport
+	projects   []string
+}
+
+// NewAnalyzer creates a new Memorystore Analyzer instance
+func NewAnalyzer(ctx context.Context) (*Analyzer, error) {
+	service, err := redisapi.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Memorystore client: %w", err)
+	}
+
+	return &Analyzer{service: service}, nil
+}
+
+// Close releases resources held by the Analyzer
+func (a *Analyzer) Close() error {
+	return nil
+}
+
+// Compile-time interface implementation check
+var _ analyzer.ResourceAnalyzer = (*Analyzer)(nil)
+
+// Analyze performs drift analysis implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) Analyze(ctx context.Context, projects []string) error {
+	a.projects = projects
+	return nil
+}
+
+// GenerateReport generates a formatted report implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GenerateReport() (string, error) {
+	if a.lastReport == nil {
+		return "", fmt.Errorf("no analysis has been performed yet")
+	}
+	return a.lastReport.FormatText(), nil
+}
+
+// GetDriftCount returns the number of drifts detected implementing analyzer.ResourceAnalyzer interface
+func (a *Analyzer) GetDriftCount() int {
+	if a.lastReport == nil {
+		return 0
+	}
+	return a.lastReport.DriftedInstances
+}
+
+// DiscoverInstances finds all Memorystore for Redis instances across the specified projects
+func (a *Analyzer) DiscoverInstances(ctx context.Context, projects []string) ([]*RedisInstance, error) {
+	var instances []*RedisInstance
+
+	for _, project := range projects {
+		projectInstances, err := a.discoverProjectInstances(ctx, project)
+		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to discover Redis instances in project %s: %w", project, err)
+		}
+		instances = append(instances, projectInstances...)
+	}
+
+	return instances, nil
+}
+
+// discoverProjectInstances lists all Redis instances in all locations of a single project
+func (a *Analyzer) discoverProjectInstances(ctx context.Context, project string) ([]*RedisInstance, error) {
+	parent := fmt.Sprintf("projects/%s/locations/-", project)
+
+	var instances []*RedisInstance
+	err := a.service.Projects.Locations.Instances.List(parent).Pages(ctx, func(resp *redisapi.ListInstancesResponse) error {
+		for _, inst := range resp.Instances {
+			instances = append(instances, &RedisInstance{
+				Project:  project,
+				Name:     shortName(inst.Name),
+				Location: inst.LocationId,
+				State:    inst.State,
+				Config:   extractConfig(inst),
+				Labels:   inst.Labels,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return instances, nil
+}
+
+// extractConfig extracts configuration parameters from a Memorystore instance
+func extractConfig(inst *redisapi.Instance) *InstanceConfig {
+	authEnabled := inst.AuthEnabled
+	replicaCount := inst.ReplicaCount
+
+	config := &InstanceConfig{
+		Tier:                  inst.Tier,
+		MemorySizeGB:          inst.MemorySizeGb,
+		RedisVersion:          inst.RedisVersion,
+		AuthEnabled:           &authEnabled,
+		TransitEncryptionMode: inst.TransitEncryptionMode,
+		ReadReplicasMode:      inst.ReadReplicasMode,
+		ReplicaCount:          &replicaCount,
+	}
+
+	if inst.MaintenancePolicy != nil && len(inst.MaintenancePolicy.WeeklyMaintenanceWindow) > 0 {
+		window := inst.MaintenancePolicy.WeeklyMaintenanceWindow[0]
+		config.MaintenanceWindow = &MaintenanceWindow{Day: window.Day}
+		if window.StartTime != nil {
+			config.MaintenanceWindow.StartHour = window.StartTime.Hours
+		}
+	}
+
+	return config
+}
+
+// shortName returns the instance ID from a full resource name
+func shortName(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// AnalyzeDrift compares discovered instances against a baseline and generates a drift report
+func (a *Analyzer) AnalyzeDrift(instances []*RedisInstance, baseline *InstanceConfig) *DriftReport {
+	report := &DriftReport{
+		Timestamp:      time.Now(),
+		TotalInstances: len(instances),
+		Instances:      make([]*InstanceDrift, 0),
+		Errors:         a.ScanErrors(),
+	}
+
+	for _, inst := range instances {
+		drift := a.analyzeInstance(inst, baseline)
+		report.Instances = append(report.Instances, drift)
+
+		if len(drift.Drifts) > 0 {
+			report.DriftedInstances++
+		}
+	}
+
+	a.lastReport = report
+	return report
+}
+
+// analyzeInstance compares a single instance against the baseline configuration
+func (a *Analyzer) analyzeInstance(inst *RedisInstance, baseline *InstanceConfig) *InstanceDrift {
+	drift := &InstanceDrift{
+		Project:  inst.Project,
+		Name:     inst.Name,
+		Location: inst.Location,
+		State:    inst.State,
+		Labels:   inst.Labels,
+		Tier:     inst.Config.Tier,
+		MemoryGB: inst.Config.MemorySizeGB,
+		Drifts:   make([]Drift, 0),
+	}
+
+	if baseline == nil {
+		return drift
+	}
+
+	actual := inst.Config
+
+	if baseline.Tier != "" && actual.Tier != baseline.Tier {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "tier",
+			Expected: baseline.Tier,
+			Actual:   actual.Tier,
+			Severity: "high",
+		})
+	}
+
+	if baseline.MemorySizeGB > 0 && actual.MemorySizeGB != baseline.MemorySizeGB {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "memory_size_gb",
+			Expected: fmt.Sprintf("%d", baseline.MemorySizeGB),
+			Actual:   fmt.Sprintf("%d", actual.MemorySizeGB),
+			Severity: "medium",
+		})
+	}
+
+	if baseline.RedisVersion != "" && actual.RedisVersion != baseline.RedisVersion {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "redis_version",
+			Expected: baseline.RedisVersion,
+			Actual:   actual.RedisVersion,
+			Severity: "medium",
+		})
+	}
+
+	a.compareSecurity(actual, baseline, drift)
+	a.compareReplicas(actual, baseline, drift)
+	a.compareMaintenanceWindow(actual, baseline, drift)
+
+	return drift
+}
+
+// compareSecurity compares AUTH and in-transit encryption settings
+func (a *Analyzer) compareSecurity(actual, baseline *InstanceConfig, drift *InstanceDrift) {
+	if baseline.AuthEnabled != nil && boolValue(actual.AuthEnabled) != *baseline.AuthEnabled {
+		severity := "medium"
+		if *baseline.AuthEnabled {
+			severity = "critical"
+		}
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "auth_enabled",
+			Expected: fmt.Sprintf("%v", *baseline.AuthEnabled),
+			Actual:   fmt.Sprintf("%v", boolValue(actual.AuthEnabled)),
+			Severity: severity,
+		})
+	}
+
+	if baseline.TransitEncryptionMode != "" && actual.TransitEncryptionMode != baseline.TransitEncryptionMode {
+		severity := "high"
+		if actual.TransitEncryptionMode == "DISABLED" || actual.TransitEncryptionMode == "" {
+			severity = "critical"
+		}
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "transit_encryption_mode",
+			Expected: baseline.TransitEncryptionMode,
+			Actual:   actual.TransitEncryptionMode,
+			Severity: severity,
+		})
+	}
+}
+
+// compareReplicas compares read replica settings
+func (a *Analyzer) compareReplicas(actual, baseline *InstanceConfig, drift *InstanceDrift) {
+	if baseline.ReadReplicasMode != "" && actual.ReadReplicasMode != baseline.ReadReplicasMode {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "read_replicas_mode",
+			Expected: baseline.ReadReplicasMode,
+			Actual:   actual.ReadReplicasMode,
+			Severity: "medium",
+		})
+	}
+
+	if baseline.ReplicaCount != nil && int64Value(actual.ReplicaCount) != *baseline.ReplicaCount {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "replica_count",
+			Expected: fmt.Sprintf("%d", *baseline.ReplicaCount),
+			Actual:   fmt.Sprintf("%d", int64Value(actual.ReplicaCount)),
+			Severity: "medium",
+		})
+	}
+}
+
+// compareMaintenanceWindow compares the weekly maintenance window
+func (a *Analyzer) compareMaintenanceWindow(actual, baseline *InstanceConfig, drift *InstanceDrift) {
+	if baseline.MaintenanceWindow == nil {
+		return
+	}
+
+	expected := fmt.Sprintf("%s %02d:00 UTC", baseline.MaintenanceWindow.Day, baseline.MaintenanceWindow.StartHour)
+	if actual.MaintenanceWindow == nil {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "maintenance_window",
+			Expected: expected,
+			Actual:   "not set",
+			Severity: "low",
+		})
+		return
+	}
+
+	got := fmt.Sprintf("%s %02d:00 UTC", actual.MaintenanceWindow.Day, actual.MaintenanceWindow.StartHour)
+	if got != expected {
+		drift.Drifts = append(drift.Drifts, Drift{
+			Field:    "maintenance_window",
+			Expected: expected,
+			Actual:   got,
+			Severity: "low",
+		})
+	}
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
+
+func int64Value(i *int64) int64 {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
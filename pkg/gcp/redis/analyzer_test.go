@@ -0,0 +1,129 @@
+package redis
+
+import (
+	"strings"
+	"testing"
+
+	redisapi "google.golang.org/api/redis/v1"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestExtractConfig(t *testing.T) {
+	inst := &redisapi.Instance{
+		Name:                  "projects/p/locations/us-central1/instances/cache",
+		Tier:                  "STANDARD_HA",
+		MemorySizeGb:          5,
+		RedisVersion:          "REDIS_7_0",
+		AuthEnabled:           true,
+		TransitEncryptionMode: "SERVER_AUTHENTICATION",
+		ReplicaCount:          2,
+		MaintenancePolicy: &redisapi.MaintenancePolicy{
+			WeeklyMaintenanceWindow: []*redisapi.WeeklyMaintenanceWindow{
+				{Day: "SUNDAY", StartTime: &redisapi.TimeOfDay{Hours: 3}},
+			},
+		},
+	}
+
+	config := extractConfig(inst)
+	if config.Tier != "STANDARD_HA" || config.MemorySizeGB != 5 || !boolValue(config.AuthEnabled) {
+		t.Errorf("extractConfig() = %+v", config)
+	}
+	if config.MaintenanceWindow == nil || config.MaintenanceWindow.Day != "SUNDAY" || config.MaintenanceWindow.StartHour != 3 {
+		t.Errorf("MaintenanceWindow = %+v, want SUNDAY 3", config.MaintenanceWindow)
+	}
+	if got := shortName(inst.Name); got != "cache" {
+		t.Errorf("shortName() = %v, want cache", got)
+	}
+}
+
+func TestAnalyzeDrift(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &InstanceConfig{
+		Tier:                  "STANDARD_HA",
+		MemorySizeGB:          5,
+		RedisVersion:          "REDIS_7_0",
+		AuthEnabled:           boolPtr(true),
+		TransitEncryptionMode: "SERVER_AUTHENTICATION",
+		MaintenanceWindow:     &MaintenanceWindow{Day: "SUNDAY", StartHour: 3},
+		ReadReplicasMode:      "READ_REPLICAS_ENABLED",
+		ReplicaCount:          int64Ptr(2),
+	}
+
+	compliant := &RedisInstance{Name: "good", Config: &InstanceConfig{
+		Tier:                  "STANDARD_HA",
+		MemorySizeGB:          5,
+		RedisVersion:          "REDIS_7_0",
+		AuthEnabled:           boolPtr(true),
+		TransitEncryptionMode: "SERVER_AUTHENTICATION",
+		MaintenanceWindow:     &MaintenanceWindow{Day: "SUNDAY", StartHour: 3},
+		ReadReplicasMode:      "READ_REPLICAS_ENABLED",
+		ReplicaCount:          int64Ptr(2),
+	}}
+
+	drifted := &RedisInstance{Name: "bad", Config: &InstanceConfig{
+		Tier:                  "BASIC",
+		MemorySizeGB:          1,
+		RedisVersion:          "REDIS_6_X",
+		AuthEnabled:           boolPtr(false),
+		TransitEncryptionMode: "DISABLED",
+		ReadReplicasMode:      "READ_REPLICAS_DISABLED",
+		ReplicaCount:          int64Ptr(0),
+	}}
+
+	report := a.AnalyzeDrift([]*RedisInstance{compliant, drifted}, baseline)
+	if report.DriftedInstances != 1 {
+		t.Fatalf("DriftedInstances = %d, want 1", report.DriftedInstances)
+	}
+	if len(report.Instances[0].Drifts) != 0 {
+		t.Errorf("compliant instance drifts = %v, want none", report.Instances[0].Drifts)
+	}
+
+	got := make(map[string]string)
+	for _, d := range report.Instances[1].Drifts {
+		got[d.Field] = d.Severity
+	}
+	want := map[string]string{
+		"tier":                    "high",
+		"memory_size_gb":          "medium",
+		"redis_version":           "medium",
+		"auth_enabled":            "critical",
+		"transit_encryption_mode": "critical",
+		"read_replicas_mode":      "medium",
+		"replica_count":           "medium",
+		"maintenance_window":      "low",
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d drifts, want %d: %v", len(got), len(want), got)
+	}
+	for field, severity := range want {
+		if got[field] != severity {
+			t.Errorf("drift %s severity = %q, want %q", field, got[field], severity)
+		}
+	}
+}
+
+func TestGenerateBaselineConfig(t *testing.T) {
+	if _, err := GenerateBaselineConfig(nil); err == nil {
+		t.Error("GenerateBaselineConfig(nil) expected error")
+	}
+
+	out, err := GenerateBaselineConfig([]*RedisInstance{
+		{Project: "p", Name: "cache", Config: &InstanceConfig{Tier: "BASIC", MemorySizeGB: 1}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateBaselineConfig() error = %v", err)
+	}
+	for _, want := range []string{"redis_baselines:", "name: cache", "tier: BASIC", "memory_size_gb: 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("GenerateBaselineConfig() missing %q:\n%s", want, out)
+		}
+	}
+}
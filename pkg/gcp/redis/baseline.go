@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"gopkg.in/yaml.v3"
+)
+
+// RedisBaseline represents a Memorystore configuration baseline with optional filters
+type RedisBaseline struct {
+	Name         string            `yaml:"name,omitempty"`
+	FilterLabels map[string]string `yaml:"filter_labels,omitempty"`
+	Config       *InstanceConfig   `yaml:"config"`
+	// Metadata records when the baseline was last reviewed
+	Metadata analyzer.BaselineMetadata `yaml:"metadata,omitempty"`
+}
+
+// Compile-time interface implementation check
+var _ analyzer.Baseline = (*RedisBaseline)(nil)
+
+// GetName returns the baseline name implementing analyzer.Baseline interface
+func (b RedisBaseline) GetName() string {
+	return b.Name
+}
+
+// Validate checks if the baseline is valid implementing analyzer.Baseline interface
+func (b RedisBaseline) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("baseline name is required")
+	}
+	if err := b.Metadata.Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	return nil
+}
+
+// Expected returns the Redis version and tier the baseline expects, so
+// deprecated ones can be flagged for review
+func (b RedisBaseline) Expected() (versions, tiers []string) {
+	if b.Config == nil {
+		return nil, nil
+	}
+	return []string{b.Config.RedisVersion}, []string{b.Config.Tier}
+}
+
+// FilterByLabels returns instances matching all of the given labels
+func FilterByLabels(instances []*RedisInstance, labels map[string]string) []*RedisInstance {
+	if len(labels) == 0 {
+		return instances
+	}
+
+	filtered := make([]*RedisInstance, 0)
+	for _, inst := range instances {
+		matches := true
+		for key, value := range labels {
+			if inst.Labels[key] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered
+}
+
+// GenerateBaselineConfig renders a redis_baselines config section from a discovered instance
+func GenerateBaselineConfig(instances []*RedisInstance) (string, error) {
+	if len(instances) == 0 {
+		return "", fmt.Errorf("no Redis instances to generate config from")
+	}
+
+	// Use first instance as baseline
+	inst := instances[0]
+
+	config := struct {
+		Projects       []string        `yaml:"projects"`
+		RedisBaselines []RedisBaseline `yaml:"redis_baselines"`
+	}{
+		Projects: []string{inst.Project},
+		RedisBaselines: []RedisBaseline{
+			{
+				Name:   inst.Name,
+				Config: inst.Config,
+			},
+		},
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return string(data), nil
+}
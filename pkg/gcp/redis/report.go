@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"gopkg.in/yaml.v3"
+)
+
+// DriftReport contains the complete analysis results for all Redis instances
+type DriftReport struct {
+	Timestamp        time.Time        `json:"timestamp" yaml:"timestamp"`
+	TotalInstances   int              `json:"total_instances" yaml:"total_instances"`
+	DriftedInstances int              `json:"drifted_instances" yaml:"drifted_instances"`
+	Instances        []*InstanceDrift `json:"instances" yaml:"instances"`
+	// Warnings flag a baseline that is due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []report.ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []report.Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []report.SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
+}
+
+// InstanceDrift represents drift analysis results for a single Redis instance
+type InstanceDrift struct {
+	Project  string            `json:"project" yaml:"project"`
+	Name     string            `json:"name" yaml:"name"`
+	Location string            `json:"location" yaml:"location"`
+	State    string            `json:"state" yaml:"state"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Tier     string            `json:"tier,omitempty" yaml:"tier,omitempty"`
+	MemoryGB int64             `json:"memory_size_gb,omitempty" yaml:"memory_size_gb,omitempty"`
+	Drifts   []Drift           `json:"drifts" yaml:"drifts"`
+}
+
+// Drift represents a single configuration difference from the baseline
+type Drift = report.Drift
+
+// FormatText generates a human-readable text report
+func (r *DriftReport) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+	sb.WriteString("  GCP Memorystore Redis Drift Analysis Report\n")
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("Generated: %s\n", r.Timestamp.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Total Instances: %d\n", r.TotalInstances))
+	sb.WriteString(fmt.Sprintf("Instances with Drift: %d\n", r.DriftedInstances))
+
+	if r.TotalInstances > 0 {
+		sb.WriteString(fmt.Sprintf("Compliance Rate: %.1f%%\n\n",
+			float64(r.TotalInstances-r.DriftedInstances)/float64(r.TotalInstances)*100))
+	}
+
+	// Summary by severity
+	var all []Drift
+	for _, inst := range r.Instances {
+		all = append(all, inst.Drifts...)
+	}
+	sb.WriteString(report.FormatDriftSummary(report.CountBySeverity(all)))
+	sb.WriteString(report.FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(report.FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(report.FormatSLO(r.SLO))
+
+	// Detailed instance reports
+	for i, inst := range r.Instances {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(inst.FormatText())
+	}
+	sb.WriteString(report.FormatScanErrors(r.Errors))
+
+	return sb.String()
+}
+
+// FormatText generates a formatted text representation of instance drift details
+func (id *InstanceDrift) FormatText() string {
+	var sb strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("203")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	divider := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("───────────────────────────────────────────────────────────────────────────────")
+
+	sb.WriteString(divider + "\n")
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("Memorystore Redis: %s", id.Name)) + "\n\n")
+	sb.WriteString(labelStyle.Render("Project:  ") + valueStyle.Render(id.Project) + "\n")
+	sb.WriteString(labelStyle.Render("Location: ") + valueStyle.Render(id.Location) + "\n")
+	sb.WriteString(labelStyle.Render("State:    ") + valueStyle.Render(id.State) + "\n")
+	if id.Tier != "" {
+		sb.WriteString(labelStyle.Render("Tier:     ") + valueStyle.Render(fmt.Sprintf("%s (%d GB)", id.Tier, id.MemoryGB)) + "\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(report.FormatDrifts(id.Drifts))
+
+	return sb.String()
+}
+
+// FormatJSON generates JSON output of the drift report
+func (r *DriftReport) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatYAML generates YAML output of the drift report
+func (r *DriftReport) FormatYAML() (string, error) {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// ToReport converts the Redis drift report into the generic report model
+func (r *DriftReport) ToReport() *report.Report {
+	resources := make([]report.Resource, 0, len(r.Instances))
+	for _, inst := range r.Instances {
+		resources = append(resources, report.Resource{
+			Type:     "Memorystore Redis",
+			Project:  inst.Project,
+			Name:     inst.Name,
+			Location: inst.Location,
+			State:    inst.State,
+			Labels:   inst.Labels,
+			Drifts:   inst.Drifts,
+		})
+	}
+
+	return &report.Report{
+		Title:            "GCP Memorystore Redis Drift Analysis Report",
+		Timestamp:        r.Timestamp,
+		Resources:        resources,
+		Warnings:         r.Warnings,
+		Errors:           r.Errors,
+		Acknowledgements: r.Acknowledgements,
+		SLO:              r.SLO,
+	}
+}
@@ -0,0 +1,112 @@
+// Package retry retries GCP API calls that fail for transient reasons: rate
+// limits, exhausted quota and server errors.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// Policy controls how often and how long failed calls are retried. The zero
+// value makes a single attempt.
+type Policy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// BaseDelay is the backoff before the first retry; it doubles per retry
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff
+	MaxDelay time.Duration
+}
+
+// DefaultPolicy is used by analyzers unless the caller sets another one
+func DefaultPolicy() Policy {
+	return Policy{MaxRetries: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: 30 * time.Second}
+}
+
+// quotaReasons are the 403 error reasons GCP uses for rate limits and quota
+var quotaReasons = map[string]bool{
+	"rateLimitExceeded":     true,
+	"userRateLimitExceeded": true,
+	"quotaExceeded":         true,
+	"dailyLimitExceeded":    true,
+}
+
+// Retryable reports whether err is a transient GCP API error worth retrying
+func Retryable(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	if apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= 500 {
+		return true
+	}
+	if apiErr.Code == http.StatusForbidden {
+		for _, item := range apiErr.Errors {
+			if quotaReasons[item.Reason] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Do runs call, the Do method of a GCP API request, until it succeeds, fails
+// with a non-retryable error, the retries are used up or ctx is done. Backoff
+// is exponential with full jitter; a Retry-After header takes precedence.
+func Do[T any](ctx context.Context, p Policy, call func(...googleapi.CallOption) (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := call()
+		if err == nil || attempt >= p.MaxRetries || !Retryable(err) {
+			return result, err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the wait before retry number attempt+1
+func (p Policy) backoff(attempt int, err error) time.Duration {
+	if wait, ok := retryAfter(err); ok {
+		if p.MaxDelay > 0 && wait > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return wait
+	}
+
+	ceiling := p.BaseDelay
+	for i := 0; i < attempt && i < 32 && (p.MaxDelay <= 0 || ceiling < p.MaxDelay); i++ {
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// retryAfter reads the Retry-After seconds the API sent with err, if any
+func retryAfter(err error) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Header == nil {
+		return 0, false
+	}
+	seconds, convErr := strconv.Atoi(apiErr.Header.Get("Retry-After"))
+	if convErr != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,107 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"rate limited", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"server error", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"quota", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "quotaExceeded"}}}, true},
+		{"permission denied", &googleapi.Error{Code: http.StatusForbidden, Errors: []googleapi.ErrorItem{{Reason: "forbidden"}}}, false},
+		{"not found", &googleapi.Error{Code: http.StatusNotFound}, false},
+		{"wrapped", errors.Join(errors.New("listing"), &googleapi.Error{Code: http.StatusBadGateway}), true},
+		{"not an API error", errors.New("dial tcp: timeout"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Retryable(tt.err); got != tt.want {
+				t.Errorf("Retryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	transient := &googleapi.Error{Code: http.StatusTooManyRequests}
+
+	t.Run("succeeds after transient errors", func(t *testing.T) {
+		calls := 0
+		got, err := Do(context.Background(), policy, func(...googleapi.CallOption) (string, error) {
+			calls++
+			if calls < 3 {
+				return "", transient
+			}
+			return "ok", nil
+		})
+		if err != nil || got != "ok" || calls != 3 {
+			t.Errorf("Do() = %q, %v after %d calls, want ok after 3", got, err, calls)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		calls := 0
+		_, err := Do(context.Background(), policy, func(...googleapi.CallOption) (int, error) {
+			calls++
+			return 0, transient
+		})
+		if !errors.Is(err, transient) || calls != 4 {
+			t.Errorf("Do() error = %v after %d calls, want the API error after 4", err, calls)
+		}
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		_, err := Do(context.Background(), policy, func(...googleapi.CallOption) (int, error) {
+			calls++
+			return 0, &googleapi.Error{Code: http.StatusNotFound}
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("Do() error = %v after %d calls, want an error after 1", err, calls)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		calls := 0
+		_, err := Do(ctx, Policy{MaxRetries: 3, BaseDelay: time.Hour}, func(...googleapi.CallOption) (int, error) {
+			calls++
+			return 0, transient
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("Do() error = %v after %d calls, want an error after 1", err, calls)
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if wait := policy.backoff(attempt, errors.New("transient")); wait <= 0 || wait > time.Second {
+			t.Errorf("backoff(%d) = %v, want within (0, 1s]", attempt, wait)
+		}
+	}
+
+	withHeader := &googleapi.Error{Code: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"7"}}}
+	if wait := (Policy{MaxDelay: time.Minute}).backoff(0, withHeader); wait != 7*time.Second {
+		t.Errorf("backoff() with Retry-After = %v, want 7s", wait)
+	}
+	if wait := policy.backoff(0, withHeader); wait != time.Second {
+		t.Errorf("backoff() with Retry-After = %v, want it capped at 1s", wait)
+	}
+}
@@ -3,14 +3,19 @@ package sql
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
 	"google.golang.org/api/sqladmin/v1"
 )
 
-// DatabaseInstance represents a GCP Cloud SQL PostgreSQL instance with its configuration
+// DatabaseInstance represents a GCP Cloud SQL PostgreSQL or SQL Server instance with its configuration
 type DatabaseInstance struct {
 	Project           string
 	Name              string
@@ -20,9 +25,45 @@ type DatabaseInstance struct {
 	MaintenanceWindow *MaintenanceWindow
 	Labels            map[string]string
 	Databases         []string
+	// Primary is the project:instance a replica replicates from; empty for
+	// primary instances
+	Primary  string
+	Replicas []Replica
+	// Users is nil when the users could not be listed
+	Users []User
+	// Certificates are the server CA certificate and the client certificates
+	Certificates []Certificate
+	// SuspensionReasons explain a SUSPENDED state, e.g. BILLING_ISSUE
+	SuspensionReasons []string
 }
 
-// DatabaseConfig holds the configuration parameters for a PostgreSQL instance
+// Certificate is an SSL certificate of an instance
+type Certificate struct {
+	// Kind is server_ca or client
+	Kind        string    `json:"kind" yaml:"kind"`
+	CommonName  string    `json:"common_name" yaml:"common_name"`
+	Fingerprint string    `json:"sha1_fingerprint,omitempty" yaml:"sha1_fingerprint,omitempty"`
+	Expires     time.Time `json:"expires" yaml:"expires"`
+}
+
+// User is a database user of an instance
+type User struct {
+	Name string `json:"name" yaml:"name"`
+	// Type is BUILT_IN for password users, or the kind of IAM principal,
+	// e.g. CLOUD_IAM_USER or CLOUD_IAM_SERVICE_ACCOUNT
+	Type string `json:"type" yaml:"type"`
+}
+
+// Replica is a read or failover replica of a primary instance
+type Replica struct {
+	Name string `json:"name" yaml:"name"`
+	// Region is empty when the replica is outside the scanned project
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+	// Type is read or failover
+	Type string `json:"type" yaml:"type"`
+}
+
+// DatabaseConfig holds the configuration parameters for a Cloud SQL instance
 type DatabaseConfig struct {
 	DatabaseVersion   string            `yaml:"database_version" json:"database_version"`
 	Tier              string            `yaml:"tier" json:"tier"`
@@ -33,6 +74,27 @@ type DatabaseConfig struct {
 	DiskAutoresize    bool              `yaml:"disk_autoresize" json:"disk_autoresize"`
 	MaintenanceDenied []string          `yaml:"maintenance_denied_periods,omitempty" json:"maintenance_denied_periods,omitempty"`
 	RequiredDatabases []string          `yaml:"required_databases,omitempty" json:"required_databases,omitempty"`
+	// Labels lists labels the instance must carry with the given values
+	Labels            map[string]string  `yaml:"labels,omitempty" json:"labels,omitempty"`
+	MaintenanceWindow *MaintenanceWindow `yaml:"maintenance_window,omitempty" json:"maintenance_window,omitempty"`
+	// AllowedValues lists acceptable values per field, replacing the single expected value
+	AllowedValues checks.Allowed `yaml:"allowed_values,omitempty" json:"allowed_values,omitempty"`
+	// SeverityOverrides replaces the built-in severity per field
+	SeverityOverrides checks.SeverityOverrides `yaml:"severity_overrides,omitempty" json:"severity_overrides,omitempty"`
+	// Ranges accepts numeric fields within bounds or a tolerance instead of an exact match
+	Ranges checks.Ranges `yaml:"ranges,omitempty" json:"ranges,omitempty"`
+	// RequiredReplicaCount is the number of replicas a primary must have
+	RequiredReplicaCount int64 `yaml:"required_replica_count,omitempty" json:"required_replica_count,omitempty"`
+	// ReplicaRegions are the regions replicas may run in; each needs at least one
+	ReplicaRegions []string `yaml:"replica_regions,omitempty" json:"replica_regions,omitempty"`
+	// RequiredUsers are database users the instance must have
+	RequiredUsers []string `yaml:"required_users,omitempty" json:"required_users,omitempty"`
+	// ForbiddenUsers are user name patterns (shell wildcards) that must not exist
+	ForbiddenUsers []string `yaml:"forbidden_users,omitempty" json:"forbidden_users,omitempty"`
+	// IAMAuthentication requires IAM database authentication on or off
+	IAMAuthentication *bool `yaml:"iam_authentication,omitempty" json:"iam_authentication,omitempty"`
+	// CertExpiryDays flags certificates expiring within this many days
+	CertExpiryDays int64 `yaml:"cert_expiry_days,omitempty" json:"cert_expiry_days,omitempty"`
 }
 
 // Settings contains the runtime and operational settings for a database instance
@@ -45,10 +107,28 @@ type Settings struct {
 	TransactionLogRetentionDays int64            `yaml:"transaction_log_retention_days,omitempty" json:"transaction_log_retention_days,omitempty"`
 	IPConfiguration             *IPConfiguration `yaml:"ip_configuration,omitempty" json:"ip_configuration,omitempty"`
 	LocationPreference          string           `yaml:"location_preference,omitempty" json:"location_preference,omitempty"`
+	SecondaryZone               string           `yaml:"secondary_zone,omitempty" json:"secondary_zone,omitempty"`
 	DataDiskSizeGb              int64            `yaml:"data_disk_size_gb" json:"data_disk_size_gb"`
 	PricingPlan                 string           `yaml:"pricing_plan" json:"pricing_plan"`
 	ReplicationType             string           `yaml:"replication_type" json:"replication_type"`
 	InsightsConfig              *InsightsConfig  `yaml:"insights_config,omitempty" json:"insights_config,omitempty"`
+
+	// SQL Server only
+	Collation       string                 `yaml:"collation,omitempty" json:"collation,omitempty"`
+	ActiveDirectory *ActiveDirectoryConfig `yaml:"active_directory,omitempty" json:"active_directory,omitempty"`
+	SQLServerAudit  *SQLServerAuditConfig  `yaml:"sql_server_audit,omitempty" json:"sql_server_audit,omitempty"`
+}
+
+// ActiveDirectoryConfig defines Managed Microsoft AD integration for SQL Server instances
+type ActiveDirectoryConfig struct {
+	Domain string `yaml:"domain" json:"domain"`
+}
+
+// SQLServerAuditConfig defines SQL Server audit log export settings
+type SQLServerAuditConfig struct {
+	Bucket            string `yaml:"bucket" json:"bucket"`
+	RetentionInterval string `yaml:"retention_interval,omitempty" json:"retention_interval,omitempty"`
+	UploadInterval    string `yaml:"upload_interval,omitempty" json:"upload_interval,omitempty"`
 }
 
 // IPConfiguration defines network and security settings for database access
@@ -76,19 +156,49 @@ type MaintenanceWindow struct {
 
 // Analyzer performs drift analysis on GCP Cloud SQL instances
 type Analyzer struct {
+	analyzer.ProjectErrors
+
 	service    *sqladmin.Service
 	lastReport *DriftReport
 	projects   []string
+	policy     *Policy
+	retry      retry.Policy
+	owners     DatabaseOwners
+	states     analyzer.OperationalSeverities
+	labels     *analyzer.LabelPolicy
+	warnings   io.Writer
 }
 
 // NewAnalyzer creates a new Analyzer instance with GCP API client
 func NewAnalyzer(ctx context.Context) (*Analyzer, error) {
-	service, err := sqladmin.NewService(ctx)
+	service, err := sqladmin.NewService(ctx, credentials.ClientOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SQL Admin client: %w", err)
 	}
 
-	return &Analyzer{service: service}, nil
+	return &Analyzer{service: service, retry: retry.DefaultPolicy()}, nil
+}
+
+// SetRetryPolicy controls how SQL Admin API calls are retried on rate
+// limits, quota and server errors
+func (a *Analyzer) SetRetryPolicy(p retry.Policy) {
+	a.retry = p
+}
+
+// SetWarnings sets where problems that do not stop discovery, such as the
+// databases of an instance that could not be listed, are written. The
+// default is stderr.
+func (a *Analyzer) SetWarnings(w io.Writer) {
+	a.warnings = w
+}
+
+// warnf writes a discovery warning
+func (a *Analyzer) warnf(format string, args ...any) {
+	w := a.warnings
+	if w == nil {
+		w = os.Stderr
+	}
+	fmt.Fprintf(w, format, args...)
 }
 
 // Close releases resources held by the Analyzer
@@ -121,13 +231,16 @@ func (a *Analyzer) GetDriftCount() int {
 	return a.lastReport.DriftedInstances
 }
 
-// DiscoverInstances finds all PostgreSQL instances across the specified GCP projects
+// DiscoverInstances finds all PostgreSQL and SQL Server instances across the specified GCP projects
 func (a *Analyzer) DiscoverInstances(ctx context.Context, projects []string) ([]*DatabaseInstance, error) {
 	var instances []*DatabaseInstance
 
 	for _, project := range projects {
 		projectInstances, err := a.discoverProjectInstances(ctx, project)
 		if err != nil {
+			if a.Skip(project, err) {
+				continue
+			}
 			return nil, fmt.Errorf("failed to discover instances in project %s: %w", project, err)
 		}
 		instances = append(instances, projectInstances...)
@@ -136,18 +249,25 @@ func (a *Analyzer) DiscoverInstances(ctx context.Context, projects []string) ([]
 	return instances, nil
 }
 
-// discoverProjectInstances lists all PostgreSQL instances in a single GCP project
+// discoverProjectInstances lists all supported instances in a single GCP project
 func (a *Analyzer) discoverProjectInstances(ctx context.Context, project string) ([]*DatabaseInstance, error) {
 	req := a.service.Instances.List(project)
-	resp, err := req.Context(ctx).Do()
+	resp, err := retry.Do(ctx, a.retry, req.Context(ctx).Do)
 	if err != nil {
 		return nil, err
 	}
 
+	// Replicas are listed as instances of their own; their regions place
+	// the replicas of each primary
+	regions := make(map[string]string, len(resp.Items))
+	for _, inst := range resp.Items {
+		regions[inst.Name] = inst.Region
+	}
+
 	var instances []*DatabaseInstance
 	for _, inst := range resp.Items {
-		// Filter for PostgreSQL only
-		if !isPostgreSQL(inst.DatabaseVersion) {
+		// Filter for supported engines (MySQL is not analyzed)
+		if !isPostgreSQL(inst.DatabaseVersion) && !isSQLServer(inst.DatabaseVersion) {
 			continue
 		}
 
@@ -156,20 +276,40 @@ func (a *Analyzer) discoverProjectInstances(ctx context.Context, project string)
 			Name:              inst.Name,
 			State:             inst.State,
 			Region:            inst.Region,
+			SuspensionReasons: inst.SuspensionReason,
 			Config:            extractConfig(inst),
 			MaintenanceWindow: extractMaintenanceWindow(inst),
 			Labels:            inst.Settings.UserLabels,
+			Primary:           inst.MasterInstanceName,
+			Replicas:          extractReplicas(inst, regions),
+		}
+		if ca := extractCertificate("server_ca", inst.ServerCaCert); ca != nil {
+			dbInstance.Certificates = append(dbInstance.Certificates, *ca)
 		}
 
 		// List databases in this instance
-		databases, err := a.listDatabases(ctx, project, inst.Name)
+		databases, err := a.listDatabases(ctx, project, inst.Name, inst.DatabaseVersion)
 		if err != nil {
 			// Log error but continue - database listing is not critical
-			fmt.Fprintf(os.Stderr, "Warning: Failed to list databases for %s: %v\n", inst.Name, err)
+			a.warnf("Warning: Failed to list databases for %s: %v\n", inst.Name, err)
 		} else {
 			dbInstance.Databases = databases
 		}
 
+		users, err := a.listUsers(ctx, project, inst.Name)
+		if err != nil {
+			a.warnf("Warning: Failed to list users for %s: %v\n", inst.Name, err)
+		} else {
+			dbInstance.Users = users
+		}
+
+		certs, err := a.listClientCertificates(ctx, project, inst.Name)
+		if err != nil {
+			a.warnf("Warning: Failed to list client certificates for %s: %v\n", inst.Name, err)
+		} else {
+			dbInstance.Certificates = append(dbInstance.Certificates, certs...)
+		}
+
 		instances = append(instances, dbInstance)
 	}
 
@@ -177,17 +317,16 @@ func (a *Analyzer) discoverProjectInstances(ctx context.Context, project string)
 }
 
 // listDatabases retrieves the list of databases in a Cloud SQL instance
-func (a *Analyzer) listDatabases(ctx context.Context, project, instance string) ([]string, error) {
+func (a *Analyzer) listDatabases(ctx context.Context, project, instance, version string) ([]string, error) {
 	req := a.service.Databases.List(project, instance)
-	resp, err := req.Context(ctx).Do()
+	resp, err := retry.Do(ctx, a.retry, req.Context(ctx).Do)
 	if err != nil {
 		return nil, err
 	}
 
 	databases := make([]string, 0)
 	for _, db := range resp.Items {
-		// Exclude template databases
-		if db.Name != "template0" && db.Name != "template1" {
+		if !isSystemDatabase(version, db.Name) {
 			databases = append(databases, db.Name)
 		}
 	}
@@ -195,11 +334,79 @@ func (a *Analyzer) listDatabases(ctx context.Context, project, instance string)
 	return databases, nil
 }
 
+// listUsers retrieves the database users of a Cloud SQL instance
+func (a *Analyzer) listUsers(ctx context.Context, project, instance string) ([]User, error) {
+	req := a.service.Users.List(project, instance)
+	resp, err := retry.Do(ctx, a.retry, req.Context(ctx).Do)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]User, 0, len(resp.Items))
+	for _, user := range resp.Items {
+		userType := user.Type
+		if userType == "" {
+			userType = "BUILT_IN"
+		}
+		users = append(users, User{Name: user.Name, Type: userType})
+	}
+
+	return users, nil
+}
+
+// listClientCertificates retrieves the client certificates of a Cloud SQL instance
+func (a *Analyzer) listClientCertificates(ctx context.Context, project, instance string) ([]Certificate, error) {
+	req := a.service.SslCerts.List(project, instance)
+	resp, err := retry.Do(ctx, a.retry, req.Context(ctx).Do)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []Certificate
+	for _, item := range resp.Items {
+		if cert := extractCertificate("client", item); cert != nil {
+			certs = append(certs, *cert)
+		}
+	}
+	return certs, nil
+}
+
+// extractCertificate converts an SSL certificate; certificates without a
+// valid expiration time are skipped
+func extractCertificate(kind string, cert *sqladmin.SslCert) *Certificate {
+	if cert == nil {
+		return nil
+	}
+	expires, err := time.Parse(time.RFC3339, cert.ExpirationTime)
+	if err != nil {
+		return nil
+	}
+	return &Certificate{Kind: kind, CommonName: cert.CommonName, Fingerprint: cert.Sha1Fingerprint, Expires: expires}
+}
+
 // isPostgreSQL checks if the database version string represents a PostgreSQL instance
 func isPostgreSQL(version string) bool {
 	return len(version) >= 8 && version[:8] == "POSTGRES"
 }
 
+// isSQLServer checks if the database version string represents a SQL Server instance
+func isSQLServer(version string) bool {
+	return strings.HasPrefix(version, "SQLSERVER")
+}
+
+// isSystemDatabase reports whether a database is created by the engine itself
+// and should not be treated as an application database
+func isSystemDatabase(version, name string) bool {
+	if isSQLServer(version) {
+		switch name {
+		case "master", "model", "msdb", "tempdb":
+			return true
+		}
+		return false
+	}
+	return name == "template0" || name == "template1"
+}
+
 // extractConfig extracts configuration parameters from a GCP database instance
 func extractConfig(inst *sqladmin.DatabaseInstance) *DatabaseConfig {
 	config := &DatabaseConfig{
@@ -241,6 +448,22 @@ func extractConfig(inst *sqladmin.DatabaseInstance) *DatabaseConfig {
 
 	if inst.Settings.LocationPreference != nil {
 		settings.LocationPreference = inst.Settings.LocationPreference.Zone
+		settings.SecondaryZone = inst.Settings.LocationPreference.SecondaryZone
+	}
+
+	// SQL Server specific settings
+	settings.Collation = inst.Settings.Collation
+	if inst.Settings.ActiveDirectoryConfig != nil {
+		settings.ActiveDirectory = &ActiveDirectoryConfig{
+			Domain: inst.Settings.ActiveDirectoryConfig.Domain,
+		}
+	}
+	if inst.Settings.SqlServerAuditConfig != nil {
+		settings.SQLServerAudit = &SQLServerAuditConfig{
+			Bucket:            inst.Settings.SqlServerAuditConfig.Bucket,
+			RetentionInterval: inst.Settings.SqlServerAuditConfig.RetentionInterval,
+			UploadInterval:    inst.Settings.SqlServerAuditConfig.UploadInterval,
+		}
 	}
 
 	// IP Configuration
@@ -297,12 +520,27 @@ func extractMaintenanceWindow(inst *sqladmin.DatabaseInstance) *MaintenanceWindo
 	}
 }
 
+// extractReplicas lists the read and failover replicas of an instance, placed
+// in their regions by instance name
+func extractReplicas(inst *sqladmin.DatabaseInstance, regions map[string]string) []Replica {
+	var replicas []Replica
+	for _, name := range inst.ReplicaNames {
+		replicas = append(replicas, Replica{Name: name, Region: regions[name], Type: "read"})
+	}
+	if inst.FailoverReplica != nil && inst.FailoverReplica.Name != "" {
+		name := inst.FailoverReplica.Name
+		replicas = append(replicas, Replica{Name: name, Region: regions[name], Type: "failover"})
+	}
+	return replicas
+}
+
 // AnalyzeDrift compares discovered instances against a baseline and generates a drift report
 func (a *Analyzer) AnalyzeDrift(instances []*DatabaseInstance, baseline *DatabaseConfig) *DriftReport {
 	report := &DriftReport{
 		Timestamp:      time.Now(),
 		TotalInstances: len(instances),
 		Instances:      make([]*InstanceDrift, 0),
+		Errors:         a.ScanErrors(),
 	}
 
 	for _, inst := range instances {
@@ -332,7 +570,11 @@ func (a *Analyzer) analyzeInstance(inst *DatabaseInstance, baseline *DatabaseCon
 		State:             inst.State,
 		Labels:            inst.Labels,
 		Databases:         inst.Databases,
+		Replicas:          inst.Replicas,
+		Users:             inst.Users,
+		Certificates:      inst.Certificates,
 		MaintenanceWindow: inst.MaintenanceWindow,
+		Inventory:         buildInventory(inst.Config),
 		Drifts:            make([]Drift, 0),
 		Recommendations:   make([]string, 0),
 	}
@@ -340,231 +582,89 @@ func (a *Analyzer) analyzeInstance(inst *DatabaseInstance, baseline *DatabaseCon
 	if baseline == nil {
 		// No baseline, provide recommendations based on best practices
 		drift.Recommendations = a.getBestPracticeRecommendations(inst)
+		a.applyPolicy(inst, drift)
+		a.checkLabelPolicy(inst, drift)
+		a.checkOperationalState(inst, drift)
 		return drift
 	}
 
 	// Compare with baseline - only check fields that are specified in baseline
-	if baseline.DatabaseVersion != "" && inst.Config.DatabaseVersion != baseline.DatabaseVersion {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "database_version",
-			Expected: baseline.DatabaseVersion,
-			Actual:   inst.Config.DatabaseVersion,
-			Severity: "medium",
-		})
-	}
-
-	if baseline.Tier != "" && inst.Config.Tier != baseline.Tier {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "tier",
-			Expected: baseline.Tier,
-			Actual:   inst.Config.Tier,
-			Severity: "high",
-		})
-	}
-
-	if baseline.DiskType != "" && inst.Config.DiskType != baseline.DiskType {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "disk_type",
-			Expected: baseline.DiskType,
-			Actual:   inst.Config.DiskType,
-			Severity: "medium",
-		})
-	}
-
-	// Check disk size if specified in baseline
-	if baseline.DiskSize > 0 && inst.Config.DiskSize != baseline.DiskSize {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "disk_size_gb",
-			Expected: fmt.Sprintf("%d", baseline.DiskSize),
-			Actual:   fmt.Sprintf("%d", inst.Config.DiskSize),
-			Severity: "medium",
-		})
-	}
-
-	// Only check disk autoresize if disk type is specified (indicating disk config matters)
-	if baseline.DiskType != "" && inst.Config.DiskAutoresize != baseline.DiskAutoresize {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "disk_autoresize",
-			Expected: fmt.Sprintf("%v", baseline.DiskAutoresize),
-			Actual:   fmt.Sprintf("%v", inst.Config.DiskAutoresize),
-			Severity: "low",
-		})
-	}
+	a.compareInstanceConfig(inst.Config, baseline, drift)
 
 	// Compare database flags
 	a.compareDatabaseFlags(inst.Config, baseline, drift)
 
 	// Compare settings
-	a.compareSettings(inst.Config.Settings, baseline.Settings, drift)
+	a.compareSettings(inst.Config.Settings, baseline.Settings, baseline.AllowedValues, baseline.Ranges, drift)
 
 	// Check required databases
 	a.checkRequiredDatabases(inst, baseline, drift)
 
-	// Generate recommendations
-	drift.Recommendations = a.getRecommendations(inst, baseline, drift)
+	// Check replica topology
+	a.compareReplicas(inst, baseline, drift)
 
-	return drift
-}
+	// Check database users and IAM authentication
+	a.compareUsers(inst, baseline, drift)
+	a.compareIAMAuthentication(inst, baseline, drift)
 
-// checkRequiredDatabases validates that required databases exist on the instance
-func (a *Analyzer) checkRequiredDatabases(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
-	if len(baseline.RequiredDatabases) == 0 {
-		return
-	}
+	// Check certificate expiry
+	a.compareCertificates(inst, baseline, drift, time.Now())
 
-	// Create a set of existing databases for quick lookup
-	existingDBs := make(map[string]bool)
-	for _, db := range inst.Databases {
-		existingDBs[db] = true
-	}
+	// Compare labels and maintenance window
+	a.compareLabels(inst, baseline, drift)
+	a.compareMaintenanceWindow(inst, baseline, drift)
 
-	// Check each required database
-	missingDatabases := make([]string, 0)
-	for _, requiredDB := range baseline.RequiredDatabases {
-		if !existingDBs[requiredDB] {
-			missingDatabases = append(missingDatabases, requiredDB)
-		}
-	}
+	// Apply the baseline's severity overrides; policy findings keep theirs
+	baseline.SeverityOverrides.Apply(drift.Drifts)
 
-	// Create a set of required databases for checking extras
-	requiredDBs := make(map[string]bool)
-	for _, db := range baseline.RequiredDatabases {
-		requiredDBs[db] = true
-	}
+	// Check organization-wide policy
+	a.applyPolicy(inst, drift)
+	a.checkLabelPolicy(inst, drift)
 
-	// Check for extra databases (in actual but not in required list)
-	extraDatabases := make([]string, 0)
-	for db := range existingDBs {
-		if !requiredDBs[db] {
-			extraDatabases = append(extraDatabases, db)
-		}
-	}
+	// Report unhealthy states apart from configuration drift
+	a.checkOperationalState(inst, drift)
 
-	// Report missing databases as drift
-	if len(missingDatabases) > 0 {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "required_databases",
-			Expected: fmt.Sprintf("%v", baseline.RequiredDatabases),
-			Actual:   fmt.Sprintf("Missing: %v", missingDatabases),
-			Severity: "high",
-		})
-	}
-
-	// Report extra databases as drift (lower severity)
-	if len(extraDatabases) > 0 {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "required_databases",
-			Expected: fmt.Sprintf("%v", baseline.RequiredDatabases),
-			Actual:   fmt.Sprintf("Extra: %v", extraDatabases),
-			Severity: "medium",
-		})
-	}
-}
-
-// compareDatabaseFlags compares database flags between actual and baseline configurations
-func (a *Analyzer) compareDatabaseFlags(config, baseline *DatabaseConfig, drift *InstanceDrift) {
-	for key, baselineValue := range baseline.DatabaseFlags {
-		actualValue, exists := config.DatabaseFlags[key]
-		if !exists {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("database_flags.%s", key),
-				Expected: baselineValue,
-				Actual:   "not set",
-				Severity: "medium",
-			})
-		} else if actualValue != baselineValue {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("database_flags.%s", key),
-				Expected: baselineValue,
-				Actual:   actualValue,
-				Severity: "medium",
-			})
-		}
-	}
+	// Generate recommendations
+	drift.Recommendations = a.getRecommendations(inst, baseline, drift)
 
-	// Check for extra flags not in baseline
-	for key, actualValue := range config.DatabaseFlags {
-		if _, exists := baseline.DatabaseFlags[key]; !exists {
-			drift.Drifts = append(drift.Drifts, Drift{
-				Field:    fmt.Sprintf("database_flags.%s", key),
-				Expected: "not set",
-				Actual:   actualValue,
-				Severity: "low",
-			})
-		}
-	}
+	return drift
 }
 
 // compareSettings compares runtime settings between actual and baseline configurations
-func (a *Analyzer) compareSettings(actual, baseline *Settings, drift *InstanceDrift) {
+func (a *Analyzer) compareSettings(actual, baseline *Settings, allowed checks.Allowed, ranges checks.Ranges, drift *InstanceDrift) {
 	if baseline == nil {
+		if len(allowed) == 0 {
+			return
+		}
+		// Acceptable values may be given without a settings block; only the
+		// value-set checks apply then
+		baseline = &Settings{}
+		a.compareAvailabilitySettings(actual, baseline, allowed, drift)
+		a.compareZonePlacement(actual, baseline, allowed, drift)
+		a.compareSQLServerSettings(actual, baseline, allowed, drift)
 		return
 	}
 
 	// Compare availability settings
-	a.compareAvailabilitySettings(actual, baseline, drift)
+	a.compareAvailabilitySettings(actual, baseline, allowed, drift)
+
+	// Compare zone placement
+	a.compareZonePlacement(actual, baseline, allowed, drift)
 
 	// Compare backup settings
-	a.compareBackupSettings(actual, baseline, drift)
+	a.compareBackupSettings(actual, baseline, ranges, drift)
 
 	// Compare IP configuration
 	a.compareIPConfig(actual, baseline, drift)
 
 	// Compare insights config
-	a.compareInsightsConfig(actual, baseline, drift)
-}
-
-// compareAuthorizedNetworks compares authorized network lists between baseline and actual
-func (a *Analyzer) compareAuthorizedNetworks(baseline, actual *IPConfiguration, drift *InstanceDrift) {
-	// Create sets for comparison
-	baselineNets := make(map[string]bool)
-	for _, net := range baseline.AuthorizedNetworks {
-		baselineNets[net] = true
-	}
+	a.compareInsightsConfig(actual, baseline, ranges, drift)
 
-	actualNets := make(map[string]bool)
-	for _, net := range actual.AuthorizedNetworks {
-		actualNets[net] = true
-	}
-
-	// Find required networks (in baseline but not in actual)
-	requiredNets := make([]string, 0)
-	for net := range baselineNets {
-		if !actualNets[net] {
-			requiredNets = append(requiredNets, net)
-		}
-	}
-
-	// Find extra networks (in actual but not in baseline)
-	extraNets := make([]string, 0)
-	for net := range actualNets {
-		if !baselineNets[net] {
-			extraNets = append(extraNets, net)
-		}
-	}
-
-	// Report required networks as high severity
-	if len(requiredNets) > 0 {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.ip_configuration.authorized_networks",
-			Expected: fmt.Sprintf("Required: %v", requiredNets),
-			Actual:   fmt.Sprintf("%v", actual.AuthorizedNetworks),
-			Severity: "high",
-		})
-	}
-
-	// Report extra networks as medium severity
-	if len(extraNets) > 0 {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.ip_configuration.authorized_networks",
-			Expected: fmt.Sprintf("%v", baseline.AuthorizedNetworks),
-			Actual:   fmt.Sprintf("Extra: %v", extraNets),
-			Severity: "medium",
-		})
-	}
+	// Compare SQL Server specific settings
+	a.compareSQLServerSettings(actual, baseline, allowed, drift)
 }
 
-// getBestPracticeRecommendations generates recommendations based on PostgreSQL best practices
+// getBestPracticeRecommendations generates recommendations based on Cloud SQL best practices
 func (a *Analyzer) getBestPracticeRecommendations(inst *DatabaseInstance) []string {
 	var recommendations []string
 
@@ -603,10 +703,15 @@ func (a *Analyzer) getBestPracticeRecommendations(inst *DatabaseInstance) []stri
 	}
 
 	// Version check (simplified)
-	if inst.Config.DatabaseVersion < "POSTGRES_14" {
+	if isPostgreSQL(inst.Config.DatabaseVersion) && inst.Config.DatabaseVersion < "POSTGRES_14" {
 		recommendations = append(recommendations, "MEDIUM: Consider upgrading to PostgreSQL 14+ for better performance and features")
 	}
 
+	// SQL Server audit logs
+	if isSQLServer(inst.Config.DatabaseVersion) && inst.Config.Settings.SQLServerAudit == nil {
+		recommendations = append(recommendations, "MEDIUM: Enable SQL Server audit log export to Cloud Storage")
+	}
+
 	// Maintenance window
 	if inst.MaintenanceWindow == nil {
 		recommendations = append(recommendations, "LOW: Set a maintenance window for predictable updates")
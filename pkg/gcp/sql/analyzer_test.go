@@ -2,7 +2,13 @@ package sql
 
 import (
 	"context"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"google.golang.org/api/sqladmin/v1"
 )
 
 func TestDatabaseConfig(t *testing.T) {
@@ -220,3 +226,587 @@ func TestAnalyzeInstance(t *testing.T) {
 		t.Errorf("Name = %v, want %v", drift.Name, inst.Name)
 	}
 }
+
+func TestAllowedValues(t *testing.T) {
+	a := &Analyzer{}
+
+	inst := &DatabaseInstance{
+		Name: "db-1",
+		Config: &DatabaseConfig{
+			DatabaseVersion: "POSTGRES_15",
+			Tier:            "db-custom-8-32768",
+			Settings:        &Settings{AvailabilityType: "ZONAL"},
+		},
+	}
+
+	baseline := &DatabaseConfig{
+		DatabaseVersion: "POSTGRES_15",
+		AllowedValues: checks.Allowed{
+			"tier":                       {"db-custom-4-16384", "db-custom-8-32768"},
+			"settings.availability_type": {"REGIONAL"},
+		},
+	}
+	if err := baseline.AllowedValues.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	drift := a.analyzeInstance(inst, baseline)
+	if len(drift.Drifts) != 1 {
+		t.Fatalf("expected one drift, got %+v", drift.Drifts)
+	}
+	got := drift.Drifts[0]
+	if got.Field != "settings.availability_type" || got.Expected != "one of [REGIONAL]" || got.Actual != "ZONAL" {
+		t.Errorf("drift = %+v", got)
+	}
+
+	if err := (checks.Allowed{"tierr": {"x"}}).Validate(); err == nil {
+		t.Error("Validate() should reject unknown fields")
+	}
+}
+
+func TestSeverityOverrides(t *testing.T) {
+	a := &Analyzer{}
+
+	inst := &DatabaseInstance{
+		Name: "db-1",
+		Config: &DatabaseConfig{
+			DatabaseVersion: "POSTGRES_15",
+			Tier:            "db-custom-2-8192",
+			Settings:        &Settings{AvailabilityType: "ZONAL"},
+		},
+	}
+
+	baseline := &DatabaseConfig{
+		DatabaseVersion:   "POSTGRES_15",
+		Tier:              "db-custom-4-16384",
+		Settings:          &Settings{AvailabilityType: "REGIONAL"},
+		SeverityOverrides: checks.SeverityOverrides{"tier": "critical"},
+	}
+	if err := (SQLBaseline{Name: "prod", Config: baseline}).Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	severities := make(map[string]string)
+	for _, d := range a.analyzeInstance(inst, baseline).Drifts {
+		severities[d.Field] = d.Severity
+	}
+	if severities["tier"] != "critical" {
+		t.Errorf("tier severity = %q, want the override critical", severities["tier"])
+	}
+	if severities["settings.availability_type"] != "high" {
+		t.Errorf("settings.availability_type severity = %q, want the built-in high", severities["settings.availability_type"])
+	}
+
+	invalid := SQLBaseline{Name: "dev", Config: &DatabaseConfig{SeverityOverrides: checks.SeverityOverrides{"tierr": "low"}}}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Validate() should reject unknown fields")
+	}
+}
+
+func TestIsSQLServer(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"SQLSERVER_2019_STANDARD", true},
+		{"SQLSERVER_2022_ENTERPRISE", true},
+		{"POSTGRES_15", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := isSQLServer(tt.version); got != tt.want {
+			t.Errorf("isSQLServer(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestIsSystemDatabase(t *testing.T) {
+	tests := []struct {
+		version string
+		name    string
+		want    bool
+	}{
+		{"SQLSERVER_2019_STANDARD", "msdb", true},
+		{"SQLSERVER_2019_STANDARD", "appdb", false},
+		{"POSTGRES_15", "template1", true},
+		{"POSTGRES_15", "msdb", false},
+	}
+
+	for _, tt := range tests {
+		if got := isSystemDatabase(tt.version, tt.name); got != tt.want {
+			t.Errorf("isSystemDatabase(%q, %q) = %v, want %v", tt.version, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompareZonePlacement(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &Settings{
+		LocationPreference: "us-central1-a",
+		SecondaryZone:      "us-central1-b",
+	}
+
+	tests := []struct {
+		name       string
+		actual     *Settings
+		wantFields []string
+	}{
+		{
+			name: "matches baseline",
+			actual: &Settings{
+				AvailabilityType:   "REGIONAL",
+				LocationPreference: "us-central1-a",
+				SecondaryZone:      "us-central1-b",
+			},
+		},
+		{
+			name: "wrong zones",
+			actual: &Settings{
+				AvailabilityType:   "ZONAL",
+				LocationPreference: "us-central1-c",
+			},
+			wantFields: []string{
+				"settings.location_preference",
+				"settings.secondary_zone",
+			},
+		},
+		{
+			name: "regional standby in primary zone",
+			actual: &Settings{
+				AvailabilityType:   "REGIONAL",
+				LocationPreference: "us-central1-b",
+				SecondaryZone:      "us-central1-b",
+			},
+			wantFields: []string{
+				"settings.location_preference",
+				"settings.secondary_zone",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := &InstanceDrift{}
+			a.compareZonePlacement(tt.actual, baseline, nil, drift)
+
+			if len(drift.Drifts) != len(tt.wantFields) {
+				t.Fatalf("got %d drifts, want %d: %+v", len(drift.Drifts), len(tt.wantFields), drift.Drifts)
+			}
+			for i, field := range tt.wantFields {
+				if drift.Drifts[i].Field != field {
+					t.Errorf("Drifts[%d].Field = %v, want %v", i, drift.Drifts[i].Field, field)
+				}
+			}
+		})
+	}
+
+	// Same-zone standby is flagged even when the baseline does not pin zones
+	drift := &InstanceDrift{}
+	a.compareZonePlacement(&Settings{
+		AvailabilityType:   "REGIONAL",
+		LocationPreference: "us-central1-a",
+		SecondaryZone:      "us-central1-a",
+	}, &Settings{}, nil, drift)
+	if len(drift.Drifts) != 1 || drift.Drifts[0].Severity != "high" {
+		t.Errorf("expected one high severity drift, got %+v", drift.Drifts)
+	}
+}
+
+func TestCompareLabelsAndMaintenanceWindow(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &DatabaseConfig{
+		Labels:            map[string]string{"team": "payments"},
+		MaintenanceWindow: &MaintenanceWindow{Day: 7, Hour: 0, UpdateTrack: "stable"},
+	}
+
+	tests := []struct {
+		name       string
+		inst       *DatabaseInstance
+		wantFields []string
+	}{
+		{
+			name: "matches baseline",
+			inst: &DatabaseInstance{
+				Labels:            map[string]string{"team": "payments", "env": "prod"},
+				MaintenanceWindow: &MaintenanceWindow{Day: 7, Hour: 0, UpdateTrack: "stable"},
+			},
+		},
+		{
+			name: "missing label and window",
+			inst: &DatabaseInstance{},
+			wantFields: []string{
+				"labels.team",
+				"settings.maintenance_window",
+			},
+		},
+		{
+			name: "wrong window",
+			inst: &DatabaseInstance{
+				Labels:            map[string]string{"team": "ledger"},
+				MaintenanceWindow: &MaintenanceWindow{Day: 1, Hour: 3, UpdateTrack: "canary"},
+			},
+			wantFields: []string{
+				"labels.team",
+				"settings.maintenance_window.day",
+				"settings.maintenance_window.hour",
+				"settings.maintenance_window.update_track",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := &InstanceDrift{}
+			a.compareLabels(tt.inst, baseline, drift)
+			a.compareMaintenanceWindow(tt.inst, baseline, drift)
+
+			if len(drift.Drifts) != len(tt.wantFields) {
+				t.Fatalf("got %d drifts, want %d: %+v", len(drift.Drifts), len(tt.wantFields), drift.Drifts)
+			}
+			for i, field := range tt.wantFields {
+				if drift.Drifts[i].Field != field {
+					t.Errorf("Drifts[%d].Field = %v, want %v", i, drift.Drifts[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareReplicas(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &DatabaseConfig{
+		RequiredReplicaCount: 2,
+		ReplicaRegions:       []string{"us-central1", "us-east1"},
+	}
+
+	tests := []struct {
+		name       string
+		inst       *DatabaseInstance
+		wantFields []string
+	}{
+		{
+			name: "matches baseline",
+			inst: &DatabaseInstance{Replicas: []Replica{
+				{Name: "db-replica-1", Region: "us-central1", Type: "read"},
+				{Name: "db-replica-2", Region: "us-east1", Type: "read"},
+			}},
+		},
+		{
+			name: "replica deleted",
+			inst: &DatabaseInstance{Replicas: []Replica{
+				{Name: "db-replica-1", Region: "us-central1", Type: "read"},
+			}},
+			wantFields: []string{"required_replica_count", "replica_regions[us-east1]"},
+		},
+		{
+			name: "replica in the wrong region",
+			inst: &DatabaseInstance{Replicas: []Replica{
+				{Name: "db-replica-1", Region: "us-central1", Type: "read"},
+				{Name: "db-replica-2", Region: "europe-west1", Type: "read"},
+			}},
+			wantFields: []string{"replicas[db-replica-2].region", "replica_regions[us-east1]"},
+		},
+		{
+			name: "replicas are not checked themselves",
+			inst: &DatabaseInstance{Primary: "prod:db-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := &InstanceDrift{}
+			a.compareReplicas(tt.inst, baseline, drift)
+
+			if len(drift.Drifts) != len(tt.wantFields) {
+				t.Fatalf("got %d drifts, want %d: %+v", len(drift.Drifts), len(tt.wantFields), drift.Drifts)
+			}
+			for i, field := range tt.wantFields {
+				if drift.Drifts[i].Field != field {
+					t.Errorf("Drifts[%d].Field = %v, want %v", i, drift.Drifts[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractReplicas(t *testing.T) {
+	inst := &sqladmin.DatabaseInstance{
+		ReplicaNames:    []string{"db-replica-1", "db-replica-2"},
+		FailoverReplica: &sqladmin.DatabaseInstanceFailoverReplica{Name: "db-failover"},
+	}
+	regions := map[string]string{"db-replica-1": "us-east1", "db-failover": "us-central1"}
+
+	got := extractReplicas(inst, regions)
+	want := []Replica{
+		{Name: "db-replica-1", Region: "us-east1", Type: "read"},
+		{Name: "db-replica-2", Type: "read"},
+		{Name: "db-failover", Region: "us-central1", Type: "failover"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractReplicas() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompareSQLServerSettings(t *testing.T) {
+	a := &Analyzer{}
+
+	baseline := &Settings{
+		Collation:       "SQL_Latin1_General_CP1_CI_AS",
+		ActiveDirectory: &ActiveDirectoryConfig{Domain: "corp.example.com"},
+		SQLServerAudit:  &SQLServerAuditConfig{Bucket: "gs://audit-logs"},
+	}
+
+	tests := []struct {
+		name       string
+		actual     *Settings
+		wantFields []string
+	}{
+		{
+			name: "matches baseline",
+			actual: &Settings{
+				Collation:       "SQL_Latin1_General_CP1_CI_AS",
+				ActiveDirectory: &ActiveDirectoryConfig{Domain: "corp.example.com"},
+				SQLServerAudit:  &SQLServerAuditConfig{Bucket: "gs://audit-logs"},
+			},
+		},
+		{
+			name:   "nothing configured",
+			actual: &Settings{Collation: "Latin1_General_100_CS_AS"},
+			wantFields: []string{
+				"settings.collation",
+				"settings.active_directory.domain",
+				"settings.sql_server_audit",
+			},
+		},
+		{
+			name: "wrong audit bucket",
+			actual: &Settings{
+				Collation:       "SQL_Latin1_General_CP1_CI_AS",
+				ActiveDirectory: &ActiveDirectoryConfig{Domain: "corp.example.com"},
+				SQLServerAudit:  &SQLServerAuditConfig{Bucket: "gs://other"},
+			},
+			wantFields: []string{"settings.sql_server_audit.bucket"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := &InstanceDrift{}
+			a.compareSQLServerSettings(tt.actual, baseline, nil, drift)
+
+			if len(drift.Drifts) != len(tt.wantFields) {
+				t.Fatalf("got %d drifts, want %d: %+v", len(drift.Drifts), len(tt.wantFields), drift.Drifts)
+			}
+			for i, field := range tt.wantFields {
+				if drift.Drifts[i].Field != field {
+					t.Errorf("Drifts[%d].Field = %v, want %v", i, drift.Drifts[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPolicy(t *testing.T) {
+	a := &Analyzer{}
+	a.SetPolicy(&Policy{RequireQueryInsights: true, MinQueryStringLength: 1024})
+
+	disabled := &DatabaseInstance{
+		Name:   "no-insights",
+		Config: &DatabaseConfig{Settings: &Settings{}},
+	}
+	enabled := &DatabaseInstance{
+		Name: "insights",
+		Config: &DatabaseConfig{Settings: &Settings{
+			InsightsConfig: &InsightsConfig{QueryInsightsEnabled: true, QueryStringLength: 4500},
+		}},
+	}
+
+	// Policy applies even without a baseline
+	drift := a.AnalyzeInstance(disabled, nil)
+	if len(drift.Drifts) != 2 {
+		t.Fatalf("got %d drifts, want 2: %+v", len(drift.Drifts), drift.Drifts)
+	}
+	if drift.Drifts[0].Field != "settings.insights_config.query_insights_enabled" {
+		t.Errorf("Drifts[0].Field = %v", drift.Drifts[0].Field)
+	}
+
+	if drift := a.AnalyzeInstance(enabled, nil); len(drift.Drifts) != 0 {
+		t.Errorf("compliant instance drifts = %+v, want none", drift.Drifts)
+	}
+
+	// A baseline drift on the same field is not reported twice
+	baseline := &DatabaseConfig{Settings: &Settings{
+		InsightsConfig: &InsightsConfig{QueryInsightsEnabled: true},
+	}}
+	withInsights := &DatabaseInstance{
+		Name: "off",
+		Config: &DatabaseConfig{Settings: &Settings{
+			InsightsConfig: &InsightsConfig{QueryStringLength: 4500},
+		}},
+	}
+	drift = a.AnalyzeInstance(withInsights, baseline)
+	count := 0
+	for _, d := range drift.Drifts {
+		if d.Field == "settings.insights_config.query_insights_enabled" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("query_insights_enabled reported %d times, want 1: %+v", count, drift.Drifts)
+	}
+}
+
+func TestCompareUsers(t *testing.T) {
+	a := &Analyzer{}
+	enabled := true
+	baseline := &DatabaseConfig{
+		RequiredUsers:     []string{"app", "drift-reader@prod.iam"},
+		ForbiddenUsers:    []string{"root", "test*"},
+		IAMAuthentication: &enabled,
+	}
+
+	tests := []struct {
+		name       string
+		inst       *DatabaseInstance
+		wantFields []string
+	}{
+		{
+			name: "matches baseline",
+			inst: &DatabaseInstance{
+				Config: &DatabaseConfig{DatabaseVersion: "POSTGRES_15", DatabaseFlags: map[string]string{"cloudsql.iam_authentication": "on"}},
+				Users:  []User{{Name: "app", Type: "BUILT_IN"}, {Name: "drift-reader@prod.iam", Type: "CLOUD_IAM_SERVICE_ACCOUNT"}},
+			},
+		},
+		{
+			name: "missing and forbidden users without IAM authentication",
+			inst: &DatabaseInstance{
+				Config: &DatabaseConfig{DatabaseVersion: "POSTGRES_15"},
+				Users:  []User{{Name: "app", Type: "BUILT_IN"}, {Name: "testuser", Type: "BUILT_IN"}},
+			},
+			wantFields: []string{"required_users[drift-reader@prod.iam]", "users[testuser]", "iam_authentication"},
+		},
+		{
+			name: "users not listed and SQL Server without IAM authentication",
+			inst: &DatabaseInstance{Config: &DatabaseConfig{DatabaseVersion: "SQLSERVER_2019_STANDARD"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drift := &InstanceDrift{}
+			a.compareUsers(tt.inst, baseline, drift)
+			a.compareIAMAuthentication(tt.inst, baseline, drift)
+
+			if len(drift.Drifts) != len(tt.wantFields) {
+				t.Fatalf("got %d drifts, want %d: %+v", len(drift.Drifts), len(tt.wantFields), drift.Drifts)
+			}
+			for i, field := range tt.wantFields {
+				if drift.Drifts[i].Field != field {
+					t.Errorf("Drifts[%d].Field = %v, want %v", i, drift.Drifts[i].Field, field)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareCertificates(t *testing.T) {
+	a := &Analyzer{}
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	inst := &DatabaseInstance{Certificates: []Certificate{
+		{Kind: "server_ca", CommonName: "C=US,O=Google\\, Inc,CN=Google Cloud SQL Server CA", Expires: now.AddDate(0, 0, 10)},
+		{Kind: "client", CommonName: "app", Expires: now.AddDate(1, 0, 0)},
+		{Kind: "client", CommonName: "batch", Expires: now.AddDate(0, 0, 29)},
+		{Kind: "client", CommonName: "legacy", Expires: now.AddDate(0, 0, -3)},
+	}}
+
+	drift := &InstanceDrift{}
+	a.compareCertificates(inst, &DatabaseConfig{CertExpiryDays: 30}, drift, now)
+
+	want := []struct{ field, severity, actual string }{
+		{"ssl.server_ca_cert", "high", "expires 2026-10-11 (in 10 days)"},
+		{"ssl.client_certs[batch]", "medium", "expires 2026-10-30 (in 29 days)"},
+		{"ssl.expired[legacy]", "critical", "expired 2026-09-28"},
+	}
+	if len(drift.Drifts) != len(want) {
+		t.Fatalf("got %d drifts, want %d: %+v", len(drift.Drifts), len(want), drift.Drifts)
+	}
+	for i, w := range want {
+		d := drift.Drifts[i]
+		if d.Field != w.field || d.Severity != w.severity || d.Actual != w.actual {
+			t.Errorf("Drifts[%d] = %s %s %q, want %s %s %q", i, d.Field, d.Severity, d.Actual, w.field, w.severity, w.actual)
+		}
+	}
+
+	// Without a window certificates are not checked
+	drift = &InstanceDrift{}
+	a.compareCertificates(inst, &DatabaseConfig{}, drift, now)
+	if len(drift.Drifts) != 0 {
+		t.Errorf("got %d drifts without cert_expiry_days", len(drift.Drifts))
+	}
+}
+
+func TestExtractCertificate(t *testing.T) {
+	cert := extractCertificate("client", &sqladmin.SslCert{CommonName: "app", Sha1Fingerprint: "ab12", ExpirationTime: "2027-01-02T03:04:05Z"})
+	want := &Certificate{Kind: "client", CommonName: "app", Fingerprint: "ab12", Expires: time.Date(2027, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if !reflect.DeepEqual(cert, want) {
+		t.Errorf("extractCertificate() = %+v, want %+v", cert, want)
+	}
+	if cert := extractCertificate("server_ca", &sqladmin.SslCert{}); cert != nil {
+		t.Errorf("extractCertificate() without expiration = %+v, want nil", cert)
+	}
+}
+
+func TestCheckOperationalState(t *testing.T) {
+	a := &Analyzer{}
+	baseline := &DatabaseConfig{Tier: "db-custom-2-7680"}
+	config := &DatabaseConfig{Tier: "db-custom-2-7680", Settings: &Settings{}}
+
+	inst := &DatabaseInstance{Name: "db-1", State: "SUSPENDED", SuspensionReasons: []string{"BILLING_ISSUE"}, Config: config}
+	drift := a.analyzeInstance(inst, baseline)
+	if len(drift.Drifts) != 1 {
+		t.Fatalf("got %d drifts, want the suspension: %+v", len(drift.Drifts), drift.Drifts)
+	}
+	if d := drift.Drifts[0]; d.Field != "state" || d.Severity != "high" || !d.Operational || d.Actual != "SUSPENDED (BILLING_ISSUE)" {
+		t.Errorf("drift = %+v", d)
+	}
+
+	// Configured severities override the defaults; ignore drops a state
+	a.SetOperationalSeverities(map[string]string{"SUSPENDED": "critical", "FAILED": "ignore"})
+	if drift := a.analyzeInstance(inst, baseline); drift.Drifts[0].Severity != "critical" {
+		t.Errorf("overridden severity = %s, want critical", drift.Drifts[0].Severity)
+	}
+	for _, state := range []string{"RUNNABLE", "FAILED"} {
+		inst := &DatabaseInstance{Name: "db-1", State: state, Config: config}
+		if drift := a.analyzeInstance(inst, baseline); len(drift.Drifts) != 0 {
+			t.Errorf("%s drifts = %+v, want none", state, drift.Drifts)
+		}
+	}
+}
+
+func TestCheckLabelPolicy(t *testing.T) {
+	a := &Analyzer{}
+	a.SetLabelPolicy(&analyzer.LabelPolicy{Required: []string{"team", "cost-center"}, Severity: "high"})
+	baseline := &DatabaseConfig{Labels: map[string]string{"team": "data"}}
+	config := &DatabaseConfig{Settings: &Settings{}}
+
+	inst := &DatabaseInstance{Name: "db-1", State: "RUNNABLE", Labels: map[string]string{"team": "web"}, Config: config}
+	drift := a.analyzeInstance(inst, baseline)
+	if len(drift.Drifts) != 2 {
+		t.Fatalf("got %d drifts, want the baseline label and the missing cost-center: %+v", len(drift.Drifts), drift.Drifts)
+	}
+	if d := drift.Drifts[0]; d.Field != "labels.team" || d.Severity != "low" {
+		t.Errorf("baseline label drift = %+v", d)
+	}
+	if d := drift.Drifts[1]; d.Field != "labels.cost-center" || d.Actual != "not set" || d.Severity != "high" {
+		t.Errorf("label policy drift = %+v", d)
+	}
+
+	// The policy applies to instances without a baseline too
+	if drift := a.analyzeInstance(inst, nil); len(drift.Drifts) != 1 {
+		t.Errorf("drifts without baseline = %+v, want the missing cost-center", drift.Drifts)
+	}
+}
@@ -0,0 +1,215 @@
+package sql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Anonymizer decides how identifiers appear in exported schema artifacts, so
+// DDL and JSON can be shared without revealing the data model
+type Anonymizer interface {
+	// Identifier returns the replacement for a table, column, view, sequence,
+	// index, constraint, function or procedure name, and whether to replace it
+	Identifier(name string) (string, bool)
+}
+
+// AnonymizeConfig configures the hashing anonymizer
+type AnonymizeConfig struct {
+	// Patterns are regular expressions matched against identifiers
+	Patterns []string `yaml:"patterns"`
+	// Salt is mixed into the hashes so common names can't be recovered by
+	// hashing guesses
+	Salt string `yaml:"salt,omitempty"`
+}
+
+// HashAnonymizer replaces identifiers matching any pattern with a stable
+// hash, e.g. patients becomes anon_3f2a9c1d7e4b. The same name always maps
+// to the same hash, so artifacts stay comparable across runs.
+type HashAnonymizer struct {
+	patterns []*regexp.Regexp
+	salt     string
+}
+
+// Compile-time interface implementation check
+var _ Anonymizer = (*HashAnonymizer)(nil)
+
+// NewHashAnonymizer compiles the configured patterns; it returns nil when
+// none are configured
+func NewHashAnonymizer(config AnonymizeConfig) (*HashAnonymizer, error) {
+	if len(config.Patterns) == 0 {
+		return nil, nil
+	}
+	a := &HashAnonymizer{salt: config.Salt}
+	for _, pattern := range config.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid anonymize pattern %q: %w", pattern, err)
+		}
+		a.patterns = append(a.patterns, re)
+	}
+	return a, nil
+}
+
+// Identifier hashes names matching any pattern
+func (a *HashAnonymizer) Identifier(name string) (string, bool) {
+	for _, re := range a.patterns {
+		if re.MatchString(name) {
+			sum := sha256.Sum256([]byte(a.salt + name))
+			return "anon_" + hex.EncodeToString(sum[:6]), true
+		}
+	}
+	return name, false
+}
+
+// Anonymized returns a copy of the schema with identifiers replaced by the
+// anonymizer. References in definitions and column defaults are rewritten
+// too, so the DDL stays consistent. The schema itself is left unchanged.
+func (schema *DatabaseSchema) Anonymized(a Anonymizer) *DatabaseSchema {
+	r := newRenamer(a, schema)
+	out := *schema
+
+	out.Tables = make([]TableInfo, len(schema.Tables))
+	for i, table := range schema.Tables {
+		t := table
+		t.Name = r.name(table.Name)
+		t.Columns = make([]ColumnInfo, len(table.Columns))
+		for j, col := range table.Columns {
+			c := col
+			c.Name = r.name(col.Name)
+			if col.DefaultValue != nil {
+				value := r.text(*col.DefaultValue)
+				c.DefaultValue = &value
+			}
+			t.Columns[j] = c
+		}
+		t.Constraints = make([]ConstraintInfo, len(table.Constraints))
+		for j, constraint := range table.Constraints {
+			c := constraint
+			c.Name = r.name(constraint.Name)
+			c.Definition = r.text(constraint.Definition)
+			t.Constraints[j] = c
+		}
+		t.Indexes = make([]IndexInfo, len(table.Indexes))
+		for j, index := range table.Indexes {
+			idx := index
+			idx.Name = r.name(index.Name)
+			idx.Definition = r.text(index.Definition)
+			idx.Columns = make([]string, len(index.Columns))
+			for k, col := range index.Columns {
+				idx.Columns[k] = r.name(col)
+			}
+			t.Indexes[j] = idx
+		}
+		out.Tables[i] = t
+	}
+
+	out.Views = make([]ViewInfo, len(schema.Views))
+	for i, view := range schema.Views {
+		v := view
+		v.Name = r.name(view.Name)
+		v.Definition = r.text(view.Definition)
+		out.Views[i] = v
+	}
+	out.Sequences = make([]SequenceInfo, len(schema.Sequences))
+	for i, seq := range schema.Sequences {
+		s := seq
+		s.Name = r.name(seq.Name)
+		out.Sequences[i] = s
+	}
+	out.Functions = make([]FunctionInfo, len(schema.Functions))
+	for i, fn := range schema.Functions {
+		f := fn
+		f.Name = r.name(fn.Name)
+		f.Definition = r.text(fn.Definition)
+		out.Functions[i] = f
+	}
+	out.Procedures = make([]ProcedureInfo, len(schema.Procedures))
+	for i, proc := range schema.Procedures {
+		p := proc
+		p.Name = r.name(proc.Name)
+		p.Definition = r.text(proc.Definition)
+		out.Procedures[i] = p
+	}
+	return &out
+}
+
+// renamer maps identifiers to their replacement, in names and in SQL text
+type renamer struct {
+	renamed map[string]string
+	// words matches any renamed identifier as a whole word
+	words *regexp.Regexp
+}
+
+// newRenamer asks the anonymizer about every identifier of the schema up
+// front, so SQL text can be rewritten in a single pass
+func newRenamer(a Anonymizer, schema *DatabaseSchema) *renamer {
+	var names []string
+	for _, table := range schema.Tables {
+		names = append(names, table.Name)
+		for _, col := range table.Columns {
+			names = append(names, col.Name)
+		}
+		for _, constraint := range table.Constraints {
+			names = append(names, constraint.Name)
+		}
+		for _, index := range table.Indexes {
+			names = append(names, index.Name)
+		}
+	}
+	for _, view := range schema.Views {
+		names = append(names, view.Name)
+	}
+	for _, seq := range schema.Sequences {
+		names = append(names, seq.Name)
+	}
+	for _, fn := range schema.Functions {
+		names = append(names, fn.Name)
+	}
+	for _, proc := range schema.Procedures {
+		names = append(names, proc.Name)
+	}
+
+	r := &renamer{renamed: make(map[string]string)}
+	for _, name := range names {
+		if replacement, ok := a.Identifier(name); ok && replacement != name {
+			r.renamed[name] = replacement
+		}
+	}
+	if len(r.renamed) == 0 {
+		return r
+	}
+
+	// Longest first, so a name is not matched by a shorter name it contains
+	quoted := make([]string, 0, len(r.renamed))
+	for name := range r.renamed {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	sort.Slice(quoted, func(i, j int) bool {
+		if len(quoted[i]) != len(quoted[j]) {
+			return len(quoted[i]) > len(quoted[j])
+		}
+		return quoted[i] < quoted[j]
+	})
+	r.words = regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b`)
+	return r
+}
+
+// name returns the replacement of an identifier
+func (r *renamer) name(name string) string {
+	if replacement, ok := r.renamed[name]; ok {
+		return replacement
+	}
+	return name
+}
+
+// text rewrites every renamed identifier appearing in SQL text
+func (r *renamer) text(sql string) string {
+	if r.words == nil {
+		return sql
+	}
+	return r.words.ReplaceAllStringFunc(sql, r.name)
+}
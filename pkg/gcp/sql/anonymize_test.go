@@ -0,0 +1,93 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaAnonymized(t *testing.T) {
+	a, err := NewHashAnonymizer(AnonymizeConfig{Patterns: []string{"^patient", "ssn"}, Salt: "s"})
+	if err != nil {
+		t.Fatalf("NewHashAnonymizer() error = %v", err)
+	}
+	patients, _ := a.Identifier("patients")
+	ssn, _ := a.Identifier("ssn")
+
+	idDefault := "nextval('patients_id_seq'::regclass)"
+	schema := &DatabaseSchema{
+		Tables: []TableInfo{{
+			Schema: "public",
+			Name:   "patients",
+			Columns: []ColumnInfo{
+				{Name: "id", DefaultValue: &idDefault},
+				{Name: "ssn"},
+			},
+			Constraints: []ConstraintInfo{{Name: "patients_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"}},
+			Indexes: []IndexInfo{{
+				Name:       "idx_ssn",
+				Columns:    []string{"ssn"},
+				Definition: "CREATE INDEX idx_ssn ON public.patients USING btree (ssn)",
+			}},
+		}},
+		Sequences: []SequenceInfo{{Schema: "public", Name: "patients_id_seq"}},
+		Views:     []ViewInfo{{Schema: "public", Name: "adults", Definition: "SELECT patients.id, patients.ssn FROM patients"}},
+	}
+
+	got := schema.Anonymized(a)
+
+	table := got.Tables[0]
+	if table.Name != patients || !strings.HasPrefix(patients, "anon_") {
+		t.Errorf("table name = %q, want %q", table.Name, patients)
+	}
+	if table.Columns[0].Name != "id" || table.Columns[1].Name != ssn {
+		t.Errorf("columns = %q, %q", table.Columns[0].Name, table.Columns[1].Name)
+	}
+	if *table.Columns[0].DefaultValue != "nextval('"+mustIdentifier(t, a, "patients_id_seq")+"'::regclass)" {
+		t.Errorf("default = %q", *table.Columns[0].DefaultValue)
+	}
+	idxSSN := mustIdentifier(t, a, "idx_ssn")
+	if table.Indexes[0].Name != idxSSN || table.Indexes[0].Columns[0] != ssn {
+		t.Errorf("index = %+v", table.Indexes[0])
+	}
+	if want := "CREATE INDEX " + idxSSN + " ON public." + patients + " USING btree (" + ssn + ")"; table.Indexes[0].Definition != want {
+		t.Errorf("index definition = %q, want %q", table.Indexes[0].Definition, want)
+	}
+	if want := "SELECT " + patients + ".id, " + patients + "." + ssn + " FROM " + patients; got.Views[0].Definition != want {
+		t.Errorf("view definition = %q, want %q", got.Views[0].Definition, want)
+	}
+	if table.Constraints[0].Name == "patients_pkey" {
+		t.Errorf("constraint name not anonymized")
+	}
+
+	// The inspected schema is left as it was
+	if schema.Tables[0].Name != "patients" || *schema.Tables[0].Columns[0].DefaultValue != idDefault {
+		t.Errorf("original schema modified: %+v", schema.Tables[0])
+	}
+}
+
+func mustIdentifier(t *testing.T, a Anonymizer, name string) string {
+	t.Helper()
+	replacement, ok := a.Identifier(name)
+	if !ok {
+		t.Fatalf("%s not anonymized", name)
+	}
+	return replacement
+}
+
+func TestNewHashAnonymizer(t *testing.T) {
+	if a, err := NewHashAnonymizer(AnonymizeConfig{}); a != nil || err != nil {
+		t.Errorf("NewHashAnonymizer() without patterns = %v, %v; want nil", a, err)
+	}
+	if _, err := NewHashAnonymizer(AnonymizeConfig{Patterns: []string{"(["}}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+
+	a, _ := NewHashAnonymizer(AnonymizeConfig{Patterns: []string{"salary"}})
+	salted, _ := NewHashAnonymizer(AnonymizeConfig{Patterns: []string{"salary"}, Salt: "x"})
+	first, _ := a.Identifier("salary")
+	second, _ := a.Identifier("salary")
+	other, _ := salted.Identifier("salary")
+	if first != second || first == other {
+		t.Errorf("hashes = %q, %q, salted %q; want stable and salt-dependent", first, second, other)
+	}
+}
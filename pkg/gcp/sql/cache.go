@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -42,6 +43,18 @@ func NewSchemaCache(cacheDir string) (*SchemaCache, error) {
 
 // Save stores a database schema to local cache
 func (sc *SchemaCache) Save(connectionName string, database string, schema *DatabaseSchema) error {
+	path, err := sc.Write(connectionName, database, schema)
+	if err != nil {
+		return err
+	}
+	
+	fmt.Printf("Cached schema to: %s\n", path)
+	return nil
+}
+
+// Write stores a database schema in the cache without printing and returns
+// the cache file path
+func (sc *SchemaCache) Write(connectionName string, database string, schema *DatabaseSchema) (string, error) {
 	cached := &CachedSchema{
 		ConnectionName: connectionName,
 		Database:       database,
@@ -55,15 +68,14 @@ func (sc *SchemaCache) Save(connectionName string, database string, schema *Data
 	// Save as JSON for better performance
 	data, err := json.MarshalIndent(cached, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal schema: %w", err)
+		return "", fmt.Errorf("failed to marshal schema: %w", err)
 	}
 	
 	if err := os.WriteFile(filepath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write cache file: %w", err)
+		return "", fmt.Errorf("failed to write cache file: %w", err)
 	}
 	
-	fmt.Printf("Cached schema to: %s\n", filepath)
-	return nil
+	return filepath, nil
 }
 
 // Load retrieves a cached database schema
@@ -204,8 +216,7 @@ func (sc *SchemaCache) GetCacheDir() string {
 // CompareSchemas compares two schemas and returns differences
 func CompareSchemas(old *DatabaseSchema, new *DatabaseSchema) *SchemaDiff {
 	diff := &SchemaDiff{
-		OldTimestamp: old.DatabaseName,
-		NewTimestamp: new.DatabaseName,
+		Database: new.DatabaseName,
 	}
 	
 	// Compare tables
@@ -215,56 +226,77 @@ func CompareSchemas(old *DatabaseSchema, new *DatabaseSchema) *SchemaDiff {
 		oldTables[key] = t
 	}
 	
-	newTables := make(map[string]TableInfo)
-	for _, t := range new.Tables {
-		key := fmt.Sprintf("%s.%s", t.Schema, t.Name)
-		newTables[key] = t
-	}
-	
-	// Find added and modified tables
-	for key, newTable := range newTables {
+	newTables := make(map[string]bool)
+	for _, newTable := range new.Tables {
+		key := fmt.Sprintf("%s.%s", newTable.Schema, newTable.Name)
+		newTables[key] = true
 		if oldTable, exists := oldTables[key]; !exists {
 			diff.AddedTables = append(diff.AddedTables, newTable)
-		} else {
-			// Compare columns count as a simple diff indicator
-			if len(oldTable.Columns) != len(newTable.Columns) {
-				diff.ModifiedTables = append(diff.ModifiedTables, newTable)
-			}
+		} else if td := compareTable(oldTable, newTable); td != nil {
+			diff.ModifiedTables = append(diff.ModifiedTables, *td)
 		}
 	}
 	
 	// Find deleted tables
-	for key, oldTable := range oldTables {
-		if _, exists := newTables[key]; !exists {
+	for _, oldTable := range old.Tables {
+		if !newTables[fmt.Sprintf("%s.%s", oldTable.Schema, oldTable.Name)] {
 			diff.DeletedTables = append(diff.DeletedTables, oldTable)
 		}
 	}
 	
-	// Similar logic for views, roles, extensions
+	// Similar logic for views, routines, roles, extensions
 	diff.compareViews(old.Views, new.Views)
+	diff.compareSequences(old.Sequences, new.Sequences)
+	diff.compareFunctions(old.Functions, new.Functions)
+	diff.compareProcedures(old.Procedures, new.Procedures)
 	diff.compareRoles(old.Roles, new.Roles)
 	diff.compareExtensions(old.Extensions, new.Extensions)
+	diff.compareSettings(old.Settings, new.Settings)
+	diff.compareOwners(old, new)
 	
 	return diff
 }
 
 // SchemaDiff represents differences between two database schemas
 type SchemaDiff struct {
+	// Database is the name of the database the new schema was read from
+	Database string `json:"database,omitempty" yaml:"database,omitempty"`
+
 	OldTimestamp string `json:"old_timestamp" yaml:"old_timestamp"`
 	NewTimestamp string `json:"new_timestamp" yaml:"new_timestamp"`
 	
-	AddedTables    []TableInfo `json:"added_tables,omitempty" yaml:"added_tables,omitempty"`
-	DeletedTables  []TableInfo `json:"deleted_tables,omitempty" yaml:"deleted_tables,omitempty"`
-	ModifiedTables []TableInfo `json:"modified_tables,omitempty" yaml:"modified_tables,omitempty"`
+	AddedTables   []TableInfo `json:"added_tables,omitempty" yaml:"added_tables,omitempty"`
+	DeletedTables []TableInfo `json:"deleted_tables,omitempty" yaml:"deleted_tables,omitempty"`
+	// ModifiedTables holds the column, index, constraint and storage
+	// parameter changes of tables in both schemas
+	ModifiedTables []TableDiff `json:"modified_tables,omitempty" yaml:"modified_tables,omitempty"`
 	
 	AddedViews    []ViewInfo `json:"added_views,omitempty" yaml:"added_views,omitempty"`
 	DeletedViews  []ViewInfo `json:"deleted_views,omitempty" yaml:"deleted_views,omitempty"`
+
+	AddedSequences    []SequenceInfo   `json:"added_sequences,omitempty" yaml:"added_sequences,omitempty"`
+	DeletedSequences  []SequenceInfo   `json:"deleted_sequences,omitempty" yaml:"deleted_sequences,omitempty"`
+	ModifiedSequences []SequenceChange `json:"modified_sequences,omitempty" yaml:"modified_sequences,omitempty"`
+
+	AddedFunctions    []FunctionInfo   `json:"added_functions,omitempty" yaml:"added_functions,omitempty"`
+	DeletedFunctions  []FunctionInfo   `json:"deleted_functions,omitempty" yaml:"deleted_functions,omitempty"`
+	ModifiedFunctions []FunctionChange `json:"modified_functions,omitempty" yaml:"modified_functions,omitempty"`
+
+	AddedProcedures    []ProcedureInfo   `json:"added_procedures,omitempty" yaml:"added_procedures,omitempty"`
+	DeletedProcedures  []ProcedureInfo   `json:"deleted_procedures,omitempty" yaml:"deleted_procedures,omitempty"`
+	ModifiedProcedures []ProcedureChange `json:"modified_procedures,omitempty" yaml:"modified_procedures,omitempty"`
 	
 	AddedRoles   []string `json:"added_roles,omitempty" yaml:"added_roles,omitempty"`
 	DeletedRoles []string `json:"deleted_roles,omitempty" yaml:"deleted_roles,omitempty"`
 	
 	AddedExtensions   []Extension `json:"added_extensions,omitempty" yaml:"added_extensions,omitempty"`
 	DeletedExtensions []Extension `json:"deleted_extensions,omitempty" yaml:"deleted_extensions,omitempty"`
+
+	AddedSettings   []DatabaseSetting `json:"added_settings,omitempty" yaml:"added_settings,omitempty"`
+	DeletedSettings []DatabaseSetting `json:"deleted_settings,omitempty" yaml:"deleted_settings,omitempty"`
+	ChangedSettings []SettingChange   `json:"changed_settings,omitempty" yaml:"changed_settings,omitempty"`
+
+	ChangedOwners []OwnerChange `json:"changed_owners,omitempty" yaml:"changed_owners,omitempty"`
 }
 
 func (sd *SchemaDiff) compareViews(old []ViewInfo, new []ViewInfo) {
@@ -341,10 +373,46 @@ func (sd *SchemaDiff) compareExtensions(old []Extension, new []Extension) {
 	}
 }
 
+// compareSettings diffs setting overrides keyed by role and name; changed
+// settings carry the new value and previous settings the old one
+func (sd *SchemaDiff) compareSettings(old []DatabaseSetting, new []DatabaseSetting) {
+	key := func(s DatabaseSetting) string {
+		return s.Role + "/" + strings.ToLower(s.Name)
+	}
+
+	oldSettings := make(map[string]DatabaseSetting)
+	for _, s := range old {
+		oldSettings[key(s)] = s
+	}
+
+	newSettings := make(map[string]bool)
+	for _, s := range new {
+		newSettings[key(s)] = true
+		oldSetting, exists := oldSettings[key(s)]
+		switch {
+		case !exists:
+			sd.AddedSettings = append(sd.AddedSettings, s)
+		case oldSetting.Value != s.Value:
+			sd.ChangedSettings = append(sd.ChangedSettings, SettingChange{Name: s.Name, Old: oldSetting, New: s})
+		}
+	}
+
+	for _, s := range old {
+		if !newSettings[key(s)] {
+			sd.DeletedSettings = append(sd.DeletedSettings, s)
+		}
+	}
+}
+
 // HasChanges returns true if there are any differences
 func (sd *SchemaDiff) HasChanges() bool {
 	return len(sd.AddedTables) > 0 || len(sd.DeletedTables) > 0 || len(sd.ModifiedTables) > 0 ||
 		len(sd.AddedViews) > 0 || len(sd.DeletedViews) > 0 ||
+		len(sd.AddedSequences) > 0 || len(sd.DeletedSequences) > 0 || len(sd.ModifiedSequences) > 0 ||
+		len(sd.AddedFunctions) > 0 || len(sd.DeletedFunctions) > 0 || len(sd.ModifiedFunctions) > 0 ||
+		len(sd.AddedProcedures) > 0 || len(sd.DeletedProcedures) > 0 || len(sd.ModifiedProcedures) > 0 ||
 		len(sd.AddedRoles) > 0 || len(sd.DeletedRoles) > 0 ||
-		len(sd.AddedExtensions) > 0 || len(sd.DeletedExtensions) > 0
+		len(sd.AddedExtensions) > 0 || len(sd.DeletedExtensions) > 0 ||
+		len(sd.AddedSettings) > 0 || len(sd.DeletedSettings) > 0 || len(sd.ChangedSettings) > 0 ||
+		len(sd.ChangedOwners) > 0
 }
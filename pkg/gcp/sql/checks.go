@@ -0,0 +1,214 @@
+package sql
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/checks"
+
+// resourceType is the resource type reported for Cloud SQL checks
+const resourceType = "Cloud SQL"
+
+// terraformInstance is the Terraform Google provider resource of an instance
+const terraformInstance = "google_sql_database_instance"
+
+func register(id, path, severity, description, remediation string) *checks.Check {
+	return checks.Register(checks.Check{
+		ID:           "sql." + id,
+		ResourceType: resourceType,
+		Path:         path,
+		Severity:     severity,
+		Description:  description,
+		Remediation:  remediation,
+	})
+}
+
+// registerRestart registers a check whose fix restarts the instance
+func registerRestart(id, path, severity, description, remediation string) *checks.Check {
+	return checks.Register(checks.Check{
+		ID:              "sql." + id,
+		ResourceType:    resourceType,
+		Path:            path,
+		Severity:        severity,
+		Description:     description,
+		Remediation:     remediation,
+		RequiresRestart: true,
+	})
+}
+
+// Instance checks
+var (
+	checkDatabaseVersion = registerRestart("database_version", "database_version", "medium", "Database engine and major version",
+		"Major version upgrades are in place but irreversible: gcloud sql instances patch INSTANCE --database-version=VERSION (test on a clone first)").WithTerraform(terraformInstance, "database_version")
+	checkTier = registerRestart("tier", "tier", "high", "Machine tier (vCPU and memory)",
+		"gcloud sql instances patch INSTANCE --tier=TIER (restarts the instance)").WithTerraform(terraformInstance, "settings.tier")
+	checkDiskType = register("disk_type", "disk_type", "medium", "Storage type (PD_SSD or PD_HDD)",
+		"Storage type cannot be changed in place; clone or restore a backup into a new instance with the baseline disk type").WithTerraform(terraformInstance, "settings.disk_type")
+	checkDiskSize = register("disk_size_gb", "disk_size_gb", "medium", "Provisioned storage size in GB",
+		"gcloud sql instances patch INSTANCE --storage-size=SIZE (storage can only grow)").WithTerraform(terraformInstance, "settings.disk_size")
+	checkDiskAutoresize = register("disk_autoresize", "disk_autoresize", "low", "Automatic storage increase (checked when disk_type is set)",
+		"gcloud sql instances patch INSTANCE --storage-auto-increase").WithTerraform(terraformInstance, "settings.disk_autoresize")
+	checkFlag = registerRestart("database_flags", "database_flags.*", "medium", "Database flag missing or set to a different value",
+		"gcloud sql instances patch INSTANCE --database-flags=FLAG=VALUE,... (the list replaces all flags; some flags restart the instance)")
+	checkExtraFlag = register("database_flags.extra", "database_flags.*", "low", "Database flag set on the instance but not in the baseline",
+		"Add the flag to the baseline or remove it with gcloud sql instances patch INSTANCE --database-flags=... listing only the baseline flags")
+	checkMissingDatabases = register("required_databases", "required_databases", "high", "Required databases missing from the instance",
+		"gcloud sql databases create DATABASE --instance=INSTANCE")
+	checkExtraDatabases = register("required_databases.extra", "required_databases", "medium", "Databases on the instance that are not in the required list",
+		"Add the database to required_databases or drop it after confirming it is unused")
+)
+
+// Backup checks
+var (
+	checkBackupEnabled = register("settings.backup_enabled", "settings.backup_enabled", "critical", "Automated backups enabled",
+		"gcloud sql instances patch INSTANCE --backup-start-time=HH:MM").WithTerraform(terraformInstance, "settings.backup_configuration.enabled")
+	checkPointInTimeRecovery = register("settings.point_in_time_recovery", "settings.point_in_time_recovery", "high", "Point-in-time recovery enabled",
+		"gcloud sql instances patch INSTANCE --enable-point-in-time-recovery").WithTerraform(terraformInstance, "settings.backup_configuration.point_in_time_recovery_enabled")
+	checkBackupRetention = register("settings.backup_retention_days", "settings.backup_retention_days", "medium", "Number of retained automated backups",
+		"gcloud sql instances patch INSTANCE --retained-backups-count=N").WithTerraform(terraformInstance, "settings.backup_configuration.backup_retention_settings.retained_backups")
+	checkTransactionLogRetain = register("settings.transaction_log_retention_days", "settings.transaction_log_retention_days", "medium", "Days of transaction logs retained for PITR",
+		"gcloud sql instances patch INSTANCE --retained-transaction-log-days=N").WithTerraform(terraformInstance, "settings.backup_configuration.transaction_log_retention_days")
+	checkBackupStartTime = register("settings.backup_start_time", "settings.backup_start_time", "low", "Backup window start time (UTC)",
+		"gcloud sql instances patch INSTANCE --backup-start-time=HH:MM").WithTerraform(terraformInstance, "settings.backup_configuration.start_time")
+)
+
+// Availability and placement checks
+var (
+	checkAvailabilityType = registerRestart("settings.availability_type", "settings.availability_type", "high", "ZONAL or REGIONAL (high availability)",
+		"gcloud sql instances patch INSTANCE --availability-type=REGIONAL|ZONAL (restarts the instance)").WithTerraform(terraformInstance, "settings.availability_type")
+	checkPricingPlan = register("settings.pricing_plan", "settings.pricing_plan", "low", "Pricing plan",
+		"gcloud sql instances patch INSTANCE --pricing-plan=PLAN").WithTerraform(terraformInstance, "settings.pricing_plan")
+	checkReplicationType = register("settings.replication_type", "settings.replication_type", "medium", "Replication type",
+		"gcloud sql instances patch INSTANCE --replication=SYNCHRONOUS|ASYNCHRONOUS")
+	checkPrimaryZone = registerRestart("settings.location_preference", "settings.location_preference", "medium", "Primary zone",
+		"gcloud sql instances patch INSTANCE --zone=ZONE").WithTerraform(terraformInstance, "settings.location_preference.zone")
+	checkSecondaryZone = register("settings.secondary_zone", "settings.secondary_zone", "medium", "Standby zone of a REGIONAL instance",
+		"gcloud sql instances patch INSTANCE --secondary-zone=ZONE").WithTerraform(terraformInstance, "settings.location_preference.secondary_zone")
+	checkDistinctZones = register("settings.secondary_zone.distinct", "settings.secondary_zone", "high", "REGIONAL standby placed in a different zone than the primary",
+		"Move the standby with gcloud sql instances patch INSTANCE --secondary-zone=ZONE so a zonal outage cannot take down both")
+)
+
+// Replica topology checks
+var (
+	checkReplicaCount = register("required_replica_count", "required_replica_count", "high", "Number of read and failover replicas of a primary instance",
+		"gcloud sql instances create REPLICA --master-instance-name=INSTANCE --region=REGION, or delete an extra replica with gcloud sql instances delete REPLICA")
+	checkReplicaRegion = register("replica_regions.allowed", "replicas[*].region", "high", "Replica running in a region outside replica_regions",
+		"Replicas cannot move; create one in an allowed region with gcloud sql instances create REPLICA --master-instance-name=INSTANCE --region=REGION, then delete the misplaced replica")
+	checkMissingReplicaRegion = register("replica_regions", "replica_regions[*]", "high", "Region in replica_regions without a replica",
+		"gcloud sql instances create REPLICA --master-instance-name=INSTANCE --region=REGION")
+)
+
+// User checks
+var (
+	checkRequiredUser = register("required_users", "required_users[*]", "high", "Required database user missing from the instance",
+		"gcloud sql users create USER --instance=INSTANCE, with --type=cloud_iam_user or cloud_iam_service_account for IAM users")
+	checkForbiddenUser = register("forbidden_users", "users[*]", "high", "Database user matching forbidden_users present on the instance",
+		"gcloud sql users delete USER --instance=INSTANCE after moving its workloads to an approved user")
+	checkIAMAuthentication = register("iam_authentication", "iam_authentication", "high", "IAM database authentication (cloudsql.iam_authentication flag)",
+		"gcloud sql instances patch INSTANCE --database-flags=cloudsql.iam_authentication=on; the flag list is replaced, so include the existing flags")
+)
+
+// Operational checks
+var (
+	checkState = register("state", "state", "critical", "Instance in an unhealthy state such as FAILED, SUSPENDED or PENDING_DELETE (operational)",
+		"Check the instance's operations with gcloud sql operations list --instance=INSTANCE; resolve billing or policy suspensions, or restore from backup")
+)
+
+// Label policy checks
+var (
+	checkLabelPolicy = register("label_policy", "labels.*", "medium", "Label required by the label_policy, with an allowed value (org policy)",
+		"gcloud sql instances patch INSTANCE --update-labels=KEY=VALUE").WithTerraform(terraformInstance, "settings.user_labels[\"*\"]")
+)
+
+// Certificate checks
+var (
+	checkServerCACert = register("ssl.server_ca_cert", "ssl.server_ca_cert", "high", "Server CA certificate expiring within cert_expiry_days",
+		"gcloud sql ssl server-ca-certs create --instance=INSTANCE, update clients with the new CA, then gcloud sql ssl server-ca-certs rotate --instance=INSTANCE")
+	checkClientCert = register("ssl.client_certs", "ssl.client_certs[*]", "medium", "Client certificate expiring within cert_expiry_days",
+		"gcloud sql ssl client-certs create NEW_CERT client-key.pem --instance=INSTANCE, move clients to it, then gcloud sql ssl client-certs delete CERT --instance=INSTANCE")
+	checkCertExpired = register("ssl.expired", "ssl.expired[*]", "critical", "Server CA or client certificate already expired",
+		"Rotate the server CA with gcloud sql ssl server-ca-certs rotate --instance=INSTANCE, or replace the client certificate with gcloud sql ssl client-certs create")
+)
+
+// Network checks
+var (
+	checkIPv4Enabled = register("settings.ip_configuration.ipv4_enabled", "settings.ip_configuration.ipv4_enabled", "medium", "Public IPv4 address enabled",
+		"gcloud sql instances patch INSTANCE --assign-ip or --no-assign-ip").WithTerraform(terraformInstance, "settings.ip_configuration.ipv4_enabled")
+	checkRequireSSL = register("settings.ip_configuration.require_ssl", "settings.ip_configuration.require_ssl", "critical", "SSL/TLS required for connections",
+		"gcloud sql instances patch INSTANCE --ssl-mode=ENCRYPTED_ONLY")
+	checkRequiredNetworks = register("settings.ip_configuration.authorized_networks", "settings.ip_configuration.authorized_networks", "high", "Required authorized networks missing",
+		"gcloud sql instances patch INSTANCE --authorized-networks=CIDR,... (the list replaces all networks)")
+	checkExtraNetworks = register("settings.ip_configuration.authorized_networks.extra", "settings.ip_configuration.authorized_networks", "medium", "Authorized networks not in the baseline",
+		"Remove the network with gcloud sql instances patch INSTANCE --authorized-networks=... or add it to the baseline")
+)
+
+// Label and maintenance checks
+var (
+	checkLabel = register("labels", "labels.*", "low", "Required instance label value",
+		"gcloud sql instances patch INSTANCE --update-labels=KEY=VALUE").WithTerraform(terraformInstance, "settings.user_labels[\"*\"]")
+	checkMaintenanceWindow = register("settings.maintenance_window", "settings.maintenance_window", "low", "Maintenance window configured",
+		"gcloud sql instances patch INSTANCE --maintenance-window-day=DAY --maintenance-window-hour=HOUR")
+	checkMaintenanceDay = register("settings.maintenance_window.day", "settings.maintenance_window.day", "low", "Maintenance window day (1=Monday ... 7=Sunday)",
+		"gcloud sql instances patch INSTANCE --maintenance-window-day=DAY").WithTerraform(terraformInstance, "settings.maintenance_window.day")
+	checkMaintenanceHour = register("settings.maintenance_window.hour", "settings.maintenance_window.hour", "low", "Maintenance window start hour (UTC)",
+		"gcloud sql instances patch INSTANCE --maintenance-window-hour=HOUR").WithTerraform(terraformInstance, "settings.maintenance_window.hour")
+	checkMaintenanceTrack = register("settings.maintenance_window.update_track", "settings.maintenance_window.update_track", "low", "Maintenance timing (canary, stable or week5)",
+		"gcloud sql instances patch INSTANCE --maintenance-release-channel=CHANNEL").WithTerraform(terraformInstance, "settings.maintenance_window.update_track")
+)
+
+// Observability checks
+var (
+	checkQueryInsights = register("settings.insights_config.query_insights_enabled", "settings.insights_config.query_insights_enabled", "low", "Query Insights enabled",
+		"gcloud sql instances patch INSTANCE --insights-config-query-insights-enabled").WithTerraform(terraformInstance, "settings.insights_config.query_insights_enabled")
+	checkQueryPlansPerMinute = register("settings.insights_config.query_plans_per_minute", "settings.insights_config.query_plans_per_minute", "low", "Query Insights sampled plans per minute",
+		"gcloud sql instances patch INSTANCE --insights-config-query-plans-per-minute=N").WithTerraform(terraformInstance, "settings.insights_config.query_plans_per_minute")
+	checkQueryStringLength = register("settings.insights_config.query_string_length", "settings.insights_config.query_string_length", "low", "Query Insights maximum query length",
+		"gcloud sql instances patch INSTANCE --insights-config-query-string-length=N").WithTerraform(terraformInstance, "settings.insights_config.query_string_length")
+)
+
+// Organization policy checks
+var (
+	checkPolicyQueryInsights = register("policy.require_query_insights", "settings.insights_config.query_insights_enabled", "medium", "Query Insights enabled on every instance (org policy)",
+		"gcloud sql instances patch INSTANCE --insights-config-query-insights-enabled").WithTerraform(terraformInstance, "settings.insights_config.query_insights_enabled")
+	checkPolicyApplicationTags = register("policy.require_record_application_tags", "settings.insights_config.record_application_tags", "low", "Application tags recorded on every instance (org policy)",
+		"gcloud sql instances patch INSTANCE --insights-config-record-application-tags").WithTerraform(terraformInstance, "settings.insights_config.record_application_tags")
+	checkPolicyQueryLength = register("policy.min_query_string_length", "settings.insights_config.query_string_length", "low", "Minimum Query Insights query length (org policy)",
+		"gcloud sql instances patch INSTANCE --insights-config-query-string-length=N")
+)
+
+// SQL Server checks
+var (
+	checkCollation = registerRestart("settings.collation", "settings.collation", "high", "SQL Server default collation",
+		"Collation is set at creation; recreate the instance or migrate the databases to one created with the baseline collation").WithTerraform(terraformInstance, "settings.collation")
+	checkActiveDirectory = register("settings.active_directory.domain", "settings.active_directory.domain", "high", "Managed Microsoft AD domain",
+		"gcloud sql instances patch INSTANCE --active-directory-domain=DOMAIN").WithTerraform(terraformInstance, "settings.active_directory_config.domain")
+	checkAudit = register("settings.sql_server_audit", "settings.sql_server_audit", "high", "SQL Server audit log export configured",
+		"gcloud sql instances patch INSTANCE --audit-bucket-path=gs://BUCKET")
+	checkAuditBucket = register("settings.sql_server_audit.bucket", "settings.sql_server_audit.bucket", "medium", "Audit log destination bucket",
+		"gcloud sql instances patch INSTANCE --audit-bucket-path=gs://BUCKET")
+	checkAuditRetention = register("settings.sql_server_audit.retention_interval", "settings.sql_server_audit.retention_interval", "medium", "Audit log retention interval",
+		"gcloud sql instances patch INSTANCE --audit-retention-interval=DURATION")
+	checkAuditUploadInterval = register("settings.sql_server_audit.upload_interval", "settings.sql_server_audit.upload_interval", "low", "Audit log upload interval",
+		"gcloud sql instances patch INSTANCE --audit-upload-interval=DURATION")
+)
+
+// Capacity checks compare the combined size of a project's instances with the
+// capacity envelope of their labels
+var (
+	checkCapacityVCPUs = register("capacity.max_vcpus", "capacity.max_vcpus", "medium", "Tier vCPUs per project within the capacity envelope",
+		"gcloud sql instances patch INSTANCE --tier=TIER with a smaller tier, or agree a higher cap")
+	checkCapacityMemory = register("capacity.max_memory_gb", "capacity.max_memory_gb", "medium", "Tier memory in GB per project within the capacity envelope",
+		"gcloud sql instances patch INSTANCE --tier=TIER with a smaller tier, or agree a higher cap")
+	checkCapacityDisk = register("capacity.max_disk_gb", "capacity.max_disk_gb", "medium", "Provisioned storage in GB per project within the capacity envelope",
+		"Storage cannot shrink; remove unused instances or agree a higher cap")
+)
+
+// Forecast checks are advisory: a metric recorded in history is projected to
+// reach its configured limit
+var (
+	checkForecastDisk = register("forecast.disk_gb", "forecast.disk_gb", "low", "Provisioned storage not projected to reach the forecast limit",
+		"Plan a storage increase or archive data before the projected date, or raise the limit")
+)
+
+// Uniqueness checks flag instances of a baseline sharing a name or label values
+// across projects beyond the baseline's unique rules
+var (
+	checkUnique = register("unique", "unique", "high", "No more instances share a name or label values than the baseline allows",
+		"Remove or relabel the duplicate, e.g. one created by a copy-pasted Terraform module")
+)
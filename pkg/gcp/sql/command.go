@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path"
 	"strings"
 
 	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/vault"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,6 +23,14 @@ type Command struct {
 	Format         string
 	FilterRole     string
 	GenerateConfig bool
+
+	// Labels selects resources carrying every label; FilterRole is the
+	// deprecated form of a single database-role label
+	Labels map[string]string
+
+	// GroupBy makes GenerateConfig emit one baseline per value of this label
+	// instead of a single baseline from the first instance
+	GroupBy string
 }
 
 // Config represents the YAML configuration file structure for SQL
@@ -39,7 +49,16 @@ type Config struct {
 type SQLBaseline struct {
 	Name         string            `yaml:"name,omitempty"`
 	FilterLabels map[string]string `yaml:"filter_labels,omitempty"`
-	Config       *DatabaseConfig   `yaml:"config"`
+	// InstanceNames and NamePattern scope the baseline to instances by name
+	InstanceNames []string        `yaml:"instance_names,omitempty"`
+	NamePattern   string          `yaml:"name_pattern,omitempty"`
+	Config        *DatabaseConfig `yaml:"config"`
+	// Metadata records when the baseline was last reviewed
+	Metadata analyzer.BaselineMetadata `yaml:"metadata,omitempty"`
+	// Capacity caps instance size per project and label; the first matching envelope applies
+	Capacity []CapacityEnvelope `yaml:"capacity,omitempty"`
+	// Unique limits how many instances may share a name or label values
+	Unique []analyzer.UniquenessRule `yaml:"unique,omitempty"`
 }
 
 // DatabaseConnection represents connection info for database schema inspection
@@ -63,6 +82,54 @@ type DatabaseConnection struct {
 	
 	// Schema baseline expectations for drift detection
 	SchemaBaseline *SchemaBaseline `yaml:"schema_baseline,omitempty"`
+	
+	// Auth is password (default) or iam. IAM database authentication logs in
+	// with an OAuth token of the tool's credentials through the Cloud SQL
+	// connector, so no password is stored; the username is the IAM user
+	// email or the service account email without .gserviceaccount.com.
+	Auth string `yaml:"auth,omitempty"`
+	
+	// Vault reads the password, and the username if the secret has one, from
+	// HashiCorp Vault when the database is inspected instead of the config
+	Vault *vault.Credentials `yaml:"vault,omitempty"`
+}
+
+// Database authentication methods
+const (
+	AuthPassword = "password"
+	AuthIAM      = "iam"
+)
+
+// validateCredentials checks the username, the authentication method and
+// where the password comes from
+func validateCredentials(auth, username, password string, secret *vault.Credentials) error {
+	// Vault may supply the username
+	if username == "" && secret == nil {
+		return fmt.Errorf("username is required")
+	}
+	
+	switch auth {
+	case "", AuthPassword:
+	case AuthIAM:
+		if password != "" {
+			return fmt.Errorf("password must not be set with auth: iam")
+		}
+		if secret != nil {
+			return fmt.Errorf("vault must not be set with auth: iam")
+		}
+	default:
+		return fmt.Errorf("unknown auth %q (password or iam)", auth)
+	}
+	
+	if secret != nil {
+		if password != "" {
+			return fmt.Errorf("password must not be set with vault")
+		}
+		if err := secret.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SchemaBaseline defines expected schema counts and specific objects
@@ -85,6 +152,26 @@ type SchemaBaseline struct {
 	
 	// Forbidden objects (must not exist)
 	ForbiddenTables []string `yaml:"forbidden_tables,omitempty"`
+
+	// Database-level setting overrides (ALTER DATABASE ... SET). Required
+	// settings must be overridden with the given value; forbidden settings must
+	// not be overridden for the database or any role in it ("*" forbids every
+	// override that is not required).
+	RequiredSettings  map[string]string `yaml:"required_settings,omitempty"`
+	ForbiddenSettings []string          `yaml:"forbidden_settings,omitempty"`
+
+	// Per-table storage parameters (fillfactor, autovacuum_*, toast.*) keyed by
+	// table ("schema.table" or bare name). Listed tables are pinned: any
+	// parameter set on the table but not listed is reported, and the value
+	// "default" requires the parameter to be unset.
+	TableStorageParams map[string]map[string]string `yaml:"table_storage_params,omitempty"`
+
+	// Full schema spec: column-level table definitions, required indexes and
+	// routine signatures, compared field by field. Spec is given inline;
+	// SpecFile is a spec YAML file, relative to the config file, usually
+	// checked into git next to it.
+	Spec     *SchemaSpec `yaml:"spec,omitempty"`
+	SpecFile string      `yaml:"spec_file,omitempty"`
 	
 	// Ownership validation
 	ExpectedDatabaseOwner string   `yaml:"expected_database_owner,omitempty"`    // e.g., "cloudsqlsuperuser"
@@ -115,6 +202,10 @@ type SSHTunnelConfig struct {
 	RemotePort   int    `yaml:"remote_port,omitempty"`     // Remote port (default: 5432)
 	UseIAP       bool   `yaml:"use_iap"`                   // Use Identity-Aware Proxy
 	SSHKeyExpiry string `yaml:"ssh_key_expiry,omitempty"`  // SSH key expiry (default: 1h)
+	
+	// Vault reads the SSH key pair for the bastion from a kv secret instead
+	// of using the gcloud key in ~/.ssh
+	Vault *vault.SSHKey `yaml:"vault,omitempty"`
 }
 
 // GetConnectionName returns the full instance connection name
@@ -145,8 +236,19 @@ func (dc *DatabaseConnection) Validate() error {
 		return fmt.Errorf("database name is required")
 	}
 	
-	if dc.Username == "" {
-		return fmt.Errorf("username is required")
+	if err := validateCredentials(dc.Auth, dc.Username, dc.Password, dc.Vault); err != nil {
+		return err
+	}
+	
+	// IAM authentication needs the Cloud SQL connector, which the tunnel bypasses
+	if dc.Auth == AuthIAM && dc.SSHTunnel != nil && dc.SSHTunnel.Enabled {
+		return fmt.Errorf("auth: iam cannot be combined with ssh_tunnel")
+	}
+	
+	if dc.SSHTunnel != nil && dc.SSHTunnel.Vault != nil {
+		if err := dc.SSHTunnel.Vault.Validate(); err != nil {
+			return fmt.Errorf("ssh_tunnel: %w", err)
+		}
 	}
 	
 	return nil
@@ -163,6 +265,8 @@ func (dc *DatabaseConnection) ToConnectionConfig() *ConnectionConfig {
 		Project:                dc.Project,
 		Region:                 dc.Region,
 		InstanceName:           dc.InstanceName,
+		Auth:                   dc.Auth,
+		Vault:                  dc.Vault,
 	}
 }
 
@@ -178,6 +282,12 @@ type ConnectionConfig struct {
 	// For instances without connection name format
 	InstanceName           string `yaml:"instance_name,omitempty"`
 	Region                 string `yaml:"region,omitempty"`
+	
+	// Auth is password (default) or iam, see DatabaseConnection.Auth
+	Auth string `yaml:"auth,omitempty"`
+	
+	// Vault supplies the login, see DatabaseConnection.Vault
+	Vault *vault.Credentials `yaml:"vault,omitempty"`
 }
 
 // Compile-time interface implementation check
@@ -193,9 +303,52 @@ func (b SQLBaseline) Validate() error {
 	if b.Name == "" {
 		return fmt.Errorf("baseline name is required")
 	}
+	if err := b.Scope().Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	if err := b.Metadata.Validate(); err != nil {
+		return fmt.Errorf("baseline %s: %w", b.Name, err)
+	}
+	if b.Config != nil {
+		if err := b.Config.AllowedValues.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: %w", b.Name, err)
+		}
+		if err := b.Config.SeverityOverrides.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: %w", b.Name, err)
+		}
+		if err := b.Config.Ranges.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: %w", b.Name, err)
+		}
+		for _, pattern := range b.Config.ForbiddenUsers {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("baseline %s: invalid forbidden_users pattern %q: %w", b.Name, pattern, err)
+			}
+		}
+	}
+	for i, envelope := range b.Capacity {
+		if err := envelope.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: capacity[%d]: %w", b.Name, i, err)
+		}
+	}
+	for i, rule := range b.Unique {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("baseline %s: unique[%d]: %w", b.Name, i, err)
+		}
+	}
 	return nil
 }
 
+// Expected returns the database versions and tiers the baseline accepts, so
+// deprecated ones can be flagged for review
+func (b SQLBaseline) Expected() (versions, tiers []string) {
+	if b.Config == nil {
+		return nil, nil
+	}
+	versions = append([]string{b.Config.DatabaseVersion}, b.Config.AllowedValues.Values(checkDatabaseVersion.Path)...)
+	tiers = append([]string{b.Config.Tier}, b.Config.AllowedValues.Values(checkTier.Path)...)
+	return versions, tiers
+}
+
 // Execute runs the SQL drift analysis command
 func (c *Command) Execute(ctx context.Context) error {
 	// Use provided baselines and projects from main
@@ -215,7 +368,13 @@ func (c *Command) Execute(ctx context.Context) error {
 		return fmt.Errorf("must provide either -projects or -config")
 	}
 
-	// Apply command-line filter if specified
+	// Apply command-line filters if specified
+	for key, value := range c.Labels {
+		if filterLabels == nil {
+			filterLabels = make(map[string]string)
+		}
+		filterLabels[key] = value
+	}
 	if c.FilterRole != "" {
 		if filterLabels == nil {
 			filterLabels = make(map[string]string)
@@ -238,19 +397,22 @@ func (c *Command) Execute(ctx context.Context) error {
 		}
 	}()
 
-	// Discover all PostgreSQL instances
+	// Discover all PostgreSQL and SQL Server instances
 	instances, err := analyzer.DiscoverInstances(ctx, projectList)
 	if err != nil {
 		return fmt.Errorf("failed to discover instances: %w", err)
 	}
 
 	if len(instances) == 0 {
-		fmt.Println("No PostgreSQL instances found in specified projects")
+		fmt.Println("No PostgreSQL or SQL Server instances found in specified projects")
 		return nil
 	}
 
 	// Generate baseline config if requested
 	if c.GenerateConfig {
+		if c.GroupBy != "" {
+			return writeGeneratedConfig(instances, c.GroupBy, c.OutputFile)
+		}
 		return generateBaselineConfig(instances, c.OutputFile)
 	}
 
@@ -301,6 +463,20 @@ func generateBaselineConfig(instances []*DatabaseInstance, outputPath string) er
 	return nil
 }
 
+// writeGeneratedConfig writes a config with one baseline per value of the
+// groupBy label
+func writeGeneratedConfig(instances []*DatabaseInstance, groupBy, outputPath string) error {
+	output, err := GenerateBaselineConfig(instances, groupBy)
+	if err != nil {
+		return err
+	}
+	if outputPath != "" {
+		return os.WriteFile(outputPath, []byte(output), 0644)
+	}
+	fmt.Print(output)
+	return nil
+}
+
 // outputReport formats and writes the drift report
 func outputReport(report *DriftReport, format, outputPath string) error {
 	var output string
@@ -318,6 +494,16 @@ func outputReport(report *DriftReport, format, outputPath string) error {
 			return err
 		}
 		output = string(data)
+	case "ndjson":
+		data, err := report.FormatNDJSON()
+		if err != nil {
+			return err
+		}
+		output = strings.TrimSuffix(data, "\n")
+	case "dot":
+		output = strings.TrimSuffix(report.ToReport().FormatDOT(), "\n")
+	case "mermaid":
+		output = strings.TrimSuffix(report.ToReport().FormatMermaid(), "\n")
 	case "text":
 		output = report.FormatText()
 	default:
@@ -346,10 +532,7 @@ func analyzeMultipleBaselines(analyzer *Analyzer, allInstances []*DatabaseInstan
 	// Analyze each baseline with its filters
 	for _, baseline := range baselines {
 		// Filter instances for this baseline
-		filteredInstances := allInstances
-		if len(baseline.FilterLabels) > 0 {
-			filteredInstances = filterInstancesByLabels(allInstances, baseline.FilterLabels)
-		}
+		filteredInstances := SelectInstances(allInstances, baseline, baselines)
 
 		// Analyze with this baseline
 		for _, inst := range filteredInstances {
@@ -431,9 +614,5 @@ func (c *ConnectionConfig) Validate() error {
 		return fmt.Errorf("database name is required")
 	}
 	
-	if c.Username == "" {
-		return fmt.Errorf("username is required")
-	}
-	
-	return nil
+	return validateCredentials(c.Auth, c.Username, c.Password, c.Vault)
 }
@@ -2,6 +2,8 @@ package sql
 
 import (
 	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/vault"
 )
 
 func TestValidateSchemaAgainstBaseline_RequiredTables(t *testing.T) {
@@ -324,3 +326,65 @@ func TestValidateSchemaAgainstBaseline_AllowedOwnersValidation(t *testing.T) {
 		t.Fatalf("Expected 1 ownership violation, got %d", len(result.OwnershipViolations))
 	}
 }
+
+func TestDatabaseConnectionValidateAuth(t *testing.T) {
+	base := DatabaseConnection{
+		Name:                   "orders",
+		InstanceConnectionName: "proj:us-central1:orders",
+		Database:               "orders",
+		Username:               "auditor@proj.iam",
+	}
+	tests := []struct {
+		name    string
+		modify  func(*DatabaseConnection)
+		wantErr bool
+	}{
+		{"password by default", func(c *DatabaseConnection) { c.Password = "secret" }, false},
+		{"iam without password", func(c *DatabaseConnection) { c.Auth = AuthIAM }, false},
+		{"iam with password", func(c *DatabaseConnection) { c.Auth, c.Password = AuthIAM, "secret" }, true},
+		{"iam with ssh tunnel", func(c *DatabaseConnection) {
+			c.Auth, c.SSHTunnel = AuthIAM, &SSHTunnelConfig{Enabled: true}
+		}, true},
+		{"unknown auth", func(c *DatabaseConnection) { c.Auth = "kerberos" }, true},
+		{"vault without username", func(c *DatabaseConnection) {
+			c.Username, c.Vault = "", &vault.Credentials{Secret: vault.Secret{Engine: vault.EngineDatabase, Role: "ro"}}
+		}, false},
+		{"vault with password", func(c *DatabaseConnection) {
+			c.Password, c.Vault = "secret", &vault.Credentials{Secret: vault.Secret{Path: "db/orders"}}
+		}, true},
+		{"vault with iam", func(c *DatabaseConnection) {
+			c.Auth, c.Vault = AuthIAM, &vault.Credentials{Secret: vault.Secret{Path: "db/orders"}}
+		}, true},
+		{"invalid vault secret", func(c *DatabaseConnection) { c.Vault = &vault.Credentials{} }, true},
+		{"invalid ssh key secret", func(c *DatabaseConnection) {
+			c.SSHTunnel = &SSHTunnelConfig{Enabled: true, Vault: &vault.SSHKey{Secret: vault.Secret{Engine: vault.EngineDatabase, Role: "ro"}}}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := base
+			tt.modify(&conn)
+			err := conn.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewInspectorFromConnectionConfigIAM(t *testing.T) {
+	inspector, err := NewInspectorFromConnectionConfig(&ConnectionConfig{
+		InstanceConnectionName: "proj:us-central1:orders",
+		Database:               "orders",
+		Username:               "auditor@proj.iam",
+		UsePrivateIP:           true,
+		Auth:                   AuthIAM,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Private IP goes through the connector instead of a proxy process
+	if !inspector.useCloudSQLConnector || inspector.proxyManager != nil || !inspector.iamAuthN || !inspector.usePrivateIP {
+		t.Errorf("inspector = %+v, want a private IP connector with IAM authentication", inspector)
+	}
+}
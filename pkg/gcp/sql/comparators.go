@@ -1,82 +1,246 @@
 package sql
 
-import "fmt"
+import (
+	"fmt"
+	"path"
+	"slices"
+	"time"
 
-// compareBackupSettings compares backup-related settings
-func (a *Analyzer) compareBackupSettings(actual, baseline *Settings, drift *InstanceDrift) {
-	if actual.BackupEnabled != baseline.BackupEnabled {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.backup_enabled",
-			Expected: fmt.Sprintf("%v", baseline.BackupEnabled),
-			Actual:   fmt.Sprintf("%v", actual.BackupEnabled),
-			Severity: "critical",
-		})
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+)
+
+// compareInstanceConfig compares instance-level settings (version, tier, disk)
+func (a *Analyzer) compareInstanceConfig(actual, baseline *DatabaseConfig, drift *InstanceDrift) {
+	allowed := baseline.AllowedValues
+	drift.Drifts = checkDatabaseVersion.OneOf(drift.Drifts, allowed, baseline.DatabaseVersion, actual.DatabaseVersion)
+	drift.Drifts = checkTier.OneOf(drift.Drifts, allowed, baseline.Tier, actual.Tier)
+	drift.Drifts = checkDiskType.OneOf(drift.Drifts, allowed, baseline.DiskType, actual.DiskType)
+	drift.Drifts = checkDiskSize.Number(drift.Drifts, baseline.Ranges, baseline.DiskSize, actual.DiskSize)
+
+	// Only check disk autoresize if disk type is specified (indicating disk config matters)
+	if baseline.DiskType != "" {
+		drift.Drifts = checkDiskAutoresize.Bool(drift.Drifts, baseline.DiskAutoresize, actual.DiskAutoresize)
 	}
+}
 
-	if actual.PointInTimeRecovery != baseline.PointInTimeRecovery {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.point_in_time_recovery",
-			Expected: fmt.Sprintf("%v", baseline.PointInTimeRecovery),
-			Actual:   fmt.Sprintf("%v", actual.PointInTimeRecovery),
-			Severity: "high",
-		})
+// compareDatabaseFlags compares database flags between actual and baseline configurations
+func (a *Analyzer) compareDatabaseFlags(config, baseline *DatabaseConfig, drift *InstanceDrift) {
+	for key, baselineValue := range baseline.DatabaseFlags {
+		actualValue, exists := config.DatabaseFlags[key]
+		if !exists {
+			actualValue = "not set"
+		}
+		drift.Drifts = checkFlag.At(key).String(drift.Drifts, baselineValue, actualValue)
 	}
 
-	if baseline.BackupRetentionDays > 0 && actual.BackupRetentionDays != baseline.BackupRetentionDays {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.backup_retention_days",
-			Expected: fmt.Sprintf("%d", baseline.BackupRetentionDays),
-			Actual:   fmt.Sprintf("%d", actual.BackupRetentionDays),
-			Severity: "medium",
-		})
+	// Check for extra flags not in baseline
+	for key, actualValue := range config.DatabaseFlags {
+		if _, exists := baseline.DatabaseFlags[key]; !exists {
+			drift.Drifts = checkExtraFlag.At(key).Append(drift.Drifts, "not set", actualValue)
+		}
 	}
+}
+
+// checkRequiredDatabases validates that required databases exist on the instance
+func (a *Analyzer) checkRequiredDatabases(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
+	if len(baseline.RequiredDatabases) == 0 {
+		return
+	}
+
+	missing, extra := checks.SetDiff(baseline.RequiredDatabases, inst.Databases)
 
-	if baseline.TransactionLogRetentionDays > 0 && actual.TransactionLogRetentionDays != baseline.TransactionLogRetentionDays {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.transaction_log_retention_days",
-			Expected: fmt.Sprintf("%d", baseline.TransactionLogRetentionDays),
-			Actual:   fmt.Sprintf("%d", actual.TransactionLogRetentionDays),
-			Severity: "medium",
-		})
+	if len(missing) > 0 {
+		drift.Drifts = checkMissingDatabases.Append(drift.Drifts,
+			fmt.Sprintf("%v", baseline.RequiredDatabases), fmt.Sprintf("Missing: %v", missing))
+		a.attributeOwners(drift, missing)
 	}
 
-	if baseline.BackupStartTime != "" && actual.BackupStartTime != baseline.BackupStartTime {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.backup_start_time",
-			Expected: baseline.BackupStartTime,
-			Actual:   actual.BackupStartTime,
-			Severity: "low",
-		})
+	if len(extra) > 0 {
+		drift.Drifts = checkExtraDatabases.Append(drift.Drifts,
+			fmt.Sprintf("%v", baseline.RequiredDatabases), fmt.Sprintf("Extra: %v", extra))
+		a.attributeOwners(drift, extra)
 	}
 }
 
-// compareAvailabilitySettings compares availability-related settings
-func (a *Analyzer) compareAvailabilitySettings(actual, baseline *Settings, drift *InstanceDrift) {
-	if baseline.AvailabilityType != "" && actual.AvailabilityType != baseline.AvailabilityType {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.availability_type",
-			Expected: baseline.AvailabilityType,
-			Actual:   actual.AvailabilityType,
-			Severity: "high",
-		})
+// attributeOwners records the services owning the databases of the drift
+// just appended
+func (a *Analyzer) attributeOwners(drift *InstanceDrift, databases []string) {
+	drift.Drifts[len(drift.Drifts)-1].Owners = a.owners.OwnersOf(databases)
+}
+
+// compareReplicas checks the replicas of a primary instance: their number and
+// that they run in, and cover, the baseline's replica regions. Replicas are
+// not checked themselves. Replicas outside the scanned project have no known
+// region and are only counted.
+func (a *Analyzer) compareReplicas(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
+	if inst.Primary != "" {
+		return
+	}
+	drift.Drifts = checkReplicaCount.Number(drift.Drifts, baseline.Ranges, baseline.RequiredReplicaCount, int64(len(inst.Replicas)))
+	if len(baseline.ReplicaRegions) == 0 {
+		return
+	}
+
+	covered := make(map[string]bool)
+	for _, replica := range inst.Replicas {
+		if replica.Region == "" {
+			continue
+		}
+		covered[replica.Region] = true
+		if !slices.Contains(baseline.ReplicaRegions, replica.Region) {
+			drift.Drifts = checkReplicaRegion.At(replica.Name).Append(drift.Drifts,
+				checks.DescribeValues(baseline.ReplicaRegions), replica.Region)
+		}
+	}
+	for _, region := range baseline.ReplicaRegions {
+		if !covered[region] {
+			drift.Drifts = checkMissingReplicaRegion.At(region).Append(drift.Drifts, "replica", "missing")
+		}
 	}
+}
+
+// compareUsers checks that the instance has the required users and none
+// matching the forbidden patterns. Instances whose users could not be listed
+// are not checked.
+func (a *Analyzer) compareUsers(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
+	if inst.Users == nil {
+		return
+	}
+
+	names := make([]string, 0, len(inst.Users))
+	for _, user := range inst.Users {
+		names = append(names, user.Name)
+	}
+	for _, required := range baseline.RequiredUsers {
+		if !slices.Contains(names, required) {
+			drift.Drifts = checkRequiredUser.At(required).Append(drift.Drifts, "present", "missing")
+		}
+	}
+
+	for _, user := range inst.Users {
+		for _, pattern := range baseline.ForbiddenUsers {
+			if matched, _ := path.Match(pattern, user.Name); matched {
+				drift.Drifts = checkForbiddenUser.At(user.Name).Append(drift.Drifts,
+					fmt.Sprintf("absent (forbidden: %s)", pattern), fmt.Sprintf("present (%s)", user.Type))
+				break
+			}
+		}
+	}
+}
 
-	if baseline.PricingPlan != "" && actual.PricingPlan != baseline.PricingPlan {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.pricing_plan",
-			Expected: baseline.PricingPlan,
-			Actual:   actual.PricingPlan,
-			Severity: "low",
-		})
+// compareIAMAuthentication checks the cloudsql.iam_authentication flag of
+// PostgreSQL instances; SQL Server has no IAM database authentication
+func (a *Analyzer) compareIAMAuthentication(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
+	if baseline.IAMAuthentication == nil || !isPostgreSQL(inst.Config.DatabaseVersion) {
+		return
 	}
+	enabled := inst.Config.DatabaseFlags["cloudsql.iam_authentication"] == "on"
+	drift.Drifts = checkIAMAuthentication.Bool(drift.Drifts, *baseline.IAMAuthentication, enabled)
+}
 
-	if baseline.ReplicationType != "" && actual.ReplicationType != baseline.ReplicationType {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.replication_type",
-			Expected: baseline.ReplicationType,
-			Actual:   actual.ReplicationType,
-			Severity: "medium",
-		})
+// compareCertificates flags certificates that expired or expire within the
+// baseline's cert_expiry_days of now
+func (a *Analyzer) compareCertificates(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift, now time.Time) {
+	if baseline.CertExpiryDays <= 0 {
+		return
+	}
+	expected := fmt.Sprintf("valid for more than %d days", baseline.CertExpiryDays)
+	deadline := now.AddDate(0, 0, int(baseline.CertExpiryDays))
+
+	for _, cert := range inst.Certificates {
+		if cert.Expires.After(deadline) {
+			continue
+		}
+		name := cert.CommonName
+		if cert.Kind == "server_ca" {
+			name = "server_ca_cert"
+		}
+		if !cert.Expires.After(now) {
+			drift.Drifts = checkCertExpired.At(name).Append(drift.Drifts, expected,
+				"expired "+cert.Expires.Format("2006-01-02"))
+			continue
+		}
+
+		check := checkServerCACert
+		if cert.Kind != "server_ca" {
+			check = checkClientCert.At(cert.CommonName)
+		}
+		drift.Drifts = check.Append(drift.Drifts, expected, fmt.Sprintf("expires %s (in %d days)",
+			cert.Expires.Format("2006-01-02"), int(cert.Expires.Sub(now).Hours()/24)))
+	}
+}
+
+// compareLabels checks that the instance carries the baseline labels; labels
+// not in the baseline are ignored
+func (a *Analyzer) compareLabels(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
+	for key, expected := range baseline.Labels {
+		actual, exists := inst.Labels[key]
+		if !exists {
+			actual = "not set"
+		}
+		drift.Drifts = checkLabel.At(key).String(drift.Drifts, expected, actual)
+	}
+}
+
+// compareMaintenanceWindow compares the maintenance window. Once a window is
+// expected its hour is always compared, since hour 0 (midnight) is valid.
+func (a *Analyzer) compareMaintenanceWindow(inst *DatabaseInstance, baseline *DatabaseConfig, drift *InstanceDrift) {
+	expected := baseline.MaintenanceWindow
+	if expected == nil {
+		return
+	}
+
+	actual := inst.MaintenanceWindow
+	if actual == nil {
+		drift.Drifts = checkMaintenanceWindow.Append(drift.Drifts,
+			fmt.Sprintf("day %d hour %d", expected.Day, expected.Hour), "not configured")
+		return
+	}
+
+	drift.Drifts = checkMaintenanceDay.Int(drift.Drifts, int64(expected.Day), int64(actual.Day))
+	if actual.Hour != expected.Hour {
+		drift.Drifts = checkMaintenanceHour.Append(drift.Drifts,
+			fmt.Sprintf("%d", expected.Hour), fmt.Sprintf("%d", actual.Hour))
+	}
+	drift.Drifts = checkMaintenanceTrack.String(drift.Drifts, expected.UpdateTrack, actual.UpdateTrack)
+}
+
+// compareBackupSettings compares backup-related settings
+func (a *Analyzer) compareBackupSettings(actual, baseline *Settings, ranges checks.Ranges, drift *InstanceDrift) {
+	drift.Drifts = checkBackupEnabled.Bool(drift.Drifts, baseline.BackupEnabled, actual.BackupEnabled)
+	drift.Drifts = checkPointInTimeRecovery.Bool(drift.Drifts, baseline.PointInTimeRecovery, actual.PointInTimeRecovery)
+	drift.Drifts = checkBackupRetention.Number(drift.Drifts, ranges, baseline.BackupRetentionDays, actual.BackupRetentionDays)
+	drift.Drifts = checkTransactionLogRetain.Number(drift.Drifts, ranges, baseline.TransactionLogRetentionDays, actual.TransactionLogRetentionDays)
+	drift.Drifts = checkBackupStartTime.String(drift.Drifts, baseline.BackupStartTime, actual.BackupStartTime)
+}
+
+// compareAvailabilitySettings compares availability-related settings
+func (a *Analyzer) compareAvailabilitySettings(actual, baseline *Settings, allowed checks.Allowed, drift *InstanceDrift) {
+	drift.Drifts = checkAvailabilityType.OneOf(drift.Drifts, allowed, baseline.AvailabilityType, actual.AvailabilityType)
+	drift.Drifts = checkPricingPlan.OneOf(drift.Drifts, allowed, baseline.PricingPlan, actual.PricingPlan)
+	drift.Drifts = checkReplicationType.OneOf(drift.Drifts, allowed, baseline.ReplicationType, actual.ReplicationType)
+}
+
+// compareZonePlacement compares primary and secondary zone placement. For
+// REGIONAL instances the standby must live in a different zone than the
+// primary, otherwise a zonal outage takes down both.
+func (a *Analyzer) compareZonePlacement(actual, baseline *Settings, allowed checks.Allowed, drift *InstanceDrift) {
+	drift.Drifts = checkPrimaryZone.OneOf(drift.Drifts, allowed, baseline.LocationPreference, actual.LocationPreference)
+
+	if zones := allowed.Values(checkSecondaryZone.Path); len(zones) > 0 {
+		drift.Drifts = checkSecondaryZone.OneOf(drift.Drifts, allowed, "", actual.SecondaryZone)
+	} else if baseline.SecondaryZone != "" && actual.SecondaryZone != baseline.SecondaryZone {
+		actualZone := actual.SecondaryZone
+		if actualZone == "" {
+			actualZone = "not set"
+		}
+		drift.Drifts = checkSecondaryZone.Append(drift.Drifts, baseline.SecondaryZone, actualZone)
+	}
+
+	if actual.AvailabilityType == "REGIONAL" && actual.SecondaryZone != "" &&
+		actual.SecondaryZone == actual.LocationPreference {
+		drift.Drifts = checkDistinctZones.Append(drift.Drifts,
+			fmt.Sprintf("zone other than %s", actual.LocationPreference), actual.SecondaryZone)
 	}
 }
 
@@ -86,61 +250,69 @@ func (a *Analyzer) compareIPConfig(actual, baseline *Settings, drift *InstanceDr
 		return
 	}
 
-	if actual.IPConfiguration.IPv4Enabled != baseline.IPConfiguration.IPv4Enabled {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.ip_configuration.ipv4_enabled",
-			Expected: fmt.Sprintf("%v", baseline.IPConfiguration.IPv4Enabled),
-			Actual:   fmt.Sprintf("%v", actual.IPConfiguration.IPv4Enabled),
-			Severity: "medium",
-		})
+	drift.Drifts = checkIPv4Enabled.Bool(drift.Drifts, baseline.IPConfiguration.IPv4Enabled, actual.IPConfiguration.IPv4Enabled)
+	drift.Drifts = checkRequireSSL.Bool(drift.Drifts, baseline.IPConfiguration.RequireSSL, actual.IPConfiguration.RequireSSL)
+
+	if len(baseline.IPConfiguration.AuthorizedNetworks) > 0 {
+		a.compareAuthorizedNetworks(baseline.IPConfiguration, actual.IPConfiguration, drift)
 	}
+}
+
+// compareAuthorizedNetworks compares authorized network lists between baseline and actual
+func (a *Analyzer) compareAuthorizedNetworks(baseline, actual *IPConfiguration, drift *InstanceDrift) {
+	required, extra := checks.SetDiff(baseline.AuthorizedNetworks, actual.AuthorizedNetworks)
 
-	if actual.IPConfiguration.RequireSSL != baseline.IPConfiguration.RequireSSL {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.ip_configuration.require_ssl",
-			Expected: fmt.Sprintf("%v", baseline.IPConfiguration.RequireSSL),
-			Actual:   fmt.Sprintf("%v", actual.IPConfiguration.RequireSSL),
-			Severity: "critical",
-		})
+	// Report required networks as high severity
+	if len(required) > 0 {
+		drift.Drifts = checkRequiredNetworks.Append(drift.Drifts,
+			fmt.Sprintf("Required: %v", required), fmt.Sprintf("%v", actual.AuthorizedNetworks))
 	}
 
-	if len(baseline.IPConfiguration.AuthorizedNetworks) > 0 {
-		a.compareAuthorizedNetworks(baseline.IPConfiguration, actual.IPConfiguration, drift)
+	// Report extra networks as medium severity
+	if len(extra) > 0 {
+		drift.Drifts = checkExtraNetworks.Append(drift.Drifts,
+			fmt.Sprintf("%v", baseline.AuthorizedNetworks), fmt.Sprintf("Extra: %v", extra))
 	}
 }
 
 // compareInsightsConfig compares insights configuration settings
-func (a *Analyzer) compareInsightsConfig(actual, baseline *Settings, drift *InstanceDrift) {
+func (a *Analyzer) compareInsightsConfig(actual, baseline *Settings, ranges checks.Ranges, drift *InstanceDrift) {
 	if baseline.InsightsConfig == nil || actual.InsightsConfig == nil {
 		return
 	}
 
-	if actual.InsightsConfig.QueryInsightsEnabled != baseline.InsightsConfig.QueryInsightsEnabled {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.insights_config.query_insights_enabled",
-			Expected: fmt.Sprintf("%v", baseline.InsightsConfig.QueryInsightsEnabled),
-			Actual:   fmt.Sprintf("%v", actual.InsightsConfig.QueryInsightsEnabled),
-			Severity: "low",
-		})
+	drift.Drifts = checkQueryInsights.Bool(drift.Drifts,
+		baseline.InsightsConfig.QueryInsightsEnabled, actual.InsightsConfig.QueryInsightsEnabled)
+	drift.Drifts = checkQueryPlansPerMinute.Number(drift.Drifts, ranges,
+		baseline.InsightsConfig.QueryPlansPerMinute, actual.InsightsConfig.QueryPlansPerMinute)
+	drift.Drifts = checkQueryStringLength.Number(drift.Drifts, ranges,
+		baseline.InsightsConfig.QueryStringLength, actual.InsightsConfig.QueryStringLength)
+}
+
+// compareSQLServerSettings compares SQL Server specific settings (collation, AD, audit)
+func (a *Analyzer) compareSQLServerSettings(actual, baseline *Settings, allowed checks.Allowed, drift *InstanceDrift) {
+	drift.Drifts = checkCollation.OneOf(drift.Drifts, allowed, baseline.Collation, actual.Collation)
+
+	if baseline.ActiveDirectory != nil {
+		actualDomain := "not configured"
+		if actual.ActiveDirectory != nil && actual.ActiveDirectory.Domain != "" {
+			actualDomain = actual.ActiveDirectory.Domain
+		}
+		drift.Drifts = checkActiveDirectory.String(drift.Drifts, baseline.ActiveDirectory.Domain, actualDomain)
 	}
 
-	if baseline.InsightsConfig.QueryPlansPerMinute > 0 &&
-		actual.InsightsConfig.QueryPlansPerMinute != baseline.InsightsConfig.QueryPlansPerMinute {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.insights_config.query_plans_per_minute",
-			Expected: fmt.Sprintf("%d", baseline.InsightsConfig.QueryPlansPerMinute),
-			Actual:   fmt.Sprintf("%d", actual.InsightsConfig.QueryPlansPerMinute),
-			Severity: "low",
-		})
+	if baseline.SQLServerAudit == nil {
+		return
 	}
 
-	if baseline.InsightsConfig.QueryStringLength > 0 &&
-		actual.InsightsConfig.QueryStringLength != baseline.InsightsConfig.QueryStringLength {
-		drift.Drifts = append(drift.Drifts, Drift{
-			Field:    "settings.insights_config.query_string_length",
-			Expected: fmt.Sprintf("%d", baseline.InsightsConfig.QueryStringLength),
-			Actual:   fmt.Sprintf("%d", actual.InsightsConfig.QueryStringLength),
-			Severity: "low",
-		})
+	if actual.SQLServerAudit == nil {
+		drift.Drifts = checkAudit.Append(drift.Drifts, "enabled", "not configured")
+		return
 	}
+
+	drift.Drifts = checkAuditBucket.String(drift.Drifts, baseline.SQLServerAudit.Bucket, actual.SQLServerAudit.Bucket)
+	drift.Drifts = checkAuditRetention.String(drift.Drifts,
+		baseline.SQLServerAudit.RetentionInterval, actual.SQLServerAudit.RetentionInterval)
+	drift.Drifts = checkAuditUploadInterval.String(drift.Drifts,
+		baseline.SQLServerAudit.UploadInterval, actual.SQLServerAudit.UploadInterval)
 }
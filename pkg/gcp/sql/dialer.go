@@ -0,0 +1,72 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"cloud.google.com/go/cloudsqlconn"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+)
+
+// Dialer shares one Cloud SQL connector dialer between inspectors, so
+// concurrent inspections reuse its token source and the certificates it
+// caches per instance instead of each fetching their own
+type Dialer struct {
+	// ctx outlives the inspections; the connector refreshes certificates in
+	// the background with it
+	ctx context.Context
+
+	mu     sync.Mutex
+	dialer *cloudsqlconn.Dialer
+}
+
+// NewDialer returns a shared dialer; the connector dialer is created on the
+// first dial, so inspections that don't use the connector never need it
+func NewDialer(ctx context.Context) *Dialer {
+	return &Dialer{ctx: ctx}
+}
+
+// Dial connects to a Cloud SQL instance (project:region:instance), over its
+// private IP if privateIP is set. With iamAuthN the connection logs in with an
+// OAuth token instead of the database password.
+func (d *Dialer) Dial(ctx context.Context, instance string, privateIP, iamAuthN bool) (net.Conn, error) {
+	dialer, err := d.connector()
+	if err != nil {
+		return nil, err
+	}
+	// Set per dial: the connector enables IAM authentication for every dial
+	// when it has configured credentials
+	opts := []cloudsqlconn.DialOption{cloudsqlconn.WithDialIAMAuthN(iamAuthN)}
+	if privateIP {
+		opts = append(opts, cloudsqlconn.WithPrivateIP())
+	}
+	return dialer.Dial(ctx, instance, opts...)
+}
+
+// connector returns the connector dialer, creating it on first use
+func (d *Dialer) connector() (*cloudsqlconn.Dialer, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dialer == nil {
+		dialer, err := cloudsqlconn.NewDialer(d.ctx, credentials.DialerOptions()...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dialer: %w", err)
+		}
+		d.dialer = dialer
+	}
+	return d.dialer, nil
+}
+
+// Close closes the connector dialer, if one was created
+func (d *Dialer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dialer == nil {
+		return nil
+	}
+	err := d.dialer.Close()
+	d.dialer = nil
+	return err
+}
@@ -0,0 +1,149 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/retry"
+	"github.com/jessequinn/drift-analysis-cli/pkg/metacache"
+)
+
+// FlagSpec describes a database flag Cloud SQL supports for a database version
+type FlagSpec struct {
+	Name                string   `json:"name"`
+	Type                string   `json:"type"`
+	AllowedStringValues []string `json:"allowed_string_values,omitempty"`
+	AllowedIntValues    []int64  `json:"allowed_int_values,omitempty"`
+	MinValue            int64    `json:"min_value,omitempty"`
+	MaxValue            int64    `json:"max_value,omitempty"`
+}
+
+// FetchSupportedFlags returns the flags Cloud SQL supports for a database
+// version by name, read through the metadata cache
+func (a *Analyzer) FetchSupportedFlags(ctx context.Context, cache *metacache.Cache, databaseVersion string) (map[string]FlagSpec, error) {
+	return metacache.Lookup(cache, "sql-flags/"+databaseVersion, func() (map[string]FlagSpec, error) {
+		resp, err := retry.Do(ctx, a.retry, a.service.Flags.List().DatabaseVersion(databaseVersion).Context(ctx).Do)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list supported flags for %s: %w", databaseVersion, err)
+		}
+		flags := make(map[string]FlagSpec, len(resp.Items))
+		for _, f := range resp.Items {
+			flags[f.Name] = FlagSpec{
+				Name:                f.Name,
+				Type:                f.Type,
+				AllowedStringValues: f.AllowedStringValues,
+				AllowedIntValues:    f.AllowedIntValues,
+				MinValue:            f.MinValue,
+				MaxValue:            f.MaxValue,
+			}
+		}
+		return flags, nil
+	})
+}
+
+// Check describes why a value is invalid for the flag, or returns "" when
+// Cloud SQL accepts it
+func (f FlagSpec) Check(value string) string {
+	switch f.Type {
+	case "BOOLEAN":
+		switch strings.ToLower(value) {
+		case "on", "off", "true", "false":
+			return ""
+		}
+		return "expected on or off"
+	case "INTEGER":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "expected an integer"
+		}
+		if len(f.AllowedIntValues) > 0 && !slices.Contains(f.AllowedIntValues, n) {
+			return fmt.Sprintf("allowed values are %v", f.AllowedIntValues)
+		}
+		return f.checkRange(float64(n))
+	case "FLOAT":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return "expected a number"
+		}
+		return f.checkRange(n)
+	case "STRING":
+		if len(f.AllowedStringValues) == 0 {
+			return ""
+		}
+		for _, allowed := range f.AllowedStringValues {
+			if strings.EqualFold(allowed, value) {
+				return ""
+			}
+		}
+		return "allowed values are " + strings.Join(f.AllowedStringValues, ", ")
+	}
+	return ""
+}
+
+func (f FlagSpec) checkRange(n float64) string {
+	if f.MaxValue > f.MinValue && (n < float64(f.MinValue) || n > float64(f.MaxValue)) {
+		return fmt.Sprintf("must be between %d and %d", f.MinValue, f.MaxValue)
+	}
+	if f.MaxValue <= f.MinValue && f.MinValue != 0 && n < float64(f.MinValue) {
+		return fmt.Sprintf("must be at least %d", f.MinValue)
+	}
+	return ""
+}
+
+// AnnotateStaleBaseline warns on database flag drifts whose expected value
+// the instance's database version does not support: the baseline itself
+// needs updating, not only the instance. Failed lookups are returned after
+// every instance has been checked.
+func (a *Analyzer) AnnotateStaleBaseline(ctx context.Context, cache *metacache.Cache, report *DriftReport, instances []*DatabaseInstance, baseline *DatabaseConfig) error {
+	if baseline == nil || len(baseline.DatabaseFlags) == 0 {
+		return nil
+	}
+
+	versions := make(map[string]string, len(instances))
+	for _, inst := range instances {
+		if inst.Config != nil {
+			versions[inst.Project+"/"+inst.Name] = inst.Config.DatabaseVersion
+		}
+	}
+
+	var errs []error
+	for _, inst := range report.Instances {
+		version := versions[inst.Project+"/"+inst.Name]
+		if version == "" {
+			continue
+		}
+		for i := range inst.Drifts {
+			name, ok := strings.CutPrefix(inst.Drifts[i].Field, "database_flags.")
+			if !ok {
+				continue
+			}
+			expected, inBaseline := baseline.DatabaseFlags[name]
+			if !inBaseline {
+				continue
+			}
+			flags, err := a.FetchSupportedFlags(ctx, cache, version)
+			if err != nil {
+				errs = append(errs, err)
+				break
+			}
+			inst.Drifts[i].Warning = flagWarning(flags, name, expected, version)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flagWarning describes a baseline flag value the database version rejects
+func flagWarning(flags map[string]FlagSpec, name, value, version string) string {
+	spec, ok := flags[name]
+	if !ok {
+		return fmt.Sprintf("flag %s is not supported on %s", name, version)
+	}
+	if reason := spec.Check(value); reason != "" {
+		return fmt.Sprintf("baseline value %q is invalid on %s: %s", value, version, reason)
+	}
+	return ""
+}
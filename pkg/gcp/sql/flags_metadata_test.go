@@ -0,0 +1,51 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFlagSpecCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  FlagSpec
+		value string
+		valid bool
+	}{
+		{"boolean", FlagSpec{Type: "BOOLEAN"}, "on", true},
+		{"boolean invalid", FlagSpec{Type: "BOOLEAN"}, "yes", false},
+		{"integer in range", FlagSpec{Type: "INTEGER", MinValue: 14, MaxValue: 262143}, "500", true},
+		{"integer out of range", FlagSpec{Type: "INTEGER", MinValue: 14, MaxValue: 262143}, "5", false},
+		{"integer not a number", FlagSpec{Type: "INTEGER"}, "lots", false},
+		{"integer allowed values", FlagSpec{Type: "INTEGER", AllowedIntValues: []int64{8, 16}}, "12", false},
+		{"minimum only", FlagSpec{Type: "INTEGER", MinValue: 64}, "1000000", true},
+		{"string allowed", FlagSpec{Type: "STRING", AllowedStringValues: []string{"none", "ddl", "mod", "all"}}, "DDL", true},
+		{"string not allowed", FlagSpec{Type: "STRING", AllowedStringValues: []string{"none", "ddl", "mod", "all"}}, "verbose", false},
+		{"free-form string", FlagSpec{Type: "STRING"}, "anything", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := tt.spec.Check(tt.value)
+			if (reason == "") != tt.valid {
+				t.Errorf("Check(%q) = %q, want valid = %v", tt.value, reason, tt.valid)
+			}
+		})
+	}
+}
+
+func TestFlagWarning(t *testing.T) {
+	flags := map[string]FlagSpec{
+		"max_connections": {Name: "max_connections", Type: "INTEGER", MinValue: 14, MaxValue: 262143},
+	}
+
+	if got := flagWarning(flags, "max_connections", "500", "POSTGRES_15"); got != "" {
+		t.Errorf("flagWarning() for a valid value = %q, want none", got)
+	}
+	if got := flagWarning(flags, "max_connections", "1", "POSTGRES_15"); !strings.Contains(got, "invalid on POSTGRES_15") {
+		t.Errorf("flagWarning() for an invalid value = %q", got)
+	}
+	if got := flagWarning(flags, "pg_stat_statements.track", "all", "POSTGRES_15"); !strings.Contains(got, "not supported on POSTGRES_15") {
+		t.Errorf("flagWarning() for an unsupported flag = %q", got)
+	}
+}
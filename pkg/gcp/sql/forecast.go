@@ -0,0 +1,44 @@
+package sql
+
+import (
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+)
+
+// Metrics recorded in history for forecasts
+const (
+	// MetricDiskGB is an instance's provisioned storage
+	MetricDiskGB = "disk_gb"
+	// MetricDatabaseSizeGB is the total table size of a database
+	MetricDatabaseSizeGB = "database_size_gb"
+)
+
+// DatabaseSizeGB returns the total size of a database's tables in GB; tables
+// whose size could not be read are left out
+func DatabaseSizeGB(schema *DatabaseSchema) float64 {
+	var total int64
+	for _, table := range schema.Tables {
+		if table.SizeBytes > 0 {
+			total += table.SizeBytes
+		}
+	}
+	return float64(total) / (1 << 30)
+}
+
+// AddForecasts adds an advisory drift to every instance whose disk is
+// projected to reach its limit
+func AddForecasts(r *DriftReport, projections []history.Projection) {
+	for _, p := range projections {
+		if p.Metric != MetricDiskGB {
+			continue
+		}
+		for _, inst := range r.Instances {
+			if inst.Project != p.Project || inst.Region != p.Location || inst.Name != p.Resource {
+				continue
+			}
+			if len(inst.Drifts) == 0 {
+				r.DriftedInstances++
+			}
+			inst.Drifts = checkForecastDisk.Append(inst.Drifts, p.Expected(), p.Actual())
+		}
+	}
+}
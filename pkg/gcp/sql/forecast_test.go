@@ -0,0 +1,52 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/history"
+)
+
+func TestAddForecasts(t *testing.T) {
+	r := &DriftReport{Instances: []*InstanceDrift{
+		{Project: "p", Region: "us-east1", Name: "db-1"},
+		{Project: "p", Region: "us-east1", Name: "db-2", Drifts: []Drift{{Field: "tier"}}},
+		{Project: "p", Region: "us-east1", Name: "db-3"},
+	}, DriftedInstances: 1}
+
+	reaches := time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC)
+	AddForecasts(r, []history.Projection{
+		{Project: "p", Location: "us-east1", Resource: "db-1", Metric: MetricDiskGB, Current: 130, Limit: 500, GrowthPerDay: 10, ReachesAt: reaches, HorizonDays: 60},
+		{Project: "p", Location: "us-east1", Resource: "db-2", Metric: MetricDiskGB, Current: 600, Limit: 500, HorizonDays: 60},
+		// Database sizes belong to inspected databases, not instances
+		{Project: "p", Location: "us-east1", Resource: "db-3", Metric: MetricDatabaseSizeGB, Current: 90, Limit: 100, HorizonDays: 60},
+	})
+
+	if d := r.Instances[0].Drifts; len(d) != 1 || d[0].Field != "forecast.disk_gb" || d[0].Severity != "low" ||
+		d[0].Actual != "130, +10/day, reaches 500 around 2026-04-10" {
+		t.Errorf("db-1 drifts = %+v", d)
+	}
+	if len(r.Instances[1].Drifts) != 2 || len(r.Instances[2].Drifts) != 0 {
+		t.Errorf("drifts = %d, %d, want 2, 0", len(r.Instances[1].Drifts), len(r.Instances[2].Drifts))
+	}
+	if r.DriftedInstances != 2 {
+		t.Errorf("DriftedInstances = %d, want 2", r.DriftedInstances)
+	}
+}
+
+func TestDatabaseSizeGB(t *testing.T) {
+	schema := &DatabaseSchema{Tables: []TableInfo{
+		{SizeBytes: 3 << 30},
+		{SizeBytes: 1 << 29},
+		// Size could not be read
+		{SizeBytes: -1},
+	}}
+	if got := DatabaseSizeGB(schema); got != 3.5 {
+		t.Errorf("DatabaseSizeGB() = %v, want 3.5", got)
+	}
+
+	res := SchemaResource(&DatabaseConnection{Name: "orders"}, schema, nil)
+	if res.Metrics[MetricDatabaseSizeGB] != 3.5 {
+		t.Errorf("SchemaResource() metrics = %v", res.Metrics)
+	}
+}
@@ -0,0 +1,61 @@
+package sql
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// GenerateBaselineConfig renders a projects list and sql_baselines section
+// from discovered instances. With groupBy, instances are grouped by the value
+// of that label and each group gets a baseline selecting it through
+// filter_labels; without it one baseline covers every instance. A baseline
+// takes the configuration of its group's first instance by project and name,
+// with only the labels all instances of the group share.
+func GenerateBaselineConfig(instances []*DatabaseInstance, groupBy string) (string, error) {
+	if len(instances) == 0 {
+		return "", fmt.Errorf("no instances to generate config from")
+	}
+
+	resources := make([]analyzer.UniqueResource, len(instances))
+	for i, inst := range instances {
+		resources[i] = analyzer.UniqueResource{Project: inst.Project, Name: inst.Name, Labels: inst.Labels}
+	}
+	groups, unlabeled := analyzer.GroupByLabel(resources, groupBy)
+	if len(groups) == 0 {
+		return "", fmt.Errorf("no instances have label %s", groupBy)
+	}
+
+	var config struct {
+		Projects     []string      `yaml:"projects"`
+		SQLBaselines []SQLBaseline `yaml:"sql_baselines"`
+	}
+	projects := make(map[string]bool)
+	comments := make([]string, 0, len(groups))
+	for _, group := range groups {
+		template := instances[group.Members[0]]
+		baseline := SQLBaseline{Name: "default"}
+		if groupBy != "" {
+			baseline.Name = group.Value
+			baseline.FilterLabels = map[string]string{groupBy: group.Value}
+		}
+		if template.Config != nil {
+			cfg := *template.Config
+			cfg.Labels = group.CommonLabels
+			baseline.Config = &cfg
+		}
+		config.SQLBaselines = append(config.SQLBaselines, baseline)
+		comments = append(comments, group.Describe(resources, "instance"))
+		for _, i := range group.Members {
+			projects[instances[i].Project] = true
+		}
+	}
+	for project := range projects {
+		config.Projects = append(config.Projects, project)
+	}
+	sort.Strings(config.Projects)
+
+	header := analyzer.UnlabeledComment(resources, unlabeled, groupBy, "instance")
+	return analyzer.MarshalGenerated(config, "sql_baselines", header, comments)
+}
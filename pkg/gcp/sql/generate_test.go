@@ -0,0 +1,63 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateBaselineConfig(t *testing.T) {
+	instances := []*DatabaseInstance{
+		{Project: "shop-prod", Name: "orders", Labels: map[string]string{"database-role": "oltp", "team": "orders"},
+			Config: &DatabaseConfig{Tier: "db-custom-4-16384", Labels: map[string]string{"database-role": "oltp", "team": "orders"}}},
+		{Project: "shop-prod", Name: "payments", Labels: map[string]string{"database-role": "oltp", "team": "payments"},
+			Config: &DatabaseConfig{Tier: "db-custom-8-32768", Labels: map[string]string{"database-role": "oltp", "team": "payments"}}},
+		{Project: "shop-data", Name: "warehouse", Labels: map[string]string{"database-role": "analytics"},
+			Config: &DatabaseConfig{Tier: "db-custom-16-65536"}},
+		{Project: "shop-dev", Name: "scratch", Config: &DatabaseConfig{Tier: "db-f1-micro"}},
+	}
+
+	output, err := GenerateBaselineConfig(instances, "database-role")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# 1 instance without label database-role not covered: shop-dev/scratch",
+		"# Generated from shop-prod/orders; covers 2 instances",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+
+	var config struct {
+		Projects     []string      `yaml:"projects"`
+		SQLBaselines []SQLBaseline `yaml:"sql_baselines"`
+	}
+	if err := yaml.Unmarshal([]byte(output), &config); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(config.Projects, ",") != "shop-data,shop-prod" {
+		t.Errorf("projects = %v, want the covered projects", config.Projects)
+	}
+	if len(config.SQLBaselines) != 2 {
+		t.Fatalf("got %d baselines, want 2", len(config.SQLBaselines))
+	}
+	oltp := config.SQLBaselines[1]
+	if oltp.Name != "oltp" || oltp.FilterLabels["database-role"] != "oltp" {
+		t.Errorf("baseline = %s %v, want oltp selected by its label", oltp.Name, oltp.FilterLabels)
+	}
+	if oltp.Config.Tier != "db-custom-4-16384" || len(oltp.Config.Labels) != 1 {
+		t.Errorf("config = %+v, want the first instance's tier and only the shared labels", oltp.Config)
+	}
+	for _, baseline := range config.SQLBaselines {
+		if err := baseline.Validate(); err != nil {
+			t.Errorf("generated baseline %s is invalid: %v", baseline.Name, err)
+		}
+	}
+
+	if _, err := GenerateBaselineConfig(instances, "missing"); err == nil {
+		t.Error("expected an error when no instance has the label")
+	}
+}
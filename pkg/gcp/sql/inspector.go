@@ -4,13 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"net"
 	"sort"
 	"strings"
+	"time"
 
-	"cloud.google.com/go/cloudsqlconn"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgconn/ctxwatch"
 	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jessequinn/drift-analysis-cli/pkg/vault"
 	_ "github.com/lib/pq"
 )
 
@@ -22,13 +26,27 @@ type DatabaseInspector struct {
 	password             string
 	database             string
 	usePrivateIP         bool   // whether to use private IP for Cloud SQL
+	iamAuthN             bool   // log in with IAM database authentication instead of a password
+	vaultLogin           *vault.Credentials // login read from Vault before connecting
 	proxyManager         *ProxyManager // manages Cloud SQL Proxy process
 	sshTunnel            *SSHTunnelManager // manages SSH tunnel through bastion
+	dialer               *Dialer   // shared Cloud SQL connector dialer (default: one per inspection)
+	out                  io.Writer // progress output (default: stdout)
 	
 	// Direct connection fields
 	connectionString string
+
+	// queryTimeout bounds each catalog query (default: defaultQueryTimeout)
+	queryTimeout time.Duration
 }
 
+// defaultQueryTimeout bounds each catalog query unless SetQueryTimeout is called
+const defaultQueryTimeout = 60 * time.Second
+
+// cancelDeadlineDelay is how long a canceled pgx query waits for the server to
+// acknowledge the cancel request before the connection is closed
+const cancelDeadlineDelay = 5 * time.Second
+
 // InspectorConfig holds configuration for creating an inspector
 type InspectorConfig struct {
 	// Cloud SQL connection (recommended)
@@ -62,6 +80,15 @@ type DatabaseSchema struct {
 	Functions    []FunctionInfo
 	Procedures   []ProcedureInfo
 	Extensions   []Extension
+	Settings     []DatabaseSetting
+}
+
+// DatabaseSetting is a GUC override stored in pg_db_role_setting, set with
+// ALTER DATABASE ... SET or, when Role is set, ALTER ROLE ... IN DATABASE ... SET
+type DatabaseSetting struct {
+	Role  string
+	Name  string
+	Value string
 }
 
 // Role represents a PostgreSQL role/user
@@ -76,14 +103,17 @@ type Role struct {
 
 // TableInfo contains table metadata
 type TableInfo struct {
-	Schema      string
-	Name        string
-	Owner       string
-	RowCount    int64
-	SizeBytes   int64
-	Columns     []ColumnInfo
-	Constraints []ConstraintInfo
-	Indexes     []IndexInfo
+	Schema    string
+	Name      string
+	Owner     string
+	RowCount  int64
+	SizeBytes int64
+	// StorageParams holds per-table storage parameters (reloptions) such as
+	// fillfactor and autovacuum_*; TOAST parameters are prefixed "toast."
+	StorageParams map[string]string
+	Columns       []ColumnInfo
+	Constraints   []ConstraintInfo
+	Indexes       []IndexInfo
 }
 
 // ColumnInfo contains column metadata
@@ -188,9 +218,26 @@ func NewInspectorFromConnectionConfig(config *ConnectionConfig) (*DatabaseInspec
 	
 	connName := config.GetConnectionName()
 	
+	// IAM authentication needs the connector, which also reaches private IPs
+	if config.Auth == AuthIAM {
+		return &DatabaseInspector{
+			useCloudSQLConnector:   true,
+			instanceConnectionName: connName,
+			user:                   config.Username,
+			database:               config.Database,
+			usePrivateIP:           config.UsePrivateIP,
+			iamAuthN:               true,
+		}, nil
+	}
+	
 	// For private IP, we need to use the proxy approach
 	if config.UsePrivateIP {
-		return NewInspectorWithProxy(connName, config.Username, config.Password, config.Database, config.UsePrivateIP)
+		inspector, err := NewInspectorWithProxy(connName, config.Username, config.Password, config.Database, config.UsePrivateIP)
+		if err != nil {
+			return nil, err
+		}
+		inspector.vaultLogin = config.Vault
+		return inspector, nil
 	}
 	
 	return &DatabaseInspector{
@@ -200,6 +247,7 @@ func NewInspectorFromConnectionConfig(config *ConnectionConfig) (*DatabaseInspec
 		password:               config.Password,
 		database:               config.Database,
 		usePrivateIP:           config.UsePrivateIP,
+		vaultLogin:             config.Vault,
 	}, nil
 }
 
@@ -236,6 +284,7 @@ func NewInspectorWithSSHTunnel(conn *DatabaseConnection) (*DatabaseInspector, er
 		database:               conn.Database,
 		usePrivateIP:           true,
 		sshTunnel:              sshTunnel,
+		vaultLogin:             conn.Vault,
 		connectionString:       "", // Will be set when tunnel is established
 	}, nil
 }
@@ -245,7 +294,6 @@ func NewInspectorWithProxy(instanceConnectionName, user, password, database stri
 	// Create proxy manager - use cloud-sql-proxy binary instead of gcloud
 	proxyConfig := ProxyConfig{
 		InstanceConnectionName: instanceConnectionName,
-		LocalPort:              5432,
 		UsePrivateIP:           usePrivateIP,
 		UseGcloud:              false, // Use cloud-sql-proxy binary
 	}
@@ -253,9 +301,7 @@ func NewInspectorWithProxy(instanceConnectionName, user, password, database stri
 	proxyManager := NewProxyManager(proxyConfig)
 	
 	// Create direct connection string to localhost (proxy will handle the tunnel)
-	// Increase timeouts for Cloud SQL proxy connections
-	connStr := fmt.Sprintf("host=localhost port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=60 statement_timeout=60000",
-		proxyConfig.LocalPort, user, password, database)
+	connStr := proxyConnectionString(proxyManager.GetLocalPort(), user, password, database)
 	
 	return &DatabaseInspector{
 		useCloudSQLConnector:   false, // Use direct connection to proxy
@@ -269,21 +315,97 @@ func NewInspectorWithProxy(instanceConnectionName, user, password, database stri
 	}, nil
 }
 
+// proxyConnectionString connects to the local port of a Cloud SQL Proxy, with
+// increased timeouts for proxy connections
+func proxyConnectionString(port int, user, password, database string) string {
+	return fmt.Sprintf("host=localhost port=%d user=%s password=%s dbname=%s sslmode=disable connect_timeout=60 statement_timeout=60000",
+		port, user, password, database)
+}
+
+// loginFromVault reads the username and password from Vault. It runs right
+// before connecting, so dynamic database credentials are generated for each
+// inspection.
+func (di *DatabaseInspector) loginFromVault(ctx context.Context) error {
+	username, password, err := di.vaultLogin.Login(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read credentials from Vault: %w", err)
+	}
+	if username != "" {
+		di.user = username
+	}
+	if di.user == "" {
+		return fmt.Errorf("no username in the connection config or the Vault secret")
+	}
+	di.password = password
+	if di.proxyManager != nil {
+		di.connectionString = proxyConnectionString(di.proxyManager.GetLocalPort(), di.user, di.password, di.database)
+	}
+	return nil
+}
+
+// SetOutput sends progress messages of the inspector and its proxy or SSH
+// tunnel to w instead of stdout
+func (di *DatabaseInspector) SetOutput(w io.Writer) {
+	di.out = w
+	if di.proxyManager != nil {
+		di.proxyManager.out = w
+	}
+	if di.sshTunnel != nil {
+		di.sshTunnel.out = w
+	}
+}
+
+// SetDialer makes the inspector connect through a dialer shared with other
+// inspectors instead of creating its own; the caller closes it
+func (di *DatabaseInspector) SetDialer(d *Dialer) {
+	di.dialer = d
+}
+
+// SetIAMAuthN makes a Cloud SQL connector inspector log in with IAM database
+// authentication instead of its password
+func (di *DatabaseInspector) SetIAMAuthN(enabled bool) {
+	di.iamAuthN = enabled
+}
+
+// SetQueryTimeout bounds each catalog query; a query running longer is
+// canceled on the server. Zero restores the default.
+func (di *DatabaseInspector) SetQueryTimeout(timeout time.Duration) {
+	di.queryTimeout = timeout
+}
+
+// queryContext derives the context of a single catalog query from the run
+// context. Both drivers send a cancel request to the server when it is done,
+// so a timed out or interrupted query does not keep running on the database.
+func (di *DatabaseInspector) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := di.queryTimeout
+	if timeout <= 0 {
+		timeout = defaultQueryTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // InspectDatabase connects and extracts detailed schema information
 func (di *DatabaseInspector) InspectDatabase(ctx context.Context) (*DatabaseSchema, error) {
+	out := outputOrStdout(di.out)
+	if di.vaultLogin != nil {
+		if err := di.loginFromVault(ctx); err != nil {
+			return nil, err
+		}
+	}
+	
 	// Start SSH tunnel if configured
 	if di.sshTunnel != nil {
-		fmt.Printf("Starting SSH tunnel for %s...\n", di.instanceConnectionName)
+		fmt.Fprintf(out, "Starting SSH tunnel for %s...\n", di.instanceConnectionName)
 		if err := di.sshTunnel.Start(ctx); err != nil {
 			return nil, fmt.Errorf("failed to start SSH tunnel: %w", err)
 		}
 		defer func() {
-			fmt.Println("Stopping SSH tunnel...")
+			fmt.Fprintln(out, "Stopping SSH tunnel...")
 			if err := di.sshTunnel.Stop(); err != nil {
-				fmt.Printf("Warning: failed to stop SSH tunnel: %v\n", err)
+				fmt.Fprintf(out, "Warning: failed to stop SSH tunnel: %v\n", err)
 			}
 		}()
-		fmt.Println("SSH tunnel established successfully")
+		fmt.Fprintln(out, "SSH tunnel established successfully")
 		
 		// Set connection string to use the tunnel
 		di.connectionString = di.sshTunnel.GetConnectionString(di.user, di.password, di.database)
@@ -291,17 +413,17 @@ func (di *DatabaseInspector) InspectDatabase(ctx context.Context) (*DatabaseSche
 	
 	// Start proxy if configured
 	if di.proxyManager != nil {
-		fmt.Printf("Starting Cloud SQL Proxy for %s...\n", di.instanceConnectionName)
+		fmt.Fprintf(out, "Starting Cloud SQL Proxy for %s...\n", di.instanceConnectionName)
 		if err := di.proxyManager.Start(ctx); err != nil {
 			return nil, fmt.Errorf("failed to start proxy: %w", err)
 		}
 		defer func() {
-			fmt.Println("Stopping Cloud SQL Proxy...")
+			fmt.Fprintln(out, "Stopping Cloud SQL Proxy...")
 			if err := di.proxyManager.Stop(); err != nil {
-				fmt.Printf("Warning: failed to stop proxy: %v\n", err)
+				fmt.Fprintf(out, "Warning: failed to stop proxy: %v\n", err)
 			}
 		}()
-		fmt.Println("Proxy started successfully")
+		fmt.Fprintln(out, "Proxy started successfully")
 	}
 	
 	var db *sql.DB
@@ -319,7 +441,10 @@ func (di *DatabaseInspector) InspectDatabase(ctx context.Context) (*DatabaseSche
 	}
 	defer cleanup()
 
-	if err := db.PingContext(ctx); err != nil {
+	pingCtx, cancel := di.queryContext(ctx)
+	err = db.PingContext(pingCtx)
+	cancel()
+	if err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
@@ -330,6 +455,11 @@ func (di *DatabaseInspector) InspectDatabase(ctx context.Context) (*DatabaseSche
 		return nil, fmt.Errorf("failed to get database info: %w", err)
 	}
 
+	// Get database-level setting overrides
+	if err := di.getDatabaseSettings(ctx, db, schema); err != nil {
+		return nil, fmt.Errorf("failed to get database settings: %w", err)
+	}
+
 	// Get roles
 	if err := di.getRoles(ctx, db, schema); err != nil {
 		return nil, fmt.Errorf("failed to get roles: %w", err)
@@ -370,25 +500,21 @@ func (di *DatabaseInspector) InspectDatabase(ctx context.Context) (*DatabaseSche
 
 // connectWithCloudSQL establishes connection using Cloud SQL connector
 func (di *DatabaseInspector) connectWithCloudSQL(ctx context.Context) (*sql.DB, func() error, error) {
-	// Create dialer with optional private IP support
-	var dialerOpts []cloudsqlconn.Option
-	if di.usePrivateIP {
-		dialerOpts = append(dialerOpts, cloudsqlconn.WithDefaultDialOptions(cloudsqlconn.WithPrivateIP()))
-	}
-	
-	d, err := cloudsqlconn.NewDialer(ctx, dialerOpts...)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create dialer: %w", err)
+	// Use the shared dialer, or one of our own that is closed with the connection
+	d := di.dialer
+	cleanup := func() error { return nil }
+	if d == nil {
+		d = NewDialer(ctx)
+		cleanup = d.Close
 	}
 
-	// Cleanup function
-	cleanup := func() error {
-		return d.Close()
+	// Create pgx connection config; with IAM authentication the connector
+	// supplies the login token
+	dsn := fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable", di.user, di.password, di.database)
+	if di.iamAuthN {
+		dsn = fmt.Sprintf("user=%s dbname=%s sslmode=disable", di.user, di.database)
 	}
-
-	// Create pgx connection config
-	connConfig, err := pgx.ParseConfig(fmt.Sprintf("user=%s password=%s dbname=%s sslmode=disable",
-		di.user, di.password, di.database))
+	connConfig, err := pgx.ParseConfig(dsn)
 	if err != nil {
 		cleanup()
 		return nil, nil, fmt.Errorf("failed to parse config: %w", err)
@@ -396,7 +522,14 @@ func (di *DatabaseInspector) connectWithCloudSQL(ctx context.Context) (*sql.DB,
 
 	// Set up Cloud SQL dialer
 	connConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
-		return d.Dial(ctx, di.instanceConnectionName)
+		return d.Dial(ctx, di.instanceConnectionName, di.usePrivateIP, di.iamAuthN)
+	}
+
+	// pgx closes the connection when a query's context is done, which leaves
+	// the query running on the server; send a cancel request first, as lib/pq
+	// does for direct connections
+	connConfig.BuildContextWatcherHandler = func(pgConn *pgconn.PgConn) ctxwatch.Handler {
+		return &pgconn.CancelRequestContextWatcherHandler{Conn: pgConn, DeadlineDelay: cancelDeadlineDelay}
 	}
 
 	// Register config and get connection string
@@ -437,6 +570,9 @@ func (di *DatabaseInspector) connectDirect(ctx context.Context) (*sql.DB, func()
 
 // getDatabaseInfo retrieves basic database information
 func (di *DatabaseInspector) getDatabaseInfo(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			current_database(),
@@ -454,8 +590,57 @@ func (di *DatabaseInspector) getDatabaseInfo(ctx context.Context, db *sql.DB, sc
 	)
 }
 
+// getDatabaseSettings retrieves setting overrides for the current database,
+// both database-wide and per role
+func (di *DatabaseInspector) getDatabaseSettings(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
+	query := `
+		SELECT
+			COALESCE(r.rolname, '') as role,
+			c.entry
+		FROM pg_catalog.pg_db_role_setting s
+		JOIN pg_catalog.pg_database d ON d.oid = s.setdatabase
+		LEFT JOIN pg_catalog.pg_roles r ON r.oid = s.setrole
+		CROSS JOIN LATERAL unnest(s.setconfig) AS c(entry)
+		WHERE d.datname = current_database()
+		ORDER BY 1, 2
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var role, entry string
+		if err := rows.Scan(&role, &entry); err != nil {
+			return err
+		}
+		if setting, ok := parseSetting(role, entry); ok {
+			schema.Settings = append(schema.Settings, setting)
+		}
+	}
+
+	return rows.Err()
+}
+
+// parseSetting splits a setconfig entry of the form name=value
+func parseSetting(role, entry string) (DatabaseSetting, bool) {
+	name, value, ok := strings.Cut(entry, "=")
+	if !ok || name == "" {
+		return DatabaseSetting{}, false
+	}
+	return DatabaseSetting{Role: role, Name: name, Value: value}, true
+}
+
 // getRoles retrieves all roles and their properties
 func (di *DatabaseInspector) getRoles(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			r.rolname,
@@ -505,6 +690,9 @@ func (di *DatabaseInspector) getRoles(ctx context.Context, db *sql.DB, schema *D
 
 // getExtensions retrieves installed extensions
 func (di *DatabaseInspector) getExtensions(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			extname,
@@ -532,37 +720,30 @@ func (di *DatabaseInspector) getExtensions(ctx context.Context, db *sql.DB, sche
 	return rows.Err()
 }
 
-// getTables retrieves all user tables with detailed information
+// getTables retrieves all user tables with detailed information. The table
+// list is read before the details, so each query runs under its own deadline.
 func (di *DatabaseInspector) getTables(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
-	query := `
-		SELECT 
-			schemaname,
-			tablename,
-			tableowner
-		FROM pg_catalog.pg_tables
-		WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
-		ORDER BY schemaname, tablename
-	`
-
-	rows, err := db.QueryContext(ctx, query)
+	tables, err := di.listTables(ctx, db)
 	if err != nil {
 		return err
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var table TableInfo
-		if err := rows.Scan(&table.Schema, &table.Name, &table.Owner); err != nil {
-			return err
-		}
 
+	for _, table := range tables {
 		// Get row count and size
 		if err := di.getTableStats(ctx, db, &table); err != nil {
-			// Log but don't fail - stats might not be available
+			// Stats might not be available, but an interrupted run must stop
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
 			table.RowCount = -1
 			table.SizeBytes = -1
 		}
 
+		// Get storage parameters
+		if err := di.getTableStorageParams(ctx, db, &table); err != nil {
+			return fmt.Errorf("failed to get storage parameters for %s.%s: %w", table.Schema, table.Name, err)
+		}
+
 		// Get columns
 		if err := di.getTableColumns(ctx, db, &table); err != nil {
 			return fmt.Errorf("failed to get columns for %s.%s: %w", table.Schema, table.Name, err)
@@ -581,11 +762,46 @@ func (di *DatabaseInspector) getTables(ctx context.Context, db *sql.DB, schema *
 		schema.Tables = append(schema.Tables, table)
 	}
 
-	return rows.Err()
+	return nil
+}
+
+// listTables retrieves the schema, name and owner of all user tables
+func (di *DatabaseInspector) listTables(ctx context.Context, db *sql.DB) ([]TableInfo, error) {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
+	query := `
+		SELECT 
+			schemaname,
+			tablename,
+			tableowner
+		FROM pg_catalog.pg_tables
+		WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
+		ORDER BY schemaname, tablename
+	`
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var table TableInfo
+		if err := rows.Scan(&table.Schema, &table.Name, &table.Owner); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
 }
 
 // getTableStats retrieves row count and size
 func (di *DatabaseInspector) getTableStats(ctx context.Context, db *sql.DB, table *TableInfo) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			COALESCE(n_live_tup, 0) as row_count,
@@ -599,8 +815,71 @@ func (di *DatabaseInspector) getTableStats(ctx context.Context, db *sql.DB, tabl
 	)
 }
 
+// getTableStorageParams retrieves storage parameters set with
+// ALTER TABLE ... SET (...) on the table and its TOAST table
+func (di *DatabaseInspector) getTableStorageParams(ctx context.Context, db *sql.DB, table *TableInfo) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
+	query := `
+		SELECT o.opt
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		CROSS JOIN LATERAL unnest(c.reloptions) AS o(opt)
+		WHERE n.nspname = $1 AND c.relname = $2
+		UNION ALL
+		SELECT 'toast.' || o.opt
+		FROM pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_catalog.pg_class t ON t.oid = c.reltoastrelid
+		CROSS JOIN LATERAL unnest(t.reloptions) AS o(opt)
+		WHERE n.nspname = $1 AND c.relname = $2
+	`
+
+	rows, err := db.QueryContext(ctx, query, table.Schema, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry string
+		if err := rows.Scan(&entry); err != nil {
+			return err
+		}
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok || name == "" {
+			continue
+		}
+		if table.StorageParams == nil {
+			table.StorageParams = make(map[string]string)
+		}
+		table.StorageParams[name] = value
+	}
+
+	return rows.Err()
+}
+
+// formatStorageParams renders storage parameters as sorted name=value pairs
+func formatStorageParams(params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, params[name]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
 // getTableColumns retrieves column information
 func (di *DatabaseInspector) getTableColumns(ctx context.Context, db *sql.DB, table *TableInfo) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			column_name,
@@ -632,6 +911,9 @@ func (di *DatabaseInspector) getTableColumns(ctx context.Context, db *sql.DB, ta
 
 // getTableConstraints retrieves constraint information
 func (di *DatabaseInspector) getTableConstraints(ctx context.Context, db *sql.DB, table *TableInfo) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			con.conname as constraint_name,
@@ -668,6 +950,9 @@ func (di *DatabaseInspector) getTableConstraints(ctx context.Context, db *sql.DB
 
 // getTableIndexes retrieves index information
 func (di *DatabaseInspector) getTableIndexes(ctx context.Context, db *sql.DB, table *TableInfo) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			i.relname as index_name,
@@ -706,6 +991,9 @@ func (di *DatabaseInspector) getTableIndexes(ctx context.Context, db *sql.DB, ta
 
 // getViews retrieves view information
 func (di *DatabaseInspector) getViews(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			schemaname,
@@ -735,11 +1023,19 @@ func (di *DatabaseInspector) getViews(ctx context.Context, db *sql.DB, schema *D
 }
 
 func (di *DatabaseInspector) getSequences(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			schemaname,
 			sequencename,
-			sequenceowner
+			sequenceowner,
+			data_type::text,
+			start_value,
+			min_value,
+			max_value,
+			increment_by
 		FROM pg_catalog.pg_sequences
 		WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
 		ORDER BY schemaname, sequencename
@@ -753,7 +1049,7 @@ func (di *DatabaseInspector) getSequences(ctx context.Context, db *sql.DB, schem
 
 	for rows.Next() {
 		var seq SequenceInfo
-		if err := rows.Scan(&seq.Schema, &seq.Name, &seq.Owner); err != nil {
+		if err := rows.Scan(&seq.Schema, &seq.Name, &seq.Owner, &seq.DataType, &seq.StartValue, &seq.MinValue, &seq.MaxValue, &seq.Increment); err != nil {
 			return err
 		}
 		schema.Sequences = append(schema.Sequences, seq)
@@ -763,6 +1059,9 @@ func (di *DatabaseInspector) getSequences(ctx context.Context, db *sql.DB, schem
 }
 
 func (di *DatabaseInspector) getFunctions(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			n.nspname as schema,
@@ -770,7 +1069,8 @@ func (di *DatabaseInspector) getFunctions(ctx context.Context, db *sql.DB, schem
 			pg_catalog.pg_get_userbyid(p.proowner) as owner,
 			l.lanname as language,
 			pg_catalog.pg_get_function_result(p.oid) as return_type,
-			pg_catalog.pg_get_function_arguments(p.oid) as arguments
+			pg_catalog.pg_get_function_arguments(p.oid) as arguments,
+			pg_catalog.pg_get_functiondef(p.oid) as definition
 		FROM pg_catalog.pg_proc p
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
 		LEFT JOIN pg_catalog.pg_language l ON l.oid = p.prolang
@@ -787,7 +1087,7 @@ func (di *DatabaseInspector) getFunctions(ctx context.Context, db *sql.DB, schem
 
 	for rows.Next() {
 		var fn FunctionInfo
-		if err := rows.Scan(&fn.Schema, &fn.Name, &fn.Owner, &fn.Language, &fn.ReturnType, &fn.Arguments); err != nil {
+		if err := rows.Scan(&fn.Schema, &fn.Name, &fn.Owner, &fn.Language, &fn.ReturnType, &fn.Arguments, &fn.Definition); err != nil {
 			return err
 		}
 		schema.Functions = append(schema.Functions, fn)
@@ -797,13 +1097,17 @@ func (di *DatabaseInspector) getFunctions(ctx context.Context, db *sql.DB, schem
 }
 
 func (di *DatabaseInspector) getProcedures(ctx context.Context, db *sql.DB, schema *DatabaseSchema) error {
+	ctx, cancel := di.queryContext(ctx)
+	defer cancel()
+
 	query := `
 		SELECT 
 			n.nspname as schema,
 			p.proname as name,
 			pg_catalog.pg_get_userbyid(p.proowner) as owner,
 			l.lanname as language,
-			pg_catalog.pg_get_function_arguments(p.oid) as arguments
+			pg_catalog.pg_get_function_arguments(p.oid) as arguments,
+			pg_catalog.pg_get_functiondef(p.oid) as definition
 		FROM pg_catalog.pg_proc p
 		LEFT JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace
 		LEFT JOIN pg_catalog.pg_language l ON l.oid = p.prolang
@@ -820,7 +1124,7 @@ func (di *DatabaseInspector) getProcedures(ctx context.Context, db *sql.DB, sche
 
 	for rows.Next() {
 		var proc ProcedureInfo
-		if err := rows.Scan(&proc.Schema, &proc.Name, &proc.Owner, &proc.Language, &proc.Arguments); err != nil {
+		if err := rows.Scan(&proc.Schema, &proc.Name, &proc.Owner, &proc.Language, &proc.Arguments, &proc.Definition); err != nil {
 			return err
 		}
 		schema.Procedures = append(schema.Procedures, proc)
@@ -838,6 +1142,22 @@ func (schema *DatabaseSchema) GenerateDDL() string {
 	sb.WriteString(fmt.Sprintf("-- Encoding: %s\n", schema.Encoding))
 	sb.WriteString(fmt.Sprintf("-- Collation: %s\n\n", schema.Collation))
 
+	// Setting overrides
+	if len(schema.Settings) > 0 {
+		sb.WriteString("-- Setting overrides\n")
+		for _, setting := range schema.Settings {
+			value := strings.ReplaceAll(setting.Value, "'", "''")
+			if setting.Role == "" {
+				sb.WriteString(fmt.Sprintf("ALTER DATABASE %s SET %s = '%s';\n",
+					schema.DatabaseName, setting.Name, value))
+			} else {
+				sb.WriteString(fmt.Sprintf("ALTER ROLE %s IN DATABASE %s SET %s = '%s';\n",
+					setting.Role, schema.DatabaseName, setting.Name, value))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	// Extensions
 	if len(schema.Extensions) > 0 {
 		sb.WriteString("-- Extensions\n")
@@ -881,37 +1201,8 @@ func (schema *DatabaseSchema) GenerateDDL() string {
 		if table.RowCount >= 0 {
 			sb.WriteString(fmt.Sprintf("-- Rows: %d\n", table.RowCount))
 		}
-		sb.WriteString(fmt.Sprintf("CREATE TABLE %s.%s (\n", table.Schema, table.Name))
-
-		// Columns
-		colDefs := []string{}
-		for _, col := range table.Columns {
-			def := fmt.Sprintf("    %s %s", col.Name, col.DataType)
-			if !col.IsNullable {
-				def += " NOT NULL"
-			}
-			if col.DefaultValue != nil {
-				def += fmt.Sprintf(" DEFAULT %s", *col.DefaultValue)
-			}
-			if col.IsIdentity {
-				def += " GENERATED ALWAYS AS IDENTITY"
-			}
-			colDefs = append(colDefs, def)
-		}
-		sb.WriteString(strings.Join(colDefs, ",\n"))
-
-		// Constraints
-		if len(table.Constraints) > 0 {
-			sb.WriteString(",\n")
-			constraintDefs := []string{}
-			for _, con := range table.Constraints {
-				constraintDefs = append(constraintDefs, fmt.Sprintf("    CONSTRAINT %s %s", con.Name, con.Definition))
-			}
-			sb.WriteString(strings.Join(constraintDefs, ",\n"))
-		}
-
-		sb.WriteString("\n);\n")
-		sb.WriteString(fmt.Sprintf("ALTER TABLE %s.%s OWNER TO %s;\n", table.Schema, table.Name, table.Owner))
+		writeCreateTable(&sb, table)
+		sb.WriteString(fmt.Sprintf("ALTER TABLE %s OWNER TO %s;\n", qualifiedName(table.Schema, table.Name), quoteIdent(table.Owner)))
 
 		// Indexes (excluding primary key which is already in constraints)
 		for _, idx := range table.Indexes {
@@ -937,6 +1228,50 @@ func (schema *DatabaseSchema) GenerateDDL() string {
 	return sb.String()
 }
 
+// writeCreateTable writes the CREATE TABLE statement of a table, with its
+// columns, constraints and storage parameters
+func writeCreateTable(sb *strings.Builder, table TableInfo) {
+	sb.WriteString(fmt.Sprintf("CREATE TABLE %s (\n", qualifiedName(table.Schema, table.Name)))
+
+	// Columns
+	colDefs := []string{}
+	for _, col := range table.Columns {
+		colDefs = append(colDefs, "    "+columnDefinition(col))
+	}
+	sb.WriteString(strings.Join(colDefs, ",\n"))
+
+	// Constraints
+	if len(table.Constraints) > 0 {
+		sb.WriteString(",\n")
+		constraintDefs := []string{}
+		for _, con := range table.Constraints {
+			constraintDefs = append(constraintDefs, fmt.Sprintf("    CONSTRAINT %s %s", quoteIdent(con.Name), con.Definition))
+		}
+		sb.WriteString(strings.Join(constraintDefs, ",\n"))
+	}
+
+	sb.WriteString("\n)")
+	if len(table.StorageParams) > 0 {
+		sb.WriteString(fmt.Sprintf(" WITH (%s)", formatStorageParams(table.StorageParams)))
+	}
+	sb.WriteString(";\n")
+}
+
+// columnDefinition returns the definition of a column as in CREATE TABLE
+func columnDefinition(col ColumnInfo) string {
+	def := fmt.Sprintf("%s %s", quoteIdent(col.Name), col.DataType)
+	if !col.IsNullable {
+		def += " NOT NULL"
+	}
+	if col.DefaultValue != nil {
+		def += fmt.Sprintf(" DEFAULT %s", *col.DefaultValue)
+	}
+	if col.IsIdentity {
+		def += " GENERATED ALWAYS AS IDENTITY"
+	}
+	return def
+}
+
 // StringArray is a helper type for scanning PostgreSQL arrays
 type StringArray []string
 
@@ -989,6 +1324,19 @@ func (schema *DatabaseSchema) FormatSchemaReport() string {
 	sb.WriteString(fmt.Sprintf("Encoding:  %s\n", schema.Encoding))
 	sb.WriteString(fmt.Sprintf("Collation: %s\n\n", schema.Collation))
 
+	// Setting overrides
+	if len(schema.Settings) > 0 {
+		sb.WriteString("Setting Overrides:\n")
+		for _, setting := range schema.Settings {
+			scope := ""
+			if setting.Role != "" {
+				scope = fmt.Sprintf(" (role %s)", setting.Role)
+			}
+			sb.WriteString(fmt.Sprintf("  • %s = %s%s\n", setting.Name, setting.Value, scope))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Extensions
 	if len(schema.Extensions) > 0 {
 		sb.WriteString("Extensions:\n")
@@ -1048,6 +1396,9 @@ func (schema *DatabaseSchema) FormatSchemaReport() string {
 			}
 			sb.WriteString(fmt.Sprintf("    Columns: %d, Indexes: %d, Constraints: %d\n",
 				len(table.Columns), len(table.Indexes), len(table.Constraints)))
+			if len(table.StorageParams) > 0 {
+				sb.WriteString(fmt.Sprintf("    Storage: %s\n", formatStorageParams(table.StorageParams)))
+			}
 		}
 		sb.WriteString(fmt.Sprintf("\nTotal Rows: %d, Total Size: %s\n\n", totalRows, formatBytes(totalSize)))
 	}
@@ -0,0 +1,244 @@
+package sql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Inventory is the size of an instance: the vCPUs and memory of its tier and
+// its provisioned storage
+type Inventory struct {
+	VCPUs    int64   `json:"vcpus" yaml:"vcpus"`
+	MemoryGB float64 `json:"memory_gb" yaml:"memory_gb"`
+	DiskGB   int64   `json:"disk_gb" yaml:"disk_gb"`
+	// UnknownTier is set when the tier could not be sized; VCPUs and MemoryGB are then zero
+	UnknownTier string `json:"unknown_tier,omitempty" yaml:"unknown_tier,omitempty"`
+}
+
+// memoryPerVCPU is the memory in GB per vCPU of predefined tiers by family
+var memoryPerVCPU = map[string]float64{
+	"db-n1-standard":        3.75,
+	"db-n1-highmem":         6.5,
+	"db-perf-optimized-N":   8,
+	"db-memory-optimized-N": 32,
+}
+
+// sharedCoreTiers are tiers without a vCPU count in their name; they run on a
+// shared vCPU, counted as one
+var sharedCoreTiers = map[string]float64{
+	"db-f1-micro": 0.6,
+	"db-g1-small": 1.7,
+}
+
+// tierSize returns the vCPUs and memory of a tier, covering shared-core,
+// predefined and custom tiers such as db-custom-4-15360
+func tierSize(tier string) (vcpus int64, memoryGB float64, ok bool) {
+	if memoryGB, found := sharedCoreTiers[tier]; found {
+		return 1, memoryGB, true
+	}
+
+	if custom, found := strings.CutPrefix(tier, "db-custom-"); found {
+		cpus, memoryMB, found := strings.Cut(custom, "-")
+		if !found {
+			return 0, 0, false
+		}
+		vcpus, err := strconv.ParseInt(cpus, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		mb, err := strconv.ParseInt(memoryMB, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+		return vcpus, float64(mb) / 1024, true
+	}
+
+	i := strings.LastIndex(tier, "-")
+	if i < 0 {
+		return 0, 0, false
+	}
+	ratio, found := memoryPerVCPU[tier[:i]]
+	if !found {
+		return 0, 0, false
+	}
+	vcpus, err := strconv.ParseInt(tier[i+1:], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return vcpus, float64(vcpus) * ratio, true
+}
+
+// buildInventory sizes an instance from its tier and disk
+func buildInventory(config *DatabaseConfig) *Inventory {
+	if config == nil {
+		return nil
+	}
+	inv := &Inventory{DiskGB: config.DiskSize}
+	vcpus, memoryGB, ok := tierSize(config.Tier)
+	if !ok {
+		inv.UnknownTier = config.Tier
+		return inv
+	}
+	inv.VCPUs = vcpus
+	inv.MemoryGB = memoryGB
+	return inv
+}
+
+// CapacityEnvelope caps the combined size of the instances carrying the given
+// labels in each project. Zero caps are not checked.
+type CapacityEnvelope struct {
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	MaxVCPUs    int64             `yaml:"max_vcpus,omitempty"`
+	MaxMemoryGB float64           `yaml:"max_memory_gb,omitempty"`
+	MaxDiskGB   int64             `yaml:"max_disk_gb,omitempty"`
+}
+
+// Validate checks that the envelope caps something and no cap is negative
+func (e CapacityEnvelope) Validate() error {
+	if e.MaxVCPUs < 0 || e.MaxMemoryGB < 0 || e.MaxDiskGB < 0 {
+		return fmt.Errorf("capacity caps must not be negative")
+	}
+	if e.MaxVCPUs == 0 && e.MaxMemoryGB == 0 && e.MaxDiskGB == 0 {
+		return fmt.Errorf("capacity envelope sets no max_vcpus, max_memory_gb or max_disk_gb")
+	}
+	return nil
+}
+
+// matches reports whether an instance carries every label of the envelope
+func (e CapacityEnvelope) matches(labels map[string]string) bool {
+	for key, value := range e.Labels {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// capacityGroup is the instances of one project that share an envelope
+type capacityGroup struct {
+	envelope  int
+	project   string
+	instances []*InstanceDrift
+}
+
+// CheckCapacity totals the inventory of the instances under each envelope per
+// project and flags every instance of a project above a cap. Each instance
+// counts towards the first envelope its labels match.
+func CheckCapacity(r *DriftReport, envelopes []CapacityEnvelope) {
+	var groups []*capacityGroup
+	index := make(map[string]*capacityGroup)
+	for _, inst := range r.Instances {
+		if inst.Inventory == nil {
+			continue
+		}
+		for i, envelope := range envelopes {
+			if !envelope.matches(inst.Labels) {
+				continue
+			}
+			key := fmt.Sprintf("%d/%s", i, inst.Project)
+			group, found := index[key]
+			if !found {
+				group = &capacityGroup{envelope: i, project: inst.Project}
+				index[key] = group
+				groups = append(groups, group)
+			}
+			group.instances = append(group.instances, inst)
+			break
+		}
+	}
+
+	for _, group := range groups {
+		var total Inventory
+		for _, inst := range group.instances {
+			total.VCPUs += inst.Inventory.VCPUs
+			total.MemoryGB += inst.Inventory.MemoryGB
+			total.DiskGB += inst.Inventory.DiskGB
+		}
+		for _, inst := range group.instances {
+			drifted := len(inst.Drifts) > 0
+			inst.Drifts = envelopes[group.envelope].check(group.project, &total, inst.Drifts)
+			if !drifted && len(inst.Drifts) > 0 {
+				r.DriftedInstances++
+			}
+		}
+	}
+}
+
+// check appends a drift per cap the project total exceeds
+func (e CapacityEnvelope) check(project string, total *Inventory, drifts []Drift) []Drift {
+	if e.MaxVCPUs > 0 && total.VCPUs > e.MaxVCPUs {
+		drifts = checkCapacityVCPUs.Append(drifts, fmt.Sprintf("<= %d in %s", e.MaxVCPUs, project), fmt.Sprintf("%d", total.VCPUs))
+	}
+	if e.MaxMemoryGB > 0 && total.MemoryGB > e.MaxMemoryGB {
+		drifts = checkCapacityMemory.Append(drifts, fmt.Sprintf("<= %.1f in %s", e.MaxMemoryGB, project), fmt.Sprintf("%.1f", total.MemoryGB))
+	}
+	if e.MaxDiskGB > 0 && total.DiskGB > e.MaxDiskGB {
+		drifts = checkCapacityDisk.Append(drifts, fmt.Sprintf("<= %d in %s", e.MaxDiskGB, project), fmt.Sprintf("%d", total.DiskGB))
+	}
+	return drifts
+}
+
+// FormatInventory renders the combined size of the instances per project and
+// the fleet total
+func FormatInventory(instances []*InstanceDrift) string {
+	type projectTotal struct {
+		Inventory
+		instances int
+		unknown   bool
+	}
+	totals := make(map[string]*projectTotal)
+	for _, inst := range instances {
+		inv := inst.Inventory
+		if inv == nil {
+			continue
+		}
+		t, found := totals[inst.Project]
+		if !found {
+			t = &projectTotal{}
+			totals[inst.Project] = t
+		}
+		t.instances++
+		t.VCPUs += inv.VCPUs
+		t.MemoryGB += inv.MemoryGB
+		t.DiskGB += inv.DiskGB
+		t.unknown = t.unknown || inv.UnknownTier != ""
+	}
+	if len(totals) == 0 {
+		return ""
+	}
+
+	projects := make([]string, 0, len(totals))
+	for project := range totals {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	var sb strings.Builder
+	var fleet projectTotal
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  Project\tInstances\tvCPUs\tMemory (GB)\tDisk (GB)")
+	for _, project := range projects {
+		t := totals[project]
+		name := project
+		if t.unknown {
+			name += " *"
+		}
+		fmt.Fprintf(w, "  %s\t%d\t%d\t%.1f\t%d\n", name, t.instances, t.VCPUs, t.MemoryGB, t.DiskGB)
+		fleet.instances += t.instances
+		fleet.VCPUs += t.VCPUs
+		fleet.MemoryGB += t.MemoryGB
+		fleet.DiskGB += t.DiskGB
+		fleet.unknown = fleet.unknown || t.unknown
+	}
+	fmt.Fprintf(w, "  Total\t%d\t%d\t%.1f\t%d\n", fleet.instances, fleet.VCPUs, fleet.MemoryGB, fleet.DiskGB)
+	w.Flush()
+
+	out := "Inventory (vCPUs and memory from tiers, provisioned disk):\n" + sb.String()
+	if fleet.unknown {
+		out += "* includes tiers that could not be sized\n"
+	}
+	return out + "\n"
+}
@@ -0,0 +1,115 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTierSize(t *testing.T) {
+	tests := []struct {
+		tier     string
+		vcpus    int64
+		memoryGB float64
+		ok       bool
+	}{
+		{"db-custom-4-15360", 4, 15, true},
+		{"db-n1-standard-8", 8, 30, true},
+		{"db-n1-highmem-2", 2, 13, true},
+		{"db-perf-optimized-N-16", 16, 128, true},
+		{"db-f1-micro", 1, 0.6, true},
+		{"db-custom-4", 0, 0, false},
+		{"db-unknown-4", 0, 0, false},
+		{"", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		vcpus, memoryGB, ok := tierSize(tt.tier)
+		if vcpus != tt.vcpus || memoryGB != tt.memoryGB || ok != tt.ok {
+			t.Errorf("tierSize(%q) = %d, %v, %v, want %d, %v, %v",
+				tt.tier, vcpus, memoryGB, ok, tt.vcpus, tt.memoryGB, tt.ok)
+		}
+	}
+}
+
+func TestBuildInventory(t *testing.T) {
+	inv := buildInventory(&DatabaseConfig{Tier: "db-custom-2-8192", DiskSize: 100})
+	if inv.VCPUs != 2 || inv.MemoryGB != 8 || inv.DiskGB != 100 || inv.UnknownTier != "" {
+		t.Errorf("buildInventory() = %+v", inv)
+	}
+
+	inv = buildInventory(&DatabaseConfig{Tier: "db-unknown", DiskSize: 20})
+	if inv.UnknownTier != "db-unknown" || inv.DiskGB != 20 || inv.VCPUs != 0 {
+		t.Errorf("buildInventory() of an unknown tier = %+v", inv)
+	}
+
+	if buildInventory(nil) != nil {
+		t.Error("buildInventory(nil) should be nil")
+	}
+}
+
+func TestCheckCapacity(t *testing.T) {
+	envelopes := []CapacityEnvelope{
+		{Labels: map[string]string{"env": "prod"}, MaxVCPUs: 16, MaxDiskGB: 1000},
+		{MaxVCPUs: 4, MaxMemoryGB: 16},
+	}
+	for _, e := range envelopes {
+		if err := e.Validate(); err != nil {
+			t.Fatalf("Validate() = %v", err)
+		}
+	}
+
+	prod := map[string]string{"env": "prod"}
+	r := &DriftReport{Instances: []*InstanceDrift{
+		// 20 vCPUs in p1 exceed the prod cap; only the first envelope applies
+		{Project: "p1", Name: "prod-a", Labels: prod, Inventory: &Inventory{VCPUs: 12, MemoryGB: 48, DiskGB: 500}},
+		{Project: "p1", Name: "prod-b", Labels: prod, Inventory: &Inventory{VCPUs: 8, MemoryGB: 32, DiskGB: 200}},
+		// Totals are per project
+		{Project: "p2", Name: "prod-c", Labels: prod, Inventory: &Inventory{VCPUs: 8, MemoryGB: 32, DiskGB: 200}},
+		{Project: "p1", Name: "dev-a", Inventory: &Inventory{VCPUs: 2, MemoryGB: 8}},
+		{Project: "p2", Name: "dev-b", Inventory: &Inventory{VCPUs: 4, MemoryGB: 26}},
+	}}
+	CheckCapacity(r, envelopes)
+
+	want := map[string]string{
+		"prod-a": "capacity.max_vcpus",
+		"prod-b": "capacity.max_vcpus",
+		"prod-c": "",
+		"dev-a":  "",
+		"dev-b":  "capacity.max_memory_gb",
+	}
+	for _, inst := range r.Instances {
+		var fields []string
+		for _, d := range inst.Drifts {
+			fields = append(fields, d.Field)
+		}
+		if got := strings.Join(fields, ","); got != want[inst.Name] {
+			t.Errorf("%s drifts = %s, want %s", inst.Name, got, want[inst.Name])
+		}
+	}
+	if got := r.Instances[0].Drifts[0]; got.Expected != "<= 16 in p1" || got.Actual != "20" {
+		t.Errorf("drift = %s / %s, want <= 16 in p1 / 20", got.Expected, got.Actual)
+	}
+	if r.DriftedInstances != 3 {
+		t.Errorf("DriftedInstances = %d, want 3", r.DriftedInstances)
+	}
+
+	if err := (CapacityEnvelope{MaxDiskGB: -1}).Validate(); err == nil {
+		t.Error("Validate() of a negative cap = nil, want error")
+	}
+}
+
+func TestFormatInventory(t *testing.T) {
+	text := FormatInventory([]*InstanceDrift{
+		{Project: "p1", Name: "a", Inventory: &Inventory{VCPUs: 4, MemoryGB: 15, DiskGB: 100}},
+		{Project: "p1", Name: "b", Inventory: &Inventory{VCPUs: 2, MemoryGB: 7.5, DiskGB: 50}},
+		{Project: "p2", Name: "c", Inventory: &Inventory{DiskGB: 10, UnknownTier: "db-unknown"}},
+	})
+	for _, want := range []string{"p1", "p2 *", "22.5", "Total", "160", "could not be sized"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatInventory() missing %q:\n%s", want, text)
+		}
+	}
+	if FormatInventory(nil) != "" {
+		t.Error("FormatInventory(nil) should be empty")
+	}
+}
@@ -0,0 +1,448 @@
+package sql
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// migration collects the statements of a migration script. Statements that
+// lose data or need a decision are preceded by a REVIEW comment, and changes
+// no statement can be generated for by a TODO comment.
+type migration struct {
+	sb strings.Builder
+}
+
+func (m *migration) section(title string) {
+	m.sb.WriteString(fmt.Sprintf("\n-- %s\n", title))
+}
+
+func (m *migration) statement(format string, args ...any) {
+	stmt := strings.TrimRight(strings.TrimSpace(fmt.Sprintf(format, args...)), ";")
+	m.sb.WriteString(stmt + ";\n")
+}
+
+func (m *migration) review(format string, args ...any) {
+	m.sb.WriteString("-- REVIEW: " + fmt.Sprintf(format, args...) + "\n")
+}
+
+func (m *migration) todo(format string, args ...any) {
+	m.sb.WriteString("-- TODO: " + fmt.Sprintf(format, args...) + "\n")
+}
+
+// MigrationSQL returns candidate DDL that brings a database with the new
+// schema of the diff back to the old one, e.g. a drifted database back to its
+// cached baseline. The script is a starting point for a DBA, not something
+// to run unattended: it runs in one transaction, marks statements that drop
+// data or may fail on existing rows with REVIEW comments, and lists changes it
+// cannot write DDL for, such as routines cached without a definition, as TODO
+// comments.
+func (sd *SchemaDiff) MigrationSQL() string {
+	database := sd.Database
+	m := &migration{}
+
+	m.sb.WriteString("-- Candidate migration generated from a schema diff. REVIEW REQUIRED:\n")
+	m.sb.WriteString("-- check every statement (data loss, locks, dependent objects) before\n")
+	m.sb.WriteString("-- running it.\n")
+	if database != "" {
+		m.sb.WriteString(fmt.Sprintf("-- Database: %s\n", database))
+	}
+	m.sb.WriteString("\nBEGIN;\n")
+
+	if len(sd.DeletedExtensions) > 0 {
+		m.section("Extensions missing from the database")
+		for _, e := range sd.DeletedExtensions {
+			m.statement("CREATE EXTENSION IF NOT EXISTS %s WITH SCHEMA %s VERSION %s", quoteIdent(e.Name), quoteIdent(e.Schema), quoteLiteral(e.Version))
+		}
+	}
+
+	// Views and routines may depend on the tables and on each other, so the
+	// extra ones are dropped first and the missing ones created last
+	if len(sd.AddedViews) > 0 {
+		m.section("Views not in the baseline")
+		for _, v := range sd.AddedViews {
+			m.statement("DROP VIEW IF EXISTS %s", qualifiedName(v.Schema, v.Name))
+		}
+	}
+	if len(sd.AddedFunctions) > 0 || len(sd.AddedProcedures) > 0 {
+		m.section("Routines not in the baseline")
+		for _, f := range sd.AddedFunctions {
+			m.statement("DROP FUNCTION IF EXISTS %s(%s)", qualifiedName(f.Schema, f.Name), identityArguments(f.Arguments))
+		}
+		for _, p := range sd.AddedProcedures {
+			m.statement("DROP PROCEDURE IF EXISTS %s(%s)", qualifiedName(p.Schema, p.Name), identityArguments(p.Arguments))
+		}
+	}
+
+	if len(sd.DeletedSequences) > 0 || len(sd.ModifiedSequences) > 0 {
+		m.section("Sequences")
+		for _, s := range sd.DeletedSequences {
+			m.statement("CREATE SEQUENCE %s%s", qualifiedName(s.Schema, s.Name), sequenceOptions(s, false))
+		}
+		for _, s := range sd.ModifiedSequences {
+			m.review("changing %s may conflict with values already handed out", s.Name)
+			m.statement("ALTER SEQUENCE %s%s", qualifiedName(s.Old.Schema, s.Old.Name), sequenceOptions(s.Old, true))
+		}
+	}
+
+	if len(sd.AddedTables) > 0 {
+		m.section("Tables not in the baseline")
+		for _, t := range sd.AddedTables {
+			m.review("drops %s.%s and all of its rows", t.Schema, t.Name)
+			m.statement("DROP TABLE %s", qualifiedName(t.Schema, t.Name))
+		}
+	}
+	if len(sd.DeletedTables) > 0 {
+		m.section("Tables missing from the database")
+		for _, t := range sd.DeletedTables {
+			var create strings.Builder
+			writeCreateTable(&create, t)
+			m.sb.WriteString(create.String())
+			if t.Owner != "" {
+				m.statement("ALTER TABLE %s OWNER TO %s", qualifiedName(t.Schema, t.Name), quoteIdent(t.Owner))
+			}
+			for _, i := range t.Indexes {
+				if !i.IsPrimary && !isConstraintIndex(t.Constraints, i) {
+					m.createIndex(t, i)
+				}
+			}
+		}
+	}
+	for _, td := range sd.ModifiedTables {
+		m.section(fmt.Sprintf("Table %s.%s", td.Schema, td.Name))
+		m.alterTable(td)
+	}
+
+	if len(sd.AddedSequences) > 0 {
+		m.section("Sequences not in the baseline")
+		for _, s := range sd.AddedSequences {
+			m.statement("DROP SEQUENCE IF EXISTS %s", qualifiedName(s.Schema, s.Name))
+		}
+	}
+
+	if len(sd.DeletedFunctions) > 0 || len(sd.ModifiedFunctions) > 0 || len(sd.DeletedProcedures) > 0 || len(sd.ModifiedProcedures) > 0 {
+		m.section("Routines")
+		for _, f := range sd.DeletedFunctions {
+			m.routine("function", f.Signature(), f.Definition)
+		}
+		for _, f := range sd.ModifiedFunctions {
+			if f.Old.ReturnType != f.New.ReturnType {
+				// CREATE OR REPLACE cannot change the return type
+				m.statement("DROP FUNCTION %s(%s)", qualifiedName(f.New.Schema, f.New.Name), identityArguments(f.New.Arguments))
+			}
+			m.routine("function", f.Signature, f.Old.Definition)
+		}
+		for _, p := range sd.DeletedProcedures {
+			m.routine("procedure", p.Signature(), p.Definition)
+		}
+		for _, p := range sd.ModifiedProcedures {
+			m.routine("procedure", p.Signature, p.Old.Definition)
+		}
+	}
+
+	if len(sd.DeletedViews) > 0 {
+		m.section("Views missing from the database")
+		for _, v := range sd.DeletedViews {
+			if v.Definition == "" {
+				m.todo("recreate view %s.%s; its definition is not in the cached schema", v.Schema, v.Name)
+				continue
+			}
+			m.statement("CREATE VIEW %s AS\n%s", qualifiedName(v.Schema, v.Name), strings.TrimSpace(v.Definition))
+			if v.Owner != "" {
+				m.statement("ALTER VIEW %s OWNER TO %s", qualifiedName(v.Schema, v.Name), quoteIdent(v.Owner))
+			}
+		}
+	}
+
+	if len(sd.AddedExtensions) > 0 {
+		m.section("Extensions not in the baseline")
+		for _, e := range sd.AddedExtensions {
+			m.review("drops extension %s; objects using it must go first", e.Name)
+			m.statement("DROP EXTENSION IF EXISTS %s", quoteIdent(e.Name))
+		}
+	}
+
+	if len(sd.AddedSettings) > 0 || len(sd.ChangedSettings) > 0 || len(sd.DeletedSettings) > 0 {
+		m.section("Setting overrides")
+		for _, s := range sd.AddedSettings {
+			m.statement("%s RESET %s", settingTarget(s, database), s.Name)
+		}
+		for _, c := range sd.ChangedSettings {
+			m.setting(c.Old, database)
+		}
+		for _, s := range sd.DeletedSettings {
+			m.setting(s, database)
+		}
+	}
+
+	if len(sd.ChangedOwners) > 0 {
+		m.section("Owners")
+		for _, o := range sd.ChangedOwners {
+			m.statement("ALTER %s %s OWNER TO %s", strings.ToUpper(o.ObjectType), ownerTarget(o), quoteIdent(o.Old))
+		}
+	}
+
+	if len(sd.AddedRoles) > 0 || len(sd.DeletedRoles) > 0 {
+		m.section("Roles (cluster-wide, shared by every database on the instance)")
+		for _, r := range sd.AddedRoles {
+			m.review("drops role %s; reassign or drop the objects it owns first", r)
+			m.statement("DROP ROLE IF EXISTS %s", quoteIdent(r))
+		}
+		for _, r := range sd.DeletedRoles {
+			m.todo("grant %s its privileges and memberships", r)
+			m.statement("CREATE ROLE %s", quoteIdent(r))
+		}
+	}
+
+	m.sb.WriteString("\nCOMMIT;\n")
+	return m.sb.String()
+}
+
+// alterTable writes the statements that revert the changes to a table:
+// constraints and indexes are dropped before its columns change and
+// recreated after
+func (m *migration) alterTable(td TableDiff) {
+	table := qualifiedName(td.Schema, td.Name)
+	// Indexes of constraints come and go with them
+	constraints := slices.Concat(td.AddedConstraints, td.DeletedConstraints)
+	for _, c := range td.ModifiedConstraints {
+		constraints = append(constraints, c.New)
+	}
+
+	for _, c := range td.AddedConstraints {
+		m.statement("ALTER TABLE %s DROP CONSTRAINT %s", table, quoteIdent(c.Name))
+	}
+	for _, c := range td.ModifiedConstraints {
+		m.statement("ALTER TABLE %s DROP CONSTRAINT %s", table, quoteIdent(c.Name))
+	}
+	for _, i := range td.AddedIndexes {
+		if !i.IsPrimary && !isConstraintIndex(constraints, i) {
+			m.statement("DROP INDEX IF EXISTS %s", qualifiedName(td.Schema, i.Name))
+		}
+	}
+	for _, i := range td.ModifiedIndexes {
+		if !i.New.IsPrimary && !isConstraintIndex(constraints, i.New) {
+			m.statement("DROP INDEX IF EXISTS %s", qualifiedName(td.Schema, i.Name))
+		}
+	}
+
+	for _, c := range td.DeletedColumns {
+		if !c.IsNullable && c.DefaultValue == nil {
+			m.review("adding NOT NULL column %s without a default fails on a table with rows", c.Name)
+		}
+		m.statement("ALTER TABLE %s ADD COLUMN %s", table, columnDefinition(c))
+	}
+	for _, c := range td.ModifiedColumns {
+		m.alterColumn(table, c)
+	}
+	for _, c := range td.AddedColumns {
+		m.review("drops column %s and its data", c.Name)
+		m.statement("ALTER TABLE %s DROP COLUMN %s", table, quoteIdent(c.Name))
+	}
+
+	for _, c := range td.DeletedConstraints {
+		m.statement("ALTER TABLE %s ADD CONSTRAINT %s %s", table, quoteIdent(c.Name), c.Definition)
+	}
+	for _, c := range td.ModifiedConstraints {
+		m.review("existing rows must satisfy %s", c.Old.Definition)
+		m.statement("ALTER TABLE %s ADD CONSTRAINT %s %s", table, quoteIdent(c.Name), c.Old.Definition)
+	}
+	t := TableInfo{Schema: td.Schema, Name: td.Name}
+	for _, i := range td.DeletedIndexes {
+		if !i.IsPrimary && !isConstraintIndex(constraints, i) {
+			m.createIndex(t, i)
+		}
+	}
+	for _, i := range td.ModifiedIndexes {
+		if !i.Old.IsPrimary && !isConstraintIndex(constraints, i.Old) {
+			m.createIndex(t, i.Old)
+		}
+	}
+
+	if td.OldStorageParams != nil || td.NewStorageParams != nil {
+		var reset []string
+		for _, name := range slices.Sorted(maps.Keys(td.NewStorageParams)) {
+			if _, ok := td.OldStorageParams[name]; !ok {
+				reset = append(reset, name)
+			}
+		}
+		if len(reset) > 0 {
+			m.statement("ALTER TABLE %s RESET (%s)", table, strings.Join(reset, ", "))
+		}
+		if len(td.OldStorageParams) > 0 {
+			m.statement("ALTER TABLE %s SET (%s)", table, formatStorageParams(td.OldStorageParams))
+		}
+	}
+}
+
+// alterColumn writes the statements that revert a column to its old definition
+func (m *migration) alterColumn(table string, c ColumnChange) {
+	column := quoteIdent(c.Name)
+	if c.Old.DataType != c.New.DataType {
+		m.review("converting %s from %s to %s may fail or lose precision, and rewrites the table", c.Name, c.New.DataType, c.Old.DataType)
+		m.statement("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s", table, column, c.Old.DataType, column, c.Old.DataType)
+	}
+	if c.New.IsIdentity && !c.Old.IsIdentity {
+		m.statement("ALTER TABLE %s ALTER COLUMN %s DROP IDENTITY IF EXISTS", table, column)
+	}
+	if columnDefault(c.Old) != columnDefault(c.New) {
+		if c.Old.DefaultValue == nil {
+			m.statement("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", table, column)
+		} else {
+			m.statement("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT %s", table, column, *c.Old.DefaultValue)
+		}
+	}
+	if c.Old.IsIdentity && !c.New.IsIdentity {
+		m.statement("ALTER TABLE %s ALTER COLUMN %s ADD GENERATED ALWAYS AS IDENTITY", table, column)
+	}
+	if c.Old.IsNullable != c.New.IsNullable {
+		if c.Old.IsNullable {
+			m.statement("ALTER TABLE %s ALTER COLUMN %s DROP NOT NULL", table, column)
+		} else {
+			m.review("fails while %s has NULL values", c.Name)
+			m.statement("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", table, column)
+		}
+	}
+}
+
+// createIndex writes the definition of an index, which pg_get_indexdef
+// returns as a complete CREATE INDEX statement
+func (m *migration) createIndex(t TableInfo, i IndexInfo) {
+	if i.Definition == "" {
+		m.todo("recreate index %s on %s.%s (%s); its definition is not in the cached schema",
+			i.Name, t.Schema, t.Name, strings.Join(i.Columns, ", "))
+		return
+	}
+	m.statement("%s", i.Definition)
+}
+
+// routine writes the definition of a function or procedure, which
+// pg_get_functiondef returns as a CREATE OR REPLACE statement
+func (m *migration) routine(kind, signature, definition string) {
+	if definition == "" {
+		m.todo("recreate %s %s; its definition is not in the cached schema", kind, signature)
+		return
+	}
+	m.statement("%s", definition)
+}
+
+func (m *migration) setting(s DatabaseSetting, database string) {
+	m.statement("%s SET %s = %s", settingTarget(s, database), s.Name, quoteLiteral(s.Value))
+}
+
+// settingTarget is the ALTER DATABASE or ALTER ROLE ... IN DATABASE clause
+// of a setting override
+func settingTarget(s DatabaseSetting, database string) string {
+	if s.Role == "" {
+		return "ALTER DATABASE " + quoteIdent(database)
+	}
+	return fmt.Sprintf("ALTER ROLE %s IN DATABASE %s", quoteIdent(s.Role), quoteIdent(database))
+}
+
+// sequenceOptions returns the options of CREATE or ALTER SEQUENCE that give
+// a sequence its settings; ALTER resets bounds the sequence has none of
+func sequenceOptions(s SequenceInfo, alter bool) string {
+	if s.DataType == "" {
+		return ""
+	}
+	opts := []string{"AS " + s.DataType, fmt.Sprintf("INCREMENT BY %d", s.Increment)}
+	switch {
+	case s.MinValue != nil:
+		opts = append(opts, fmt.Sprintf("MINVALUE %d", *s.MinValue))
+	case alter:
+		opts = append(opts, "NO MINVALUE")
+	}
+	switch {
+	case s.MaxValue != nil:
+		opts = append(opts, fmt.Sprintf("MAXVALUE %d", *s.MaxValue))
+	case alter:
+		opts = append(opts, "NO MAXVALUE")
+	}
+	opts = append(opts, fmt.Sprintf("START WITH %d", s.StartValue))
+	return " " + strings.Join(opts, " ")
+}
+
+// isConstraintIndex reports whether an index backs a primary key, unique or
+// exclusion constraint, which creates and drops it
+func isConstraintIndex(constraints []ConstraintInfo, i IndexInfo) bool {
+	for _, c := range constraints {
+		if c.Name == i.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteIdent double-quotes an identifier, doubling any embedded quote, so
+// mixed-case names and reserved words survive
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral single-quotes a string constant, doubling any embedded quote
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// qualifiedName quotes a schema-qualified name
+func qualifiedName(schema, name string) string {
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+// ownerTarget quotes the object of an owner change; functions and procedures
+// are named by their identity signature
+func ownerTarget(o OwnerChange) string {
+	if o.Schema == "" {
+		return quoteIdent(o.Name)
+	}
+	name := strings.TrimPrefix(o.Name, o.Schema+".")
+	if o.ObjectType != "function" && o.ObjectType != "procedure" {
+		return qualifiedName(o.Schema, name)
+	}
+	routine, args, _ := strings.Cut(identitySignature(name), "(")
+	return qualifiedName(o.Schema, routine) + "(" + args
+}
+
+// identitySignature strips the DEFAULT expressions from the arguments of a
+// function signature such as public.total(order_id integer DEFAULT 0)
+func identitySignature(signature string) string {
+	open := strings.Index(signature, "(")
+	if open < 0 || !strings.HasSuffix(signature, ")") {
+		return signature
+	}
+	return signature[:open] + "(" + identityArguments(signature[open+1:len(signature)-1]) + ")"
+}
+
+// identityArguments strips the DEFAULT expressions, which DROP FUNCTION does
+// not accept, from function arguments as returned by pg_get_function_arguments
+func identityArguments(args string) string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range args {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, args[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, args[start:])
+
+	identity := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if i := strings.Index(strings.ToUpper(part), " DEFAULT "); i >= 0 {
+			part = part[:i]
+		}
+		if part != "" {
+			identity = append(identity, part)
+		}
+	}
+	return strings.Join(identity, ", ")
+}
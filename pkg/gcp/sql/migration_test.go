@@ -0,0 +1,185 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaDiff_MigrationSQL(t *testing.T) {
+	baseline := &DatabaseSchema{
+		DatabaseName: "shop",
+		Tables: []TableInfo{
+			{
+				Schema: "public",
+				Name:   "orders",
+				Owner:  "app",
+				Columns: []ColumnInfo{
+					{Name: "id", DataType: "integer"},
+					{Name: "status", DataType: "text", IsNullable: true},
+					{Name: "note", DataType: "text", IsNullable: true},
+				},
+				Indexes: []IndexInfo{
+					{Name: "orders_pkey", IsPrimary: true, IsUnique: true, Columns: []string{"id"}, Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)"},
+					{Name: "orders_status_idx", Columns: []string{"status"}, Definition: "CREATE INDEX orders_status_idx ON public.orders USING btree (status)"},
+				},
+				Constraints: []ConstraintInfo{
+					{Name: "orders_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"},
+				},
+				StorageParams: map[string]string{"fillfactor": "90"},
+			},
+			{Schema: "public", Name: "audit", Owner: "app", Columns: []ColumnInfo{{Name: "id", DataType: "bigint"}}},
+		},
+		Functions: []FunctionInfo{
+			{Schema: "public", Name: "total", Arguments: "order_id integer", ReturnType: "numeric", Language: "sql",
+				Definition: "CREATE OR REPLACE FUNCTION public.total(order_id integer)\n RETURNS numeric\n LANGUAGE sql\nAS $function$SELECT 1$function$\n"},
+		},
+		Settings: []DatabaseSetting{{Name: "statement_timeout", Value: "30s"}},
+	}
+	current := &DatabaseSchema{
+		DatabaseName: "shop",
+		Tables: []TableInfo{
+			{
+				Schema: "public",
+				Name:   "orders",
+				Owner:  "app",
+				Columns: []ColumnInfo{
+					{Name: "id", DataType: "bigint"},
+					{Name: "status", DataType: "text", DefaultValue: strPtr("'new'::text")},
+					{Name: "created_at", DataType: "timestamp with time zone", IsNullable: true},
+				},
+				Indexes: []IndexInfo{
+					{Name: "orders_pkey", IsPrimary: true, IsUnique: true, Columns: []string{"id"}, Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)"},
+					{Name: "orders_created_at_idx", Columns: []string{"created_at"}, Definition: "CREATE INDEX orders_created_at_idx ON public.orders USING btree (created_at)"},
+				},
+				Constraints: []ConstraintInfo{
+					{Name: "orders_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"},
+				},
+				StorageParams: map[string]string{"fillfactor": "70", "autovacuum_enabled": "false"},
+			},
+			{Schema: "public", Name: "scratch", Columns: []ColumnInfo{{Name: "id", DataType: "integer"}}},
+		},
+		Functions: []FunctionInfo{
+			{Schema: "public", Name: "total", Arguments: "order_id integer", ReturnType: "numeric", Language: "sql",
+				Definition: "CREATE OR REPLACE FUNCTION public.total(order_id integer)\n RETURNS numeric\n LANGUAGE sql\nAS $function$SELECT 2$function$\n"},
+			{Schema: "public", Name: "debug", Arguments: "verbose boolean DEFAULT false", ReturnType: "void", Language: "plpgsql"},
+		},
+		Settings: []DatabaseSetting{{Name: "statement_timeout", Value: "0"}, {Name: "work_mem", Value: "1GB"}},
+	}
+
+	script := CompareSchemas(baseline, current).MigrationSQL()
+
+	for _, want := range []string{
+		"REVIEW REQUIRED",
+		"BEGIN;\n",
+		`DROP FUNCTION IF EXISTS "public"."debug"(verbose boolean);` + "\n",
+		"-- REVIEW: drops public.scratch and all of its rows\n" + `DROP TABLE "public"."scratch";` + "\n",
+		"CREATE TABLE \"public\".\"audit\" (\n    \"id\" bigint NOT NULL\n);\nALTER TABLE \"public\".\"audit\" OWNER TO \"app\";\n",
+		`DROP INDEX IF EXISTS "public"."orders_created_at_idx";` + "\n",
+		`ALTER TABLE "public"."orders" ADD COLUMN "note" text;` + "\n",
+		`ALTER TABLE "public"."orders" ALTER COLUMN "id" TYPE integer USING "id"::integer;` + "\n",
+		`ALTER TABLE "public"."orders" ALTER COLUMN "status" DROP DEFAULT;` + "\n",
+		`ALTER TABLE "public"."orders" ALTER COLUMN "status" DROP NOT NULL;` + "\n",
+		"-- REVIEW: drops column created_at and its data\n" + `ALTER TABLE "public"."orders" DROP COLUMN "created_at";` + "\n",
+		"CREATE INDEX orders_status_idx ON public.orders USING btree (status);\n",
+		"ALTER TABLE \"public\".\"orders\" RESET (autovacuum_enabled);\nALTER TABLE \"public\".\"orders\" SET (fillfactor=90);\n",
+		"AS $function$SELECT 1$function$;\n",
+		`ALTER DATABASE "shop" RESET work_mem;` + "\n",
+		`ALTER DATABASE "shop" SET statement_timeout = '30s';` + "\n",
+		"COMMIT;\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("migration is missing %q:\n%s", want, script)
+		}
+	}
+	// The primary key index comes with its constraint
+	if strings.Contains(script, "orders_pkey") {
+		t.Errorf("migration should leave orders_pkey alone:\n%s", script)
+	}
+	if strings.Index(script, `DROP COLUMN "created_at"`) > strings.Index(script, "CREATE INDEX orders_status_idx") {
+		t.Errorf("indexes should be recreated after the columns change:\n%s", script)
+	}
+}
+
+func TestSchemaDiff_MigrationSQL_MissingDefinitions(t *testing.T) {
+	diff := &SchemaDiff{
+		Database:         "shop",
+		DeletedFunctions: []FunctionInfo{{Schema: "public", Name: "total", Arguments: "order_id integer"}},
+		DeletedViews:     []ViewInfo{{Schema: "public", Name: "open_orders"}},
+	}
+
+	script := diff.MigrationSQL()
+	for _, want := range []string{
+		"-- TODO: recreate function public.total(order_id integer); its definition is not in the cached schema\n",
+		"-- TODO: recreate view public.open_orders; its definition is not in the cached schema\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("migration is missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestIdentityArguments(t *testing.T) {
+	tests := []struct {
+		args string
+		want string
+	}{
+		{"", ""},
+		{"order_id integer", "order_id integer"},
+		{"amount numeric(10,2) DEFAULT 0, currency text DEFAULT 'EUR'::text", "amount numeric(10,2), currency text"},
+		{"VARIADIC ids integer[]", "VARIADIC ids integer[]"},
+	}
+	for _, tt := range tests {
+		if got := identityArguments(tt.args); got != tt.want {
+			t.Errorf("identityArguments(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaDiff_MigrationSQL_Owners(t *testing.T) {
+	diff := &SchemaDiff{ChangedOwners: []OwnerChange{
+		{ObjectType: "database", Name: "shop", Old: "app", New: "dba"},
+		{ObjectType: "table", Schema: "public", Name: "public.orders", Old: "app", New: "dba"},
+		{ObjectType: "function", Schema: "public", Name: "public.total(id integer DEFAULT 0)", Old: "app", New: "dba"},
+	}}
+
+	script := diff.MigrationSQL()
+	for _, want := range []string{
+		`ALTER DATABASE "shop" OWNER TO "app";` + "\n",
+		`ALTER TABLE "public"."orders" OWNER TO "app";` + "\n",
+		`ALTER FUNCTION "public"."total"(id integer) OWNER TO "app";` + "\n",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("migration is missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestSchemaDiff_MigrationSQL_QuotesIdentifiers(t *testing.T) {
+	table := func(columns ...ColumnInfo) TableInfo {
+		return TableInfo{
+			Schema:      "Sales",
+			Name:        "OrderItems",
+			Columns:     columns,
+			Constraints: []ConstraintInfo{{Name: "OrderItems_pkey", Type: "PRIMARY KEY", Definition: `PRIMARY KEY ("order")`}},
+		}
+	}
+	baseline := &DatabaseSchema{DatabaseName: "shop", Tables: []TableInfo{
+		table(ColumnInfo{Name: "order", DataType: "integer"}, ColumnInfo{Name: `Note "A"`, DataType: "text", IsNullable: true}),
+		{Schema: "Sales", Name: "user", Columns: []ColumnInfo{{Name: "select", DataType: "text"}}},
+	}, Extensions: []Extension{{Name: "uuid-ossp", Schema: "public", Version: "1.1'beta"}}}
+	current := &DatabaseSchema{DatabaseName: "shop", Tables: []TableInfo{
+		table(ColumnInfo{Name: "order", DataType: "bigint"}),
+	}}
+
+	script := CompareSchemas(baseline, current).MigrationSQL()
+	for _, want := range []string{
+		`ALTER TABLE "Sales"."OrderItems" ALTER COLUMN "order" TYPE integer USING "order"::integer;`,
+		`ALTER TABLE "Sales"."OrderItems" ADD COLUMN "Note ""A""" text;`,
+		"CREATE TABLE \"Sales\".\"user\" (\n    \"select\" text NOT NULL\n);",
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" WITH SCHEMA "public" VERSION '1.1''beta';`,
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("migration is missing %q:\n%s", want, script)
+		}
+	}
+}
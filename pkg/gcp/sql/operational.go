@@ -0,0 +1,39 @@
+package sql
+
+import (
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+// DefaultOperationalStates are the severities of unhealthy instance states
+// unless overridden in the operational section of the config
+var DefaultOperationalStates = analyzer.OperationalSeverities{
+	"FAILED":         "critical",
+	"SUSPENDED":      "high",
+	"PENDING_DELETE": "medium",
+}
+
+// SetOperationalSeverities overrides the severities of unhealthy instance
+// states; states left out keep their defaults
+func (a *Analyzer) SetOperationalSeverities(states analyzer.OperationalSeverities) {
+	a.states = states
+}
+
+// checkOperationalState reports an instance in an unhealthy state as an
+// operational finding, with the reasons of a suspension. It is added after
+// the baseline's severity overrides, which apply to configuration drift.
+func (a *Analyzer) checkOperationalState(inst *DatabaseInstance, drift *InstanceDrift) {
+	severity, ok := a.states.Severity(inst.State, DefaultOperationalStates)
+	if !ok {
+		return
+	}
+	actual := inst.State
+	if len(inst.SuspensionReasons) > 0 {
+		actual += " (" + strings.Join(inst.SuspensionReasons, ", ") + ")"
+	}
+	drift.Drifts = checkState.Append(drift.Drifts, "RUNNABLE", actual)
+	finding := &drift.Drifts[len(drift.Drifts)-1]
+	finding.Severity = severity
+	finding.Operational = true
+}
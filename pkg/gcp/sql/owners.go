@@ -0,0 +1,66 @@
+package sql
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// DatabaseOwners maps database names to the service that owns them, so
+// database findings reach the application team. Names may use shell
+// wildcards; an exact name wins over a pattern.
+type DatabaseOwners map[string]string
+
+// Validate checks that every pattern is well formed and names a service
+func (o DatabaseOwners) Validate() error {
+	for pattern, service := range o {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("database_owners: invalid pattern %q: %w", pattern, err)
+		}
+		if service == "" {
+			return fmt.Errorf("database_owners: %q has no service", pattern)
+		}
+	}
+	return nil
+}
+
+// Owner returns the service owning a database, or "" when it is unmapped
+func (o DatabaseOwners) Owner(database string) string {
+	if service, ok := o[database]; ok {
+		return service
+	}
+
+	// Patterns are tried in sorted order so overlapping patterns resolve
+	// the same way on every run
+	patterns := make([]string, 0, len(o))
+	for pattern := range o {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, database); matched {
+			return o[pattern]
+		}
+	}
+	return ""
+}
+
+// OwnersOf returns the sorted, distinct services owning any of the databases
+func (o DatabaseOwners) OwnersOf(databases []string) []string {
+	seen := make(map[string]bool)
+	var owners []string
+	for _, db := range databases {
+		if service := o.Owner(db); service != "" && !seen[service] {
+			seen[service] = true
+			owners = append(owners, service)
+		}
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// SetDatabaseOwners sets the database to service mapping used to attribute
+// required and extra database findings
+func (a *Analyzer) SetDatabaseOwners(owners DatabaseOwners) {
+	a.owners = owners
+}
@@ -0,0 +1,71 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDatabaseOwners(t *testing.T) {
+	owners := DatabaseOwners{
+		"orders":      "checkout",
+		"orders_*":    "checkout-archive",
+		"analytics_*": "data-platform",
+		"*_audit":     "compliance",
+	}
+	if err := owners.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	tests := []struct {
+		database string
+		want     string
+	}{
+		{"orders", "checkout"},
+		{"orders_2024", "checkout-archive"},
+		{"analytics_events", "data-platform"},
+		// Overlapping patterns resolve in sorted order
+		{"analytics_audit", "compliance"},
+		{"billing", ""},
+	}
+	for _, tt := range tests {
+		if got := owners.Owner(tt.database); got != tt.want {
+			t.Errorf("Owner(%q) = %q, want %q", tt.database, got, tt.want)
+		}
+	}
+
+	got := owners.OwnersOf([]string{"orders_2024", "billing", "analytics_events", "analytics_raw"})
+	if want := []string{"checkout-archive", "data-platform"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("OwnersOf() = %v, want %v", got, want)
+	}
+
+	for name, invalid := range map[string]DatabaseOwners{
+		"bad pattern": {"orders[": "checkout"},
+		"no service":  {"orders": ""},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("%s: Validate() = nil, want error", name)
+		}
+	}
+}
+
+func TestCheckRequiredDatabasesOwners(t *testing.T) {
+	a := &Analyzer{}
+	a.SetDatabaseOwners(DatabaseOwners{"orders": "checkout", "legacy_*": "billing"})
+
+	inst := &DatabaseInstance{Databases: []string{"inventory", "legacy_invoices", "scratch"}}
+	baseline := &DatabaseConfig{RequiredDatabases: []string{"orders", "inventory"}}
+
+	drift := &InstanceDrift{}
+	a.checkRequiredDatabases(inst, baseline, drift)
+
+	if len(drift.Drifts) != 2 {
+		t.Fatalf("got %d drifts, want 2: %+v", len(drift.Drifts), drift.Drifts)
+	}
+	if got := drift.Drifts[0].Owners; !reflect.DeepEqual(got, []string{"checkout"}) {
+		t.Errorf("missing databases owners = %v, want [checkout]", got)
+	}
+	// scratch has no owner, so only the mapped database is attributed
+	if got := drift.Drifts[1].Owners; !reflect.DeepEqual(got, []string{"billing"}) {
+		t.Errorf("extra databases owners = %v, want [billing]", got)
+	}
+}
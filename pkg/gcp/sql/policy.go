@@ -0,0 +1,54 @@
+package sql
+
+import "fmt"
+
+// Policy defines organization-wide requirements that apply to every analyzed
+// instance, independent of which baseline matched it
+type Policy struct {
+	RequireQueryInsights   bool  `yaml:"require_query_insights" json:"require_query_insights"`
+	RequireApplicationTags bool  `yaml:"require_record_application_tags" json:"require_record_application_tags"`
+	MinQueryStringLength   int64 `yaml:"min_query_string_length,omitempty" json:"min_query_string_length,omitempty"`
+}
+
+// SetPolicy sets the organization policy checked on every instance
+func (a *Analyzer) SetPolicy(policy *Policy) {
+	a.policy = policy
+}
+
+// applyPolicy checks an instance against the organization policy. Fields already
+// reported by the baseline comparison are not reported twice.
+func (a *Analyzer) applyPolicy(inst *DatabaseInstance, drift *InstanceDrift) {
+	if a.policy == nil {
+		return
+	}
+
+	reported := make(map[string]bool)
+	for _, d := range drift.Drifts {
+		reported[d.Field] = true
+	}
+	add := func(d Drift) {
+		if !reported[d.Field] {
+			drift.Drifts = append(drift.Drifts, d)
+			reported[d.Field] = true
+		}
+	}
+
+	insights := &InsightsConfig{}
+	if inst.Config.Settings != nil && inst.Config.Settings.InsightsConfig != nil {
+		insights = inst.Config.Settings.InsightsConfig
+	}
+
+	if a.policy.RequireQueryInsights && !insights.QueryInsightsEnabled {
+		add(checkPolicyQueryInsights.Drift("true (org policy)", "false"))
+	}
+
+	if a.policy.RequireApplicationTags && !insights.RecordApplicationTags {
+		add(checkPolicyApplicationTags.Drift("true (org policy)", "false"))
+	}
+
+	if a.policy.MinQueryStringLength > 0 && insights.QueryStringLength < a.policy.MinQueryStringLength {
+		add(checkPolicyQueryLength.Drift(
+			fmt.Sprintf(">= %d (org policy)", a.policy.MinQueryStringLength),
+			fmt.Sprintf("%d", insights.QueryStringLength)))
+	}
+}
@@ -0,0 +1,41 @@
+package sql
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	allocatedPortsMu sync.Mutex
+	allocatedPorts   = map[int]bool{}
+)
+
+// allocatePort returns a free local port that no other proxy or tunnel in
+// this process has been given. getFreePort alone can hand the same port to
+// two concurrent inspections before either of them binds it.
+func allocatePort() (int, error) {
+	allocatedPortsMu.Lock()
+	defer allocatedPortsMu.Unlock()
+
+	for attempt := 0; attempt < 20; attempt++ {
+		port, err := getFreePort()
+		if err != nil {
+			return 0, err
+		}
+		if !allocatedPorts[port] {
+			allocatedPorts[port] = true
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no unallocated local port available")
+}
+
+// outputOrStdout returns w, or stdout when no output was set
+func outputOrStdout(w io.Writer) io.Writer {
+	if w == nil {
+		return os.Stdout
+	}
+	return w
+}
@@ -0,0 +1,33 @@
+package sql
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestAllocatePortIsUnique(t *testing.T) {
+	const n = 20
+	ports := make([]int, n)
+	var wg sync.WaitGroup
+	for i := range ports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			port, err := allocatePort()
+			if err != nil {
+				t.Errorf("allocatePort() error = %v", err)
+				return
+			}
+			ports[i] = port
+		}()
+	}
+	wg.Wait()
+
+	seen := map[int]bool{}
+	for _, port := range ports {
+		if seen[port] {
+			t.Errorf("port %d allocated twice", port)
+		}
+		seen[port] = true
+	}
+}
@@ -0,0 +1,51 @@
+package sql
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// PromotedBaseline is a cached schema promoted to the canonical baseline of a
+// connection. It records who promoted it, when and from which commit, so
+// baseline updates can be audited.
+type PromotedBaseline struct {
+	ConnectionName string    `json:"connection_name" yaml:"connection_name"`
+	Database       string    `json:"database" yaml:"database"`
+	InspectedAt    time.Time `json:"inspected_at" yaml:"inspected_at"`
+	PromotedAt     time.Time `json:"promoted_at" yaml:"promoted_at"`
+	PromotedBy     string    `json:"promoted_by" yaml:"promoted_by"`
+	// GitSHA is the commit of the repository holding the config, if any
+	GitSHA string `json:"git_sha,omitempty" yaml:"git_sha,omitempty"`
+	// Message says why the baseline was updated
+	Message string          `json:"message,omitempty" yaml:"message,omitempty"`
+	Schema  *DatabaseSchema `json:"schema" yaml:"schema"`
+}
+
+// Promote turns a cached schema into a canonical baseline
+func (cs *CachedSchema) Promote(by, gitSHA, message string, at time.Time) *PromotedBaseline {
+	return &PromotedBaseline{
+		ConnectionName: cs.ConnectionName,
+		Database:       cs.Database,
+		InspectedAt:    cs.Timestamp,
+		PromotedAt:     at,
+		PromotedBy:     by,
+		GitSHA:         gitSHA,
+		Message:        message,
+		Schema:         cs.Schema,
+	}
+}
+
+// LoadPromotedBaseline reads a baseline written by sql db snapshot --promote
+func LoadPromotedBaseline(path string) (*PromotedBaseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline PromotedBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &baseline, nil
+}
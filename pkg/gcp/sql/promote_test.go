@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCachedSchema_Promote(t *testing.T) {
+	inspected := time.Date(2026, 10, 1, 6, 0, 0, 0, time.UTC)
+	promoted := inspected.Add(48 * time.Hour)
+	cached := &CachedSchema{
+		ConnectionName: "shop-prod:us-east1:orders-db",
+		Database:       "orders",
+		Timestamp:      inspected,
+		Schema:         &DatabaseSchema{DatabaseName: "orders", Tables: []TableInfo{{Schema: "public", Name: "orders"}}},
+	}
+
+	data, err := json.Marshal(cached.Promote("alice", "3f2c1ab", "add orders.created_at", promoted))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "orders-baseline.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline, err := LoadPromotedBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadPromotedBaseline() error = %v", err)
+	}
+	if baseline.PromotedBy != "alice" || baseline.GitSHA != "3f2c1ab" || baseline.Message != "add orders.created_at" {
+		t.Errorf("metadata = %q %q %q", baseline.PromotedBy, baseline.GitSHA, baseline.Message)
+	}
+	if !baseline.InspectedAt.Equal(inspected) || !baseline.PromotedAt.Equal(promoted) {
+		t.Errorf("inspected at %v, promoted at %v", baseline.InspectedAt, baseline.PromotedAt)
+	}
+	if baseline.ConnectionName != cached.ConnectionName || len(baseline.Schema.Tables) != 1 {
+		t.Errorf("baseline = %+v", baseline)
+	}
+
+	if _, err := LoadPromotedBaseline(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadPromotedBaseline() should fail for a missing file")
+	}
+}
@@ -3,6 +3,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os/exec"
 	"time"
@@ -15,12 +16,13 @@ type ProxyManager struct {
 	localPort        int
 	usePrivateIP     bool
 	useGcloud        bool // if true, use gcloud instead of cloud-sql-proxy
+	out              io.Writer // progress output (default: stdout)
 }
 
 // ProxyConfig configures the proxy manager
 type ProxyConfig struct {
 	InstanceConnectionName string
-	LocalPort              int  // Local port to bind (default: 5432)
+	LocalPort              int  // Local port to bind (default: a free port)
 	UsePrivateIP           bool
 	UseGcloud              bool // Use gcloud command instead of cloud-sql-proxy binary
 }
@@ -28,7 +30,11 @@ type ProxyConfig struct {
 // NewProxyManager creates a new proxy manager
 func NewProxyManager(config ProxyConfig) *ProxyManager {
 	if config.LocalPort == 0 {
+		// Each proxy gets its own port so several can run side by side
 		config.LocalPort = 5432
+		if port, err := allocatePort(); err == nil {
+			config.LocalPort = port
+		}
 	}
 	
 	return &ProxyManager{
@@ -95,7 +101,7 @@ func (pm *ProxyManager) startGcloudProxy(ctx context.Context) error {
 	}
 	
 	// Wait longer for the proxy to initialize and be ready
-	fmt.Println("Waiting for proxy to be ready...")
+	fmt.Fprintln(outputOrStdout(pm.out), "Waiting for proxy to be ready...")
 	time.Sleep(8 * time.Second)
 	
 	return nil
@@ -131,14 +137,14 @@ func (pm *ProxyManager) startCloudSQLProxy(ctx context.Context) error {
 		pm.cmd = exec.CommandContext(ctx, binary, args...)
 		if err := pm.cmd.Start(); err == nil {
 			// Wait for the proxy to be ready by checking port
-			fmt.Printf("Started %s (PID: %d), waiting for it to be ready...\n", binary, pm.cmd.Process.Pid)
+			fmt.Fprintf(outputOrStdout(pm.out), "Started %s (PID: %d), waiting for it to be ready...\n", binary, pm.cmd.Process.Pid)
 			
 			if err := pm.waitForProxy(30 * time.Second); err != nil {
 				pm.cmd.Process.Kill()
 				return fmt.Errorf("proxy failed to become ready: %w", err)
 			}
 			
-			fmt.Println("Proxy process is running and ready")
+			fmt.Fprintln(outputOrStdout(pm.out), "Proxy process is running and ready")
 			return nil
 		} else {
 			lastErr = err
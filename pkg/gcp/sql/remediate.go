@@ -0,0 +1,297 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"google.golang.org/api/sqladmin/v1"
+)
+
+// operationPollInterval is how often a running patch operation is polled
+const operationPollInterval = 5 * time.Second
+
+// ErrSettingsChanged is returned by Apply when the instance no longer has the
+// values the plan was made from
+var ErrSettingsChanged = errors.New("settings changed since the plan was made")
+
+// remediableChecks are the low-risk checks the remediator may fix; none of
+// them restart the instance
+var remediableChecks = map[string]bool{
+	checkBackupEnabled.ID:         true,
+	checkBackupStartTime.ID:       true,
+	checkBackupRetention.ID:       true,
+	checkLabel.ID:                 true,
+	checkMaintenanceWindow.ID:     true,
+	checkMaintenanceDay.ID:        true,
+	checkMaintenanceHour.ID:       true,
+	checkMaintenanceTrack.ID:      true,
+	checkQueryInsights.ID:         true,
+	checkQueryPlansPerMinute.ID:   true,
+	checkQueryStringLength.ID:     true,
+	checkPolicyQueryInsights.ID:   true,
+	checkPolicyApplicationTags.ID: true,
+	checkPolicyQueryLength.ID:     true,
+}
+
+// Remediator applies whitelisted fixes to Cloud SQL instances through the
+// SQL Admin API
+type Remediator struct {
+	service *sqladmin.Service
+}
+
+// NewRemediator creates a remediator with a SQL Admin API client
+func NewRemediator(ctx context.Context) (*Remediator, error) {
+	service, err := sqladmin.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL Admin client: %w", err)
+	}
+	return &Remediator{service: service}, nil
+}
+
+// Supports reports whether a check is whitelisted for auto-remediation
+func (r *Remediator) Supports(checkID string) bool {
+	return remediableChecks[checkID]
+}
+
+// Apply patches one instance with every step and waits for the operation to
+// finish. It fails with ErrSettingsChanged instead of overwriting a change
+// made since the plan was read, and the patch carries the settings version
+// it was checked against, so it also fails on a concurrent change.
+func (r *Remediator) Apply(ctx context.Context, steps []checks.Step) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	project, name := steps[0].Project, steps[0].Resource
+
+	inst, err := r.service.Instances.Get(project, name).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get instance %s/%s: %w", project, name, err)
+	}
+
+	if err := checkUnchanged(inst, steps); err != nil {
+		return fmt.Errorf("instance %s/%s: %w", project, name, err)
+	}
+	patch, err := buildPatch(inst, steps)
+	if err != nil {
+		return err
+	}
+
+	op, err := r.service.Instances.Patch(project, name, patch).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to patch instance %s/%s: %w", project, name, err)
+	}
+	return r.waitForOperation(ctx, project, op)
+}
+
+// waitForOperation polls a SQL Admin operation until it is done
+func (r *Remediator) waitForOperation(ctx context.Context, project string, op *sqladmin.Operation) error {
+	for op.Status != "DONE" {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(operationPollInterval):
+		}
+
+		var err error
+		op, err = r.service.Operations.Get(project, op.Name).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("failed to get operation %s: %w", op.Name, err)
+		}
+	}
+
+	if op.Error != nil && len(op.Error.Errors) > 0 {
+		var messages []string
+		for _, e := range op.Error.Errors {
+			messages = append(messages, fmt.Sprintf("%s: %s", e.Code, e.Message))
+		}
+		return fmt.Errorf("operation %s failed: %s", op.Name, strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// checkUnchanged compares the instance's current value of every step's field
+// with the value the plan was made from
+func checkUnchanged(inst *sqladmin.DatabaseInstance, steps []checks.Step) error {
+	for _, step := range steps {
+		if current := currentValue(inst.Settings, step); current != step.Actual {
+			return fmt.Errorf("%w: %s is %q, the plan expected %q", ErrSettingsChanged, step.Field, current, step.Actual)
+		}
+	}
+	return nil
+}
+
+// currentValue renders the current value of a step's field the way the
+// analyzer reports it as the drift's actual value
+func currentValue(s *sqladmin.Settings, step checks.Step) string {
+	if s == nil {
+		s = &sqladmin.Settings{}
+	}
+	window := s.MaintenanceWindow
+	if window == nil {
+		window = &sqladmin.MaintenanceWindow{}
+	}
+	insights := s.InsightsConfig
+	if insights == nil {
+		insights = &sqladmin.InsightsConfig{}
+	}
+	backup := s.BackupConfiguration
+	if backup == nil {
+		backup = &sqladmin.BackupConfiguration{}
+	}
+
+	switch step.CheckID {
+	case checkLabel.ID:
+		if value, ok := s.UserLabels[strings.TrimPrefix(step.Field, "labels.")]; ok {
+			return value
+		}
+		return "not set"
+	case checkMaintenanceWindow.ID:
+		if s.MaintenanceWindow == nil {
+			return "not configured"
+		}
+		return fmt.Sprintf("day %d hour %d", window.Day, window.Hour)
+	case checkMaintenanceDay.ID:
+		return strconv.FormatInt(window.Day, 10)
+	case checkMaintenanceHour.ID:
+		return strconv.FormatInt(window.Hour, 10)
+	case checkMaintenanceTrack.ID:
+		return window.UpdateTrack
+	case checkBackupEnabled.ID:
+		return strconv.FormatBool(backup.Enabled)
+	case checkBackupStartTime.ID:
+		return backup.StartTime
+	case checkBackupRetention.ID:
+		if backup.BackupRetentionSettings == nil {
+			return "0"
+		}
+		return strconv.FormatInt(backup.BackupRetentionSettings.RetainedBackups, 10)
+	case checkQueryInsights.ID, checkPolicyQueryInsights.ID:
+		return strconv.FormatBool(insights.QueryInsightsEnabled)
+	case checkPolicyApplicationTags.ID:
+		return strconv.FormatBool(insights.RecordApplicationTags)
+	case checkQueryPlansPerMinute.ID:
+		return strconv.FormatInt(insights.QueryPlansPerMinute, 10)
+	case checkQueryStringLength.ID, checkPolicyQueryLength.ID:
+		return strconv.FormatInt(insights.QueryStringLength, 10)
+	}
+	return step.Actual
+}
+
+// buildPatch creates a patch request that sets every step to its expected
+// value, starting from the instance's current labels, maintenance window,
+// insights and backup config so unrelated values are preserved. Backups are
+// only ever enabled, never disabled.
+func buildPatch(inst *sqladmin.DatabaseInstance, steps []checks.Step) (*sqladmin.DatabaseInstance, error) {
+	current := inst.Settings
+	if current == nil {
+		current = &sqladmin.Settings{}
+	}
+	settings := &sqladmin.Settings{SettingsVersion: current.SettingsVersion}
+
+	labels := func() map[string]string {
+		if settings.UserLabels == nil {
+			settings.UserLabels = make(map[string]string, len(current.UserLabels)+1)
+			for k, v := range current.UserLabels {
+				settings.UserLabels[k] = v
+			}
+		}
+		return settings.UserLabels
+	}
+	window := func() *sqladmin.MaintenanceWindow {
+		if settings.MaintenanceWindow == nil {
+			settings.MaintenanceWindow = &sqladmin.MaintenanceWindow{}
+			if current.MaintenanceWindow != nil {
+				*settings.MaintenanceWindow = *current.MaintenanceWindow
+			}
+			// Hour 0 (midnight) is a valid value and must not be omitted
+			settings.MaintenanceWindow.ForceSendFields = []string{"Hour"}
+		}
+		return settings.MaintenanceWindow
+	}
+	insights := func() *sqladmin.InsightsConfig {
+		if settings.InsightsConfig == nil {
+			settings.InsightsConfig = &sqladmin.InsightsConfig{}
+			if current.InsightsConfig != nil {
+				*settings.InsightsConfig = *current.InsightsConfig
+			}
+			settings.InsightsConfig.ForceSendFields = []string{"QueryInsightsEnabled", "RecordApplicationTags"}
+		}
+		return settings.InsightsConfig
+	}
+
+	backup := func() *sqladmin.BackupConfiguration {
+		if settings.BackupConfiguration == nil {
+			settings.BackupConfiguration = &sqladmin.BackupConfiguration{}
+			if current.BackupConfiguration != nil {
+				*settings.BackupConfiguration = *current.BackupConfiguration
+			}
+			settings.BackupConfiguration.ForceSendFields = []string{"Enabled"}
+		}
+		return settings.BackupConfiguration
+	}
+
+	for _, step := range steps {
+		expected := expectedValue(step.Expected)
+		var err error
+		switch step.CheckID {
+		case checkLabel.ID:
+			key := strings.TrimPrefix(step.Field, "labels.")
+			labels()[key] = expected
+		case checkMaintenanceWindow.ID:
+			var day, hour int64
+			if _, err = fmt.Sscanf(expected, "day %d hour %d", &day, &hour); err == nil {
+				window().Day, window().Hour = day, hour
+			}
+		case checkMaintenanceDay.ID:
+			window().Day, err = strconv.ParseInt(expected, 10, 64)
+		case checkMaintenanceHour.ID:
+			window().Hour, err = strconv.ParseInt(expected, 10, 64)
+		case checkMaintenanceTrack.ID:
+			window().UpdateTrack = expected
+		case checkBackupEnabled.ID:
+			if expected != "true" {
+				return nil, fmt.Errorf("%s: disabling backups is never applied automatically", step.Field)
+			}
+			backup().Enabled = true
+		case checkBackupStartTime.ID:
+			if _, err = time.Parse("15:04", expected); err == nil {
+				backup().StartTime = expected
+			}
+		case checkBackupRetention.ID:
+			var retained int64
+			if retained, err = strconv.ParseInt(expected, 10, 64); err == nil {
+				backup().BackupRetentionSettings = &sqladmin.BackupRetentionSettings{RetainedBackups: retained, RetentionUnit: "COUNT"}
+			}
+		case checkQueryInsights.ID, checkPolicyQueryInsights.ID:
+			insights().QueryInsightsEnabled, err = strconv.ParseBool(expected)
+		case checkPolicyApplicationTags.ID:
+			insights().RecordApplicationTags, err = strconv.ParseBool(expected)
+		case checkQueryPlansPerMinute.ID:
+			insights().QueryPlansPerMinute, err = strconv.ParseInt(expected, 10, 64)
+		case checkQueryStringLength.ID, checkPolicyQueryLength.ID:
+			insights().QueryStringLength, err = strconv.ParseInt(expected, 10, 64)
+		default:
+			return nil, fmt.Errorf("check %s is not whitelisted for auto-remediation", step.CheckID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid expected value %q for %s: %w", step.Expected, step.Field, err)
+		}
+	}
+
+	return &sqladmin.DatabaseInstance{Settings: settings}, nil
+}
+
+// expectedValue strips the annotations policy drifts add to expected values,
+// e.g. "true (org policy)" or ">= 1024 (org policy)"
+func expectedValue(value string) string {
+	if i := strings.Index(value, " ("); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(strings.TrimPrefix(value, ">="))
+}
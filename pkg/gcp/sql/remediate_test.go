@@ -0,0 +1,123 @@
+package sql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"google.golang.org/api/sqladmin/v1"
+)
+
+func TestBuildPatch(t *testing.T) {
+	inst := &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{
+			SettingsVersion:   42,
+			UserLabels:        map[string]string{"env": "prod", "team": "ledger"},
+			MaintenanceWindow: &sqladmin.MaintenanceWindow{Day: 1, Hour: 3, UpdateTrack: "stable"},
+			InsightsConfig:    &sqladmin.InsightsConfig{QueryStringLength: 1024, QueryPlansPerMinute: 5},
+		},
+	}
+
+	steps := []checks.Step{
+		{CheckID: checkLabel.ID, Field: "labels.team", Expected: "payments"},
+		{CheckID: checkMaintenanceHour.ID, Field: "settings.maintenance_window.hour", Expected: "0"},
+		{CheckID: checkPolicyQueryInsights.ID, Field: "settings.insights_config.query_insights_enabled", Expected: "true (org policy)"},
+		{CheckID: checkPolicyQueryLength.ID, Field: "settings.insights_config.query_string_length", Expected: ">= 4096 (org policy)"},
+	}
+
+	patch, err := buildPatch(inst, steps)
+	if err != nil {
+		t.Fatalf("buildPatch() error = %v", err)
+	}
+
+	settings := patch.Settings
+	if settings.SettingsVersion != 42 {
+		t.Errorf("SettingsVersion = %d, want 42", settings.SettingsVersion)
+	}
+	if settings.UserLabels["team"] != "payments" || settings.UserLabels["env"] != "prod" {
+		t.Errorf("UserLabels = %v, want team updated and env kept", settings.UserLabels)
+	}
+	if inst.Settings.UserLabels["team"] != "ledger" {
+		t.Error("buildPatch() modified the current instance labels")
+	}
+	if w := settings.MaintenanceWindow; w.Day != 1 || w.Hour != 0 || w.UpdateTrack != "stable" {
+		t.Errorf("MaintenanceWindow = %+v, want day 1 hour 0 stable", w)
+	}
+	if ic := settings.InsightsConfig; !ic.QueryInsightsEnabled || ic.QueryStringLength != 4096 || ic.QueryPlansPerMinute != 5 {
+		t.Errorf("InsightsConfig = %+v", ic)
+	}
+}
+
+func TestBuildPatchBackups(t *testing.T) {
+	inst := &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{
+			BackupConfiguration: &sqladmin.BackupConfiguration{StartTime: "02:00", PointInTimeRecoveryEnabled: true},
+		},
+	}
+
+	patch, err := buildPatch(inst, []checks.Step{
+		{CheckID: checkBackupEnabled.ID, Field: "settings.backup_enabled", Expected: "true"},
+		{CheckID: checkBackupRetention.ID, Field: "settings.backup_retention_days", Expected: "14"},
+	})
+	if err != nil {
+		t.Fatalf("buildPatch() error = %v", err)
+	}
+	b := patch.Settings.BackupConfiguration
+	if !b.Enabled || b.StartTime != "02:00" || !b.PointInTimeRecoveryEnabled {
+		t.Errorf("BackupConfiguration = %+v, want enabled with the current start time and PITR kept", b)
+	}
+	if r := b.BackupRetentionSettings; r == nil || r.RetainedBackups != 14 || r.RetentionUnit != "COUNT" {
+		t.Errorf("BackupRetentionSettings = %+v, want 14 backups", r)
+	}
+
+	if _, err := buildPatch(inst, []checks.Step{{CheckID: checkBackupEnabled.ID, Field: "settings.backup_enabled", Expected: "false"}}); err == nil {
+		t.Error("buildPatch() must not disable backups")
+	}
+	if _, err := buildPatch(inst, []checks.Step{{CheckID: checkBackupStartTime.ID, Field: "settings.backup_start_time", Expected: "25:00"}}); err == nil {
+		t.Error("buildPatch() should reject an invalid start time")
+	}
+}
+
+func TestBuildPatchRejectsUnsupported(t *testing.T) {
+	inst := &sqladmin.DatabaseInstance{Settings: &sqladmin.Settings{}}
+
+	if _, err := buildPatch(inst, []checks.Step{{CheckID: checkTier.ID, Field: "tier", Expected: "db-custom-4-16384"}}); err == nil {
+		t.Error("buildPatch() should reject checks that are not whitelisted")
+	}
+	if _, err := buildPatch(inst, []checks.Step{{CheckID: checkMaintenanceDay.ID, Field: "settings.maintenance_window.day", Expected: "sunday"}}); err == nil {
+		t.Error("buildPatch() should reject invalid values")
+	}
+	if (&Remediator{}).Supports(checkTier.ID) {
+		t.Error("tier must not be whitelisted")
+	}
+}
+
+func TestCheckUnchanged(t *testing.T) {
+	inst := &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{
+			UserLabels:        map[string]string{"team": "ledger"},
+			MaintenanceWindow: &sqladmin.MaintenanceWindow{Day: 1, Hour: 3},
+		},
+	}
+
+	steps := []checks.Step{
+		{CheckID: checkLabel.ID, Field: "labels.team", Expected: "payments", Actual: "ledger"},
+		{CheckID: checkLabel.ID, Field: "labels.env", Expected: "prod", Actual: "not set"},
+		{CheckID: checkMaintenanceHour.ID, Field: "settings.maintenance_window.hour", Expected: "0", Actual: "3"},
+		{CheckID: checkPolicyQueryInsights.ID, Field: "settings.insights_config.query_insights_enabled", Expected: "true (org policy)", Actual: "false"},
+	}
+	if err := checkUnchanged(inst, steps); err != nil {
+		t.Fatalf("checkUnchanged() error = %v", err)
+	}
+
+	// Someone moved the window after the plan was made
+	inst.Settings.MaintenanceWindow.Hour = 5
+	err := checkUnchanged(inst, steps)
+	if !errors.Is(err, ErrSettingsChanged) {
+		t.Fatalf("checkUnchanged() error = %v, want ErrSettingsChanged", err)
+	}
+	if !strings.Contains(err.Error(), `settings.maintenance_window.hour is "5", the plan expected "3"`) {
+		t.Errorf("checkUnchanged() error = %v", err)
+	}
+}
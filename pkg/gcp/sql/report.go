@@ -17,6 +17,14 @@ type DriftReport struct {
 	TotalInstances   int              `json:"total_instances" yaml:"total_instances"`
 	DriftedInstances int              `json:"drifted_instances" yaml:"drifted_instances"`
 	Instances        []*InstanceDrift `json:"instances" yaml:"instances"`
+	// Warnings flag a baseline that is due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []report.ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []report.Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []report.SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
 }
 
 // InstanceDrift represents drift analysis results for a single database instance
@@ -27,7 +35,11 @@ type InstanceDrift struct {
 	State             string             `json:"state" yaml:"state"`
 	Labels            map[string]string  `json:"labels,omitempty" yaml:"labels,omitempty"`
 	Databases         []string           `json:"databases,omitempty" yaml:"databases,omitempty"`
+	Replicas          []Replica          `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+	Users             []User             `json:"users,omitempty" yaml:"users,omitempty"`
+	Certificates      []Certificate      `json:"certificates,omitempty" yaml:"certificates,omitempty"`
 	MaintenanceWindow *MaintenanceWindow `json:"maintenance_window,omitempty" yaml:"maintenance_window,omitempty"`
+	Inventory         *Inventory         `json:"inventory,omitempty" yaml:"inventory,omitempty"`
 	Drifts            []Drift            `json:"drifts" yaml:"drifts"`
 	Recommendations   []string           `json:"recommendations" yaml:"recommendations"`
 }
@@ -40,7 +52,7 @@ func (r *DriftReport) FormatText() string {
 	var sb strings.Builder
 
 	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
-	sb.WriteString("  GCP PostgreSQL Drift Analysis Report\n")
+	sb.WriteString("  GCP Cloud SQL Drift Analysis Report\n")
 	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
 	sb.WriteString(fmt.Sprintf("Generated: %s\n", r.Timestamp.Format(time.RFC3339)))
 	sb.WriteString(fmt.Sprintf("Total Instances: %d\n", r.TotalInstances))
@@ -51,6 +63,10 @@ func (r *DriftReport) FormatText() string {
 	// Summary by severity
 	criticalCount, highCount, mediumCount, lowCount := r.countBySeverity()
 	sb.WriteString(report.FormatDriftSummary(criticalCount, highCount, mediumCount, lowCount))
+	sb.WriteString(report.FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(report.FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(report.FormatSLO(r.SLO))
+	sb.WriteString(FormatInventory(r.Instances))
 
 	// Detailed instance reports
 	for i, inst := range r.Instances {
@@ -59,6 +75,7 @@ func (r *DriftReport) FormatText() string {
 		}
 		sb.WriteString(inst.FormatText())
 	}
+	sb.WriteString(report.FormatScanErrors(r.Errors))
 
 	return sb.String()
 }
@@ -116,6 +133,38 @@ func (id *InstanceDrift) FormatText() string {
 		}
 	}
 
+	if len(id.Replicas) > 0 {
+		replicas := make([]string, 0, len(id.Replicas))
+		for _, replica := range id.Replicas {
+			region := replica.Region
+			if region == "" {
+				region = "unknown region"
+			}
+			replicas = append(replicas, fmt.Sprintf("%s (%s, %s)", replica.Name, replica.Type, region))
+		}
+		sb.WriteString(labelStyle.Render("Replicas: ") + valueStyle.Render(strings.Join(replicas, ", ")) + "\n")
+	}
+
+	if len(id.Users) > 0 {
+		users := make([]string, 0, len(id.Users))
+		for _, user := range id.Users {
+			users = append(users, fmt.Sprintf("%s (%s)", user.Name, user.Type))
+		}
+		sb.WriteString(labelStyle.Render("Users: ") + valueStyle.Render(strings.Join(users, ", ")) + "\n")
+	}
+
+	if len(id.Certificates) > 0 {
+		certs := make([]string, 0, len(id.Certificates))
+		for _, cert := range id.Certificates {
+			name := cert.CommonName
+			if cert.Kind == "server_ca" {
+				name = "server CA"
+			}
+			certs = append(certs, fmt.Sprintf("%s (expires %s)", name, cert.Expires.Format("2006-01-02")))
+		}
+		sb.WriteString(labelStyle.Render("Certificates: ") + valueStyle.Render(strings.Join(certs, ", ")) + "\n")
+	}
+
 	if id.MaintenanceWindow != nil {
 		sb.WriteString(labelStyle.Render("Maintenance Window: ") +
 			valueStyle.Render(fmt.Sprintf("Day %d, Hour %d UTC (%s)",
@@ -157,3 +206,38 @@ func (r *DriftReport) FormatYAML() (string, error) {
 	}
 	return string(data), nil
 }
+
+// ToReport converts the SQL drift report into the generic report model
+func (r *DriftReport) ToReport() *report.Report {
+	resources := make([]report.Resource, 0, len(r.Instances))
+	for _, inst := range r.Instances {
+		resource := report.Resource{
+			Type:     "Cloud SQL",
+			Project:  inst.Project,
+			Name:     inst.Name,
+			Location: inst.Region,
+			State:    inst.State,
+			Labels:   inst.Labels,
+			Drifts:   inst.Drifts,
+		}
+		if inst.Inventory != nil {
+			resource.Metrics = map[string]float64{MetricDiskGB: float64(inst.Inventory.DiskGB)}
+		}
+		resources = append(resources, resource)
+	}
+
+	return &report.Report{
+		Title:            "GCP Cloud SQL Drift Analysis Report",
+		Timestamp:        r.Timestamp,
+		Resources:        resources,
+		Warnings:         r.Warnings,
+		Errors:           r.Errors,
+		Acknowledgements: r.Acknowledgements,
+		SLO:              r.SLO,
+	}
+}
+
+// FormatNDJSON generates one JSON object per drift finding, tagged with a new scan ID
+func (r *DriftReport) FormatNDJSON() (string, error) {
+	return r.ToReport().FormatNDJSON(report.NewScanID())
+}
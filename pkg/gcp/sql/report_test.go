@@ -31,7 +31,7 @@ func TestDriftReport_FormatText(t *testing.T) {
 				},
 			},
 			want: []string{
-				"PostgreSQL Drift Analysis Report",
+				"Cloud SQL Drift Analysis Report",
 				"Total Instances: 2",
 				"Instances with Drift: 0",
 				"Compliance Rate: 100.0%",
@@ -58,7 +58,7 @@ func TestDriftReport_FormatText(t *testing.T) {
 				},
 			},
 			want: []string{
-				"PostgreSQL Drift Analysis Report",
+				"Cloud SQL Drift Analysis Report",
 				"Total Instances: 3",
 				"Instances with Drift: 1",
 				"Compliance Rate: 66.7%",
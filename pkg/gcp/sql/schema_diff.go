@@ -0,0 +1,508 @@
+package sql
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// TableDiff holds the changes to a table that is in both schemas
+type TableDiff struct {
+	Schema string `json:"schema" yaml:"schema"`
+	Name   string `json:"name" yaml:"name"`
+
+	AddedColumns    []ColumnInfo   `json:"added_columns,omitempty" yaml:"added_columns,omitempty"`
+	DeletedColumns  []ColumnInfo   `json:"deleted_columns,omitempty" yaml:"deleted_columns,omitempty"`
+	ModifiedColumns []ColumnChange `json:"modified_columns,omitempty" yaml:"modified_columns,omitempty"`
+
+	AddedIndexes    []IndexInfo   `json:"added_indexes,omitempty" yaml:"added_indexes,omitempty"`
+	DeletedIndexes  []IndexInfo   `json:"deleted_indexes,omitempty" yaml:"deleted_indexes,omitempty"`
+	ModifiedIndexes []IndexChange `json:"modified_indexes,omitempty" yaml:"modified_indexes,omitempty"`
+
+	AddedConstraints    []ConstraintInfo   `json:"added_constraints,omitempty" yaml:"added_constraints,omitempty"`
+	DeletedConstraints  []ConstraintInfo   `json:"deleted_constraints,omitempty" yaml:"deleted_constraints,omitempty"`
+	ModifiedConstraints []ConstraintChange `json:"modified_constraints,omitempty" yaml:"modified_constraints,omitempty"`
+
+	// OldStorageParams and NewStorageParams are the storage parameters of
+	// the table, set only when they differ
+	OldStorageParams map[string]string `json:"old_storage_params,omitempty" yaml:"old_storage_params,omitempty"`
+	NewStorageParams map[string]string `json:"new_storage_params,omitempty" yaml:"new_storage_params,omitempty"`
+}
+
+// ColumnChange is a column whose type, nullability, default or identity changed
+type ColumnChange struct {
+	Name string     `json:"name" yaml:"name"`
+	Old  ColumnInfo `json:"old" yaml:"old"`
+	New  ColumnInfo `json:"new" yaml:"new"`
+}
+
+// IndexChange is an index whose definition changed
+type IndexChange struct {
+	Name string    `json:"name" yaml:"name"`
+	Old  IndexInfo `json:"old" yaml:"old"`
+	New  IndexInfo `json:"new" yaml:"new"`
+}
+
+// ConstraintChange is a constraint whose type or definition changed
+type ConstraintChange struct {
+	Name string         `json:"name" yaml:"name"`
+	Old  ConstraintInfo `json:"old" yaml:"old"`
+	New  ConstraintInfo `json:"new" yaml:"new"`
+}
+
+// compareTable returns the changes from old to new of a table, or nil when
+// the two are the same. Columns, indexes and constraints are matched by name.
+func compareTable(old, new TableInfo) *TableDiff {
+	td := &TableDiff{Schema: new.Schema, Name: new.Name}
+
+	oldColumns := make(map[string]ColumnInfo)
+	for _, c := range old.Columns {
+		oldColumns[c.Name] = c
+	}
+	newColumns := make(map[string]bool)
+	for _, c := range new.Columns {
+		newColumns[c.Name] = true
+		oldColumn, exists := oldColumns[c.Name]
+		switch {
+		case !exists:
+			td.AddedColumns = append(td.AddedColumns, c)
+		case !columnsEqual(oldColumn, c):
+			td.ModifiedColumns = append(td.ModifiedColumns, ColumnChange{Name: c.Name, Old: oldColumn, New: c})
+		}
+	}
+	for _, c := range old.Columns {
+		if !newColumns[c.Name] {
+			td.DeletedColumns = append(td.DeletedColumns, c)
+		}
+	}
+
+	oldIndexes := make(map[string]IndexInfo)
+	for _, i := range old.Indexes {
+		oldIndexes[i.Name] = i
+	}
+	newIndexes := make(map[string]bool)
+	for _, i := range new.Indexes {
+		newIndexes[i.Name] = true
+		oldIndex, exists := oldIndexes[i.Name]
+		switch {
+		case !exists:
+			td.AddedIndexes = append(td.AddedIndexes, i)
+		case !indexesEqual(oldIndex, i):
+			td.ModifiedIndexes = append(td.ModifiedIndexes, IndexChange{Name: i.Name, Old: oldIndex, New: i})
+		}
+	}
+	for _, i := range old.Indexes {
+		if !newIndexes[i.Name] {
+			td.DeletedIndexes = append(td.DeletedIndexes, i)
+		}
+	}
+
+	oldConstraints := make(map[string]ConstraintInfo)
+	for _, c := range old.Constraints {
+		oldConstraints[c.Name] = c
+	}
+	newConstraints := make(map[string]bool)
+	for _, c := range new.Constraints {
+		newConstraints[c.Name] = true
+		oldConstraint, exists := oldConstraints[c.Name]
+		switch {
+		case !exists:
+			td.AddedConstraints = append(td.AddedConstraints, c)
+		case oldConstraint.Type != c.Type || oldConstraint.Definition != c.Definition:
+			td.ModifiedConstraints = append(td.ModifiedConstraints, ConstraintChange{Name: c.Name, Old: oldConstraint, New: c})
+		}
+	}
+	for _, c := range old.Constraints {
+		if !newConstraints[c.Name] {
+			td.DeletedConstraints = append(td.DeletedConstraints, c)
+		}
+	}
+
+	if !maps.Equal(old.StorageParams, new.StorageParams) {
+		td.OldStorageParams = old.StorageParams
+		td.NewStorageParams = new.StorageParams
+	}
+
+	if !td.hasChanges() {
+		return nil
+	}
+	return td
+}
+
+// hasChanges returns true if any part of the table changed
+func (td *TableDiff) hasChanges() bool {
+	return len(td.AddedColumns) > 0 || len(td.DeletedColumns) > 0 || len(td.ModifiedColumns) > 0 ||
+		len(td.AddedIndexes) > 0 || len(td.DeletedIndexes) > 0 || len(td.ModifiedIndexes) > 0 ||
+		len(td.AddedConstraints) > 0 || len(td.DeletedConstraints) > 0 || len(td.ModifiedConstraints) > 0 ||
+		td.OldStorageParams != nil || td.NewStorageParams != nil
+}
+
+func columnsEqual(a, b ColumnInfo) bool {
+	return a.DataType == b.DataType && a.IsNullable == b.IsNullable &&
+		a.IsIdentity == b.IsIdentity && columnDefault(a) == columnDefault(b)
+}
+
+// indexesEqual compares the definitions of two indexes, or what is known of
+// them when a cached schema has no definition
+func indexesEqual(a, b IndexInfo) bool {
+	if a.Definition != "" && b.Definition != "" {
+		return a.Definition == b.Definition
+	}
+	return a.IsUnique == b.IsUnique && a.IsPrimary == b.IsPrimary && slices.Equal(a.Columns, b.Columns)
+}
+
+// columnDefault returns the default expression of a column, or "" for none
+func columnDefault(c ColumnInfo) string {
+	if c.DefaultValue == nil {
+		return ""
+	}
+	return *c.DefaultValue
+}
+
+// Changes describes what changed in the column, e.g.
+// "type integer → bigint", "nullable → NOT NULL"
+func (cc ColumnChange) Changes() []string {
+	var changes []string
+	if cc.Old.DataType != cc.New.DataType {
+		changes = append(changes, fmt.Sprintf("type %s → %s", cc.Old.DataType, cc.New.DataType))
+	}
+	if cc.Old.IsNullable != cc.New.IsNullable {
+		changes = append(changes, fmt.Sprintf("%s → %s", nullability(cc.Old), nullability(cc.New)))
+	}
+	if columnDefault(cc.Old) != columnDefault(cc.New) {
+		changes = append(changes, fmt.Sprintf("default %s → %s", defaultDescription(cc.Old), defaultDescription(cc.New)))
+	}
+	if cc.Old.IsIdentity != cc.New.IsIdentity {
+		if cc.New.IsIdentity {
+			changes = append(changes, "became an identity column")
+		} else {
+			changes = append(changes, "no longer an identity column")
+		}
+	}
+	return changes
+}
+
+func nullability(c ColumnInfo) string {
+	if c.IsNullable {
+		return "nullable"
+	}
+	return "NOT NULL"
+}
+
+func defaultDescription(c ColumnInfo) string {
+	if c.DefaultValue == nil {
+		return "(none)"
+	}
+	return *c.DefaultValue
+}
+
+// Summary describes the changes to the table in one line per change, each
+// prefixed with + (added), - (deleted) or ~ (modified)
+func (td *TableDiff) Summary() []string {
+	var lines []string
+	for _, c := range td.AddedColumns {
+		lines = append(lines, fmt.Sprintf("+ column %s %s", c.Name, c.DataType))
+	}
+	for _, c := range td.DeletedColumns {
+		lines = append(lines, fmt.Sprintf("- column %s", c.Name))
+	}
+	for _, c := range td.ModifiedColumns {
+		lines = append(lines, fmt.Sprintf("~ column %s: %s", c.Name, strings.Join(c.Changes(), ", ")))
+	}
+	for _, i := range td.AddedIndexes {
+		lines = append(lines, fmt.Sprintf("+ index %s", indexDescription(i)))
+	}
+	for _, i := range td.DeletedIndexes {
+		lines = append(lines, fmt.Sprintf("- index %s", i.Name))
+	}
+	for _, i := range td.ModifiedIndexes {
+		lines = append(lines, fmt.Sprintf("~ index %s: %s → %s", i.Name, indexDescription(i.Old), indexDescription(i.New)))
+	}
+	for _, c := range td.AddedConstraints {
+		lines = append(lines, fmt.Sprintf("+ constraint %s %s", c.Name, c.Definition))
+	}
+	for _, c := range td.DeletedConstraints {
+		lines = append(lines, fmt.Sprintf("- constraint %s", c.Name))
+	}
+	for _, c := range td.ModifiedConstraints {
+		lines = append(lines, fmt.Sprintf("~ constraint %s: %s → %s", c.Name, c.Old.Definition, c.New.Definition))
+	}
+	if td.OldStorageParams != nil || td.NewStorageParams != nil {
+		lines = append(lines, fmt.Sprintf("~ storage parameters: %s → %s",
+			storageParamsDescription(td.OldStorageParams), storageParamsDescription(td.NewStorageParams)))
+	}
+	return lines
+}
+
+// indexDescription is the definition of an index, or its name and columns
+// when a cached schema has no definition
+func indexDescription(i IndexInfo) string {
+	if i.Definition != "" {
+		return i.Definition
+	}
+	kind := ""
+	if i.IsUnique {
+		kind = "unique "
+	}
+	return fmt.Sprintf("%s %s(%s)", i.Name, kind, strings.Join(i.Columns, ", "))
+}
+
+func storageParamsDescription(params map[string]string) string {
+	if len(params) == 0 {
+		return "(none)"
+	}
+	return formatStorageParams(params)
+}
+
+// FunctionChange is a function whose definition, return type or language
+// changed; Signature is its schema-qualified name and arguments
+type FunctionChange struct {
+	Signature string       `json:"signature" yaml:"signature"`
+	Old       FunctionInfo `json:"old" yaml:"old"`
+	New       FunctionInfo `json:"new" yaml:"new"`
+}
+
+// ProcedureChange is a procedure whose definition or language changed
+type ProcedureChange struct {
+	Signature string        `json:"signature" yaml:"signature"`
+	Old       ProcedureInfo `json:"old" yaml:"old"`
+	New       ProcedureInfo `json:"new" yaml:"new"`
+}
+
+// SequenceChange is a sequence whose type, start, bounds or increment changed
+type SequenceChange struct {
+	Name string       `json:"name" yaml:"name"`
+	Old  SequenceInfo `json:"old" yaml:"old"`
+	New  SequenceInfo `json:"new" yaml:"new"`
+}
+
+// SettingChange is a setting override whose value changed; Old and New carry
+// the role of ALTER ROLE ... IN DATABASE overrides
+type SettingChange struct {
+	Name string          `json:"name" yaml:"name"`
+	Old  DatabaseSetting `json:"old" yaml:"old"`
+	New  DatabaseSetting `json:"new" yaml:"new"`
+}
+
+// Signature returns the schema-qualified name and arguments of the function,
+// which tell overloads apart
+func (f FunctionInfo) Signature() string {
+	return fmt.Sprintf("%s.%s(%s)", f.Schema, f.Name, f.Arguments)
+}
+
+// Signature returns the schema-qualified name and arguments of the procedure
+func (p ProcedureInfo) Signature() string {
+	return fmt.Sprintf("%s.%s(%s)", p.Schema, p.Name, p.Arguments)
+}
+
+// compareFunctions diffs functions by signature. Definitions are only
+// compared when both schemas have them, so caches taken before definitions
+// were collected don't report every function as modified.
+func (sd *SchemaDiff) compareFunctions(old []FunctionInfo, new []FunctionInfo) {
+	oldFunctions := make(map[string]FunctionInfo)
+	for _, f := range old {
+		oldFunctions[f.Signature()] = f
+	}
+
+	newFunctions := make(map[string]bool)
+	for _, f := range new {
+		newFunctions[f.Signature()] = true
+		oldFunction, exists := oldFunctions[f.Signature()]
+		switch {
+		case !exists:
+			sd.AddedFunctions = append(sd.AddedFunctions, f)
+		case oldFunction.ReturnType != f.ReturnType || oldFunction.Language != f.Language ||
+			definitionChanged(oldFunction.Definition, f.Definition):
+			sd.ModifiedFunctions = append(sd.ModifiedFunctions, FunctionChange{Signature: f.Signature(), Old: oldFunction, New: f})
+		}
+	}
+
+	for _, f := range old {
+		if !newFunctions[f.Signature()] {
+			sd.DeletedFunctions = append(sd.DeletedFunctions, f)
+		}
+	}
+}
+
+// compareProcedures diffs procedures by signature, like compareFunctions
+func (sd *SchemaDiff) compareProcedures(old []ProcedureInfo, new []ProcedureInfo) {
+	oldProcedures := make(map[string]ProcedureInfo)
+	for _, p := range old {
+		oldProcedures[p.Signature()] = p
+	}
+
+	newProcedures := make(map[string]bool)
+	for _, p := range new {
+		newProcedures[p.Signature()] = true
+		oldProcedure, exists := oldProcedures[p.Signature()]
+		switch {
+		case !exists:
+			sd.AddedProcedures = append(sd.AddedProcedures, p)
+		case oldProcedure.Language != p.Language || definitionChanged(oldProcedure.Definition, p.Definition):
+			sd.ModifiedProcedures = append(sd.ModifiedProcedures, ProcedureChange{Signature: p.Signature(), Old: oldProcedure, New: p})
+		}
+	}
+
+	for _, p := range old {
+		if !newProcedures[p.Signature()] {
+			sd.DeletedProcedures = append(sd.DeletedProcedures, p)
+		}
+	}
+}
+
+// compareSequences diffs sequences by schema-qualified name. Their settings
+// are only compared when both schemas have them.
+func (sd *SchemaDiff) compareSequences(old []SequenceInfo, new []SequenceInfo) {
+	key := func(s SequenceInfo) string {
+		return s.Schema + "." + s.Name
+	}
+
+	oldSequences := make(map[string]SequenceInfo)
+	for _, s := range old {
+		oldSequences[key(s)] = s
+	}
+
+	newSequences := make(map[string]bool)
+	for _, s := range new {
+		newSequences[key(s)] = true
+		oldSequence, exists := oldSequences[key(s)]
+		switch {
+		case !exists:
+			sd.AddedSequences = append(sd.AddedSequences, s)
+		case oldSequence.DataType != "" && s.DataType != "" && len(SequenceChange{Old: oldSequence, New: s}.Changes()) > 0:
+			sd.ModifiedSequences = append(sd.ModifiedSequences, SequenceChange{Name: key(s), Old: oldSequence, New: s})
+		}
+	}
+
+	for _, s := range old {
+		if !newSequences[key(s)] {
+			sd.DeletedSequences = append(sd.DeletedSequences, s)
+		}
+	}
+}
+
+// definitionChanged reports whether two definitions differ, ignoring a
+// missing one
+func definitionChanged(old, new string) bool {
+	return old != "" && new != "" && strings.TrimSpace(old) != strings.TrimSpace(new)
+}
+
+// Changes describes what changed in the sequence, e.g. "increment 1 → 10"
+func (sc SequenceChange) Changes() []string {
+	var changes []string
+	if sc.Old.DataType != sc.New.DataType {
+		changes = append(changes, fmt.Sprintf("type %s → %s", sc.Old.DataType, sc.New.DataType))
+	}
+	if sc.Old.StartValue != sc.New.StartValue {
+		changes = append(changes, fmt.Sprintf("start %d → %d", sc.Old.StartValue, sc.New.StartValue))
+	}
+	if sc.Old.Increment != sc.New.Increment {
+		changes = append(changes, fmt.Sprintf("increment %d → %d", sc.Old.Increment, sc.New.Increment))
+	}
+	if bound(sc.Old.MinValue) != bound(sc.New.MinValue) {
+		changes = append(changes, fmt.Sprintf("min %s → %s", bound(sc.Old.MinValue), bound(sc.New.MinValue)))
+	}
+	if bound(sc.Old.MaxValue) != bound(sc.New.MaxValue) {
+		changes = append(changes, fmt.Sprintf("max %s → %s", bound(sc.Old.MaxValue), bound(sc.New.MaxValue)))
+	}
+	return changes
+}
+
+// Changes describes what changed in the function
+func (fc FunctionChange) Changes() []string {
+	var changes []string
+	if fc.Old.ReturnType != fc.New.ReturnType {
+		changes = append(changes, fmt.Sprintf("returns %s → %s", fc.Old.ReturnType, fc.New.ReturnType))
+	}
+	if fc.Old.Language != fc.New.Language {
+		changes = append(changes, fmt.Sprintf("language %s → %s", fc.Old.Language, fc.New.Language))
+	}
+	if definitionChanged(fc.Old.Definition, fc.New.Definition) {
+		changes = append(changes, "definition changed")
+	}
+	return changes
+}
+
+// Changes describes what changed in the procedure
+func (pc ProcedureChange) Changes() []string {
+	var changes []string
+	if pc.Old.Language != pc.New.Language {
+		changes = append(changes, fmt.Sprintf("language %s → %s", pc.Old.Language, pc.New.Language))
+	}
+	if definitionChanged(pc.Old.Definition, pc.New.Definition) {
+		changes = append(changes, "definition changed")
+	}
+	return changes
+}
+
+func bound(v *int64) string {
+	if v == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%d", *v)
+}
+
+// OwnerChange is an object in both schemas whose owner changed
+type OwnerChange struct {
+	// ObjectType is database, table, view, sequence, function or procedure
+	ObjectType string `json:"object_type" yaml:"object_type"`
+	// Schema is empty for the database
+	Schema string `json:"schema,omitempty" yaml:"schema,omitempty"`
+	// Name is schema-qualified; functions and procedures carry their signature
+	Name string `json:"name" yaml:"name"`
+	Old  string `json:"old" yaml:"old"`
+	New  string `json:"new" yaml:"new"`
+}
+
+// compareOwners diffs the owners of the database and of the objects in both
+// schemas; an owner missing on either side is not compared
+func (sd *SchemaDiff) compareOwners(old *DatabaseSchema, new *DatabaseSchema) {
+	add := func(objectType, schema, name, oldOwner, newOwner string) {
+		if oldOwner != "" && newOwner != "" && oldOwner != newOwner {
+			sd.ChangedOwners = append(sd.ChangedOwners, OwnerChange{ObjectType: objectType, Schema: schema, Name: name, Old: oldOwner, New: newOwner})
+		}
+	}
+
+	add("database", "", new.DatabaseName, old.Owner, new.Owner)
+
+	tables := make(map[string]string)
+	for _, t := range old.Tables {
+		tables[t.Schema+"."+t.Name] = t.Owner
+	}
+	for _, t := range new.Tables {
+		add("table", t.Schema, t.Schema+"."+t.Name, tables[t.Schema+"."+t.Name], t.Owner)
+	}
+
+	views := make(map[string]string)
+	for _, v := range old.Views {
+		views[v.Schema+"."+v.Name] = v.Owner
+	}
+	for _, v := range new.Views {
+		add("view", v.Schema, v.Schema+"."+v.Name, views[v.Schema+"."+v.Name], v.Owner)
+	}
+
+	sequences := make(map[string]string)
+	for _, s := range old.Sequences {
+		sequences[s.Schema+"."+s.Name] = s.Owner
+	}
+	for _, s := range new.Sequences {
+		add("sequence", s.Schema, s.Schema+"."+s.Name, sequences[s.Schema+"."+s.Name], s.Owner)
+	}
+
+	functions := make(map[string]string)
+	for _, f := range old.Functions {
+		functions[f.Signature()] = f.Owner
+	}
+	for _, f := range new.Functions {
+		add("function", f.Schema, f.Signature(), functions[f.Signature()], f.Owner)
+	}
+
+	procedures := make(map[string]string)
+	for _, p := range old.Procedures {
+		procedures[p.Signature()] = p.Owner
+	}
+	for _, p := range new.Procedures {
+		add("procedure", p.Schema, p.Signature(), procedures[p.Signature()], p.Owner)
+	}
+}
@@ -0,0 +1,251 @@
+package sql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestCompareSchemas_TableDetail(t *testing.T) {
+	old := &DatabaseSchema{Tables: []TableInfo{{
+		Schema: "public",
+		Name:   "orders",
+		Columns: []ColumnInfo{
+			{Name: "id", DataType: "integer"},
+			{Name: "status", DataType: "text", IsNullable: true},
+			{Name: "note", DataType: "text", IsNullable: true},
+		},
+		Indexes: []IndexInfo{
+			{Name: "orders_pkey", IsPrimary: true, IsUnique: true, Columns: []string{"id"}, Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)"},
+			{Name: "orders_status_idx", Columns: []string{"status"}, Definition: "CREATE INDEX orders_status_idx ON public.orders USING btree (status)"},
+		},
+		Constraints: []ConstraintInfo{
+			{Name: "orders_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"},
+			{Name: "orders_status_check", Type: "CHECK", Definition: "CHECK ((status <> ''::text))"},
+		},
+	}}}
+	new := &DatabaseSchema{Tables: []TableInfo{{
+		Schema: "public",
+		Name:   "orders",
+		Columns: []ColumnInfo{
+			{Name: "id", DataType: "bigint"},
+			{Name: "status", DataType: "text", DefaultValue: strPtr("'new'::text")},
+			{Name: "created_at", DataType: "timestamp with time zone"},
+		},
+		Indexes: []IndexInfo{
+			{Name: "orders_pkey", IsPrimary: true, IsUnique: true, Columns: []string{"id"}, Definition: "CREATE UNIQUE INDEX orders_pkey ON public.orders USING btree (id)"},
+			{Name: "orders_status_idx", Columns: []string{"status", "created_at"}, Definition: "CREATE INDEX orders_status_idx ON public.orders USING btree (status, created_at)"},
+			{Name: "orders_created_at_idx", Columns: []string{"created_at"}, Definition: "CREATE INDEX orders_created_at_idx ON public.orders USING btree (created_at)"},
+		},
+		Constraints: []ConstraintInfo{
+			{Name: "orders_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"},
+		},
+	}}}
+
+	diff := CompareSchemas(old, new)
+	if len(diff.ModifiedTables) != 1 {
+		t.Fatalf("Expected orders to be modified, got %+v", diff.ModifiedTables)
+	}
+	td := diff.ModifiedTables[0]
+
+	if len(td.AddedColumns) != 1 || td.AddedColumns[0].Name != "created_at" {
+		t.Errorf("Expected created_at to be added, got %+v", td.AddedColumns)
+	}
+	if len(td.DeletedColumns) != 1 || td.DeletedColumns[0].Name != "note" {
+		t.Errorf("Expected note to be deleted, got %+v", td.DeletedColumns)
+	}
+	if len(td.ModifiedColumns) != 2 {
+		t.Fatalf("Expected id and status to be modified, got %+v", td.ModifiedColumns)
+	}
+	if got, want := td.ModifiedColumns[0].Changes(), []string{"type integer → bigint"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("id changes = %v, want %v", got, want)
+	}
+	if got, want := td.ModifiedColumns[1].Changes(), []string{"nullable → NOT NULL", "default (none) → 'new'::text"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("status changes = %v, want %v", got, want)
+	}
+
+	if len(td.AddedIndexes) != 1 || td.AddedIndexes[0].Name != "orders_created_at_idx" {
+		t.Errorf("Expected orders_created_at_idx to be added, got %+v", td.AddedIndexes)
+	}
+	if len(td.ModifiedIndexes) != 1 || td.ModifiedIndexes[0].Name != "orders_status_idx" {
+		t.Errorf("Expected orders_status_idx to be modified, got %+v", td.ModifiedIndexes)
+	}
+	if len(td.DeletedConstraints) != 1 || td.DeletedConstraints[0].Name != "orders_status_check" {
+		t.Errorf("Expected orders_status_check to be deleted, got %+v", td.DeletedConstraints)
+	}
+	if len(td.AddedConstraints) != 0 || len(td.ModifiedConstraints) != 0 {
+		t.Errorf("Expected no other constraint changes, got %+v", td)
+	}
+}
+
+func TestCompareSchemas_UnchangedTable(t *testing.T) {
+	table := TableInfo{
+		Schema:      "public",
+		Name:        "users",
+		Columns:     []ColumnInfo{{Name: "id", DataType: "integer"}, {Name: "email", DataType: "text", DefaultValue: strPtr("''::text")}},
+		Indexes:     []IndexInfo{{Name: "users_pkey", IsPrimary: true, Columns: []string{"id"}}},
+		Constraints: []ConstraintInfo{{Name: "users_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"}},
+	}
+	// Defaults are compared by value, not by pointer
+	other := table
+	other.Columns = []ColumnInfo{{Name: "id", DataType: "integer"}, {Name: "email", DataType: "text", DefaultValue: strPtr("''::text")}}
+
+	diff := CompareSchemas(&DatabaseSchema{Tables: []TableInfo{table}}, &DatabaseSchema{Tables: []TableInfo{other}})
+	if diff.HasChanges() {
+		t.Errorf("Expected no changes, got %+v", diff)
+	}
+}
+
+func TestTableDiff_Summary(t *testing.T) {
+	td := TableDiff{
+		AddedColumns:     []ColumnInfo{{Name: "created_at", DataType: "timestamp"}},
+		DeletedIndexes:   []IndexInfo{{Name: "orders_note_idx"}},
+		OldStorageParams: map[string]string{"fillfactor": "90"},
+	}
+
+	want := []string{
+		"+ column created_at timestamp",
+		"- index orders_note_idx",
+		"~ storage parameters: fillfactor=90 → (none)",
+	}
+	if got := td.Summary(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summary() = %v, want %v", got, want)
+	}
+}
+
+func TestCompareSchemas_Routines(t *testing.T) {
+	old := &DatabaseSchema{
+		Functions: []FunctionInfo{
+			{Schema: "public", Name: "total", Arguments: "order_id integer", ReturnType: "numeric", Language: "sql", Definition: "SELECT sum(amount) FROM items"},
+			{Schema: "public", Name: "total", Arguments: "order_id bigint", ReturnType: "numeric", Language: "sql", Definition: "SELECT 1"},
+			{Schema: "public", Name: "legacy", Language: "plpgsql", ReturnType: "void"},
+		},
+		Procedures: []ProcedureInfo{
+			{Schema: "public", Name: "archive", Arguments: "days integer", Language: "plpgsql", Definition: "BEGIN DELETE FROM orders; END"},
+		},
+	}
+	new := &DatabaseSchema{
+		Functions: []FunctionInfo{
+			{Schema: "public", Name: "total", Arguments: "order_id integer", ReturnType: "numeric", Language: "sql", Definition: "SELECT sum(amount) - sum(discount) FROM items"},
+			{Schema: "public", Name: "total", Arguments: "order_id bigint", ReturnType: "numeric", Language: "sql", Definition: "SELECT 1"},
+			{Schema: "public", Name: "refresh", Language: "plpgsql", ReturnType: "trigger"},
+		},
+		Procedures: []ProcedureInfo{
+			{Schema: "public", Name: "archive", Arguments: "days integer", Language: "plpgsql", Definition: "BEGIN DELETE FROM orders WHERE created_at < now() - days; END"},
+		},
+	}
+
+	diff := CompareSchemas(old, new)
+	if len(diff.AddedFunctions) != 1 || diff.AddedFunctions[0].Name != "refresh" {
+		t.Errorf("Expected refresh to be added, got %+v", diff.AddedFunctions)
+	}
+	if len(diff.DeletedFunctions) != 1 || diff.DeletedFunctions[0].Name != "legacy" {
+		t.Errorf("Expected legacy to be deleted, got %+v", diff.DeletedFunctions)
+	}
+	if len(diff.ModifiedFunctions) != 1 || diff.ModifiedFunctions[0].Signature != "public.total(order_id integer)" {
+		t.Fatalf("Expected only the integer overload of total to be modified, got %+v", diff.ModifiedFunctions)
+	}
+	if got, want := diff.ModifiedFunctions[0].Changes(), []string{"definition changed"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("total changes = %v, want %v", got, want)
+	}
+	if len(diff.ModifiedProcedures) != 1 || diff.ModifiedProcedures[0].Signature != "public.archive(days integer)" {
+		t.Errorf("Expected archive to be modified, got %+v", diff.ModifiedProcedures)
+	}
+	if !diff.HasChanges() {
+		t.Error("Expected changes")
+	}
+}
+
+func TestCompareSchemas_RoutinesWithoutDefinitions(t *testing.T) {
+	// A cache taken before definitions were collected
+	old := &DatabaseSchema{
+		Functions:  []FunctionInfo{{Schema: "public", Name: "total", ReturnType: "numeric", Language: "sql"}},
+		Sequences:  []SequenceInfo{{Schema: "public", Name: "orders_id_seq"}},
+		Procedures: []ProcedureInfo{{Schema: "public", Name: "archive", Language: "plpgsql"}},
+	}
+	new := &DatabaseSchema{
+		Functions:  []FunctionInfo{{Schema: "public", Name: "total", ReturnType: "numeric", Language: "sql", Definition: "SELECT 1"}},
+		Sequences:  []SequenceInfo{{Schema: "public", Name: "orders_id_seq", DataType: "bigint", StartValue: 1, Increment: 1}},
+		Procedures: []ProcedureInfo{{Schema: "public", Name: "archive", Language: "plpgsql", Definition: "BEGIN END"}},
+	}
+
+	if diff := CompareSchemas(old, new); diff.HasChanges() {
+		t.Errorf("Expected no changes, got %+v", diff)
+	}
+}
+
+func TestCompareSchemas_Sequences(t *testing.T) {
+	max := int64(2147483647)
+	old := &DatabaseSchema{Sequences: []SequenceInfo{
+		{Schema: "public", Name: "orders_id_seq", DataType: "integer", StartValue: 1, Increment: 1, MaxValue: &max},
+		{Schema: "public", Name: "old_seq", DataType: "bigint", StartValue: 1, Increment: 1},
+	}}
+	new := &DatabaseSchema{Sequences: []SequenceInfo{
+		{Schema: "public", Name: "orders_id_seq", DataType: "bigint", StartValue: 1, Increment: 10},
+		{Schema: "public", Name: "invoices_id_seq", DataType: "bigint", StartValue: 1, Increment: 1},
+	}}
+
+	diff := CompareSchemas(old, new)
+	if len(diff.AddedSequences) != 1 || diff.AddedSequences[0].Name != "invoices_id_seq" {
+		t.Errorf("Expected invoices_id_seq to be added, got %+v", diff.AddedSequences)
+	}
+	if len(diff.DeletedSequences) != 1 || diff.DeletedSequences[0].Name != "old_seq" {
+		t.Errorf("Expected old_seq to be deleted, got %+v", diff.DeletedSequences)
+	}
+	if len(diff.ModifiedSequences) != 1 {
+		t.Fatalf("Expected orders_id_seq to be modified, got %+v", diff.ModifiedSequences)
+	}
+	want := []string{"type integer → bigint", "increment 1 → 10", "max 2147483647 → (none)"}
+	if got := diff.ModifiedSequences[0].Changes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("orders_id_seq changes = %v, want %v", got, want)
+	}
+}
+
+func TestCompareSchemas_Owners(t *testing.T) {
+	old := &DatabaseSchema{
+		DatabaseName: "shop",
+		Owner:        "postgres",
+		Tables:       []TableInfo{{Schema: "public", Name: "orders", Owner: "app"}, {Schema: "public", Name: "audit", Owner: "app"}},
+		Functions:    []FunctionInfo{{Schema: "public", Name: "total", Arguments: "id integer DEFAULT 0", Owner: "app"}},
+	}
+	new := &DatabaseSchema{
+		DatabaseName: "shop",
+		Owner:        "postgres",
+		Tables:       []TableInfo{{Schema: "public", Name: "orders", Owner: "dba"}, {Schema: "public", Name: "audit"}},
+		Functions:    []FunctionInfo{{Schema: "public", Name: "total", Arguments: "id integer DEFAULT 0", Owner: "dba"}},
+	}
+
+	diff := CompareSchemas(old, new)
+	want := []OwnerChange{
+		{ObjectType: "table", Schema: "public", Name: "public.orders", Old: "app", New: "dba"},
+		{ObjectType: "function", Schema: "public", Name: "public.total(id integer DEFAULT 0)", Old: "app", New: "dba"},
+	}
+	if !reflect.DeepEqual(diff.ChangedOwners, want) {
+		t.Errorf("ChangedOwners = %+v, want %+v", diff.ChangedOwners, want)
+	}
+}
+
+func TestCompareSchemas_Settings(t *testing.T) {
+	old := &DatabaseSchema{Settings: []DatabaseSetting{
+		{Name: "statement_timeout", Value: "30s"},
+		{Name: "work_mem", Role: "reporting", Value: "64MB"},
+		{Name: "search_path", Value: "public"},
+	}}
+	new := &DatabaseSchema{Settings: []DatabaseSetting{
+		{Name: "work_mem", Role: "reporting", Value: "1GB"},
+		{Name: "search_path", Value: "public"},
+		{Name: "statement_timeout", Value: "0"},
+	}}
+
+	diff := CompareSchemas(old, new)
+	want := []SettingChange{
+		{Name: "work_mem", Old: DatabaseSetting{Name: "work_mem", Role: "reporting", Value: "64MB"}, New: DatabaseSetting{Name: "work_mem", Role: "reporting", Value: "1GB"}},
+		{Name: "statement_timeout", Old: DatabaseSetting{Name: "statement_timeout", Value: "30s"}, New: DatabaseSetting{Name: "statement_timeout", Value: "0"}},
+	}
+	if !reflect.DeepEqual(diff.ChangedSettings, want) {
+		t.Errorf("ChangedSettings = %+v, want %+v", diff.ChangedSettings, want)
+	}
+}
@@ -0,0 +1,118 @@
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// SchemaResourceType is the generic report type of inspected databases
+const SchemaResourceType = "Cloud SQL Database"
+
+// ToDrifts converts schema validation findings into generic drifts, so schema
+// drift can be rendered and notified like instance drift
+func (r *SchemaValidationResult) ToDrifts() []report.Drift {
+	drifts := make([]report.Drift, 0)
+	for _, cm := range r.CountMismatches {
+		drifts = append(drifts, report.Drift{
+			Field:    "schema." + strings.ToLower(cm.ObjectType) + ".count",
+			Expected: strconv.Itoa(cm.Expected),
+			Actual:   strconv.Itoa(cm.Actual),
+			Severity: "medium",
+		})
+	}
+	for _, mo := range r.MissingObjects {
+		drifts = append(drifts, report.Drift{
+			Field:    schemaObjectField(mo.ObjectType, mo.Name),
+			Expected: "present",
+			Actual:   "missing",
+			Severity: "high",
+		})
+	}
+	for _, fo := range r.ForbiddenObjects {
+		drifts = append(drifts, report.Drift{
+			Field:    schemaObjectField(fo.ObjectType, fo.Name),
+			Expected: "absent",
+			Actual:   "present",
+			Severity: "high",
+		})
+	}
+	for _, ov := range r.OwnershipViolations {
+		severity := "medium"
+		if ov.ViolationType == "forbidden_owner" {
+			severity = "high"
+		}
+		drifts = append(drifts, report.Drift{
+			Field:    schemaObjectField(ov.ObjectType, ov.ObjectName) + ".owner",
+			Expected: ov.ExpectedOwner,
+			Actual:   ov.ActualOwner,
+			Severity: severity,
+		})
+	}
+	for _, sv := range r.SettingViolations {
+		field := fmt.Sprintf("schema.setting[%s]", sv.Name)
+		if sv.Role != "" {
+			field = fmt.Sprintf("schema.role[%s].setting[%s]", sv.Role, sv.Name)
+		}
+		drifts = append(drifts, report.Drift{
+			Field:    field,
+			Expected: sv.Expected,
+			Actual:   sv.Actual,
+			Severity: "medium",
+		})
+	}
+	for _, sp := range r.StorageViolations {
+		drifts = append(drifts, report.Drift{
+			Field:    fmt.Sprintf("schema.table[%s].%s", sp.Table, sp.Param),
+			Expected: sp.Expected,
+			Actual:   sp.Actual,
+			Severity: "low",
+		})
+	}
+	for _, sv := range r.SpecViolations {
+		field := schemaObjectField(sv.ObjectType, sv.ObjectName)
+		if sv.Field != "" {
+			field += "." + sv.Field
+		}
+		severity := "medium"
+		if sv.ViolationType == "missing" {
+			severity = "high"
+		}
+		drifts = append(drifts, report.Drift{
+			Field:    field,
+			Expected: sv.Expected,
+			Actual:   sv.Actual,
+			Severity: severity,
+		})
+	}
+	return drifts
+}
+
+// schemaObjectField names a schema object as a drift field, e.g.
+// schema.table[public.users]
+func schemaObjectField(objectType, name string) string {
+	return fmt.Sprintf("schema.%s[%s]", strings.ToLower(objectType), name)
+}
+
+// SchemaResource describes an inspected database, its size and its schema
+// drift in the generic report model
+func SchemaResource(conn *DatabaseConnection, schema *DatabaseSchema, result *SchemaValidationResult) report.Resource {
+	res := report.Resource{
+		Type:   SchemaResourceType,
+		Name:   conn.Name,
+		Drifts: []report.Drift{},
+	}
+	// project:region:instance
+	if parts := strings.Split(conn.GetConnectionName(), ":"); len(parts) == 3 {
+		res.Project, res.Location = parts[0], parts[1]
+	}
+	if schema != nil {
+		res.Metrics = map[string]float64{MetricDatabaseSizeGB: DatabaseSizeGB(schema)}
+	}
+	if result != nil {
+		res.Drifts = result.ToDrifts()
+	}
+	return res
+}
@@ -0,0 +1,38 @@
+package sql
+
+import "testing"
+
+func TestSchemaResource(t *testing.T) {
+	conn := &DatabaseConnection{Name: "orders", InstanceConnectionName: "shop-prod:us-east1:orders-db", Database: "orders"}
+	result := &SchemaValidationResult{
+		HasDrift:            true,
+		CountMismatches:     []CountMismatch{{ObjectType: "Tables", Expected: 12, Actual: 14}},
+		MissingObjects:      []MissingObject{{ObjectType: "View", Name: "public.active_orders"}},
+		OwnershipViolations: []OwnershipViolation{{ObjectType: "Table", ObjectName: "public.orders", ActualOwner: "postgres", ExpectedOwner: "app", ViolationType: "forbidden_owner"}},
+		SettingViolations:   []SettingViolation{{Name: "statement_timeout", Role: "app", Expected: "30s", Actual: "0"}},
+	}
+
+	res := SchemaResource(conn, nil, result)
+	if res.Type != SchemaResourceType || res.Project != "shop-prod" || res.Location != "us-east1" {
+		t.Errorf("SchemaResource() = %s %s/%s, want %s shop-prod/us-east1", res.Type, res.Project, res.Location, SchemaResourceType)
+	}
+
+	want := []struct{ field, severity string }{
+		{"schema.tables.count", "medium"},
+		{"schema.view[public.active_orders]", "high"},
+		{"schema.table[public.orders].owner", "high"},
+		{"schema.role[app].setting[statement_timeout]", "medium"},
+	}
+	if len(res.Drifts) != len(want) {
+		t.Fatalf("got %d drifts, want %d: %+v", len(res.Drifts), len(want), res.Drifts)
+	}
+	for i, w := range want {
+		if res.Drifts[i].Field != w.field || res.Drifts[i].Severity != w.severity {
+			t.Errorf("drift %d = %s (%s), want %s (%s)", i, res.Drifts[i].Field, res.Drifts[i].Severity, w.field, w.severity)
+		}
+	}
+
+	if res := SchemaResource(conn, nil, nil); len(res.Drifts) != 0 {
+		t.Errorf("SchemaResource() without baseline has %d drifts, want none", len(res.Drifts))
+	}
+}
@@ -0,0 +1,373 @@
+package sql
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaSpec is a declarative, column-level description of a database schema,
+// usually kept in a YAML file checked into git next to the config. Only the
+// objects and fields it lists are validated, unless Strict is set.
+type SchemaSpec struct {
+	// Tables are keyed by "schema.table" or bare name
+	Tables map[string]TableSpec `yaml:"tables,omitempty"`
+	// Functions and Procedures are required signatures, e.g.
+	// public.order_total(order_id bigint)
+	Functions  []string `yaml:"functions,omitempty"`
+	Procedures []string `yaml:"procedures,omitempty"`
+	// Strict reports tables, functions and procedures not in the spec
+	Strict bool `yaml:"strict,omitempty"`
+}
+
+// TableSpec describes the expected definition of a table
+type TableSpec struct {
+	Owner       string                `yaml:"owner,omitempty"`
+	Columns     map[string]ColumnSpec `yaml:"columns,omitempty"`
+	Indexes     []IndexSpec           `yaml:"indexes,omitempty"`
+	Constraints []ConstraintSpec      `yaml:"constraints,omitempty"`
+	// Strict reports columns, indexes and constraints not in the spec.
+	// Indexes backing a constraint are covered by the constraint.
+	Strict bool `yaml:"strict,omitempty"`
+}
+
+// ColumnSpec describes the expected definition of a column; unset fields are
+// not checked
+type ColumnSpec struct {
+	Type     string `yaml:"type,omitempty"`
+	Nullable *bool  `yaml:"nullable,omitempty"`
+	// Default is the default expression, or "none" to require no default
+	Default  *string `yaml:"default,omitempty"`
+	Identity *bool   `yaml:"identity,omitempty"`
+}
+
+// IndexSpec describes a required index; unset fields are not checked
+type IndexSpec struct {
+	Name       string   `yaml:"name"`
+	Columns    []string `yaml:"columns,omitempty"`
+	Unique     *bool    `yaml:"unique,omitempty"`
+	Definition string   `yaml:"definition,omitempty"`
+}
+
+// ConstraintSpec describes a required constraint; unset fields are not checked
+type ConstraintSpec struct {
+	Name       string `yaml:"name"`
+	Type       string `yaml:"type,omitempty"`
+	Definition string `yaml:"definition,omitempty"`
+}
+
+// SpecViolation represents a field of a schema object that does not match
+// the schema spec
+type SpecViolation struct {
+	ObjectType    string // "Table", "Function", "Procedure"
+	ObjectName    string
+	Field         string // e.g. "column[id].type", "index[orders_status_idx]"; empty for the object itself
+	Expected      string
+	Actual        string
+	ViolationType string // "missing", "wrong_value", "unexpected"
+}
+
+// LoadSchemaSpec reads a schema spec file
+func LoadSchemaSpec(path string) (*SchemaSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema spec: %w", err)
+	}
+	var spec SchemaSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse schema spec %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// LoadSchemaSpecs loads the spec_file of every schema baseline, resolving
+// relative paths against dir (usually the directory of the config file)
+func (c *Config) LoadSchemaSpecs(dir string) error {
+	for i := range c.DatabaseConnections {
+		conn := &c.DatabaseConnections[i]
+		baseline := conn.SchemaBaseline
+		if baseline == nil || baseline.SpecFile == "" {
+			continue
+		}
+		if baseline.Spec != nil {
+			return fmt.Errorf("%s: schema_baseline spec and spec_file are mutually exclusive", conn.Name)
+		}
+		path := baseline.SpecFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		spec, err := LoadSchemaSpec(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", conn.Name, err)
+		}
+		baseline.Spec = spec
+	}
+	return nil
+}
+
+// NewSchemaSpec describes an inspected schema as a strict spec, as a starting
+// point for a spec file checked into git
+func NewSchemaSpec(schema *DatabaseSchema) *SchemaSpec {
+	spec := &SchemaSpec{Tables: make(map[string]TableSpec), Strict: true}
+	for _, table := range schema.Tables {
+		ts := TableSpec{Owner: table.Owner, Columns: make(map[string]ColumnSpec), Strict: true}
+		for _, col := range table.Columns {
+			def := "none"
+			if col.DefaultValue != nil {
+				def = *col.DefaultValue
+			}
+			ts.Columns[col.Name] = ColumnSpec{
+				Type:     col.DataType,
+				Nullable: boolPtr(col.IsNullable),
+				Default:  &def,
+				Identity: boolPtr(col.IsIdentity),
+			}
+		}
+		for _, idx := range table.Indexes {
+			if isConstraintIndex(table.Constraints, idx) {
+				continue
+			}
+			ts.Indexes = append(ts.Indexes, IndexSpec{Name: idx.Name, Columns: idx.Columns, Unique: boolPtr(idx.IsUnique)})
+		}
+		for _, con := range table.Constraints {
+			ts.Constraints = append(ts.Constraints, ConstraintSpec{Name: con.Name, Type: con.Type, Definition: con.Definition})
+		}
+		spec.Tables[table.Schema+"."+table.Name] = ts
+	}
+	for _, fn := range schema.Functions {
+		spec.Functions = append(spec.Functions, identitySignature(fn.Signature()))
+	}
+	for _, proc := range schema.Procedures {
+		spec.Procedures = append(spec.Procedures, identitySignature(proc.Signature()))
+	}
+	sort.Strings(spec.Functions)
+	sort.Strings(spec.Procedures)
+	return spec
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// validateSpec compares the schema against the spec field by field. Missing
+// tables and routines are reported as missing objects, everything else as
+// spec violations.
+func validateSpec(schema *DatabaseSchema, spec *SchemaSpec, result *SchemaValidationResult) {
+	tableMap := make(map[string]TableInfo)
+	for _, table := range schema.Tables {
+		tableMap[fmt.Sprintf("%s.%s", table.Schema, table.Name)] = table
+		if _, exists := tableMap[table.Name]; !exists {
+			tableMap[table.Name] = table
+		}
+	}
+
+	tableNames := make([]string, 0, len(spec.Tables))
+	for name := range spec.Tables {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+
+	specified := make(map[string]bool)
+	for _, tableName := range tableNames {
+		table, exists := tableMap[tableName]
+		if !exists {
+			if !containsMissing(result.MissingObjects, "Table", tableName) {
+				result.MissingObjects = append(result.MissingObjects, MissingObject{
+					ObjectType: "Table",
+					Name:       tableName,
+				})
+			}
+			continue
+		}
+		specified[fmt.Sprintf("%s.%s", table.Schema, table.Name)] = true
+		result.SpecViolations = append(result.SpecViolations, validateTableSpec(tableName, table, spec.Tables[tableName])...)
+	}
+
+	if spec.Strict {
+		for _, table := range schema.Tables {
+			name := fmt.Sprintf("%s.%s", table.Schema, table.Name)
+			if !specified[name] {
+				result.SpecViolations = append(result.SpecViolations, SpecViolation{
+					ObjectType:    "Table",
+					ObjectName:    name,
+					Expected:      "(not in spec)",
+					Actual:        "present",
+					ViolationType: "unexpected",
+				})
+			}
+		}
+	}
+
+	functions := make([]string, 0, len(schema.Functions))
+	for _, fn := range schema.Functions {
+		functions = append(functions, fn.Signature())
+	}
+	validateRoutines("Function", functions, spec.Functions, spec.Strict, result)
+
+	procedures := make([]string, 0, len(schema.Procedures))
+	for _, proc := range schema.Procedures {
+		procedures = append(procedures, proc.Signature())
+	}
+	validateRoutines("Procedure", procedures, spec.Procedures, spec.Strict, result)
+}
+
+// validateTableSpec compares a table against its spec
+func validateTableSpec(name string, table TableInfo, spec TableSpec) []SpecViolation {
+	var violations []SpecViolation
+	add := func(field, expected, actual, violationType string) {
+		violations = append(violations, SpecViolation{
+			ObjectType:    "Table",
+			ObjectName:    name,
+			Field:         field,
+			Expected:      expected,
+			Actual:        actual,
+			ViolationType: violationType,
+		})
+	}
+
+	if spec.Owner != "" && table.Owner != spec.Owner {
+		add("owner", spec.Owner, table.Owner, "wrong_value")
+	}
+
+	columns := make(map[string]ColumnInfo)
+	for _, col := range table.Columns {
+		columns[col.Name] = col
+	}
+	columnNames := make([]string, 0, len(spec.Columns))
+	for colName := range spec.Columns {
+		columnNames = append(columnNames, colName)
+	}
+	sort.Strings(columnNames)
+
+	for _, colName := range columnNames {
+		cs := spec.Columns[colName]
+		field := fmt.Sprintf("column[%s]", colName)
+		col, exists := columns[colName]
+		if !exists {
+			add(field, cmp.Or(cs.Type, "present"), "(missing)", "missing")
+			continue
+		}
+		if cs.Type != "" && !strings.EqualFold(col.DataType, cs.Type) {
+			add(field+".type", cs.Type, col.DataType, "wrong_value")
+		}
+		if cs.Nullable != nil && col.IsNullable != *cs.Nullable {
+			add(field+".nullable", nullability(ColumnInfo{IsNullable: *cs.Nullable}), nullability(col), "wrong_value")
+		}
+		if cs.Default != nil {
+			expected := *cs.Default
+			if expected == "none" {
+				expected = ""
+			}
+			if columnDefault(col) != expected {
+				add(field+".default", cmp.Or(expected, "(none)"), cmp.Or(columnDefault(col), "(none)"), "wrong_value")
+			}
+		}
+		if cs.Identity != nil && col.IsIdentity != *cs.Identity {
+			add(field+".identity", fmt.Sprint(*cs.Identity), fmt.Sprint(col.IsIdentity), "wrong_value")
+		}
+	}
+
+	indexes := make(map[string]IndexInfo)
+	for _, idx := range table.Indexes {
+		indexes[idx.Name] = idx
+	}
+	for _, is := range spec.Indexes {
+		field := fmt.Sprintf("index[%s]", is.Name)
+		idx, exists := indexes[is.Name]
+		if !exists {
+			add(field, cmp.Or(is.Definition, "present"), "(missing)", "missing")
+			continue
+		}
+		if len(is.Columns) > 0 && !slices.Equal(idx.Columns, is.Columns) {
+			add(field+".columns", strings.Join(is.Columns, ", "), strings.Join(idx.Columns, ", "), "wrong_value")
+		}
+		if is.Unique != nil && idx.IsUnique != *is.Unique {
+			add(field+".unique", fmt.Sprint(*is.Unique), fmt.Sprint(idx.IsUnique), "wrong_value")
+		}
+		if is.Definition != "" && idx.Definition != is.Definition {
+			add(field+".definition", is.Definition, idx.Definition, "wrong_value")
+		}
+	}
+
+	constraints := make(map[string]ConstraintInfo)
+	for _, con := range table.Constraints {
+		constraints[con.Name] = con
+	}
+	for _, cs := range spec.Constraints {
+		field := fmt.Sprintf("constraint[%s]", cs.Name)
+		con, exists := constraints[cs.Name]
+		if !exists {
+			add(field, cmp.Or(cs.Definition, cs.Type, "present"), "(missing)", "missing")
+			continue
+		}
+		if cs.Type != "" && !strings.EqualFold(con.Type, cs.Type) {
+			add(field+".type", cs.Type, con.Type, "wrong_value")
+		}
+		if cs.Definition != "" && con.Definition != cs.Definition {
+			add(field+".definition", cs.Definition, con.Definition, "wrong_value")
+		}
+	}
+
+	if spec.Strict {
+		for _, col := range table.Columns {
+			if _, listed := spec.Columns[col.Name]; !listed {
+				add(fmt.Sprintf("column[%s]", col.Name), "(not in spec)", col.DataType, "unexpected")
+			}
+		}
+		for _, idx := range table.Indexes {
+			listed := slices.ContainsFunc(spec.Indexes, func(is IndexSpec) bool { return is.Name == idx.Name })
+			if !listed && !isConstraintIndex(table.Constraints, idx) {
+				add(fmt.Sprintf("index[%s]", idx.Name), "(not in spec)", indexDescription(idx), "unexpected")
+			}
+		}
+		for _, con := range table.Constraints {
+			if !slices.ContainsFunc(spec.Constraints, func(cs ConstraintSpec) bool { return cs.Name == con.Name }) {
+				add(fmt.Sprintf("constraint[%s]", con.Name), "(not in spec)", cmp.Or(con.Definition, con.Type), "unexpected")
+			}
+		}
+	}
+
+	return violations
+}
+
+// validateRoutines checks that every required signature exists. Signatures
+// match with or without argument defaults.
+func validateRoutines(objectType string, signatures []string, required []string, strict bool, result *SchemaValidationResult) {
+	present := make(map[string]bool)
+	for _, sig := range signatures {
+		present[sig] = true
+		present[identitySignature(sig)] = true
+	}
+	listed := make(map[string]bool)
+	for _, sig := range required {
+		listed[sig] = true
+		listed[identitySignature(sig)] = true
+		if !present[sig] && !present[identitySignature(sig)] && !containsMissing(result.MissingObjects, objectType, sig) {
+			result.MissingObjects = append(result.MissingObjects, MissingObject{
+				ObjectType: objectType,
+				Name:       sig,
+			})
+		}
+	}
+
+	if !strict {
+		return
+	}
+	for _, sig := range signatures {
+		if !listed[sig] && !listed[identitySignature(sig)] {
+			result.SpecViolations = append(result.SpecViolations, SpecViolation{
+				ObjectType:    objectType,
+				ObjectName:    sig,
+				Expected:      "(not in spec)",
+				Actual:        "present",
+				ViolationType: "unexpected",
+			})
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package sql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func specTestSchema() *DatabaseSchema {
+	return &DatabaseSchema{
+		Tables: []TableInfo{
+			{
+				Schema: "public",
+				Name:   "orders",
+				Owner:  "postgres",
+				Columns: []ColumnInfo{
+					{Name: "id", DataType: "integer"},
+					{Name: "status", DataType: "text", IsNullable: true, DefaultValue: strPtr("'new'::text")},
+					{Name: "debug", DataType: "jsonb", IsNullable: true},
+				},
+				Indexes: []IndexInfo{
+					{Name: "orders_pkey", IsPrimary: true, IsUnique: true, Columns: []string{"id"}},
+					{Name: "orders_status_idx", Columns: []string{"status", "id"}},
+					{Name: "orders_tmp_idx", Columns: []string{"debug"}},
+				},
+				Constraints: []ConstraintInfo{{Name: "orders_pkey", Type: "PRIMARY KEY", Definition: "PRIMARY KEY (id)"}},
+			},
+			{Schema: "public", Name: "scratch"},
+		},
+		Functions: []FunctionInfo{
+			{Schema: "public", Name: "order_total", Arguments: "order_id bigint, currency text DEFAULT 'EUR'::text"},
+			{Schema: "public", Name: "debug_dump", Arguments: ""},
+		},
+	}
+}
+
+func TestValidateSchemaAgainstBaseline_Spec(t *testing.T) {
+	spec := &SchemaSpec{
+		Tables: map[string]TableSpec{
+			"public.orders": {
+				Owner: "app",
+				Columns: map[string]ColumnSpec{
+					"id":         {Type: "bigint", Nullable: boolPtr(false)},
+					"status":     {Type: "TEXT", Nullable: boolPtr(false), Default: strPtr("none")},
+					"created_at": {Type: "timestamp with time zone"},
+				},
+				Indexes: []IndexSpec{
+					{Name: "orders_status_idx", Columns: []string{"status"}},
+					{Name: "orders_created_at_idx"},
+				},
+				Constraints: []ConstraintSpec{{Name: "orders_pkey", Type: "primary key"}},
+				Strict:      true,
+			},
+			"payments": {},
+		},
+		Functions: []string{"public.order_total(order_id bigint, currency text)", "public.refund(order_id bigint)"},
+		Strict:    true,
+	}
+
+	result := ValidateSchemaAgainstBaseline(specTestSchema(), &SchemaBaseline{Spec: spec})
+	if !result.HasDrift {
+		t.Fatal("Expected drift to be detected")
+	}
+
+	got := make(map[string]string)
+	for _, v := range result.SpecViolations {
+		got[v.ObjectName+" "+v.Field] = v.ViolationType
+	}
+	want := map[string]string{
+		"public.orders owner":                            "wrong_value",
+		"public.orders column[created_at]":               "missing",
+		"public.orders column[id].type":                  "wrong_value",
+		"public.orders column[status].nullable":          "wrong_value",
+		"public.orders column[status].default":           "wrong_value",
+		"public.orders index[orders_status_idx].columns": "wrong_value",
+		"public.orders index[orders_created_at_idx]":     "missing",
+		"public.orders column[debug]":                    "unexpected",
+		"public.orders index[orders_tmp_idx]":            "unexpected",
+		"public.scratch ":                                "unexpected",
+		"public.debug_dump() ":                           "unexpected",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+	for key, violation := range want {
+		if got[key] != violation {
+			t.Errorf("%s = %q, want %q", key, got[key], violation)
+		}
+	}
+
+	missing := make(map[string]bool)
+	for _, m := range result.MissingObjects {
+		missing[m.ObjectType+" "+m.Name] = true
+	}
+	if len(missing) != 2 || !missing["Table payments"] || !missing["Function public.refund(order_id bigint)"] {
+		t.Errorf("Expected payments and public.refund to be reported missing, got %+v", result.MissingObjects)
+	}
+}
+
+func TestNewSchemaSpec_MatchesSchema(t *testing.T) {
+	schema := specTestSchema()
+
+	// The generated spec survives a round trip through YAML and matches the
+	// schema it was generated from
+	data, err := yaml.Marshal(NewSchemaSpec(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec SchemaSpec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spec.Tables["public.orders"].Columns["status"]; !ok {
+		t.Fatalf("spec is missing public.orders.status:\n%s", data)
+	}
+
+	result := ValidateSchemaAgainstBaseline(schema, &SchemaBaseline{Spec: &spec})
+	if result.HasDrift {
+		t.Errorf("Expected no drift against the generated spec, got:\n%s", FormatValidationResult(result))
+	}
+
+	schema.Tables[0].Columns[0].DataType = "bigint"
+	if result := ValidateSchemaAgainstBaseline(schema, &SchemaBaseline{Spec: &spec}); len(result.SpecViolations) != 1 {
+		t.Errorf("Expected the changed column type to be reported, got %+v", result.SpecViolations)
+	}
+}
+
+func TestConfig_LoadSchemaSpecs(t *testing.T) {
+	dir := t.TempDir()
+	spec := "tables:\n  public.orders:\n    columns:\n      id: {type: bigint, nullable: false}\nfunctions:\n  - public.order_total(order_id bigint)\n"
+	if err := os.WriteFile(filepath.Join(dir, "orders-spec.yaml"), []byte(spec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{DatabaseConnections: []DatabaseConnection{
+		{Name: "orders", SchemaBaseline: &SchemaBaseline{SpecFile: "orders-spec.yaml"}},
+		{Name: "billing"},
+	}}
+	if err := cfg.LoadSchemaSpecs(dir); err != nil {
+		t.Fatalf("LoadSchemaSpecs() error = %v", err)
+	}
+	loaded := cfg.DatabaseConnections[0].SchemaBaseline.Spec
+	if loaded == nil || loaded.Tables["public.orders"].Columns["id"].Type != "bigint" || len(loaded.Functions) != 1 {
+		t.Errorf("LoadSchemaSpecs() spec = %+v", loaded)
+	}
+
+	cfg.DatabaseConnections[0].SchemaBaseline.SpecFile = "missing.yaml"
+	cfg.DatabaseConnections[0].SchemaBaseline.Spec = nil
+	if err := cfg.LoadSchemaSpecs(dir); err == nil {
+		t.Error("LoadSchemaSpecs() should fail for a missing spec file")
+	}
+
+	cfg.DatabaseConnections[0].SchemaBaseline.Spec = loaded
+	if err := cfg.LoadSchemaSpecs(dir); err == nil {
+		t.Error("LoadSchemaSpecs() should reject spec together with spec_file")
+	}
+}
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,6 +13,9 @@ type SchemaValidationResult struct {
 	MissingObjects      []MissingObject
 	ForbiddenObjects    []ForbiddenObject
 	OwnershipViolations []OwnershipViolation
+	SettingViolations   []SettingViolation
+	StorageViolations   []StorageParamViolation
+	SpecViolations      []SpecViolation
 }
 
 // OwnershipViolation represents an object with incorrect ownership
@@ -23,6 +27,26 @@ type OwnershipViolation struct {
 	ViolationType  string // "wrong_owner", "forbidden_owner", "database_owner"
 }
 
+// SettingViolation represents a database-level setting override that does not
+// match the baseline
+type SettingViolation struct {
+	Name          string
+	Role          string // set for ALTER ROLE ... IN DATABASE overrides
+	Expected      string
+	Actual        string
+	ViolationType string // "missing", "wrong_value", "forbidden"
+}
+
+// StorageParamViolation represents a table storage parameter that does not
+// match the baseline
+type StorageParamViolation struct {
+	Table         string
+	Param         string
+	Expected      string
+	Actual        string
+	ViolationType string // "missing", "wrong_value", "unexpected"
+}
+
 // CountMismatch represents a mismatch in expected vs actual counts
 type CountMismatch struct {
 	ObjectType string
@@ -53,6 +77,9 @@ func ValidateSchemaAgainstBaseline(schema *DatabaseSchema, baseline *SchemaBasel
 		MissingObjects:      []MissingObject{},
 		ForbiddenObjects:    []ForbiddenObject{},
 		OwnershipViolations: []OwnershipViolation{},
+		SettingViolations:   validateSettings(schema.Settings, baseline),
+		StorageViolations:   []StorageParamViolation{},
+		SpecViolations:      []SpecViolation{},
 	}
 
 	// Check expected counts
@@ -480,15 +507,189 @@ func ValidateSchemaAgainstBaseline(schema *DatabaseSchema, baseline *SchemaBasel
 		}
 	}
 
+	validateStorageParams(schema.Tables, baseline, result)
+	if baseline.Spec != nil {
+		validateSpec(schema, baseline.Spec, result)
+	}
+
 	// Determine if there's drift
 	result.HasDrift = len(result.CountMismatches) > 0 ||
 		len(result.MissingObjects) > 0 ||
 		len(result.ForbiddenObjects) > 0 ||
-		len(result.OwnershipViolations) > 0
+		len(result.OwnershipViolations) > 0 ||
+		len(result.SettingViolations) > 0 ||
+		len(result.StorageViolations) > 0 ||
+		len(result.SpecViolations) > 0
 
 	return result
 }
 
+// validateSettings checks database-level setting overrides against the
+// required and forbidden settings. Setting names are case-insensitive.
+func validateSettings(settings []DatabaseSetting, baseline *SchemaBaseline) []SettingViolation {
+	violations := []SettingViolation{}
+
+	databaseWide := make(map[string]string)
+	for _, setting := range settings {
+		if setting.Role == "" {
+			databaseWide[strings.ToLower(setting.Name)] = setting.Value
+		}
+	}
+
+	required := make(map[string]bool)
+	names := make([]string, 0, len(baseline.RequiredSettings))
+	for name := range baseline.RequiredSettings {
+		names = append(names, name)
+		required[strings.ToLower(name)] = true
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expected := baseline.RequiredSettings[name]
+		actual, ok := databaseWide[strings.ToLower(name)]
+		switch {
+		case !ok:
+			violations = append(violations, SettingViolation{
+				Name:          name,
+				Expected:      expected,
+				Actual:        "(not set)",
+				ViolationType: "missing",
+			})
+		case actual != expected:
+			violations = append(violations, SettingViolation{
+				Name:          name,
+				Expected:      expected,
+				Actual:        actual,
+				ViolationType: "wrong_value",
+			})
+		}
+	}
+
+	forbidden := make(map[string]bool)
+	forbidAll := false
+	for _, name := range baseline.ForbiddenSettings {
+		if name == "*" {
+			forbidAll = true
+		}
+		forbidden[strings.ToLower(name)] = true
+	}
+
+	for _, setting := range settings {
+		name := strings.ToLower(setting.Name)
+		isRequired := setting.Role == "" && required[name]
+		if forbidden[name] || (forbidAll && !isRequired) {
+			violations = append(violations, SettingViolation{
+				Name:          setting.Name,
+				Role:          setting.Role,
+				Expected:      "(not set)",
+				Actual:        setting.Value,
+				ViolationType: "forbidden",
+			})
+		}
+	}
+
+	return violations
+}
+
+// validateStorageParams checks the storage parameters of every table listed
+// in the baseline. A listed table that does not exist is reported as missing.
+func validateStorageParams(tables []TableInfo, baseline *SchemaBaseline, result *SchemaValidationResult) {
+	tableMap := make(map[string]TableInfo)
+	for _, table := range tables {
+		tableMap[fmt.Sprintf("%s.%s", table.Schema, table.Name)] = table
+		if _, exists := tableMap[table.Name]; !exists {
+			tableMap[table.Name] = table
+		}
+	}
+
+	tableNames := make([]string, 0, len(baseline.TableStorageParams))
+	for name := range baseline.TableStorageParams {
+		tableNames = append(tableNames, name)
+	}
+	sort.Strings(tableNames)
+
+	for _, tableName := range tableNames {
+		table, exists := tableMap[tableName]
+		if !exists {
+			if !containsMissing(result.MissingObjects, "Table", tableName) {
+				result.MissingObjects = append(result.MissingObjects, MissingObject{
+					ObjectType: "Table",
+					Name:       tableName,
+				})
+			}
+			continue
+		}
+
+		expectedParams := baseline.TableStorageParams[tableName]
+		params := make([]string, 0, len(expectedParams))
+		for param := range expectedParams {
+			params = append(params, param)
+		}
+		sort.Strings(params)
+
+		for _, param := range params {
+			expected := expectedParams[param]
+			actual, ok := table.StorageParams[param]
+			switch {
+			case expected == "default":
+				if ok {
+					result.StorageViolations = append(result.StorageViolations, StorageParamViolation{
+						Table:         tableName,
+						Param:         param,
+						Expected:      "(default)",
+						Actual:        actual,
+						ViolationType: "unexpected",
+					})
+				}
+			case !ok:
+				result.StorageViolations = append(result.StorageViolations, StorageParamViolation{
+					Table:         tableName,
+					Param:         param,
+					Expected:      expected,
+					Actual:        "(default)",
+					ViolationType: "missing",
+				})
+			case actual != expected:
+				result.StorageViolations = append(result.StorageViolations, StorageParamViolation{
+					Table:         tableName,
+					Param:         param,
+					Expected:      expected,
+					Actual:        actual,
+					ViolationType: "wrong_value",
+				})
+			}
+		}
+
+		extra := make([]string, 0)
+		for param := range table.StorageParams {
+			if _, listed := expectedParams[param]; !listed {
+				extra = append(extra, param)
+			}
+		}
+		sort.Strings(extra)
+
+		for _, param := range extra {
+			result.StorageViolations = append(result.StorageViolations, StorageParamViolation{
+				Table:         tableName,
+				Param:         param,
+				Expected:      "(default)",
+				Actual:        table.StorageParams[param],
+				ViolationType: "unexpected",
+			})
+		}
+	}
+}
+
+// containsMissing reports whether an object is already listed as missing
+func containsMissing(missing []MissingObject, objectType, name string) bool {
+	for _, m := range missing {
+		if m.ObjectType == objectType && m.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // FormatValidationResult formats the validation result as a human-readable string
 func FormatValidationResult(result *SchemaValidationResult) string {
 	if !result.HasDrift {
@@ -527,6 +728,60 @@ func FormatValidationResult(result *SchemaValidationResult) string {
 		sb.WriteString("\n")
 	}
 
+	if len(result.SettingViolations) > 0 {
+		sb.WriteString("Database Setting Overrides:\n")
+		for _, violation := range result.SettingViolations {
+			name := violation.Name
+			if violation.Role != "" {
+				name = fmt.Sprintf("%s (role %s)", violation.Name, violation.Role)
+			}
+			switch violation.ViolationType {
+			case "missing":
+				sb.WriteString(fmt.Sprintf("  [MISSING] %s - Expected: %s\n", name, violation.Expected))
+			case "wrong_value":
+				sb.WriteString(fmt.Sprintf("  [WARNING] %s - Value: %s, Expected: %s\n", name, violation.Actual, violation.Expected))
+			case "forbidden":
+				sb.WriteString(fmt.Sprintf("  [ERROR] %s = %s (override not allowed)\n", name, violation.Actual))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(result.StorageViolations) > 0 {
+		sb.WriteString("Table Storage Parameters:\n")
+		for _, violation := range result.StorageViolations {
+			name := fmt.Sprintf("%s %s", violation.Table, violation.Param)
+			switch violation.ViolationType {
+			case "missing":
+				sb.WriteString(fmt.Sprintf("  [MISSING] %s - Expected: %s\n", name, violation.Expected))
+			case "wrong_value":
+				sb.WriteString(fmt.Sprintf("  [WARNING] %s - Value: %s, Expected: %s\n", name, violation.Actual, violation.Expected))
+			case "unexpected":
+				sb.WriteString(fmt.Sprintf("  [ERROR] %s = %s (not in baseline)\n", name, violation.Actual))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(result.SpecViolations) > 0 {
+		sb.WriteString("Schema Spec:\n")
+		for _, violation := range result.SpecViolations {
+			name := fmt.Sprintf("%s: %s", violation.ObjectType, violation.ObjectName)
+			if violation.Field != "" {
+				name = fmt.Sprintf("%s %s", name, violation.Field)
+			}
+			switch violation.ViolationType {
+			case "missing":
+				sb.WriteString(fmt.Sprintf("  [MISSING] %s - Expected: %s\n", name, violation.Expected))
+			case "wrong_value":
+				sb.WriteString(fmt.Sprintf("  [WARNING] %s - Value: %s, Expected: %s\n", name, violation.Actual, violation.Expected))
+			case "unexpected":
+				sb.WriteString(fmt.Sprintf("  [ERROR] %s = %s (not in spec)\n", name, violation.Actual))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
 	if len(result.OwnershipViolations) > 0 {
 		sb.WriteString("Ownership Violations:\n")
 		for _, violation := range result.OwnershipViolations {
@@ -538,3 +538,141 @@ func TestValidateSchemaAgainstBaseline_MultipleCountMismatches(t *testing.T) {
 		t.Fatalf("Expected 3 count mismatches, got %d", len(result.CountMismatches))
 	}
 }
+
+func TestValidateSchemaAgainstBaseline_Settings(t *testing.T) {
+	schema := &DatabaseSchema{
+		Settings: []DatabaseSetting{
+			{Name: "statement_timeout", Value: "0"},
+			{Name: "work_mem", Value: "256MB"},
+			{Role: "reporting", Name: "work_mem", Value: "1GB"},
+		},
+	}
+
+	baseline := &SchemaBaseline{
+		RequiredSettings: map[string]string{
+			"statement_timeout":                   "30s",
+			"idle_in_transaction_session_timeout": "60s",
+		},
+		ForbiddenSettings: []string{"work_mem"},
+	}
+
+	result := ValidateSchemaAgainstBaseline(schema, baseline)
+	if !result.HasDrift {
+		t.Fatal("Expected drift to be detected")
+	}
+
+	got := make(map[string]string)
+	for _, v := range result.SettingViolations {
+		got[v.Name+"/"+v.Role] = v.ViolationType
+	}
+	want := map[string]string{
+		"idle_in_transaction_session_timeout/": "missing",
+		"statement_timeout/":                   "wrong_value",
+		"work_mem/":                            "forbidden",
+		"work_mem/reporting":                   "forbidden",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+	for key, violation := range want {
+		if got[key] != violation {
+			t.Errorf("%s = %q, want %q", key, got[key], violation)
+		}
+	}
+}
+
+func TestValidateSchemaAgainstBaseline_ForbidAllSettings(t *testing.T) {
+	schema := &DatabaseSchema{
+		Settings: []DatabaseSetting{
+			{Name: "statement_timeout", Value: "30s"},
+			{Name: "search_path", Value: "app, public"},
+		},
+	}
+
+	baseline := &SchemaBaseline{
+		RequiredSettings:  map[string]string{"statement_timeout": "30s"},
+		ForbiddenSettings: []string{"*"},
+	}
+
+	result := ValidateSchemaAgainstBaseline(schema, baseline)
+	if len(result.SettingViolations) != 1 || result.SettingViolations[0].Name != "search_path" {
+		t.Errorf("Expected only search_path to be forbidden, got %+v", result.SettingViolations)
+	}
+}
+
+func TestParseSetting(t *testing.T) {
+	setting, ok := parseSetting("app", `search_path="$user", public`)
+	if !ok || setting.Name != "search_path" || setting.Value != `"$user", public` || setting.Role != "app" {
+		t.Errorf("parseSetting() = %+v, %v", setting, ok)
+	}
+	if _, ok := parseSetting("", "malformed"); ok {
+		t.Error("parseSetting() should reject entries without '='")
+	}
+}
+
+func TestValidateSchemaAgainstBaseline_TableStorageParams(t *testing.T) {
+	schema := &DatabaseSchema{
+		Tables: []TableInfo{
+			{
+				Schema: "public",
+				Name:   "orders",
+				StorageParams: map[string]string{
+					"fillfactor":                     "90",
+					"autovacuum_vacuum_scale_factor": "0.2",
+					"autovacuum_enabled":             "false",
+				},
+			},
+			{Schema: "public", Name: "events", StorageParams: map[string]string{"toast.autovacuum_enabled": "false"}},
+		},
+	}
+
+	baseline := &SchemaBaseline{
+		TableStorageParams: map[string]map[string]string{
+			"public.orders": {
+				"fillfactor":                      "90",
+				"autovacuum_vacuum_scale_factor":  "0.01",
+				"autovacuum_analyze_scale_factor": "0.005",
+			},
+			"events":   {"toast.autovacuum_enabled": "default"},
+			"payments": {"fillfactor": "80"},
+		},
+	}
+
+	result := ValidateSchemaAgainstBaseline(schema, baseline)
+	if !result.HasDrift {
+		t.Fatal("Expected drift to be detected")
+	}
+
+	got := make(map[string]string)
+	for _, v := range result.StorageViolations {
+		got[v.Table+"/"+v.Param] = v.ViolationType
+	}
+	want := map[string]string{
+		"public.orders/autovacuum_analyze_scale_factor": "missing",
+		"public.orders/autovacuum_vacuum_scale_factor":  "wrong_value",
+		"public.orders/autovacuum_enabled":              "unexpected",
+		"events/toast.autovacuum_enabled":               "unexpected",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("violations = %v, want %v", got, want)
+	}
+	for key, violation := range want {
+		if got[key] != violation {
+			t.Errorf("%s = %q, want %q", key, got[key], violation)
+		}
+	}
+
+	if len(result.MissingObjects) != 1 || result.MissingObjects[0].Name != "payments" {
+		t.Errorf("Expected payments to be reported missing, got %+v", result.MissingObjects)
+	}
+}
+
+func TestCompareSchemas_StorageParams(t *testing.T) {
+	old := &DatabaseSchema{Tables: []TableInfo{{Schema: "public", Name: "orders", StorageParams: map[string]string{"fillfactor": "90"}}}}
+	new := &DatabaseSchema{Tables: []TableInfo{{Schema: "public", Name: "orders", StorageParams: map[string]string{"fillfactor": "70"}}}}
+
+	diff := CompareSchemas(old, new)
+	if len(diff.ModifiedTables) != 1 {
+		t.Errorf("Expected orders to be modified, got %+v", diff.ModifiedTables)
+	}
+}
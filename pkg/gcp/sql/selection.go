@@ -0,0 +1,46 @@
+package sql
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// Scope returns the name restriction of the baseline
+func (b SQLBaseline) Scope() analyzer.NameScope {
+	return analyzer.NameScope{Names: b.InstanceNames, Pattern: b.NamePattern}
+}
+
+// Selects reports whether the baseline applies to an instance: it carries
+// every filter label and its name is in the baseline's scope
+func (b SQLBaseline) Selects(inst *DatabaseInstance) bool {
+	if len(b.FilterLabels) > 0 && !matchesLabels(inst, b.FilterLabels) {
+		return false
+	}
+	return b.Scope().Matches(inst.Name)
+}
+
+// SelectInstances returns the instances a baseline applies to. Baselines
+// scoped by name take precedence: an instance selected by one of them is not
+// analyzed by baselines that only filter by labels.
+func SelectInstances(instances []*DatabaseInstance, baseline SQLBaseline, baselines []SQLBaseline) []*DatabaseInstance {
+	selected := make([]*DatabaseInstance, 0)
+	for _, inst := range instances {
+		if baseline.Selects(inst) && (baseline.Scope().Scoped() || !claimedByScoped(inst, baselines)) {
+			selected = append(selected, inst)
+		}
+	}
+	return selected
+}
+
+// claimedByScoped reports whether a name-scoped baseline selects the instance
+func claimedByScoped(inst *DatabaseInstance, baselines []SQLBaseline) bool {
+	for _, b := range baselines {
+		if b.Scope().Scoped() && b.Selects(inst) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByLabels returns the instances carrying every label; no labels
+// select every instance
+func FilterByLabels(instances []*DatabaseInstance, labels map[string]string) []*DatabaseInstance {
+	return filterInstancesByLabels(instances, labels)
+}
@@ -0,0 +1,55 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectInstances(t *testing.T) {
+	instances := []*DatabaseInstance{
+		{Name: "prod-orders", Labels: map[string]string{"env": "prod"}},
+		{Name: "prod-billing", Labels: map[string]string{"env": "prod"}},
+		{Name: "legacy-db", Labels: map[string]string{"env": "prod"}},
+		{Name: "dev-orders", Labels: map[string]string{"env": "dev"}},
+	}
+	baselines := []SQLBaseline{
+		{Name: "prod", FilterLabels: map[string]string{"env": "prod"}},
+		{Name: "billing", InstanceNames: []string{"prod-billing"}},
+		{Name: "legacy", FilterLabels: map[string]string{"env": "prod"}, NamePattern: "^legacy-"},
+		{Name: "orders", NamePattern: "-orders$"},
+	}
+
+	tests := []struct {
+		baseline string
+		want     string
+	}{
+		// Instances claimed by a name-scoped baseline are left to it
+		{"prod", ""},
+		{"billing", "prod-billing"},
+		{"legacy", "legacy-db"},
+		{"orders", "prod-orders,dev-orders"},
+	}
+	for _, tt := range tests {
+		var baseline SQLBaseline
+		for _, b := range baselines {
+			if b.Name == tt.baseline {
+				baseline = b
+			}
+		}
+
+		var names []string
+		for _, inst := range SelectInstances(instances, baseline, baselines) {
+			names = append(names, inst.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("SelectInstances(%s) = %q, want %q", tt.baseline, got, tt.want)
+		}
+	}
+}
+
+func TestSQLBaselineValidateNamePattern(t *testing.T) {
+	b := SQLBaseline{Name: "prod", NamePattern: "^prod-(", Config: &DatabaseConfig{}}
+	if err := b.Validate(); err == nil || !strings.Contains(err.Error(), "name_pattern") {
+		t.Errorf("Validate() error = %v, want invalid name_pattern", err)
+	}
+}
@@ -3,7 +3,9 @@ package sql
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"os"
 	"os/exec"
 	"time"
 )
@@ -13,6 +15,8 @@ type SSHTunnelManager struct {
 	config      *SSHTunnelConfig
 	cmd         *exec.Cmd
 	isConnected bool
+	out         io.Writer // progress output (default: stdout)
+	keyDir      string    // holds the key pair read from Vault while connected
 }
 
 // getFreePort finds an available port on localhost
@@ -40,7 +44,7 @@ func NewSSHTunnelManager(config *SSHTunnelConfig) (*SSHTunnelManager, error) {
 	// Set defaults
 	if config.LocalPort == 0 {
 		// Automatically find a free port
-		port, err := getFreePort()
+		port, err := allocatePort()
 		if err != nil {
 			return nil, fmt.Errorf("failed to find free port: %w", err)
 		}
@@ -65,7 +69,7 @@ func (stm *SSHTunnelManager) Start(ctx context.Context) error {
 		return nil // Already connected
 	}
 
-	fmt.Printf("Establishing SSH tunnel via bastion host %s...\n", stm.config.BastionHost)
+	fmt.Fprintf(outputOrStdout(stm.out), "Establishing SSH tunnel via bastion host %s...\n", stm.config.BastionHost)
 
 	// Build gcloud compute ssh command
 	args := []string{
@@ -77,6 +81,15 @@ func (stm *SSHTunnelManager) Start(ctx context.Context) error {
 		"--ssh-key-expire-after", stm.config.SSHKeyExpiry,
 	}
 
+	// Use the key pair from Vault instead of the gcloud key in ~/.ssh
+	if stm.config.Vault != nil {
+		keyFile, err := stm.writeVaultKey(ctx)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--ssh-key-file", keyFile)
+	}
+
 	// Add IAP tunnel flag if enabled
 	if stm.config.UseIAP {
 		args = append(args, "--tunnel-through-iap")
@@ -95,19 +108,21 @@ func (stm *SSHTunnelManager) Start(ctx context.Context) error {
 
 	// Start SSH tunnel
 	if err := stm.cmd.Start(); err != nil {
+		stm.removeKey()
 		return fmt.Errorf("failed to start SSH tunnel: %w", err)
 	}
 
-	fmt.Printf("SSH tunnel started (PID: %d), waiting for it to be ready...\n", stm.cmd.Process.Pid)
+	fmt.Fprintf(outputOrStdout(stm.out), "SSH tunnel started (PID: %d), waiting for it to be ready...\n", stm.cmd.Process.Pid)
 
 	// Wait for tunnel to be ready
 	if err := stm.waitForTunnel(30 * time.Second); err != nil {
 		stm.Stop()
+		stm.removeKey()
 		return fmt.Errorf("SSH tunnel failed to become ready: %w", err)
 	}
 
 	stm.isConnected = true
-	fmt.Printf("SSH tunnel established: localhost:%d -> %s:%d\n",
+	fmt.Fprintf(outputOrStdout(stm.out), "SSH tunnel established: localhost:%d -> %s:%d\n",
 		stm.config.LocalPort,
 		stm.config.PrivateIP,
 		stm.config.RemotePort,
@@ -122,7 +137,7 @@ func (stm *SSHTunnelManager) Stop() error {
 		return nil
 	}
 
-	fmt.Println("Closing SSH tunnel...")
+	fmt.Fprintln(outputOrStdout(stm.out), "Closing SSH tunnel...")
 
 	if stm.cmd != nil && stm.cmd.Process != nil {
 		if err := stm.cmd.Process.Kill(); err != nil {
@@ -132,10 +147,35 @@ func (stm *SSHTunnelManager) Stop() error {
 		_ = stm.cmd.Wait()
 	}
 
+	stm.removeKey()
 	stm.isConnected = false
 	return nil
 }
 
+// writeVaultKey writes the key pair from Vault to a private temporary
+// directory, removed again when the tunnel stops
+func (stm *SSHTunnelManager) writeVaultKey(ctx context.Context) (string, error) {
+	dir, err := os.MkdirTemp("", "drift-ssh-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create SSH key directory: %w", err)
+	}
+	stm.keyDir = dir
+	keyFile, err := stm.config.Vault.WriteFiles(ctx, dir)
+	if err != nil {
+		stm.removeKey()
+		return "", fmt.Errorf("failed to read SSH key from Vault: %w", err)
+	}
+	return keyFile, nil
+}
+
+// removeKey deletes the key pair read from Vault, if any
+func (stm *SSHTunnelManager) removeKey() {
+	if stm.keyDir != "" {
+		_ = os.RemoveAll(stm.keyDir)
+		stm.keyDir = ""
+	}
+}
+
 // IsConnected returns whether the tunnel is currently active
 func (stm *SSHTunnelManager) IsConnected() bool {
 	if !stm.isConnected {
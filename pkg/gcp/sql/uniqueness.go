@@ -0,0 +1,26 @@
+package sql
+
+import "github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+
+// CheckUniqueness flags every instance of a group that holds more instances
+// than a unique rule allows, e.g. two vault instances labelled env=prod in
+// different projects
+func CheckUniqueness(r *DriftReport, rules []analyzer.UniquenessRule) {
+	resources := make([]analyzer.UniqueResource, len(r.Instances))
+	for i, inst := range r.Instances {
+		resources[i] = analyzer.UniqueResource{Project: inst.Project, Name: inst.Name, Labels: inst.Labels}
+	}
+
+	for _, rule := range rules {
+		for _, duplicate := range rule.Duplicates(resources) {
+			actual := duplicate.Actual(resources)
+			for _, i := range duplicate.Members {
+				inst := r.Instances[i]
+				if len(inst.Drifts) == 0 {
+					r.DriftedInstances++
+				}
+				inst.Drifts = checkUnique.Append(inst.Drifts, rule.Expected(duplicate.Group), actual)
+			}
+		}
+	}
+}
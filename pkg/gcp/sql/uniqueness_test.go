@@ -0,0 +1,30 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+)
+
+func TestCheckUniqueness(t *testing.T) {
+	vault := map[string]string{"database-role": "vault", "env": "prod"}
+	r := &DriftReport{Instances: []*InstanceDrift{
+		{Project: "p1", Name: "vault-a", Labels: vault},
+		{Project: "p2", Name: "vault-b", Labels: vault, Drifts: []Drift{{Field: "tier"}}},
+		{Project: "p3", Name: "vault-c", Labels: map[string]string{"database-role": "vault", "env": "dev"}},
+	}, DriftedInstances: 1}
+	CheckUniqueness(r, []analyzer.UniquenessRule{{By: []string{"label:env"}}})
+
+	for i, want := range []int{1, 2, 0} {
+		if got := len(r.Instances[i].Drifts); got != want {
+			t.Errorf("%s has %d drifts, want %d", r.Instances[i].Name, got, want)
+		}
+	}
+	d := r.Instances[0].Drifts[0]
+	if d.Field != "unique" || d.Expected != "at most 1 with env=prod" || d.Actual != "2: p1/vault-a, p2/vault-b" || d.Severity != "high" {
+		t.Errorf("drift = %+v", d)
+	}
+	if r.DriftedInstances != 2 {
+		t.Errorf("DriftedInstances = %d, want 2", r.DriftedInstances)
+	}
+}
@@ -0,0 +1,97 @@
+// Package gcs writes generated files to Cloud Storage locations given as
+// gs://bucket/path URIs.
+package gcs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"google.golang.org/api/storage/v1"
+)
+
+// TimeLayout formats the timestamps in object names so they sort chronologically
+const TimeLayout = "20060102T150405Z"
+
+// IsURI reports whether a location is a gs:// URI
+func IsURI(location string) bool {
+	return strings.HasPrefix(location, "gs://")
+}
+
+// Split returns the bucket and the object path of a gs://bucket/path URI.
+// The path has no leading or trailing slashes and may be empty.
+func Split(uri string) (bucket, objectPath string, err error) {
+	if !IsURI(uri) {
+		return "", "", fmt.Errorf("invalid Cloud Storage location %q: expected gs://bucket/path", uri)
+	}
+	bucket, objectPath, _ = strings.Cut(strings.TrimPrefix(uri, "gs://"), "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("invalid Cloud Storage location %q: expected gs://bucket/path", uri)
+	}
+	return bucket, strings.Trim(objectPath, "/"), nil
+}
+
+// Join appends a file name to a gs:// location
+func Join(location, name string) string {
+	return strings.TrimSuffix(location, "/") + "/" + name
+}
+
+// TimestampedName returns a file name with a UTC timestamp between the base
+// name and the extension, e.g. sql-production-20261017T060000Z.json
+func TimestampedName(base, ext string, at time.Time) string {
+	return base + "-" + at.UTC().Format(TimeLayout) + ext
+}
+
+// Client uploads objects to Cloud Storage
+type Client struct {
+	service *storage.Service
+}
+
+// NewClient creates a client using Application Default Credentials
+func NewClient(ctx context.Context) (*Client, error) {
+	service, err := storage.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	return &Client{service: service}, nil
+}
+
+// Upload writes data to the object at a gs://bucket/object URI, replacing
+// any existing object
+func (c *Client) Upload(ctx context.Context, uri string, data []byte) error {
+	bucket, name, err := Split(uri)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return fmt.Errorf("invalid Cloud Storage object %q: no object name", uri)
+	}
+
+	object := &storage.Object{Name: name, ContentType: contentType(name)}
+	if _, err := c.service.Objects.Insert(bucket, object).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", uri, err)
+	}
+	return nil
+}
+
+// contentType guesses the content type of an object from its extension
+func contentType(name string) string {
+	switch ext := path.Ext(name); ext {
+	case ".yaml", ".yml":
+		return "application/yaml"
+	case ".ndjson":
+		return "application/x-ndjson"
+	case ".sql", ".txt", ".dot", ".mmd", "":
+		return "text/plain; charset=utf-8"
+	default:
+		if t := mime.TypeByExtension(ext); t != "" {
+			return t
+		}
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,65 @@
+package gcs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		uri        string
+		bucket     string
+		objectPath string
+		wantErr    bool
+	}{
+		{"gs://drift-reports", "drift-reports", "", false},
+		{"gs://drift-reports/ci/", "drift-reports", "ci", false},
+		{"gs://drift-reports/ci/sql.json", "drift-reports", "ci/sql.json", false},
+		{"gs://", "", "", true},
+		{"reports/ci", "", "", true},
+	}
+
+	for _, tt := range tests {
+		bucket, objectPath, err := Split(tt.uri)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Split(%q) error = %v, wantErr %v", tt.uri, err, tt.wantErr)
+			continue
+		}
+		if bucket != tt.bucket || objectPath != tt.objectPath {
+			t.Errorf("Split(%q) = %q, %q, want %q, %q", tt.uri, bucket, objectPath, tt.bucket, tt.objectPath)
+		}
+	}
+}
+
+func TestTimestampedName(t *testing.T) {
+	at := time.Date(2026, 10, 17, 6, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	tests := []struct {
+		base, ext, want string
+	}{
+		{"sql-production", ".json", "sql-production-20261017T040000Z.json"},
+		{"gke-prod.v2", ".plan.yaml", "gke-prod.v2-20261017T040000Z.plan.yaml"},
+		{"no-extension", "", "no-extension-20261017T040000Z"},
+	}
+	for _, tt := range tests {
+		if got := TimestampedName(tt.base, tt.ext, at); got != tt.want {
+			t.Errorf("TimestampedName(%q, %q) = %q, want %q", tt.base, tt.ext, got, tt.want)
+		}
+	}
+	if got := Join("gs://bucket/ci/", "a.json"); got != "gs://bucket/ci/a.json" {
+		t.Errorf("Join() = %q", got)
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := map[string]string{
+		"a.json":   "application/json",
+		"a.yaml":   "application/yaml",
+		"a.ndjson": "application/x-ndjson",
+		"a.sql":    "text/plain; charset=utf-8",
+	}
+	for name, want := range tests {
+		if got := contentType(name); got != want {
+			t.Errorf("contentType(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// EscalationRule raises the severity of a finding left unresolved for a
+// number of days
+type EscalationRule struct {
+	// Severity is the severity the finding was reported with
+	Severity string `yaml:"severity"`
+	// AfterDays is how long the finding must have been reported without a
+	// break before it escalates
+	AfterDays int `yaml:"after_days"`
+	// To is the severity it escalates to
+	To string `yaml:"to"`
+}
+
+// EscalationPolicy escalates long-ignored drift using the reports recorded in
+// history, e.g. medium drift unresolved for 14 days becomes high and for 30
+// days critical
+type EscalationPolicy struct {
+	Rules []EscalationRule `yaml:"rules"`
+}
+
+// Validate checks the severities and age of every rule
+func (p *EscalationPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for i, rule := range p.Rules {
+		if report.SeverityRank(rule.Severity) == 0 {
+			return fmt.Errorf("escalation rule %d: unknown severity %q", i+1, rule.Severity)
+		}
+		if report.SeverityRank(rule.To) == 0 {
+			return fmt.Errorf("escalation rule %d: unknown severity %q", i+1, rule.To)
+		}
+		if report.SeverityRank(rule.To) <= report.SeverityRank(rule.Severity) {
+			return fmt.Errorf("escalation rule %d: %s must escalate to a higher severity, not %s", i+1, rule.Severity, rule.To)
+		}
+		if rule.AfterDays <= 0 {
+			return fmt.Errorf("escalation rule %d: after_days must be positive", i+1)
+		}
+	}
+	return nil
+}
+
+// Escalate raises the severity of the current report's drifts per the rules.
+// A finding's age counts from the first of the reports in past, ordered
+// oldest first, that it appears in without a break up to the current report.
+// The highest severity among the rules that apply wins; drifts are changed
+// in place and annotated with the escalation. It returns how many drifts
+// were escalated.
+func (p *EscalationPolicy) Escalate(past []*report.Report, current *report.Report) int {
+	if p == nil || len(p.Rules) == 0 {
+		return 0
+	}
+
+	since := make(map[string]time.Time)
+	for _, r := range past {
+		next := make(map[string]time.Time)
+		for key := range findings(r) {
+			if first, ok := since[key]; ok {
+				next[key] = first
+			} else {
+				next[key] = r.Timestamp
+			}
+		}
+		since = next
+	}
+
+	escalated := 0
+	for _, res := range current.Resources {
+		for i := range res.Drifts {
+			d := &res.Drifts[i]
+			f := Finding{ResourceType: res.Type, Project: res.Project, Location: res.Location, Resource: res.Name, Field: d.Field}
+			first, ok := since[f.key()]
+			if !ok {
+				continue
+			}
+			days := int(current.Timestamp.Sub(first).Hours() / 24)
+
+			to := d.Severity
+			for _, rule := range p.Rules {
+				if rule.Severity == d.Severity && days >= rule.AfterDays && report.SeverityRank(rule.To) > report.SeverityRank(to) {
+					to = rule.To
+				}
+			}
+			if to == d.Severity {
+				continue
+			}
+			d.Escalation = fmt.Sprintf("from %s, unresolved since %s (%d days)", strings.ToUpper(d.Severity), first.Format("2006-01-02"), days)
+			d.Severity = to
+			escalated++
+		}
+	}
+	return escalated
+}
@@ -0,0 +1,71 @@
+package history
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// tierReport reports medium tier drift on the given databases
+func tierReport(at time.Time, names ...string) *report.Report {
+	r := &report.Report{Timestamp: at}
+	for _, name := range names {
+		r.Resources = append(r.Resources, report.Resource{
+			Type: "Cloud SQL", Project: "prod", Name: name,
+			Drifts: []report.Drift{{Field: "tier", Severity: "medium"}},
+		})
+	}
+	return r
+}
+
+func TestEscalate(t *testing.T) {
+	policy := &EscalationPolicy{Rules: []EscalationRule{
+		{Severity: "medium", AfterDays: 14, To: "high"},
+		{Severity: "medium", AfterDays: 30, To: "critical"},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	past := []*report.Report{
+		tierReport(start, "db-1", "db-2"),
+		tierReport(start.AddDate(0, 0, 10), "db-1", "db-2", "db-3"),
+		// db-2 was resolved, so its age starts over when it reappears
+		tierReport(start.AddDate(0, 0, 20), "db-1", "db-3"),
+		tierReport(start.AddDate(0, 0, 30), "db-1", "db-2", "db-3"),
+	}
+	current := tierReport(start.AddDate(0, 0, 35), "db-1", "db-2", "db-3", "db-4")
+
+	if n := policy.Escalate(past, current); n != 2 {
+		t.Errorf("Escalate() = %d, want 2", n)
+	}
+
+	want := map[string]string{"db-1": "critical", "db-2": "medium", "db-3": "high", "db-4": "medium"}
+	for _, res := range current.Resources {
+		d := res.Drifts[0]
+		if d.Severity != want[res.Name] {
+			t.Errorf("%s severity = %s, want %s", res.Name, d.Severity, want[res.Name])
+		}
+		if escalated := d.Severity != "medium"; escalated != (d.Escalation != "") {
+			t.Errorf("%s escalation = %q", res.Name, d.Escalation)
+		}
+	}
+	if got := current.Resources[0].Drifts[0].Escalation; !strings.Contains(got, "from MEDIUM, unresolved since 2026-03-01 (35 days)") {
+		t.Errorf("escalation = %q", got)
+	}
+}
+
+func TestEscalationPolicyValidate(t *testing.T) {
+	for _, rule := range []EscalationRule{
+		{Severity: "urgent", AfterDays: 14, To: "high"},
+		{Severity: "high", AfterDays: 14, To: "medium"},
+		{Severity: "medium", AfterDays: 0, To: "high"},
+	} {
+		if err := (&EscalationPolicy{Rules: []EscalationRule{rule}}).Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", rule)
+		}
+	}
+}
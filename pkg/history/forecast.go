@@ -0,0 +1,207 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Forecast defaults
+const (
+	defaultHorizonDays = 30
+	defaultMinSamples  = 3
+)
+
+// ForecastPolicy projects the growth of resource metrics recorded in history
+// and flags resources expected to reach a limit within the horizon
+type ForecastPolicy struct {
+	// HorizonDays is how far ahead to look (default 30)
+	HorizonDays int `yaml:"horizon_days,omitempty"`
+	// MinSamples is the fewest recorded runs a forecast is made from (default 3)
+	MinSamples int `yaml:"min_samples,omitempty"`
+	// Limits caps each metric, e.g. disk_gb: 500
+	Limits map[string]float64 `yaml:"limits"`
+}
+
+// Validate checks the horizon, sample count and limits
+func (p *ForecastPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.HorizonDays < 0 {
+		return fmt.Errorf("forecast.horizon_days must not be negative")
+	}
+	if p.MinSamples < 0 || p.MinSamples == 1 {
+		return fmt.Errorf("forecast.min_samples must be at least 2")
+	}
+	for metric, limit := range p.Limits {
+		if limit <= 0 {
+			return fmt.Errorf("forecast limit for %s must be positive", metric)
+		}
+	}
+	return nil
+}
+
+// horizonDays returns the forecast horizon in days
+func (p *ForecastPolicy) horizonDays() int {
+	if p.HorizonDays == 0 {
+		return defaultHorizonDays
+	}
+	return p.HorizonDays
+}
+
+// minSamples returns the fewest samples a forecast is made from
+func (p *ForecastPolicy) minSamples() int {
+	if p.MinSamples == 0 {
+		return defaultMinSamples
+	}
+	return p.MinSamples
+}
+
+// Projection is a resource whose metric is expected to reach its limit
+type Projection struct {
+	ResourceType string  `json:"resource_type"`
+	Project      string  `json:"project,omitempty"`
+	Location     string  `json:"location,omitempty"`
+	Resource     string  `json:"resource"`
+	Metric       string  `json:"metric"`
+	Current      float64 `json:"current"`
+	Limit        float64 `json:"limit"`
+	// GrowthPerDay is the slope of a least-squares fit over the samples
+	GrowthPerDay float64 `json:"growth_per_day"`
+	// ReachesAt is when the fit crosses the limit; the last sample's time
+	// when the limit is already reached
+	ReachesAt time.Time `json:"reaches_at"`
+	// HorizonDays is the horizon the projection was made for
+	HorizonDays int `json:"horizon_days"`
+}
+
+// Expected describes the limit and horizon, as shown in a finding
+func (p Projection) Expected() string {
+	return fmt.Sprintf("< %s for the next %d days", formatAmount(p.Limit), p.HorizonDays)
+}
+
+// Actual describes the current value, growth and projected date
+func (p Projection) Actual() string {
+	if p.Current >= p.Limit {
+		return fmt.Sprintf("%s, limit reached", formatAmount(p.Current))
+	}
+	return fmt.Sprintf("%s, +%s/day, reaches %s around %s",
+		formatAmount(p.Current), formatAmount(p.GrowthPerDay), formatAmount(p.Limit), p.ReachesAt.Format("2006-01-02"))
+}
+
+// formatAmount renders a metric value without trailing zeros
+func formatAmount(v float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+// sample is one recorded value of a metric
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+// series is the recorded values of one metric of one resource
+type series struct {
+	projection Projection
+	samples    []sample
+}
+
+// Forecast fits a line through every limited metric of every resource in
+// reports, ordered oldest first, and returns the projections that reach their
+// limit within the horizon after the latest report, sorted by date
+func (p *ForecastPolicy) Forecast(reports []*report.Report) []Projection {
+	if p == nil || len(p.Limits) == 0 || len(reports) == 0 {
+		return nil
+	}
+
+	var order []string
+	bySeries := make(map[string]*series)
+	for _, r := range reports {
+		for _, res := range r.Resources {
+			for metric, value := range res.Metrics {
+				limit, ok := p.Limits[metric]
+				if !ok {
+					continue
+				}
+				key := strings.Join([]string{res.Type, res.Project, res.Location, res.Name, metric}, "\x00")
+				s, found := bySeries[key]
+				if !found {
+					s = &series{projection: Projection{
+						ResourceType: res.Type,
+						Project:      res.Project,
+						Location:     res.Location,
+						Resource:     res.Name,
+						Metric:       metric,
+						Limit:        limit,
+						HorizonDays:  p.horizonDays(),
+					}}
+					bySeries[key] = s
+					order = append(order, key)
+				}
+				s.samples = append(s.samples, sample{at: r.Timestamp, value: value})
+			}
+		}
+	}
+
+	latest := reports[len(reports)-1].Timestamp
+	deadline := latest.AddDate(0, 0, p.horizonDays())
+	var projections []Projection
+	for _, key := range order {
+		s := bySeries[key]
+		last := s.samples[len(s.samples)-1]
+		// Resources gone from the latest report are not forecast
+		if !last.at.Equal(latest) {
+			continue
+		}
+		projection := s.projection
+		projection.Current = last.value
+		if last.value >= projection.Limit {
+			projection.ReachesAt = last.at
+			projections = append(projections, projection)
+			continue
+		}
+		if len(s.samples) < p.minSamples() {
+			continue
+		}
+		slope, ok := growthPerDay(s.samples)
+		if !ok || slope <= 0 {
+			continue
+		}
+		days := (projection.Limit - last.value) / slope
+		projection.GrowthPerDay = slope
+		projection.ReachesAt = last.at.Add(time.Duration(days * float64(24*time.Hour)))
+		if projection.ReachesAt.After(deadline) {
+			continue
+		}
+		projections = append(projections, projection)
+	}
+
+	sort.SliceStable(projections, func(i, j int) bool {
+		return projections[i].ReachesAt.Before(projections[j].ReachesAt)
+	})
+	return projections
+}
+
+// growthPerDay returns the slope of the least-squares line through the
+// samples; it fails when all samples were taken at the same time
+func growthPerDay(samples []sample) (float64, bool) {
+	origin := samples[0].at
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.at.Sub(origin).Hours() / 24
+		sumX += x
+		sumY += s.value
+		sumXY += x * s.value
+		sumXX += x * x
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denominator, true
+}
@@ -0,0 +1,94 @@
+package history
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// diskReports records disk_gb for three instances on four days: db-1 grows
+// 10 GB a day, db-2 grows 1 GB a day and db-3 is already at its limit
+func diskReports() []*report.Report {
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	var reports []*report.Report
+	for day := 0; day < 4; day++ {
+		reports = append(reports, &report.Report{
+			Timestamp: start.AddDate(0, 0, day),
+			Resources: []report.Resource{
+				{Type: "Cloud SQL", Project: "prod", Name: "db-1", Metrics: map[string]float64{"disk_gb": 100 + 10*float64(day)}},
+				{Type: "Cloud SQL", Project: "prod", Name: "db-2", Metrics: map[string]float64{"disk_gb": 100 + float64(day)}},
+				{Type: "Cloud SQL", Project: "prod", Name: "db-3", Metrics: map[string]float64{"disk_gb": 600}},
+			},
+		})
+	}
+	return reports
+}
+
+func TestForecast(t *testing.T) {
+	policy := &ForecastPolicy{HorizonDays: 60, Limits: map[string]float64{"disk_gb": 500}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	projections := policy.Forecast(diskReports())
+	if len(projections) != 2 {
+		t.Fatalf("got %d projections, want 2: %+v", len(projections), projections)
+	}
+
+	// Sorted by date: db-3 has reached its limit on the latest run
+	if p := projections[0]; p.Resource != "db-3" || p.Actual() != "600, limit reached" {
+		t.Errorf("projections[0] = %s %s", p.Resource, p.Actual())
+	}
+	p := projections[1]
+	if p.Resource != "db-1" || p.Current != 130 || p.GrowthPerDay != 10 {
+		t.Errorf("projections[1] = %+v, want db-1 at 130 growing 10/day", p)
+	}
+	// (500 - 130) / 10 = 37 days after the latest run on 2026-03-04
+	if got := p.ReachesAt.Format("2006-01-02"); got != "2026-04-10" {
+		t.Errorf("ReachesAt = %s, want 2026-04-10", got)
+	}
+	if p.Expected() != "< 500 for the next 60 days" || p.Actual() != "130, +10/day, reaches 500 around 2026-04-10" {
+		t.Errorf("finding = %q / %q", p.Expected(), p.Actual())
+	}
+}
+
+func TestForecastSkips(t *testing.T) {
+	reports := diskReports()
+
+	// db-1 reaches the limit after the default 30-day horizon
+	policy := &ForecastPolicy{Limits: map[string]float64{"disk_gb": 500}}
+	for _, p := range policy.Forecast(reports) {
+		if p.Resource == "db-1" {
+			t.Errorf("db-1 projected at %s, beyond the horizon", p.ReachesAt)
+		}
+	}
+
+	// Too few samples to fit a line
+	policy = &ForecastPolicy{MinSamples: 5, HorizonDays: 60, Limits: map[string]float64{"disk_gb": 500}}
+	for _, p := range policy.Forecast(reports) {
+		if p.Resource == "db-1" {
+			t.Error("db-1 projected from fewer than min_samples runs")
+		}
+	}
+
+	// Resources missing from the latest run are not projected
+	latest := reports[len(reports)-1]
+	latest.Resources = latest.Resources[1:]
+	policy = &ForecastPolicy{HorizonDays: 60, Limits: map[string]float64{"disk_gb": 500}}
+	for _, p := range policy.Forecast(reports) {
+		if p.Resource == "db-1" {
+			t.Error("db-1 projected although it is gone")
+		}
+	}
+
+	if (*ForecastPolicy)(nil).Forecast(reports) != nil {
+		t.Error("nil policy should not project")
+	}
+	if err := (&ForecastPolicy{MinSamples: 1}).Validate(); err == nil {
+		t.Error("Validate() with min_samples 1 = nil, want error")
+	}
+	if err := (&ForecastPolicy{Limits: map[string]float64{"disk_gb": 0}}).Validate(); err == nil {
+		t.Error("Validate() with a zero limit = nil, want error")
+	}
+}
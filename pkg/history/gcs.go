@@ -0,0 +1,175 @@
+package history
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/credentials"
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"google.golang.org/api/storage/v1"
+)
+
+// gcsStore keeps reports and suppressions as objects in a GCS bucket
+type gcsStore struct {
+	service *storage.Service
+	bucket  string
+	prefix  string
+}
+
+// newGCSStore opens the store at a gs://bucket/prefix location
+func newGCSStore(ctx context.Context, location string) (*gcsStore, error) {
+	bucket, prefix, err := gcs.Split(location)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := storage.NewService(ctx, credentials.ClientOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	return &gcsStore{service: service, bucket: bucket, prefix: prefix}, nil
+}
+
+// object returns the object name of a path relative to the store root
+func (g *gcsStore) object(name string) string {
+	return path.Join(g.prefix, name)
+}
+
+// SaveReport uploads a report to gs://<bucket>/<prefix>/reports/<name>/<timestamp>.json
+func (g *gcsStore) SaveReport(ctx context.Context, name string, r *report.Report) (string, error) {
+	data, err := encodeReport(r)
+	if err != nil {
+		return "", err
+	}
+
+	object := &storage.Object{Name: g.object(reportObjectName(name, r)), ContentType: "application/json"}
+	location := "gs://" + g.bucket + "/" + object.Name
+	if _, err := g.service.Objects.Insert(g.bucket, object).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", location, err)
+	}
+	return location, nil
+}
+
+// ReportNames lists the names reports were saved under
+func (g *gcsStore) ReportNames(ctx context.Context) ([]string, error) {
+	root := g.object(reportsDir) + "/"
+	var names []string
+	err := g.service.Objects.List(g.bucket).Prefix(root).Delimiter("/").Pages(ctx, func(objects *storage.Objects) error {
+		for _, prefix := range objects.Prefixes {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(prefix, root), "/"))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports in gs://%s/%s: %w", g.bucket, root, err)
+	}
+	return names, nil
+}
+
+// LoadReports downloads the reports saved under a name, oldest first
+func (g *gcsStore) LoadReports(ctx context.Context, name string) ([]*report.Report, error) {
+	root := g.object(reportsDir+"/"+name) + "/"
+	var objectNames []string
+	err := g.service.Objects.List(g.bucket).Prefix(root).Pages(ctx, func(objects *storage.Objects) error {
+		for _, object := range objects.Items {
+			if strings.HasSuffix(object.Name, ".json") {
+				objectNames = append(objectNames, object.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports in gs://%s/%s: %w", g.bucket, root, err)
+	}
+
+	var reports []*report.Report
+	for _, objectName := range objectNames {
+		location := "gs://" + g.bucket + "/" + objectName
+		data, err := g.download(ctx, objectName)
+		if err != nil {
+			return nil, err
+		}
+		r, err := decodeReport(location, data)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	sortReports(reports)
+	return reports, nil
+}
+
+// AppendSuppression uploads a suppression to
+// gs://<bucket>/<prefix>/suppressions/<created>-<key hash>.json; objects are
+// never rewritten, so concurrent acks do not overwrite each other
+func (g *gcsStore) AppendSuppression(ctx context.Context, sup Suppression) error {
+	data, err := json.Marshal(sup)
+	if err != nil {
+		return fmt.Errorf("failed to encode suppression: %w", err)
+	}
+
+	sum := sha256.Sum256([]byte(sup.Key))
+	name := suppressionsDir + "/" + sup.Created.UTC().Format(gcs.TimeLayout) + "-" + hex.EncodeToString(sum[:8]) + ".json"
+	object := &storage.Object{Name: g.object(name), ContentType: "application/json"}
+	if _, err := g.service.Objects.Insert(g.bucket, object).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to upload gs://%s/%s: %w", g.bucket, object.Name, err)
+	}
+	return nil
+}
+
+// ActiveSuppressions downloads the recorded suppressions and returns the
+// latest per key that has not expired at now
+func (g *gcsStore) ActiveSuppressions(ctx context.Context, now time.Time) (map[string]Suppression, error) {
+	root := g.object(suppressionsDir) + "/"
+	var objectNames []string
+	err := g.service.Objects.List(g.bucket).Prefix(root).Pages(ctx, func(objects *storage.Objects) error {
+		for _, object := range objects.Items {
+			if strings.HasSuffix(object.Name, ".json") {
+				objectNames = append(objectNames, object.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list suppressions in gs://%s/%s: %w", g.bucket, root, err)
+	}
+
+	var sups []Suppression
+	for _, objectName := range objectNames {
+		data, err := g.download(ctx, objectName)
+		if err != nil {
+			return nil, err
+		}
+		var sup Suppression
+		if err := json.Unmarshal(data, &sup); err != nil {
+			return nil, fmt.Errorf("failed to read gs://%s/%s: %w", g.bucket, objectName, err)
+		}
+		sups = append(sups, sup)
+	}
+	sortSuppressions(sups)
+	return activeSuppressions(sups, now), nil
+}
+
+// download reads an object of the bucket
+func (g *gcsStore) download(ctx context.Context, objectName string) ([]byte, error) {
+	location := "gs://" + g.bucket + "/" + objectName
+	resp, err := g.service.Objects.Get(g.bucket, objectName).Context(ctx).Download()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", location, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", location, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,123 @@
+// Package history persists records of past runs, such as the remediation
+// change log and acknowledged findings, under a local directory. Drift
+// reports can also be kept in a GCS bucket for trend analysis.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// changesFile is the append-only remediation change log
+const changesFile = "changes.ndjson"
+
+// Change statuses
+const (
+	StatusApplied = "applied"
+	StatusDryRun  = "dry_run"
+	StatusFailed  = "failed"
+)
+
+// Change records one remediation attempt on a drifted field
+type Change struct {
+	Time         time.Time `json:"time"`
+	Actor        string    `json:"actor,omitempty"`
+	ApprovedBy   string    `json:"approved_by,omitempty"`
+	CheckID      string    `json:"check_id"`
+	ResourceType string    `json:"resource_type"`
+	Project      string    `json:"project,omitempty"`
+	Resource     string    `json:"resource"`
+	Field        string    `json:"field"`
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Store reads and writes history files in a directory
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store rooted at dir; the directory is created on first write
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the store directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// AppendChanges appends changes to the change log, one JSON object per line
+func (s *Store) AppendChanges(changes []Change) error {
+	records := make([]interface{}, len(changes))
+	for i := range changes {
+		records[i] = changes[i]
+	}
+	return s.appendRecords(changesFile, records)
+}
+
+// Changes reads the change log in the order it was written
+func (s *Store) Changes() ([]Change, error) {
+	var changes []Change
+	err := s.readRecords(changesFile, func(dec *json.Decoder) error {
+		var change Change
+		if err := dec.Decode(&change); err != nil {
+			return err
+		}
+		changes = append(changes, change)
+		return nil
+	})
+	return changes, err
+}
+
+// appendRecords appends records to an NDJSON file in the store
+func (s *Store) appendRecords(name string, records []interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	path := filepath.Join(s.dir, name)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// readRecords calls decode for each record of an NDJSON file in the store; a
+// missing file has no records
+func (s *Store) readRecords(name string, decode func(*json.Decoder) error) error {
+	path := filepath.Join(s.dir, name)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		if err := decode(dec); err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+	return nil
+}
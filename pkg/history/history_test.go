@@ -0,0 +1,99 @@
+package history
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestAppendChanges(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	if changes, err := store.Changes(); err != nil || len(changes) != 0 {
+		t.Fatalf("Changes() on empty store = %v, %v", changes, err)
+	}
+
+	first := Change{Time: time.Now().UTC(), CheckID: "sql.labels", Resource: "db-1", Field: "labels.team", From: "not set", To: "payments", Status: StatusApplied}
+	second := Change{Time: time.Now().UTC(), CheckID: "sql.labels", Resource: "db-2", Field: "labels.team", From: "ledger", To: "payments", Status: StatusFailed, Error: "boom"}
+	if err := store.AppendChanges([]Change{first}); err != nil {
+		t.Fatalf("AppendChanges() error = %v", err)
+	}
+	if err := store.AppendChanges([]Change{second}); err != nil {
+		t.Fatalf("AppendChanges() error = %v", err)
+	}
+
+	changes, err := store.Changes()
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if len(changes) != 2 || changes[0].Resource != "db-1" || changes[1].Error != "boom" {
+		t.Errorf("Changes() = %+v", changes)
+	}
+}
+
+func TestActiveSuppressions(t *testing.T) {
+	store := NewStore(t.TempDir())
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	sups := []Suppression{
+		{Key: "a", Until: now.Add(time.Hour)},
+		{Key: "b", Until: now.Add(-time.Minute)},
+		{Key: "c", Until: now.Add(time.Hour)},
+		// A later ack replaces the earlier one, here ending the snooze early
+		{Key: "c", Until: now},
+	}
+	for _, sup := range sups {
+		if err := store.AppendSuppression(context.Background(), sup); err != nil {
+			t.Fatalf("AppendSuppression() error = %v", err)
+		}
+	}
+
+	active, err := store.ActiveSuppressions(context.Background(), now)
+	if err != nil {
+		t.Fatalf("ActiveSuppressions() error = %v", err)
+	}
+	if len(active) != 1 || active["a"].Key != "a" {
+		t.Errorf("ActiveSuppressions() = %+v, want only a", active)
+	}
+}
+
+func TestSortSuppressions(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// GCS lists objects by name, not in the order acks were made
+	sups := []Suppression{
+		{Key: "c", Until: now, Created: now.Add(-time.Minute)},
+		{Key: "c", Until: now.Add(time.Hour), Created: now.Add(-time.Hour)},
+	}
+	sortSuppressions(sups)
+	if active := activeSuppressions(sups, now); len(active) != 0 {
+		t.Errorf("activeSuppressions() = %+v, want the later ack to end the snooze", active)
+	}
+}
+
+func TestAcknowledgements(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := &report.Report{Resources: []report.Resource{
+		{Type: "Cloud SQL", Project: "proj", Name: "db-1", Drifts: []report.Drift{{Field: "tier"}, {Field: "disk_type"}}},
+		{Type: "Cloud SQL", Project: "proj", Name: "db-2"},
+	}}
+	active := map[string]Suppression{
+		"tier": {ResourceType: "Cloud SQL", Resource: "proj/db-1", Field: "tier", By: "alice", Reason: "migration", Until: now.Add(time.Hour)},
+		// No longer drifted, so it did not affect the report
+		"db-2": {ResourceType: "Cloud SQL", Resource: "proj/db-2", Field: "tier", Until: now.Add(time.Hour)},
+		"disk": {ResourceType: "Cloud SQL", Resource: "proj/db-1", Field: "disk_type", Until: now.Add(time.Hour)},
+	}
+
+	acks := Acknowledgements(r, active)
+	if len(acks) != 2 {
+		t.Fatalf("Acknowledgements() = %+v, want 2", acks)
+	}
+	if acks[0].Field != "disk_type" || acks[1].Field != "tier" || acks[1].By != "alice" || acks[1].Reason != "migration" {
+		t.Errorf("Acknowledgements() = %+v", acks)
+	}
+	if Acknowledgements(r, nil) != nil {
+		t.Error("Acknowledgements() without suppressions should be nil")
+	}
+}
@@ -0,0 +1,139 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// reportsDir holds one subdirectory of JSON reports per report name
+const reportsDir = "reports"
+
+// ReportStore persists the drift report of every run under a name, such as
+// "sql-production", so trends can be computed across runs
+type ReportStore interface {
+	// SaveReport stores a report and returns where it was written
+	SaveReport(ctx context.Context, name string, r *report.Report) (string, error)
+	// ReportNames lists the names reports were saved under, sorted
+	ReportNames(ctx context.Context) ([]string, error)
+	// LoadReports returns the reports saved under a name, oldest first
+	LoadReports(ctx context.Context, name string) ([]*report.Report, error)
+}
+
+// OpenReportStore opens a report store at a local directory or, for
+// gs://bucket/prefix locations, in a GCS bucket
+func OpenReportStore(ctx context.Context, location string) (ReportStore, error) {
+	if gcs.IsURI(location) {
+		return newGCSStore(ctx, location)
+	}
+	return NewStore(location), nil
+}
+
+// reportObjectName returns the path of a report relative to the store root
+func reportObjectName(name string, r *report.Report) string {
+	return reportsDir + "/" + name + "/" + r.Timestamp.UTC().Format(gcs.TimeLayout) + ".json"
+}
+
+// encodeReport renders a report as stored in history
+func encodeReport(r *report.Report) ([]byte, error) {
+	output, err := r.FormatJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format JSON: %w", err)
+	}
+	return []byte(output + "\n"), nil
+}
+
+// decodeReport parses a stored report
+func decodeReport(path string, data []byte) (*report.Report, error) {
+	var r report.Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// SaveReport writes a report to <dir>/reports/<name>/<timestamp>.json
+func (s *Store) SaveReport(ctx context.Context, name string, r *report.Report) (string, error) {
+	data, err := encodeReport(r)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(reportObjectName(name, r)))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// ReportNames lists the names reports were saved under
+func (s *Store) ReportNames(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, reportsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// LoadReports reads the reports saved under a name, oldest first
+func (s *Store) LoadReports(ctx context.Context, name string) ([]*report.Report, error) {
+	dir := filepath.Join(s.dir, reportsDir, name)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reports: %w", err)
+	}
+
+	var reports []*report.Report
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		r, err := decodeReport(path, data)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	sortReports(reports)
+	return reports, nil
+}
+
+// sortReports orders reports by timestamp, oldest first
+func sortReports(reports []*report.Report) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Timestamp.Before(reports[j].Timestamp)
+	})
+}
+
+// Compile-time interface implementation checks
+var (
+	_ ReportStore = (*Store)(nil)
+	_ ReportStore = (*gcsStore)(nil)
+)
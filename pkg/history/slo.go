@@ -0,0 +1,187 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+const (
+	// DefaultSLOTeamLabel is the resource label findings are grouped by
+	DefaultSLOTeamLabel = "team"
+	// DefaultSLOWindowDays is how far back resolved findings are counted
+	DefaultSLOWindowDays = 30
+	// unassignedTeam groups findings of resources without a team label
+	unassignedTeam = "unassigned"
+)
+
+// SLOTarget is the time drift of a severity must be resolved within
+type SLOTarget struct {
+	Severity string `yaml:"severity"`
+	// ResolveWithin is a duration such as 48h
+	ResolveWithin string `yaml:"resolve_within"`
+	// Objective is the percentage of findings that must be resolved in time
+	Objective float64 `yaml:"objective"`
+}
+
+// SLOPolicy sets remediation targets, e.g. critical drift resolved within
+// 48h for 95% of findings, measured per team from the reports recorded in
+// history
+type SLOPolicy struct {
+	Targets []SLOTarget `yaml:"targets"`
+	// TeamLabel is the resource label naming the owning team
+	TeamLabel string `yaml:"team_label"`
+	// WindowDays is how many days of resolved findings are counted
+	WindowDays int `yaml:"window_days"`
+}
+
+// Validate checks the severity, duration and objective of every target
+func (p *SLOPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.WindowDays < 0 {
+		return fmt.Errorf("slo: window_days must be positive")
+	}
+	seen := make(map[string]bool)
+	for i, target := range p.Targets {
+		if report.SeverityRank(target.Severity) == 0 {
+			return fmt.Errorf("slo target %d: unknown severity %q", i+1, target.Severity)
+		}
+		if seen[target.Severity] {
+			return fmt.Errorf("slo target %d: duplicate target for %s", i+1, target.Severity)
+		}
+		seen[target.Severity] = true
+		if within, err := time.ParseDuration(target.ResolveWithin); err != nil || within <= 0 {
+			return fmt.Errorf("slo target %d: resolve_within must be a positive duration such as 48h, not %q", i+1, target.ResolveWithin)
+		}
+		if target.Objective <= 0 || target.Objective > 100 {
+			return fmt.Errorf("slo target %d: objective must be a percentage between 0 and 100", i+1)
+		}
+	}
+	return nil
+}
+
+// episode is one unbroken stretch of reports a finding appeared in
+type episode struct {
+	team     string
+	severity string
+	opened   time.Time
+	// resolved is zero while the finding is still reported
+	resolved time.Time
+}
+
+// episodes walks reports ordered oldest first and returns every stretch a
+// finding was reported without a break. A finding is resolved by the first
+// report it is missing from, and keeps the severity it was first reported
+// with, so escalations don't move it to a stricter target.
+func (p *SLOPolicy) episodes(reports []*report.Report) []episode {
+	label := p.TeamLabel
+	if label == "" {
+		label = DefaultSLOTeamLabel
+	}
+
+	var done []episode
+	open := make(map[string]*episode)
+	for _, r := range reports {
+		seen := make(map[string]bool)
+		for _, res := range r.Resources {
+			team := res.Labels[label]
+			if team == "" {
+				team = unassignedTeam
+			}
+			for _, d := range res.Drifts {
+				f := Finding{ResourceType: res.Type, Project: res.Project, Location: res.Location, Resource: res.Name, Field: d.Field}
+				key := f.key()
+				seen[key] = true
+				if _, ok := open[key]; !ok {
+					open[key] = &episode{team: team, severity: d.Severity, opened: r.Timestamp}
+				}
+			}
+		}
+		for key, e := range open {
+			if !seen[key] {
+				e.resolved = r.Timestamp
+				done = append(done, *e)
+				delete(open, key)
+			}
+		}
+	}
+	for _, e := range open {
+		done = append(done, *e)
+	}
+	return done
+}
+
+// Attainment measures each team's attainment of the targets as of now from
+// the histories of one or more analyses, each ordered oldest first. Findings
+// resolved before the window are not counted; open findings count as
+// breached once they are older than the target. Results are ordered by team,
+// then by the targets' order.
+func (p *SLOPolicy) Attainment(histories [][]*report.Report, now time.Time) []report.SLOAttainment {
+	if p == nil || len(p.Targets) == 0 {
+		return nil
+	}
+	windowDays := p.WindowDays
+	if windowDays == 0 {
+		windowDays = DefaultSLOWindowDays
+	}
+	windowStart := now.AddDate(0, 0, -windowDays)
+
+	targets := make(map[string]int)
+	for i, target := range p.Targets {
+		targets[target.Severity] = i
+	}
+
+	byTeam := make(map[string][]*report.SLOAttainment)
+	for _, reports := range histories {
+		for _, e := range p.episodes(reports) {
+			i, ok := targets[e.severity]
+			if !ok || (!e.resolved.IsZero() && e.resolved.Before(windowStart)) {
+				continue
+			}
+			if byTeam[e.team] == nil {
+				byTeam[e.team] = make([]*report.SLOAttainment, len(p.Targets))
+			}
+			slo := byTeam[e.team][i]
+			if slo == nil {
+				target := p.Targets[i]
+				slo = &report.SLOAttainment{Team: e.team, Severity: target.Severity, ResolveWithin: target.ResolveWithin, Objective: target.Objective}
+				byTeam[e.team][i] = slo
+			}
+
+			within, _ := time.ParseDuration(p.Targets[i].ResolveWithin)
+			switch {
+			case !e.resolved.IsZero() && e.resolved.Sub(e.opened) <= within:
+				slo.Met++
+			case e.resolved.IsZero() && now.Sub(e.opened) <= within:
+				slo.Open++
+			default:
+				slo.Breached++
+			}
+		}
+	}
+
+	teams := make([]string, 0, len(byTeam))
+	for team := range byTeam {
+		teams = append(teams, team)
+	}
+	sort.Strings(teams)
+
+	var results []report.SLOAttainment
+	for _, team := range teams {
+		for _, slo := range byTeam[team] {
+			if slo == nil {
+				continue
+			}
+			slo.Attainment = 100
+			if counted := slo.Met + slo.Breached; counted > 0 {
+				slo.Attainment = float64(slo.Met) / float64(counted) * 100
+			}
+			results = append(results, *slo)
+		}
+	}
+	return results
+}
@@ -0,0 +1,90 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// sloReport reports drift of the given severity on databases by team
+func sloReport(at time.Time, severity string, teams map[string]string) *report.Report {
+	r := &report.Report{Timestamp: at}
+	for name, team := range teams {
+		res := report.Resource{
+			Type: "Cloud SQL", Project: "prod", Name: name,
+			Drifts: []report.Drift{{Field: "tier", Severity: severity}},
+		}
+		if team != "" {
+			res.Labels = map[string]string{"team": team}
+		}
+		r.Resources = append(r.Resources, res)
+	}
+	return r
+}
+
+func TestSLOAttainment(t *testing.T) {
+	policy := &SLOPolicy{Targets: []SLOTarget{
+		{Severity: "critical", ResolveWithin: "48h", Objective: 95},
+		{Severity: "high", ResolveWithin: "168h", Objective: 90},
+	}}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return start.AddDate(0, 0, n) }
+	critical := []*report.Report{
+		// Resolved before the 30 day window, so not counted
+		sloReport(day(-40), "critical", map[string]string{"db-old": "payments"}),
+		sloReport(day(-39), "critical", nil),
+		sloReport(day(0), "critical", map[string]string{"db-1": "payments", "db-2": "payments"}),
+		// db-1 resolved within 24h, db-2 after 72h
+		sloReport(day(1), "critical", map[string]string{"db-2": "payments"}),
+		sloReport(day(2), "critical", map[string]string{"db-2": "payments", "db-3": "data"}),
+		sloReport(day(3), "critical", map[string]string{"db-3": "data"}),
+	}
+	// Unlabeled and still open within its target; medium has no target
+	high := []*report.Report{
+		sloReport(day(0), "high", map[string]string{"db-4": ""}),
+		sloReport(day(3), "high", map[string]string{"db-4": ""}),
+	}
+	medium := []*report.Report{sloReport(day(3), "medium", map[string]string{"db-5": "data"})}
+
+	got := policy.Attainment([][]*report.Report{critical, high, medium}, day(3))
+	want := []report.SLOAttainment{
+		{Team: "data", Severity: "critical", ResolveWithin: "48h", Objective: 95, Open: 1, Attainment: 100},
+		{Team: "payments", Severity: "critical", ResolveWithin: "48h", Objective: 95, Met: 1, Breached: 1, Attainment: 50},
+		{Team: "unassigned", Severity: "high", ResolveWithin: "168h", Objective: 90, Open: 1, Attainment: 100},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Attainment() =\n%+v\nwant\n%+v", got, want)
+	}
+	if got[1].Meets() || !got[0].Meets() {
+		t.Errorf("Meets() = %v, %v", got[0].Meets(), got[1].Meets())
+	}
+
+	// Left open past its target, db-3 breaches it
+	got = policy.Attainment([][]*report.Report{critical}, day(5))
+	if got[0].Breached != 1 || got[0].Open != 0 || got[0].Attainment != 0 {
+		t.Errorf("open past target = %+v, want breached", got[0])
+	}
+}
+
+func TestSLOPolicyValidate(t *testing.T) {
+	for _, policy := range []*SLOPolicy{
+		{Targets: []SLOTarget{{Severity: "urgent", ResolveWithin: "48h", Objective: 95}}},
+		{Targets: []SLOTarget{{Severity: "critical", ResolveWithin: "2d", Objective: 95}}},
+		{Targets: []SLOTarget{{Severity: "critical", ResolveWithin: "48h", Objective: 120}}},
+		{Targets: []SLOTarget{
+			{Severity: "critical", ResolveWithin: "48h", Objective: 95},
+			{Severity: "critical", ResolveWithin: "24h", Objective: 90},
+		}},
+		{WindowDays: -1},
+	} {
+		if err := policy.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", policy)
+		}
+	}
+}
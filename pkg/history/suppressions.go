@@ -0,0 +1,137 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/gcs"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Where suppressions are kept: an append-only log in a local store, one
+// object per suppression in a GCS bucket
+const (
+	suppressionsFile = "suppressions.ndjson"
+	suppressionsDir  = "suppressions"
+)
+
+// Suppression silences notifications for one finding until it expires
+type Suppression struct {
+	// Key identifies the finding, as used for notification deduplication
+	Key          string    `json:"key"`
+	ResourceType string    `json:"resource_type"`
+	Resource     string    `json:"resource"`
+	Field        string    `json:"field"`
+	Until        time.Time `json:"until"`
+	By           string    `json:"by,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Created      time.Time `json:"created"`
+}
+
+// SuppressionStore records suppressions where both ack and the daemon can
+// read them: a local directory or a GCS bucket
+type SuppressionStore interface {
+	// AppendSuppression records a suppression; a later entry for the same
+	// key replaces earlier ones
+	AppendSuppression(ctx context.Context, sup Suppression) error
+	// ActiveSuppressions returns the latest suppression per key that has
+	// not expired at now
+	ActiveSuppressions(ctx context.Context, now time.Time) (map[string]Suppression, error)
+}
+
+// OpenSuppressionStore opens a suppression store at a local directory or,
+// for gs://bucket/prefix locations, in a GCS bucket
+func OpenSuppressionStore(ctx context.Context, location string) (SuppressionStore, error) {
+	if gcs.IsURI(location) {
+		return newGCSStore(ctx, location)
+	}
+	return NewStore(location), nil
+}
+
+// AppendSuppression records a suppression; a later entry for the same key
+// replaces earlier ones
+func (s *Store) AppendSuppression(ctx context.Context, sup Suppression) error {
+	return s.appendRecords(suppressionsFile, []interface{}{sup})
+}
+
+// Suppressions reads every recorded suppression in the order it was written
+func (s *Store) Suppressions() ([]Suppression, error) {
+	var sups []Suppression
+	err := s.readRecords(suppressionsFile, func(dec *json.Decoder) error {
+		var sup Suppression
+		if err := dec.Decode(&sup); err != nil {
+			return err
+		}
+		sups = append(sups, sup)
+		return nil
+	})
+	return sups, err
+}
+
+// ActiveSuppressions returns the latest suppression per key that has not
+// expired at now
+func (s *Store) ActiveSuppressions(ctx context.Context, now time.Time) (map[string]Suppression, error) {
+	sups, err := s.Suppressions()
+	if err != nil {
+		return nil, err
+	}
+	return activeSuppressions(sups, now), nil
+}
+
+// activeSuppressions returns the last of the suppressions per key, dropping
+// those expired at now
+func activeSuppressions(sups []Suppression, now time.Time) map[string]Suppression {
+	latest := make(map[string]Suppression)
+	for _, sup := range sups {
+		latest[sup.Key] = sup
+	}
+	for key, sup := range latest {
+		if !sup.Until.After(now) {
+			delete(latest, key)
+		}
+	}
+	return latest
+}
+
+// sortSuppressions orders suppressions by when they were created, oldest first
+func sortSuppressions(sups []Suppression) {
+	sort.SliceStable(sups, func(i, j int) bool {
+		return sups[i].Created.Before(sups[j].Created)
+	})
+}
+
+// Acknowledgements returns the active suppressions covering drifts of the
+// report, so the report can show the documented acceptance next to them
+func Acknowledgements(r *report.Report, active map[string]Suppression) []report.Acknowledgement {
+	if len(active) == 0 {
+		return nil
+	}
+
+	type finding struct{ resourceType, resource, field string }
+	drifted := make(map[finding]bool)
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			drifted[finding{res.Type, res.QualifiedName(), d.Field}] = true
+		}
+	}
+
+	var acks []report.Acknowledgement
+	for _, sup := range active {
+		if !drifted[finding{sup.ResourceType, sup.Resource, sup.Field}] {
+			continue
+		}
+		acks = append(acks, report.Acknowledgement{
+			ResourceType: sup.ResourceType,
+			Resource:     sup.Resource,
+			Field:        sup.Field,
+			By:           sup.By,
+			Reason:       sup.Reason,
+			Created:      sup.Created,
+			Until:        sup.Until,
+		})
+	}
+	report.SortAcknowledgements(acks)
+	return acks
+}
@@ -0,0 +1,133 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Finding identifies one drifted field on one resource across runs
+type Finding struct {
+	ResourceType string `json:"resource_type"`
+	Project      string `json:"project,omitempty"`
+	Location     string `json:"location,omitempty"`
+	Resource     string `json:"resource"`
+	Field        string `json:"field"`
+	Severity     string `json:"severity"`
+}
+
+// key identifies the finding independent of its severity, which can be
+// changed by overrides between runs
+func (f Finding) key() string {
+	return strings.Join([]string{f.ResourceType, f.Project, f.Location, f.Resource, f.Field}, "\x00")
+}
+
+// Run summarizes one stored report and how it differs from the run before
+type Run struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Resources  int       `json:"resources"`
+	Drifted    int       `json:"drifted"`
+	Compliance float64   `json:"compliance"`
+	// Appeared and Resolved are empty for the first run, which has nothing
+	// to compare against
+	Appeared []Finding `json:"appeared,omitempty"`
+	Resolved []Finding `json:"resolved,omitempty"`
+}
+
+// findings returns the findings of a report by key
+func findings(r *report.Report) map[string]Finding {
+	byKey := make(map[string]Finding)
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			f := Finding{
+				ResourceType: res.Type,
+				Project:      res.Project,
+				Location:     res.Location,
+				Resource:     res.Name,
+				Field:        d.Field,
+				Severity:     d.Severity,
+			}
+			byKey[f.key()] = f
+		}
+	}
+	return byKey
+}
+
+// missingFrom returns the findings of a that are not in b, sorted
+func missingFrom(a, b map[string]Finding) []Finding {
+	var missing []Finding
+	for key, f := range a {
+		if _, ok := b[key]; !ok {
+			missing = append(missing, f)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return missing[i].key() < missing[j].key()
+	})
+	return missing
+}
+
+// Trend summarizes reports ordered oldest first, comparing each report with
+// the one before it
+func Trend(reports []*report.Report) []Run {
+	runs := make([]Run, 0, len(reports))
+	var previous map[string]Finding
+	for i, r := range reports {
+		current := findings(r)
+		run := Run{
+			Timestamp:  r.Timestamp,
+			Resources:  len(r.Resources),
+			Drifted:    r.DriftedCount(),
+			Compliance: r.Compliance(),
+		}
+		if i > 0 {
+			run.Appeared = missingFrom(current, previous)
+			run.Resolved = missingFrom(previous, current)
+		}
+		runs = append(runs, run)
+		previous = current
+	}
+	return runs
+}
+
+// FormatTrend renders the compliance trend of the runs stored under a name,
+// listing the drifts that appeared and were resolved in each run
+func FormatTrend(name string, runs []Run) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("History: %s (%d run(s))\n", name, len(runs)))
+	if len(runs) == 0 {
+		return sb.String()
+	}
+
+	first, last := runs[0], runs[len(runs)-1]
+	sb.WriteString(fmt.Sprintf("Compliance: %.1f%% -> %.1f%% (%+.1f points)\n\n",
+		first.Compliance, last.Compliance, last.Compliance-first.Compliance))
+
+	for i, run := range runs {
+		changes := "first run"
+		if i > 0 {
+			changes = fmt.Sprintf("+%d new, -%d resolved", len(run.Appeared), len(run.Resolved))
+		}
+		sb.WriteString(fmt.Sprintf("  %s  %5.1f%% compliant  %d/%d drifted  %s\n",
+			run.Timestamp.UTC().Format("2006-01-02 15:04 UTC"), run.Compliance, run.Drifted, run.Resources, changes))
+		for _, f := range run.Appeared {
+			sb.WriteString("      + " + formatFinding(f) + "\n")
+		}
+		for _, f := range run.Resolved {
+			sb.WriteString("      - " + formatFinding(f) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// formatFinding renders a finding on one line
+func formatFinding(f Finding) string {
+	resource := f.Resource
+	if f.Project != "" {
+		resource = f.Project + "/" + resource
+	}
+	return fmt.Sprintf("[%s] %s %s %s", strings.ToUpper(f.Severity), f.ResourceType, resource, f.Field)
+}
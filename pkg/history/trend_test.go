@@ -0,0 +1,122 @@
+package history
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func trendReports() []*report.Report {
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	return []*report.Report{
+		{Timestamp: start, Resources: []report.Resource{
+			{Type: "Cloud SQL", Project: "prod", Name: "db-1", Drifts: []report.Drift{{Field: "tier", Severity: "high"}}},
+			{Type: "Cloud SQL", Project: "prod", Name: "db-2"},
+		}},
+		{Timestamp: start.Add(24 * time.Hour), Resources: []report.Resource{
+			{Type: "Cloud SQL", Project: "prod", Name: "db-1"},
+			{Type: "Cloud SQL", Project: "prod", Name: "db-2", Drifts: []report.Drift{
+				{Field: "settings.backup_enabled", Severity: "critical"},
+				{Field: "labels.team", Severity: "low"},
+			}},
+		}},
+		{Timestamp: start.Add(48 * time.Hour), Resources: []report.Resource{
+			{Type: "Cloud SQL", Project: "prod", Name: "db-1"},
+			{Type: "Cloud SQL", Project: "prod", Name: "db-2", Drifts: []report.Drift{
+				// A severity override does not make the finding new
+				{Field: "labels.team", Severity: "medium"},
+			}},
+		}},
+	}
+}
+
+func TestTrend(t *testing.T) {
+	runs := Trend(trendReports())
+	if len(runs) != 3 {
+		t.Fatalf("got %d runs, want 3", len(runs))
+	}
+
+	tests := []struct {
+		compliance float64
+		appeared   []string
+		resolved   []string
+	}{
+		{50, nil, nil},
+		{50, []string{"labels.team", "settings.backup_enabled"}, []string{"tier"}},
+		{50, nil, []string{"settings.backup_enabled"}},
+	}
+	for i, tt := range tests {
+		run := runs[i]
+		if run.Compliance != tt.compliance {
+			t.Errorf("runs[%d].Compliance = %v, want %v", i, run.Compliance, tt.compliance)
+		}
+		if got := fields(run.Appeared); strings.Join(got, ",") != strings.Join(tt.appeared, ",") {
+			t.Errorf("runs[%d].Appeared = %v, want %v", i, got, tt.appeared)
+		}
+		if got := fields(run.Resolved); strings.Join(got, ",") != strings.Join(tt.resolved, ",") {
+			t.Errorf("runs[%d].Resolved = %v, want %v", i, got, tt.resolved)
+		}
+	}
+
+	text := FormatTrend("sql-production", runs)
+	for _, want := range []string{
+		"History: sql-production (3 run(s))",
+		"Compliance: 50.0% -> 50.0% (+0.0 points)",
+		"2026-03-02 06:00 UTC   50.0% compliant  1/2 drifted  +2 new, -1 resolved",
+		"+ [CRITICAL] Cloud SQL prod/db-2 settings.backup_enabled",
+		"- [HIGH] Cloud SQL prod/db-1 tier",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatTrend() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func fields(findings []Finding) []string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.Field)
+	}
+	return out
+}
+
+func TestLocalReportStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(t.TempDir())
+
+	if names, err := store.ReportNames(ctx); err != nil || len(names) != 0 {
+		t.Fatalf("ReportNames() on empty store = %v, %v", names, err)
+	}
+
+	reports := trendReports()
+	// Saved out of order; loading returns them oldest first
+	for _, i := range []int{2, 0, 1} {
+		if _, err := store.SaveReport(ctx, "sql-production", reports[i]); err != nil {
+			t.Fatalf("SaveReport() error = %v", err)
+		}
+	}
+	if _, err := store.SaveReport(ctx, "gke-production", reports[0]); err != nil {
+		t.Fatalf("SaveReport() error = %v", err)
+	}
+
+	names, err := store.ReportNames(ctx)
+	if err != nil || strings.Join(names, ",") != "gke-production,sql-production" {
+		t.Errorf("ReportNames() = %v, %v", names, err)
+	}
+
+	loaded, err := store.LoadReports(ctx, "sql-production")
+	if err != nil {
+		t.Fatalf("LoadReports() error = %v", err)
+	}
+	if len(loaded) != 3 {
+		t.Fatalf("LoadReports() returned %d reports, want 3", len(loaded))
+	}
+	for i, r := range loaded {
+		if !r.Timestamp.Equal(reports[i].Timestamp) {
+			t.Errorf("loaded[%d].Timestamp = %v, want %v", i, r.Timestamp, reports[i].Timestamp)
+		}
+	}
+}
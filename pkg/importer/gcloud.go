@@ -0,0 +1,214 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// GcloudDiff converts a unified diff of two `gcloud ... describe --format=yaml`
+// outputs into a drift report. The "---" side is treated as the expected
+// baseline and the "+++" side as the actual state. resourceType labels the
+// resources in the report (e.g. "Compute Instance").
+func GcloudDiff(r io.Reader, resourceType string) (*report.Report, error) {
+	if resourceType == "" {
+		resourceType = "gcloud resource"
+	}
+
+	rep := &report.Report{
+		Title:     "gcloud Diff Drift Report",
+		Timestamp: time.Now(),
+		Resources: make([]report.Resource, 0),
+	}
+
+	var current *diffResource
+	flush := func() {
+		if current != nil {
+			rep.Resources = append(rep.Resources, current.resource(resourceType))
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			flush()
+			current = newDiffResource()
+		case strings.HasPrefix(line, "+++ "):
+			if current == nil {
+				current = newDiffResource()
+			}
+			current.name = diffFileName(line[4:])
+		case strings.HasPrefix(line, "@@"), strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "\\"):
+			continue
+		default:
+			if current == nil {
+				current = newDiffResource()
+			}
+			current.add(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+	flush()
+
+	return rep, nil
+}
+
+// diffResource accumulates changed YAML keys for one file in the diff
+type diffResource struct {
+	name     string
+	stack    []yamlKey
+	order    []string
+	removed  map[string]string
+	added    map[string]string
+	metadata map[string]string
+}
+
+type yamlKey struct {
+	indent int
+	key    string
+}
+
+func newDiffResource() *diffResource {
+	return &diffResource{
+		removed:  make(map[string]string),
+		added:    make(map[string]string),
+		metadata: make(map[string]string),
+	}
+}
+
+// add processes a single diff line, tracking the YAML key path by indentation
+func (d *diffResource) add(line string) {
+	if line == "" {
+		return
+	}
+	marker, content := line[0], line[1:]
+	if marker != ' ' && marker != '-' && marker != '+' {
+		return
+	}
+
+	trimmed := strings.TrimLeft(content, " ")
+	if trimmed == "" {
+		return
+	}
+	indent := len(content) - len(trimmed)
+
+	for len(d.stack) > 0 && d.stack[len(d.stack)-1].indent >= indent {
+		d.stack = d.stack[:len(d.stack)-1]
+	}
+
+	path := d.path()
+	value := trimmed
+	if key, rest, ok := strings.Cut(trimmed, ":"); ok && !strings.HasPrefix(trimmed, "- ") && !strings.Contains(key, " ") {
+		rest = strings.TrimSpace(rest)
+		if path != "" {
+			path += "." + key
+		} else {
+			path = key
+		}
+		if rest == "" {
+			d.stack = append(d.stack, yamlKey{indent: indent, key: key})
+			return
+		}
+		value = rest
+	}
+
+	if len(d.stack) == 0 && (path == "project" || path == "region" || path == "zone" || path == "location" || path == "name" || path == "selfLink") {
+		d.metadata[path] = strings.Trim(value, `"'`)
+	}
+
+	switch marker {
+	case '-':
+		d.record(d.removed, path, value)
+	case '+':
+		d.record(d.added, path, value)
+	}
+}
+
+// record stores a changed value, joining list items under the same path
+func (d *diffResource) record(values map[string]string, path, value string) {
+	if _, seen := d.removed[path]; !seen {
+		if _, seen := d.added[path]; !seen {
+			d.order = append(d.order, path)
+		}
+	}
+	if existing, ok := values[path]; ok {
+		values[path] = existing + ", " + value
+		return
+	}
+	values[path] = value
+}
+
+func (d *diffResource) path() string {
+	keys := make([]string, len(d.stack))
+	for i, k := range d.stack {
+		keys[i] = k.key
+	}
+	return strings.Join(keys, ".")
+}
+
+// resource converts the accumulated changes into a report resource
+func (d *diffResource) resource(resourceType string) report.Resource {
+	name := d.metadata["name"]
+	if name == "" {
+		name = d.name
+	}
+
+	location := d.metadata["location"]
+	if location == "" {
+		location = d.metadata["region"]
+	}
+	if location == "" {
+		location = d.metadata["zone"]
+	}
+	if location != "" {
+		location = filepath.Base(location)
+	}
+
+	res := report.Resource{
+		Type:     resourceType,
+		Project:  d.metadata["project"],
+		Name:     name,
+		Location: location,
+		Drifts:   make([]report.Drift, 0),
+	}
+
+	for _, path := range d.order {
+		expected, hasExpected := d.removed[path]
+		actual, hasActual := d.added[path]
+		if hasExpected && hasActual && expected == actual {
+			continue
+		}
+		if !hasExpected {
+			expected = "not set"
+		}
+		if !hasActual {
+			actual = "not set"
+		}
+		res.Drifts = append(res.Drifts, report.Drift{
+			Field:    path,
+			Expected: expected,
+			Actual:   actual,
+			Severity: "medium",
+		})
+	}
+
+	return res
+}
+
+// diffFileName extracts a resource name from a diff header path
+func diffFileName(header string) string {
+	name, _, _ := strings.Cut(header, "\t")
+	name = strings.TrimPrefix(strings.TrimSpace(name), "b/")
+	base := filepath.Base(name)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
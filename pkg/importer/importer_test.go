@@ -0,0 +1,133 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const terraformPlanJSON = `{
+  "resource_drift": [
+    {
+      "address": "google_sql_database_instance.main",
+      "type": "google_sql_database_instance",
+      "name": "main",
+      "change": {
+        "actions": ["update"],
+        "before": {"project": "my-project", "region": "us-central1", "settings": {"tier": "db-custom-2-7680"}},
+        "after": {"project": "my-project", "region": "us-central1", "settings": {"tier": "db-custom-4-15360"}}
+      }
+    }
+  ],
+  "resource_changes": [
+    {
+      "address": "google_storage_bucket.logs",
+      "type": "google_storage_bucket",
+      "name": "logs",
+      "change": {
+        "actions": ["update"],
+        "before": {"project": "my-project", "location": "US", "versioning": false, "password": "old"},
+        "after": {"project": "my-project", "location": "US", "versioning": true, "password": "new"},
+        "after_unknown": {"self_link": true},
+        "after_sensitive": {"password": true}
+      }
+    },
+    {
+      "address": "google_project_service.sql",
+      "type": "google_project_service",
+      "name": "sql",
+      "change": {"actions": ["no-op"], "before": {}, "after": {}}
+    }
+  ]
+}`
+
+func TestTerraform(t *testing.T) {
+	rep, err := Terraform(strings.NewReader(terraformPlanJSON))
+	if err != nil {
+		t.Fatalf("Terraform() error = %v", err)
+	}
+
+	if len(rep.Resources) != 2 {
+		t.Fatalf("len(Resources) = %d, want 2", len(rep.Resources))
+	}
+
+	drifted := rep.Resources[0]
+	if drifted.State != "drifted" || drifted.Project != "my-project" || drifted.Location != "us-central1" {
+		t.Errorf("drifted resource = %+v", drifted)
+	}
+	if len(drifted.Drifts) != 1 {
+		t.Fatalf("len(drifted.Drifts) = %d, want 1", len(drifted.Drifts))
+	}
+	d := drifted.Drifts[0]
+	if d.Field != "settings.tier" || d.Expected != "db-custom-2-7680" || d.Actual != "db-custom-4-15360" || d.Severity != "high" {
+		t.Errorf("drift = %+v", d)
+	}
+
+	pending := rep.Resources[1]
+	fields := make(map[string][2]string)
+	for _, d := range pending.Drifts {
+		fields[d.Field] = [2]string{d.Expected, d.Actual}
+	}
+	if got := fields["versioning"]; got != [2]string{"true", "false"} {
+		t.Errorf("versioning = %v, want [true false]", got)
+	}
+	if got := fields["password"]; got[0] != "(sensitive)" {
+		t.Errorf("password expected = %q, want (sensitive)", got[0])
+	}
+	if got := fields["self_link"]; got[0] != "(known after apply)" {
+		t.Errorf("self_link expected = %q, want (known after apply)", got[0])
+	}
+}
+
+func TestTerraformInvalid(t *testing.T) {
+	if _, err := Terraform(strings.NewReader("not json")); err == nil {
+		t.Error("Terraform() expected error for invalid JSON")
+	}
+}
+
+const gcloudDiff = `--- baseline/prod-db.yaml	2024-01-01
++++ current/prod-db.yaml	2024-01-02
+@@ -1,12 +1,13 @@
+ name: prod-db
+ project: my-project
+ region: us-central1
+ settings:
+-  tier: db-custom-2-7680
++  tier: db-custom-4-15360
+   ipConfiguration:
+-    requireSsl: true
++    requireSsl: false
++    ipv4Enabled: true
+   backupConfiguration:
+     enabled: true
+`
+
+func TestGcloudDiff(t *testing.T) {
+	rep, err := GcloudDiff(strings.NewReader(gcloudDiff), "Cloud SQL")
+	if err != nil {
+		t.Fatalf("GcloudDiff() error = %v", err)
+	}
+
+	if len(rep.Resources) != 1 {
+		t.Fatalf("len(Resources) = %d, want 1", len(rep.Resources))
+	}
+
+	res := rep.Resources[0]
+	if res.Name != "prod-db" || res.Project != "my-project" || res.Location != "us-central1" || res.Type != "Cloud SQL" {
+		t.Errorf("resource = %+v", res)
+	}
+
+	want := []struct{ field, expected, actual string }{
+		{"settings.tier", "db-custom-2-7680", "db-custom-4-15360"},
+		{"settings.ipConfiguration.requireSsl", "true", "false"},
+		{"settings.ipConfiguration.ipv4Enabled", "not set", "true"},
+	}
+	if len(res.Drifts) != len(want) {
+		t.Fatalf("len(Drifts) = %d, want %d: %+v", len(res.Drifts), len(want), res.Drifts)
+	}
+	for i, w := range want {
+		d := res.Drifts[i]
+		if d.Field != w.field || d.Expected != w.expected || d.Actual != w.actual {
+			t.Errorf("Drifts[%d] = %+v, want %+v", i, d, w)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// terraformPlan is the subset of `terraform show -json <plan>` output used for import
+type terraformPlan struct {
+	ResourceDrift   []terraformResourceChange `json:"resource_drift"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+}
+
+type terraformResourceChange struct {
+	Address string          `json:"address"`
+	Type    string          `json:"type"`
+	Name    string          `json:"name"`
+	Change  terraformChange `json:"change"`
+}
+
+type terraformChange struct {
+	Actions         []string               `json:"actions"`
+	Before          map[string]interface{} `json:"before"`
+	After           map[string]interface{} `json:"after"`
+	AfterUnknown    interface{}            `json:"after_unknown"`
+	BeforeSensitive interface{}            `json:"before_sensitive"`
+	AfterSensitive  interface{}            `json:"after_sensitive"`
+}
+
+// Terraform converts a JSON plan (`terraform show -json plan.out`) into a drift report.
+//
+// Entries in resource_drift are changes made outside Terraform: the prior state
+// is the expected value and the refreshed state is the actual value.
+// Entries in resource_changes are pending changes: the configuration (after)
+// is the expected value and the current state (before) is the actual value.
+func Terraform(r io.Reader) (*report.Report, error) {
+	var plan terraformPlan
+	if err := json.NewDecoder(r).Decode(&plan); err != nil {
+		return nil, fmt.Errorf("failed to parse terraform plan JSON: %w", err)
+	}
+
+	rep := &report.Report{
+		Title:     "Terraform Drift Report",
+		Timestamp: time.Now(),
+		Resources: make([]report.Resource, 0),
+	}
+
+	for _, rc := range plan.ResourceDrift {
+		rep.Resources = append(rep.Resources, terraformResource(rc, true))
+	}
+
+	for _, rc := range plan.ResourceChanges {
+		if isNoOp(rc.Change.Actions) {
+			continue
+		}
+		rep.Resources = append(rep.Resources, terraformResource(rc, false))
+	}
+
+	return rep, nil
+}
+
+// terraformResource builds a report resource from a single plan entry
+func terraformResource(rc terraformResourceChange, outOfBand bool) report.Resource {
+	expected, actual := rc.Change.After, rc.Change.Before
+	expectedSensitive, actualSensitive := rc.Change.AfterSensitive, rc.Change.BeforeSensitive
+	state := strings.Join(rc.Change.Actions, "+")
+	if outOfBand {
+		expected, actual = rc.Change.Before, rc.Change.After
+		expectedSensitive, actualSensitive = rc.Change.BeforeSensitive, rc.Change.AfterSensitive
+		state = "drifted"
+	}
+
+	res := report.Resource{
+		Type:     rc.Type,
+		Project:  stringAttr("project", rc.Change.After, rc.Change.Before),
+		Name:     rc.Address,
+		Location: stringAttr("location", rc.Change.After, rc.Change.Before),
+		State:    state,
+		Drifts:   make([]report.Drift, 0),
+	}
+	if res.Location == "" {
+		res.Location = stringAttr("region", rc.Change.After, rc.Change.Before)
+	}
+	if res.Location == "" {
+		res.Location = stringAttr("zone", rc.Change.After, rc.Change.Before)
+	}
+
+	severity := terraformSeverity(rc.Change.Actions, outOfBand)
+
+	expectedFlat := make(map[string]string)
+	actualFlat := make(map[string]string)
+	flatten("", expected, expectedFlat)
+	flatten("", actual, actualFlat)
+
+	unknown := make(map[string]string)
+	flatten("", rc.Change.AfterUnknown, unknown)
+
+	keys := make(map[string]bool)
+	for k := range expectedFlat {
+		keys[k] = true
+	}
+	for k := range actualFlat {
+		keys[k] = true
+	}
+	if !outOfBand {
+		for k, v := range unknown {
+			if v == "true" {
+				keys[k] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		exp, expOK := expectedFlat[key]
+		act, actOK := actualFlat[key]
+		if !outOfBand && unknown[key] == "true" {
+			exp, expOK = "(known after apply)", true
+		}
+		if expOK && actOK && exp == act {
+			continue
+		}
+		if !expOK {
+			exp = "not set"
+		}
+		if !actOK {
+			act = "not set"
+		}
+		if isSensitive(expectedSensitive, key) {
+			exp = "(sensitive)"
+		}
+		if isSensitive(actualSensitive, key) {
+			act = "(sensitive)"
+		}
+		res.Drifts = append(res.Drifts, report.Drift{
+			Field:    key,
+			Expected: exp,
+			Actual:   act,
+			Severity: severity,
+		})
+	}
+
+	return res
+}
+
+// terraformSeverity maps plan actions to a drift severity
+func terraformSeverity(actions []string, outOfBand bool) string {
+	if outOfBand {
+		return "high"
+	}
+	for _, action := range actions {
+		if action == "delete" {
+			return "high"
+		}
+	}
+	return "medium"
+}
+
+// isNoOp reports whether the plan actions leave the resource untouched
+func isNoOp(actions []string) bool {
+	for _, action := range actions {
+		if action != "no-op" && action != "read" {
+			return false
+		}
+	}
+	return true
+}
+
+// stringAttr returns the first non-empty top-level string attribute found
+func stringAttr(key string, values ...map[string]interface{}) string {
+	for _, v := range values {
+		if s, ok := v[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// flatten converts nested maps into dotted keys; lists are kept as JSON values
+func flatten(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			flatten(key, child, out)
+		}
+	case nil:
+		// Unset attributes are reported as "not set" by the caller
+	case string:
+		out[prefix] = v
+	case []interface{}:
+		if allFalse(v) {
+			return
+		}
+		data, _ := json.Marshal(v)
+		out[prefix] = string(data)
+	default:
+		out[prefix] = fmt.Sprintf("%v", v)
+	}
+}
+
+// allFalse reports whether a list only contains false/empty markers, as
+// Terraform emits for after_unknown and *_sensitive on nested blocks
+func allFalse(values []interface{}) bool {
+	for _, v := range values {
+		switch b := v.(type) {
+		case bool:
+			if b {
+				return false
+			}
+		case map[string]interface{}:
+			if len(b) > 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isSensitive reports whether a flattened key is marked sensitive
+func isSensitive(sensitive interface{}, key string) bool {
+	flat := make(map[string]string)
+	flatten("", sensitive, flat)
+	for k, v := range flat {
+		if v == "true" && (k == key || strings.HasPrefix(key, k+".")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Package metacache is a read-through cache for slow-changing GCP metadata,
+// such as the GKE versions a location offers or the database flags a Cloud
+// SQL version supports. Entries are kept in memory and, when a directory is
+// set, on disk so later runs skip the lookups until the entries expire.
+package metacache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDir is where metadata is cached between runs
+const DefaultDir = ".drift-cache/metadata"
+
+// Cache stores fetched metadata by key
+type Cache struct {
+	dir    string
+	ttl    time.Duration
+	now    func() time.Time
+	mu     sync.Mutex
+	memory map[string]entry
+}
+
+type entry struct {
+	FetchedAt time.Time       `json:"fetched_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// New creates a cache whose entries expire after ttl. An empty dir keeps
+// entries in memory only.
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		dir:    dir,
+		ttl:    ttl,
+		now:    time.Now,
+		memory: make(map[string]entry),
+	}
+}
+
+// Lookup returns the cached value for key, calling fetch and storing its
+// result when the entry is missing or expired. Failed fetches are not cached.
+func Lookup[T any](c *Cache, key string, fetch func() (T, error)) (T, error) {
+	var value T
+	if e, ok := c.get(key); ok {
+		if err := json.Unmarshal(e.Value, &value); err == nil {
+			return value, nil
+		}
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value, fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	c.put(key, entry{FetchedAt: c.now(), Value: data})
+	return value, nil
+}
+
+// get returns a fresh entry from memory or disk
+func (c *Cache) get(key string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.memory[key]
+	if !ok && c.dir != "" {
+		data, err := os.ReadFile(c.path(key))
+		if err == nil && json.Unmarshal(data, &e) == nil {
+			ok = true
+			c.memory[key] = e
+		}
+	}
+	if !ok || c.now().Sub(e.FetchedAt) > c.ttl {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// put stores an entry; a cache that cannot be written only costs lookups
+func (c *Cache) put(key string, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.memory[key] = e
+	if c.dir == "" {
+		return
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(c.path(key), data, 0644)
+}
+
+// path maps a key such as gke-server-config/my-project/us-central1 to a file
+func (c *Cache) path(key string) string {
+	name := strings.NewReplacer("/", "_", ":", "_", "\\", "_").Replace(key)
+	return filepath.Join(c.dir, name+".json")
+}
@@ -0,0 +1,55 @@
+package metacache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(dir, time.Hour)
+	c.now = func() time.Time { return now }
+
+	calls := 0
+	fetch := func() ([]string, error) {
+		calls++
+		return []string{"1.29", "1.30"}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := Lookup(c, "gke/p/us-central1", fetch)
+		if err != nil || len(got) != 2 {
+			t.Fatalf("Lookup() = %v, %v", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+
+	// A new cache reads the entry written to disk
+	reopened := New(dir, time.Hour)
+	reopened.now = c.now
+	if _, err := Lookup(reopened, "gke/p/us-central1", fetch); err != nil || calls != 1 {
+		t.Errorf("Lookup() from disk: err = %v, fetch calls = %d, want 1", err, calls)
+	}
+
+	// Expired entries are fetched again
+	now = now.Add(2 * time.Hour)
+	if _, err := Lookup(c, "gke/p/us-central1", fetch); err != nil || calls != 2 {
+		t.Errorf("Lookup() after expiry: err = %v, fetch calls = %d, want 2", err, calls)
+	}
+}
+
+func TestLookupErrorNotCached(t *testing.T) {
+	c := New("", time.Hour)
+	failing := func() (int, error) { return 0, errors.New("permission denied") }
+	if _, err := Lookup(c, "k", failing); err == nil {
+		t.Fatal("Lookup() should return the fetch error")
+	}
+	got, err := Lookup(c, "k", func() (int, error) { return 42, nil })
+	if err != nil || got != 42 {
+		t.Errorf("Lookup() after failure = %v, %v, want 42", got, err)
+	}
+}
@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPagerDutyNotify(t *testing.T) {
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	pd, err := NewPagerDuty(PagerDutyConfig{RoutingKey: "key", EventsURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewPagerDuty() error = %v", err)
+	}
+	if err := pd.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	// Default minimum severity is critical, so only the backup drift triggers
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event["event_action"] != "trigger" || event["routing_key"] != "key" {
+		t.Errorf("unexpected event envelope: %v", event)
+	}
+	if want := "drift-analysis/Cloud SQL/prod/db-1/settings.backup_enabled"; event["dedup_key"] != want {
+		t.Errorf("dedup_key = %v, want %s", event["dedup_key"], want)
+	}
+	payload := event["payload"].(map[string]interface{})
+	if payload["severity"] != "critical" || payload["source"] != "prod/db-1" {
+		t.Errorf("unexpected payload: %v", payload)
+	}
+}
+
+func TestPagerDutyDedupKeyStable(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&event)
+		keys = append(keys, event["dedup_key"].(string))
+	}))
+	defer server.Close()
+
+	pd, err := NewPagerDuty(PagerDutyConfig{RoutingKey: "key", EventsURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewPagerDuty() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := pd.Notify(context.Background(), testReport()); err != nil {
+			t.Fatalf("Notify() error = %v", err)
+		}
+	}
+	if len(keys) != 2 || keys[0] != keys[1] {
+		t.Errorf("dedup keys differ across runs: %v", keys)
+	}
+}
+
+func TestOpsgenieNotify(t *testing.T) {
+	var auth string
+	var alerts []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		var alert map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("failed to decode alert: %v", err)
+		}
+		alerts = append(alerts, alert)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	og, err := NewOpsgenie(OpsgenieConfig{APIKey: "secret", AlertsURL: server.URL, MinSeverity: "medium", Responders: []string{"dba"}})
+	if err != nil {
+		t.Fatalf("NewOpsgenie() error = %v", err)
+	}
+	if err := og.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if auth != "GenieKey secret" {
+		t.Errorf("Authorization = %q, want GenieKey secret", auth)
+	}
+	if len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want 2", len(alerts))
+	}
+	if alerts[0]["priority"] != "P1" || alerts[1]["priority"] != "P3" {
+		t.Errorf("priorities = %v, %v, want P1, P3", alerts[0]["priority"], alerts[1]["priority"])
+	}
+	if want := "drift-analysis/Cloud SQL/prod/db-1/tier"; alerts[1]["alias"] != want {
+		t.Errorf("alias = %v, want %s", alerts[1]["alias"], want)
+	}
+	if _, ok := alerts[0]["responders"]; !ok {
+		t.Error("alert missing responders")
+	}
+}
+
+func TestNewAlertingValidation(t *testing.T) {
+	if _, err := NewPagerDuty(PagerDutyConfig{}); err == nil {
+		t.Error("NewPagerDuty() without routing_key should fail")
+	}
+	if _, err := NewOpsgenie(OpsgenieConfig{}); err == nil {
+		t.Error("NewOpsgenie() without api_key should fail")
+	}
+	if _, err := NewOpsgenie(OpsgenieConfig{APIKey: "secret", Region: "apac"}); err == nil {
+		t.Error("NewOpsgenie() with invalid region should fail")
+	}
+	og, err := NewOpsgenie(OpsgenieConfig{APIKey: "secret", Region: "eu"})
+	if err != nil {
+		t.Fatalf("NewOpsgenie() error = %v", err)
+	}
+	if og.config.AlertsURL != opsgenieAlertsURLs["eu"] {
+		t.Errorf("AlertsURL = %s, want EU endpoint", og.config.AlertsURL)
+	}
+}
@@ -0,0 +1,390 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dead-letter defaults
+const (
+	DefaultDeadLetterDir     = ".drift-cache/notifications"
+	defaultDeadLetterRetries = 10
+	defaultDeadLetterBackoff = time.Minute
+	defaultDeadLetterMaxWait = 6 * time.Hour
+)
+
+// ErrQueued marks a notification that failed and was queued for a retry
+var ErrQueued = errors.New("queued for retry")
+
+// DeadLetterConfig queues notifications that fail with a network error, a
+// timeout, 429 or a 5xx response on disk, so they are retried on the next run
+// instead of being dropped
+type DeadLetterConfig struct {
+	// Dir holds one file per queued request (default: .drift-cache/notifications)
+	Dir string `yaml:"dir,omitempty"`
+	// MaxAttempts drops a request after this many failed sends (default: 10)
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+	// Backoff is the wait before the first retry, doubled after every
+	// failed retry (default: 1m)
+	Backoff string `yaml:"backoff,omitempty"`
+	// MaxBackoff caps the wait between retries (default: 6h)
+	MaxBackoff string `yaml:"max_backoff,omitempty"`
+}
+
+// DeadLetter is a queued request. Headers are stored as sent, so the queue
+// directory is only readable by its owner.
+type DeadLetter struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+	Attempts    int         `json:"attempts"`
+	FirstFailed time.Time   `json:"first_failed"`
+	NextAttempt time.Time   `json:"next_attempt"`
+	LastError   string      `json:"last_error"`
+}
+
+// FlushResult counts what a flush did with the queued requests
+type FlushResult struct {
+	Sent    int
+	Pending int
+	Dropped int
+}
+
+// DeadLetterQueue stores failed notification requests and replays them
+type DeadLetterQueue struct {
+	dir         string
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+	client      *http.Client
+	now         func() time.Time
+
+	mu sync.Mutex
+}
+
+// NewDeadLetterQueue creates a queue from the config; the directory is
+// created on the first failure
+func NewDeadLetterQueue(config DeadLetterConfig) (*DeadLetterQueue, error) {
+	q := &DeadLetterQueue{
+		dir:         config.Dir,
+		maxAttempts: config.MaxAttempts,
+		backoff:     defaultDeadLetterBackoff,
+		maxBackoff:  defaultDeadLetterMaxWait,
+		client:      &http.Client{Timeout: defaultTimeout},
+		now:         time.Now,
+	}
+	if q.dir == "" {
+		q.dir = DefaultDeadLetterDir
+	}
+	if q.maxAttempts == 0 {
+		q.maxAttempts = defaultDeadLetterRetries
+	}
+	if q.maxAttempts < 0 {
+		return nil, fmt.Errorf("dead_letter: invalid max_attempts %d", config.MaxAttempts)
+	}
+	var err error
+	if config.Backoff != "" {
+		if q.backoff, err = time.ParseDuration(config.Backoff); err != nil || q.backoff <= 0 {
+			return nil, fmt.Errorf("dead_letter: invalid backoff %q", config.Backoff)
+		}
+	}
+	if config.MaxBackoff != "" {
+		if q.maxBackoff, err = time.ParseDuration(config.MaxBackoff); err != nil || q.maxBackoff <= 0 {
+			return nil, fmt.Errorf("dead_letter: invalid max_backoff %q", config.MaxBackoff)
+		}
+	}
+	return q, nil
+}
+
+// Dir returns the directory requests are queued in
+func (q *DeadLetterQueue) Dir() string {
+	return q.dir
+}
+
+// queueable is a notifier whose failed requests can be queued
+type queueable interface {
+	useQueue(q *DeadLetterQueue)
+}
+
+// Attach queues the failed requests of the notifiers, including the sinks of
+// routers and the notifiers behind digests. A nil queue attaches nothing.
+func (q *DeadLetterQueue) Attach(notifiers ...Notifier) {
+	if q == nil {
+		return
+	}
+	for _, n := range notifiers {
+		if n, ok := n.(queueable); ok {
+			n.useQueue(q)
+		}
+	}
+}
+
+func (s *Slack) useQueue(q *DeadLetterQueue) {
+	s.client.Transport = q.transport(s.client.Transport)
+}
+
+func (p *PagerDuty) useQueue(q *DeadLetterQueue) {
+	p.client.Transport = q.transport(p.client.Transport)
+}
+
+func (o *Opsgenie) useQueue(q *DeadLetterQueue) {
+	o.client.Transport = q.transport(o.client.Transport)
+}
+
+func (w *Webhook) useQueue(q *DeadLetterQueue) {
+	w.client.Transport = q.transport(w.client.Transport)
+}
+
+func (r *Router) useQueue(q *DeadLetterQueue) {
+	for _, sink := range r.sinks {
+		q.Attach(sink)
+	}
+}
+
+func (d *Digest) useQueue(q *DeadLetterQueue) {
+	q.Attach(d.next)
+}
+
+// transport wraps next so retryable failures are queued. The failure is still
+// returned, wrapping ErrQueued, so callers log it.
+func (q *DeadLetterQueue) transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &queueTransport{queue: q, next: next}
+}
+
+type queueTransport struct {
+	queue *DeadLetterQueue
+	next  http.RoundTripper
+}
+
+func (t *queueTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body is consumed by the request, so keep a copy to queue
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		body, err = io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	var failure string
+	switch {
+	case err != nil:
+		failure = err.Error()
+	case retryable(resp.StatusCode):
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		failure = fmt.Sprintf("%s returned %s: %s", req.URL, resp.Status, bytes.TrimSpace(msg))
+	default:
+		return resp, nil
+	}
+
+	if qerr := t.queue.enqueue(req, body, failure); qerr != nil {
+		return nil, fmt.Errorf("%s (and could not be queued: %v)", failure, qerr)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrQueued, failure)
+}
+
+// retryable reports whether a response status is worth retrying later
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// enqueue stores a failed request for its first retry
+func (q *DeadLetterQueue) enqueue(req *http.Request, body []byte, failure string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err := os.MkdirAll(q.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create dead-letter directory: %w", err)
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	now := q.now()
+	letter := &DeadLetter{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Header:      req.Header.Clone(),
+		Body:        body,
+		Attempts:    1,
+		FirstFailed: now,
+		NextAttempt: now.Add(q.wait(1)),
+		LastError:   failure,
+	}
+	name := fmt.Sprintf("%d-%s.json", now.UnixNano(), hex.EncodeToString(suffix))
+	return q.write(filepath.Join(q.dir, name), letter)
+}
+
+// wait is the backoff after a number of failed attempts
+func (q *DeadLetterQueue) wait(attempts int) time.Duration {
+	wait := q.backoff
+	for i := 1; i < attempts && wait < q.maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, q.maxBackoff)
+}
+
+// write saves a queued request, replacing the file atomically
+func (q *DeadLetterQueue) write(path string, letter *DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write dead letter: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// List returns the queued requests in the order they failed
+func (q *DeadLetterQueue) List() ([]DeadLetter, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	paths, err := q.paths()
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]DeadLetter, 0, len(paths))
+	for _, path := range paths {
+		letter, err := readDeadLetter(path)
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, *letter)
+	}
+	return letters, nil
+}
+
+// Flush resends the queued requests that are due, or all of them when force
+// is set. Sent requests are removed; a request that fails again waits twice
+// as long, and is dropped once it reaches max_attempts or is rejected with a
+// status that retrying won't fix.
+func (q *DeadLetterQueue) Flush(ctx context.Context, force bool) (FlushResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var result FlushResult
+	paths, err := q.paths()
+	if err != nil {
+		return result, err
+	}
+
+	now := q.now()
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		letter, err := readDeadLetter(path)
+		if err != nil {
+			return result, err
+		}
+		if !force && now.Before(letter.NextAttempt) {
+			result.Pending++
+			continue
+		}
+
+		retry, err := q.resend(ctx, letter)
+		if err == nil {
+			result.Sent++
+			if err := os.Remove(path); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		letter.Attempts++
+		letter.LastError = err.Error()
+		if !retry || letter.Attempts >= q.maxAttempts {
+			result.Dropped++
+			if err := os.Remove(path); err != nil {
+				return result, err
+			}
+			continue
+		}
+		letter.NextAttempt = now.Add(q.wait(letter.Attempts))
+		result.Pending++
+		if err := q.write(path, letter); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// resend sends a queued request; retry reports whether a failure may succeed later
+func (q *DeadLetterQueue) resend(ctx context.Context, letter *DeadLetter) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, letter.Method, letter.URL, bytes.NewReader(letter.Body))
+	if err != nil {
+		return false, err
+	}
+	req.Header = letter.Header.Clone()
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return retryable(resp.StatusCode), fmt.Errorf("%s returned %s: %s", letter.URL, resp.Status, bytes.TrimSpace(msg))
+	}
+	return false, nil
+}
+
+// paths returns the queued request files, oldest first
+func (q *DeadLetterQueue) paths() ([]string, error) {
+	entries, err := os.ReadDir(q.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead-letter directory: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		paths = append(paths, filepath.Join(q.dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// readDeadLetter reads a queued request
+func readDeadLetter(path string) (*DeadLetter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var letter DeadLetter
+	if err := json.Unmarshal(data, &letter); err != nil {
+		return nil, fmt.Errorf("invalid dead letter %s: %w", path, err)
+	}
+	return &letter, nil
+}
@@ -0,0 +1,132 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeadLetterQueueAndFlush(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	config := &Config{
+		Slack:      &SlackConfig{WebhookURL: server.URL},
+		DeadLetter: &DeadLetterConfig{Dir: t.TempDir(), Backoff: "1m", MaxAttempts: 3},
+	}
+	notifiers, err := config.Notifiers()
+	if err != nil {
+		t.Fatalf("Notifiers() error = %v", err)
+	}
+	queue, err := config.DeadLetterQueue()
+	if err != nil {
+		t.Fatalf("DeadLetterQueue() error = %v", err)
+	}
+	// Notifiers and the queue built here share the directory
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	notifiers[0].(*Slack).client.Transport.(*queueTransport).queue.now = func() time.Time { return now }
+	queue.now = func() time.Time { return now }
+
+	err = notifiers[0].Notify(context.Background(), testReport())
+	if !errors.Is(err, ErrQueued) {
+		t.Fatalf("Notify() error = %v, want ErrQueued", err)
+	}
+	letters, err := queue.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(letters) != 1 || letters[0].Attempts != 1 || string(letters[0].Body) != bodies[0] {
+		t.Fatalf("queued = %+v, want the failed request", letters)
+	}
+	if got := letters[0].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("queued Content-Type = %q", got)
+	}
+
+	// Not due yet
+	result, err := queue.Flush(context.Background(), false)
+	if err != nil || result != (FlushResult{Pending: 1}) || len(bodies) != 1 {
+		t.Fatalf("Flush() before backoff = %+v, %v with %d requests", result, err, len(bodies))
+	}
+
+	// Due and still failing: the backoff doubles
+	now = now.Add(time.Minute)
+	result, err = queue.Flush(context.Background(), false)
+	if err != nil || result != (FlushResult{Pending: 1}) {
+		t.Fatalf("Flush() failing = %+v, %v", result, err)
+	}
+	letters, _ = queue.List()
+	if letters[0].Attempts != 2 || !letters[0].NextAttempt.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("after retry = attempts %d, next %v", letters[0].Attempts, letters[0].NextAttempt)
+	}
+
+	// Forced and recovered: the request is replayed as queued and removed
+	status = http.StatusOK
+	result, err = queue.Flush(context.Background(), true)
+	if err != nil || result != (FlushResult{Sent: 1}) {
+		t.Fatalf("Flush() recovered = %+v, %v", result, err)
+	}
+	if len(bodies) != 3 || bodies[2] != bodies[0] {
+		t.Errorf("replayed body = %q, want %q", bodies[len(bodies)-1], bodies[0])
+	}
+	if letters, _ := queue.List(); len(letters) != 0 {
+		t.Errorf("queue not empty after flush: %+v", letters)
+	}
+}
+
+func TestDeadLetterDrops(t *testing.T) {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	queue, err := NewDeadLetterQueue(DeadLetterConfig{Dir: t.TempDir(), MaxAttempts: 2})
+	if err != nil {
+		t.Fatalf("NewDeadLetterQueue() error = %v", err)
+	}
+	webhook, err := NewWebhook(WebhookConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	queue.Attach(webhook)
+
+	// Client errors are not retried, so nothing is queued
+	if err := webhook.Notify(context.Background(), testReport()); err == nil || errors.Is(err, ErrQueued) {
+		t.Fatalf("Notify() error = %v, want an unqueued error", err)
+	}
+	if letters, _ := queue.List(); len(letters) != 0 {
+		t.Fatalf("queued %d requests for a 400", len(letters))
+	}
+
+	// A rate-limited request is queued and dropped after max_attempts
+	status = http.StatusTooManyRequests
+	if err := webhook.Notify(context.Background(), testReport()); !errors.Is(err, ErrQueued) {
+		t.Fatalf("Notify() error = %v, want ErrQueued", err)
+	}
+	result, err := queue.Flush(context.Background(), true)
+	if err != nil || result != (FlushResult{Dropped: 1}) {
+		t.Fatalf("Flush() = %+v, %v, want one dropped", result, err)
+	}
+}
+
+func TestDeadLetterConfigInvalid(t *testing.T) {
+	for _, config := range []DeadLetterConfig{
+		{Backoff: "soon"},
+		{MaxBackoff: "-1h"},
+		{MaxAttempts: -1},
+	} {
+		if _, err := NewDeadLetterQueue(config); err == nil {
+			t.Errorf("NewDeadLetterQueue(%+v) succeeded, want an error", config)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Digest collects the latest state of every resource it is notified about
+// and forwards it as one report at most once per interval. State is kept in
+// memory, so a restart starts a new interval.
+type Digest struct {
+	next     Notifier
+	interval time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	due       time.Time
+	resources map[string]report.Resource
+}
+
+// NewDigest wraps a notifier; the first digest is sent one interval after creation
+func NewDigest(next Notifier, interval time.Duration, now func() time.Time) *Digest {
+	return &Digest{
+		next:      next,
+		interval:  interval,
+		now:       now,
+		due:       now().Add(interval),
+		resources: make(map[string]report.Resource),
+	}
+}
+
+// Notify records the report and sends the digest once it is due. A failed
+// send keeps the collected state for the next attempt, unless the digest was
+// queued for a retry.
+func (d *Digest) Notify(ctx context.Context, r *report.Report) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, res := range r.Resources {
+		d.resources[res.Type+"/"+res.Project+"/"+res.Name] = res
+	}
+
+	now := d.now()
+	if now.Before(d.due) {
+		return nil
+	}
+
+	keys := make([]string, 0, len(d.resources))
+	for key := range d.resources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	digest := &report.Report{Title: "Drift Digest", Timestamp: now}
+	for _, key := range keys {
+		digest.Resources = append(digest.Resources, d.resources[key])
+	}
+	err := d.next.Notify(ctx, digest)
+	if err != nil && !errors.Is(err, ErrQueued) {
+		return err
+	}
+
+	d.resources = make(map[string]report.Resource)
+	d.due = now.Add(d.interval)
+	return err
+}
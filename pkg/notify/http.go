@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultTimeout bounds each request to a notification backend
+const defaultTimeout = 30 * time.Second
+
+// postJSON sends payload as JSON and fails on a non-2xx response, including
+// the start of the response body in the error
+func postJSON(ctx context.Context, client *http.Client, integration, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode message: %w", integration, err)
+	}
+	return send(ctx, client, integration, http.MethodPost, url, "application/json", headers, body)
+}
+
+// send makes a request with body and fails on a non-2xx response, including
+// the start of the response body in the error. headers may override the
+// content type.
+func send(ctx context.Context, client *http.Client, integration, method, url, contentType string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: failed to create request: %w", integration, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: request failed: %w", integration, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s returned %s: %s", integration, url, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
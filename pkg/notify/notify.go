@@ -0,0 +1,185 @@
+// Package notify delivers drift report summaries to external channels.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// maxListedDrifts caps the drifts included in a notification message
+const maxListedDrifts = 10
+
+// Notifier sends a summary of a drift report
+type Notifier interface {
+	Notify(ctx context.Context, r *report.Report) error
+}
+
+// Config holds the notification channels configured for scheduled analyses
+type Config struct {
+	Slack     *SlackConfig     `yaml:"slack"`
+	PagerDuty *PagerDutyConfig `yaml:"pagerduty"`
+	Opsgenie  *OpsgenieConfig  `yaml:"opsgenie"`
+	Webhooks  []WebhookConfig  `yaml:"webhooks"`
+	// Sinks are named channels for Routes; they receive only routed drifts
+	Sinks  map[string]SinkConfig `yaml:"sinks"`
+	Routes []RouteConfig         `yaml:"routes"`
+	// Snooze adds a signed snooze command to every finding
+	Snooze *SnoozeConfig `yaml:"snooze"`
+	// DeadLetter queues failed notifications on disk for a later retry
+	DeadLetter *DeadLetterConfig `yaml:"dead_letter"`
+}
+
+// Notifiers builds the notifiers enabled in the config
+func (c *Config) Notifiers() ([]Notifier, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	snoozer, err := c.Snoozer()
+	if err != nil {
+		return nil, err
+	}
+
+	var notifiers []Notifier
+	if c.Slack != nil {
+		slack, err := NewSlack(*c.Slack)
+		if err != nil {
+			return nil, err
+		}
+		slack.snoozer = snoozer
+		notifiers = append(notifiers, slack)
+	}
+	if c.PagerDuty != nil {
+		pagerDuty, err := NewPagerDuty(*c.PagerDuty)
+		if err != nil {
+			return nil, err
+		}
+		pagerDuty.snoozer = snoozer
+		notifiers = append(notifiers, pagerDuty)
+	}
+	if c.Opsgenie != nil {
+		opsgenie, err := NewOpsgenie(*c.Opsgenie)
+		if err != nil {
+			return nil, err
+		}
+		opsgenie.snoozer = snoozer
+		notifiers = append(notifiers, opsgenie)
+	}
+	for _, config := range c.Webhooks {
+		webhook, err := NewWebhook(config)
+		if err != nil {
+			return nil, err
+		}
+		webhook.snoozer = snoozer
+		notifiers = append(notifiers, webhook)
+	}
+	if len(c.Sinks) > 0 || len(c.Routes) > 0 {
+		router, err := NewRouter(c.Sinks, c.Routes, snoozer)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, router)
+	}
+
+	queue, err := c.DeadLetterQueue()
+	if err != nil {
+		return nil, err
+	}
+	queue.Attach(notifiers...)
+	return notifiers, nil
+}
+
+// DeadLetterQueue builds the dead-letter queue; it returns nil when failed
+// notifications are not queued
+func (c *Config) DeadLetterQueue() (*DeadLetterQueue, error) {
+	if c == nil || c.DeadLetter == nil {
+		return nil, nil
+	}
+	return NewDeadLetterQueue(*c.DeadLetter)
+}
+
+// Snoozer builds the snoozer; it returns nil when snoozing is not configured
+func (c *Config) Snoozer() (*Snoozer, error) {
+	if c == nil || c.Snooze == nil {
+		return nil, nil
+	}
+	return NewSnoozer(*c.Snooze)
+}
+
+// finding is a drift together with the resource it was found on
+type finding struct {
+	resourceType string
+	resource     string
+	drift        report.Drift
+}
+
+// dedupKey identifies a finding across runs so alerting backends group
+// repeated detections of the same drift into one incident
+func (f finding) dedupKey() string {
+	return fmt.Sprintf("drift-analysis/%s/%s/%s", f.resourceType, f.resource, f.drift.Field)
+}
+
+// title is a one-line description of the finding
+func (f finding) title() string {
+	return fmt.Sprintf("[%s] %s %s drifted: expected %s, got %s",
+		strings.ToUpper(f.drift.Severity), f.resource, f.drift.Field, f.drift.Expected, f.drift.Actual)
+}
+
+// validMinSeverity checks a configured minimum severity
+func validMinSeverity(integration, severity string) error {
+	if report.SeverityRank(severity) == 0 {
+		return fmt.Errorf("%s: invalid min_severity %q", integration, severity)
+	}
+	return nil
+}
+
+// findings returns the drifts at or above minSeverity, in report order
+func findings(r *report.Report, minSeverity string) []finding {
+	minRank := report.SeverityRank(minSeverity)
+
+	var found []finding
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			if report.SeverityRank(d.Severity) >= minRank {
+				found = append(found, newFinding(res, d))
+			}
+		}
+	}
+	return found
+}
+
+// newFinding pairs a drift with its resource, named project/name when the
+// project is known
+func newFinding(res report.Resource, drift report.Drift) finding {
+	return finding{resourceType: res.Type, resource: res.QualifiedName(), drift: drift}
+}
+
+// summarize renders a plain-text summary of the findings, with a snooze
+// instruction per listed finding when snoozer is set
+func summarize(r *report.Report, found []finding, snoozer *Snoozer) string {
+	drifts := make([]report.Drift, 0, len(found))
+	for _, f := range found {
+		drifts = append(drifts, f.drift)
+	}
+	critical, high, medium, low := report.CountBySeverity(drifts)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("*%s*: %d drift(s) across %d resource(s) (critical: %d, high: %d, medium: %d, low: %d)\n",
+		r.Title, len(found), r.DriftedCount(), critical, high, medium, low))
+
+	for i, f := range found {
+		if i == maxListedDrifts {
+			sb.WriteString(fmt.Sprintf("…and %d more\n", len(found)-maxListedDrifts))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("• [%s] %s %s: expected %s, got %s\n",
+			strings.ToUpper(f.drift.Severity), f.resource, f.drift.Field, f.drift.Expected, f.drift.Actual))
+		if snoozer != nil {
+			sb.WriteString(fmt.Sprintf("   snooze: `%s`\n", snoozer.Instruction(f)))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// opsgenieAlertsURLs are the Opsgenie Alert API endpoints per region
+var opsgenieAlertsURLs = map[string]string{
+	"us": "https://api.opsgenie.com/v2/alerts",
+	"eu": "https://api.eu.opsgenie.com/v2/alerts",
+}
+
+// OpsgenieConfig configures Opsgenie alerts through the Alert API
+type OpsgenieConfig struct {
+	APIKey string `yaml:"api_key"`
+	// Region selects the API endpoint: us (default) or eu
+	Region string `yaml:"region,omitempty"`
+	// MinSeverity is the lowest severity that opens an alert (default: critical)
+	MinSeverity string `yaml:"min_severity,omitempty"`
+	// Responders are teams notified for the alert
+	Responders []string `yaml:"responders,omitempty"`
+	// AlertsURL overrides the Alert API endpoint
+	AlertsURL string `yaml:"alerts_url,omitempty"`
+}
+
+// Opsgenie opens one Opsgenie alert per drifted field. The alert alias is a
+// dedup key per resource and field; Opsgenie increments the count of an open
+// alert with the same alias instead of creating a new one.
+type Opsgenie struct {
+	config  OpsgenieConfig
+	client  *http.Client
+	snoozer *Snoozer
+}
+
+// NewOpsgenie creates an Opsgenie notifier
+func NewOpsgenie(config OpsgenieConfig) (*Opsgenie, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("opsgenie: api_key is required")
+	}
+	if config.MinSeverity == "" {
+		config.MinSeverity = "critical"
+	}
+	if err := validMinSeverity("opsgenie", config.MinSeverity); err != nil {
+		return nil, err
+	}
+	if config.AlertsURL == "" {
+		region := config.Region
+		if region == "" {
+			region = "us"
+		}
+		url, ok := opsgenieAlertsURLs[region]
+		if !ok {
+			return nil, fmt.Errorf("opsgenie: invalid region %q (us|eu)", config.Region)
+		}
+		config.AlertsURL = url
+	}
+
+	return &Opsgenie{
+		config: config,
+		client: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+// opsgeniePriority maps drift severities onto Opsgenie priorities
+var opsgeniePriority = map[string]string{
+	"critical": "P1",
+	"high":     "P2",
+	"medium":   "P3",
+	"low":      "P4",
+}
+
+// Notify creates an alert for every drift at or above the minimum severity
+func (o *Opsgenie) Notify(ctx context.Context, r *report.Report) error {
+	responders := make([]map[string]string, 0, len(o.config.Responders))
+	for _, team := range o.config.Responders {
+		responders = append(responders, map[string]string{"name": team, "type": "team"})
+	}
+
+	headers := map[string]string{"Authorization": "GenieKey " + o.config.APIKey}
+	for _, f := range findings(r, o.config.MinSeverity) {
+		message := f.title()
+		// Opsgenie truncates messages at 130 characters
+		if len(message) > 130 {
+			message = message[:127] + "..."
+		}
+
+		alert := map[string]interface{}{
+			"message":     message,
+			"alias":       f.dedupKey(),
+			"description": fmt.Sprintf("%s\nField: %s\nExpected: %s\nActual: %s", r.Title, f.drift.Field, f.drift.Expected, f.drift.Actual),
+			"priority":    opsgeniePriority[f.drift.Severity],
+			"source":      "drift-analysis-cli",
+			"entity":      f.resource,
+			"tags":        []string{"drift", f.drift.Severity},
+			"details": map[string]string{
+				"resource_type": f.resourceType,
+				"field":         f.drift.Field,
+			},
+		}
+		if o.snoozer != nil {
+			alert["details"].(map[string]string)["snooze"] = o.snoozer.Instruction(f)
+		}
+		if len(responders) > 0 {
+			alert["responders"] = responders
+		}
+		if err := postJSON(ctx, o.client, "opsgenie", o.config.AlertsURL, headers, alert); err != nil {
+			return err
+		}
+	}
+	return nil
+}
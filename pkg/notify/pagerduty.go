@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+const pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyConfig configures PagerDuty incidents through the Events API v2
+type PagerDutyConfig struct {
+	// RoutingKey is the integration key of an Events API v2 service integration
+	RoutingKey string `yaml:"routing_key"`
+	// MinSeverity is the lowest severity that opens an incident (default: critical)
+	MinSeverity string `yaml:"min_severity,omitempty"`
+	// EventsURL overrides the Events API endpoint
+	EventsURL string `yaml:"events_url,omitempty"`
+}
+
+// PagerDuty triggers one PagerDuty incident per drifted field. Events carry a
+// dedup key per resource and field, so repeated runs update the open incident
+// instead of paging again.
+type PagerDuty struct {
+	config  PagerDutyConfig
+	client  *http.Client
+	snoozer *Snoozer
+}
+
+// NewPagerDuty creates a PagerDuty notifier
+func NewPagerDuty(config PagerDutyConfig) (*PagerDuty, error) {
+	if config.RoutingKey == "" {
+		return nil, fmt.Errorf("pagerduty: routing_key is required")
+	}
+	if config.MinSeverity == "" {
+		config.MinSeverity = "critical"
+	}
+	if err := validMinSeverity("pagerduty", config.MinSeverity); err != nil {
+		return nil, err
+	}
+	if config.EventsURL == "" {
+		config.EventsURL = pagerDutyEventsURL
+	}
+
+	return &PagerDuty{
+		config: config,
+		client: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+// pagerDutySeverity maps drift severities onto PagerDuty event severities
+var pagerDutySeverity = map[string]string{
+	"critical": "critical",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "info",
+}
+
+// Notify triggers an event for every drift at or above the minimum severity
+func (p *PagerDuty) Notify(ctx context.Context, r *report.Report) error {
+	for _, f := range findings(r, p.config.MinSeverity) {
+		details := map[string]interface{}{
+			"field":     f.drift.Field,
+			"expected":  f.drift.Expected,
+			"actual":    f.drift.Actual,
+			"immutable": f.drift.Immutable,
+			"report":    r.Title,
+		}
+		if p.snoozer != nil {
+			details["snooze"] = p.snoozer.Instruction(f)
+		}
+		event := map[string]interface{}{
+			"routing_key":  p.config.RoutingKey,
+			"event_action": "trigger",
+			"dedup_key":    f.dedupKey(),
+			"payload": map[string]interface{}{
+				"summary":        f.title(),
+				"source":         f.resource,
+				"severity":       pagerDutySeverity[f.drift.Severity],
+				"component":      f.resourceType,
+				"class":          "configuration-drift",
+				"custom_details": details,
+			},
+		}
+		if err := postJSON(ctx, p.client, "pagerduty", p.config.EventsURL, nil, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+	"github.com/jessequinn/drift-analysis-cli/pkg/scheduler"
+)
+
+// SinkConfig is a named notification channel that routes deliver to. Exactly
+// one backend must be set.
+type SinkConfig struct {
+	Slack     *SlackConfig     `yaml:"slack,omitempty"`
+	PagerDuty *PagerDutyConfig `yaml:"pagerduty,omitempty"`
+	Opsgenie  *OpsgenieConfig  `yaml:"opsgenie,omitempty"`
+	Webhook   *WebhookConfig   `yaml:"webhook,omitempty"`
+	// Digest batches routed findings and sends the latest state at most once
+	// per schedule (e.g. @weekly) instead of on every scan
+	Digest string `yaml:"digest,omitempty"`
+}
+
+// RouteConfig maps findings to sinks. Empty matchers match everything, and a
+// finding is delivered to the sinks of every route it matches.
+type RouteConfig struct {
+	ResourceTypes []string          `yaml:"resource_types,omitempty"`
+	Severities    []string          `yaml:"severities,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	// Owners matches findings attributed to any of these services
+	Owners []string `yaml:"owners,omitempty"`
+	Sinks  []string `yaml:"sinks"`
+}
+
+// matches reports whether a drift on a resource is selected by the route
+func (rc RouteConfig) matches(res report.Resource, drift report.Drift) bool {
+	if len(rc.ResourceTypes) > 0 && !contains(rc.ResourceTypes, res.Type) {
+		return false
+	}
+	if len(rc.Severities) > 0 && !contains(rc.Severities, drift.Severity) {
+		return false
+	}
+	for key, value := range rc.Labels {
+		if res.Labels[key] != value {
+			return false
+		}
+	}
+	if len(rc.Owners) > 0 && !containsAny(rc.Owners, drift.Owners) {
+		return false
+	}
+	return true
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, c := range candidates {
+		if contains(values, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Router delivers each finding to the sinks its routes select
+type Router struct {
+	routes []RouteConfig
+	sinks  map[string]Notifier
+	// names are the sink names in a stable delivery order
+	names []string
+}
+
+// NewRouter builds the sinks and validates the routes against them. A
+// non-nil snoozer adds snooze instructions to the sinks' messages.
+func NewRouter(sinks map[string]SinkConfig, routes []RouteConfig, snoozer *Snoozer) (*Router, error) {
+	r := &Router{routes: routes, sinks: make(map[string]Notifier)}
+	for name, config := range sinks {
+		sink, err := newSink(name, config, snoozer)
+		if err != nil {
+			return nil, err
+		}
+		r.sinks[name] = sink
+		r.names = append(r.names, name)
+	}
+	sort.Strings(r.names)
+
+	for i, route := range routes {
+		if len(route.Sinks) == 0 {
+			return nil, fmt.Errorf("notifications: route %d has no sinks", i+1)
+		}
+		for _, name := range route.Sinks {
+			if _, ok := r.sinks[name]; !ok {
+				return nil, fmt.Errorf("notifications: route %d references unknown sink %q", i+1, name)
+			}
+		}
+		for _, severity := range route.Severities {
+			if report.SeverityRank(severity) == 0 {
+				return nil, fmt.Errorf("notifications: route %d has invalid severity %q", i+1, severity)
+			}
+		}
+	}
+	return r, nil
+}
+
+// newSink builds the notifier for one sink. Routes decide which severities a
+// sink receives, so the backend's min_severity defaults to low.
+func newSink(name string, config SinkConfig, snoozer *Snoozer) (Notifier, error) {
+	var sinks []Notifier
+	if config.Slack != nil {
+		c := *config.Slack
+		if c.MinSeverity == "" {
+			c.MinSeverity = "low"
+		}
+		slack, err := NewSlack(c)
+		if err != nil {
+			return nil, fmt.Errorf("sink %s: %w", name, err)
+		}
+		slack.snoozer = snoozer
+		sinks = append(sinks, slack)
+	}
+	if config.PagerDuty != nil {
+		c := *config.PagerDuty
+		if c.MinSeverity == "" {
+			c.MinSeverity = "low"
+		}
+		pagerDuty, err := NewPagerDuty(c)
+		if err != nil {
+			return nil, fmt.Errorf("sink %s: %w", name, err)
+		}
+		pagerDuty.snoozer = snoozer
+		sinks = append(sinks, pagerDuty)
+	}
+	if config.Opsgenie != nil {
+		c := *config.Opsgenie
+		if c.MinSeverity == "" {
+			c.MinSeverity = "low"
+		}
+		opsgenie, err := NewOpsgenie(c)
+		if err != nil {
+			return nil, fmt.Errorf("sink %s: %w", name, err)
+		}
+		opsgenie.snoozer = snoozer
+		sinks = append(sinks, opsgenie)
+	}
+	if config.Webhook != nil {
+		c := *config.Webhook
+		if c.MinSeverity == "" {
+			c.MinSeverity = "low"
+		}
+		webhook, err := NewWebhook(c)
+		if err != nil {
+			return nil, fmt.Errorf("sink %s: %w", name, err)
+		}
+		webhook.snoozer = snoozer
+		sinks = append(sinks, webhook)
+	}
+	if len(sinks) != 1 {
+		return nil, fmt.Errorf("sink %s: configure exactly one of slack, pagerduty, opsgenie and webhook", name)
+	}
+
+	if config.Digest == "" {
+		return sinks[0], nil
+	}
+	interval, err := scheduler.ParseSchedule(config.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("sink %s: digest: %w", name, err)
+	}
+	return NewDigest(sinks[0], interval, time.Now), nil
+}
+
+// Notify sends every sink the drifts routed to it. Each sink receives all
+// resources of the report, with only its routed drifts, so digests also learn
+// about resources that no longer drift.
+func (r *Router) Notify(ctx context.Context, rep *report.Report) error {
+	var errs []error
+	for _, name := range r.names {
+		if err := r.sinks[name].Notify(ctx, r.routed(rep, name)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// routed returns a copy of the report with only the drifts routed to a sink
+func (r *Router) routed(rep *report.Report, sink string) *report.Report {
+	out := &report.Report{Title: rep.Title, Timestamp: rep.Timestamp}
+	for _, res := range rep.Resources {
+		routed := res
+		routed.Drifts = nil
+		for _, drift := range res.Drifts {
+			if r.routesTo(res, drift, sink) {
+				routed.Drifts = append(routed.Drifts, drift)
+			}
+		}
+		out.Resources = append(out.Resources, routed)
+	}
+	return out
+}
+
+// routesTo reports whether any route sends the drift to the sink
+func (r *Router) routesTo(res report.Resource, drift report.Drift, sink string) bool {
+	for _, route := range r.routes {
+		if contains(route.Sinks, sink) && route.matches(res, drift) {
+			return true
+		}
+	}
+	return false
+}
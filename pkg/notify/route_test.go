@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// recorder captures the reports a sink receives
+type recorder struct {
+	reports []*report.Report
+}
+
+func (r *recorder) Notify(ctx context.Context, rep *report.Report) error {
+	r.reports = append(r.reports, rep)
+	return nil
+}
+
+// drifts returns the fields of every drift received, as resource:field
+func (r *recorder) drifts() []string {
+	var fields []string
+	for _, rep := range r.reports {
+		for _, res := range rep.Resources {
+			for _, d := range res.Drifts {
+				fields = append(fields, res.Name+":"+d.Field)
+			}
+		}
+	}
+	return fields
+}
+
+func routingReport() *report.Report {
+	return &report.Report{Resources: []report.Resource{
+		{Type: "Cloud SQL", Name: "db-prod", Labels: map[string]string{"env": "prod"}, Drifts: []report.Drift{
+			{Field: "settings.backup_enabled", Severity: "critical"},
+			{Field: "tier", Severity: "low"},
+		}},
+		{Type: "Cloud SQL", Name: "db-dev", Labels: map[string]string{"env": "dev"}, Drifts: []report.Drift{
+			{Field: "settings.backup_enabled", Severity: "critical"},
+		}},
+		{Type: "GKE Cluster", Name: "gke-1", Drifts: []report.Drift{
+			{Field: "release_channel", Severity: "low"},
+			{Field: "network_policy", Severity: "high"},
+		}},
+	}}
+}
+
+func TestRouterNotify(t *testing.T) {
+	pager, digest, slack := &recorder{}, &recorder{}, &recorder{}
+	router := &Router{
+		sinks: map[string]Notifier{"pager": pager, "digest": digest, "slack": slack},
+		names: []string{"digest", "pager", "slack"},
+		routes: []RouteConfig{
+			{ResourceTypes: []string{"Cloud SQL"}, Severities: []string{"critical"}, Labels: map[string]string{"env": "prod"}, Sinks: []string{"pager", "slack"}},
+			{ResourceTypes: []string{"GKE Cluster"}, Severities: []string{"low"}, Sinks: []string{"digest"}},
+			{Severities: []string{"high", "critical"}, Sinks: []string{"slack"}},
+		},
+	}
+
+	if err := router.Notify(context.Background(), routingReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sink *recorder
+		want string
+	}{
+		{pager, "db-prod:settings.backup_enabled"},
+		{digest, "gke-1:release_channel"},
+		// Matching several routes delivers a drift once
+		{slack, "db-prod:settings.backup_enabled,db-dev:settings.backup_enabled,gke-1:network_policy"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.sink.drifts(), ","); got != tt.want {
+			t.Errorf("routed drifts = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestRouteOwners(t *testing.T) {
+	route := RouteConfig{Owners: []string{"checkout"}, Sinks: []string{"slack"}}
+	res := report.Resource{Type: "Cloud SQL", Name: "db-prod"}
+
+	if !route.matches(res, report.Drift{Field: "required_databases", Owners: []string{"billing", "checkout"}}) {
+		t.Error("route should match a drift owned by checkout")
+	}
+	if route.matches(res, report.Drift{Field: "required_databases", Owners: []string{"billing"}}) {
+		t.Error("route should not match a drift owned by another service")
+	}
+	if route.matches(res, report.Drift{Field: "tier"}) {
+		t.Error("route should not match a drift without owners")
+	}
+}
+
+func TestNewRouterValidation(t *testing.T) {
+	sinks := map[string]SinkConfig{"slack": {Slack: &SlackConfig{WebhookURL: "https://hooks.example.com"}}}
+
+	tests := []struct {
+		name    string
+		sinks   map[string]SinkConfig
+		routes  []RouteConfig
+		wantErr string
+	}{
+		{"unknown sink", sinks, []RouteConfig{{Sinks: []string{"pager"}}}, `unknown sink "pager"`},
+		{"no sinks", sinks, []RouteConfig{{Severities: []string{"low"}}}, "has no sinks"},
+		{"bad severity", sinks, []RouteConfig{{Severities: []string{"urgent"}, Sinks: []string{"slack"}}}, `invalid severity "urgent"`},
+		{"two backends", map[string]SinkConfig{"both": {
+			Slack:     &SlackConfig{WebhookURL: "https://hooks.example.com"},
+			PagerDuty: &PagerDutyConfig{RoutingKey: "key"},
+		}}, nil, "exactly one"},
+		{"bad digest", map[string]SinkConfig{"weekly": {
+			Slack:  &SlackConfig{WebhookURL: "https://hooks.example.com"},
+			Digest: "fortnightly",
+		}}, nil, "digest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRouter(tt.sinks, tt.routes, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewRouter() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	router, err := NewRouter(map[string]SinkConfig{"weekly": {
+		Slack:  &SlackConfig{WebhookURL: "https://hooks.example.com"},
+		Digest: "@weekly",
+	}}, []RouteConfig{{Sinks: []string{"weekly"}}}, nil)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	if _, ok := router.sinks["weekly"].(*Digest); !ok {
+		t.Errorf("digest sink is %T, want *Digest", router.sinks["weekly"])
+	}
+}
+
+func TestDigest(t *testing.T) {
+	now := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	next := &recorder{}
+	digest := NewDigest(next, 7*24*time.Hour, func() time.Time { return now })
+	ctx := context.Background()
+
+	first := &report.Report{Resources: []report.Resource{
+		{Type: "GKE Cluster", Name: "gke-1", Drifts: []report.Drift{{Field: "release_channel", Severity: "low"}}},
+		{Type: "GKE Cluster", Name: "gke-2", Drifts: []report.Drift{{Field: "release_channel", Severity: "low"}}},
+	}}
+	if err := digest.Notify(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.reports) != 0 {
+		t.Fatal("digest sent before it was due")
+	}
+
+	// gke-2 was fixed in a later scan, so the digest only reports gke-1
+	now = now.Add(7 * 24 * time.Hour)
+	second := &report.Report{Resources: []report.Resource{{Type: "GKE Cluster", Name: "gke-2"}}}
+	if err := digest.Notify(ctx, second); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(next.drifts(), ","); got != "gke-1:release_channel" {
+		t.Errorf("digest drifts = %s, want gke-1:release_channel", got)
+	}
+
+	if err := digest.Notify(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+	if len(next.reports) != 1 {
+		t.Errorf("digest sent %d times, want once per interval", len(next.reports))
+	}
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// SlackConfig configures a Slack incoming webhook
+type SlackConfig struct {
+	WebhookURL string `yaml:"webhook_url"`
+	Channel    string `yaml:"channel,omitempty"`
+	// MinSeverity is the lowest severity included in messages (default: high)
+	MinSeverity string `yaml:"min_severity,omitempty"`
+}
+
+// Slack posts drift summaries to a Slack incoming webhook
+type Slack struct {
+	config  SlackConfig
+	client  *http.Client
+	snoozer *Snoozer
+}
+
+// NewSlack creates a Slack notifier
+func NewSlack(config SlackConfig) (*Slack, error) {
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("slack: webhook_url is required")
+	}
+	if config.MinSeverity == "" {
+		config.MinSeverity = "high"
+	}
+	if err := validMinSeverity("slack", config.MinSeverity); err != nil {
+		return nil, err
+	}
+
+	return &Slack{
+		config: config,
+		client: &http.Client{Timeout: defaultTimeout},
+	}, nil
+}
+
+// Notify posts a summary of the report; nothing is sent when no drift meets the minimum severity
+func (s *Slack) Notify(ctx context.Context, r *report.Report) error {
+	found := findings(r, s.config.MinSeverity)
+	if len(found) == 0 {
+		return nil
+	}
+
+	payload := map[string]string{"text": summarize(r, found, s.snoozer)}
+	if s.config.Channel != "" {
+		payload["channel"] = s.config.Channel
+	}
+	return postJSON(ctx, s.client, "slack", s.config.WebhookURL, nil, payload)
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func testReport() *report.Report {
+	return &report.Report{
+		Title: "Cloud SQL Drift Analysis",
+		Resources: []report.Resource{
+			{
+				Type:    "Cloud SQL",
+				Project: "prod",
+				Name:    "db-1",
+				Drifts: []report.Drift{
+					{Field: "settings.backup_enabled", Expected: "true", Actual: "false", Severity: "critical"},
+					{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-custom-2-8192", Severity: "medium"},
+				},
+			},
+			{Type: "Cloud SQL", Project: "prod", Name: "db-2"},
+		},
+	}
+}
+
+func TestSlackNotify(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	slack, err := NewSlack(SlackConfig{WebhookURL: server.URL, Channel: "#drift"})
+	if err != nil {
+		t.Fatalf("NewSlack() error = %v", err)
+	}
+	if err := slack.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	if got["channel"] != "#drift" {
+		t.Errorf("channel = %q, want #drift", got["channel"])
+	}
+	if !strings.Contains(got["text"], "prod/db-1 settings.backup_enabled") {
+		t.Errorf("text missing critical drift: %q", got["text"])
+	}
+	// Default minimum severity is high, so the medium tier drift is left out
+	if strings.Contains(got["text"], "tier") {
+		t.Errorf("text should not include medium drift: %q", got["text"])
+	}
+}
+
+func TestSlackNotifySkipsBelowMinSeverity(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	r := testReport()
+	r.Resources[0].Drifts = r.Resources[0].Drifts[1:]
+
+	slack, err := NewSlack(SlackConfig{WebhookURL: server.URL})
+	if err != nil {
+		t.Fatalf("NewSlack() error = %v", err)
+	}
+	if err := slack.Notify(context.Background(), r); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+	if called {
+		t.Error("webhook called for report without high severity drift")
+	}
+}
+
+func TestSlackNotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	slack, err := NewSlack(SlackConfig{WebhookURL: server.URL, MinSeverity: "low"})
+	if err != nil {
+		t.Fatalf("NewSlack() error = %v", err)
+	}
+	err = slack.Notify(context.Background(), testReport())
+	if err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Notify() error = %v, want webhook error", err)
+	}
+}
+
+func TestNewSlackValidation(t *testing.T) {
+	if _, err := NewSlack(SlackConfig{}); err == nil {
+		t.Error("NewSlack() without webhook_url should fail")
+	}
+	if _, err := NewSlack(SlackConfig{WebhookURL: "https://example.com", MinSeverity: "urgent"}); err == nil {
+		t.Error("NewSlack() with invalid min_severity should fail")
+	}
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Snooze defaults
+const (
+	defaultSnoozeDuration = 24 * time.Hour
+	defaultSnoozeTokenTTL = 7 * 24 * time.Hour
+)
+
+// SnoozeConfig adds a signed snooze command to every finding in a
+// notification. Redeeming it with `ack` suppresses notifications for that
+// finding until the snooze expires.
+type SnoozeConfig struct {
+	// Secret signs tokens; defaults to the DRIFT_SNOOZE_SECRET environment variable
+	Secret string `yaml:"secret,omitempty"`
+	// Duration is how long a finding stays snoozed (default: 24h)
+	Duration string `yaml:"duration,omitempty"`
+	// TokenTTL is how long a token can be redeemed after it was sent (default: 168h)
+	TokenTTL string `yaml:"token_ttl,omitempty"`
+	// HistoryDir is the history store, a directory or gs://bucket/prefix,
+	// suppressions are recorded in (default: daemon.history_dir)
+	HistoryDir string `yaml:"history_dir,omitempty"`
+}
+
+// SnoozeClaims are the signed contents of a snooze token
+type SnoozeClaims struct {
+	Key          string        `json:"k"`
+	ResourceType string        `json:"t"`
+	Resource     string        `json:"r"`
+	Field        string        `json:"f"`
+	Duration     time.Duration `json:"d"`
+	Expires      int64         `json:"exp"`
+}
+
+// Snoozer signs and verifies snooze tokens
+type Snoozer struct {
+	config   SnoozeConfig
+	secret   []byte
+	duration time.Duration
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewSnoozer creates a snoozer from the config
+func NewSnoozer(config SnoozeConfig) (*Snoozer, error) {
+	if config.Secret == "" {
+		config.Secret = os.Getenv("DRIFT_SNOOZE_SECRET")
+	}
+	if config.Secret == "" {
+		return nil, fmt.Errorf("snooze: secret is required (or set DRIFT_SNOOZE_SECRET)")
+	}
+
+	s := &Snoozer{
+		config:   config,
+		secret:   []byte(config.Secret),
+		duration: defaultSnoozeDuration,
+		ttl:      defaultSnoozeTokenTTL,
+		now:      time.Now,
+	}
+	var err error
+	if config.Duration != "" {
+		if s.duration, err = time.ParseDuration(config.Duration); err != nil || s.duration <= 0 {
+			return nil, fmt.Errorf("snooze: invalid duration %q", config.Duration)
+		}
+	}
+	if config.TokenTTL != "" {
+		if s.ttl, err = time.ParseDuration(config.TokenTTL); err != nil || s.ttl <= 0 {
+			return nil, fmt.Errorf("snooze: invalid token_ttl %q", config.TokenTTL)
+		}
+	}
+	return s, nil
+}
+
+// HistoryDir returns the configured history store for suppressions, or ""
+// to use the daemon's
+func (s *Snoozer) HistoryDir() string {
+	return s.config.HistoryDir
+}
+
+// Token signs a snooze token for a finding
+func (s *Snoozer) Token(f finding) string {
+	claims := SnoozeClaims{
+		Key:          f.dedupKey(),
+		ResourceType: f.resourceType,
+		Resource:     f.resource,
+		Field:        f.drift.Field,
+		Duration:     s.duration,
+		Expires:      s.now().Add(s.ttl).Unix(),
+	}
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded))
+}
+
+// Instruction tells the reader how to snooze a finding: the ack command
+func (s *Snoozer) Instruction(f finding) string {
+	return "drift-analysis-cli ack --token " + s.Token(f)
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func (s *Snoozer) Verify(token string) (*SnoozeClaims, error) {
+	encoded, signature, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return nil, fmt.Errorf("snooze: malformed token")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, s.sign(encoded)) {
+		return nil, fmt.Errorf("snooze: invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("snooze: malformed token")
+	}
+	var claims SnoozeClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("snooze: malformed token: %w", err)
+	}
+	if expires := time.Unix(claims.Expires, 0); s.now().After(expires) {
+		return nil, fmt.Errorf("snooze: token expired at %s", expires.UTC().Format(time.RFC3339))
+	}
+	return &claims, nil
+}
+
+// Until returns when a snooze redeemed at now ends: after the signed
+// duration, or after requested when it is set. The token holder may shorten
+// the snooze but not extend it.
+func (c *SnoozeClaims) Until(now time.Time, requested time.Duration) (time.Time, error) {
+	switch {
+	case requested < 0:
+		return time.Time{}, fmt.Errorf("snooze: invalid duration %s", requested)
+	case requested > c.Duration:
+		return time.Time{}, fmt.Errorf("snooze: %s exceeds the %s signed in the token", requested, c.Duration)
+	case requested == 0:
+		requested = c.Duration
+	}
+	return now.Add(requested), nil
+}
+
+func (s *Snoozer) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// Unsuppressed returns a copy of the report without the drifts whose
+// notification key is in suppressed
+func Unsuppressed(r *report.Report, suppressed map[string]bool) *report.Report {
+	if len(suppressed) == 0 {
+		return r
+	}
+
+	out := &report.Report{Title: r.Title, Timestamp: r.Timestamp}
+	for _, res := range r.Resources {
+		kept := res
+		kept.Drifts = nil
+		for _, drift := range res.Drifts {
+			if !suppressed[newFinding(res, drift).dedupKey()] {
+				kept.Drifts = append(kept.Drifts, drift)
+			}
+		}
+		out.Resources = append(out.Resources, kept)
+	}
+	return out
+}
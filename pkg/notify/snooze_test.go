@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnoozeToken(t *testing.T) {
+	snoozer, err := NewSnoozer(SnoozeConfig{Secret: "s3cret", Duration: "48h"})
+	if err != nil {
+		t.Fatalf("NewSnoozer() error = %v", err)
+	}
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	snoozer.now = func() time.Time { return now }
+
+	res := testReport().Resources[0]
+	f := newFinding(res, res.Drifts[0])
+	token := snoozer.Token(f)
+
+	claims, err := snoozer.Verify(token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if claims.Key != f.dedupKey() || claims.Resource != "prod/db-1" || claims.Duration != 48*time.Hour {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if !strings.HasPrefix(snoozer.Instruction(f), "drift-analysis-cli ack --token ") {
+		t.Errorf("Instruction() = %q, want the ack command", snoozer.Instruction(f))
+	}
+
+	other, _ := NewSnoozer(SnoozeConfig{Secret: "other"})
+	if _, err := other.Verify(token); err == nil || !strings.Contains(err.Error(), "signature") {
+		t.Errorf("Verify() with another secret error = %v, want signature error", err)
+	}
+	if _, err := snoozer.Verify(token[:len(token)-2]); err == nil {
+		t.Error("Verify() accepted a truncated token")
+	}
+
+	now = now.Add(8 * 24 * time.Hour)
+	if _, err := snoozer.Verify(token); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("Verify() after token_ttl error = %v, want expired", err)
+	}
+
+	if _, err := NewSnoozer(SnoozeConfig{}); err == nil {
+		t.Error("NewSnoozer() without a secret should fail")
+	}
+}
+
+func TestUnsuppressed(t *testing.T) {
+	r := testReport()
+	key := newFinding(r.Resources[0], r.Resources[0].Drifts[0]).dedupKey()
+
+	filtered := Unsuppressed(r, map[string]bool{key: true})
+	if len(filtered.Resources) != 2 || len(filtered.Resources[0].Drifts) != 1 || filtered.Resources[0].Drifts[0].Field != "tier" {
+		t.Errorf("Unsuppressed() = %+v", filtered.Resources)
+	}
+	if len(r.Resources[0].Drifts) != 2 {
+		t.Error("Unsuppressed() modified the original report")
+	}
+}
+
+func TestSnoozeClaimsUntil(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	claims := &SnoozeClaims{Duration: 48 * time.Hour}
+
+	if until, err := claims.Until(now, 0); err != nil || !until.Equal(now.Add(48*time.Hour)) {
+		t.Errorf("Until(0) = %v, %v, want the signed duration", until, err)
+	}
+	if until, err := claims.Until(now, 2*time.Hour); err != nil || !until.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("Until(2h) = %v, %v, want a shorter snooze", until, err)
+	}
+	if _, err := claims.Until(now, 72*time.Hour); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Until(72h) error = %v, want exceeds", err)
+	}
+	if _, err := claims.Until(now, -time.Hour); err == nil {
+		t.Error("Until() accepted a negative duration")
+	}
+}
+
+func TestSummarizeSnooze(t *testing.T) {
+	snoozer, _ := NewSnoozer(SnoozeConfig{Secret: "s3cret"})
+	r := testReport()
+
+	text := summarize(r, findings(r, "low"), snoozer)
+	if strings.Count(text, "snooze: `drift-analysis-cli ack --token ") != 2 {
+		t.Errorf("summary should carry a snooze command per finding:\n%s", text)
+	}
+}
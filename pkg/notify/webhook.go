@@ -0,0 +1,225 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Webhook delivery modes
+const (
+	// WebhookPerRun sends one request per report listing every finding
+	WebhookPerRun = "run"
+	// WebhookPerFinding sends one request per finding
+	WebhookPerFinding = "finding"
+)
+
+// WebhookConfig configures a generic HTTP webhook. The body is rendered from
+// a Go template; without one the template data is sent as JSON.
+type WebhookConfig struct {
+	URL string `yaml:"url"`
+	// Method is the HTTP method (default POST)
+	Method  string            `yaml:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// ContentType is sent unless a header overrides it (default application/json)
+	ContentType string `yaml:"content_type,omitempty"`
+	// Body is a text/template rendered with WebhookRun or WebhookFinding
+	Body string `yaml:"body,omitempty"`
+	// Mode is run (one request per report, default) or finding (one request
+	// per finding)
+	Mode string `yaml:"mode,omitempty"`
+	// MinSeverity is the lowest severity sent (default: high per run,
+	// critical per finding)
+	MinSeverity string `yaml:"min_severity,omitempty"`
+}
+
+// WebhookFinding is a drift as exposed to webhook templates
+type WebhookFinding struct {
+	ResourceType string            `json:"resource_type"`
+	Project      string            `json:"project,omitempty"`
+	Resource     string            `json:"resource"`
+	Location     string            `json:"location,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Field        string            `json:"field"`
+	Expected     string            `json:"expected"`
+	Actual       string            `json:"actual"`
+	Severity     string            `json:"severity"`
+	Immutable    bool              `json:"immutable,omitempty"`
+	Owners       []string          `json:"owners,omitempty"`
+	// DedupKey identifies the finding across runs
+	DedupKey string `json:"dedup_key"`
+	// Snooze is the snooze command when snoozing is configured
+	Snooze string `json:"snooze,omitempty"`
+}
+
+// WebhookRun is the template data of a per-run webhook
+type WebhookRun struct {
+	Title     string           `json:"title"`
+	Timestamp time.Time        `json:"timestamp"`
+	Critical  int              `json:"critical"`
+	High      int              `json:"high"`
+	Medium    int              `json:"medium"`
+	Low       int              `json:"low"`
+	Findings  []WebhookFinding `json:"findings"`
+}
+
+// WebhookEvent is the template data of a per-finding webhook
+type WebhookEvent struct {
+	Title     string         `json:"title"`
+	Timestamp time.Time      `json:"timestamp"`
+	Finding   WebhookFinding `json:"finding"`
+}
+
+// webhookFuncs are the functions available to body templates
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// Webhook sends drift findings to an arbitrary HTTP endpoint
+type Webhook struct {
+	config  WebhookConfig
+	body    *template.Template
+	client  *http.Client
+	snoozer *Snoozer
+}
+
+// NewWebhook creates a webhook notifier, parsing its body template
+func NewWebhook(config WebhookConfig) (*Webhook, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook: url is required")
+	}
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+	switch config.Mode {
+	case "":
+		config.Mode = WebhookPerRun
+	case WebhookPerRun, WebhookPerFinding:
+	default:
+		return nil, fmt.Errorf("webhook: invalid mode %q (run|finding)", config.Mode)
+	}
+	if config.MinSeverity == "" {
+		config.MinSeverity = "high"
+		if config.Mode == WebhookPerFinding {
+			config.MinSeverity = "critical"
+		}
+	}
+	if err := validMinSeverity("webhook", config.MinSeverity); err != nil {
+		return nil, err
+	}
+
+	w := &Webhook{
+		config: config,
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+	if config.Body != "" {
+		body, err := template.New("webhook").Funcs(webhookFuncs).Option("missingkey=error").Parse(config.Body)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: invalid body template: %w", err)
+		}
+		w.body = body
+	}
+	return w, nil
+}
+
+// Notify sends the findings at or above the minimum severity, in one request
+// or one request per finding; nothing is sent when there are none
+func (w *Webhook) Notify(ctx context.Context, r *report.Report) error {
+	found := w.findings(r)
+	if len(found) == 0 {
+		return nil
+	}
+
+	if w.config.Mode == WebhookPerFinding {
+		for _, f := range found {
+			if err := w.send(ctx, WebhookEvent{Title: r.Title, Timestamp: r.Timestamp, Finding: f}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	run := WebhookRun{Title: r.Title, Timestamp: r.Timestamp, Findings: found}
+	for _, f := range found {
+		switch f.Severity {
+		case "critical":
+			run.Critical++
+		case "high":
+			run.High++
+		case "medium":
+			run.Medium++
+		case "low":
+			run.Low++
+		}
+	}
+	return w.send(ctx, run)
+}
+
+// findings returns the report's drifts at or above the minimum severity
+func (w *Webhook) findings(r *report.Report) []WebhookFinding {
+	minRank := report.SeverityRank(w.config.MinSeverity)
+
+	var found []WebhookFinding
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			if report.SeverityRank(d.Severity) < minRank {
+				continue
+			}
+			f := newFinding(res, d)
+			wf := WebhookFinding{
+				ResourceType: res.Type,
+				Project:      res.Project,
+				Resource:     res.Name,
+				Location:     res.Location,
+				Labels:       res.Labels,
+				Field:        d.Field,
+				Expected:     d.Expected,
+				Actual:       d.Actual,
+				Severity:     d.Severity,
+				Immutable:    d.Immutable,
+				Owners:       d.Owners,
+				DedupKey:     f.dedupKey(),
+			}
+			if w.snoozer != nil {
+				wf.Snooze = w.snoozer.Instruction(f)
+			}
+			found = append(found, wf)
+		}
+	}
+	return found
+}
+
+// send renders the body for data and makes the request
+func (w *Webhook) send(ctx context.Context, data interface{}) error {
+	var body []byte
+	if w.body == nil {
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("webhook: failed to encode message: %w", err)
+		}
+		body = encoded
+	} else {
+		var buf bytes.Buffer
+		if err := w.body.Execute(&buf, data); err != nil {
+			return fmt.Errorf("webhook: failed to render body: %w", err)
+		}
+		body = buf.Bytes()
+	}
+	return send(ctx, w.client, "webhook", w.config.Method, w.config.URL, w.config.ContentType, w.config.Headers, body)
+}
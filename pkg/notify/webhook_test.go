@@ -0,0 +1,105 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebhookPerRun(t *testing.T) {
+	var bodies []string
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		headers = r.Header
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(WebhookConfig{
+		URL:         server.URL,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ContentType: "text/plain",
+		Body:        `{{.Title}}: {{.Critical}} critical{{range .Findings}} {{.Resource}}/{{.Field}}={{json .Actual}}{{end}}`,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	if err := webhook.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	// The default minimum severity per run is high, so the medium drift is left out
+	if len(bodies) != 1 {
+		t.Fatalf("got %d requests, want 1", len(bodies))
+	}
+	if want := `Cloud SQL Drift Analysis: 1 critical db-1/settings.backup_enabled="false"`; bodies[0] != want {
+		t.Errorf("body = %q, want %q", bodies[0], want)
+	}
+	if headers.Get("Authorization") != "Bearer token" || headers.Get("Content-Type") != "text/plain" {
+		t.Errorf("headers = %v", headers)
+	}
+}
+
+func TestWebhookPerFinding(t *testing.T) {
+	var events []WebhookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event WebhookEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("failed to decode event: %v", err)
+		}
+		events = append(events, event)
+	}))
+	defer server.Close()
+
+	webhook, err := NewWebhook(WebhookConfig{URL: server.URL, Mode: WebhookPerFinding, MinSeverity: "medium"})
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	if err := webhook.Notify(context.Background(), testReport()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	// Without a body template the template data is sent as JSON
+	if len(events) != 2 {
+		t.Fatalf("got %d requests, want 2", len(events))
+	}
+	f := events[1].Finding
+	if f.Field != "tier" || f.Severity != "medium" || f.Project != "prod" {
+		t.Errorf("finding = %+v", f)
+	}
+	if want := "drift-analysis/Cloud SQL/prod/db-1/tier"; f.DedupKey != want {
+		t.Errorf("dedup_key = %s, want %s", f.DedupKey, want)
+	}
+}
+
+func TestNewWebhookErrors(t *testing.T) {
+	tests := []struct {
+		config WebhookConfig
+		want   string
+	}{
+		{WebhookConfig{}, "url is required"},
+		{WebhookConfig{URL: "http://x", Mode: "hourly"}, "invalid mode"},
+		{WebhookConfig{URL: "http://x", MinSeverity: "urgent"}, "invalid min_severity"},
+		{WebhookConfig{URL: "http://x", Body: "{{.Title"}, "invalid body template"},
+	}
+	for _, tt := range tests {
+		if _, err := NewWebhook(tt.config); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NewWebhook(%+v) error = %v, want %q", tt.config, err, tt.want)
+		}
+	}
+
+	// Fields missing from the template data fail the request instead of
+	// sending a partial body
+	webhook, err := NewWebhook(WebhookConfig{URL: "http://127.0.0.1:0", Body: "{{.Finding.Field}}"})
+	if err != nil {
+		t.Fatalf("NewWebhook() error = %v", err)
+	}
+	if err := webhook.Notify(context.Background(), testReport()); err == nil || !strings.Contains(err.Error(), "failed to render body") {
+		t.Errorf("Notify() error = %v, want a render error", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Package pipeline runs named sequences of scan, inspect, render and notify
+// steps defined in the config file.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Pipeline is a named sequence of steps. Scan and inspect steps add resources
+// to the pipeline's report; render and notify steps act on everything
+// collected so far.
+type Pipeline struct {
+	Steps []Step `yaml:"steps"`
+	// ContinueOnError runs the remaining steps after a failed one; the
+	// pipeline still fails at the end
+	ContinueOnError bool `yaml:"continue_on_error,omitempty"`
+}
+
+// Step is one pipeline action. Exactly one of Scan, Inspect, Render and
+// Notify must be set.
+type Step struct {
+	// Name labels the step in logs (default: the action)
+	Name string `yaml:"name,omitempty"`
+	// Scan analyzes every baseline of a resource type: sql or gke
+	Scan    string       `yaml:"scan,omitempty"`
+	Inspect *InspectStep `yaml:"inspect,omitempty"`
+	Render  *RenderStep  `yaml:"render,omitempty"`
+	Notify  *NotifyStep  `yaml:"notify,omitempty"`
+}
+
+// InspectStep inspects database schemas and reports schema baseline drift.
+// Without connections or a name pattern every database connection is
+// inspected.
+type InspectStep struct {
+	Connections []string `yaml:"connections,omitempty"`
+	NamePattern string   `yaml:"name_pattern,omitempty"`
+}
+
+// Scope returns the connections the step inspects
+func (s InspectStep) Scope() analyzer.NameScope {
+	return analyzer.NameScope{Names: s.Connections, Pattern: s.NamePattern}
+}
+
+// RenderStep writes the report in an output format
+type RenderStep struct {
+	Format string `yaml:"format"`
+	// Path is the output file (default: stdout)
+	Path string `yaml:"path,omitempty"`
+}
+
+// NotifyStep sends the report to notification sinks
+type NotifyStep struct {
+	// Sinks names entries of daemon.notifications.sinks; empty sends to every
+	// configured notifier
+	Sinks []string `yaml:"sinks,omitempty"`
+}
+
+// Action returns the kind of step: scan, inspect, render or notify
+func (s Step) Action() string {
+	switch {
+	case s.Scan != "":
+		return "scan"
+	case s.Inspect != nil:
+		return "inspect"
+	case s.Render != nil:
+		return "render"
+	case s.Notify != nil:
+		return "notify"
+	}
+	return ""
+}
+
+// Label returns the step's name, or a description of its action
+func (s Step) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	switch s.Action() {
+	case "scan":
+		return "scan " + s.Scan
+	case "render":
+		if s.Render.Path != "" {
+			return fmt.Sprintf("render %s to %s", s.Render.Format, s.Render.Path)
+		}
+		return "render " + s.Render.Format
+	}
+	return s.Action()
+}
+
+// Validate checks every step of the pipeline. formats lists the supported
+// render formats.
+func (p Pipeline) Validate(formats map[string]bool) error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("no steps")
+	}
+	for i, step := range p.Steps {
+		actions := 0
+		for _, set := range []bool{step.Scan != "", step.Inspect != nil, step.Render != nil, step.Notify != nil} {
+			if set {
+				actions++
+			}
+		}
+		if actions != 1 {
+			return fmt.Errorf("step %d: set exactly one of scan, inspect, render and notify", i+1)
+		}
+
+		switch {
+		case step.Scan != "":
+			if step.Scan != "sql" && step.Scan != "gke" {
+				return fmt.Errorf("step %d: unsupported scan %q (sql|gke)", i+1, step.Scan)
+			}
+		case step.Inspect != nil:
+			if err := step.Inspect.Scope().Validate(); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+		case step.Render != nil:
+			if !formats[step.Render.Format] {
+				return fmt.Errorf("step %d: unsupported render format %q", i+1, step.Render.Format)
+			}
+		}
+	}
+	return nil
+}
+
+// Handlers perform the pipeline's steps
+type Handlers struct {
+	Scan    func(ctx context.Context, kind string) (*report.Report, error)
+	Inspect func(ctx context.Context, step InspectStep) (*report.Report, error)
+	Render  func(r *report.Report, step RenderStep) error
+	Notify  func(ctx context.Context, r *report.Report, step NotifyStep) error
+	// Logf reports progress
+	Logf func(format string, args ...any)
+}
+
+// Run executes the steps in order and returns the collected report
+func (p Pipeline) Run(ctx context.Context, name string, h Handlers) (*report.Report, error) {
+	collected := &report.Report{
+		Title:     "Pipeline " + name,
+		Timestamp: time.Now(),
+		Resources: []report.Resource{},
+	}
+
+	var failed []string
+	for i, step := range p.Steps {
+		if err := ctx.Err(); err != nil {
+			return collected, err
+		}
+		h.Logf("[%d/%d] %s", i+1, len(p.Steps), step.Label())
+
+		err := p.runStep(ctx, step, collected, h)
+		if err == nil {
+			continue
+		}
+		if !p.ContinueOnError {
+			return collected, fmt.Errorf("pipeline %s: %s: %w", name, step.Label(), err)
+		}
+		h.Logf("%s failed: %v", step.Label(), err)
+		failed = append(failed, step.Label())
+	}
+
+	if len(failed) > 0 {
+		return collected, fmt.Errorf("pipeline %s: steps failed: %v", name, failed)
+	}
+	return collected, nil
+}
+
+func (p Pipeline) runStep(ctx context.Context, step Step, collected *report.Report, h Handlers) error {
+	var found *report.Report
+	var err error
+	switch step.Action() {
+	case "scan":
+		found, err = h.Scan(ctx, step.Scan)
+	case "inspect":
+		found, err = h.Inspect(ctx, *step.Inspect)
+	case "render":
+		return h.Render(collected, *step.Render)
+	case "notify":
+		return h.Notify(ctx, collected, *step.Notify)
+	}
+	// A failed step may still return the resources it analyzed
+	if found != nil {
+		collected.Resources = append(collected.Resources, found.Resources...)
+		h.Logf("collected %d resources, %d with drift", len(found.Resources), found.DriftedCount())
+	}
+	return err
+}
@@ -0,0 +1,119 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestValidate(t *testing.T) {
+	formats := map[string]bool{"json": true}
+	tests := []struct {
+		name    string
+		steps   []Step
+		wantErr string
+	}{
+		{"no steps", nil, "no steps"},
+		{"no action", []Step{{Name: "empty"}}, "exactly one"},
+		{"two actions", []Step{{Scan: "sql", Notify: &NotifyStep{}}}, "exactly one"},
+		{"unknown scan", []Step{{Scan: "redis"}}, `unsupported scan "redis"`},
+		{"unknown format", []Step{{Render: &RenderStep{Format: "html"}}}, `unsupported render format "html"`},
+		{"bad pattern", []Step{{Inspect: &InspectStep{NamePattern: "("}}}, "name_pattern"},
+		{"valid", []Step{{Scan: "sql"}, {Inspect: &InspectStep{}}, {Render: &RenderStep{Format: "json"}}, {Notify: &NotifyStep{}}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Pipeline{Steps: tt.steps}.Validate(formats)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// recordingHandlers records the steps run and the resources each render sees
+func recordingHandlers(ran *[]string, scanErr error) Handlers {
+	return Handlers{
+		Scan: func(ctx context.Context, kind string) (*report.Report, error) {
+			*ran = append(*ran, "scan "+kind)
+			return &report.Report{Resources: []report.Resource{{Type: kind, Name: kind + "-1"}}}, scanErr
+		},
+		Inspect: func(ctx context.Context, step InspectStep) (*report.Report, error) {
+			*ran = append(*ran, "inspect")
+			return &report.Report{Resources: []report.Resource{{Type: "Cloud SQL Database", Name: "orders"}}}, nil
+		},
+		Render: func(r *report.Report, step RenderStep) error {
+			*ran = append(*ran, "render "+resourceNames(r))
+			return nil
+		},
+		Notify: func(ctx context.Context, r *report.Report, step NotifyStep) error {
+			*ran = append(*ran, "notify "+resourceNames(r))
+			return nil
+		},
+		Logf: func(string, ...any) {},
+	}
+}
+
+func resourceNames(r *report.Report) string {
+	var names []string
+	for _, res := range r.Resources {
+		names = append(names, res.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRun(t *testing.T) {
+	p := Pipeline{Steps: []Step{
+		{Scan: "sql"},
+		{Render: &RenderStep{Format: "json"}},
+		{Scan: "gke"},
+		{Inspect: &InspectStep{}},
+		{Notify: &NotifyStep{}},
+	}}
+
+	var ran []string
+	r, err := p.Run(context.Background(), "nightly", recordingHandlers(&ran, nil))
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := "scan sql|render sql-1|scan gke|inspect|notify sql-1,gke-1,orders"
+	if got := strings.Join(ran, "|"); got != want {
+		t.Errorf("steps = %s, want %s", got, want)
+	}
+	if r.Title != "Pipeline nightly" || len(r.Resources) != 3 {
+		t.Errorf("Run() report = %q with %d resources", r.Title, len(r.Resources))
+	}
+}
+
+func TestRunFailure(t *testing.T) {
+	steps := []Step{{Scan: "sql"}, {Notify: &NotifyStep{}}}
+	scanErr := errors.New("permission denied")
+
+	var ran []string
+	_, err := Pipeline{Steps: steps}.Run(context.Background(), "nightly", recordingHandlers(&ran, scanErr))
+	if err == nil || !errors.Is(err, scanErr) {
+		t.Errorf("Run() error = %v, want %v", err, scanErr)
+	}
+	if len(ran) != 1 {
+		t.Errorf("steps after failure = %v, want the pipeline to stop", ran)
+	}
+
+	// The partial scan result still reaches later steps
+	ran = nil
+	_, err = Pipeline{Steps: steps, ContinueOnError: true}.Run(context.Background(), "nightly", recordingHandlers(&ran, scanErr))
+	if err == nil || !strings.Contains(err.Error(), "steps failed") {
+		t.Errorf("Run() error = %v, want steps failed", err)
+	}
+	if got := strings.Join(ran, "|"); got != "scan sql|notify sql-1" {
+		t.Errorf("steps = %s, want scan sql|notify sql-1", got)
+	}
+}
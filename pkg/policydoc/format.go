@@ -0,0 +1,115 @@
+package policydoc
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// noValue fills the severity and rationale of fields without a check
+const noValue = "—"
+
+// FormatMarkdown renders the document as Markdown, one table per baseline
+func FormatMarkdown(doc *Document) string {
+	var sb strings.Builder
+	sb.WriteString("# " + doc.Title + "\n\n")
+	sb.WriteString(fmt.Sprintf("Generated from `%s` by drift-analysis-cli. Resources deviating from these values are reported as drift with the listed severity.\n", doc.Source))
+
+	for _, section := range doc.Sections {
+		sb.WriteString(fmt.Sprintf("\n## %s: %s\n\n", section.Kind, section.Name))
+		sb.WriteString("Applies to " + section.Scope + ".")
+		if section.Owner != "" {
+			sb.WriteString(" Owner: " + section.Owner + ".")
+		}
+		if section.Reviewed != "" {
+			sb.WriteString(" Last reviewed " + section.Reviewed + ".")
+		}
+		sb.WriteString("\n\n")
+
+		if len(section.Rules) == 0 {
+			sb.WriteString("No fields are required.\n")
+			continue
+		}
+		sb.WriteString("| Field | Required value | Severity | Rationale |\n")
+		sb.WriteString("|-------|----------------|----------|-----------|\n")
+		for _, rule := range section.Rules {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+				markdownCell(rule.Field), markdownCell(rule.Required),
+				markdownCell(orNoValue(strings.ToUpper(rule.Severity))), markdownCell(rationale(rule))))
+		}
+	}
+	return sb.String()
+}
+
+// markdownCell escapes a value for a table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// rationale is the check description followed by its ID, for `checks show`
+func rationale(rule Rule) string {
+	if rule.Check == "" {
+		return noValue
+	}
+	return fmt.Sprintf("%s (%s)", rule.Rationale, rule.Check)
+}
+
+func orNoValue(s string) string {
+	if s == "" {
+		return noValue
+	}
+	return s
+}
+
+var htmlTemplate = template.Must(template.New("policydoc").Funcs(template.FuncMap{
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"orNoValue": orNoValue,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+code { font-size: 90%; }
+.critical { color: #b00020; font-weight: bold; }
+.high { color: #d35400; font-weight: bold; }
+.medium { color: #b7950b; }
+.low { color: #2471a3; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated from <code>{{.Source}}</code> by drift-analysis-cli. Resources deviating from these values are reported as drift with the listed severity.</p>
+{{- range .Sections}}
+<h2>{{.Kind}}: {{.Name}}</h2>
+<p>Applies to {{.Scope}}.{{if .Owner}} Owner: {{.Owner}}.{{end}}{{if .Reviewed}} Last reviewed {{.Reviewed}}.{{end}}</p>
+{{- if .Rules}}
+<table>
+<tr><th>Field</th><th>Required value</th><th>Severity</th><th>Rationale</th></tr>
+{{- range .Rules}}
+<tr><td><code>{{.Field}}</code></td><td>{{.Required}}</td><td class="{{lower .Severity}}">{{orNoValue (upper .Severity)}}</td><td>{{if .Check}}{{.Rationale}} (<code>{{.Check}}</code>){{else}}—{{end}}</td></tr>
+{{- end}}
+</table>
+{{- else}}
+<p>No fields are required.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// FormatHTML renders the document as a standalone HTML page
+func FormatHTML(doc *Document) (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,393 @@
+// Package policydoc renders the baselines of a config file as a readable
+// configuration standard: every field a baseline sets, the value it
+// requires, the severity drift is reported with and why the check exists.
+// The document is generated from the same config the scanner enforces, so
+// the written standard cannot drift from the tooling.
+package policydoc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/analyzer"
+	"github.com/jessequinn/drift-analysis-cli/pkg/checks"
+	"gopkg.in/yaml.v3"
+)
+
+// Keys of a config block holding adjustments of other fields rather than
+// required values
+const (
+	allowedValuesKey     = "allowed_values"
+	severityOverridesKey = "severity_overrides"
+	rangesKey            = "ranges"
+)
+
+// Block is a section of a baseline holding required values, e.g. config or
+// cluster_config
+type Block struct {
+	// Key is the YAML key of the block in a baseline
+	Key string
+	// PerItem marks a list of named blocks, e.g. GKE nodepools; "{name}" in
+	// the prefixes is replaced by each item's name or name_pattern
+	PerItem bool
+	// IDPrefix maps a YAML path to the check ID reporting it, e.g.
+	// "gke.cluster." for cluster_config.release_channel
+	IDPrefix string
+	// PathPrefixes are tried in order to map a YAML path to the drift field
+	// of a registered check; the first is used for fields without a check
+	PathPrefixes []string
+}
+
+// Kind is a list of baselines in the config file, e.g. sql_baselines
+type Kind struct {
+	Key   string
+	Title string
+	// ResourceType is the resource type checks are registered for; empty
+	// when the resource has no registered checks
+	ResourceType string
+	Blocks       []Block
+}
+
+// Kinds are the baseline lists of the config file in document order
+var Kinds = []Kind{
+	{Key: "sql_baselines", Title: "Cloud SQL", ResourceType: "Cloud SQL", Blocks: []Block{
+		{Key: "config", IDPrefix: "sql.", PathPrefixes: []string{"", "settings."}},
+	}},
+	{Key: "gke_baselines", Title: "GKE", ResourceType: "GKE Cluster", Blocks: []Block{
+		{Key: "cluster_config", IDPrefix: "gke.cluster.", PathPrefixes: []string{"cluster."}},
+		{Key: "nodepool_config", IDPrefix: "gke.nodepool.", PathPrefixes: []string{"nodepool[*]."}},
+		{Key: "nodepools", PerItem: true, IDPrefix: "gke.nodepool.", PathPrefixes: []string{"nodepool[{name}]."}},
+		{Key: "workloads", PathPrefixes: []string{"workloads."}},
+	}},
+	{Key: "redis_baselines", Title: "Memorystore Redis", Blocks: []Block{
+		{Key: "config"},
+	}},
+	{Key: "pubsub_baselines", Title: "Pub/Sub", Blocks: []Block{
+		{Key: "topic_config", PathPrefixes: []string{"topic_config."}},
+		{Key: "subscription_config", PathPrefixes: []string{"subscription_config."}},
+	}},
+	{Key: "vpc_baselines", Title: "VPC", Blocks: []Block{
+		{Key: "config"},
+	}},
+	{Key: "bigquery_baselines", Title: "BigQuery", Blocks: []Block{
+		{Key: "config"},
+	}},
+}
+
+// Rule is one requirement of a baseline
+type Rule struct {
+	// Field is the drift field as shown by `checks list`, or the YAML path in
+	// the baseline for fields without registered checks
+	Field string `json:"field" yaml:"field"`
+	// Required is the required value, or the values or range accepted
+	Required string `json:"required" yaml:"required"`
+	// Severity, Rationale and Check are empty for fields without a
+	// registered check
+	Severity  string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Rationale string `json:"rationale,omitempty" yaml:"rationale,omitempty"`
+	Check     string `json:"check,omitempty" yaml:"check,omitempty"`
+}
+
+// Section is the standard set by one baseline
+type Section struct {
+	Kind string `json:"kind" yaml:"kind"`
+	Name string `json:"name" yaml:"name"`
+	// Scope describes the resources the baseline applies to
+	Scope    string `json:"scope" yaml:"scope"`
+	Owner    string `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Reviewed string `json:"reviewed,omitempty" yaml:"reviewed,omitempty"`
+	Rules    []Rule `json:"rules" yaml:"rules"`
+}
+
+// Document is the configuration standard of a config file
+type Document struct {
+	Title string `json:"title" yaml:"title"`
+	// Source names the config file the document was generated from
+	Source   string    `json:"source" yaml:"source"`
+	Sections []Section `json:"sections" yaml:"sections"`
+}
+
+// baselineHeader holds the keys of a baseline that scope it
+type baselineHeader struct {
+	Name          string                    `yaml:"name"`
+	FilterLabels  map[string]string         `yaml:"filter_labels"`
+	InstanceNames []string                  `yaml:"instance_names"`
+	NamePattern   string                    `yaml:"name_pattern"`
+	Networks      []string                  `yaml:"networks"`
+	Metadata      analyzer.BaselineMetadata `yaml:"metadata"`
+}
+
+// Build renders the baselines of a config file, in the order they are
+// defined, as a document. Fields are taken from the YAML as written, so only
+// the values a baseline actually sets are listed.
+func Build(title, source string, config []byte, kinds []Kind) (*Document, error) {
+	var root map[string]yaml.Node
+	if err := yaml.Unmarshal(config, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	doc := &Document{Title: title, Source: source}
+	for _, kind := range kinds {
+		list, ok := root[kind.Key]
+		if !ok {
+			continue
+		}
+		if list.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("%s: expected a list of baselines", kind.Key)
+		}
+		for i, item := range list.Content {
+			section, err := buildSection(kind, item)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d]: %w", kind.Key, i, err)
+			}
+			doc.Sections = append(doc.Sections, section)
+		}
+	}
+	return doc, nil
+}
+
+// buildSection collects the rules of one baseline
+func buildSection(kind Kind, baseline *yaml.Node) (Section, error) {
+	var header baselineHeader
+	if err := baseline.Decode(&header); err != nil {
+		return Section{}, err
+	}
+	section := Section{
+		Kind:     kind.Title,
+		Name:     header.Name,
+		Scope:    header.scope(),
+		Owner:    header.Metadata.Owner,
+		Reviewed: header.Metadata.UpdatedAt,
+	}
+
+	for _, block := range kind.Blocks {
+		node := mappingValue(baseline, block.Key)
+		if node == nil {
+			continue
+		}
+		if !block.PerItem {
+			rules, err := blockRules(kind, block, block.PathPrefixes, node)
+			if err != nil {
+				return Section{}, fmt.Errorf("%s: %w", block.Key, err)
+			}
+			section.Rules = append(section.Rules, rules...)
+			continue
+		}
+
+		for i, item := range node.Content {
+			name := scalarValue(item, "name")
+			if name == "" {
+				name = scalarValue(item, "name_pattern")
+			}
+			prefixes := make([]string, len(block.PathPrefixes))
+			for j, prefix := range block.PathPrefixes {
+				prefixes[j] = strings.ReplaceAll(prefix, "{name}", name)
+			}
+			rules, err := blockRules(kind, block, prefixes, item)
+			if err != nil {
+				return Section{}, fmt.Errorf("%s[%d]: %w", block.Key, i, err)
+			}
+			section.Rules = append(section.Rules, rules...)
+		}
+	}
+	return section, nil
+}
+
+// scope describes the resources a baseline applies to
+func (h baselineHeader) scope() string {
+	var parts []string
+	if len(h.FilterLabels) > 0 {
+		keys := make([]string, 0, len(h.FilterLabels))
+		for key := range h.FilterLabels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		labels := make([]string, len(keys))
+		for i, key := range keys {
+			labels[i] = key + "=" + h.FilterLabels[key]
+		}
+		parts = append(parts, "labeled "+strings.Join(labels, ", "))
+	}
+	if len(h.InstanceNames) > 0 {
+		parts = append(parts, "named "+strings.Join(h.InstanceNames, ", "))
+	}
+	if h.NamePattern != "" {
+		parts = append(parts, "matching "+h.NamePattern)
+	}
+	if len(h.Networks) > 0 {
+		parts = append(parts, "networks "+strings.Join(h.Networks, ", "))
+	}
+	if len(parts) == 0 {
+		return "all resources"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// blockRules flattens a block into rules, applying its allowed values,
+// ranges and severity overrides
+func blockRules(kind Kind, block Block, prefixes []string, node *yaml.Node) ([]Rule, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("expected a mapping")
+	}
+
+	var allowed checks.Allowed
+	var overrides checks.SeverityOverrides
+	var ranges checks.Ranges
+	for key, target := range map[string]any{allowedValuesKey: &allowed, severityOverridesKey: &overrides, rangesKey: &ranges} {
+		if value := mappingValue(node, key); value != nil {
+			if err := value.Decode(target); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+
+	var rules []Rule
+	walk(node, nil, func(path []string, leaf *yaml.Node) {
+		if path[0] == allowedValuesKey || path[0] == severityOverridesKey || path[0] == rangesKey {
+			return
+		}
+		// The name of a per-item block is part of the field
+		if block.PerItem && len(path) == 1 && (path[0] == "name" || path[0] == "name_pattern") {
+			return
+		}
+
+		rule := Rule{Required: leafValue(leaf)}
+		check, field := lookupCheck(kind, block, prefixes, path, leaf.Kind == yaml.SequenceNode)
+		rule.Field = field
+		if values := allowed.Values(field); len(values) > 0 {
+			rule.Required = checks.DescribeValues(values)
+		} else if expected, err := strconv.ParseFloat(leaf.Value, 64); err == nil && leaf.Kind == yaml.ScalarNode {
+			if described, ok := ranges.Describe(field, expected); ok {
+				rule.Required = described
+			}
+		}
+		if check != nil {
+			rule.Check = check.ID
+			rule.Severity = check.Severity
+			rule.Rationale = check.Description
+		}
+		if severity, ok := overrides.Severity(field); ok {
+			rule.Severity = severity
+		}
+		rules = append(rules, rule)
+	})
+	return rules, nil
+}
+
+// lookupCheck finds the check reporting a YAML path of a block: by check ID
+// first, then by the drift field under each prefix. It returns the drift
+// field the path is reported as.
+func lookupCheck(kind Kind, block Block, prefixes []string, path []string, list bool) (*checks.Check, string) {
+	dotted := strings.Join(path, ".")
+	fields := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		fields[i] = prefix + dotted
+	}
+	if len(fields) == 0 {
+		fields = []string{dotted}
+	}
+	if kind.ResourceType == "" {
+		return nil, fields[0]
+	}
+
+	var byID *checks.Check
+	if block.IDPrefix != "" {
+		if check, ok := checks.Get(block.IDPrefix + dotted); ok && check.ResourceType == kind.ResourceType {
+			byID = check
+		}
+	}
+	for _, field := range fields {
+		for _, candidate := range fieldCandidates(path, field) {
+			check, ok := checks.ForField(kind.ResourceType, candidate)
+			if !ok || (byID != nil && check.Path != byID.Path) {
+				continue
+			}
+			if byID != nil {
+				check = byID
+			}
+			// Entries of a list are reported individually; the rule is
+			// about the list
+			if list {
+				candidate = field
+			}
+			return check, candidate
+		}
+	}
+	return byID, fields[0]
+}
+
+// fieldCandidates are the drift fields a YAML path may be reported as: the
+// field itself, its entries when it is a list, e.g. replica_regions[*], and
+// map keys in brackets, e.g. labels.team as labels[team]
+func fieldCandidates(path []string, field string) []string {
+	candidates := []string{field, field + "[*]"}
+	if len(path) > 1 {
+		last := path[len(path)-1]
+		candidates = append(candidates, strings.TrimSuffix(field, "."+last)+"["+last+"]")
+	}
+	return candidates
+}
+
+// walk calls fn for every leaf of a mapping in document order; lists are
+// leaves
+func walk(node *yaml.Node, path []string, fn func(path []string, leaf *yaml.Node)) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		child := append(append([]string(nil), path...), key.Value)
+		switch {
+		case value.Kind == yaml.MappingNode && len(value.Content) > 0:
+			walk(value, child, fn)
+		case value.Kind == yaml.ScalarNode && value.Tag == "!!null":
+			// Unset
+		default:
+			fn(child, value)
+		}
+	}
+}
+
+// leafValue renders a leaf: scalars as written, lists and maps inline
+func leafValue(node *yaml.Node) string {
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	out, err := yaml.Marshal(inline(node))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// inline copies a node in flow style without its comments
+func inline(node *yaml.Node) *yaml.Node {
+	flow := *node
+	flow.Style = yaml.FlowStyle
+	flow.HeadComment, flow.LineComment, flow.FootComment = "", "", ""
+	flow.Content = make([]*yaml.Node, len(node.Content))
+	for i, child := range node.Content {
+		flow.Content[i] = inline(child)
+	}
+	return &flow
+}
+
+// mappingValue returns the value of key in a mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// scalarValue returns the scalar value of key in a mapping, or ""
+func scalarValue(node *yaml.Node, key string) string {
+	if value := mappingValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package policydoc
+
+import (
+	"strings"
+	"testing"
+
+	// Analyzers register their checks at initialization
+	_ "github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
+	_ "github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+)
+
+const testConfig = `
+sql_baselines:
+  - name: production
+    filter_labels:
+      env: prod
+    metadata:
+      owner: db-platform
+      updated_at: "2026-01-15"
+    config:
+      tier: db-custom-4-16384
+      disk_size_gb: 200
+      database_flags:
+        max_connections: "200"
+      replica_regions: [europe-west1, europe-west4] # DR
+      maintenance_window:
+        day: 7
+      settings:
+        backup_enabled: true
+      allowed_values:
+        tier: [db-custom-4-16384, db-custom-8-32768]
+      ranges:
+        disk_size_gb:
+          min: 100
+          max: 500
+      severity_overrides:
+        tier: critical
+gke_baselines:
+  - name: prod
+    name_pattern: ^prod-
+    cluster_config:
+      release_channel: STABLE
+    nodepools:
+      - name: gpu
+        machine_type: a2-highgpu-1g
+        labels:
+          accelerator: nvidia
+redis_baselines:
+  - name: cache
+    config:
+      tier: STANDARD_HA
+`
+
+func TestBuild(t *testing.T) {
+	doc, err := Build("Standard", "config.yaml", []byte(testConfig), Kinds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Sections) != 3 {
+		t.Fatalf("got %d sections, want 3", len(doc.Sections))
+	}
+
+	sql := doc.Sections[0]
+	if sql.Kind != "Cloud SQL" || sql.Scope != "labeled env=prod" || sql.Owner != "db-platform" || sql.Reviewed != "2026-01-15" {
+		t.Errorf("section = %+v", sql)
+	}
+	want := []Rule{
+		{Field: "tier", Required: "one of [db-custom-4-16384 db-custom-8-32768]", Severity: "critical", Check: "sql.tier"},
+		{Field: "disk_size_gb", Required: "between 100 and 500", Severity: "medium", Check: "sql.disk_size_gb"},
+		{Field: "database_flags.max_connections", Required: "200", Severity: "medium", Check: "sql.database_flags"},
+		{Field: "replica_regions", Required: "[europe-west1, europe-west4]", Severity: "high", Check: "sql.replica_regions"},
+		{Field: "settings.maintenance_window.day", Required: "7", Severity: "low", Check: "sql.settings.maintenance_window.day"},
+		{Field: "settings.backup_enabled", Required: "true", Severity: "critical", Check: "sql.settings.backup_enabled"},
+	}
+	assertRules(t, sql.Rules, want)
+
+	gke := doc.Sections[1]
+	if gke.Scope != "matching ^prod-" {
+		t.Errorf("GKE scope = %q", gke.Scope)
+	}
+	assertRules(t, gke.Rules, []Rule{
+		{Field: "cluster.release_channel", Required: "STABLE", Severity: "medium", Check: "gke.cluster.release_channel"},
+		{Field: "nodepool[gpu].machine_type", Required: "a2-highgpu-1g", Severity: "high", Check: "gke.nodepool.machine_type"},
+		{Field: "nodepool[gpu].labels[accelerator]", Required: "nvidia", Severity: "high", Check: "gke.nodepool.labels"},
+	})
+
+	// Resources without registered checks are listed without severity
+	assertRules(t, doc.Sections[2].Rules, []Rule{{Field: "tier", Required: "STANDARD_HA"}})
+	if doc.Sections[2].Scope != "all resources" {
+		t.Errorf("Redis scope = %q", doc.Sections[2].Scope)
+	}
+}
+
+func assertRules(t *testing.T, got, want []Rule) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d rules %+v, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		g := got[i]
+		g.Rationale = ""
+		if g != want[i] {
+			t.Errorf("rule %d = %+v, want %+v", i, g, want[i])
+		}
+		if want[i].Check != "" && got[i].Rationale == "" {
+			t.Errorf("rule %d has no rationale", i)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	doc := &Document{Title: "Standard", Source: "config.yaml", Sections: []Section{{
+		Kind:  "Cloud SQL",
+		Name:  "prod",
+		Scope: "all resources",
+		Rules: []Rule{
+			{Field: "tier", Required: "a|b", Severity: "high", Rationale: "Machine tier", Check: "sql.tier"},
+			{Field: "notes", Required: "<script>"},
+		},
+	}}}
+
+	md := FormatMarkdown(doc)
+	for _, want := range []string{"# Standard", "## Cloud SQL: prod", "| `tier` | a\\|b | HIGH | Machine tier (sql.tier) |", "| `notes` | <script> | — | — |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	html, err := FormatHTML(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>") || !strings.Contains(html, "&lt;script&gt;") {
+		t.Errorf("HTML does not escape values:\n%s", html)
+	}
+	if !strings.Contains(html, `<td class="high">HIGH</td>`) {
+		t.Errorf("HTML missing severity cell:\n%s", html)
+	}
+}
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Acknowledgement is a documented acceptance of a finding, such as a snooze
+// recorded with the ack command, that was active when the report was made
+type Acknowledgement struct {
+	ResourceType string `json:"resource_type" yaml:"resource_type"`
+	// Resource is the project-qualified resource name, project/name
+	Resource string    `json:"resource" yaml:"resource"`
+	Field    string    `json:"field" yaml:"field"`
+	By       string    `json:"by,omitempty" yaml:"by,omitempty"`
+	Reason   string    `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Created  time.Time `json:"created" yaml:"created"`
+	Until    time.Time `json:"until" yaml:"until"`
+}
+
+// QualifiedName returns the resource's name prefixed with its project, as
+// used to identify findings across runs
+func (r Resource) QualifiedName() string {
+	if r.Project == "" {
+		return r.Name
+	}
+	return r.Project + "/" + r.Name
+}
+
+// Acknowledged reports whether a drift of the resource has an acknowledgement
+func Acknowledged(acks []Acknowledgement, res Resource, d Drift) (Acknowledgement, bool) {
+	resource := res.QualifiedName()
+	for _, ack := range acks {
+		if ack.ResourceType == res.Type && ack.Resource == resource && ack.Field == d.Field {
+			return ack, true
+		}
+	}
+	return Acknowledgement{}, false
+}
+
+// SortAcknowledgements orders acknowledgements by resource type, resource,
+// then field
+func SortAcknowledgements(acks []Acknowledgement) {
+	sort.SliceStable(acks, func(i, j int) bool {
+		a, b := acks[i], acks[j]
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		return a.Field < b.Field
+	})
+}
+
+// FormatAcknowledgements renders the acknowledged findings of a text report,
+// or "" when there are none
+func FormatAcknowledgements(acks []Acknowledgement) string {
+	if len(acks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("75")).
+		Underline(true).
+		Render(fmt.Sprintf("Acknowledged Findings (%d)", len(acks))) + "\n")
+	for _, ack := range acks {
+		sb.WriteString(fmt.Sprintf("  ~ %s %s %s\n", ack.ResourceType, ack.Resource, ack.Field))
+		by := ack.By
+		if by == "" {
+			by = "unknown"
+		}
+		sb.WriteString(fmt.Sprintf("      by %s on %s, until %s\n",
+			by, ack.Created.Format("2006-01-02"), ack.Until.Format(time.RFC3339)))
+		if ack.Reason != "" {
+			sb.WriteString(fmt.Sprintf("      reason: %s\n", ack.Reason))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
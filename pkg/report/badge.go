@@ -0,0 +1,97 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// badgeColors maps badge states to shields.io-style fill colors
+var badgeColors = map[string]string{
+	"compliant": "#4c1",
+	"critical":  "#e05d44",
+	"high":      "#fe7d37",
+	"medium":    "#dfb317",
+	"low":       "#a4a61d",
+}
+
+// Compliance returns the percentage of resources without drift. A report
+// without resources is fully compliant.
+func (r *Report) Compliance() float64 {
+	if len(r.Resources) == 0 {
+		return 100
+	}
+	return float64(len(r.Resources)-r.DriftedCount()) / float64(len(r.Resources)) * 100
+}
+
+// worstSeverity returns the most severe drift severity and how many drifts
+// have it, or "" when the report has no drift
+func (r *Report) worstSeverity() (string, int) {
+	critical, high, medium, low := CountBySeverity(r.AllDrifts())
+	switch {
+	case critical > 0:
+		return "critical", critical
+	case high > 0:
+		return "high", high
+	case medium > 0:
+		return "medium", medium
+	case low > 0:
+		return "low", low
+	default:
+		return "", 0
+	}
+}
+
+// BadgeMessage summarizes the report for a badge, e.g. "97% | 2 critical".
+// The percentage is rounded down so any drift keeps it below 100%.
+func (r *Report) BadgeMessage() string {
+	message := fmt.Sprintf("%d%%", int(math.Floor(r.Compliance())))
+	if severity, count := r.worstSeverity(); count > 0 {
+		message += fmt.Sprintf(" | %d %s", count, severity)
+	}
+	return message
+}
+
+// FormatBadge renders a flat SVG badge with the given label and BadgeMessage,
+// colored by the most severe drift
+func (r *Report) FormatBadge(label string) string {
+	message := r.BadgeMessage()
+	color := badgeColors["compliant"]
+	if severity, _ := r.worstSeverity(); severity != "" {
+		color = badgeColors[severity]
+	}
+
+	labelWidth := badgeTextWidth(label)
+	messageWidth := badgeTextWidth(message)
+	width := labelWidth + messageWidth
+	label, message = html.EscapeString(label), html.EscapeString(message)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n", width, label, message))
+	sb.WriteString(fmt.Sprintf("  <title>%s: %s</title>\n", label, message))
+	sb.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	sb.WriteString(fmt.Sprintf(`  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width))
+	sb.WriteString(`  <g clip-path="url(#r)">` + "\n")
+	sb.WriteString(fmt.Sprintf(`    <rect width="%d" height="20" fill="#555"/>`+"\n", labelWidth))
+	sb.WriteString(fmt.Sprintf(`    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", labelWidth, messageWidth, color))
+	sb.WriteString(fmt.Sprintf(`    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", width))
+	sb.WriteString("  </g>\n")
+	sb.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	writeBadgeText(&sb, labelWidth/2, label)
+	writeBadgeText(&sb, labelWidth+messageWidth/2, message)
+	sb.WriteString("  </g>\n")
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// writeBadgeText writes centered badge text with a drop shadow
+func writeBadgeText(sb *strings.Builder, x int, text string) {
+	sb.WriteString(fmt.Sprintf(`    <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", x, text))
+	sb.WriteString(fmt.Sprintf(`    <text x="%d" y="14">%s</text>`+"\n", x, text))
+}
+
+// badgeTextWidth approximates the rendered width of 11px Verdana text plus padding
+func badgeTextWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBadgeMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *Report
+		want   string
+	}{
+		{
+			name:   "no resources",
+			report: &Report{},
+			want:   "100%",
+		},
+		{
+			name: "worst severity counted",
+			report: &Report{Resources: []Resource{
+				{Name: "a", Drifts: []Drift{{Severity: "critical"}, {Severity: "critical"}, {Severity: "high"}}},
+				{Name: "b"},
+				{Name: "c"},
+			}},
+			want: "66% | 2 critical",
+		},
+		{
+			name: "high only",
+			report: &Report{Resources: []Resource{
+				{Name: "a", Drifts: []Drift{{Severity: "high"}}},
+				{Name: "b"},
+			}},
+			want: "50% | 1 high",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.report.BadgeMessage(); got != tt.want {
+				t.Errorf("BadgeMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBadge(t *testing.T) {
+	r := &Report{Resources: []Resource{
+		{Name: "a", Drifts: []Drift{{Severity: "critical"}}},
+		{Name: "b"},
+	}}
+
+	svg := r.FormatBadge("drift")
+	if !strings.HasPrefix(svg, "<svg") {
+		t.Errorf("FormatBadge() should start with <svg, got %q", svg[:20])
+	}
+	if !strings.Contains(svg, `aria-label="drift: 50% | 1 critical"`) {
+		t.Error("FormatBadge() missing label and message")
+	}
+	if !strings.Contains(svg, badgeColors["critical"]) {
+		t.Error("FormatBadge() should use the critical color")
+	}
+
+	clean := (&Report{Resources: []Resource{{Name: "a"}}}).FormatBadge("drift")
+	if !strings.Contains(clean, badgeColors["compliant"]) {
+		t.Error("FormatBadge() should use the compliant color without drift")
+	}
+}
@@ -0,0 +1,142 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// maxCanaryFields caps the fields listed in a canary summary
+const maxCanaryFields = 10
+
+// CanaryProjection extrapolates the drift found in a random sample of
+// resources to every resource the baseline matched
+type CanaryProjection struct {
+	Sampled    int
+	Population int
+	Drifted    int
+	// Rate is the percentage of sampled resources with drift; Low and High
+	// bound it with a 95% confidence interval
+	Rate, Low, High float64
+	// Projected is the expected number of drifted resources in the population
+	Projected int
+	// Severities counts sampled resources by their most severe drift
+	Severities map[string]int
+	// Fields lists drifted fields by how many sampled resources they affect
+	Fields []FieldRate
+}
+
+// FieldRate is the share of sampled resources drifting on one field
+type FieldRate struct {
+	Field     string
+	Resources int
+	Rate      float64
+}
+
+// ProjectCanary treats the report as a random sample of population resources
+// and projects its drift rate onto the population
+func (r *Report) ProjectCanary(population int) CanaryProjection {
+	p := CanaryProjection{
+		Sampled:    len(r.Resources),
+		Population: population,
+		Severities: make(map[string]int),
+	}
+	if p.Sampled == 0 {
+		return p
+	}
+
+	fields := make(map[string]int)
+	for _, resource := range r.Resources {
+		if len(resource.Drifts) == 0 {
+			continue
+		}
+		p.Drifted++
+
+		worst := ""
+		seen := make(map[string]bool)
+		for _, drift := range resource.Drifts {
+			if SeverityRank(drift.Severity) > SeverityRank(worst) {
+				worst = drift.Severity
+			}
+			field := FieldRuleID(drift.Field)
+			if !seen[field] {
+				seen[field] = true
+				fields[field]++
+			}
+		}
+		p.Severities[worst]++
+	}
+
+	for field, count := range fields {
+		p.Fields = append(p.Fields, FieldRate{Field: field, Resources: count, Rate: percent(count, p.Sampled)})
+	}
+	sort.Slice(p.Fields, func(i, j int) bool {
+		if p.Fields[i].Resources != p.Fields[j].Resources {
+			return p.Fields[i].Resources > p.Fields[j].Resources
+		}
+		return p.Fields[i].Field < p.Fields[j].Field
+	})
+
+	p.Rate = percent(p.Drifted, p.Sampled)
+	p.Low, p.High = wilsonInterval(p.Drifted, p.Sampled, population)
+	p.Projected = int(math.Round(p.Rate / 100 * float64(population)))
+	return p
+}
+
+// Format renders the projection as plain text
+func (p CanaryProjection) Format() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Canary: evaluated %d of %d matched resources\n", p.Sampled, p.Population)
+	if p.Sampled == 0 {
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "  Drifted:   %d (%.1f%%, 95%% CI %.1f%%-%.1f%%)\n", p.Drifted, p.Rate, p.Low, p.High)
+	fmt.Fprintf(&b, "  Projected: ~%d of %d resources would drift\n", p.Projected, p.Population)
+	for _, severity := range []string{"critical", "high", "medium", "low"} {
+		if count := p.Severities[severity]; count > 0 {
+			fmt.Fprintf(&b, "  Worst %-9s %d (%.1f%%)\n", severity+":", count, percent(count, p.Sampled))
+		}
+	}
+
+	if len(p.Fields) > 0 {
+		b.WriteString("  Top drifted fields:\n")
+		for i, field := range p.Fields {
+			if i == maxCanaryFields {
+				fmt.Fprintf(&b, "    ... and %d more\n", len(p.Fields)-maxCanaryFields)
+				break
+			}
+			fmt.Fprintf(&b, "    %-50s %d (%.1f%%)\n", field.Field, field.Resources, field.Rate)
+		}
+	}
+	return b.String()
+}
+
+// percent returns part as a percentage of whole
+func percent(part, whole int) float64 {
+	return float64(part) / float64(whole) * 100
+}
+
+// wilsonInterval returns the 95% Wilson score interval, in percent, for k
+// successes in a sample of n drawn without replacement from population. The
+// finite population correction narrows the interval as the sample approaches
+// the whole population.
+func wilsonInterval(k, n, population int) (low, high float64) {
+	const z = 1.96
+	phat := float64(k) / float64(n)
+	nf := float64(n)
+
+	center := (phat + z*z/(2*nf)) / (1 + z*z/nf)
+	margin := z * math.Sqrt(phat*(1-phat)/nf+z*z/(4*nf*nf)) / (1 + z*z/nf)
+	if population > n {
+		margin *= math.Sqrt(float64(population-n) / float64(population-1))
+	} else {
+		margin = 0
+		center = phat
+	}
+
+	low = math.Max(0, center-margin) * 100
+	high = math.Min(1, center+margin) * 100
+	return low, high
+}
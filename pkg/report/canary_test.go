@@ -0,0 +1,57 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProjectCanary(t *testing.T) {
+	r := &Report{Resources: []Resource{
+		{Name: "a", Drifts: []Drift{
+			{Field: "nodepool[pool-a].machine_type", Severity: "medium"},
+			{Field: "nodepool[pool-b].machine_type", Severity: "critical"},
+		}},
+		{Name: "b", Drifts: []Drift{{Field: "nodepool[default].machine_type", Severity: "medium"}}},
+		{Name: "c", Drifts: []Drift{{Field: "release_channel", Severity: "high"}}},
+		{Name: "d"},
+		{Name: "e"},
+	}}
+
+	p := r.ProjectCanary(50)
+	if p.Sampled != 5 || p.Drifted != 3 || p.Rate != 60 || p.Projected != 30 {
+		t.Errorf("ProjectCanary() = %d sampled, %d drifted, %.1f%%, projected %d; want 5, 3, 60%%, 30",
+			p.Sampled, p.Drifted, p.Rate, p.Projected)
+	}
+	if p.Low >= p.Rate || p.High <= p.Rate || p.Low < 0 || p.High > 100 {
+		t.Errorf("confidence interval %.1f-%.1f should contain %.1f", p.Low, p.High, p.Rate)
+	}
+	if p.Severities["critical"] != 1 || p.Severities["medium"] != 1 || p.Severities["high"] != 1 {
+		t.Errorf("Severities = %v, want one resource each for critical, high and medium", p.Severities)
+	}
+
+	// Node pool fields are grouped, and each resource is counted once per field
+	if len(p.Fields) != 2 || p.Fields[0] != (FieldRate{Field: "nodepool[*].machine_type", Resources: 2, Rate: 40}) {
+		t.Errorf("Fields = %+v", p.Fields)
+	}
+
+	text := p.Format()
+	for _, want := range []string{"evaluated 5 of 50", "~30 of 50", "nodepool[*].machine_type"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Format() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestProjectCanaryFullPopulation(t *testing.T) {
+	r := &Report{Resources: []Resource{{Name: "a", Drifts: []Drift{{Field: "tier", Severity: "high"}}}, {Name: "b"}}}
+
+	// Sampling everything leaves no uncertainty
+	p := r.ProjectCanary(2)
+	if p.Low != 50 || p.High != 50 {
+		t.Errorf("interval = %.1f-%.1f, want 50-50", p.Low, p.High)
+	}
+
+	if empty := (&Report{}).ProjectCanary(10); empty.Drifted != 0 || empty.Projected != 0 {
+		t.Errorf("empty sample projected %+v", empty)
+	}
+}
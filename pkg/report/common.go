@@ -13,6 +13,48 @@ type Drift struct {
 	Expected string `json:"expected" yaml:"expected"`
 	Actual   string `json:"actual" yaml:"actual"`
 	Severity string `json:"severity" yaml:"severity"`
+	// Immutable marks settings fixed at creation time; remediation requires recreating the resource
+	Immutable bool `json:"immutable,omitempty" yaml:"immutable,omitempty"`
+	// Operational marks an unhealthy resource state, such as a failed
+	// instance, rather than a configuration difference
+	Operational bool `json:"operational,omitempty" yaml:"operational,omitempty"`
+	// Warning flags a stale baseline, e.g. an expected value the resource's
+	// version no longer supports
+	Warning string `json:"warning,omitempty" yaml:"warning,omitempty"`
+	// Owners are the services owning the databases a finding is about
+	Owners []string `json:"owners,omitempty" yaml:"owners,omitempty"`
+	// Escalation explains a severity raised because the drift went unresolved
+	Escalation string `json:"escalation,omitempty" yaml:"escalation,omitempty"`
+	// HourlyCostDelta is the estimated change in on-demand USD cost per hour
+	// from the expected to the actual value
+	HourlyCostDelta float64 `json:"hourly_cost_delta,omitempty" yaml:"hourly_cost_delta,omitempty"`
+	// Link opens the console page where the drifted setting is changed
+	Link string `json:"link,omitempty" yaml:"link,omitempty"`
+	// Remediation is the fix for the drift, filled in with --show-remediation
+	Remediation *Remediation `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+}
+
+// Remediation is an actionable fix for a single drift
+type Remediation struct {
+	// Command brings the resource back to the expected value, e.g.
+	// gcloud sql instances patch
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// Terraform is the attribute change for resources managed with the
+	// Terraform Google provider
+	Terraform string `json:"terraform,omitempty" yaml:"terraform,omitempty"`
+	// Instructions explain fixes that are not a single command, such as
+	// recreating the resource, and caveats of the command
+	Instructions string `json:"instructions,omitempty" yaml:"instructions,omitempty"`
+}
+
+// FormatCostDelta formats an hourly cost delta in USD, e.g. +$12.34/hour
+func FormatCostDelta(delta float64) string {
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+		delta = -delta
+	}
+	return fmt.Sprintf("%s$%.2f/hour", sign, delta)
 }
 
 // GetIconForSeverity returns an appropriate styled icon for the severity level
@@ -41,6 +83,22 @@ func GetIconForSeverity(severity string) string {
 	}
 }
 
+// SeverityRank orders severities from low (1) to critical (4); unknown severities rank 0
+func SeverityRank(severity string) int {
+	switch severity {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
+
 // CountBySeverity tallies the number of drifts by severity level
 func CountBySeverity(drifts []Drift) (critical, high, medium, low int) {
 	for _, drift := range drifts {
@@ -95,6 +153,26 @@ func FormatDriftSummary(critical, high, medium, low int) string {
 	return sb.String()
 }
 
+// FormatBaselineWarnings renders warnings about baselines due for review, or
+// "" when there are none
+func FormatBaselineWarnings(warnings []string) string {
+	if len(warnings) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("214")).
+		Underline(true).
+		Render("Baseline Review") + "\n")
+	for _, warning := range warnings {
+		sb.WriteString(fmt.Sprintf("  ! %s\n", warning))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
 // FormatDrifts generates formatted text for a list of drifts
 func FormatDrifts(drifts []Drift) string {
 	var sb strings.Builder
@@ -121,6 +199,13 @@ func FormatDrifts(drifts []Drift) string {
 		actualStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("196"))
 
+		immutableStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("201")).
+			Bold(true)
+
+		warningStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("214"))
+
 		sb.WriteString(headerStyle.Render(fmt.Sprintf("Detected Drifts: %d", len(drifts))) + "\n\n")
 		for _, drift := range drifts {
 			icon := GetIconForSeverity(drift.Severity)
@@ -136,12 +221,46 @@ func FormatDrifts(drifts []Drift) string {
 				severityStyle = severityStyle.Foreground(lipgloss.Color("244"))
 			}
 
-			sb.WriteString(fmt.Sprintf("  %s %s %s\n",
+			marker := ""
+			if drift.Immutable {
+				marker = " " + immutableStyle.Render("[RECREATE]")
+			}
+			if drift.Operational {
+				marker += " " + warningStyle.Render("[OPERATIONAL]")
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s %s%s\n",
 				icon,
 				severityStyle.Render(fmt.Sprintf("[%s]", strings.ToUpper(drift.Severity))),
-				fieldStyle.Render(drift.Field)))
+				fieldStyle.Render(drift.Field),
+				marker))
 			sb.WriteString(labelStyle.Render("     Expected: ") + expectedStyle.Render(drift.Expected) + "\n")
 			sb.WriteString(labelStyle.Render("     Actual:   ") + actualStyle.Render(drift.Actual) + "\n")
+			if drift.Warning != "" {
+				sb.WriteString(labelStyle.Render("     Warning:  ") + warningStyle.Render(drift.Warning) + "\n")
+			}
+			if len(drift.Owners) > 0 {
+				sb.WriteString(labelStyle.Render("     Owners:   ") + strings.Join(drift.Owners, ", ") + "\n")
+			}
+			if drift.Escalation != "" {
+				sb.WriteString(labelStyle.Render("     Escalated: ") + warningStyle.Render(drift.Escalation) + "\n")
+			}
+			if drift.HourlyCostDelta != 0 {
+				sb.WriteString(labelStyle.Render("     Cost:     ") + FormatCostDelta(drift.HourlyCostDelta) + " (estimated)\n")
+			}
+			if drift.Link != "" {
+				sb.WriteString(labelStyle.Render("     Console:  ") + drift.Link + "\n")
+			}
+			if fix := drift.Remediation; fix != nil {
+				if fix.Command != "" {
+					sb.WriteString(labelStyle.Render("     Fix:      ") + fix.Command + "\n")
+				}
+				if fix.Terraform != "" {
+					sb.WriteString(labelStyle.Render("     Terraform: ") + fix.Terraform + "\n")
+				}
+				if fix.Instructions != "" {
+					sb.WriteString(labelStyle.Render("     Note:     ") + fix.Instructions + "\n")
+				}
+			}
 			sb.WriteString("\n")
 		}
 	}
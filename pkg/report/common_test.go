@@ -174,3 +174,15 @@ func TestFormatDrifts(t *testing.T) {
 		})
 	}
 }
+
+func TestSeverityRank(t *testing.T) {
+	order := []string{"", "low", "medium", "high", "critical"}
+	for i := 1; i < len(order); i++ {
+		if SeverityRank(order[i]) <= SeverityRank(order[i-1]) {
+			t.Errorf("SeverityRank(%q) should rank above %q", order[i], order[i-1])
+		}
+	}
+	if got := SeverityRank("bogus"); got != 0 {
+		t.Errorf("SeverityRank(bogus) = %d, want 0", got)
+	}
+}
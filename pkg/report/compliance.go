@@ -0,0 +1,97 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ComplianceSummary is the compliance of a report covering several resource
+// types, overall and per type
+type ComplianceSummary struct {
+	// Compliance is the percentage of resources without drift
+	Compliance float64          `json:"compliance" yaml:"compliance"`
+	Resources  int              `json:"resources" yaml:"resources"`
+	Drifted    int              `json:"drifted" yaml:"drifted"`
+	ByType     []TypeCompliance `json:"by_type" yaml:"by_type"`
+}
+
+// TypeCompliance is the compliance of the resources of one type
+type TypeCompliance struct {
+	Type       string  `json:"type" yaml:"type"`
+	Compliance float64 `json:"compliance" yaml:"compliance"`
+	Resources  int     `json:"resources" yaml:"resources"`
+	Drifted    int     `json:"drifted" yaml:"drifted"`
+}
+
+// Summarize computes the compliance of the report overall and per resource
+// type, with types in name order
+func (r *Report) Summarize() *ComplianceSummary {
+	byType := make(map[string]*TypeCompliance)
+	for _, res := range r.Resources {
+		t, ok := byType[res.Type]
+		if !ok {
+			t = &TypeCompliance{Type: res.Type}
+			byType[res.Type] = t
+		}
+		t.Resources++
+		if len(res.Drifts) > 0 {
+			t.Drifted++
+		}
+	}
+
+	summary := &ComplianceSummary{
+		Compliance: r.Compliance(),
+		Resources:  len(r.Resources),
+		Drifted:    r.DriftedCount(),
+		ByType:     make([]TypeCompliance, 0, len(byType)),
+	}
+	for _, t := range byType {
+		t.Compliance = float64(t.Resources-t.Drifted) / float64(t.Resources) * 100
+		summary.ByType = append(summary.ByType, *t)
+	}
+	sort.Slice(summary.ByType, func(i, j int) bool {
+		return summary.ByType[i].Type < summary.ByType[j].Type
+	})
+	return summary
+}
+
+// FormatCompliance renders the compliance section of a text report, or ""
+// when the report has no summary
+func FormatCompliance(s *ComplianceSummary) string {
+	if s == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("75")).
+		Underline(true).
+		Render("Compliance") + "\n")
+	sb.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(complianceColor(s.Compliance)).
+		Render(fmt.Sprintf("  %-20s %5.1f%%  %d of %d resources drifted", "Overall", s.Compliance, s.Drifted, s.Resources)) + "\n")
+	for _, t := range s.ByType {
+		sb.WriteString(lipgloss.NewStyle().
+			Foreground(complianceColor(t.Compliance)).
+			Render(fmt.Sprintf("  %-20s %5.1f%%  %d of %d resources drifted", t.Type, t.Compliance, t.Drifted, t.Resources)) + "\n")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// complianceColor is green for full compliance, yellow above 80% and red below
+func complianceColor(compliance float64) lipgloss.Color {
+	switch {
+	case compliance >= 100:
+		return lipgloss.Color("42")
+	case compliance >= 80:
+		return lipgloss.Color("220")
+	default:
+		return lipgloss.Color("196")
+	}
+}
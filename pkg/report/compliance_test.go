@@ -0,0 +1,34 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	r := &Report{Resources: []Resource{
+		{Type: "GKE Cluster", Name: "c1", Drifts: []Drift{{Severity: "high"}}},
+		{Type: "Cloud SQL", Name: "db-1"},
+		{Type: "Cloud SQL", Name: "db-2", Drifts: []Drift{{Severity: "low"}}},
+		{Type: "Cloud SQL", Name: "db-3"},
+		{Type: "Cloud SQL", Name: "db-4"},
+	}}
+
+	want := &ComplianceSummary{
+		Compliance: 60,
+		Resources:  5,
+		Drifted:    2,
+		ByType: []TypeCompliance{
+			{Type: "Cloud SQL", Compliance: 75, Resources: 4, Drifted: 1},
+			{Type: "GKE Cluster", Compliance: 0, Resources: 1, Drifted: 1},
+		},
+	}
+	if got := r.Summarize(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+
+	empty := (&Report{}).Summarize()
+	if empty.Compliance != 100 || len(empty.ByType) != 0 {
+		t.Errorf("Summarize() of an empty report = %+v, want 100%% and no types", empty)
+	}
+}
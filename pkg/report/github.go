@@ -0,0 +1,138 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// githubLevels maps drift severities onto GitHub Actions annotation commands
+var githubLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "warning",
+}
+
+// githubSummaryRows caps the drift table of a step summary, which GitHub
+// limits to 1 MiB per step
+const githubSummaryRows = 200
+
+// GitHubOptions configures GitHub Actions annotations
+type GitHubOptions struct {
+	// Locate returns the file and line an annotation of a drift points at,
+	// usually the baseline entry in the config file; a line of 0 annotates
+	// the file
+	Locate func(res Resource, d Drift) (file string, line int)
+	// Title returns the title of an annotation, such as the check ID; the
+	// drift field is used when it is nil or returns ""
+	Title func(res Resource, d Drift) string
+}
+
+// FormatGitHubAnnotations renders one ::error or ::warning workflow command
+// per drift: critical and high drift are errors, the rest warnings
+func (r *Report) FormatGitHubAnnotations(opts GitHubOptions) string {
+	var sb strings.Builder
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			level, ok := githubLevels[d.Severity]
+			if !ok {
+				level = "warning"
+			}
+
+			var props []string
+			if opts.Locate != nil {
+				if file, line := opts.Locate(res, d); file != "" {
+					props = append(props, "file="+githubProperty(file))
+					if line > 0 {
+						props = append(props, fmt.Sprintf("line=%d", line))
+					}
+				}
+			}
+			title := ""
+			if opts.Title != nil {
+				title = opts.Title(res, d)
+			}
+			if title == "" {
+				title = d.Field
+			}
+			props = append(props, "title="+githubProperty(title))
+
+			message := fmt.Sprintf("%s %s: %s is %s, expected %s (%s)",
+				res.Type, res.QualifiedName(), d.Field, githubValue(d.Actual), githubValue(d.Expected), d.Severity)
+			if d.Remediation != nil && d.Remediation.Command != "" {
+				message += "\nFix: " + d.Remediation.Command
+			}
+			sb.WriteString(fmt.Sprintf("::%s %s::%s\n", level, strings.Join(props, ","), githubData(message)))
+		}
+	}
+	return sb.String()
+}
+
+// FormatGitHubSummary renders the report as Markdown for $GITHUB_STEP_SUMMARY:
+// the counts, then a table of the drifts, most severe first
+func (r *Report) FormatGitHubSummary(title string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### %s\n\n", title))
+
+	critical, high, medium, low := CountBySeverity(r.AllDrifts())
+	sb.WriteString("| Resources | With drift | Compliance | Critical | High | Medium | Low |\n")
+	sb.WriteString("|---:|---:|---:|---:|---:|---:|---:|\n")
+	sb.WriteString(fmt.Sprintf("| %d | %d | %.1f%% | %d | %d | %d | %d |\n\n",
+		len(r.Resources), r.DriftedCount(), r.Compliance(), critical, high, medium, low))
+
+	type row struct {
+		res   Resource
+		drift Drift
+	}
+	var rows []row
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			rows = append(rows, row{res, d})
+		}
+	}
+	if len(rows) == 0 {
+		sb.WriteString("No drift detected.\n\n")
+		return sb.String()
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return SeverityRank(rows[i].drift.Severity) > SeverityRank(rows[j].drift.Severity)
+	})
+
+	sb.WriteString("| Severity | Resource | Field | Expected | Actual |\n")
+	sb.WriteString("|---|---|---|---|---|\n")
+	for i, row := range rows {
+		if i == githubSummaryRows {
+			sb.WriteString(fmt.Sprintf("\n…and %d more drift(s)\n", len(rows)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | %s |\n",
+			row.drift.Severity, markdownCell(row.res.Type+" "+row.res.QualifiedName()), row.drift.Field,
+			markdownCell(githubValue(row.drift.Expected)), markdownCell(githubValue(row.drift.Actual))))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// githubValue shows an empty drift value as "(unset)"
+func githubValue(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
+
+// githubData escapes the message of a workflow command
+func githubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// githubProperty escapes a property value of a workflow command
+func githubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// markdownCell escapes a value for a Markdown table cell
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
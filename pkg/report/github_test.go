@@ -0,0 +1,62 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatGitHubAnnotations(t *testing.T) {
+	r := &Report{Resources: []Resource{
+		{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []Drift{
+			{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-f1-micro", Severity: "high",
+				Remediation: &Remediation{Command: "gcloud sql instances patch db-1 --tier=db-custom-4-16384"}},
+			{Field: "labels.team", Expected: "payments", Severity: "low"},
+		}},
+		{Type: "Cloud SQL", Project: "p", Name: "db-2"},
+	}}
+
+	got := r.FormatGitHubAnnotations(GitHubOptions{
+		Locate: func(res Resource, d Drift) (string, int) {
+			if d.Field == "tier" {
+				return "config.yaml", 12
+			}
+			return "config, prod.yaml", 0
+		},
+		Title: func(res Resource, d Drift) string {
+			if d.Field == "tier" {
+				return "sql.tier"
+			}
+			return ""
+		},
+	})
+	want := "::error file=config.yaml,line=12,title=sql.tier::Cloud SQL p/db-1: tier is db-f1-micro, expected db-custom-4-16384 (high)%0AFix: gcloud sql instances patch db-1 --tier=db-custom-4-16384\n" +
+		"::warning file=config%2C prod.yaml,title=labels.team::Cloud SQL p/db-1: labels.team is (unset), expected payments (low)\n"
+	if got != want {
+		t.Errorf("FormatGitHubAnnotations() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFormatGitHubSummary(t *testing.T) {
+	r := &Report{Resources: []Resource{
+		{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []Drift{
+			{Field: "labels.team", Expected: "a|b", Severity: "low"},
+			{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-f1-micro", Severity: "critical"},
+		}},
+		{Type: "Cloud SQL", Project: "p", Name: "db-2"},
+	}}
+
+	got := r.FormatGitHubSummary("Cloud SQL: oltp")
+	for _, want := range []string{
+		"### Cloud SQL: oltp\n",
+		"| 2 | 1 | 50.0% | 1 | 0 | 0 | 1 |\n",
+		"| critical | Cloud SQL p/db-1 | `tier` | db-custom-4-16384 | db-f1-micro |\n| low | Cloud SQL p/db-1 | `labels.team` | a\\|b | (unset) |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatGitHubSummary() missing %q in\n%s", want, got)
+		}
+	}
+
+	if got := (&Report{}).FormatGitHubSummary("empty"); !strings.Contains(got, "No drift detected.") {
+		t.Errorf("FormatGitHubSummary() of a clean report = %q", got)
+	}
+}
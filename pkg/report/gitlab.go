@@ -0,0 +1,89 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// codeQualitySeverities maps drift severities onto GitLab Code Quality severities
+var codeQualitySeverities = map[string]string{
+	"critical": "critical",
+	"high":     "major",
+	"medium":   "minor",
+	"low":      "info",
+}
+
+// CodeQualityOptions configures GitLab Code Quality output
+type CodeQualityOptions struct {
+	// Path is reported as the location of every issue; GitLab requires one,
+	// so it is usually the baseline config file
+	Path string
+	// CheckName resolves the check behind a drift, such as the check ID; the
+	// rule ID derived from the field is used when it is nil or returns ""
+	CheckName func(res Resource, d Drift) string
+}
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+}
+
+// FormatGitLabCodeQuality generates a GitLab Code Quality report, a JSON
+// array with one issue per drift, for the code quality widget of merge
+// requests. Acknowledged drift is left out.
+func (r *Report) FormatGitLabCodeQuality(opts CodeQualityOptions) (string, error) {
+	issues := []codeQualityIssue{}
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			if _, ok := Acknowledged(r.Acknowledgements, res, d); ok {
+				continue
+			}
+
+			checkName := ""
+			if opts.CheckName != nil {
+				checkName = opts.CheckName(res, d)
+			}
+			if checkName == "" {
+				checkName = FieldRuleID(d.Field)
+			}
+			severity, ok := codeQualitySeverities[d.Severity]
+			if !ok {
+				severity = "info"
+			}
+
+			resource := res.QualifiedName()
+			// Stable across runs so GitLab tracks the same issue between pipelines
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", res.Type, resource, d.Field)))
+			issues = append(issues, codeQualityIssue{
+				Description: fmt.Sprintf("%s %s: %s is %s, expected %s", res.Type, resource, d.Field, d.Actual, d.Expected),
+				CheckName:   checkName,
+				Fingerprint: hex.EncodeToString(sum[:]),
+				Severity:    severity,
+				Location: codeQualityLocation{
+					Path:  opts.Path,
+					Lines: codeQualityLines{Begin: 1},
+				},
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal code quality report: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestFormatGitLabCodeQuality(t *testing.T) {
+	r := &Report{
+		Resources: []Resource{
+			{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []Drift{
+				{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-f1-micro", Severity: "high"},
+				{Field: "labels.team", Expected: "payments", Severity: "low"},
+			}},
+			{Type: "GKE Cluster", Project: "p", Name: "c1", Drifts: []Drift{
+				{Field: "nodepool[default].machine_type", Expected: "e2-standard-8", Actual: "e2-standard-4", Severity: "critical"},
+			}},
+		},
+		Acknowledgements: []Acknowledgement{
+			{ResourceType: "Cloud SQL", Resource: "p/db-1", Field: "labels.team", Reason: "migrating", Until: time.Now().Add(time.Hour)},
+		},
+	}
+
+	output, err := r.FormatGitLabCodeQuality(CodeQualityOptions{
+		Path: "config.yaml",
+		CheckName: func(res Resource, d Drift) string {
+			if d.Field == "tier" {
+				return "sql.tier"
+			}
+			return ""
+		},
+	})
+	if err != nil {
+		t.Fatalf("FormatGitLabCodeQuality() error = %v", err)
+	}
+
+	var issues []codeQualityIssue
+	if err := json.Unmarshal([]byte(output), &issues); err != nil {
+		t.Fatalf("output is not a JSON array: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %d issues, want 2 without the acknowledged drift", len(issues))
+	}
+
+	tier, pool := issues[0], issues[1]
+	if tier.CheckName != "sql.tier" || tier.Severity != "major" || tier.Location.Path != "config.yaml" || tier.Location.Lines.Begin != 1 {
+		t.Errorf("tier issue = %+v", tier)
+	}
+	if tier.Description != "Cloud SQL p/db-1: tier is db-f1-micro, expected db-custom-4-16384" {
+		t.Errorf("description = %q", tier.Description)
+	}
+	if pool.CheckName != "nodepool[*].machine_type" || pool.Severity != "critical" {
+		t.Errorf("node pool issue = %+v", pool)
+	}
+	if len(tier.Fingerprint) != 64 || tier.Fingerprint == pool.Fingerprint {
+		t.Errorf("fingerprints = %q, %q, want distinct SHA-256 hashes", tier.Fingerprint, pool.Fingerprint)
+	}
+
+	again, _ := r.FormatGitLabCodeQuality(CodeQualityOptions{Path: "config.yaml"})
+	var rerun []codeQualityIssue
+	if err := json.Unmarshal([]byte(again), &rerun); err != nil || rerun[0].Fingerprint != tier.Fingerprint {
+		t.Errorf("fingerprint changed between runs")
+	}
+
+	empty, _ := (&Report{}).FormatGitLabCodeQuality(CodeQualityOptions{})
+	if empty != "[]" {
+		t.Errorf("empty report = %q, want []", empty)
+	}
+}
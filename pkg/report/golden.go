@@ -0,0 +1,81 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// GoldenTime replaces the timestamp of deterministic reports, so reports
+// rendered at different times can be compared byte for byte
+var GoldenTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// GoldenScanID is the scan ID of deterministic NDJSON output
+const GoldenScanID = "00000000-0000-0000-0000-000000000000"
+
+// DisableColor renders every styled string as plain text
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// SortDrifts orders drifts by field, then by expected and actual value.
+// Analyzers compare maps such as database flags and labels, so their drift
+// order otherwise varies between runs.
+func SortDrifts(drifts []Drift) {
+	sort.SliceStable(drifts, func(i, j int) bool {
+		a, b := drifts[i], drifts[j]
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		if a.Expected != b.Expected {
+			return a.Expected < b.Expected
+		}
+		return a.Actual < b.Actual
+	})
+}
+
+// Normalize makes the report deterministic: the timestamp is fixed and
+// resources, their drifts and scan errors are sorted
+func (r *Report) Normalize() {
+	r.Timestamp = GoldenTime
+	sort.SliceStable(r.Resources, func(i, j int) bool {
+		return resourceKey(r.Resources[i]) < resourceKey(r.Resources[j])
+	})
+	for i := range r.Resources {
+		SortDrifts(r.Resources[i].Drifts)
+	}
+	SortScanErrors(r.Errors)
+}
+
+func resourceKey(res Resource) string {
+	return strings.Join([]string{res.Type, res.Project, res.Location, res.Name}, "\x00")
+}
+
+// DiffGolden compares output with the contents of a golden file and describes
+// the first difference, or returns "" when they are identical
+func DiffGolden(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	wantLines := strings.Split(want, "\n")
+	gotLines := strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g || i >= len(wantLines) || i >= len(gotLines) {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s\n(%d lines in golden file, %d in output)",
+				i+1, w, g, len(wantLines), len(gotLines))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	r := &Report{
+		Timestamp: time.Now(),
+		Resources: []Resource{
+			{Type: "GKE Cluster", Project: "p", Name: "gke-1"},
+			{Type: "Cloud SQL", Project: "p", Name: "db-2"},
+			{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []Drift{
+				{Field: "tier", Expected: "b"},
+				{Field: "database_flags.max_connections"},
+				{Field: "tier", Expected: "a"},
+			}},
+		},
+	}
+	r.Normalize()
+
+	if !r.Timestamp.Equal(GoldenTime) {
+		t.Errorf("Timestamp = %v, want %v", r.Timestamp, GoldenTime)
+	}
+	var names []string
+	for _, res := range r.Resources {
+		names = append(names, res.Name)
+	}
+	if got := strings.Join(names, ","); got != "db-1,db-2,gke-1" {
+		t.Errorf("resource order = %s, want db-1,db-2,gke-1", got)
+	}
+	var fields []string
+	for _, d := range r.Resources[0].Drifts {
+		fields = append(fields, d.Field+"="+d.Expected)
+	}
+	if got := strings.Join(fields, ","); got != "database_flags.max_connections=,tier=a,tier=b" {
+		t.Errorf("drift order = %s", got)
+	}
+}
+
+func TestDiffGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		got  string
+		diff string
+	}{
+		{"identical", "a\nb\n", "a\nb\n", ""},
+		{"changed line", "a\nb\n", "a\nc\n", "line 2:\n- b\n+ c"},
+		{"extra line", "a\n", "a\nb\n", "line 2:\n- \n+ b"},
+		{"missing line", "a\nb\n", "a\n", "line 2:\n- b\n+ "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := DiffGolden(tt.want, tt.got)
+			if tt.diff == "" {
+				if diff != "" {
+					t.Errorf("DiffGolden() = %q, want no difference", diff)
+				}
+				return
+			}
+			if !strings.HasPrefix(diff, tt.diff) {
+				t.Errorf("DiffGolden() = %q, want prefix %q", diff, tt.diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// severityRank orders severities from least to most severe
+var severityRank = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"high":     3,
+	"critical": 4,
+}
+
+// severityColors maps severities to graph node fill colors
+var severityColors = map[string]string{
+	"critical": "#ff4d4f",
+	"high":     "#ffa940",
+	"medium":   "#ffe58f",
+	"low":      "#d9d9d9",
+}
+
+// graphProject groups drifted resources under a project
+type graphProject struct {
+	name      string
+	total     int
+	resources []graphResource
+}
+
+type graphResource struct {
+	label      string
+	categories []graphCategory
+}
+
+type graphCategory struct {
+	name     string
+	count    int
+	severity string
+}
+
+// DriftCategory groups a drift field path into a category: the path without
+// its last segment and index qualifiers (e.g. "settings.ip_configuration.require_ssl"
+// becomes "settings.ip_configuration", "nodepool[default].disk_size_gb" becomes
+// "nodepool"). Top-level fields are their own category.
+func DriftCategory(field string) string {
+	var segments []string
+	for _, segment := range strings.Split(field, ".") {
+		if i := strings.Index(segment, "["); i >= 0 {
+			segment = segment[:i]
+		}
+		segments = append(segments, segment)
+	}
+	if len(segments) == 1 {
+		return segments[0]
+	}
+	return strings.Join(segments[:len(segments)-1], ".")
+}
+
+// buildGraph groups the report into projects → drifted resources → drift categories
+func (r *Report) buildGraph() []graphProject {
+	byProject := make(map[string]*graphProject)
+	for _, res := range r.Resources {
+		project := res.Project
+		if project == "" {
+			project = "(no project)"
+		}
+		gp, ok := byProject[project]
+		if !ok {
+			gp = &graphProject{name: project}
+			byProject[project] = gp
+		}
+		gp.total++
+
+		if len(res.Drifts) == 0 {
+			continue
+		}
+
+		categories := make(map[string]*graphCategory)
+		for _, d := range res.Drifts {
+			name := DriftCategory(d.Field)
+			c, ok := categories[name]
+			if !ok {
+				c = &graphCategory{name: name}
+				categories[name] = c
+			}
+			c.count++
+			if severityRank[d.Severity] > severityRank[c.severity] {
+				c.severity = d.Severity
+			}
+		}
+
+		gr := graphResource{label: fmt.Sprintf("%s: %s", res.Type, res.Name)}
+		for _, c := range categories {
+			gr.categories = append(gr.categories, *c)
+		}
+		sort.Slice(gr.categories, func(i, j int) bool {
+			return gr.categories[i].name < gr.categories[j].name
+		})
+		gp.resources = append(gp.resources, gr)
+	}
+
+	projects := make([]graphProject, 0, len(byProject))
+	for _, gp := range byProject {
+		projects = append(projects, *gp)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].name < projects[j].name
+	})
+	return projects
+}
+
+// FormatDOT renders the drift map as a Graphviz DOT digraph
+func (r *Report) FormatDOT() string {
+	var sb strings.Builder
+
+	sb.WriteString("digraph drift {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\", fontname=\"Helvetica\"];\n")
+
+	for pi, project := range r.buildGraph() {
+		projectID := fmt.Sprintf("p%d", pi)
+		sb.WriteString(fmt.Sprintf("  %s [label=%s, shape=folder, fillcolor=\"#e6f4ff\"];\n",
+			projectID, dotQuote(fmt.Sprintf("%s\n%d/%d drifted", project.name, len(project.resources), project.total))))
+
+		for ri, res := range project.resources {
+			resourceID := fmt.Sprintf("%s_r%d", projectID, ri)
+			sb.WriteString(fmt.Sprintf("  %s [label=%s];\n", resourceID, dotQuote(res.label)))
+			sb.WriteString(fmt.Sprintf("  %s -> %s;\n", projectID, resourceID))
+
+			for ci, c := range res.categories {
+				categoryID := fmt.Sprintf("%s_c%d", resourceID, ci)
+				sb.WriteString(fmt.Sprintf("  %s [label=%s, shape=ellipse, fillcolor=%s];\n",
+					categoryID, dotQuote(c.name), dotQuote(severityColors[c.severity])))
+				sb.WriteString(fmt.Sprintf("  %s -> %s [label=%s];\n",
+					resourceID, categoryID, dotQuote(fmt.Sprintf("%d %s", c.count, c.severity))))
+			}
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// FormatMermaid renders the drift map as a Mermaid flowchart
+func (r *Report) FormatMermaid() string {
+	var sb strings.Builder
+
+	sb.WriteString("flowchart LR\n")
+	for _, severity := range []string{"critical", "high", "medium", "low"} {
+		sb.WriteString(fmt.Sprintf("  classDef %s fill:%s,stroke:#333\n", severity, severityColors[severity]))
+	}
+
+	for pi, project := range r.buildGraph() {
+		projectID := fmt.Sprintf("p%d", pi)
+		sb.WriteString(fmt.Sprintf("  %s[/%s/]\n",
+			projectID, mermaidQuote(fmt.Sprintf("%s<br/>%d/%d drifted", project.name, len(project.resources), project.total))))
+
+		for ri, res := range project.resources {
+			resourceID := fmt.Sprintf("%s_r%d", projectID, ri)
+			sb.WriteString(fmt.Sprintf("  %s[%s]\n", resourceID, mermaidQuote(res.label)))
+			sb.WriteString(fmt.Sprintf("  %s --> %s\n", projectID, resourceID))
+
+			for ci, c := range res.categories {
+				categoryID := fmt.Sprintf("%s_c%d", resourceID, ci)
+				sb.WriteString(fmt.Sprintf("  %s -->|%s| %s(%s):::%s\n",
+					resourceID, mermaidQuote(fmt.Sprintf("%d %s", c.count, c.severity)), categoryID, mermaidQuote(c.name), c.severity))
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,77 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDriftCategory(t *testing.T) {
+	tests := []struct {
+		field string
+		want  string
+	}{
+		{"tier", "tier"},
+		{"database_flags.max_connections", "database_flags"},
+		{"settings.ip_configuration.require_ssl", "settings.ip_configuration"},
+		{"nodepool[default-pool].disk_size_gb", "nodepool"},
+		{"cluster.network", "cluster"},
+	}
+
+	for _, tt := range tests {
+		if got := DriftCategory(tt.field); got != tt.want {
+			t.Errorf("DriftCategory(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func testGraphReport() *Report {
+	return &Report{
+		Resources: []Resource{
+			{Type: "Cloud SQL", Project: "proj-b", Name: "db-1", Drifts: []Drift{
+				{Field: "settings.backup_enabled", Severity: "critical"},
+				{Field: "settings.pricing_plan", Severity: "low"},
+				{Field: "tier", Severity: "high"},
+			}},
+			{Type: "Cloud SQL", Project: "proj-b", Name: "db-2"},
+			{Type: "Cloud SQL", Project: "proj-a", Name: "db-3", Drifts: []Drift{
+				{Field: "disk_type", Severity: "medium"},
+			}},
+		},
+	}
+}
+
+func TestFormatDOT(t *testing.T) {
+	out := testGraphReport().FormatDOT()
+
+	for _, want := range []string{
+		"digraph drift {",
+		`p0 [label="proj-a\n1/1 drifted"`,
+		`p1 [label="proj-b\n1/2 drifted"`,
+		`p1_r0 [label="Cloud SQL: db-1"]`,
+		`p1_r0_c0 [label="settings", shape=ellipse, fillcolor="#ff4d4f"]`,
+		`p1_r0 -> p1_r0_c0 [label="2 critical"]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("FormatDOT() missing %q\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, "db-2") {
+		t.Error("FormatDOT() should omit resources without drift")
+	}
+}
+
+func TestFormatMermaid(t *testing.T) {
+	out := testGraphReport().FormatMermaid()
+
+	for _, want := range []string{
+		"flowchart LR",
+		"classDef critical fill:#ff4d4f",
+		`p1 --> p1_r0`,
+		`p1_r0 -->|"1 high"| p1_r0_c1("tier"):::high`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("FormatMermaid() missing %q\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// JUnitOptions configures JUnit XML output
+type JUnitOptions struct {
+	// Fields lists the baseline fields checked for a resource type. Each field
+	// becomes a passing test case unless a drift is reported on it; drifts on
+	// unlisted fields are always included.
+	Fields func(resourceType string) []string
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr,omitempty"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr,omitempty"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure,omitempty"`
+	Errors    []junitFailure `xml:"error,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// FormatJUnit generates JUnit XML with one test suite per resource and one
+// test case per baseline field, failed when drift is detected on the field,
+// so CI systems render drift as test failures
+func (r *Report) FormatJUnit(opts JUnitOptions) (string, error) {
+	suites := junitTestSuites{Name: r.Title}
+
+	for _, res := range r.Resources {
+		resource := res.Name
+		if res.Project != "" {
+			resource = res.Project + "/" + res.Name
+		}
+		suite := junitTestSuite{Name: fmt.Sprintf("%s %s", res.Type, resource)}
+		if !r.Timestamp.IsZero() {
+			suite.Timestamp = r.Timestamp.UTC().Format("2006-01-02T15:04:05")
+		}
+
+		failures := make(map[string][]junitFailure)
+		var fields []string
+		for _, d := range res.Drifts {
+			if _, seen := failures[d.Field]; !seen {
+				fields = append(fields, d.Field)
+			}
+			failures[d.Field] = append(failures[d.Field], junitFailure{
+				Message: fmt.Sprintf("[%s] %s: expected %s, actual %s", d.Severity, d.Field, d.Expected, d.Actual),
+				Type:    d.Severity,
+				Text:    fmt.Sprintf("Severity: %s\nExpected: %s\nActual: %s\n", d.Severity, d.Expected, d.Actual),
+			})
+		}
+		if opts.Fields != nil {
+			for _, field := range opts.Fields(res.Type) {
+				if _, drifted := failures[field]; !drifted {
+					fields = append(fields, field)
+					failures[field] = nil
+				}
+			}
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      field,
+				ClassName: fmt.Sprintf("%s.%s", res.Type, resource),
+				Failures:  failures[field],
+			})
+			if len(failures[field]) > 0 {
+				suite.Failures++
+			}
+		}
+		suite.Tests = len(suite.Cases)
+
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	// Projects that could not be scanned fail the run as errors rather than drift
+	if len(r.Errors) > 0 {
+		suite := junitTestSuite{Name: "Scan errors"}
+		for _, e := range r.Errors {
+			suite.Cases = append(suite.Cases, junitTestCase{
+				Name:      e.Project,
+				ClassName: "scan",
+				Errors:    []junitFailure{{Message: e.Error, Type: "scan_error"}},
+			})
+		}
+		suite.Tests = len(suite.Cases)
+		suite.Errors = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Errors += suite.Errors
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	data, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JUnit XML: %w", err)
+	}
+	return xml.Header + string(data), nil
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestFormatJUnit(t *testing.T) {
+	r := testGraphReport()
+	out, err := r.FormatJUnit(JUnitOptions{
+		Fields: func(resourceType string) []string {
+			return []string{"tier", "settings.backup_enabled", "disk_size_gb"}
+		},
+	})
+	if err != nil {
+		t.Fatalf("FormatJUnit() error = %v", err)
+	}
+	if !strings.HasPrefix(out, xml.Header) {
+		t.Error("FormatJUnit() should start with the XML header")
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatalf("invalid JUnit XML: %v", err)
+	}
+	if len(suites.Suites) != 3 {
+		t.Fatalf("got %d suites, want 3", len(suites.Suites))
+	}
+
+	// db-1: 3 drifted fields plus disk_size_gb passing
+	db1 := suites.Suites[0]
+	if db1.Name != "Cloud SQL proj-b/db-1" || db1.Tests != 4 || db1.Failures != 3 {
+		t.Errorf("db-1 suite = %s tests=%d failures=%d", db1.Name, db1.Tests, db1.Failures)
+	}
+	// db-2 has no drift, so every field passes
+	if db2 := suites.Suites[1]; db2.Tests != 3 || db2.Failures != 0 {
+		t.Errorf("db-2 suite tests=%d failures=%d, want 3/0", db2.Tests, db2.Failures)
+	}
+	if suites.Tests != 11 || suites.Failures != 4 {
+		t.Errorf("totals tests=%d failures=%d, want 11/4", suites.Tests, suites.Failures)
+	}
+
+	for _, tc := range db1.Cases {
+		if tc.Name == "settings.backup_enabled" {
+			if len(tc.Failures) != 1 || !strings.HasPrefix(tc.Failures[0].Message, "[critical]") {
+				t.Errorf("backup_enabled failure = %+v", tc.Failures)
+			}
+		}
+	}
+}
+
+func TestFormatJUnitScanErrors(t *testing.T) {
+	r := testGraphReport()
+	r.Errors = []ScanError{{Project: "locked-project", Error: "permission denied"}}
+	out, err := r.FormatJUnit(JUnitOptions{})
+	if err != nil {
+		t.Fatalf("FormatJUnit() error = %v", err)
+	}
+
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(strings.TrimPrefix(out, xml.Header)), &suites); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	if suites.Errors != 1 {
+		t.Errorf("errors = %d, want 1", suites.Errors)
+	}
+	last := suites.Suites[len(suites.Suites)-1]
+	if last.Name != "Scan errors" || len(last.Cases) != 1 || last.Cases[0].Name != "locked-project" {
+		t.Errorf("scan error suite = %+v", last)
+	}
+	if !strings.Contains(r.FormatText(), "locked-project: permission denied") {
+		t.Error("FormatText() should list scan errors")
+	}
+}
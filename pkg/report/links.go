@@ -0,0 +1,170 @@
+package report
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// consoleURL is the Google Cloud console links are generated for
+const consoleURL = "https://console.cloud.google.com"
+
+// LinkConfig controls the console links added to drifts. Organizations that
+// reach the console through a proxy or wrapper rewrite the links with a
+// template.
+type LinkConfig struct {
+	// Disabled leaves drifts without links
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	// BaseURL replaces https://console.cloud.google.com
+	BaseURL string `yaml:"base_url,omitempty" json:"base_url,omitempty"`
+	// AuthUser opens links as this Google account (email or account index),
+	// for browsers signed in to several accounts
+	AuthUser string `yaml:"authuser,omitempty" json:"authuser,omitempty"`
+	// Rewrite is a Go template producing the final link from a Link, e.g.
+	// https://proxy.example.com/open?target={{urlquery .URL}}
+	Rewrite string `yaml:"rewrite,omitempty" json:"rewrite,omitempty"`
+}
+
+// Link is a console link of a drift, as passed to the rewrite template
+type Link struct {
+	URL          string
+	ResourceType string
+	Project      string
+	Resource     string
+	Location     string
+	Field        string
+}
+
+// Linker adds console links to the drifts of reports
+type Linker struct {
+	base     string
+	authUser string
+	rewrite  *template.Template
+}
+
+// NewLinker creates a linker from the config; it returns nil when links are
+// disabled
+func NewLinker(config LinkConfig) (*Linker, error) {
+	if config.Disabled {
+		return nil, nil
+	}
+	l := &Linker{base: strings.TrimSuffix(config.BaseURL, "/"), authUser: config.AuthUser}
+	if l.base == "" {
+		l.base = consoleURL
+	}
+	if config.Rewrite != "" {
+		tmpl, err := template.New("link").Option("missingkey=error").Parse(config.Rewrite)
+		if err != nil {
+			return nil, fmt.Errorf("links: invalid rewrite template: %w", err)
+		}
+		l.rewrite = tmpl
+	}
+	return l, nil
+}
+
+// Annotate sets the console link of every drift whose resource type has a
+// console page. Drifts are shared with the report r was converted from, so
+// both are updated. A nil linker adds nothing.
+func (l *Linker) Annotate(r *Report) error {
+	if l == nil {
+		return nil
+	}
+	for _, res := range r.Resources {
+		for i := range res.Drifts {
+			link, err := l.Link(res, res.Drifts[i])
+			if err != nil {
+				return err
+			}
+			res.Drifts[i].Link = link
+		}
+	}
+	return nil
+}
+
+// Link returns the console link of a drift, or "" when the resource type has
+// no console page
+func (l *Linker) Link(res Resource, drift Drift) (string, error) {
+	path, query := consolePage(res, drift.Field)
+	if path == "" {
+		return "", nil
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	if res.Project != "" {
+		query.Set("project", res.Project)
+	}
+	if l.authUser != "" {
+		query.Set("authuser", l.authUser)
+	}
+	link := l.base + path + "?" + query.Encode()
+	if l.rewrite == nil {
+		return link, nil
+	}
+
+	var sb strings.Builder
+	err := l.rewrite.Execute(&sb, Link{
+		URL:          link,
+		ResourceType: res.Type,
+		Project:      res.Project,
+		Resource:     res.Name,
+		Location:     res.Location,
+		Field:        drift.Field,
+	})
+	if err != nil {
+		return "", fmt.Errorf("links: rewrite failed: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// consolePage returns the console path and query of the page where a drifted
+// field is changed, falling back to the resource's overview
+func consolePage(res Resource, field string) (string, url.Values) {
+	name := url.PathEscape(res.Name)
+	location := url.PathEscape(res.Location)
+	switch res.Type {
+	case "Cloud SQL":
+		switch {
+		case strings.HasPrefix(field, "required_databases"):
+			return "/sql/instances/" + name + "/databases", nil
+		case strings.HasPrefix(field, "replica") || strings.HasPrefix(field, "required_replica_count"):
+			return "/sql/instances/" + name + "/replicas", nil
+		case strings.HasPrefix(field, "required_users") || strings.HasPrefix(field, "users["):
+			return "/sql/instances/" + name + "/users", nil
+		case strings.HasPrefix(field, "ssl."):
+			return "/sql/instances/" + name + "/connections/security", nil
+		case strings.HasPrefix(field, "settings.") || strings.HasPrefix(field, "database_flags.") ||
+			strings.HasPrefix(field, "labels.") || strings.HasPrefix(field, "tier") || strings.HasPrefix(field, "disk_") ||
+			field == "iam_authentication":
+			return "/sql/instances/" + name + "/edit", nil
+		}
+		return "/sql/instances/" + name + "/overview", nil
+	case "GKE Cluster":
+		if pool, ok := nodePoolOf(field); ok {
+			return "/kubernetes/nodepool/" + location + "/" + name + "/" + url.PathEscape(pool), nil
+		}
+		return "/kubernetes/clusters/details/" + location + "/" + name + "/details", nil
+	case "Memorystore Redis":
+		return "/memorystore/redis/locations/" + location + "/instances/" + name + "/details/overview", nil
+	case "Pub/Sub Topic":
+		return "/cloudpubsub/topic/detail/" + name, nil
+	case "Pub/Sub Subscription":
+		return "/cloudpubsub/subscription/detail/" + name, nil
+	case "VPC Network":
+		return "/networking/networks/details/" + name, nil
+	case "BigQuery Dataset":
+		return "/bigquery", url.Values{"p": {res.Project}, "d": {res.Name}, "page": {"dataset"}}
+	}
+	return "", nil
+}
+
+// nodePoolOf returns the node pool of a field such as nodepool[default].machine_type
+func nodePoolOf(field string) (string, bool) {
+	rest, ok := strings.CutPrefix(field, "nodepool[")
+	if !ok {
+		return "", false
+	}
+	pool, _, ok := strings.Cut(rest, "]")
+	return pool, ok
+}
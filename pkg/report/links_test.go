@@ -0,0 +1,81 @@
+package report
+
+import "testing"
+
+func TestLinkerAnnotate(t *testing.T) {
+	linker, err := NewLinker(LinkConfig{})
+	if err != nil {
+		t.Fatalf("NewLinker() error = %v", err)
+	}
+
+	r := &Report{Resources: []Resource{
+		{
+			Type: "Cloud SQL", Project: "prod", Name: "db-1",
+			Drifts: []Drift{
+				{Field: "settings.backup_enabled"},
+				{Field: "required_databases"},
+				{Field: "replica_regions[us-east1]"},
+			},
+		},
+		{
+			Type: "GKE Cluster", Project: "prod", Name: "gke-1", Location: "us-central1",
+			Drifts: []Drift{
+				{Field: "nodepool[default-pool].machine_type"},
+				{Field: "master_version"},
+			},
+		},
+		{Type: "BigQuery Dataset", Project: "prod", Name: "analytics", Drifts: []Drift{{Field: "location"}}},
+		{Type: "Custom", Name: "x", Drifts: []Drift{{Field: "y"}}},
+	}}
+	if err := linker.Annotate(r); err != nil {
+		t.Fatalf("Annotate() error = %v", err)
+	}
+
+	want := [][]string{
+		{
+			"https://console.cloud.google.com/sql/instances/db-1/edit?project=prod",
+			"https://console.cloud.google.com/sql/instances/db-1/databases?project=prod",
+			"https://console.cloud.google.com/sql/instances/db-1/replicas?project=prod",
+		},
+		{
+			"https://console.cloud.google.com/kubernetes/nodepool/us-central1/gke-1/default-pool?project=prod",
+			"https://console.cloud.google.com/kubernetes/clusters/details/us-central1/gke-1/details?project=prod",
+		},
+		{"https://console.cloud.google.com/bigquery?d=analytics&p=prod&page=dataset&project=prod"},
+		{""},
+	}
+	for i, res := range r.Resources {
+		for j, drift := range res.Drifts {
+			if drift.Link != want[i][j] {
+				t.Errorf("%s %s link = %q, want %q", res.Name, drift.Field, drift.Link, want[i][j])
+			}
+		}
+	}
+}
+
+func TestLinkerRewrite(t *testing.T) {
+	linker, err := NewLinker(LinkConfig{
+		BaseURL:  "https://console.example.com/gcp/",
+		AuthUser: "sre@example.com",
+		Rewrite:  "https://proxy.example.com/open?for={{.Project}}&target={{urlquery .URL}}",
+	})
+	if err != nil {
+		t.Fatalf("NewLinker() error = %v", err)
+	}
+
+	link, err := linker.Link(Resource{Type: "VPC Network", Project: "net", Name: "shared"}, Drift{Field: "mtu"})
+	if err != nil {
+		t.Fatalf("Link() error = %v", err)
+	}
+	want := "https://proxy.example.com/open?for=net&target=https%3A%2F%2Fconsole.example.com%2Fgcp%2Fnetworking%2Fnetworks%2Fdetails%2Fshared%3Fauthuser%3Dsre%2540example.com%26project%3Dnet"
+	if link != want {
+		t.Errorf("Link() = %q, want %q", link, want)
+	}
+
+	if _, err := NewLinker(LinkConfig{Rewrite: "{{.URL"}); err == nil {
+		t.Error("NewLinker() with an invalid template succeeded, want an error")
+	}
+	if linker, _ := NewLinker(LinkConfig{Disabled: true}); linker != nil {
+		t.Error("NewLinker() with links disabled returned a linker")
+	}
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a single drift finding, flattened for log ingestion pipelines
+type Event struct {
+	ScanID       string            `json:"scan_id"`
+	Timestamp    time.Time         `json:"timestamp"`
+	ResourceType string            `json:"resource_type"`
+	Project      string            `json:"project,omitempty"`
+	Resource     string            `json:"resource"`
+	Location     string            `json:"location,omitempty"`
+	State        string            `json:"state,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Field        string            `json:"field"`
+	Expected     string            `json:"expected"`
+	Actual       string            `json:"actual"`
+	Severity     string            `json:"severity"`
+	Immutable    bool              `json:"immutable,omitempty"`
+	Operational  bool              `json:"operational,omitempty"`
+	Warning      string            `json:"warning,omitempty"`
+	Owners       []string          `json:"owners,omitempty"`
+	// HourlyCostDelta is the estimated cost change of the drift in USD per hour
+	HourlyCostDelta float64 `json:"hourly_cost_delta,omitempty"`
+	Link            string  `json:"link,omitempty"`
+}
+
+// NewScanID returns a unique identifier used to correlate the events of one scan
+func NewScanID() string {
+	return uuid.NewString()
+}
+
+// Events flattens the report into one event per drift finding
+func (r *Report) Events(scanID string) []Event {
+	events := make([]Event, 0)
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			events = append(events, Event{
+				ScanID:          scanID,
+				Timestamp:       r.Timestamp.UTC(),
+				ResourceType:    res.Type,
+				Project:         res.Project,
+				Resource:        res.Name,
+				Location:        res.Location,
+				State:           res.State,
+				Labels:          res.Labels,
+				Field:           d.Field,
+				Expected:        d.Expected,
+				Actual:          d.Actual,
+				Severity:        d.Severity,
+				Immutable:       d.Immutable,
+				Operational:     d.Operational,
+				Warning:         d.Warning,
+				Owners:          d.Owners,
+				HourlyCostDelta: d.HourlyCostDelta,
+				Link:            d.Link,
+			})
+		}
+	}
+	return events
+}
+
+// FormatNDJSON generates newline-delimited JSON with one object per drift finding
+func (r *Report) FormatNDJSON(scanID string) (string, error) {
+	var sb strings.Builder
+	for _, event := range r.Events(scanID) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal event: %w", err)
+		}
+		sb.Write(data)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,168 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Resource is a resource-agnostic view of a single analyzed resource and its drifts
+type Resource struct {
+	Type     string            `json:"type" yaml:"type"`
+	Project  string            `json:"project,omitempty" yaml:"project,omitempty"`
+	Name     string            `json:"name" yaml:"name"`
+	Location string            `json:"location,omitempty" yaml:"location,omitempty"`
+	State    string            `json:"state,omitempty" yaml:"state,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Drifts   []Drift           `json:"drifts" yaml:"drifts"`
+	// Metrics are measurements such as disk_gb, kept in history for forecasts
+	Metrics map[string]float64 `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+}
+
+// Report is a resource-agnostic drift report shared by analyzers and importers
+type Report struct {
+	Title     string     `json:"title" yaml:"title"`
+	Timestamp time.Time  `json:"timestamp" yaml:"timestamp"`
+	Resources []Resource `json:"resources" yaml:"resources"`
+	// Warnings flag baselines that are due for review
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	// Errors lists projects that could not be scanned
+	Errors []ScanError `json:"errors,omitempty" yaml:"errors,omitempty"`
+	// Acknowledgements lists the active acknowledgements of reported drifts
+	Acknowledgements []Acknowledgement `json:"acknowledgements,omitempty" yaml:"acknowledgements,omitempty"`
+	// SLO is the remediation SLO attainment per team, from history
+	SLO []SLOAttainment `json:"slo,omitempty" yaml:"slo,omitempty"`
+	// Summary is the compliance per resource type of a report covering
+	// several analyzers
+	Summary *ComplianceSummary `json:"summary,omitempty" yaml:"summary,omitempty"`
+}
+
+// DriftedCount returns the number of resources with at least one drift
+func (r *Report) DriftedCount() int {
+	count := 0
+	for _, res := range r.Resources {
+		if len(res.Drifts) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// AllDrifts returns every drift in the report, in resource order
+func (r *Report) AllDrifts() []Drift {
+	var drifts []Drift
+	for _, res := range r.Resources {
+		drifts = append(drifts, res.Drifts...)
+	}
+	return drifts
+}
+
+// FormatText generates a human-readable text report
+func (r *Report) FormatText() string {
+	var sb strings.Builder
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+	sb.WriteString(fmt.Sprintf("  %s\n", r.Title))
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("Generated: %s\n", r.Timestamp.Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Total Resources: %d\n", len(r.Resources)))
+	sb.WriteString(fmt.Sprintf("Resources with Drift: %d\n\n", r.DriftedCount()))
+
+	sb.WriteString(FormatCompliance(r.Summary))
+	sb.WriteString(FormatDriftSummary(CountBySeverity(r.AllDrifts())))
+	sb.WriteString(FormatBaselineWarnings(r.Warnings))
+	sb.WriteString(FormatAcknowledgements(r.Acknowledgements))
+	sb.WriteString(FormatSLO(r.SLO))
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("39")).
+		Background(lipgloss.Color("236")).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244")).
+		Bold(true)
+
+	valueStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("252"))
+
+	divider := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("───────────────────────────────────────────────────────────────────────────────")
+
+	for i, res := range r.Resources {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(divider + "\n")
+		sb.WriteString(headerStyle.Render(fmt.Sprintf("%s: %s", res.Type, res.Name)) + "\n\n")
+		if res.Project != "" {
+			sb.WriteString(labelStyle.Render("Project:  ") + valueStyle.Render(res.Project) + "\n")
+		}
+		if res.Location != "" {
+			sb.WriteString(labelStyle.Render("Location: ") + valueStyle.Render(res.Location) + "\n")
+		}
+		if res.State != "" {
+			sb.WriteString(labelStyle.Render("State:    ") + valueStyle.Render(res.State) + "\n")
+		}
+		sb.WriteString("\n")
+		sb.WriteString(FormatDrifts(res.Drifts))
+	}
+	sb.WriteString(FormatScanErrors(r.Errors))
+
+	return sb.String()
+}
+
+// FormatJSON generates JSON output of the report
+func (r *Report) FormatJSON() (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// FormatYAML generates YAML output of the report
+func (r *Report) FormatYAML() (string, error) {
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	return string(data), nil
+}
+
+// SplitByLabel partitions the report by the value of a resource label.
+// Resources without the label are grouped under the "unlabeled" key.
+func (r *Report) SplitByLabel(key string) map[string]*Report {
+	parts := make(map[string]*Report)
+	for _, res := range r.Resources {
+		value := res.Labels[key]
+		if value == "" {
+			value = "unlabeled"
+		}
+		part, ok := parts[value]
+		if !ok {
+			part = &Report{
+				Title:     fmt.Sprintf("%s (%s=%s)", r.Title, key, value),
+				Timestamp: r.Timestamp,
+				Resources: make([]Resource, 0),
+				// Every part shares the baseline and is missing the same projects
+				Warnings: r.Warnings,
+				Errors:   r.Errors,
+			}
+			parts[value] = part
+		}
+		part.Resources = append(part.Resources, res)
+		for _, d := range res.Drifts {
+			if ack, ok := Acknowledged(r.Acknowledgements, res, d); ok {
+				part.Acknowledgements = append(part.Acknowledgements, ack)
+			}
+		}
+	}
+	return parts
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	r := &Report{
+		Title:     "Test Drift Report",
+		Timestamp: time.Now(),
+		Resources: []Resource{
+			{Type: "Cloud SQL", Name: "db-1", Drifts: []Drift{{Field: "tier", Expected: "a", Actual: "b", Severity: "high"}}},
+			{Type: "Cloud SQL", Name: "db-2"},
+		},
+	}
+
+	if got := r.DriftedCount(); got != 1 {
+		t.Errorf("DriftedCount() = %v, want 1", got)
+	}
+	if got := len(r.AllDrifts()); got != 1 {
+		t.Errorf("len(AllDrifts()) = %v, want 1", got)
+	}
+
+	text := r.FormatText()
+	for _, want := range []string{"Test Drift Report", "Cloud SQL: db-1", "Resources with Drift: 1"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText() missing %q", want)
+		}
+	}
+
+	if _, err := r.FormatJSON(); err != nil {
+		t.Errorf("FormatJSON() error = %v", err)
+	}
+	if _, err := r.FormatYAML(); err != nil {
+		t.Errorf("FormatYAML() error = %v", err)
+	}
+}
+
+func TestFormatNDJSON(t *testing.T) {
+	r := &Report{
+		Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Resources: []Resource{
+			{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []Drift{
+				{Field: "tier", Expected: "a", Actual: "b", Severity: "high"},
+				{Field: "disk_type", Expected: "PD_SSD", Actual: "PD_HDD", Severity: "medium"},
+			}},
+			{Type: "Cloud SQL", Project: "p", Name: "db-2"},
+		},
+	}
+
+	out, err := r.FormatNDJSON("scan-1")
+	if err != nil {
+		t.Fatalf("FormatNDJSON() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+
+	want := `{"scan_id":"scan-1","timestamp":"2024-01-02T03:04:05Z","resource_type":"Cloud SQL","project":"p","resource":"db-1","field":"tier","expected":"a","actual":"b","severity":"high"}`
+	if lines[0] != want {
+		t.Errorf("line[0] = %s, want %s", lines[0], want)
+	}
+}
+
+func TestSplitByLabel(t *testing.T) {
+	r := &Report{
+		Title: "Drift",
+		Resources: []Resource{
+			{Name: "a", Labels: map[string]string{"team": "payments"}},
+			{Name: "b", Labels: map[string]string{"team": "search"}},
+			{Name: "c", Labels: map[string]string{"team": "payments"}},
+			{Name: "d"},
+		},
+	}
+
+	parts := r.SplitByLabel("team")
+	if len(parts) != 3 {
+		t.Fatalf("len(parts) = %d, want 3", len(parts))
+	}
+	if got := len(parts["payments"].Resources); got != 2 {
+		t.Errorf("payments resources = %d, want 2", got)
+	}
+	if got := len(parts["unlabeled"].Resources); got != 1 {
+		t.Errorf("unlabeled resources = %d, want 1", got)
+	}
+	if got := parts["search"].Title; got != "Drift (team=search)" {
+		t.Errorf("Title = %q, want %q", got, "Drift (team=search)")
+	}
+}
+
+func TestFormatAcknowledgements(t *testing.T) {
+	if got := FormatAcknowledgements(nil); got != "" {
+		t.Errorf("FormatAcknowledgements(nil) = %q, want empty", got)
+	}
+
+	until := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	r := &Report{
+		Title: "Drift",
+		Resources: []Resource{
+			{Type: "Cloud SQL", Project: "proj", Name: "db-1", Labels: map[string]string{"team": "payments"}, Drifts: []Drift{{Field: "tier"}}},
+			{Type: "Cloud SQL", Project: "proj", Name: "db-2", Labels: map[string]string{"team": "search"}, Drifts: []Drift{{Field: "tier"}}},
+		},
+		Acknowledgements: []Acknowledgement{
+			{ResourceType: "Cloud SQL", Resource: "proj/db-1", Field: "tier", By: "alice", Reason: "sized for launch", Until: until},
+		},
+	}
+
+	text := r.FormatText()
+	for _, want := range []string{"Acknowledged Findings (1)", "~ Cloud SQL proj/db-1 tier", "by alice", "until 2026-04-01T00:00:00Z", "reason: sized for launch"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("FormatText() missing %q", want)
+		}
+	}
+
+	parts := r.SplitByLabel("team")
+	if got := len(parts["payments"].Acknowledgements); got != 1 {
+		t.Errorf("payments acknowledgements = %d, want 1", got)
+	}
+	if got := len(parts["search"].Acknowledgements); got != 0 {
+		t.Errorf("search acknowledgements = %d, want 0", got)
+	}
+}
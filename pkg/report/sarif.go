@@ -0,0 +1,236 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// sarifSchema is the SARIF 2.1.0 JSON schema referenced by generated logs
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLevels maps drift severities onto SARIF result levels
+var sarifLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "note",
+}
+
+// sarifSecuritySeverity maps drift severities onto the CVSS-style scores
+// GitHub Code Scanning uses to rank security findings
+var sarifSecuritySeverity = map[string]string{
+	"critical": "9.5",
+	"high":     "7.5",
+	"medium":   "5.0",
+	"low":      "2.0",
+}
+
+// fieldKeys matches index qualifiers such as "[default-pool]" in drift fields
+var fieldKeys = regexp.MustCompile(`\[[^\]]*\]`)
+
+// SARIFRule describes the rule behind a drift finding
+type SARIFRule struct {
+	ID          string
+	Description string
+	Help        string
+}
+
+// SARIFOptions configures SARIF output
+type SARIFOptions struct {
+	ToolVersion string
+	// ArtifactURI is reported as the location of every result; code scanning
+	// requires one, so it is usually the baseline config file
+	ArtifactURI string
+	// Rule resolves the rule for a drift; when nil or it returns false the
+	// rule is derived from the drift field
+	Rule func(res Resource, d Drift) (SARIFRule, bool)
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string               `json:"name"`
+	Version        string               `json:"version,omitempty"`
+	InformationURI string               `json:"informationUri"`
+	Rules          []sarifReportingRule `json:"rules"`
+}
+
+type sarifReportingRule struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	Help                 *sarifMessage          `json:"help,omitempty"`
+	DefaultConfiguration sarifConfiguration     `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string                 `json:"ruleId"`
+	RuleIndex           int                    `json:"ruleIndex"`
+	Level               string                 `json:"level"`
+	Message             sarifMessage           `json:"message"`
+	Locations           []sarifLocation        `json:"locations"`
+	PartialFingerprints map[string]string      `json:"partialFingerprints"`
+	Properties          map[string]interface{} `json:"properties"`
+	// Suppressions record acknowledgements of the finding
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// FieldRuleID derives a rule ID from a drift field by replacing index
+// qualifiers with "*", e.g. "nodepool[default].machine_type" becomes
+// "nodepool[*].machine_type"
+func FieldRuleID(field string) string {
+	return fieldKeys.ReplaceAllString(field, "[*]")
+}
+
+// FormatSARIF generates a SARIF 2.1.0 log with one result per drift finding,
+// for upload to GitHub Code Scanning or Azure DevOps
+func (r *Report) FormatSARIF(opts SARIFOptions) (string, error) {
+	driver := sarifDriver{
+		Name:           "drift-analysis-cli",
+		Version:        opts.ToolVersion,
+		InformationURI: "https://github.com/jessequinn/drift-analysis-cli",
+		Rules:          []sarifReportingRule{},
+	}
+	ruleIndex := make(map[string]int)
+	results := []sarifResult{}
+
+	for _, res := range r.Resources {
+		for _, d := range res.Drifts {
+			rule, ok := SARIFRule{}, false
+			if opts.Rule != nil {
+				rule, ok = opts.Rule(res, d)
+			}
+			if !ok {
+				rule = SARIFRule{ID: FieldRuleID(d.Field), Description: fmt.Sprintf("%s %s drift", res.Type, FieldRuleID(d.Field))}
+			}
+
+			index, seen := ruleIndex[rule.ID]
+			if !seen {
+				index = len(driver.Rules)
+				ruleIndex[rule.ID] = index
+				reporting := sarifReportingRule{
+					ID:                   rule.ID,
+					ShortDescription:     sarifMessage{Text: rule.Description},
+					DefaultConfiguration: sarifConfiguration{Level: sarifLevels[d.Severity]},
+					Properties: map[string]interface{}{
+						"tags":              []string{"drift", res.Type},
+						"security-severity": sarifSecuritySeverity[d.Severity],
+					},
+				}
+				if rule.Help != "" {
+					reporting.Help = &sarifMessage{Text: rule.Help}
+				}
+				driver.Rules = append(driver.Rules, reporting)
+			}
+
+			resource := res.QualifiedName()
+
+			location := sarifLocation{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               res.Name,
+					FullyQualifiedName: resource,
+					Kind:               "resource",
+				}},
+			}
+			if opts.ArtifactURI != "" {
+				location.PhysicalLocation = &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: opts.ArtifactURI},
+					Region:           sarifRegion{StartLine: 1},
+				}
+			}
+
+			var suppressions []sarifSuppression
+			if ack, ok := Acknowledged(r.Acknowledgements, res, d); ok {
+				suppressions = []sarifSuppression{{
+					Kind:          "external",
+					Status:        "accepted",
+					Justification: ack.Reason,
+				}}
+			}
+
+			results = append(results, sarifResult{
+				RuleID:    rule.ID,
+				RuleIndex: index,
+				Level:     sarifLevels[d.Severity],
+				Message: sarifMessage{Text: fmt.Sprintf("%s %s: %s is %s, expected %s",
+					res.Type, resource, d.Field, d.Actual, d.Expected)},
+				Locations: []sarifLocation{location},
+				// Stable across runs so code scanning tracks the same alert
+				PartialFingerprints: map[string]string{
+					"driftFinding/v1": fmt.Sprintf("%s/%s/%s", res.Type, resource, d.Field),
+				},
+				Properties: map[string]interface{}{
+					"field":     d.Field,
+					"expected":  d.Expected,
+					"actual":    d.Actual,
+					"severity":  d.Severity,
+					"immutable": d.Immutable,
+				},
+				Suppressions: suppressions,
+			})
+		}
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal SARIF: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFieldRuleID(t *testing.T) {
+	if got := FieldRuleID("nodepool[default-pool].machine_type"); got != "nodepool[*].machine_type" {
+		t.Errorf("FieldRuleID() = %q", got)
+	}
+	if got := FieldRuleID("tier"); got != "tier" {
+		t.Errorf("FieldRuleID() = %q", got)
+	}
+}
+
+func TestFormatSARIF(t *testing.T) {
+	r := testGraphReport()
+	r.Resources = append(r.Resources, Resource{Type: "Cloud SQL", Project: "proj-c", Name: "db-4", Drifts: []Drift{
+		{Field: "tier", Expected: "db-custom-4-16384", Actual: "db-custom-2-8192", Severity: "high"},
+	}})
+
+	out, err := r.FormatSARIF(SARIFOptions{
+		ToolVersion: "1.0.0",
+		ArtifactURI: "config.yaml",
+		Rule: func(res Resource, d Drift) (SARIFRule, bool) {
+			if d.Field != "tier" {
+				return SARIFRule{}, false
+			}
+			return SARIFRule{ID: "sql.tier", Description: "Machine tier", Help: "gcloud sql instances patch"}, true
+		},
+	})
+	if err != nil {
+		t.Fatalf("FormatSARIF() error = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+
+	run := log.Runs[0]
+	if len(run.Results) != 5 {
+		t.Errorf("got %d results, want 5", len(run.Results))
+	}
+	// tier is reported twice but declared once
+	if len(run.Tool.Driver.Rules) != 4 {
+		t.Errorf("got %d rules, want 4", len(run.Tool.Driver.Rules))
+	}
+
+	for _, result := range run.Results {
+		rule := run.Tool.Driver.Rules[result.RuleIndex]
+		if rule.ID != result.RuleID {
+			t.Errorf("result %s points at rule %s", result.RuleID, rule.ID)
+		}
+		if result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "config.yaml" {
+			t.Errorf("result %s missing artifact location", result.RuleID)
+		}
+	}
+
+	last := run.Results[len(run.Results)-1]
+	if last.RuleID != "sql.tier" || last.Level != "error" {
+		t.Errorf("last result = %s/%s, want sql.tier/error", last.RuleID, last.Level)
+	}
+	if last.PartialFingerprints["driftFinding/v1"] != "Cloud SQL/proj-c/db-4/tier" {
+		t.Errorf("fingerprint = %v", last.PartialFingerprints)
+	}
+}
+
+func TestFormatSARIFAcknowledged(t *testing.T) {
+	r := testGraphReport()
+	r.Acknowledgements = []Acknowledgement{
+		{ResourceType: "Cloud SQL", Resource: "proj-a/db-3", Field: "disk_type", By: "alice", Reason: "HDD until migration"},
+	}
+
+	out, err := r.FormatSARIF(SARIFOptions{})
+	if err != nil {
+		t.Fatalf("FormatSARIF() error = %v", err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("invalid SARIF JSON: %v", err)
+	}
+
+	for _, result := range log.Runs[0].Results {
+		acknowledged := result.RuleID == "disk_type"
+		if got := len(result.Suppressions) == 1; got != acknowledged {
+			t.Errorf("result %s suppressions = %+v", result.RuleID, result.Suppressions)
+		}
+		if acknowledged && result.Suppressions[0].Justification != "HDD until migration" {
+			t.Errorf("justification = %q", result.Suppressions[0].Justification)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ScanError records a project that could not be scanned, so a partial report
+// says what it is missing
+type ScanError struct {
+	Project string `json:"project" yaml:"project"`
+	Error   string `json:"error" yaml:"error"`
+}
+
+// SortScanErrors orders scan errors by project, then message
+func SortScanErrors(errs []ScanError) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Project != errs[j].Project {
+			return errs[i].Project < errs[j].Project
+		}
+		return errs[i].Error < errs[j].Error
+	})
+}
+
+// FormatScanErrors renders the Errors section of a text report, or "" when
+// every project was scanned
+func FormatScanErrors(errs []ScanError) string {
+	if len(errs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("\n")
+	sb.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("196")).
+		Render(fmt.Sprintf("Errors (%d project(s) not scanned)", len(errs))) + "\n")
+	for _, e := range errs {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", e.Project, e.Error))
+	}
+	return sb.String()
+}
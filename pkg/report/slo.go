@@ -0,0 +1,58 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SLOAttainment is how well one team met its remediation target for drifts
+// of one severity, e.g. critical drift resolved within 48h
+type SLOAttainment struct {
+	Team     string `json:"team" yaml:"team"`
+	Severity string `json:"severity" yaml:"severity"`
+	// ResolveWithin is the target time to resolve a finding, e.g. 48h
+	ResolveWithin string `json:"resolve_within" yaml:"resolve_within"`
+	// Objective is the percentage of findings that must meet the target
+	Objective float64 `json:"objective" yaml:"objective"`
+	// Met were resolved within the target, Breached were resolved late or are
+	// still open past it, and Open are still open within it
+	Met      int `json:"met" yaml:"met"`
+	Breached int `json:"breached" yaml:"breached"`
+	Open     int `json:"open" yaml:"open"`
+	// Attainment is the percentage of met findings among the met and
+	// breached ones, 100 when there are none yet
+	Attainment float64 `json:"attainment" yaml:"attainment"`
+}
+
+// Meets reports whether the attainment reaches the objective
+func (s SLOAttainment) Meets() bool {
+	return s.Attainment >= s.Objective
+}
+
+// FormatSLO renders the SLO attainment section of a text report, or "" when
+// no SLO is configured
+func FormatSLO(slos []SLOAttainment) string {
+	if len(slos) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("75")).
+		Underline(true).
+		Render("Remediation SLO Attainment") + "\n")
+	for _, slo := range slos {
+		status := lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("MET")
+		if !slo.Meets() {
+			status = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true).Render("BREACHED")
+		}
+		sb.WriteString(fmt.Sprintf("  %-20s %-8s within %-6s %5.1f%% of %.1f%%  %s\n",
+			slo.Team, strings.ToUpper(slo.Severity), slo.ResolveWithin, slo.Attainment, slo.Objective, status))
+		sb.WriteString(fmt.Sprintf("      %d met, %d breached, %d open\n", slo.Met, slo.Breached, slo.Open))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
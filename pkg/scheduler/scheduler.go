@@ -0,0 +1,77 @@
+// Package scheduler runs analyses repeatedly on a fixed schedule so the CLI
+// can run as a long-lived process (e.g. a Kubernetes Deployment).
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseSchedule converts a schedule expression into an interval. Supported
+// forms are the cron-style descriptors "@hourly", "@daily" and "@weekly",
+// "@every <duration>", and a bare Go duration such as "30m".
+func ParseSchedule(expr string) (time.Duration, error) {
+	expr = strings.TrimSpace(expr)
+
+	var interval time.Duration
+	switch {
+	case expr == "":
+		return 0, fmt.Errorf("schedule is required")
+	case expr == "@hourly":
+		interval = time.Hour
+	case expr == "@daily" || expr == "@midnight":
+		interval = 24 * time.Hour
+	case expr == "@weekly":
+		interval = 7 * 24 * time.Hour
+	case strings.HasPrefix(expr, "@every "):
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return 0, fmt.Errorf("invalid schedule %q: %w", expr, err)
+		}
+		interval = d
+	default:
+		d, err := time.ParseDuration(expr)
+		if err != nil {
+			return 0, fmt.Errorf("invalid schedule %q: expected @hourly, @daily, @weekly, @every <duration> or a duration", expr)
+		}
+		interval = d
+	}
+
+	if interval < time.Minute {
+		return 0, fmt.Errorf("invalid schedule %q: interval must be at least 1m", expr)
+	}
+	return interval, nil
+}
+
+// Scheduler runs a job immediately and then once per interval
+type Scheduler struct {
+	Interval time.Duration
+	Job      func(ctx context.Context) error
+	// OnError is called when a run fails; the scheduler keeps running
+	OnError func(err error)
+}
+
+// Run executes the job until ctx is cancelled. A run that is still in
+// progress when the next tick fires delays that tick rather than overlapping.
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.Interval <= 0 {
+		return fmt.Errorf("scheduler interval must be positive")
+	}
+
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Job(ctx); err != nil && ctx.Err() == nil && s.OnError != nil {
+			s.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
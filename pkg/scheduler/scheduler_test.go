@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"@hourly", time.Hour, false},
+		{"@daily", 24 * time.Hour, false},
+		{"@weekly", 7 * 24 * time.Hour, false},
+		{"@every 15m", 15 * time.Minute, false},
+		{"6h", 6 * time.Hour, false},
+		{"", 0, true},
+		{"@every soon", 0, true},
+		{"*/5 * * * *", 0, true},
+		{"10s", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseSchedule(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSchedule(%q) error = %v, wantErr %v", tt.expr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSchedule(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	var errs []error
+	s := &Scheduler{
+		Interval: time.Millisecond,
+		Job: func(ctx context.Context) error {
+			runs++
+			if runs == 3 {
+				cancel()
+			}
+			return errors.New("scan failed")
+		},
+		OnError: func(err error) { errs = append(errs, err) },
+	}
+
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if runs != 3 {
+		t.Errorf("runs = %d, want 3", runs)
+	}
+	// The run that cancelled the context does not report its error
+	if len(errs) != 2 {
+		t.Errorf("errors reported = %d, want 2", len(errs))
+	}
+}
@@ -0,0 +1,356 @@
+// Package server exposes drift analyses over HTTP: a portal can trigger an
+// analysis, fetch the latest report of each analysis and look up the drift
+// of single resources without running the CLI.
+//
+// Endpoints, all returning JSON:
+//
+//	GET  /healthz                       liveness
+//	GET  /api/v1/analyses               the analyses and the state of their last run
+//	POST /api/v1/analyses/{kind}        start an analysis; 202, or 409 while it runs
+//	GET  /api/v1/analyses/{kind}        the state of the last run of an analysis
+//	GET  /api/v1/reports/{kind}         the latest report of an analysis
+//	GET  /api/v1/resources              resources of the latest reports, filtered
+//	                                    by ?type=, project=, name=, severity= and drifted=true
+//	GET  /api/v1/resources/{project}/{name}  the resources with that project and name
+//
+// With a token set, every endpoint but /healthz requires it as a bearer token.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// Run states
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// ErrRunning is returned by Trigger while the analysis is already running
+var ErrRunning = errors.New("analysis is already running")
+
+// Analysis runs one kind of analysis, e.g. every Cloud SQL baseline
+type Analysis func(ctx context.Context) (*report.Report, error)
+
+// Run is the state of the last run of an analysis
+type Run struct {
+	Kind       string     `json:"kind"`
+	Status     string     `json:"status"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	// Resources and Drifted count the resources of the latest report
+	Resources int `json:"resources"`
+	Drifted   int `json:"drifted"`
+	// ReportAt is when the latest report was produced; a failed run keeps
+	// the report of the run before it
+	ReportAt *time.Time `json:"report_at,omitempty"`
+}
+
+// ResourceResult is a resource of the latest report of an analysis
+type ResourceResult struct {
+	Analysis string `json:"analysis"`
+	report.Resource
+}
+
+// Server runs analyses on request and keeps their latest reports in memory
+type Server struct {
+	ctx      context.Context
+	analyses map[string]Analysis
+	logf     func(format string, args ...any)
+	token    string
+
+	mu      sync.Mutex
+	runs    map[string]*Run
+	reports map[string]*report.Report
+	wg      sync.WaitGroup
+}
+
+// New returns a server for the named analyses. Analyses run with ctx, so
+// cancelling it stops the runs in progress; logf receives run failures and
+// may be nil.
+func New(ctx context.Context, analyses map[string]Analysis, logf func(format string, args ...any)) *Server {
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	return &Server{
+		ctx:      ctx,
+		analyses: analyses,
+		logf:     logf,
+		runs:     make(map[string]*Run),
+		reports:  make(map[string]*report.Report),
+	}
+}
+
+// SetToken requires every request but /healthz to carry the token in an
+// "Authorization: Bearer <token>" header; an empty token disables the check
+func (s *Server) SetToken(token string) {
+	s.token = token
+}
+
+// Kinds returns the names of the analyses in order
+func (s *Server) Kinds() []string {
+	kinds := make([]string, 0, len(s.analyses))
+	for kind := range s.analyses {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Trigger starts an analysis in the background. It fails for an unknown
+// analysis and with ErrRunning while the analysis runs.
+func (s *Server) Trigger(kind string) (Run, error) {
+	analysis, ok := s.analyses[kind]
+	if !ok {
+		return Run{}, fmt.Errorf("unknown analysis %q", kind)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run := s.runs[kind]
+	if run != nil && run.Status == StatusRunning {
+		return *run, ErrRunning
+	}
+
+	next := &Run{Kind: kind, Status: StatusRunning, StartedAt: time.Now().UTC()}
+	if run != nil {
+		next.Resources, next.Drifted, next.ReportAt = run.Resources, run.Drifted, run.ReportAt
+	}
+	s.runs[kind] = next
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		r, err := analysis(s.ctx)
+		s.finish(kind, r, err)
+	}()
+	return *next, nil
+}
+
+// finish records the result of a run
+func (s *Server) finish(kind string, r *report.Report, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := s.runs[kind]
+	finished := time.Now().UTC()
+	run.FinishedAt = &finished
+	if err != nil {
+		run.Status = StatusFailed
+		run.Error = err.Error()
+		s.logf("%s analysis failed: %v", kind, err)
+		return
+	}
+	run.Status = StatusSucceeded
+	run.Resources, run.Drifted = len(r.Resources), r.DriftedCount()
+	run.ReportAt = &finished
+	s.reports[kind] = r
+}
+
+// Wait blocks until the analyses in progress finish
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+// Handler returns the HTTP handler of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /api/v1/analyses", s.listAnalyses)
+	mux.HandleFunc("POST /api/v1/analyses/{kind}", s.triggerAnalysis)
+	mux.HandleFunc("GET /api/v1/analyses/{kind}", s.getAnalysis)
+	mux.HandleFunc("GET /api/v1/reports/{kind}", s.getReport)
+	mux.HandleFunc("GET /api/v1/resources", s.listResources)
+	mux.HandleFunc("GET /api/v1/resources/{project}/{name}", s.getResource)
+	if s.token == "" {
+		return mux
+	}
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the bearer token; /healthz stays open
+// for liveness probes
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	want := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if r.URL.Path != "/healthz" && subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsLoopback reports whether a listen address such as 127.0.0.1:8080 only
+// accepts local connections. An empty host listens on every interface.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) listAnalyses(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	runs := make([]Run, 0, len(s.analyses))
+	for _, kind := range s.Kinds() {
+		run := Run{Kind: kind}
+		if last := s.runs[kind]; last != nil {
+			run = *last
+		}
+		runs = append(runs, run)
+	}
+	writeJSON(w, http.StatusOK, runs)
+}
+
+func (s *Server) triggerAnalysis(w http.ResponseWriter, r *http.Request) {
+	kind := r.PathValue("kind")
+	if _, ok := s.analyses[kind]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown analysis %q", kind))
+		return
+	}
+	run, err := s.Trigger(kind)
+	if errors.Is(err, ErrRunning) {
+		writeJSON(w, http.StatusConflict, run)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run)
+}
+
+func (s *Server) getAnalysis(w http.ResponseWriter, r *http.Request) {
+	kind := r.PathValue("kind")
+	if _, ok := s.analyses[kind]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown analysis %q", kind))
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	run := Run{Kind: kind}
+	if last := s.runs[kind]; last != nil {
+		run = *last
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (s *Server) getReport(w http.ResponseWriter, r *http.Request) {
+	kind := r.PathValue("kind")
+	if _, ok := s.analyses[kind]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown analysis %q", kind))
+		return
+	}
+	s.mu.Lock()
+	latest := s.reports[kind]
+	s.mu.Unlock()
+	if latest == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no %s report yet; POST /api/v1/analyses/%s to run one", kind, kind))
+		return
+	}
+	writeJSON(w, http.StatusOK, latest)
+}
+
+func (s *Server) listResources(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	match := func(res report.Resource) bool {
+		if v := query.Get("type"); v != "" && !strings.EqualFold(res.Type, v) {
+			return false
+		}
+		if v := query.Get("project"); v != "" && res.Project != v {
+			return false
+		}
+		if v := query.Get("name"); v != "" && res.Name != v {
+			return false
+		}
+		if query.Get("drifted") == "true" && len(res.Drifts) == 0 {
+			return false
+		}
+		return true
+	}
+	writeJSON(w, http.StatusOK, s.resources(match, query.Get("severity")))
+}
+
+func (s *Server) getResource(w http.ResponseWriter, r *http.Request) {
+	project, name := r.PathValue("project"), r.PathValue("name")
+	results := s.resources(func(res report.Resource) bool {
+		return res.Project == project && res.Name == name
+	}, "")
+	if len(results) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no resource %s/%s in the latest reports", project, name))
+		return
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// resources returns the matching resources of the latest reports, in
+// analysis order. A severity keeps only the drifts of that severity and the
+// resources with one.
+func (s *Server) resources(match func(report.Resource) bool, severity string) []ResourceResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	results := []ResourceResult{}
+	for _, kind := range s.Kinds() {
+		latest := s.reports[kind]
+		if latest == nil {
+			continue
+		}
+		for _, res := range latest.Resources {
+			if !match(res) {
+				continue
+			}
+			if severity != "" {
+				var drifts []report.Drift
+				for _, d := range res.Drifts {
+					if strings.EqualFold(d.Severity, severity) {
+						drifts = append(drifts, d)
+					}
+				}
+				if len(drifts) == 0 {
+					continue
+				}
+				res.Drifts = drifts
+			}
+			results = append(results, ResourceResult{Analysis: kind, Resource: res})
+		}
+	}
+	return results
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
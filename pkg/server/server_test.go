@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+func TestServer(t *testing.T) {
+	release := make(chan struct{})
+	fail := false
+	s := New(context.Background(), map[string]Analysis{
+		"sql": func(ctx context.Context) (*report.Report, error) {
+			<-release
+			if fail {
+				return nil, errors.New("quota exceeded")
+			}
+			return &report.Report{Resources: []report.Resource{
+				{Type: "Cloud SQL", Project: "p", Name: "db-1", Drifts: []report.Drift{
+					{Field: "tier", Severity: "high"},
+					{Field: "labels.team", Severity: "low"},
+				}},
+				{Type: "Cloud SQL", Project: "p", Name: "db-2"},
+			}}, nil
+		},
+	}, nil)
+	h := s.Handler()
+
+	get := func(path string, want int, v any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("GET %s = %d, want %d: %s", path, rec.Code, want, rec.Body)
+		}
+		if v != nil {
+			if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+				t.Fatalf("GET %s: %v", path, err)
+			}
+		}
+	}
+	post := func(path string, want int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, nil))
+		if rec.Code != want {
+			t.Fatalf("POST %s = %d, want %d: %s", path, rec.Code, want, rec.Body)
+		}
+	}
+
+	get("/api/v1/reports/sql", http.StatusNotFound, nil)
+	get("/api/v1/reports/redis", http.StatusNotFound, nil)
+	post("/api/v1/analyses/redis", http.StatusNotFound)
+
+	post("/api/v1/analyses/sql", http.StatusAccepted)
+	post("/api/v1/analyses/sql", http.StatusConflict)
+	var run Run
+	get("/api/v1/analyses/sql", http.StatusOK, &run)
+	if run.Status != StatusRunning {
+		t.Errorf("status = %s, want %s", run.Status, StatusRunning)
+	}
+	release <- struct{}{}
+	s.Wait()
+
+	get("/api/v1/analyses/sql", http.StatusOK, &run)
+	if run.Status != StatusSucceeded || run.Resources != 2 || run.Drifted != 1 {
+		t.Errorf("run = %+v, want succeeded with 2 resources, 1 drifted", run)
+	}
+	var latest report.Report
+	get("/api/v1/reports/sql", http.StatusOK, &latest)
+	if len(latest.Resources) != 2 {
+		t.Errorf("report has %d resources, want 2", len(latest.Resources))
+	}
+
+	var results []ResourceResult
+	get("/api/v1/resources?drifted=true", http.StatusOK, &results)
+	if len(results) != 1 || results[0].Name != "db-1" || results[0].Analysis != "sql" {
+		t.Errorf("drifted resources = %+v, want db-1 of sql", results)
+	}
+	get("/api/v1/resources?severity=low", http.StatusOK, &results)
+	if len(results) != 1 || len(results[0].Drifts) != 1 || results[0].Drifts[0].Field != "labels.team" {
+		t.Errorf("low resources = %+v, want db-1 with its low drift", results)
+	}
+	get("/api/v1/resources/p/db-2", http.StatusOK, &results)
+	if len(results) != 1 || len(results[0].Drifts) != 0 {
+		t.Errorf("p/db-2 = %+v, want the compliant resource", results)
+	}
+	get("/api/v1/resources/p/missing", http.StatusNotFound, nil)
+
+	// A failed run keeps the previous report
+	fail = true
+	post("/api/v1/analyses/sql", http.StatusAccepted)
+	release <- struct{}{}
+	s.Wait()
+	get("/api/v1/analyses/sql", http.StatusOK, &run)
+	if run.Status != StatusFailed || run.Error != "quota exceeded" || run.ReportAt == nil {
+		t.Errorf("run = %+v, want failed with the previous report kept", run)
+	}
+	get("/api/v1/reports/sql", http.StatusOK, &latest)
+}
+
+func TestServer_Token(t *testing.T) {
+	s := New(context.Background(), map[string]Analysis{
+		"sql": func(ctx context.Context) (*report.Report, error) { return &report.Report{}, nil },
+	}, nil)
+	s.SetToken("s3cret")
+	h := s.Handler()
+
+	tests := []struct {
+		method, path, auth string
+		want               int
+	}{
+		{http.MethodGet, "/healthz", "", http.StatusOK},
+		{http.MethodGet, "/api/v1/analyses", "", http.StatusUnauthorized},
+		{http.MethodPost, "/api/v1/analyses/sql", "Bearer wrong", http.StatusUnauthorized},
+		{http.MethodPost, "/api/v1/analyses/sql", "s3cret", http.StatusUnauthorized},
+		{http.MethodGet, "/api/v1/analyses", "Bearer s3cret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s %s with %q = %d, want %d", tt.method, tt.path, tt.auth, rec.Code, tt.want)
+		}
+	}
+	s.Wait()
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+		"8080":           false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
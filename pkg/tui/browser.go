@@ -0,0 +1,278 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
+)
+
+// severityFilters are cycled with the severity key; "" shows every resource
+var severityFilters = []string{"", "critical", "high", "medium", "low"}
+
+// browserHeaderLines is the number of lines above the first resource in the list
+const browserHeaderLines = 2
+
+// browser is the state of the resources tab: a selectable list of resources,
+// narrowed by severity and a name search, and the drift view of one resource
+type browser struct {
+	items []DriftItem
+	// visible are the indices of the items matching the filters
+	visible   []int
+	cursor    int
+	severity  string
+	query     string
+	searching bool
+	// open shows the drifts of the selected resource instead of the list
+	open bool
+}
+
+func newBrowser(items []DriftItem) *browser {
+	b := &browser{items: items}
+	b.filter()
+	return b
+}
+
+// selected returns the index of the item under the cursor, or -1
+func (b *browser) selected() int {
+	if b.cursor < len(b.visible) {
+		return b.visible[b.cursor]
+	}
+	return -1
+}
+
+// filter recomputes the visible items, keeping the cursor on the selected
+// item when it still matches
+func (b *browser) filter() {
+	selected := b.selected()
+	query := strings.ToLower(b.query)
+
+	b.visible = b.visible[:0]
+	b.cursor = 0
+	for i, item := range b.items {
+		if b.severity != "" && len(filterDriftsBySeverity(item.Drifts, b.severity)) == 0 {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(item.Project+"/"+item.Name), query) {
+			continue
+		}
+		if i == selected {
+			b.cursor = len(b.visible)
+		}
+		b.visible = append(b.visible, i)
+	}
+}
+
+// setItems replaces the resources, keeping the filters and, when it is still
+// listed, the selected resource
+func (b *browser) setItems(items []DriftItem) {
+	var selected DriftItem
+	if i := b.selected(); i >= 0 {
+		selected = b.items[i]
+	}
+	b.items = items
+	b.visible = b.visible[:0]
+	b.filter()
+	for pos, i := range b.visible {
+		item := items[i]
+		if item.ResourceType == selected.ResourceType && item.Project == selected.Project && item.Name == selected.Name {
+			b.cursor = pos
+		}
+	}
+	b.open = b.open && b.selected() >= 0
+}
+
+// update handles a key on the resources tab and reports whether it was used.
+// While searching every key but ctrl+c edits the query.
+func (b *browser) update(msg tea.KeyMsg, keys KeyMap) bool {
+	if b.searching {
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return false
+		case tea.KeyEnter:
+			b.searching = false
+		case tea.KeyEsc:
+			b.searching = false
+			b.query = ""
+			b.filter()
+		case tea.KeyBackspace:
+			if runes := []rune(b.query); len(runes) > 0 {
+				b.query = string(runes[:len(runes)-1])
+				b.filter()
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			b.query += string(msg.Runes)
+			b.filter()
+		}
+		return true
+	}
+
+	if b.open {
+		if key.Matches(msg, keys.Back) {
+			b.open = false
+			return true
+		}
+		// The viewport scrolls the drift view
+		return false
+	}
+
+	switch {
+	case key.Matches(msg, keys.Up):
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if b.cursor < len(b.visible)-1 {
+			b.cursor++
+		}
+	case key.Matches(msg, keys.Open):
+		b.open = b.selected() >= 0
+	case key.Matches(msg, keys.Severity):
+		for i, s := range severityFilters {
+			if s == b.severity {
+				b.severity = severityFilters[(i+1)%len(severityFilters)]
+				break
+			}
+		}
+		b.filter()
+	case key.Matches(msg, keys.Search):
+		b.searching = true
+	case key.Matches(msg, keys.Back) && (b.query != "" || b.severity != ""):
+		b.query, b.severity = "", ""
+		b.filter()
+	default:
+		return false
+	}
+	return true
+}
+
+// cursorLine is the line of the selected resource in the list view
+func (b *browser) cursorLine() int {
+	return browserHeaderLines + b.cursor
+}
+
+// view renders the list or the drift view of the selected resource
+func (b *browser) view() string {
+	if b.open {
+		if i := b.selected(); i >= 0 {
+			return b.detailView(b.items[i])
+		}
+	}
+	return b.listView()
+}
+
+// listView renders one line per visible resource with its worst severity
+func (b *browser) listView() string {
+	var sb strings.Builder
+
+	headerStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("cyan"))
+	filterStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244"))
+
+	filters := []string{"severity: " + orAll(b.severity)}
+	if b.query != "" || b.searching {
+		search := fmt.Sprintf("search: %q", b.query)
+		if b.searching {
+			search = "search: " + b.query + "█"
+		}
+		filters = append(filters, search)
+	}
+	sb.WriteString(headerStyle.Render(fmt.Sprintf("Resources (%d of %d)", len(b.visible), len(b.items))) +
+		filterStyle.Render("  "+strings.Join(filters, " • ")) + "\n\n")
+
+	if len(b.visible) == 0 {
+		sb.WriteString(filterStyle.Render("  No resources match the filters") + "\n")
+		return sb.String()
+	}
+
+	selectedStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("15")).
+		Background(lipgloss.Color("63"))
+	okStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("46"))
+
+	width := 0
+	for _, i := range b.visible {
+		width = max(width, len(b.items[i].Project)+1+len(b.items[i].Name))
+	}
+	for pos, i := range b.visible {
+		item := b.items[i]
+		name := fmt.Sprintf("%-12s %-*s", item.ResourceType, width, item.Project+"/"+item.Name)
+		if pos == b.cursor {
+			name = selectedStyle.Render("> " + name)
+		} else {
+			name = "  " + name
+		}
+
+		summary := okStyle.Render("[OK]")
+		if len(item.Drifts) > 0 {
+			worst := worstSeverity(item.Drifts)
+			summary = getSeverityStyle(worst).Render(fmt.Sprintf("%d drift(s), worst %s", len(item.Drifts), worst))
+		}
+		sb.WriteString(name + "  " + summary + "\n")
+	}
+	return sb.String()
+}
+
+// detailView renders every drift of one resource, narrowed by the severity filter
+func (b *browser) detailView(item DriftItem) string {
+	var sb strings.Builder
+
+	sb.WriteString(formatDriftItem(item, b.severity))
+
+	if len(item.Labels) > 0 {
+		labelStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("244"))
+		sb.WriteString("\n" + labelStyle.Render("  Labels:") + "\n")
+		for _, k := range sortedKeys(item.Labels) {
+			sb.WriteString(labelStyle.Render(fmt.Sprintf("    %s=%s", k, item.Labels[k])) + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// help describes the keys of the current browser state
+func (b *browser) help() string {
+	switch {
+	case b.searching:
+		return " type to search • enter: keep • esc: clear "
+	case b.open:
+		return " esc: back • ↑/↓/pgup/pgdn: scroll • q: quit "
+	default:
+		return " ↑/↓: select • enter: open • s: severity • /: search • tab: next • q: quit "
+	}
+}
+
+// worstSeverity returns the highest severity among the drifts
+func worstSeverity(drifts []DriftDetail) string {
+	worst := ""
+	for _, d := range drifts {
+		if report.SeverityRank(d.Severity) > report.SeverityRank(worst) {
+			worst = d.Severity
+		}
+	}
+	return worst
+}
+
+func orAll(severity string) string {
+	if severity == "" {
+		return "all"
+	}
+	return severity
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
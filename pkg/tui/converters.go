@@ -3,6 +3,7 @@ package tui
 import (
 	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/gke"
 	"github.com/jessequinn/drift-analysis-cli/pkg/gcp/sql"
+	"github.com/jessequinn/drift-analysis-cli/pkg/report"
 )
 
 // FromSQLReport converts a SQL drift report to TUI format
@@ -10,15 +11,7 @@ func FromSQLReport(report *sql.DriftReport) ReportData {
 	items := make([]DriftItem, 0, len(report.Instances))
 
 	for _, inst := range report.Instances {
-		drifts := make([]DriftDetail, 0, len(inst.Drifts))
-		for _, d := range inst.Drifts {
-			drifts = append(drifts, DriftDetail{
-				Field:    d.Field,
-				Expected: d.Expected,
-				Actual:   d.Actual,
-				Severity: d.Severity,
-			})
-		}
+		drifts := driftDetails(inst.Drifts)
 
 		items = append(items, DriftItem{
 			ResourceType: "Cloud SQL",
@@ -32,7 +25,7 @@ func FromSQLReport(report *sql.DriftReport) ReportData {
 	}
 
 	return ReportData{
-		Title:            "GCP PostgreSQL Drift Analysis Report",
+		Title:            "GCP Cloud SQL Drift Analysis Report",
 		Timestamp:        report.Timestamp,
 		TotalResources:   report.TotalInstances,
 		DriftedResources: report.DriftedInstances,
@@ -45,15 +38,7 @@ func FromGKEReport(report *gke.DriftReport) ReportData {
 	items := make([]DriftItem, 0, len(report.Instances))
 
 	for _, cluster := range report.Instances {
-		drifts := make([]DriftDetail, 0, len(cluster.Drifts))
-		for _, d := range cluster.Drifts {
-			drifts = append(drifts, DriftDetail{
-				Field:    d.Field,
-				Expected: d.Expected,
-				Actual:   d.Actual,
-				Severity: d.Severity,
-			})
-		}
+		drifts := driftDetails(cluster.Drifts)
 
 		items = append(items, DriftItem{
 			ResourceType: "GKE Cluster",
@@ -74,3 +59,53 @@ func FromGKEReport(report *gke.DriftReport) ReportData {
 		Items:            items,
 	}
 }
+
+// FromReport converts a generic report to TUI format
+func FromReport(r *report.Report) ReportData {
+	items := make([]DriftItem, 0, len(r.Resources))
+
+	for _, res := range r.Resources {
+		drifts := driftDetails(res.Drifts)
+
+		items = append(items, DriftItem{
+			ResourceType: res.Type,
+			Project:      res.Project,
+			Name:         res.Name,
+			Location:     res.Location,
+			State:        res.State,
+			Labels:       res.Labels,
+			Drifts:       drifts,
+		})
+	}
+
+	return ReportData{
+		Title:            r.Title,
+		Timestamp:        r.Timestamp,
+		TotalResources:   len(r.Resources),
+		DriftedResources: r.DriftedCount(),
+		Items:            items,
+	}
+}
+
+// driftDetails converts report drifts, showing the fix command or, when
+// there is none, the remediation instructions
+func driftDetails(drifts []report.Drift) []DriftDetail {
+	details := make([]DriftDetail, 0, len(drifts))
+	for _, d := range drifts {
+		detail := DriftDetail{
+			Field:     d.Field,
+			Expected:  d.Expected,
+			Actual:    d.Actual,
+			Severity:  d.Severity,
+			Immutable: d.Immutable,
+		}
+		if fix := d.Remediation; fix != nil {
+			detail.Remediation = fix.Command
+			if detail.Remediation == "" {
+				detail.Remediation = fix.Instructions
+			}
+		}
+		details = append(details, detail)
+	}
+	return details
+}
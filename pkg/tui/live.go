@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Reloader re-runs the analysis behind a report
+type Reloader func(ctx context.Context) (ReportData, error)
+
+// live is the state of a TUI that can re-run its analysis
+type live struct {
+	reload  Reloader
+	spinner spinner.Model
+	running bool
+	cancel  context.CancelFunc
+	data    ReportData
+	// previous is the compliance rate before the last reload, shown as a delta
+	previous float64
+	updated  time.Time
+	err      error
+}
+
+// reloadedMsg carries the result of a reload
+type reloadedMsg struct {
+	data ReportData
+	err  error
+}
+
+// RunLive starts the TUI with report data that is re-analyzed when r is
+// pressed. The analysis runs in the background while a spinner is shown, and
+// the tabs and compliance numbers are replaced when it finishes.
+func RunLive(data ReportData, reload Reloader) error {
+	model := newReportModel(data)
+	model.live = &live{
+		reload:   reload,
+		spinner:  spinner.New(spinner.WithSpinner(spinner.Dot)),
+		data:     data,
+		previous: complianceRate(data),
+		updated:  time.Now(),
+	}
+	defer model.live.stop()
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// start begins a reload and returns the commands running it and the spinner
+func (l *live) start() tea.Cmd {
+	if l.running {
+		return nil
+	}
+	l.running = true
+	l.err = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+	reload := l.reload
+	return tea.Batch(l.spinner.Tick, func() tea.Msg {
+		data, err := reload(ctx)
+		return reloadedMsg{data: data, err: err}
+	})
+}
+
+// finish records the result of a reload; it reports whether data changed
+func (l *live) finish(msg reloadedMsg) bool {
+	l.running = false
+	l.cancel()
+	if msg.err != nil {
+		l.err = msg.err
+		return false
+	}
+	l.previous = complianceRate(l.data)
+	l.data = msg.data
+	l.updated = time.Now()
+	return true
+}
+
+// stop cancels a running reload
+func (l *live) stop() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+}
+
+// status renders the compliance numbers and the reload state for the header
+func (l *live) status() string {
+	infoStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("244"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196"))
+
+	rate := complianceRate(l.data)
+	status := fmt.Sprintf("compliance %.1f%%", rate)
+	if delta := rate - l.previous; delta != 0 {
+		status += fmt.Sprintf(" (%+.1f)", delta)
+	}
+	status += fmt.Sprintf(" • %d/%d drifted", l.data.DriftedResources, l.data.TotalResources)
+
+	switch {
+	case l.running:
+		return infoStyle.Render(status+" • ") + l.spinner.View() + infoStyle.Render(" re-analyzing...")
+	case l.err != nil:
+		return infoStyle.Render(status+" • ") + errorStyle.Render("re-run failed: "+l.err.Error())
+	default:
+		return infoStyle.Render(status + " • updated " + l.updated.Format("15:04:05") + " • r: re-run")
+	}
+}
+
+// setData replaces the report shown, keeping the active tab and the filters
+// and selection of the resource list
+func (m *Model) setData(data ReportData) {
+	m.tabs = buildTabs(data)
+	if m.browser != nil {
+		m.browser.setItems(data.Items)
+	}
+	m.viewport.SetContent(m.content())
+}
+
+// complianceRate is the percentage of resources without drift
+func complianceRate(data ReportData) float64 {
+	if data.TotalResources == 0 {
+		return 100
+	}
+	return float64(data.TotalResources-data.DriftedResources) / float64(data.TotalResources) * 100
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,6 +15,9 @@ import (
 type Tab struct {
 	Title   string
 	Content string
+	// Browser marks the tab showing the interactive resource list instead
+	// of Content
+	Browser bool
 }
 
 // Model represents the TUI state
@@ -25,6 +29,8 @@ type Model struct {
 	width        int
 	height       int
 	keyMap       KeyMap
+	browser      *browser
+	live         *live
 }
 
 // KeyMap defines the keyboard shortcuts
@@ -37,6 +43,11 @@ type KeyMap struct {
 	PageDown     key.Binding
 	HalfPageUp   key.Binding
 	HalfPageDown key.Binding
+	Open         key.Binding
+	Back         key.Binding
+	Severity     key.Binding
+	Search       key.Binding
+	Reload       key.Binding
 	Quit         key.Binding
 }
 
@@ -75,6 +86,26 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("d", "ctrl+d"),
 			key.WithHelp("d", "½ page down"),
 		),
+		Open: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "open resource"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("esc", "backspace"),
+			key.WithHelp("esc", "back"),
+		),
+		Severity: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "filter by severity"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search by name"),
+		),
+		Reload: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "re-run analysis"),
+		),
 		Quit: key.NewBinding(
 			key.WithKeys("q", "ctrl+c", "esc"),
 			key.WithHelp("q", "quit"),
@@ -102,21 +133,40 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.onBrowser() && m.browser.update(msg, m.keyMap) {
+			m.refreshBrowser()
+			return m, nil
+		}
 		switch {
 		case key.Matches(msg, m.keyMap.Quit):
 			return m, tea.Quit
+		case m.live != nil && key.Matches(msg, m.keyMap.Reload):
+			return m, m.live.start()
 		case key.Matches(msg, m.keyMap.NextTab):
 			m.activeTab = (m.activeTab + 1) % len(m.tabs)
-			m.viewport.SetContent(m.tabs[m.activeTab].Content)
+			m.viewport.SetContent(m.content())
 			m.viewport.GotoTop()
 			return m, nil
 		case key.Matches(msg, m.keyMap.PrevTab):
 			m.activeTab = (m.activeTab - 1 + len(m.tabs)) % len(m.tabs)
-			m.viewport.SetContent(m.tabs[m.activeTab].Content)
+			m.viewport.SetContent(m.content())
 			m.viewport.GotoTop()
 			return m, nil
 		}
 
+	case reloadedMsg:
+		if m.live != nil && m.live.finish(msg) {
+			m.setData(msg.data)
+		}
+		return m, nil
+
+	case spinner.TickMsg:
+		if m.live == nil || !m.live.running {
+			return m, nil
+		}
+		m.live.spinner, cmd = m.live.spinner.Update(msg)
+		return m, cmd
+
 	case tea.WindowSizeMsg:
 		headerHeight := lipgloss.Height(m.headerView())
 		footerHeight := lipgloss.Height(m.footerView())
@@ -126,7 +176,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport = viewport.New(msg.Width, msg.Height-verticalMargins)
 			m.viewport.YPosition = headerHeight
 			if len(m.tabs) > 0 {
-				m.viewport.SetContent(m.tabs[m.activeTab].Content)
+				m.viewport.SetContent(m.content())
 			}
 			m.ready = true
 		} else {
@@ -182,6 +232,9 @@ func (m Model) headerView() string {
 		Padding(0, 1)
 
 	title := titleStyle.Render("Drift Analysis Report")
+	if m.live != nil {
+		title = lipgloss.JoinHorizontal(lipgloss.Top, title, m.live.status())
+	}
 
 	header := lipgloss.JoinVertical(lipgloss.Left,
 		title,
@@ -197,7 +250,7 @@ func (m Model) footerView() string {
 	// Get content from current tab instead of viewport
 	content := ""
 	if m.activeTab < len(m.tabs) {
-		content = m.tabs[m.activeTab].Content
+		content = m.content()
 	}
 
 	info := lipgloss.NewStyle().
@@ -212,6 +265,9 @@ func (m Model) footerView() string {
 		Foreground(lipgloss.Color("244"))
 
 	help := helpStyle.Render(" tab: next • ←/→: switch • ↑/↓/pgup/pgdn: scroll • q: quit ")
+	if m.onBrowser() {
+		help = helpStyle.Render(m.browser.help())
+	}
 
 	line := strings.Repeat("─", max(0, m.width-lipgloss.Width(info)-lipgloss.Width(help)))
 
@@ -219,6 +275,35 @@ func (m Model) footerView() string {
 	return footer
 }
 
+// onBrowser reports whether the active tab is the resource list
+func (m Model) onBrowser() bool {
+	return m.browser != nil && m.activeTab < len(m.tabs) && m.tabs[m.activeTab].Browser
+}
+
+// content returns the text of the active tab
+func (m Model) content() string {
+	if m.onBrowser() {
+		return m.browser.view()
+	}
+	return m.tabs[m.activeTab].Content
+}
+
+// refreshBrowser renders the browser into the viewport, keeping the selected
+// resource in view in the list and starting a resource's drifts at the top
+func (m *Model) refreshBrowser() {
+	m.viewport.SetContent(m.browser.view())
+	if m.browser.open {
+		m.viewport.GotoTop()
+		return
+	}
+	line := m.browser.cursorLine()
+	if line < m.viewport.YOffset {
+		m.viewport.SetYOffset(line)
+	} else if line >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(line - m.viewport.Height + 1)
+	}
+}
+
 func max(a, b int) int {
 	if a > b {
 		return a
@@ -22,10 +22,13 @@ type DriftItem struct {
 
 // DriftDetail represents a single drift
 type DriftDetail struct {
-	Field    string
-	Expected string
-	Actual   string
-	Severity string
+	Field     string
+	Expected  string
+	Actual    string
+	Severity  string
+	Immutable bool
+	// Remediation is the fix command or instructions, when the report has them
+	Remediation string
 }
 
 // ReportData holds the complete report data for TUI
@@ -39,13 +42,18 @@ type ReportData struct {
 
 // Run starts the TUI with the provided report data
 func Run(data ReportData) error {
-	tabs := buildTabs(data)
-	model := NewModel(tabs)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	p := tea.NewProgram(newReportModel(data), tea.WithAltScreen())
 	_, err := p.Run()
 	return err
 }
 
+// newReportModel creates a model with the tabs and resource list of a report
+func newReportModel(data ReportData) Model {
+	model := NewModel(buildTabs(data))
+	model.browser = newBrowser(data.Items)
+	return model
+}
+
 // buildTabs creates tabs from report data
 func buildTabs(data ReportData) []Tab {
 	tabs := []Tab{
@@ -53,6 +61,10 @@ func buildTabs(data ReportData) []Tab {
 			Title:   "Overview",
 			Content: buildOverviewTab(data),
 		},
+		{
+			Title:   "Resources",
+			Browser: true,
+		},
 		{
 			Title:   "Critical",
 			Content: buildSeverityTab(data, "critical"),
@@ -276,12 +288,24 @@ func formatDriftItem(item DriftItem, filterSeverity string) string {
 			actualStyle := lipgloss.NewStyle().
 				Foreground(lipgloss.Color("196"))
 
-			sb.WriteString(fmt.Sprintf("    %s %s %s\n",
+			marker := ""
+			if drift.Immutable {
+				marker = " " + lipgloss.NewStyle().
+					Foreground(lipgloss.Color("201")).
+					Bold(true).
+					Render("[RECREATE]")
+			}
+
+			sb.WriteString(fmt.Sprintf("    %s %s %s%s\n",
 				icon,
 				severityStyle.Render(fmt.Sprintf("[%s]", strings.ToUpper(drift.Severity))),
-				fieldStyle.Render(drift.Field)))
+				fieldStyle.Render(drift.Field),
+				marker))
 			sb.WriteString(labelStyle.Render("       Expected: ") + expectedStyle.Render(drift.Expected) + "\n")
 			sb.WriteString(labelStyle.Render("       Actual:   ") + actualStyle.Render(drift.Actual) + "\n")
+			if drift.Remediation != "" {
+				sb.WriteString(labelStyle.Render("       Fix:      ") + drift.Remediation + "\n")
+			}
 		}
 	}
 
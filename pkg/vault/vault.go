@@ -0,0 +1,270 @@
+// Package vault reads database credentials and SSH keys from HashiCorp Vault
+// over its HTTP API: static secrets from the KV engine (version 1 or 2) and
+// dynamic credentials from the database secrets engine.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Secret engines
+const (
+	EngineKV       = "kv"
+	EngineDatabase = "database"
+)
+
+// defaultTimeout bounds each request to Vault
+const defaultTimeout = 30 * time.Second
+
+var httpClient = &http.Client{Timeout: defaultTimeout}
+
+// Secret locates a secret in Vault. The server and token default to the
+// VAULT_ADDR, VAULT_NAMESPACE and VAULT_TOKEN environment variables, falling
+// back to the ~/.vault-token file written by `vault login`.
+type Secret struct {
+	// Engine is kv (default) or database
+	Engine string `yaml:"engine,omitempty"`
+	// Mount is the path the engine is mounted at (default: secret for kv,
+	// database for database)
+	Mount string `yaml:"mount,omitempty"`
+	// Path of a kv secret below the mount
+	Path string `yaml:"path,omitempty"`
+	// KVVersion is the version of the kv engine: 1 or 2 (default)
+	KVVersion int `yaml:"kv_version,omitempty"`
+	// Role of the database engine whose credentials are generated
+	Role string `yaml:"role,omitempty"`
+	// Address overrides VAULT_ADDR
+	Address string `yaml:"address,omitempty"`
+	// Namespace overrides VAULT_NAMESPACE (Vault Enterprise)
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// Validate checks the engine and the fields it needs
+func (s *Secret) Validate() error {
+	switch s.Engine {
+	case "", EngineKV:
+		if s.Path == "" {
+			return fmt.Errorf("vault: path is required for the kv engine")
+		}
+		if s.KVVersion != 0 && s.KVVersion != 1 && s.KVVersion != 2 {
+			return fmt.Errorf("vault: invalid kv_version %d (1 or 2)", s.KVVersion)
+		}
+	case EngineDatabase:
+		if s.Role == "" {
+			return fmt.Errorf("vault: role is required for the database engine")
+		}
+	default:
+		return fmt.Errorf("vault: unknown engine %q (kv or database)", s.Engine)
+	}
+	return nil
+}
+
+// Read returns the data of the secret, generating new credentials for the
+// database engine. Values that aren't strings are returned as JSON.
+func Read(ctx context.Context, s Secret) (map[string]string, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	address := s.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return nil, fmt.Errorf("vault: no address, set VAULT_ADDR or vault.address")
+	}
+	token, err := token()
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL, err := url.JoinPath(address, "v1", s.apiPath())
+	if err != nil {
+		return nil, fmt.Errorf("vault: invalid address %q: %w", address, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vault: failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	namespace := s.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault: %s returned %s: %s", s.apiPath(), resp.Status, bytes.TrimSpace(msg))
+	}
+
+	var body struct {
+		Data map[string]json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("vault: failed to decode %s: %w", s.apiPath(), err)
+	}
+	data := body.Data
+	// KV version 2 nests the secret and its metadata
+	if s.kvVersion() == 2 {
+		data = nil
+		if raw, ok := body.Data["data"]; ok {
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, fmt.Errorf("vault: failed to decode %s: %w", s.apiPath(), err)
+			}
+		}
+	}
+	if data == nil {
+		return nil, fmt.Errorf("vault: %s has no data", s.apiPath())
+	}
+
+	values := make(map[string]string, len(data))
+	for key, raw := range data {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// apiPath is the API path of the secret below /v1
+func (s *Secret) apiPath() string {
+	if s.Engine == EngineDatabase {
+		return strings.Trim(orDefault(s.Mount, "database"), "/") + "/creds/" + s.Role
+	}
+	mount := strings.Trim(orDefault(s.Mount, "secret"), "/")
+	path := strings.Trim(s.Path, "/")
+	if s.kvVersion() == 2 {
+		return mount + "/data/" + path
+	}
+	return mount + "/" + path
+}
+
+// kvVersion is the version of the kv engine, or 0 for the database engine
+func (s *Secret) kvVersion() int {
+	if s.Engine == EngineDatabase {
+		return 0
+	}
+	if s.KVVersion == 0 {
+		return 2
+	}
+	return s.KVVersion
+}
+
+// token returns VAULT_TOKEN or the token saved by `vault login`
+func token() (string, error) {
+	if t := os.Getenv("VAULT_TOKEN"); t != "" {
+		return t, nil
+	}
+	home, err := os.UserHomeDir()
+	if err == nil {
+		if data, err := os.ReadFile(filepath.Join(home, ".vault-token")); err == nil {
+			if t := strings.TrimSpace(string(data)); t != "" {
+				return t, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("vault: no token, set VAULT_TOKEN or run vault login")
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// Credentials is a database login stored in Vault
+type Credentials struct {
+	Secret `yaml:",inline"`
+	// UsernameField is the key of the username (default: username)
+	UsernameField string `yaml:"username_field,omitempty"`
+	// PasswordField is the key of the password (default: password)
+	PasswordField string `yaml:"password_field,omitempty"`
+}
+
+// Login reads the username and password. The username is empty when a kv
+// secret only stores the password.
+func (c Credentials) Login(ctx context.Context) (username, password string, err error) {
+	values, err := Read(ctx, c.Secret)
+	if err != nil {
+		return "", "", err
+	}
+	passwordField := orDefault(c.PasswordField, "password")
+	password, ok := values[passwordField]
+	if !ok {
+		return "", "", fmt.Errorf("vault: %s has no %s", c.apiPath(), passwordField)
+	}
+	return values[orDefault(c.UsernameField, "username")], password, nil
+}
+
+// SSHKey is an SSH key pair stored in a kv secret
+type SSHKey struct {
+	Secret `yaml:",inline"`
+	// PrivateKeyField is the key of the private key (default: private_key)
+	PrivateKeyField string `yaml:"private_key_field,omitempty"`
+	// PublicKeyField is the key of the public key (default: public_key)
+	PublicKeyField string `yaml:"public_key_field,omitempty"`
+}
+
+// Validate checks the secret; SSH keys are only read from the kv engine
+func (k *SSHKey) Validate() error {
+	if k.Engine != "" && k.Engine != EngineKV {
+		return fmt.Errorf("vault: SSH keys are read from the kv engine")
+	}
+	return k.Secret.Validate()
+}
+
+// WriteFiles writes the key pair to dir as the private key file and its .pub
+// file, as gcloud compute ssh --ssh-key-file expects, and returns the path
+// of the private key
+func (k SSHKey) WriteFiles(ctx context.Context, dir string) (string, error) {
+	if err := k.Validate(); err != nil {
+		return "", err
+	}
+	values, err := Read(ctx, k.Secret)
+	if err != nil {
+		return "", err
+	}
+	privateField := orDefault(k.PrivateKeyField, "private_key")
+	publicField := orDefault(k.PublicKeyField, "public_key")
+	private, public := values[privateField], values[publicField]
+	if private == "" || public == "" {
+		return "", fmt.Errorf("vault: %s must have %s and %s", k.apiPath(), privateField, publicField)
+	}
+
+	path := filepath.Join(dir, "id_vault")
+	if err := os.WriteFile(path, []byte(ensureNewline(private)), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write SSH key: %w", err)
+	}
+	if err := os.WriteFile(path+".pub", []byte(ensureNewline(public)), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write SSH key: %w", err)
+	}
+	return path, nil
+}
+
+// ensureNewline terminates a key file; ssh rejects private keys without it
+func ensureNewline(s string) string {
+	if strings.HasSuffix(s, "\n") {
+		return s
+	}
+	return s + "\n"
+}
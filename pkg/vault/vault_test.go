@@ -0,0 +1,115 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testServer serves fixed responses per API path and records the token
+func testServer(t *testing.T, responses map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "test-token")
+	return server
+}
+
+func TestCredentialsLogin(t *testing.T) {
+	testServer(t, map[string]string{
+		"/v1/secret/data/db/orders": `{"data": {"data": {"user": "inspector", "password": "kv2"}, "metadata": {"version": 3}}}`,
+		"/v1/kv/db/orders":          `{"data": {"password": "kv1", "port": 5432}}`,
+		"/v1/database/creds/ro":     `{"lease_id": "database/creds/ro/abc", "data": {"username": "v-ro-123", "password": "dynamic"}}`,
+	})
+
+	tests := []struct {
+		name         string
+		creds        Credentials
+		wantUsername string
+		wantPassword string
+		wantErr      bool
+	}{
+		{"kv version 2", Credentials{Secret: Secret{Path: "db/orders"}, UsernameField: "user"}, "inspector", "kv2", false},
+		{"kv version 1", Credentials{Secret: Secret{Mount: "kv", Path: "db/orders", KVVersion: 1}}, "", "kv1", false},
+		{"database engine", Credentials{Secret: Secret{Engine: EngineDatabase, Role: "ro"}}, "v-ro-123", "dynamic", false},
+		{"missing field", Credentials{Secret: Secret{Path: "db/orders"}, PasswordField: "secret"}, "", "", true},
+		{"missing secret", Credentials{Secret: Secret{Path: "db/other"}}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			username, password, err := tt.creds.Login(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if username != tt.wantUsername || password != tt.wantPassword {
+				t.Errorf("Login() = %q, %q, want %q, %q", username, password, tt.wantUsername, tt.wantPassword)
+			}
+		})
+	}
+}
+
+func TestSecretValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  Secret
+		wantErr bool
+	}{
+		{"kv", Secret{Path: "db"}, false},
+		{"kv without path", Secret{}, true},
+		{"invalid kv version", Secret{Path: "db", KVVersion: 3}, true},
+		{"database", Secret{Engine: EngineDatabase, Role: "ro"}, false},
+		{"database without role", Secret{Engine: EngineDatabase}, true},
+		{"unknown engine", Secret{Engine: "pki"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.secret.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSSHKeyWriteFiles(t *testing.T) {
+	testServer(t, map[string]string{
+		"/v1/secret/data/bastion": `{"data": {"data": {"private_key": "PRIVATE", "public_key": "ssh-ed25519 AAAA"}}}`,
+	})
+
+	dir := t.TempDir()
+	path, err := SSHKey{Secret: Secret{Path: "bastion"}}.WriteFiles(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "id_vault") {
+		t.Errorf("path = %s", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("private key mode = %v, want 0600", info.Mode().Perm())
+	}
+	if data, _ := os.ReadFile(path + ".pub"); string(data) != "ssh-ed25519 AAAA\n" {
+		t.Errorf("public key = %q", data)
+	}
+
+	if _, err := (SSHKey{Secret: Secret{Engine: EngineDatabase, Role: "ro"}}).WriteFiles(context.Background(), dir); err == nil {
+		t.Error("expected an error for the database engine")
+	}
+}